@@ -1,4 +1,6 @@
 import logging
+from collections import Counter
+from collections.abc import Iterable, Mapping
 from pathlib import Path
 
 from langchain_core.language_models import BaseChatModel
@@ -7,15 +9,18 @@ from langchain_core.prompts import PromptTemplate
 from agents.agent import CodeBoardingAgent
 from agents.agent_responses import (
     AnalysisInsights,
+    Component,
     ComponentApiSurfaces,
     ComponentArchitecture,
     ComponentRelations,
     ClusterAnalysis,
     MetaAnalysisInsights,
+    SourceCodeReference,
     assign_component_ids,
     assign_relation_ids,
 )
 from agents.cluster_methods_mixin import ClusterMethodsMixin
+from agents.content_hash import SourceCache
 from agents.prompts import (
     get_final_analysis_message,
     get_api_surfaces_message,
@@ -23,6 +28,7 @@ from agents.prompts import (
     get_system_message,
     format_project_system_message,
 )
+from agents.prompts.prompt_templates import PromptTemplates, languages_str
 from agents.relation_edges import index_relation_endpoints
 from agents.repair import ComponentRepairContext, repair_component_group_names, repair_key_entities
 from agents.validation import (
@@ -31,11 +37,18 @@ from agents.validation import (
     validate_key_entities,
     validate_relations,
 )
+from diagram_analysis.deprecation import DeprecatedSymbols
+from diagram_analysis.external_boundaries import ExternalBoundaries
+from diagram_analysis.file_index import build_files_index
+from diagram_analysis.grouping import Grouping, NodeGroup, group_nodes
 from monitoring import trace
+from repo_utils.path_utils import normalize_repo_path
 from static_analyzer import StaticAnalysisFatalError
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.cluster_helpers import build_all_cluster_results
-from static_analyzer.graph import ClusterResult
+from static_analyzer.cluster_relations import build_global_relations
+from static_analyzer.graph import ClusterResult, Edge
+from static_analyzer.standard_interfaces import collect_standard_interface_tags
 
 logger = logging.getLogger(__name__)
 
@@ -49,12 +62,25 @@ class AbstractionAgent(ClusterMethodsMixin, CodeBoardingAgent):
         meta_context: MetaAnalysisInsights,
         agent_llm: BaseChatModel,
         parsing_llm: BaseChatModel,
+        external_boundaries: ExternalBoundaries | None = None,
+        deprecated_symbols: DeprecatedSymbols | None = None,
+        grouping: Grouping = Grouping.SEMANTIC,
+        prompt_templates: PromptTemplates = PromptTemplates(),
     ):
-        system_message = format_project_system_message(get_system_message(), project_name, meta_context)
+        system_message = format_project_system_message(
+            prompt_templates.system or get_system_message(),
+            project_name,
+            meta_context,
+            languages_str(static_analysis.get_languages()),
+        )
         super().__init__(repo_dir, static_analysis, system_message, agent_llm, parsing_llm)
 
         self.project_name = project_name
         self.meta_context = meta_context
+        self.external_boundaries = external_boundaries or ExternalBoundaries()
+        self.deprecated_symbols = deprecated_symbols or DeprecatedSymbols()
+        self.standard_interfaces = collect_standard_interface_tags(static_analysis)
+        self.grouping = grouping
 
         self.prompts = {
             "final_analysis": PromptTemplate(
@@ -110,6 +136,13 @@ class AbstractionAgent(ClusterMethodsMixin, CodeBoardingAgent):
                 f"Every one of these names must appear in exactly one component's source_group_names: {group_names}\n"
             )
 
+        if self.external_boundaries:
+            prompt += self.external_boundaries.llm_str()
+        if self.deprecated_symbols:
+            prompt += self.deprecated_symbols.llm_str()
+        if self.standard_interfaces:
+            prompt += self.standard_interfaces.llm_str()
+
         context = ValidationContext(
             cluster_results=cluster_results,
             static_analysis=self.static_analysis,
@@ -139,15 +172,93 @@ class AbstractionAgent(ClusterMethodsMixin, CodeBoardingAgent):
             components_relations=[],
         )
 
+    def static_grouping_analysis(self) -> AnalysisInsights:
+        """``--grouping package|directory``: one leaf component per package or directory, with no LLM request."""
+        groups = group_nodes(self.static_analysis, self.repo_dir, self.grouping)
+        if not groups:
+            raise StaticAnalysisFatalError(
+                f"No {self.grouping} groups found for {self.project_name}: the static analysis produced no symbols."
+            )
+        logger.info(f"[AbstractionAgent] Grouping {self.project_name} by {self.grouping}: {len(groups)} components")
+        cfg_graphs = self.static_analysis.available_cfgs()
+        degrees: Counter[str] = Counter()
+        for cfg in cfg_graphs.values():
+            for edge in cfg.edges:
+                degrees[edge.get_source()] += 1
+                degrees[edge.get_destination()] += 1
+        analysis = AnalysisInsights(
+            description=f"{self.project_name}, grouped into one component per {self.grouping} ({len(groups)} in all).",
+            components=[
+                Component(
+                    name=group.name,
+                    description=group.description(self.grouping),
+                    key_entities=self._group_key_entities(group, degrees),
+                    source_group_names=[group.name],
+                )
+                for group in groups
+            ],
+            components_relations=[],
+        )
+        assign_component_ids(analysis)
+        source_cache: SourceCache = {}
+        for component, group in zip(analysis.components, groups):
+            component.file_methods = self._build_file_methods_from_nodes(group.nodes, source_cache)
+        analysis.files = build_files_index(analysis, self.repo_dir, source_cache)
+        analysis.components_relations = build_global_relations(analysis, {}, cfg_graphs)
+        index_relation_endpoints(analysis, self.repo_dir)
+        return analysis
+
+    def add_dependency_components(
+        self, analysis: AnalysisInsights, groups: list[NodeGroup], calls: Iterable[Edge], scope: str
+    ) -> list[Component]:
+        """``--path``/``--package``: one external leaf per package *groups* outside *scope*, called over *calls*."""
+        degrees = Counter(edge.get_destination() for edge in calls)
+        taken = {component.name for component in analysis.components}
+        added: list[Component] = []
+        for group in groups:
+            name = group.name if group.name not in taken else f"{group.name} (external)"
+            added.append(
+                Component(
+                    name=name,
+                    description=f"External dependency of `{scope}`. {group.description(Grouping.PACKAGE)}",
+                    key_entities=self._group_key_entities(group, degrees),
+                    source_group_names=[],
+                    external=True,
+                )
+            )
+        analysis.components.extend(added)
+        assign_component_ids(analysis, only_new=True)
+        source_cache: SourceCache = {}
+        for component, group in zip(added, groups):
+            component.file_methods = self._build_file_methods_from_nodes(group.nodes, source_cache)
+        analysis.files = build_files_index(analysis, self.repo_dir, source_cache)
+        logger.info(f"[AbstractionAgent] Added {len(added)} external dependency components outside {scope}")
+        return added
+
+    def _group_key_entities(self, group: NodeGroup, degrees: Mapping[str, int]) -> list[SourceCodeReference]:
+        return [
+            SourceCodeReference(
+                qualified_name=node.fully_qualified_name,
+                reference_file=normalize_repo_path(node.file_path, self.repo_dir),
+                reference_start_line=node.line_start,
+                reference_end_line=node.line_end,
+            )
+            for node in group.key_nodes(degrees)
+        ]
+
     @trace
     def step_api_surfaces(self, analysis: AnalysisInsights) -> ComponentApiSurfaces:
         logger.info(f"[AbstractionAgent] Analyzing component API surfaces for: {self.project_name}")
-        static_call_evidence = self.build_scope_cfg_string(analysis)
-        prompt = self.prompts["api_surfaces"].format(
-            component_summaries=analysis.llm_str(),
-            static_call_evidence=static_call_evidence,
-        )
-        return self._parse_invoke(prompt, ComponentApiSurfaces)
+
+        def surfaces(components: list[Component]) -> ComponentApiSurfaces:
+            batch = analysis.model_copy(update={"components": components})
+            prompt = self.prompts["api_surfaces"].format(
+                component_summaries=batch.llm_str(),
+                static_call_evidence=self.build_scope_cfg_string(batch),
+            )
+            return self._parse_invoke(prompt, ComponentApiSurfaces)
+
+        return self._invoke_in_batches(analysis.components, surfaces, ComponentApiSurfaces.merged)
 
     @trace
     def step_relation_analysis(
@@ -187,6 +298,8 @@ class AbstractionAgent(ClusterMethodsMixin, CodeBoardingAgent):
         self.build_static_relations(analysis)
 
     def run(self):
+        if self.grouping != Grouping.SEMANTIC:
+            return self.static_grouping_analysis(), {}
         # Build full cluster results dict for all languages ONCE
         cluster_results = build_all_cluster_results(self.static_analysis)
 
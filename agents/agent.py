@@ -1,10 +1,12 @@
 import json
 import logging
+import threading
+import time
 from collections.abc import Callable
 from pathlib import Path
 from typing import Protocol, TypeVar
 
-from google.api_core.exceptions import ResourceExhausted
+from langchain_core.callbacks import BaseCallbackHandler
 from langchain_core.exceptions import OutputParserException
 from langchain_core.language_models import BaseChatModel
 from langchain_core.messages import SystemMessage, HumanMessage, AIMessage, ToolMessage
@@ -12,7 +14,7 @@ from langchain_core.output_parsers import PydanticOutputParser
 from langchain_core.prompts import PromptTemplate
 from langchain.agents import create_agent
 from langgraph.graph.state import CompiledStateGraph
-from pydantic import ValidationError
+from pydantic import BaseModel, ValidationError
 from trustcall import create_extractor
 
 from agents.prompts import get_validation_feedback_message
@@ -23,23 +25,62 @@ from agents.validation import ValidationResult, score_validation_results, VALIDA
 from monitoring.mixin import MonitoringMixin
 from repo_utils.ignore import RepoIgnoreManager
 from agents.agent_responses import LLMBaseModel
-from agents.llm_config import MONITORING_CALLBACK, current_provider_key_context
-from agents.llm_errors import detect_auth_error
+from agents.llm_config import (
+    MONITORING_CALLBACK,
+    active_provider,
+    can_fail_over,
+    current_provider_key_context,
+    fail_over,
+    get_current_agent_model_ref,
+    initialize_llms,
+    llm_max_attempts,
+    structured_output_method,
+)
+from agents.constants import LLMDefaults
+from caching.cache import ModelSettings
+from caching.response_cache import AGENT_NAMESPACE, PARSE_NAMESPACE, ResponseCache, open_response_cache
+from agents.llm_errors import (
+    LLMBudgetError,
+    LLMRequestTooLargeError,
+    LLMSafetyError,
+    detect_auth_error,
+    is_rate_limited,
+    is_request_too_large,
+    is_safety_blocked,
+    safety_finish_reason,
+)
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.reference_resolver import StaticReferenceResolver
 
 logger = logging.getLogger(__name__)
 
+# What ``_invoke`` returns once the retries are used up; never cached.
+_NO_RESPONSE = "Could not get response from the agent."
+
 ParseResultT = TypeVar("ParseResultT")
+ItemT = TypeVar("ItemT")
 ResultT = TypeVar("ResultT", bound="RepairValidationResult")
 RepairContextT = TypeVar("RepairContextT")
 ValidationContextT = TypeVar("ValidationContextT")
+FailoverT = TypeVar("FailoverT")
 
 
 class RepairValidationResult(Protocol):
     def llm_str(self) -> str: ...
 
 
+class StreamActivity(BaseCallbackHandler):
+    """When the model last produced output: a streamed token, a finished answer or a tool result."""
+
+    def __init__(self) -> None:
+        self.last_output = time.monotonic()
+
+    def _touch(self, *args, **kwargs) -> None:
+        self.last_output = time.monotonic()
+
+    on_llm_new_token = on_llm_end = on_tool_end = _touch
+
+
 class EmptyExtractorMessageError(ValueError):
     """Raised when extractor returns an empty message payload."""
 
@@ -59,7 +100,39 @@ def _raise_if_auth_error(exc: Exception) -> None:
         raise auth_error from exc
 
 
+def _raise_if_too_large(exc: Exception) -> None:
+    """Raise :class:`LLMRequestTooLargeError` when *exc* says the request exceeds the model's token limit.
+
+    Resending the same payload cannot succeed, so it is not retried; a
+    ``--llm-fallback`` provider with a larger window still gets a try, and
+    :meth:`CodeBoardingAgent._invoke_in_batches` retries on smaller batches.
+    """
+    if is_request_too_large(exc):
+        logger.error("LLM request exceeds the model's token limit — not retrying: %s", exc)
+        raise LLMRequestTooLargeError(f"Request too large for the model: {exc}") from exc
+
+
+def _raise_if_safety_blocked(exc: Exception) -> None:
+    """Raise :class:`LLMSafetyError` when the provider's safety filters blocked the request or its answer.
+
+    At temperature 0 the same request is blocked again, so it is not retried;
+    a ``--llm-fallback`` provider still gets a try.
+    """
+    if isinstance(exc, LLMSafetyError):
+        raise exc
+    if is_safety_blocked(exc):
+        logger.error("LLM request blocked by the provider's safety filters — not retrying: %s", exc)
+        raise LLMSafetyError(f"Blocked by the provider's safety filters: {exc}") from exc
+
+
+def _rate_limit_backoff(attempt: int) -> float:
+    return default_backoff(attempt, initial_s=30.0, multiplier=2.0, max_s=300.0, jitter=LLMDefaults.BACKOFF_JITTER)
+
+
 class CodeBoardingAgent(MonitoringMixin):
+    # Cleared when the provider rejects its native structured-output mode; text parsing takes over.
+    _native_output_enabled = True
+
     def __init__(
         self,
         repo_dir: Path,
@@ -79,11 +152,18 @@ class CodeBoardingAgent(MonitoringMixin):
         context = RepoContext(repo_dir=repo_dir, ignore_manager=self.ignore_manager, static_analysis=static_analysis)
         self.toolkit = CodeBoardingToolkit(context=context)
 
+        # Kept so a provider failover can rebuild the agent around the next provider's model.
+        self.agent_tools = self.toolkit.get_agent_tools()
         self.agent: CompiledStateGraph = create_agent(
             model=agent_llm,
-            tools=self.toolkit.get_agent_tools(),
+            tools=self.agent_tools,
         )
         self.system_message = SystemMessage(content=system_message)
+        self.llm_provider = active_provider()
+        self.llm_model_ref = get_current_agent_model_ref()
+        self._served_by = threading.local()
+        # Opened on first use, by namespace; None when the repo's cache dir is not writable.
+        self._response_caches: dict[str, ResponseCache | None] = {}
 
     @property
     def read_source_reference(self):
@@ -125,17 +205,77 @@ class CodeBoardingAgent(MonitoringMixin):
     def external_deps_tool(self):
         return self.toolkit.external_deps
 
+    def served_by(self) -> str:
+        """``provider/model`` that answered this thread's latest LLM call."""
+        return getattr(self._served_by, "model_ref", self.llm_model_ref)
+
+    def _switch_llms(self) -> None:
+        """Re-initialize the models from the active provider after a failover."""
+        self.agent_llm, self.parsing_llm = initialize_llms()
+        self.agent = create_agent(model=self.agent_llm, tools=self.agent_tools)
+        self._native_output_enabled = True
+        self.llm_provider = active_provider()
+        self.llm_model_ref = get_current_agent_model_ref()
+
+    def _with_failover(self, call: Callable[[], FailoverT]) -> FailoverT:
+        """Run *call*; when it fails for good, move to the next ``--llm-fallback`` provider and rerun.
+
+        *call* owns the per-provider retries, so reaching here means the active
+        provider exhausted them or failed hard (rejected key, retired model).
+        Without a fallback chain the error propagates unchanged.
+        """
+        while True:
+            provider = self.llm_provider
+            try:
+                result = call()
+            except Exception as exc:
+                if provider is None or not fail_over(provider):
+                    raise
+                logger.info(f"[{type(self).__name__}] '{provider}' failed ({type(exc).__name__}); switching provider")
+                self._switch_llms()
+                continue
+            self._served_by.model_ref = self.llm_model_ref
+            return result
+
+    def _response_cache(self, namespace: str) -> ResponseCache | None:
+        if namespace not in self._response_caches:
+            self._response_caches[namespace] = open_response_cache(self.repo_dir, namespace)
+        return self._response_caches[namespace]
+
+    def _response_key(self, llm: BaseChatModel, system_prompt: str, prompt: str):
+        settings = ModelSettings.from_chat_model(provider=self.llm_provider or "unknown", llm=llm)
+        return ResponseCache.build_key(settings, system_prompt, prompt)
+
     def _invoke(self, prompt, callbacks: list | None = None) -> str:
+        """``_invoke_uncached``, answered from the LLM response cache when this exact request was made before."""
+        cache = self._response_cache(AGENT_NAMESPACE)
+        system_prompt = str(self.system_message.content)
+        if cache is not None and (cached := cache.lookup(self._response_key(self.agent_llm, system_prompt, prompt))):
+            logger.info(f"[{type(self).__name__}] LLM response cache hit ({len(prompt)} chars of prompt)")
+            return cached
+        response = self._invoke_uncached(prompt, callbacks)
+        if cache is not None and response and response != _NO_RESPONSE:
+            # Keyed by the model that answered, which a failover may have changed.
+            cache.save(self._response_key(self.agent_llm, system_prompt, prompt), response)
+        return response
+
+    def _invoke_uncached(self, prompt, callbacks: list | None = None) -> str:
         """Unified agent invocation method with timeout and exponential backoff.
 
         Classification applied per exception:
         - ``TimeoutError``: backoff ``min(10·2^n, 120)``, raise on exhaustion.
-        - ``ResourceExhausted``: backoff ``min(30·2^n, 300)``, raise on exhaustion.
+        - Rate limits (``ResourceExhausted``, HTTP 429, Anthropic's 529 overloaded, Gemini's
+          ``RESOURCE_EXHAUSTED``): backoff ``min(30·2^n, 300)``, ``LLMBudgetError`` on exhaustion.
+        - A Gemini safety block, raised or as the answer's finish reason: ``LLMSafetyError`` immediately.
         - ``status_code == 404``: raise immediately (retired model ID, etc.).
+        - A request over the model's token limit: ``LLMRequestTooLargeError`` immediately.
         - Other exceptions: backoff ``min(10·2^n, 120)``, return fallback string
           on exhaustion (non-raising — callers treat the fallback as a failed run).
+
+        Exhaustion or a hard error fails over to the next ``--llm-fallback``
+        provider first; the above only applies once the chain is used up.
         """
-        max_attempts = 5
+        max_attempts = llm_max_attempts()
         # Counter captured by the closure so we can vary the per-attempt timeout
         # without reaching into the retry helper.
         attempt_counter = [0]
@@ -156,6 +296,8 @@ class CodeBoardingAgent(MonitoringMixin):
             )
             agent_response = response["messages"][-1]
             assert isinstance(agent_response, AIMessage), f"Expected AIMessage, but got {type(agent_response)}"
+            if (reason := safety_finish_reason(agent_response.response_metadata)) is not None:
+                raise LLMSafetyError(f"Answer stopped by the provider's safety filters (finish reason {reason})")
             if isinstance(agent_response.content, str):
                 return agent_response.content
             if isinstance(agent_response.content, list):
@@ -164,48 +306,58 @@ class CodeBoardingAgent(MonitoringMixin):
 
         def classify(exc: Exception, attempt: int) -> RetryDecision:
             _raise_if_auth_error(exc)
+            _raise_if_too_large(exc)
+            _raise_if_safety_blocked(exc)
             if getattr(exc, "status_code", None) == 404:
                 logger.error(f"Permanent HTTP 404 — not retrying: {type(exc).__name__}: {exc}")
                 return RetryDecision(action=RetryAction.GIVE_UP)
-            if isinstance(exc, ResourceExhausted):
-                return RetryDecision(
-                    action=RetryAction.RETRY,
-                    backoff_s=default_backoff(attempt, initial_s=30.0, multiplier=2.0, max_s=300.0),
-                )
+            if is_rate_limited(exc):
+                return RetryDecision(action=RetryAction.RETRY, backoff_s=_rate_limit_backoff(attempt))
             # TimeoutError + generic Exception share the same backoff.
             return RetryDecision(
                 action=RetryAction.RETRY,
-                backoff_s=default_backoff(attempt, initial_s=10.0, multiplier=2.0, max_s=120.0),
+                backoff_s=default_backoff(
+                    attempt, initial_s=10.0, multiplier=2.0, max_s=120.0, jitter=LLMDefaults.BACKOFF_JITTER
+                ),
             )
 
         def on_exhausted(exc: Exception) -> str:
             # Typed exceptions surface the original error; only generic falls through
             # to the historic fallback string that callers have long relied on.
-            if isinstance(exc, (TimeoutError, ResourceExhausted)):
+            # Raising while a fallback provider remains lets _with_failover switch.
+            if is_rate_limited(exc):
+                raise LLMBudgetError(f"Still rate-limited after {max_attempts} attempts: {exc}") from exc
+            if isinstance(exc, TimeoutError) or can_fail_over():
                 raise exc
-            return "Could not get response from the agent."
-
-        return with_retries(
-            call_once,
-            max_attempts=max_attempts,
-            classify=classify,
-            on_exhausted=on_exhausted,
-            log_prefix="Agent invocation",
+            return _NO_RESPONSE
+
+        return self._with_failover(
+            lambda: with_retries(
+                call_once,
+                max_attempts=max_attempts,
+                classify=classify,
+                on_exhausted=on_exhausted,
+                log_prefix="Agent invocation",
+            )
         )
 
     def _invoke_with_timeout(self, timeout_seconds: int, callback_list: list, prompt: str):
-        """Invoke agent with a timeout using threading."""
-        import threading
+        """Invoke agent with a timeout using threading.
+
+        The timeout counts from the model's last output, so a streamed answer (Ollama sends one token at
+        a time) runs as long as tokens keep arriving and only times out once the server goes quiet.
+        """
         from queue import Queue, Empty
 
         result_queue: Queue = Queue()
         exception_queue: Queue = Queue()
+        activity = StreamActivity()
 
         def invoke_target():
             try:
                 response = self.agent.invoke(
                     {"messages": [self.system_message, HumanMessage(content=prompt)]},
-                    config={"callbacks": callback_list, "recursion_limit": 40},
+                    config={"callbacks": [*callback_list, activity], "recursion_limit": 40},
                 )
                 result_queue.put(response)
             except Exception as e:
@@ -213,12 +365,14 @@ class CodeBoardingAgent(MonitoringMixin):
 
         thread = threading.Thread(target=invoke_target, daemon=True)
         thread.start()
-        thread.join(timeout=timeout_seconds)
-
-        if thread.is_alive():
-            # Thread is still running - timeout occurred
-            logger.error(f"Agent invoke thread still running after {timeout_seconds}s timeout")
-            raise TimeoutError(f"Agent invocation exceeded {timeout_seconds}s timeout")
+        while True:
+            thread.join(timeout=max(0.0, activity.last_output + timeout_seconds - time.monotonic()))
+            if not thread.is_alive():
+                break
+            if time.monotonic() - activity.last_output >= timeout_seconds:
+                # Thread is still running - timeout occurred
+                logger.error(f"Agent invoke thread produced no output for {timeout_seconds}s")
+                raise TimeoutError(f"Agent invocation exceeded {timeout_seconds}s timeout")
 
         # Check for exceptions
         try:
@@ -243,6 +397,28 @@ class CodeBoardingAgent(MonitoringMixin):
         assert isinstance(response, str), f"Expected a string as response type got {response}"
         return self._parse_response(prompt, response, return_type, include_hidden=include_hidden)
 
+    def _invoke_in_batches(
+        self,
+        items: list[ItemT],
+        invoke: Callable[[list[ItemT]], ParseResultT],
+        merge: Callable[[ParseResultT, ParseResultT], ParseResultT],
+    ) -> ParseResultT:
+        """``invoke(items)``, split in half and merged when the prompt exceeds the model's token limit.
+
+        Only a single item that still does not fit raises :class:`LLMRequestTooLargeError`.
+        """
+        try:
+            return invoke(items)
+        except LLMRequestTooLargeError:
+            if len(items) <= 1:
+                raise
+        half = len(items) // 2
+        logger.warning(f"[{type(self).__name__}] Prompt for {len(items)} items is too large; retrying in two halves")
+        return merge(
+            self._invoke_in_batches(items[:half], invoke, merge),
+            self._invoke_in_batches(items[half:], invoke, merge),
+        )
+
     def _repair_result(
         self,
         result: ResultT,
@@ -393,7 +569,25 @@ class CodeBoardingAgent(MonitoringMixin):
 
         return best_result
 
-    def _parse_response(self, prompt, response, return_type, max_retries=5, attempt=0, include_hidden: bool = False):
+    def _parse_response(self, prompt, response, return_type, max_retries=None, attempt=0, include_hidden: bool = False):
+        """*response* as *return_type*, from the LLM response cache when this answer was parsed before."""
+        cache = self._response_cache(PARSE_NAMESPACE)
+        target = f"{return_type.__module__}.{return_type.__qualname__} include_hidden={include_hidden}"
+        if cache is not None and (cached := cache.lookup(self._response_key(self.parsing_llm, target, response))):
+            try:
+                return return_type.model_validate_json(cached)
+            except (ValidationError, ValueError) as e:
+                # The type changed since the entry was written: parse again.
+                logger.debug(f"Ignoring stale cached {return_type.__name__}: {e}")
+        result = self._parse_response_uncached(prompt, response, return_type, max_retries, attempt, include_hidden)
+        if cache is not None and isinstance(result, BaseModel):
+            cache.save(self._response_key(self.parsing_llm, target, response), result.model_dump_json())
+        return result
+
+    def _parse_response_uncached(
+        self, prompt, response, return_type, max_retries=None, attempt=0, include_hidden: bool = False
+    ):
+        max_retries = max_retries or llm_max_attempts()
         if response is None or response.strip() == "":
             logger.error(f"Empty response for prompt: {prompt}")
 
@@ -409,6 +603,9 @@ class CodeBoardingAgent(MonitoringMixin):
             format_instructions = parser.get_format_instructions()
 
         def call_once():
+            native = self._native_parse(response, return_type, include_hidden=include_hidden)
+            if native is not None:
+                return native
             try:
                 result = self._structured_parse(response, parser, format_instructions=format_instructions)
                 logger.debug("[parse_response] structured_parse succeeded for %s", return_type.__name__)
@@ -419,30 +616,58 @@ class CodeBoardingAgent(MonitoringMixin):
 
         def classify(exc: Exception, attempt: int) -> RetryDecision:
             _raise_if_auth_error(exc)
-            if isinstance(exc, ResourceExhausted):
-                return RetryDecision(
-                    action=RetryAction.RETRY,
-                    backoff_s=default_backoff(attempt, initial_s=30.0, multiplier=2.0, max_s=300.0),
-                )
+            _raise_if_too_large(exc)
+            _raise_if_safety_blocked(exc)
+            if is_rate_limited(exc):
+                return RetryDecision(action=RetryAction.RETRY, backoff_s=_rate_limit_backoff(attempt))
             if isinstance(exc, (EmptyExtractorMessageError, IndexError, json.JSONDecodeError, ValueError)):
                 return RetryDecision(action=RetryAction.RETRY_NOW)
             return RetryDecision(action=RetryAction.GIVE_UP)
 
         def on_exhausted(exc: Exception):
-            if isinstance(exc, ResourceExhausted):
-                logger.error(f"Resource exhausted on final parsing attempt: {exc}")
-                raise exc
+            if is_rate_limited(exc):
+                raise LLMBudgetError(f"Still rate-limited on the final parsing attempt: {exc}") from exc
             logger.error(f"Max retries ({max_retries}) reached for parsing response: {response}")
             raise Exception(f"Max retries reached for parsing response: {response}")
 
-        return with_retries(
-            call_once,
-            max_attempts=max(1, max_retries - attempt),
-            classify=classify,
-            on_exhausted=on_exhausted,
-            log_prefix="Parse response",
+        return self._with_failover(
+            lambda: with_retries(
+                call_once,
+                max_attempts=max(1, max_retries - attempt),
+                classify=classify,
+                on_exhausted=on_exhausted,
+                log_prefix="Parse response",
+            )
         )
 
+    def _native_parse(self, response, return_type, include_hidden: bool = False):
+        """*response* as *return_type* via the provider's JSON schema / tool-use mode; None to parse text instead."""
+        method = structured_output_method(self.parsing_llm) if self._native_output_enabled else None
+        if method is None:
+            return None
+        schema = return_type
+        if include_hidden and issubclass(return_type, LLMBaseModel):
+            schema = return_type.model_json_schema(include_hidden=True)
+        try:
+            structured = self.parsing_llm.with_structured_output(schema, method=method)
+            result = structured.invoke(
+                return_type.extractor_str(include_hidden=include_hidden) + response,
+                config={"callbacks": [MONITORING_CALLBACK, self.agent_monitoring_callback]},
+            )
+            if result is None:
+                raise ValueError("no structured output in the response")
+            return result if isinstance(result, return_type) else return_type.model_validate(result)
+        except Exception as e:
+            if is_rate_limited(e):
+                raise
+            _raise_if_auth_error(e)
+            logger.warning(
+                f"[parse_response] native {method} output failed for {return_type.__name__} ({e}); "
+                f"parsing text for the rest of this run"
+            )
+            self._native_output_enabled = False
+            return None
+
     def _structured_parse(self, message_content, parser, format_instructions: str | None = None):
         if format_instructions is None:
             format_instructions = parser.get_format_instructions()
@@ -12,8 +12,16 @@ from pydantic import BaseModel, Field
 from pydantic.fields import FieldInfo
 
 from agents.cluster_ids import CodeBoardingClusterId, GraphClusterId
-from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from agents.file_index_models import (
+    ExternalPackageCalls,
+    FileEntry,
+    FileMethodGroup,
+    HttpRouteLink,
+    MethodEntry,
+    SpecOperationLink,
+)
 from agents.scope_ids import ROOT_SCOPE_ID
+from constants import IMPLEMENTS_RELATION_LABEL
 
 logger = logging.getLogger(__name__)
 
@@ -195,6 +203,12 @@ class RelationEdge(LLMBaseModel):
         exclude=True,
         json_schema_extra={"hidden": True},
     )
+    flags: dict[str, str] = Field(
+        default_factory=dict,
+        description="Feature flag states (on/off) every call site of this edge is guarded by.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
 
     @classmethod
     def from_dict(cls, edge: dict, methods_index: dict[str, MethodIndexEntry]) -> RelationEdge:
@@ -208,6 +222,7 @@ class RelationEdge(LLMBaseModel):
             target=_relation_endpoint_from_key(target_key, methods_index),
             description=edge.get("description", ""),
             call_sites=[RelationCallSite.model_validate(site) for site in call_sites],
+            flags=dict(edge.get("flags") or {}),
         )
 
     @classmethod
@@ -296,6 +311,12 @@ class Relation(LLMBaseModel):
         exclude=True,
         json_schema_extra={"hidden": True},
     )
+    implements: bool = Field(
+        default=False,
+        description="True if the source's types satisfy interfaces declared in the target rather than calling it.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
 
     @classmethod
     def from_edges(
@@ -308,6 +329,7 @@ class Relation(LLMBaseModel):
         edges: list[RelationEdge],
         is_static: bool,
         evidence: str = "",
+        implements: bool = False,
     ) -> Relation:
         return cls(
             relation=relation,
@@ -319,6 +341,7 @@ class Relation(LLMBaseModel):
             dst_id=dst_id,
             is_static=is_static,
             all_edges=cls._unique_edges(edges),
+            implements=implements,
         )
 
     def llm_str(self) -> str:
@@ -327,6 +350,9 @@ class Relation(LLMBaseModel):
     def pair_key(self, include_relation: bool = False) -> tuple[str, str] | tuple[str, str, str]:
         src = self.src_id
         dst = self.dst_id
+        if self.implements:
+            # Interface satisfaction never merges into a call relation between the same pair.
+            return (src, dst, IMPLEMENTS_RELATION_LABEL)
         if include_relation:
             return (src, dst, self.relation)
         return (src, dst)
@@ -343,6 +369,7 @@ class Relation(LLMBaseModel):
             dst_id=self.dst_id,
             is_static=self.is_static,
             all_edges=all_edges,
+            implements=self.implements,
         )
 
     def merge_edges_from(self, relation: Relation) -> None:
@@ -489,6 +516,69 @@ class Component(LLMBaseModel):
         json_schema_extra={"hidden": True},
     )
 
+    external: bool = Field(
+        default=False,
+        description="True when every file of the component sits behind an annotated external boundary.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    spec_operations: list[SpecOperationLink] = Field(
+        default_factory=list,
+        description="Spec operations implemented by generated API client methods of this component.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    http_routes: list[HttpRouteLink] = Field(
+        default_factory=list,
+        description="HTTP routes served by the component's handler functions (--framework http).",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    external_calls: list[ExternalPackageCalls] = Field(
+        default_factory=list,
+        description="Packages outside the project the component's functions call, with call counts.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    deprecated: bool = Field(
+        default=False,
+        description="True when every method of the component is marked deprecated.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    deprecated_symbols: list[str] = Field(
+        default_factory=list,
+        description="Qualified names of the component's methods that are marked deprecated.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    owners: list[str] = Field(
+        default_factory=list,
+        description="CODEOWNERS owners of the component's files, most files first.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    tests: list[str] = Field(
+        default_factory=list,
+        description="IDs of the test functions that name the component's methods.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
+    not_described: str = Field(
+        default="",
+        description="Why the component was not expanded into subcomponents, e.g. 'call budget reached'.",
+        exclude=True,
+        json_schema_extra={"hidden": True},
+    )
+
     def file_paths(self) -> list[str]:
         """File paths this component spans, one per ``file_methods`` group."""
         return [group.file_path for group in self.file_methods]
@@ -597,6 +687,10 @@ class ComponentApiSurfaces(LLMBaseModel):
             return "No component API surfaces found."
         return "\n".join(surface.llm_str() for surface in self.api_surfaces)
 
+    def merged(self, other: "ComponentApiSurfaces") -> "ComponentApiSurfaces":
+        """This batch's surfaces followed by *other*'s, for a scope analysed in parts."""
+        return ComponentApiSurfaces(api_surfaces=self.api_surfaces + other.api_surfaces)
+
 
 class ComponentRelations(LLMBaseModel):
     """Relations discovered from component API surfaces."""
@@ -13,7 +13,7 @@ from agents.agent_responses import (
     Component,
     ComponentArchitecture,
 )
-from agents.file_index_models import FileMethodGroup, MethodEntry
+from agents.file_index_models import FileMethodGroup, MethodEntry, signature_str
 from agents.cluster_budget import ClusterPromptBudget
 from agents.content_hash import (
     SourceCache,
@@ -166,6 +166,8 @@ class ClusterMethodsMixin:
     # These attributes must be provided by the class using this mixin
     repo_dir: Path
     static_analysis: StaticAnalysisResults
+    # Edge kinds listed in cluster strings (``--llm-edge-kinds``); None = ClusteringConfig default.
+    llm_edge_kinds: tuple[str, ...] | None = None
 
     def deterministic_cluster_grouping(
         self,
@@ -293,7 +295,10 @@ class ClusterMethodsMixin:
             cfg = self.static_analysis.get_cfg(lang)
             cluster_result = cluster_results.get(lang)
             cluster_str = cfg.to_cluster_string(
-                cluster_ids or set(), cluster_result, skip_nodes=skip_sets.get(lang, set())
+                cluster_ids or set(),
+                cluster_result,
+                skip_nodes=skip_sets.get(lang, set()),
+                edge_kinds=self.llm_edge_kinds,
             )
 
             if cluster_str.strip() and cluster_str not in ("empty", "none", "No clusters found."):
@@ -358,7 +363,12 @@ class ClusterMethodsMixin:
 
                 for target in self._language_budget_targets(current_len, deficit):
                     try:
-                        skip = plan_skip_set(self.static_analysis.get_cfg(lang), cluster_results[lang], target)
+                        skip = plan_skip_set(
+                            self.static_analysis.get_cfg(lang),
+                            cluster_results[lang],
+                            target,
+                            edge_kinds=self.llm_edge_kinds,
+                        )
                     except ContextBudgetExceededError:
                         continue
 
@@ -639,7 +649,9 @@ class ClusterMethodsMixin:
         for lang in self.static_analysis.get_languages():
             if lang not in cluster_results:
                 continue
-            cluster_str = subgraph_cfgs[lang].to_cluster_string(cluster_result=cluster_results[lang])
+            cluster_str = subgraph_cfgs[lang].to_cluster_string(
+                cluster_result=cluster_results[lang], edge_kinds=self.llm_edge_kinds
+            )
             if cluster_str.strip() and cluster_str not in ("empty", "none", "No clusters found."):
                 result_parts.append(f"\n## {lang.capitalize()} - Component CFG\n")
                 result_parts.append(cluster_str)
@@ -774,6 +786,9 @@ class ClusterMethodsMixin:
                 start_line=node.line_start,
                 end_line=node.line_end,
                 node_type=node.type.name,
+                receiver_kind=node.receiver_kind.value,
+                visibility=node.visibility.value,
+                signature=signature_str(node),
                 content_hash=hash_method_body(
                     read_source_lines(self.repo_dir, rel_path, source_cache),
                     node.line_start,
@@ -0,0 +1,171 @@
+"""Focused Q&A over one component of a saved analysis.
+
+``codeboarding ask`` answers a question about a single component without
+re-running anything: the component's symbols, its relations (with the static
+edges behind them) and the neighbors those relations cite are rendered into one
+prompt, and the answer has to cite symbols from that context. Cheap enough to
+run on demand instead of regenerating the docs.
+
+Providers with a native structured-output mode return the answer and its
+citations as typed fields; for the others the citations are the backticked
+names scraped from the prose.
+"""
+
+import logging
+import re
+from dataclasses import dataclass, field
+
+from langchain_core.language_models import BaseChatModel
+from langchain_core.messages import HumanMessage
+from pydantic import BaseModel, Field
+
+from agents.agent_responses import AnalysisInsights, Component, index_components_by_id
+from agents.llm_config import structured_output_method
+from agents.prompts import get_component_question_message
+from utils import sanitize
+
+logger = logging.getLogger(__name__)
+
+# Why: keeps the prompt (and its cost) bounded on god-components with thousands of methods.
+MAX_METHODS = 200
+MAX_EDGES_PER_RELATION = 12
+
+_CITATION_RE = re.compile(r"`([^`\s]+)`")
+
+
+class ComponentNotFoundError(LookupError):
+    pass
+
+
+class ComponentAnswer(BaseModel):
+    """The answer to a ``codeboarding ask`` question."""
+
+    answer: str = Field(description="The answer in Markdown, with every cited symbol or file in backticks.")
+    citations: list[str] = Field(
+        default_factory=list,
+        description="Every fully qualified symbol name and file path the answer cites, exactly as in the context.",
+    )
+
+
+@dataclass
+class ComponentContext:
+    component: Component
+    text: str
+    symbols: set[str] = field(default_factory=set)
+
+
+def find_component(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights], ref: str
+) -> tuple[Component, AnalysisInsights]:
+    """Resolve *ref* (component ID or case-insensitive name) to the component and the scope holding it."""
+    scopes = [root_analysis, *sub_analyses.values()]
+    by_id = [(c, scope) for scope in scopes for c in scope.components if c.component_id == ref]
+    if by_id:
+        return by_id[0]
+    wanted = sanitize(ref).lower()
+    by_name = [(c, scope) for scope in scopes for c in scope.components if sanitize(c.name).lower() == wanted]
+    if len(by_name) == 1:
+        return by_name[0]
+    if by_name:
+        ids = ", ".join(c.component_id for c, _ in by_name)
+        raise ComponentNotFoundError(f"'{ref}' names several components ({ids}); pass a component ID instead")
+    components = index_components_by_id(root_analysis, sub_analyses).values()
+    names = ", ".join(f"{c.component_id} {c.name}" for c in components)
+    raise ComponentNotFoundError(f"No component '{ref}'. Available: {names}")
+
+
+def build_component_context(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights], ref: str
+) -> ComponentContext:
+    component, scope = find_component(root_analysis, sub_analyses, ref)
+    symbols: set[str] = set()
+    lines = [
+        f"## Project\n{root_analysis.description}",
+        f"## Component {component.component_id}: {component.name}\n{component.description}",
+    ]
+
+    if component.key_entities:
+        lines.append("### Key entities")
+        lines += [f"- `{e.qualified_name}` ({e.reference_file or 'unknown file'})" for e in component.key_entities]
+        symbols |= {e.qualified_name for e in component.key_entities}
+
+    methods = [(group.file_path, m.qualified_name) for group in component.file_methods for m in group.methods]
+    if methods:
+        lines.append(f"### Symbols ({len(methods)} across {len(component.file_methods)} files)")
+        shown = methods[:MAX_METHODS]
+        lines += [f"- `{name}` in {path}" for path, name in shown]
+        if len(methods) > len(shown):
+            lines.append(f"- ... {len(methods) - len(shown)} more not shown")
+        symbols |= {name for _, name in shown}
+
+    if sub := sub_analyses.get(component.component_id):
+        lines.append("### Sub-components")
+        lines += [f"- {c.component_id} {c.name}: {c.description}" for c in sub.components]
+
+    neighbors: dict[str, Component] = {}
+    by_id = {c.component_id: c for c in scope.components}
+    relations = [r for r in scope.components_relations if component.component_id in (r.src_id, r.dst_id)]
+    if relations:
+        lines.append("### Relations")
+    for rel in relations:
+        other_id = rel.dst_id if rel.src_id == component.component_id else rel.src_id
+        if other_id in by_id and other_id != component.component_id:
+            neighbors[other_id] = by_id[other_id]
+        evidence = f" ({rel.evidence})" if rel.evidence else ""
+        lines.append(f"- {rel.src_name} {rel.relation} {rel.dst_name}{evidence}")
+        edges = rel.all_edges or rel.key_edges
+        for edge in edges[:MAX_EDGES_PER_RELATION]:
+            lines.append(f"  - `{edge.source.qualified_name}` -> `{edge.target.qualified_name}`")
+            symbols |= {edge.source.qualified_name, edge.target.qualified_name}
+        if len(edges) > MAX_EDGES_PER_RELATION:
+            lines.append(f"  - ... {len(edges) - MAX_EDGES_PER_RELATION} more edges")
+
+    if neighbors:
+        lines.append("### Cited neighbors")
+        for neighbor in neighbors.values():
+            lines.append(f"- {neighbor.component_id} {neighbor.name}: {neighbor.description}")
+            entities = ", ".join(f"`{e.qualified_name}`" for e in neighbor.key_entities)
+            if entities:
+                lines.append(f"  - key entities: {entities}")
+            symbols |= {e.qualified_name for e in neighbor.key_entities}
+
+    return ComponentContext(component=component, text="\n".join(lines), symbols=symbols)
+
+
+def scrape_citations(text: str) -> list[str]:
+    """Backticked, symbol-looking names in *text*."""
+    return sorted({m for m in _CITATION_RE.findall(text) if "." in m or "::" in m})
+
+
+def unverified_citations(answer: ComponentAnswer | str, context: ComponentContext) -> list[str]:
+    """Citations in *answer* that the context never listed."""
+    cited = set(answer.citations) if isinstance(answer, ComponentAnswer) else set(scrape_citations(answer))
+    known_files = {group.file_path for group in context.component.file_methods}
+    return sorted(c for c in cited if c not in context.symbols and c not in known_files)
+
+
+def _structured_answer(llm: BaseChatModel, prompt: str) -> ComponentAnswer | None:
+    method = structured_output_method(llm)
+    if method is None:
+        return None
+    try:
+        result = llm.with_structured_output(ComponentAnswer, method=method).invoke([HumanMessage(content=prompt)])
+    except Exception as e:
+        logger.warning(f"Native {method} output failed ({e}); asking for a plain-text answer")
+        return None
+    if result is None:
+        return None
+    return result if isinstance(result, ComponentAnswer) else ComponentAnswer.model_validate(result)
+
+
+def ask_component(llm: BaseChatModel, context: ComponentContext, question: str) -> ComponentAnswer:
+    prompt = get_component_question_message().format(component_context=context.text, question=question)
+    logger.info(f"Asking about component {context.component.component_id} (prompt length: {len(prompt)})")
+    if (structured := _structured_answer(llm, prompt)) is not None:
+        return structured
+    response = llm.invoke([HumanMessage(content=prompt)])
+    if isinstance(response.content, str):
+        text = response.content
+    else:
+        text = "".join(part if isinstance(part, str) else part.get("text", "") for part in response.content)
+    return ComponentAnswer(answer=text, citations=scrape_citations(text))
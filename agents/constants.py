@@ -5,6 +5,14 @@ class LLMDefaults:
     DEFAULT_AGENT_TEMPERATURE = 0
     DEFAULT_PARSING_TEMPERATURE = 0
     AWS_MAX_TOKENS = 4096
+    # ``--deterministic``: sampling seed sent to the clients that accept one.
+    DETERMINISTIC_SEED = 42
+    # ``--max-retries``: attempts per LLM request before giving up.
+    MAX_ATTEMPTS = 5
+    # Fraction of each backoff drawn at random, so parallel agents don't retry in lockstep.
+    BACKOFF_JITTER = 0.25
+    # Where ``ollama serve`` listens unless told otherwise.
+    OLLAMA_HOST = "http://localhost:11434"
 
 
 class FileStructureConfig:
@@ -24,6 +32,8 @@ class ModelCapabilities:
         "modelsdev": "https://models.dev/api.json",
         "openrouter": "https://openrouter.ai/api/v1/models",
     }
+    # Generative Language API ``models.get``, which advertises each Gemini model's token limits.
+    GEMINI_MODELS_URL = "https://generativelanguage.googleapis.com/v1beta/models"
 
     # models.dev uses slugs that diverge from our internal provider names.
     MODELSDEV_SLUG = {
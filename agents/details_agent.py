@@ -23,10 +23,18 @@ from agents.prompts import (
     get_relation_analysis_message,
     format_project_system_message,
 )
+from agents.prompts.prompt_templates import (
+    COMPONENT_VARIABLES,
+    PromptTemplates,
+    edge_summary,
+    entities_str,
+    languages_str,
+)
 from agents.relation_edges import index_relation_endpoints
 from agents.repair import ComponentRepairContext, repair_component_group_names, repair_key_entities
 from agents.cluster_methods_mixin import ClusterMethodsMixin
 from caching.cache import ModelSettings
+from caching.component_cache import ComponentDocs, ComponentDocsCache
 from caching.details_cache import FinalAnalysisCache
 from agents.validation import (
     ValidationContext,
@@ -37,6 +45,13 @@ from agents.validation import (
 from monitoring import trace
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.cluster_helpers import SUBCOMPONENTS_MAX, SUBCOMPONENTS_MIN
+from static_analyzer.complexity import (
+    HOTSPOT_LIMIT,
+    FunctionComplexity,
+    component_functions,
+    compute_function_complexity,
+    hotspots_llm_str,
+)
 from static_analyzer.graph import CallGraph, ClusterResult
 
 logger = logging.getLogger(__name__)
@@ -52,29 +67,45 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
         agent_llm: BaseChatModel,
         parsing_llm: BaseChatModel,
         run_id: str,
+        prompt_templates: PromptTemplates = PromptTemplates(),
     ):
-        system_message = format_project_system_message(get_system_details_message(), project_name, meta_context)
+        system_message = format_project_system_message(
+            prompt_templates.system or get_system_details_message(),
+            project_name,
+            meta_context,
+            languages_str(static_analysis.get_languages()),
+        )
         super().__init__(repo_dir, static_analysis, system_message, agent_llm, parsing_llm)
         self.project_name = project_name
         self.meta_context = meta_context
         self.run_id = run_id
         self._cache_model_settings = ModelSettings.from_chat_model(provider="unknown", llm=agent_llm)
         self._analysis_cache = FinalAnalysisCache(repo_dir=repo_dir)
+        self._docs_cache = ComponentDocsCache(repo_dir=repo_dir)
+        self._complexities: dict[str, FunctionComplexity] | None = None
 
+        templates = (
+            prompt_templates.component or get_details_message(),
+            get_api_surfaces_message(),
+            get_relation_analysis_message(),
+        )
+        # Part of the cache keys: a reworded prompt, or a --prompt-dir system prompt, regenerates every component.
+        self._system_override = prompt_templates.system or ""
+        self._docs_templates = "\n".join(templates) + self._system_override
         self.prompts = {
             "final_analysis": PromptTemplate(
-                template=get_details_message(),
-                input_variables=["cluster_analysis", "component"],
+                template=templates[0],
+                input_variables=sorted(COMPONENT_VARIABLES),
             ),
             "api_surfaces": PromptTemplate(
-                template=get_api_surfaces_message(),
+                template=templates[1],
                 input_variables=[
                     "component_summaries",
                     "static_call_evidence",
                 ],
             ),
             "relation_analysis": PromptTemplate(
-                template=get_relation_analysis_message(),
+                template=templates[2],
                 input_variables=[
                     "component_summaries",
                     "api_surfaces",
@@ -83,6 +114,18 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
             ),
         }
 
+    def _switch_llms(self) -> None:
+        super()._switch_llms()
+        # Cached final analyses are keyed by model; a failover must not reuse the old provider's key.
+        self._cache_model_settings = ModelSettings.from_chat_model(provider="unknown", llm=self.agent_llm)
+
+    @property
+    def complexities(self) -> dict[str, FunctionComplexity]:
+        """Per-function complexity over the whole static analysis, computed once on first use."""
+        if self._complexities is None:
+            self._complexities = compute_function_complexity(self.static_analysis, self.repo_dir)
+        return self._complexities
+
     @trace
     def step_clusters_grouping(
         self, component: Component, subgraph_cluster_results: dict[str, ClusterResult]
@@ -123,6 +166,10 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
         prompt = self.prompts["final_analysis"].format(
             cluster_analysis=cluster_str,
             component=component.llm_str(),
+            component_name=component.name,
+            entities=entities_str(component),
+            edge_summary=edge_summary(subgraph_cfgs),
+            language=languages_str(subgraph_cfgs),
         )
 
         if group_names:
@@ -131,6 +178,14 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
                 f"Every one of these names: {group_names} must appear in exactly one component's source_group_names\n"
             )
 
+        if hotspots := component_functions(component, self.complexities)[:HOTSPOT_LIMIT]:
+            prompt += (
+                "\n\n## Complexity Hotspots\n"
+                "The most branch-heavy functions in this component; where relevant, "
+                "say in the descriptions where the core complexity lives.\n"
+                f"{hotspots_llm_str(hotspots)}\n"
+            )
+
         self.toolkit.context.cluster_analysis = cluster_analysis
         self.toolkit.context.cluster_results = subgraph_cluster_results
         self.toolkit.context.cfg_graphs = subgraph_cfgs
@@ -141,7 +196,7 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
             llm_cluster_analysis=cluster_analysis,
         )
 
-        cache_key = self._analysis_cache.build_key(prompt, self._cache_model_settings)
+        cache_key = self._analysis_cache.build_key(self._system_override + prompt, self._cache_model_settings)
 
         if (cached := self._analysis_cache.load(cache_key)) is not None:
             return cached
@@ -177,12 +232,16 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
     @trace
     def step_api_surfaces(self, analysis: AnalysisInsights) -> ComponentApiSurfaces:
         logger.info(f"[DetailsAgent] Analyzing component API surfaces for: {self.project_name}")
-        static_call_evidence = self.build_scope_cfg_string(analysis)
-        prompt = self.prompts["api_surfaces"].format(
-            component_summaries=analysis.llm_str(),
-            static_call_evidence=static_call_evidence,
-        )
-        return self._parse_invoke(prompt, ComponentApiSurfaces)
+
+        def surfaces(components: list[Component]) -> ComponentApiSurfaces:
+            batch = analysis.model_copy(update={"components": components})
+            prompt = self.prompts["api_surfaces"].format(
+                component_summaries=batch.llm_str(),
+                static_call_evidence=self.build_scope_cfg_string(batch),
+            )
+            return self._parse_invoke(prompt, ComponentApiSurfaces)
+
+        return self._invoke_in_batches(analysis.components, surfaces, ComponentApiSurfaces.merged)
 
     @trace
     def step_relation_analysis(
@@ -193,34 +252,39 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
         cluster_results: dict[str, ClusterResult],
         cfg_graphs: dict[str, CallGraph],
         source_cluster_id_prefix: str,
-    ) -> None:
-        logger.info(f"[DetailsAgent] Discovering component relations for: {self.project_name}")
-        static_call_evidence = self.build_scope_cfg_string(analysis)
-        self.toolkit.context.cluster_analysis = cluster_analysis
-        self.toolkit.context.cluster_results = cluster_results
-        self.toolkit.context.cfg_graphs = cfg_graphs
-        prompt = self.prompts["relation_analysis"].format(
-            component_summaries=analysis.llm_str(),
-            api_surfaces=api_surfaces.llm_str(),
-            static_call_evidence=static_call_evidence,
-        )
-        relation_result = self._invoke_validate(
-            prompt,
-            ComponentRelations,
-            validators=[validate_relations],
-            validation_context=ValidationContext(
-                cluster_results=cluster_results,
-                cfg_graphs=cfg_graphs,
-                repo_dir=str(self.repo_dir),
-                static_analysis=self.static_analysis,
-                llm_cluster_analysis=cluster_analysis,
-                components=analysis.components,
-            ),
-            max_validation_attempts=3,
-        )
-        analysis.components_relations = relation_result.components_relations
+        relations: ComponentRelations | None = None,
+    ) -> ComponentRelations:
+        """Attach the LLM's relations, or the cached *relations*, plus their static edges; returns them unedged."""
+        if relations is None:
+            logger.info(f"[DetailsAgent] Discovering component relations for: {self.project_name}")
+            static_call_evidence = self.build_scope_cfg_string(analysis)
+            self.toolkit.context.cluster_analysis = cluster_analysis
+            self.toolkit.context.cluster_results = cluster_results
+            self.toolkit.context.cfg_graphs = cfg_graphs
+            prompt = self.prompts["relation_analysis"].format(
+                component_summaries=analysis.llm_str(),
+                api_surfaces=api_surfaces.llm_str(),
+                static_call_evidence=static_call_evidence,
+            )
+            relations = self._invoke_validate(
+                prompt,
+                ComponentRelations,
+                validators=[validate_relations],
+                validation_context=ValidationContext(
+                    cluster_results=cluster_results,
+                    cfg_graphs=cfg_graphs,
+                    repo_dir=str(self.repo_dir),
+                    static_analysis=self.static_analysis,
+                    llm_cluster_analysis=cluster_analysis,
+                    components=analysis.components,
+                ),
+                max_validation_attempts=3,
+            )
+        # A copy: the static edges are attached in place and must not reach the cache.
+        analysis.components_relations = relations.model_copy(deep=True).components_relations
         assign_relation_ids(analysis)
         self.build_static_relations(analysis, cfg_graphs, source_cluster_id_prefix=source_cluster_id_prefix)
+        return relations
 
     def run(self, component: Component):
         """
@@ -252,9 +316,20 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
         # Step 2: Group clusters within the subgraph
         cluster_analysis = self.step_clusters_grouping(component, subgraph_cluster_results)
 
-        # Step 3: Generate detailed analysis from grouped clusters
+        # Step 3: Generate detailed analysis from grouped clusters, unless the component's
+        # subgraph is unchanged since its docs were cached: then steps 3, 7 and 8 reuse them.
         # Validation ensures key_entities are within cluster scope (no rescue needed)
-        analysis = self.step_final_analysis(component, cluster_analysis, subgraph_cluster_results, subgraph_cfgs)
+        docs_key = self._docs_cache.build_key(
+            subgraph_cfgs, cluster_analysis, self._cache_model_settings, self._docs_templates
+        )
+        cached_docs = self._docs_cache.load(docs_key)
+        if cached_docs is not None:
+            logger.info(f"[DetailsAgent] Reusing cached docs for unchanged component: {component.name}")
+            analysis = cached_docs.final_analysis
+        else:
+            analysis = self.step_final_analysis(component, cluster_analysis, subgraph_cluster_results, subgraph_cfgs)
+        # Steps 4-11 fill the analysis in place; the cache keeps the LLM's answer.
+        final_analysis = analysis.model_copy(deep=True)
 
         # Step 4: Assign hierarchical component IDs (e.g., "1.1", "1.2" under parent "1")
         assign_component_ids(analysis, parent_id=component.component_id)
@@ -268,17 +343,22 @@ class DetailsAgent(ClusterMethodsMixin, CodeBoardingAgent):
         self.populate_file_methods(analysis, subgraph_cluster_results, subgraph_cfgs)
 
         # Step 7: Analyze component API surfaces
-        api_surfaces = self.step_api_surfaces(analysis)
+        api_surfaces = cached_docs.api_surfaces if cached_docs is not None else self.step_api_surfaces(analysis)
 
         # Step 8: Discover relations from API surfaces and attach deterministic all_edges
-        self.step_relation_analysis(
+        relations = self.step_relation_analysis(
             analysis,
             api_surfaces,
             cluster_analysis,
             subgraph_cluster_results,
             subgraph_cfgs,
             component.component_id,
+            relations=cached_docs.relations if cached_docs is not None else None,
         )
+        if cached_docs is None:
+            self._docs_cache.store(
+                docs_key, ComponentDocs(final_analysis=final_analysis, api_surfaces=api_surfaces, relations=relations)
+            )
 
         # Step 9: Fix source code reference lines (resolves reference_file paths)
         analysis = self.reference_resolver.fix_source_code_reference_lines(analysis)
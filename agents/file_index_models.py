@@ -22,6 +22,22 @@ class MethodEntry(BaseModel):
         default="",
         description="Truncated SHA-256 of the method's source lines; '' when source was unavailable.",
     )
+    kind: str = Field(
+        default="",
+        description="Canonical kind shared across languages (type, function, method, ...); '' until saved.",
+    )
+    receiver_kind: str = Field(
+        default="",
+        description="Go method receiver: 'value', 'pointer', or 'none' for anything else; '' when unknown.",
+    )
+    visibility: str = Field(
+        default="",
+        description="'exported' or 'unexported', read from the name (Go capitalisation); '' when unknown.",
+    )
+    signature: str = Field(
+        default="",
+        description="Declared signature, e.g. 'Compose(fns ...HandlerFunc) HandlerFunc'; '' when not read.",
+    )
 
     def __hash__(self) -> int:
         return hash(self.qualified_name)
@@ -39,9 +55,18 @@ class MethodEntry(BaseModel):
             start_line=node.line_start,
             end_line=node.line_end,
             node_type=node.type.name,
+            receiver_kind=node.receiver_kind.value,
+            visibility=node.visibility.value,
+            signature=signature_str(node),
         )
 
 
+def signature_str(node) -> str:
+    """The node's declared signature under its short name; '' when none was read."""
+    signature = getattr(node, "signature", None)
+    return signature.render(node.fully_qualified_name.rsplit(".", 1)[-1]) if signature is not None else ""
+
+
 class FileMethodGroup(BaseModel):
     """All methods/functions belonging to a component within a single file."""
 
@@ -52,6 +77,31 @@ class FileMethodGroup(BaseModel):
     )
 
 
+class SpecOperationLink(BaseModel):
+    """A generated API client method and the OpenAPI/Swagger operation it implements."""
+
+    qualified_name: str = Field(description="Qualified name of the generated client method.")
+    spec_file: str = Field(description="Repo-relative path of the spec defining the operation.")
+    method: str = Field(description="HTTP method of the operation, upper-case.")
+    path: str = Field(description="Path template of the operation, e.g. /pets/{petId}.")
+    operation_id: str = Field(description="The operation's operationId in the spec.")
+
+
+class HttpRouteLink(BaseModel):
+    """An HTTP route a Go service registers and the handler serving it (``--framework http``)."""
+
+    method: str = Field(description="HTTP method of the route, upper-case; ANY when it matches every method.")
+    path: str = Field(description="Path pattern of the route, group prefixes included, e.g. /v1/users/{id}.")
+    qualified_name: str = Field(description="Qualified name of the handler function or method.")
+
+
+class ExternalPackageCalls(BaseModel):
+    """A package outside the project and how many of its symbols a component calls."""
+
+    package: str = Field(description="Import path of the package, e.g. fmt or github.com/spf13/cobra.")
+    calls: int = Field(description="Distinct (function, symbol) calls from the component into the package.")
+
+
 class FileEntry(BaseModel):
     """Single source of truth for methods in one file."""
 
@@ -93,6 +143,10 @@ class FileEntry(BaseModel):
                 preferred, fallback = indexed, candidate
             preferred.start_line = preferred.start_line or fallback.start_line
             preferred.end_line = preferred.end_line or fallback.end_line
+            preferred.kind = preferred.kind or fallback.kind
+            preferred.receiver_kind = preferred.receiver_kind or fallback.receiver_kind
+            preferred.visibility = preferred.visibility or fallback.visibility
+            preferred.signature = preferred.signature or fallback.signature
             preferred.content_hash = preferred.content_hash or fallback.content_hash
             methods_by_qname[candidate.qualified_name] = preferred
 
@@ -35,6 +35,7 @@ from agents.prompts import (
     get_relation_analysis_message,
     get_system_message,
 )
+from agents.prompts.prompt_templates import PromptTemplates, languages_str
 from agents.relation_edges import index_relation_endpoints
 from agents.scope_ids import ROOT_SCOPE_ID
 from agents.validation import ValidationContext, validate_relations
@@ -60,8 +61,14 @@ class IncrementalAgent(ClusterMethodsMixin, CodeBoardingAgent):
         agent_llm: BaseChatModel,
         parsing_llm: BaseChatModel,
         changes: ChangeSet | None = None,
+        prompt_templates: PromptTemplates = PromptTemplates(),
     ):
-        system_message = format_project_system_message(get_system_message(), project_name, meta_context)
+        system_message = format_project_system_message(
+            prompt_templates.system or get_system_message(),
+            project_name,
+            meta_context,
+            languages_str(static_analysis.get_languages()),
+        )
         super().__init__(repo_dir, static_analysis, system_message, agent_llm, parsing_llm)
         if changes is not None:
             self.toolkit.context.changes = changes
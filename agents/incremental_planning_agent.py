@@ -17,6 +17,7 @@ from agents.agent_responses import (
 )
 from agents.cluster_ids import CodeBoardingClusterIds
 from agents.prompts import format_project_system_message, get_planning_message, get_system_message
+from agents.prompts.prompt_templates import PromptTemplates, languages_str
 from agents.repair import (
     ScopeOperationRepairContext,
     repair_unambiguous_routing_and_optional_key_entity_metadata,
@@ -53,15 +54,19 @@ class IncrementalPlanningAgent(CodeBoardingAgent):
         agent_llm: BaseChatModel,
         parsing_llm: BaseChatModel,
         changes: ChangeSet | None = None,
+        prompt_templates: PromptTemplates = PromptTemplates(),
     ):
-        system_message = format_project_system_message(get_system_message(), project_name, meta_context)
+        system_message = format_project_system_message(
+            prompt_templates.system or get_system_message(),
+            project_name,
+            meta_context,
+            languages_str(static_analysis.get_languages()),
+        )
         super().__init__(repo_dir, static_analysis, system_message, agent_llm, parsing_llm)
         if changes is not None:
             self.toolkit.context.changes = changes
-        self.agent = create_agent(
-            model=agent_llm,
-            tools=[self.toolkit.read_source_reference, self.toolkit.list_git_changes],
-        )
+        self.agent_tools = [self.toolkit.read_source_reference, self.toolkit.list_git_changes]
+        self.agent = create_agent(model=agent_llm, tools=self.agent_tools)
         self.project_name = project_name
         self.meta_context = meta_context
         self.prompt = PromptTemplate(
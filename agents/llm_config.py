@@ -1,5 +1,6 @@
 import logging
 import os
+import threading
 from dataclasses import dataclass, field
 from typing import Any, Type
 
@@ -49,12 +50,26 @@ def _model_accepts_temperature(model_name: str) -> bool:
 # ---------------------------------------------------------------------------
 _agent_model_override: str | None = None
 _parsing_model_override: str | None = None
+# Ordered ``--llm-fallback`` chain (primary first) and the position currently in use.
+# Process-wide: a provider that is down for one agent is down for all of them.
+_fallback_providers: list[str] = []
+_active_provider_index = 0
+_failover_lock = threading.Lock()
+# ``--deterministic``: temperature 0 for every provider and a fixed seed where the client takes one.
+_deterministic = False
+# ``--max-retries``: attempts per LLM request, shared by every agent.
+_max_attempts = LLMDefaults.MAX_ATTEMPTS
+# Clients whose constructor accepts a sampling ``seed``.
+_SEEDABLE_CHAT_CLASSES: tuple[type[BaseChatModel], ...] = (ChatOpenAI, ChatOllama)
 
 
 def configure_models(
     agent_model: str | None = None,
     parsing_model: str | None = None,
     api_keys: dict[str, str] | None = None,
+    fallback_providers: list[str] | None = None,
+    deterministic: bool = False,
+    max_attempts: int | None = None,
 ) -> None:
     """Set process-wide model and provider overrides.  Call this once at startup.
 
@@ -70,10 +85,26 @@ def configure_models(
       2. ``api_keys`` passed here  /  values from ~/.codeboarding/config.toml
       3. AGENT_MODEL / PARSING_MODEL environment variables (for model names)
       4. Provider defaults defined in LLM_PROVIDERS
+
+    ``fallback_providers`` is the ordered ``--llm-fallback`` list (aliases such as
+    ``gemini`` resolved). When set, the
+    first entry is used instead of the single env-selected provider, and agents
+    move down the list when a provider exhausts its retries or fails hard.
+
+    ``deterministic`` (``--deterministic``) pins sampling: temperature 0 regardless of
+    provider defaults, plus ``LLMDefaults.DETERMINISTIC_SEED`` for clients that take a seed.
+
+    ``max_attempts`` (``--max-retries``) caps the attempts per LLM request; default
+    ``LLMDefaults.MAX_ATTEMPTS``.
     """
-    global _agent_model_override, _parsing_model_override
+    global _agent_model_override, _parsing_model_override, _fallback_providers, _active_provider_index
+    global _deterministic, _max_attempts
+    _deterministic = deterministic
+    _max_attempts = max_attempts or LLMDefaults.MAX_ATTEMPTS
     _agent_model_override = agent_model
     _parsing_model_override = parsing_model
+    _fallback_providers = [PROVIDER_ALIASES.get(name, name) for name in fallback_providers or []]
+    _active_provider_index = 0
     if api_keys:
         for env_var, value in api_keys.items():
             if value and not os.environ.get(env_var):
@@ -107,6 +138,20 @@ class LLMConfig:
     parsing_temperature: float = LLMDefaults.DEFAULT_PARSING_TEMPERATURE
     extra_args: dict[str, Any] = field(default_factory=dict)
     api_key_env: str | None = None
+    structured_output: str | None = None
+    """``with_structured_output`` method this provider honours for typed responses.
+
+    ``"json_schema"`` (OpenAI ``response_format``, Gemini response schema) or
+    ``"function_calling"`` (Anthropic / Bedrock tool use). None for providers,
+    including most OpenAI-compatible endpoints, whose schema support is uneven;
+    their responses are parsed out of text instead.
+    """
+    alias_key_envs: tuple[str, ...] = ()
+    """Further env vars holding the same secret, read when ``api_key_env`` is unset."""
+    default_base_url: str | None = None
+    """Endpoint assumed when ``--provider``/``--llm-fallback`` names this provider but no selection env var is set."""
+    supported_models: tuple[str, ...] = ()
+    """Agent models this provider is known to serve; ``--model`` warns on others. Empty for gateways and self-hosted."""
     keyless_capable: bool = False
     """Whether this provider can run without a real API key.
 
@@ -117,7 +162,9 @@ class LLMConfig:
     """
 
     def get_api_key(self) -> str | None:
-        return os.getenv(self.api_key_env) if self.api_key_env else None
+        if self.api_key_env is None:
+            return None
+        return next((value for env in (self.api_key_env, *self.alias_key_envs) if (value := os.getenv(env))), None)
 
     def has_real_api_key(self) -> bool:
         """True if the provider's API-key env var holds a value.
@@ -149,6 +196,8 @@ LLM_PROVIDERS = {
         agent_model="gpt-4o",
         parsing_model="gpt-4o-mini",
         llm_type=LLMType.GPT4,
+        structured_output="json_schema",
+        supported_models=("gpt-4o", "gpt-4o-mini", "gpt-4.1", "gpt-4.1-mini", "gpt-4.1-nano", "o3", "o4-mini"),
         keyless_capable=True,
         extra_args={
             "base_url": lambda: os.getenv("OPENAI_BASE_URL"),
@@ -178,6 +227,7 @@ LLM_PROVIDERS = {
         agent_model="claude-sonnet-4-6",
         parsing_model="claude-haiku-4-5",
         llm_type=LLMType.CLAUDE,
+        structured_output="function_calling",
         extra_args={
             "max_tokens": 8192,
             "timeout": None,
@@ -186,11 +236,14 @@ LLM_PROVIDERS = {
     ),
     "google": LLMConfig(
         chat_class=ChatGoogleGenerativeAI,
-        selection_envs=["GOOGLE_API_KEY"],
+        # GEMINI_API_KEY is the same Generative Language API key, as the Gemini SDKs name it.
+        selection_envs=["GOOGLE_API_KEY", "GEMINI_API_KEY"],
         api_key_env="GOOGLE_API_KEY",
+        alias_key_envs=("GEMINI_API_KEY",),
         agent_model="gemini-3-flash-preview",
         parsing_model="gemini-3.1-flash-lite",
         llm_type=LLMType.GEMINI_FLASH,
+        structured_output="json_schema",
         extra_args={
             "max_tokens": None,
             "timeout": None,
@@ -204,6 +257,7 @@ LLM_PROVIDERS = {
         agent_model="anthropic.claude-sonnet-4-6",
         parsing_model="claude-haiku-4-5",
         llm_type=LLMType.CLAUDE_SONNET,
+        structured_output="function_calling",
         extra_args={
             "max_tokens": 4096,
             "region_name": lambda: os.getenv("AWS_DEFAULT_REGION", "us-east-1"),
@@ -229,6 +283,7 @@ LLM_PROVIDERS = {
         # when no base_url is passed, and sends OLLAMA_API_KEY (Ollama cloud) itself.
         selection_envs=["OLLAMA_BASE_URL", "OLLAMA_HOST"],
         api_key_env="OLLAMA_API_KEY",
+        default_base_url=LLMDefaults.OLLAMA_HOST,
         keyless_capable=True,
         agent_model="qwen3:30b",
         parsing_model="qwen2.5:7b",
@@ -315,6 +370,10 @@ LLM_PROVIDERS = {
 }
 
 
+# Other names ``--provider``/``--llm-fallback`` accept for a provider.
+PROVIDER_ALIASES = {"gemini": "google"}
+
+
 def _all_selection_envs() -> list[str]:
     return sorted({var for config in LLM_PROVIDERS.values() for var in config.selection_envs})
 
@@ -334,6 +393,41 @@ def selected_providers() -> list[str]:
     return [name for name, config in LLM_PROVIDERS.items() if config.is_selected_by_env()]
 
 
+def provider_chain() -> list[str]:
+    """Providers in failover order: the ``--llm-fallback`` list, else the env-selected one."""
+    if _fallback_providers:
+        return list(_fallback_providers)
+    return selected_providers()[:1]
+
+
+def active_provider() -> str | None:
+    """The provider LLMs are currently initialized from, or None when none is configured."""
+    chain = provider_chain()
+    return chain[min(_active_provider_index, len(chain) - 1)] if chain else None
+
+
+def can_fail_over() -> bool:
+    """True while the chain still has a provider after the active one."""
+    return _active_provider_index + 1 < len(provider_chain())
+
+
+def fail_over(failed_provider: str) -> bool:
+    """Move the active provider past *failed_provider*; False when the chain is exhausted.
+
+    Agents sharing a provider hit its outage concurrently, so only the first
+    caller advances the chain; later callers just pick up the new active one.
+    """
+    global _active_provider_index
+    with _failover_lock:
+        if active_provider() != failed_provider:
+            return active_provider() is not None
+        if not can_fail_over():
+            return False
+        _active_provider_index += 1
+        logger.warning(f"LLM provider '{failed_provider}' failed; failing over to '{active_provider()}'")
+        return True
+
+
 def _initialize_llm(
     model_override: str | None,
     model_attr: str,
@@ -360,8 +454,12 @@ def _initialize_llm(
 
     kwargs: dict[str, Any] = {"model": model_name}
     if _model_accepts_temperature(model_name):
-        kwargs["temperature"] = getattr(config, temperature_attr)
+        kwargs["temperature"] = 0 if _deterministic else getattr(config, temperature_attr)
+    elif _deterministic:
+        logger.warning(f"{model_name} rejects sampling parameters; --deterministic cannot pin its temperature")
     kwargs.update(config.get_resolved_extra_args())
+    if _deterministic and isinstance(config.chat_class, type) and issubclass(config.chat_class, _SEEDABLE_CHAT_CLASSES):
+        kwargs["seed"] = LLMDefaults.DETERMINISTIC_SEED
 
     # ChatBedrockConverse and ChatOllama take no api_key kwarg; their SDKs read
     # AWS_BEARER_TOKEN_BEDROCK / OLLAMA_API_KEY from the environment directly.
@@ -377,12 +475,20 @@ def _resolve_selected_provider(
     model_override: str | None,
     model_attr: str,
 ) -> tuple[str, LLMConfig, str] | None:
-    """Return the selected provider, config, and resolved model name."""
-    for name, config in LLM_PROVIDERS.items():
-        if not config.is_selected_by_env():
-            continue
-        return name, config, model_override or getattr(config, model_attr)
-    return None
+    """Return the active provider, config, and resolved model name."""
+    name = active_provider()
+    if name is None:
+        return None
+    config = LLM_PROVIDERS[name]
+    # Why: model overrides name the primary provider's models; a fallback uses its own defaults.
+    if _active_provider_index > 0:
+        model_override = None
+    return name, config, model_override or getattr(config, model_attr)
+
+
+def llm_max_attempts() -> int:
+    """Attempts per LLM request before the retry loop gives up (``--max-retries``)."""
+    return _max_attempts
 
 
 class LLMConfigError(ValueError):
@@ -401,6 +507,9 @@ def validate_api_key_provided() -> None:
     surfaced, and a key set for an unselected provider (e.g. LITELLM_API_KEY
     without LITELLM_BASE_URL) is reported rather than silently ignored.
     """
+    if _fallback_providers:
+        _validate_fallback_chain()
+        return
     hints = _unselected_key_hints()
     selected = selected_providers()
     if not selected:
@@ -422,6 +531,46 @@ def validate_api_key_provided() -> None:
         )
 
 
+def validate_agent_model(model_name: str) -> None:
+    """Warn when *model_name* (``--model``) is not among the primary provider's known models.
+
+    The list lags the provider's releases, so an unlisted name still runs. Skipped for providers without a model
+    list and for a custom ``base_url``, whose endpoint serves its own models.
+    """
+    name = provider_chain()[0] if provider_chain() else None
+    config = LLM_PROVIDERS.get(name) if name else None
+    if config is None or not config.supported_models or config.get_resolved_extra_args().get("base_url"):
+        return
+    if model_name not in config.supported_models:
+        logger.warning(
+            "Unknown %s model '%s'; trying it anyway. Known models: %s.",
+            name,
+            model_name,
+            ", ".join(config.supported_models),
+        )
+
+
+def _validate_fallback_chain() -> None:
+    """Every ``--llm-fallback`` (or ``--provider``) entry must be a known provider the environment selects."""
+    unknown = [name for name in _fallback_providers if name not in LLM_PROVIDERS]
+    if unknown:
+        raise LLMConfigError(
+            f"Unknown provider(s): {', '.join(unknown)}. Choose from: {', '.join(LLM_PROVIDERS)}."
+        )
+    unconfigured = [
+        name
+        for name in _fallback_providers
+        if not LLM_PROVIDERS[name].is_selected_by_env() and LLM_PROVIDERS[name].default_base_url is None
+    ]
+    if unconfigured:
+        needs = [f"{name} needs {' or '.join(LLM_PROVIDERS[name].selection_envs)}" for name in unconfigured]
+        raise LLMConfigError(f"LLM provider(s) not configured: {'; '.join(needs)}.")
+    if len(_fallback_providers) == 1:
+        logger.info(f"LLM provider: {_fallback_providers[0]}")
+    else:
+        logger.info(f"LLM provider failover order: {' -> '.join(_fallback_providers)}")
+
+
 def initialize_agent_llm(model_override: str | None = None) -> BaseChatModel:
     model, model_name = _initialize_llm(model_override, "agent_model", "agent_temperature", "", init_factory=True)
     MONITORING_CALLBACK.model_name = model_name
@@ -431,9 +580,10 @@ def initialize_agent_llm(model_override: str | None = None) -> BaseChatModel:
 def get_current_agent_context_window() -> ContextWindow:
     """Context window for the currently selected agent provider/model.
 
-    Resolves the first selected provider (same rule as ``_initialize_llm``) on
-    every call. ``get_context_window`` handles its own caching, so this is
-    cheap enough to call without a module-level cache.
+    Resolves the active provider (same rule as ``_initialize_llm``) on every
+    call, so prompt budgets follow a ``--llm-fallback`` failover.
+    ``get_context_window`` handles its own caching, so this is cheap enough to
+    call without a module-level cache.
     """
     resolved = _resolve_selected_provider(_agent_model_override or os.getenv("AGENT_MODEL"), "agent_model")
     if resolved is not None:
@@ -454,6 +604,15 @@ def get_current_agent_model_ref() -> str:
     return f"{name}/{model_name}"
 
 
+def get_current_agent_model() -> tuple[str, str] | None:
+    """``(provider, model)`` for the currently active agent LLM, or None when no provider is configured."""
+    resolved = _resolve_selected_provider(_agent_model_override or os.getenv("AGENT_MODEL"), "agent_model")
+    if resolved is None:
+        return None
+    name, _config, model_name = resolved
+    return name, model_name
+
+
 def current_provider_key_context() -> tuple[str, str]:
     """The selected provider name and a masked key tail, for auth-error messages.
 
@@ -462,10 +621,9 @@ def current_provider_key_context() -> tuple[str, str]:
     selected or the SDK reads its own credentials, e.g. AWS/Ollama). Never
     returns the full secret — only enough for the user to recognize which key.
     """
-    selected = selected_providers()
-    if not selected:
+    name = active_provider()
+    if name is None:
         return "unknown", "unknown"
-    name = selected[0]
     key = LLM_PROVIDERS[name].get_api_key()
     key_tail = key[-4:] if key and len(key) >= 4 else "unknown"
     return name, key_tail
@@ -489,3 +647,16 @@ def supports_prompt_caching(llm: BaseChatModel) -> bool:
     today; other providers either cache transparently or not at all.
     """
     return llm.__class__.__module__.startswith("langchain_anthropic")
+
+
+def structured_output_method(llm: BaseChatModel) -> str | None:
+    """The ``with_structured_output`` method to request typed output from *llm* with, or None.
+
+    None when *llm* is not the active provider's chat model (e.g. a test double)
+    or the provider has no reliable native mode; callers then parse text.
+    """
+    provider = active_provider()
+    config = LLM_PROVIDERS.get(provider) if provider else None
+    if config is None or not isinstance(llm, config.chat_class):
+        return None
+    return config.structured_output
@@ -1,4 +1,4 @@
-"""Provider-agnostic detection and typing of LLM authentication failures.
+"""Provider-agnostic detection and typing of LLM authentication failures and rate limits.
 
 A rejected API key (HTTP 401, or a provider's equivalent auth/permission
 error) is *permanent* for the run: retrying it wastes minutes of backoff and
@@ -11,11 +11,22 @@ raised it.
 provider, a masked key tail, and the provider's own message, and forwards them
 as ``telemetry_properties`` so the PostHog ``$exception`` event — and the
 dashboard's structured ``error`` columns — populate instead of staying null.
+
+``is_rate_limited`` recognizes the *transient* opposite: quota, rate-limit and
+overload responses, which the retry loop waits out with its longest backoff.
+When that does not help, or the request alone exceeds the model's token limit
+(``is_request_too_large``), the call fails with :class:`LLMBudgetError`.
+
+``is_safety_blocked`` recognizes a Gemini safety block (a prompt or answer
+stopped for ``SAFETY``, ``PROHIBITED_CONTENT`` and the like). Sampling at
+temperature 0 blocks the same request again, so it fails at once with
+:class:`LLMSafetyError` rather than being retried.
 """
 
 from __future__ import annotations
 
 import re
+from collections.abc import Mapping
 
 # Process exit code for a rejected key. Distinct from 1 (generic failure) so the
 # OSS CLI and the wrapper subprocess both signal "fix your key" the same way, and
@@ -35,6 +46,37 @@ _AUTH_TYPE_NAMES = {
     "UnrecognizedClientException",
 }
 
+# Class names meaning "slow down": google's ``ResourceExhausted``, openai/anthropic's
+# ``RateLimitError``, anthropic's ``OverloadedError`` and Bedrock's ``ThrottlingException``.
+_RATE_LIMIT_TYPE_NAMES = {"ResourceExhausted", "RateLimitError", "OverloadedError", "ThrottlingException"}
+# 429 Too Many Requests, and Anthropic's 529 "overloaded".
+_RATE_LIMIT_STATUS_CODES = (429, 529)
+# Gemini's quota and overload answers, which langchain re-wraps so that only the text keeps the status.
+_RATE_LIMIT_MESSAGE_PATTERNS = (
+    re.compile(r"\bRESOURCE_EXHAUSTED\b"),
+    re.compile(r"model is overloaded", re.IGNORECASE),
+)
+
+# Gemini's finish and block reasons for a prompt or an answer its safety filters stopped.
+SAFETY_FINISH_REASONS = frozenset({"SAFETY", "PROHIBITED_CONTENT", "BLOCKLIST", "SPII", "IMAGE_SAFETY"})
+_SAFETY_MESSAGE_PATTERNS = (
+    re.compile(
+        r"\b(?:finish|block)_?reason\b\W*(?:\w+\.)?(?:SAFETY|PROHIBITED_CONTENT|BLOCKLIST|SPII)\b", re.IGNORECASE
+    ),
+    re.compile(r"blocked (?:by|due to|for) safety", re.IGNORECASE),
+)
+
+# A request over the model's per-request or per-minute token limit: resending it unchanged can never succeed.
+# OpenAI answers 429 "Request too large ... Requested 45000", Anthropic 400 "prompt is too long",
+# Gemini 400 "The input token count (1200000) exceeds the maximum number of tokens allowed".
+_TOO_LARGE_MESSAGE_PATTERNS = (
+    re.compile(r"request too large", re.IGNORECASE),
+    re.compile(r"prompt is too long", re.IGNORECASE),
+    re.compile(r"context[\s_]length[\s_]exceeded", re.IGNORECASE),
+    re.compile(r"maximum context length", re.IGNORECASE),
+    re.compile(r"input token count.*exceeds the maximum", re.IGNORECASE),
+)
+
 # Substrings in a provider's message that indicate an auth failure even when the
 # status code isn't exposed on the exception (e.g. errors re-wrapped by langchain
 # or botocore, where only the string survives).
@@ -70,6 +112,21 @@ class LLMAuthError(RuntimeError):
         self.telemetry_properties = telemetry_properties
 
 
+class LLMSafetyError(RuntimeError):
+    """The provider's safety filters blocked the request or its answer; resending it would be blocked again."""
+
+
+class LLMBudgetError(RuntimeError):
+    """An LLM request could not fit the provider's limits: still rate-limited after every retry, or too large.
+
+    Raised instead of retrying forever, so the component fails and the run moves on.
+    """
+
+
+class LLMRequestTooLargeError(LLMBudgetError):
+    """An LLM request exceeds the model's token limit; a smaller prompt may still fit."""
+
+
 def _status_code(exc: BaseException) -> int | None:
     code = getattr(exc, "status_code", None)
     if code is None:
@@ -92,6 +149,34 @@ def _is_auth_failure(exc: BaseException) -> bool:
     return any(p.search(text) for p in _AUTH_MESSAGE_PATTERNS)
 
 
+def is_rate_limited(exc: BaseException) -> bool:
+    """True when *exc* is a provider's rate-limit, quota or overload response."""
+    if type(exc).__name__ in _RATE_LIMIT_TYPE_NAMES or _status_code(exc) in _RATE_LIMIT_STATUS_CODES:
+        return True
+    text = str(exc)
+    return any(p.search(text) for p in _RATE_LIMIT_MESSAGE_PATTERNS)
+
+
+def is_safety_blocked(exc: BaseException) -> bool:
+    """True when *exc* reports a prompt or an answer blocked by the provider's safety filters."""
+    if isinstance(exc, LLMSafetyError):
+        return True
+    text = str(exc)
+    return any(p.search(text) for p in _SAFETY_MESSAGE_PATTERNS)
+
+
+def safety_finish_reason(response_metadata: Mapping[str, object]) -> str | None:
+    """The reason a Gemini answer was cut off by its safety filters, read from a message's ``response_metadata``."""
+    reason = str(response_metadata.get("finish_reason") or "").rsplit(".", 1)[-1].upper()
+    return reason if reason in SAFETY_FINISH_REASONS else None
+
+
+def is_request_too_large(exc: BaseException) -> bool:
+    """True when *exc* says the request exceeds the model's token limit."""
+    text = str(exc)
+    return any(p.search(text) for p in _TOO_LARGE_MESSAGE_PATTERNS)
+
+
 def detect_auth_error(exc: BaseException, *, provider: str, key_tail: str) -> LLMAuthError | None:
     """Return an :class:`LLMAuthError` if *exc* is an auth failure, else ``None``.
 
@@ -35,15 +35,13 @@ class MetaAgent(CodeBoardingAgent):
             template=get_meta_information_prompt(), input_variables=["project_name"]
         )
 
-        self.agent = create_agent(
-            model=agent_llm,
-            tools=[
-                self.toolkit.read_docs,
-                self.toolkit.read_file,
-                self.toolkit.external_deps,
-                self.toolkit.read_file_structure,
-            ],
-        )
+        self.agent_tools = [
+            self.toolkit.read_docs,
+            self.toolkit.read_file,
+            self.toolkit.external_deps,
+            self.toolkit.read_file_structure,
+        ]
+        self.agent = create_agent(model=agent_llm, tools=self.agent_tools)
 
         self._meta_cache = MetaCache(repo_dir=repo_dir, ignore_manager=self.ignore_manager)
 
@@ -8,7 +8,7 @@ from dataclasses import dataclass
 from functools import lru_cache
 from pathlib import Path
 
-from agents.constants import ModelCapabilities
+from agents.constants import LLMDefaults, ModelCapabilities
 from utils import get_cache_dir
 
 logger = logging.getLogger(__name__)
@@ -18,6 +18,8 @@ _BEDROCK_REGION = re.compile(r"^(us|eu|apac|global|au|ca|us-gov)\.")
 # Why: cached by hand (not via @lru_cache) so a transient Ollama outage -- user starts the
 # app before `ollama serve` is up -- doesn't memoize None for the rest of the process.
 _OLLAMA_CACHE: dict[tuple[str, str], tuple[int, int]] = {}
+# Same for the Generative Language API, keyed by model: the limits don't depend on the key.
+_GEMINI_CACHE: dict[str, tuple[int, int]] = {}
 
 
 @dataclass(frozen=True)
@@ -32,6 +34,7 @@ def get_context_window(provider: str, model_name: str) -> ContextWindow:
         _resolve_env,
         _resolve_user_config,
         _resolve_ollama,
+        _resolve_gemini,
         _resolve_modelsdev,
         _resolve_litellm,
         _resolve_openrouter,
@@ -44,6 +47,74 @@ def get_context_window(provider: str, model_name: str) -> ContextWindow:
     return ContextWindow(ModelCapabilities.FALLBACK_INPUT, ModelCapabilities.FALLBACK_OUTPUT, is_fallback=True)
 
 
+@dataclass(frozen=True)
+class ModelPricing:
+    # USD per million tokens.
+    input_per_million: float
+    output_per_million: float
+
+    def cost(self, input_tokens: int, output_tokens: int) -> float:
+        return (input_tokens * self.input_per_million + output_tokens * self.output_per_million) / 1_000_000
+
+
+def get_pricing(provider: str, model_name: str) -> ModelPricing | None:
+    """Token prices for a model, from the same catalogs as the context window; None when no catalog lists it."""
+    if provider == "ollama":
+        return ModelPricing(0.0, 0.0)
+    for resolver in (_price_env, _price_modelsdev, _price_litellm, _price_openrouter):
+        hit = resolver(provider, model_name)
+        if hit is not None:
+            return ModelPricing(*hit)
+    return None
+
+
+def price_env_var(provider: str, model_name: str) -> str:
+    """The environment variable that prices *model_name* when no catalog does."""
+    return f"CB_PRICE_{provider.upper()}_{re.sub(r'[^A-Z0-9]', '_', model_name.upper())}"
+
+
+def _price_env(provider: str, model_name: str) -> tuple[float, float] | None:
+    key = price_env_var(provider, model_name)
+    val = os.getenv(key)
+    if not val:
+        return None
+    try:
+        inp, out = (float(part) for part in val.split(","))
+    except ValueError as e:
+        logger.warning(f"Ignoring malformed {key}={val!r}; expected 'input,output' USD per 1M tokens ({e})")
+        return None
+    return inp, out
+
+
+def _price_modelsdev(provider: str, model_name: str) -> tuple[float, float] | None:
+    data = _load("modelsdev")
+    slug = ModelCapabilities.MODELSDEV_SLUG.get(provider, provider)
+    cost = (data.get(slug, {}).get("models", {}).get(model_name) or {}).get("cost") or {}
+    if cost.get("input") is None or cost.get("output") is None:
+        return None
+    # models.dev already prices per million tokens.
+    return float(cost["input"]), float(cost["output"])
+
+
+def _price_litellm(provider: str, model_name: str) -> tuple[float, float] | None:
+    data = _load("litellm")
+    base = _BEDROCK_REGION.sub("", model_name) if provider == "aws" else model_name
+    for key in (base, f"{provider}/{base}", f"bedrock/{base}"):
+        entry = data.get(key) or {}
+        inp, out = entry.get("input_cost_per_token"), entry.get("output_cost_per_token")
+        if inp is not None and out is not None:
+            return float(inp) * 1_000_000, float(out) * 1_000_000
+    return None
+
+
+def _price_openrouter(provider: str, model_name: str) -> tuple[float, float] | None:
+    pricing = (_load("openrouter").get(_openrouter_id(provider, model_name)) or {}).get("pricing") or {}
+    try:
+        return float(pricing["prompt"]) * 1_000_000, float(pricing["completion"]) * 1_000_000
+    except (KeyError, TypeError, ValueError):
+        return None
+
+
 def _resolve_env(provider: str, model_name: str) -> tuple[int, int] | None:
     key = f"CB_CTX_{provider.upper()}_{re.sub(r'[^A-Z0-9]', '_', model_name.upper())}"
     val = os.getenv(key)
@@ -82,9 +153,7 @@ def _user_context_window_override() -> int | None:
 def _resolve_ollama(provider: str, model_name: str) -> tuple[int, int] | None:
     if provider != "ollama":
         return None
-    base = os.getenv("OLLAMA_BASE_URL") or os.getenv("OLLAMA_HOST")
-    if not base:
-        return None
+    base = os.getenv("OLLAMA_BASE_URL") or os.getenv("OLLAMA_HOST") or LLMDefaults.OLLAMA_HOST
     if "://" not in base:
         # OLLAMA_HOST conventionally allows bare host:port.
         base = f"http://{base}"
@@ -130,6 +199,35 @@ def _parse_num_ctx(params: str) -> int | None:
     return int(m.group(1)) if m else None
 
 
+def _resolve_gemini(provider: str, model_name: str) -> tuple[int, int] | None:
+    # Why: Gemini windows run to millions of tokens and new models reach the catalogs late;
+    # asking the API keeps the prompt budget from falling back to an OpenAI-sized window.
+    if provider != "google":
+        return None
+    api_key = os.getenv("GOOGLE_API_KEY") or os.getenv("GEMINI_API_KEY")
+    if not api_key:
+        return None
+    cached = _GEMINI_CACHE.get(model_name)
+    if cached is not None:
+        return cached
+    try:
+        req = urllib.request.Request(
+            f"{ModelCapabilities.GEMINI_MODELS_URL}/{model_name.removeprefix('models/')}",
+            headers={"x-goog-api-key": api_key},
+        )
+        with urllib.request.urlopen(req, timeout=3) as r:
+            info = json.load(r)
+    except Exception as e:
+        logger.warning(f"Gemini models.get failed for {model_name} ({e})")
+        return None
+    inp = info.get("inputTokenLimit")
+    if not inp:
+        return None
+    result = (int(inp), int(info.get("outputTokenLimit") or ModelCapabilities.FALLBACK_OUTPUT))
+    _GEMINI_CACHE[model_name] = result
+    return result
+
+
 def _resolve_modelsdev(provider: str, model_name: str) -> tuple[int, int] | None:
     data = _load("modelsdev")
     slug = ModelCapabilities.MODELSDEV_SLUG.get(provider, provider)
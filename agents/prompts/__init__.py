@@ -33,6 +33,7 @@ from .prompt_factory import (
     get_scope_relations_message,
     get_api_surfaces_message,
     get_relation_analysis_message,
+    get_component_question_message,
 )
 
 
@@ -80,6 +81,7 @@ __all__ = [
     "get_scope_relations_message",
     "get_api_surfaces_message",
     "get_relation_analysis_message",
+    "get_component_question_message",
     # Prompt constants (available via __getattr__)
     "SYSTEM_MESSAGE",
     "CLUSTER_GROUPING_MESSAGE",
@@ -76,6 +76,9 @@ class AbstractPromptFactory(ABC):
     def get_relation_analysis_message(self) -> str:
         return RELATION_ANALYSIS_MESSAGE
 
+    def get_component_question_message(self) -> str:
+        return COMPONENT_QUESTION_MESSAGE
+
 
 API_SURFACES_MESSAGE = """Analyze the component API surfaces.
 
@@ -112,3 +115,16 @@ For each relation:
 - evidence should concisely explain the communication mechanism
 - key_edges should contain 1-3 important source-to-target code references when possible, similar to key_entities
 - avoid generic implementation-only calls and avoid adding relations solely because a static edge exists"""
+
+
+COMPONENT_QUESTION_MESSAGE = """Answer a question about one component of a software project, using only the analysis data below.
+
+{component_context}
+
+Question: {question}
+
+Rules:
+- Ground every claim in the data above; if it does not contain the answer, say what is missing instead of guessing
+- Cite the symbols, files and relations your answer relies on, writing each symbol's qualified name in backticks exactly as listed
+- Never cite a symbol that does not appear above
+- Keep the answer short: a direct answer first, then the supporting evidence"""
@@ -133,12 +133,14 @@ def format_project_system_message(
     template: str,
     project_name: str,
     meta_context: MetaAnalysisInsights | None,
+    language: str = "unknown",
 ) -> str:
     """Render a provider system prompt with the shared project context."""
     return template.format(
         project_name=project_name,
         project_type=meta_context.project_type if meta_context else "unknown",
         meta_context=meta_context.llm_str() if meta_context else "No project context available.",
+        language=language,
     )
 
 
@@ -209,3 +211,7 @@ def get_api_surfaces_message() -> str:
 
 def get_relation_analysis_message() -> str:
     return get_global_factory()._prompt_factory.get_relation_analysis_message()
+
+
+def get_component_question_message() -> str:
+    return get_global_factory()._prompt_factory.get_component_question_message()
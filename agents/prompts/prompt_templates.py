@@ -0,0 +1,121 @@
+"""User-supplied prompts for documentation generation (``--prompt-dir``).
+
+The directory may hold two files, each replacing one built-in prompt and
+written with ``{variable}`` placeholders (``{{`` and ``}}`` for literal braces):
+
+- ``system.md``: the system prompt of the agents writing the overview and each
+  component's docs. Variables: ``project_name``, ``project_type`` and
+  ``meta_context`` (what the project is, as found by the meta agent), and
+  ``language`` (the project's languages, e.g. ``go, python``).
+- ``component.md``: the prompt describing one component's subcomponents.
+  Variables: ``component_name``, ``component`` (its name, description and key
+  entities), ``entities`` (its key entities, one ``- name (file)`` line each),
+  ``edge_summary`` (how many calls link its functions and the most called
+  ones), ``language`` (the component's languages) and ``cluster_analysis``
+  (the groups of functions to name and describe, one subcomponent per group).
+  ``{cluster_analysis}`` is required: the answer is checked against its groups.
+
+A missing file keeps the built-in prompt for the model in use. Starting points
+ship in ``agents/prompts/templates/``. An unknown variable, a missing
+``{cluster_analysis}`` or a stray brace fails the run before any LLM request.
+"""
+
+import re
+import string
+from collections import Counter
+from collections.abc import Iterable, Mapping
+from dataclasses import dataclass
+from pathlib import Path
+
+from agents.agent_responses import Component
+from static_analyzer.graph import CallGraph
+
+SYSTEM_TEMPLATE = "system.md"
+COMPONENT_TEMPLATE = "component.md"
+DEFAULT_TEMPLATES_DIR = Path(__file__).parent / "templates"
+
+SYSTEM_VARIABLES = frozenset({"project_name", "project_type", "meta_context", "language"})
+COMPONENT_VARIABLES = frozenset(
+    {"component_name", "component", "entities", "edge_summary", "language", "cluster_analysis"}
+)
+_REQUIRED_COMPONENT_VARIABLES = ("cluster_analysis",)
+# Functions named in ``edge_summary``.
+MOST_CALLED_LIMIT = 5
+
+
+class PromptTemplateError(ValueError):
+    pass
+
+
+def _check(name: str, template: str, allowed: frozenset[str], required: tuple[str, ...] = ()) -> None:
+    try:
+        fields = [field for _, field, _, _ in string.Formatter().parse(template) if field is not None]
+    except ValueError as e:
+        raise PromptTemplateError(f"{name}: {e}; write {{{{ and }}}} for literal braces") from e
+    used = {re.split(r"[.\[]", field, maxsplit=1)[0] for field in fields}
+    if unknown := sorted(used - allowed):
+        listed = ", ".join(f"{{{variable}}}" for variable in unknown)
+        raise PromptTemplateError(f"{name}: unknown variable(s) {listed}; available: {', '.join(sorted(allowed))}")
+    if missing := [variable for variable in required if variable not in used]:
+        raise PromptTemplateError(f"{name}: must include {', '.join(f'{{{variable}}}' for variable in missing)}")
+
+
+@dataclass(frozen=True)
+class PromptTemplates:
+    """The prompts of a ``--prompt-dir``; None where the built-in one is kept."""
+
+    system: str | None = None
+    component: str | None = None
+
+    @classmethod
+    def load(cls, directory: Path) -> "PromptTemplates":
+        """Read and check the templates in *directory*; raises ``PromptTemplateError`` or ``OSError``."""
+        if not directory.is_dir():
+            raise PromptTemplateError(f"{directory}: not a directory")
+        texts = {}
+        for name in (SYSTEM_TEMPLATE, COMPONENT_TEMPLATE):
+            path = directory / name
+            texts[name] = path.read_text(encoding="utf-8") if path.is_file() else None
+        if all(text is None for text in texts.values()):
+            raise PromptTemplateError(f"{directory}: has neither {SYSTEM_TEMPLATE} nor {COMPONENT_TEMPLATE}")
+        templates = cls(system=texts[SYSTEM_TEMPLATE], component=texts[COMPONENT_TEMPLATE])
+        if templates.system is not None:
+            _check(SYSTEM_TEMPLATE, templates.system, SYSTEM_VARIABLES)
+        if templates.component is not None:
+            _check(COMPONENT_TEMPLATE, templates.component, COMPONENT_VARIABLES, _REQUIRED_COMPONENT_VARIABLES)
+        return templates
+
+
+def prompt_templates(directory: Path | None) -> PromptTemplates:
+    """The templates of the ``--prompt-dir`` *directory*; empty without one."""
+    return PromptTemplates.load(directory) if directory is not None else PromptTemplates()
+
+
+def languages_str(languages: Iterable[object]) -> str:
+    """``go, python``: the ``language`` variable."""
+    return ", ".join(sorted({str(language) for language in languages})) or "unknown"
+
+
+def entities_str(component: Component) -> str:
+    lines = [
+        f"- {reference.qualified_name}" + (f" ({reference.reference_file})" if reference.reference_file else "")
+        for reference in component.key_entities
+    ]
+    return "\n".join(lines) or "No key entities."
+
+
+def edge_summary(cfgs: Mapping[str, CallGraph]) -> str:
+    """How many calls link the functions of *cfgs*, and which are called most."""
+    callers: Counter[str] = Counter()
+    functions: set[str] = set()
+    calls = 0
+    for cfg in cfgs.values():
+        functions.update(cfg.nodes)
+        for edge in cfg.edges:
+            calls += 1
+            if not edge.recursive:
+                callers[edge.get_destination()] += 1
+    if not calls:
+        return f"No calls between the {len(functions)} functions of this component."
+    most_called = ", ".join(f"{name} ({count})" for name, count in callers.most_common(MOST_CALLED_LIMIT))
+    return f"{calls} calls between {len(functions)} functions; most called (callers): {most_called}."
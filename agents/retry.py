@@ -27,12 +27,15 @@ The helper here owns the loop structure. Callers supply:
 from __future__ import annotations
 
 import logging
+import random
 import time
 from collections.abc import Callable
 from dataclasses import dataclass
 from enum import Enum, auto
 from typing import TypeVar
 
+from monitoring.progress import get_progress
+
 logger = logging.getLogger(__name__)
 
 T = TypeVar("T")
@@ -55,10 +58,17 @@ class RetryDecision:
     backoff_s: float = 0.0
 
 
-def default_backoff(attempt: int, *, initial_s: float, multiplier: float, max_s: float | None) -> float:
-    """Standard exponential backoff: ``initial * multiplier**attempt`` clamped to ``max_s``."""
+def default_backoff(
+    attempt: int, *, initial_s: float, multiplier: float, max_s: float | None, jitter: float = 0.0
+) -> float:
+    """Standard exponential backoff: ``initial * multiplier**attempt`` clamped to ``max_s``.
+
+    A *jitter* of 0.25 shortens the delay by a random 0-25%.
+    """
     delay = initial_s * (multiplier**attempt)
-    return min(delay, max_s) if max_s is not None else delay
+    if max_s is not None:
+        delay = min(delay, max_s)
+    return delay * (1 - random.uniform(0, jitter)) if jitter else delay
 
 
 def _default_classify(_exc: Exception, _attempt: int) -> RetryDecision:
@@ -101,6 +111,8 @@ def with_retries(
                     exc,
                     decision.backoff_s,
                 )
+                # A long backoff would otherwise look like a hung run to ``--progress`` users.
+                get_progress().retry(log_prefix, attempt + 1, max_attempts, decision.backoff_s, exc)
                 time.sleep(decision.backoff_s)
             else:  # RETRY_NOW
                 logger.warning(
@@ -7,6 +7,7 @@ from pathlib import Path
 from typing import Generic, TypeVar
 
 from filelock import FileLock
+from caching.stats import record_cache_access
 from utils import get_cache_dir
 
 from langchain_core.language_models import BaseChatModel
@@ -188,9 +189,11 @@ class BaseCache(Generic[K, V]):
             value_json = self._lookup(key_signature)
             if value_json is None:
                 logger.debug("Cache miss: %s key=%s", self.file_path.name, key_signature)
+                record_cache_access(self.file_path.stem, hit=False)
                 return None
             value = self._value_type.model_validate_json(value_json)
             logger.debug("Cache load success: %s key=%s", self.file_path.name, key_signature)
+            record_cache_access(self.file_path.stem, hit=True)
             return value
         except Exception as e:
             logger.warning("Cache load failed: %s", e)
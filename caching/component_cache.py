@@ -0,0 +1,115 @@
+"""Cross-run cache of each component's generated docs, keyed by its subgraph rather than its prompts.
+
+A component's prompts embed upstream text (the parent's description, sibling names) that moves on
+every regeneration, so the response cache alone would re-describe every component after any change.
+"""
+
+import hashlib
+import json
+import logging
+from collections.abc import Mapping
+from pathlib import Path
+
+from pydantic import BaseModel
+
+from agents.agent_responses import AnalysisInsights, ClusterAnalysis, ComponentApiSurfaces, ComponentRelations
+from caching.cache import CACHE_VERSION, ModelSettings
+from caching.response_cache import refresh_requested
+from caching.stats import record_cache_access
+from repo_utils.path_utils import normalize_repo_path
+from static_analyzer.graph import CallGraph
+from utils import get_cache_dir
+
+logger = logging.getLogger(__name__)
+
+COMPONENT_CACHE_DIRNAME = "components"
+
+
+def hash_subgraph(subgraph_cfgs: Mapping[str, CallGraph], repo_dir: Path) -> str:
+    """SHA-256 of the symbols, signatures and edges of a component's subgraph, in name order."""
+    lines: list[str] = []
+    for language in sorted(subgraph_cfgs):
+        cfg = subgraph_cfgs[language]
+        lines.append(f"language {language}")
+        for name in sorted(cfg.nodes):
+            node = cfg.nodes[name]
+            signature = node.signature.render("") if node.signature is not None else ""
+            path = normalize_repo_path(node.file_path, repo_dir)
+            lines.append(f"node {name} {int(node.type)} {path} {signature}")
+        lines.extend(sorted(f"call {edge.get_source()} {edge.get_destination()}" for edge in cfg.edges))
+        lines.extend(sorted(f"{kind} {src} {dst}" for src, dst, kind in cfg.reference_edges))
+    return hashlib.sha256("\n".join(lines).encode("utf-8")).hexdigest()
+
+
+def hash_cluster_groups(cluster_analysis: ClusterAnalysis) -> str:
+    """SHA-256 of each group's name and cluster ids, in name order."""
+    lines = sorted(
+        f"group {group.name} {' '.join(sorted(str(cid) for cid in group.cluster_ids))}"
+        for group in cluster_analysis.cluster_components
+    )
+    return hashlib.sha256("\n".join(lines).encode("utf-8")).hexdigest()
+
+
+class ComponentDocs(BaseModel):
+    """The LLM answers behind one component's sub-analysis."""
+
+    final_analysis: AnalysisInsights
+    api_surfaces: ComponentApiSurfaces
+    relations: ComponentRelations
+
+
+class ComponentDocsCache:
+    """One JSON file per component subgraph under ``components/`` in the cache dir."""
+
+    def __init__(self, repo_dir: Path):
+        self.directory = get_cache_dir(repo_dir) / COMPONENT_CACHE_DIRNAME
+        self._repo_dir = repo_dir
+
+    def build_key(
+        self,
+        subgraph_cfgs: Mapping[str, CallGraph],
+        cluster_analysis: ClusterAnalysis,
+        model_settings: ModelSettings,
+        prompt: str,
+    ) -> str:
+        """Entry name for a subgraph grouped and described by a model; a new *prompt* template invalidates.
+
+        The cached answer names the groups and resolves their cluster ids, so a regrouping invalidates too.
+        """
+        payload = {
+            "cache_version": CACHE_VERSION,
+            "groups": hash_cluster_groups(cluster_analysis),
+            "model_settings": model_settings.signature(),
+            "prompt": hashlib.sha256(prompt.encode("utf-8")).hexdigest(),
+            "subgraph": hash_subgraph(subgraph_cfgs, self._repo_dir),
+        }
+        return hashlib.sha256(json.dumps(payload, sort_keys=True).encode("utf-8")).hexdigest()
+
+    def _path(self, key: str) -> Path:
+        return self.directory / f"{key}.json"
+
+    def load(self, key: str) -> ComponentDocs | None:
+        """The entry for *key*; always None under ``--refresh-llm``."""
+        if refresh_requested():
+            return None
+        try:
+            docs = ComponentDocs.model_validate_json(self._path(key).read_text(encoding="utf-8"))
+        except FileNotFoundError:
+            record_cache_access(COMPONENT_CACHE_DIRNAME, hit=False)
+            return None
+        except (OSError, ValueError) as e:
+            logger.warning("Component docs cache load failed for %s: %s", key, e)
+            record_cache_access(COMPONENT_CACHE_DIRNAME, hit=False)
+            return None
+        record_cache_access(COMPONENT_CACHE_DIRNAME, hit=True)
+        return docs
+
+    def store(self, key: str, docs: ComponentDocs) -> None:
+        try:
+            self.directory.mkdir(parents=True, exist_ok=True)
+            # Why: a run killed mid-write must not leave a truncated entry behind.
+            partial = self._path(key).with_suffix(".tmp")
+            partial.write_text(docs.model_dump_json(), encoding="utf-8")
+            partial.replace(self._path(key))
+        except OSError as e:
+            logger.warning("Component docs cache store failed for %s: %s", key, e)
@@ -0,0 +1,110 @@
+"""What ``codeboarding cache list|clear`` sees on disk.
+
+Three kinds of cache, each safe to delete at the cost of redoing its work:
+
+- ``llm``: SQLite LLM-response caches under the repo's cache dir, and the
+  per-component docs in its ``components/`` directory.
+- ``static``: the static-analysis pickle (+ SHA tag) and the per-language
+  incremental indices — losing them costs a full LSP re-index.
+- ``clone``: remote repositories cloned by ``codeboarding full <url>``.
+"""
+
+import shutil
+import time
+from dataclasses import dataclass
+from enum import StrEnum
+from pathlib import Path
+
+from caching.component_cache import COMPONENT_CACHE_DIRNAME
+from caching.stats import STATS_FILENAME
+from static_analyzer.analysis_cache import STATIC_ANALYSIS_LOCK, STATIC_ANALYSIS_PKL, STATIC_ANALYSIS_SHA
+from utils import get_artifact_dir, get_cache_dir
+
+# SQLite WAL/SHM files and file locks belong to the database next to them.
+_SIDECAR_SUFFIXES = ("-wal", "-shm", "-journal", ".lock")
+
+
+class CacheKind(StrEnum):
+    LLM = "llm"
+    STATIC = "static"
+    CLONE = "clone"
+
+
+@dataclass
+class CacheEntry:
+    kind: CacheKind
+    name: str
+    paths: list[Path]
+    size_bytes: int
+    modified: float
+
+    @property
+    def age_seconds(self) -> float:
+        return max(0.0, time.time() - self.modified)
+
+
+def _tree_size(path: Path) -> tuple[int, float]:
+    if path.is_file():
+        stat = path.stat()
+        return stat.st_size, stat.st_mtime
+    size, modified = 0, path.stat().st_mtime
+    for child in path.rglob("*"):
+        if child.is_file() and not child.is_symlink():
+            stat = child.stat()
+            size += stat.st_size
+            modified = max(modified, stat.st_mtime)
+    return size, modified
+
+
+def _entry(kind: CacheKind, name: str, paths: list[Path]) -> CacheEntry:
+    sizes = [_tree_size(p) for p in paths]
+    return CacheEntry(kind, name, paths, sum(s for s, _ in sizes), max(m for _, m in sizes))
+
+
+def _base_name(filename: str) -> str:
+    for suffix in _SIDECAR_SUFFIXES:
+        if filename.endswith(suffix):
+            return filename[: -len(suffix)]
+    return filename
+
+
+def list_cache_entries(repo_dir: Path, clone_root: Path | None = None) -> list[CacheEntry]:
+    entries: list[CacheEntry] = []
+
+    cache_dir = get_cache_dir(repo_dir)
+    groups: dict[str, list[Path]] = {}
+    if cache_dir.is_dir():
+        for path in sorted(cache_dir.iterdir()):
+            if path.name != STATS_FILENAME:
+                groups.setdefault(_base_name(path.name), []).append(path)
+    for name, paths in groups.items():
+        kind = CacheKind.LLM if name.endswith(".sqlite") or name == COMPONENT_CACHE_DIRNAME else CacheKind.STATIC
+        entries.append(_entry(kind, name, paths))
+
+    artifact_dir = get_artifact_dir(repo_dir)
+    static_paths = [
+        artifact_dir / name
+        for name in (STATIC_ANALYSIS_PKL, STATIC_ANALYSIS_SHA, STATIC_ANALYSIS_LOCK)
+        if (artifact_dir / name).exists()
+    ]
+    if static_paths:
+        entries.append(_entry(CacheKind.STATIC, STATIC_ANALYSIS_PKL, static_paths))
+
+    if clone_root is not None and clone_root.is_dir():
+        for clone in sorted(p for p in clone_root.iterdir() if p.is_dir()):
+            entries.append(_entry(CacheKind.CLONE, clone.name, [clone]))
+
+    return entries
+
+
+def clear_cache_entries(entries: list[CacheEntry]) -> int:
+    """Delete *entries* from disk; returns the bytes freed."""
+    freed = 0
+    for entry in entries:
+        for path in entry.paths:
+            if path.is_dir():
+                shutil.rmtree(path, ignore_errors=True)
+            else:
+                path.unlink(missing_ok=True)
+        freed += entry.size_bytes
+    return freed
@@ -0,0 +1,81 @@
+"""Cross-run cache of LLM responses, keyed by model, system prompt and prompt.
+
+Regenerating the docs after a formatting or template tweak sends exactly the
+prompts of the previous run. Their completions are kept in
+``llm_responses.sqlite`` in the cache dir, in two namespaces:
+
+- ``agent``: the agent model's raw answer to (system prompt, prompt);
+- ``parse``: the structured result the parsing model extracted from such an
+  answer, keyed by the target type instead of a system prompt.
+
+A replayed run therefore makes no request at all. ``--refresh-llm`` skips the
+lookups for the run and overwrites the entries with fresh completions.
+"""
+
+import logging
+from pathlib import Path
+
+from pydantic import BaseModel
+
+from caching.cache import CACHE_VERSION, BaseCache, ModelSettings
+
+logger = logging.getLogger(__name__)
+
+RESPONSE_CACHE_FILENAME = "llm_responses.sqlite"
+AGENT_NAMESPACE = "agent"
+PARSE_NAMESPACE = "parse"
+
+# ``--refresh-llm``: process-wide, like the rest of the LLM settings.
+_refresh = False
+
+
+def configure_response_cache(refresh: bool = False) -> None:
+    global _refresh
+    _refresh = refresh
+
+
+def refresh_requested() -> bool:
+    """Whether ``--refresh-llm`` is set for this run."""
+    return _refresh
+
+
+class ResponseCacheKey(BaseModel):
+    cache_version: int = CACHE_VERSION
+    model_settings: ModelSettings
+    system_prompt: str
+    prompt: str
+
+
+class CachedResponse(BaseModel):
+    text: str
+
+
+class ResponseCache(BaseCache[ResponseCacheKey, CachedResponse]):
+    """SQLite-backed cache of LLM completions by prompt."""
+
+    def __init__(self, repo_dir: Path, namespace: str = AGENT_NAMESPACE):
+        super().__init__(RESPONSE_CACHE_FILENAME, value_type=CachedResponse, repo_dir=repo_dir, namespace=namespace)
+
+    @staticmethod
+    def build_key(model_settings: ModelSettings, system_prompt: str, prompt: str) -> ResponseCacheKey:
+        return ResponseCacheKey(model_settings=model_settings, system_prompt=system_prompt, prompt=prompt)
+
+    def lookup(self, key: ResponseCacheKey) -> str | None:
+        """The cached completion for *key*; always None under ``--refresh-llm``."""
+        if _refresh:
+            return None
+        cached = self.load(key)
+        return cached.text if cached is not None else None
+
+    def save(self, key: ResponseCacheKey, text: str) -> None:
+        # Entries outlive runs (RunContext only prunes the details caches), so no run id is recorded.
+        self.store(key, CachedResponse(text=text), run_id="")
+
+
+def open_response_cache(repo_dir: Path, namespace: str = AGENT_NAMESPACE) -> ResponseCache | None:
+    """The response cache of *repo_dir*, or None when its cache dir cannot be created."""
+    try:
+        return ResponseCache(repo_dir, namespace)
+    except OSError as e:
+        logger.warning(f"LLM response cache disabled for {repo_dir}: {e}")
+        return None
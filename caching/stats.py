@@ -0,0 +1,54 @@
+"""Per-run cache hit/miss counters, persisted for ``codeboarding cache stats``.
+
+Caches call :func:`record_cache_access` on every lookup; the pipeline resets the
+counters when a run starts and writes them next to the caches when it ends, so
+the file always describes the most recent run.
+"""
+
+import json
+import logging
+import threading
+from collections import defaultdict
+from datetime import datetime, timezone
+from pathlib import Path
+
+logger = logging.getLogger(__name__)
+
+STATS_FILENAME = "last_run_stats.json"
+
+_lock = threading.Lock()
+_counters: dict[str, dict[str, int]] = defaultdict(lambda: {"hits": 0, "misses": 0})
+
+
+def record_cache_access(cache: str, hit: bool) -> None:
+    with _lock:
+        _counters[cache]["hits" if hit else "misses"] += 1
+
+
+def reset_cache_stats() -> None:
+    with _lock:
+        _counters.clear()
+
+
+def write_cache_stats(cache_dir: Path) -> Path | None:
+    """Write this run's counters to ``cache_dir/last_run_stats.json``; no-op when nothing was looked up."""
+    with _lock:
+        caches = {name: dict(counts) for name, counts in sorted(_counters.items())}
+    if not caches:
+        return None
+    path = cache_dir / STATS_FILENAME
+    try:
+        cache_dir.mkdir(parents=True, exist_ok=True)
+        payload = {"recorded_at": datetime.now(timezone.utc).isoformat(timespec="seconds"), "caches": caches}
+        path.write_text(json.dumps(payload, indent=2), encoding="utf-8")
+    except OSError as e:
+        logger.warning(f"Could not write cache stats to {path}: {e}")
+        return None
+    return path
+
+
+def load_cache_stats(cache_dir: Path) -> dict | None:
+    try:
+        return json.loads((cache_dir / STATS_FILENAME).read_text(encoding="utf-8"))
+    except (OSError, json.JSONDecodeError):
+        return None
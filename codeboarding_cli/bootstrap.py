@@ -1,18 +1,59 @@
 import argparse
 import logging
+import os
+import sys
 from pathlib import Path
 
-from agents.llm_config import configure_models, validate_api_key_provided
+from agents.llm_config import LLMConfigError, configure_models, validate_agent_model, validate_api_key_provided
+from caching.response_cache import configure_response_cache
 from core import get_registries, load_plugins
+from diagram_analysis.dead_code import DEAD_CODE_FILENAME
+from diagram_analysis.io_utils import load_analysis_metadata
 from diagram_analysis.run_context import RunPaths
 from install import ensure_tools
 from logging_config import setup_logging
+from monitoring.progress import Phase, get_progress
+from codeboarding_workflows.analysis import AnalysisOptions
+from codeboarding_workflows.rendering import (
+    render_c4,
+    render_call_graph_dot,
+    render_chord,
+    render_class_diagram,
+    render_dead_code_report,
+    render_docs,
+    render_import_graph,
+    render_neo4j,
+    render_pdf,
+    render_sequence_diagram,
+)
+from output_generators.c4 import C4_FILENAME, DEFAULT_C4_LEVEL
+from output_generators.class_diagram import CLASS_DIAGRAM_FILENAME
+from output_generators.doc_templates import DocTemplates
+from output_generators.dot import DOT_FILENAME
+from output_generators.neo4j import NEO4J_DIR_NAME
+from output_generators.pdf import EXIT_PDF_TOOLCHAIN_MISSING, PDF_DIR_NAME, PdfToolchainError
+from output_generators.preamble import DocsPreamble
+from output_generators.sequence import DEFAULT_MAX_DEPTH, SEQUENCE_FILENAME
+from output_generators.snapshot import SNAPSHOT_FILENAME, write_snapshot
+from output_generators.snippets import SnippetSource
+from project_config import load_project_config
+from repo_utils.git_ops import get_commit_epoch
+from static_analyzer.analysis_coverage import EXIT_COVERAGE_BELOW_MINIMUM, AnalysisCoverage
+from static_analyzer.framework_edges import Framework
+from static_analyzer.graph import EdgeKind
 from user_config import ensure_config_template, load_user_config
 from utils import CODEBOARDING_DIR_NAME
 from vscode_constants import update_config
 
 logger = logging.getLogger(__name__)
 
+SPHINX_DIR_NAME = "sphinx"
+# Agent model for runs without ``--model``.
+MODEL_ENV = "CODEBOARDING_MODEL"
+
+# ``SOURCE_DATE_EPOCH`` value set by :func:`pin_generated_at` (None: not set by us).
+_pinned_epoch: str | None = None
+
 
 def resolve_local_run_paths(args: argparse.Namespace) -> RunPaths:
     """Derive the paths + project name shared by all local-mode commands.
@@ -26,13 +67,286 @@ def resolve_local_run_paths(args: argparse.Namespace) -> RunPaths:
     return RunPaths(repo_path=repo_path, output_dir=output_dir, project_name=project_name)
 
 
-def bootstrap_environment(output_dir: Path, binary_location: Path | None) -> None:
-    setup_logging(log_dir=output_dir)
+def frameworks_from_args(args: argparse.Namespace) -> tuple[Framework, ...]:
+    names = getattr(args, "framework", None) or []
+    # Why: a project config may set ``framework = "nest"`` rather than a list.
+    if isinstance(names, str):
+        names = [names]
+    return tuple(Framework(name) for name in names)
+
+
+def llm_edge_kinds_from_args(args: argparse.Namespace) -> tuple[str, ...] | None:
+    names = getattr(args, "llm_edge_kinds", None)
+    if not names:
+        return None
+    # Why: a project config may set ``llm_edge_kinds = "call,inherits"`` rather than a list.
+    if isinstance(names, str):
+        names = names.split(",")
+    return tuple(str(EdgeKind(name.strip())) for name in names)
+
+
+def llm_providers_from_args(args: argparse.Namespace) -> list[str] | None:
+    """The ``--llm-fallback`` chain, or ``--provider`` as a chain of one."""
+    provider = getattr(args, "provider", None)
+    return getattr(args, "llm_fallback", None) or ([provider] if provider else None)
+
+
+def docs_preamble_from_args(args: argparse.Namespace) -> DocsPreamble:
+    """``--title`` and the ``--intro`` file's text; an unreadable intro is logged and left out."""
+    title = getattr(args, "title", None)
+    try:
+        return DocsPreamble.load(title, getattr(args, "intro", None))
+    except OSError as e:
+        logger.warning(f"Could not read --intro file, generating docs without it: {e}")
+        return DocsPreamble.load(title, None)
+
+
+def doc_templates_from_args(args: argparse.Namespace) -> DocTemplates | None:
+    """``--doc-template`` files, compiled while parsing; None when none were given."""
+    templates = getattr(args, "doc_template", None)
+    return DocTemplates.of(templates) if templates else None
+
+
+def snippets_from_args(args: argparse.Namespace, repo_path: Path) -> SnippetSource | None:
+    """``--snippets``: excerpts read from *repo_path* under its ``[snippets]`` policy; None without the flag."""
+    return SnippetSource.for_repo(repo_path) if getattr(args, "snippets", False) else None
+
+
+def flag_settings_from_args(args: argparse.Namespace) -> dict[str, bool]:
+    """``--flag NAME=on|off`` settings; the last setting of a flag wins."""
+    return dict(getattr(args, "flag", None) or [])
+
+
+def analysis_options_from_args(args: argparse.Namespace) -> AnalysisOptions:
+    """The analysis flags of *args*; flags a command does not define keep their defaults."""
+    defaults = AnalysisOptions()
+    return AnalysisOptions(
+        frameworks=frameworks_from_args(args),
+        llm_edge_kinds=llm_edge_kinds_from_args(args),
+        main_package=getattr(args, "main_package", None),
+        resolve_interface_dispatch=getattr(args, "resolve_interface_dispatch", False),
+        lsp_concurrency=getattr(args, "concurrency", None),
+        lsp_timeout=getattr(args, "lsp_timeout", None),
+        include=tuple(getattr(args, "include", None) or ()),
+        exclude=tuple(getattr(args, "exclude", None) or ()),
+        dump_lsp_dir=getattr(args, "dump_lsp", None),
+        prompt_dir=getattr(args, "prompt_dir", None),
+        hide_deprecated=getattr(args, "hide_deprecated", False),
+        use_codeowners=getattr(args, "use_codeowners", False),
+        select=getattr(args, "select", None),
+        flags=flag_settings_from_args(args),
+        deterministic=getattr(args, "deterministic", False),
+        test_coverage_graph=getattr(args, "test_coverage_graph", False),
+        test_map=getattr(args, "test_map", False),
+        show_external=getattr(args, "show_external", False),
+        api_only=getattr(args, "api_only", False),
+        max_llm_calls=getattr(args, "max_llm_calls", None),
+        max_cost=getattr(args, "max_cost", None),
+        grouping=getattr(args, "grouping", defaults.grouping),
+        subtree=getattr(args, "subtree", None),
+    )
+
+
+def write_requested_outputs(args: argparse.Namespace, analysis_path: Path, project_name: str, repo_path: Path) -> None:
+    """Honor ``--snapshot``, ``--format``, ``--diagram-style``, ``--report`` and ``--sequence-from``.
+
+    Every output is written next to ``analysis.json``; ``--snippets`` excerpts are read from *repo_path*.
+    """
+    with get_progress().phase(Phase.RENDERING):
+        _write_outputs(args, analysis_path, project_name, repo_path)
+
+
+def _write_outputs(args: argparse.Namespace, analysis_path: Path, project_name: str, repo_path: Path) -> None:
+    if getattr(args, "snapshot", False):
+        snapshot_path = write_snapshot(analysis_path, analysis_path.parent / SNAPSHOT_FILENAME)
+        logger.info(f"Architecture snapshot written to {snapshot_path}")
+    formats = getattr(args, "format", None) or []
+    if "chord" in formats:
+        render_chord(analysis_path, repo_name=project_name, output_dir=analysis_path.parent)
+    if "rst" in formats:
+        # A self-contained tree a Sphinx project can list in its toctree as ``<path>/sphinx/index``.
+        rst_dir = analysis_path.parent / SPHINX_DIR_NAME
+        rst_dir.mkdir(exist_ok=True)
+        render_docs(
+            analysis_path,
+            repo_name=project_name,
+            repo_ref="",
+            temp_dir=rst_dir,
+            format=".rst",
+            root_name="index",
+            preamble=docs_preamble_from_args(args),
+            doc_templates=doc_templates_from_args(args),
+        )
+        logger.info(f"Sphinx docs written to {rst_dir}")
+    if "pdf" in formats:
+        pdf_dir = analysis_path.parent / PDF_DIR_NAME
+        try:
+            render_pdf(
+                analysis_path,
+                repo_name=project_name,
+                output_dir=pdf_dir,
+                preamble=docs_preamble_from_args(args),
+                doc_templates=doc_templates_from_args(args),
+                snippets=snippets_from_args(args, repo_path),
+            )
+        except PdfToolchainError as e:
+            logger.error(f"{e}. The Markdown docs it would contain are in {pdf_dir}")
+            raise SystemExit(EXIT_PDF_TOOLCHAIN_MISSING) from e
+    if "neo4j" in formats:
+        render_neo4j(analysis_path, output_dir=analysis_path.parent / NEO4J_DIR_NAME)
+    if "c4" in formats:
+        render_c4(
+            analysis_path,
+            repo_name=project_name,
+            output_path=analysis_path.parent / C4_FILENAME,
+            level=getattr(args, "c4_level", None) or DEFAULT_C4_LEVEL,
+        )
+    if "dot" in formats:
+        render_call_graph_dot(
+            analysis_path,
+            repo_name=project_name,
+            output_path=analysis_path.parent / DOT_FILENAME,
+            image_format=getattr(args, "render", None),
+            collapse_chains=getattr(args, "collapse_chains", False),
+        )
+    reports = getattr(args, "report", None) or []
+    if "dead-code" in reports:
+        render_dead_code_report(analysis_path, output_path=analysis_path.parent / DEAD_CODE_FILENAME)
+    if "imports" in reports:
+        render_import_graph(analysis_path, repo_name=project_name, output_dir=analysis_path.parent)
+    if getattr(args, "diagram_style", None) == "class":
+        render_class_diagram(
+            analysis_path, repo_name=project_name, output_path=analysis_path.parent / CLASS_DIAGRAM_FILENAME
+        )
+    if getattr(args, "sequence_from", None):
+        render_sequence_diagram(
+            analysis_path,
+            entry=args.sequence_from,
+            output_path=analysis_path.parent / SEQUENCE_FILENAME,
+            max_depth=getattr(args, "max_depth", None) or DEFAULT_MAX_DEPTH,
+        )
+
+
+def enforce_min_coverage(args: argparse.Namespace, analysis_path: Path) -> None:
+    """Report the run's analysis coverage and exit when it is below ``--min-coverage``."""
+    minimum = getattr(args, "min_coverage", None)
+    recorded = (load_analysis_metadata(analysis_path.parent) or {}).get("analysis_coverage")
+    if not recorded:
+        if minimum is not None:
+            logger.warning(f"--min-coverage: no analysis coverage recorded in {analysis_path}; not gating")
+        return
+    coverage = AnalysisCoverage(
+        resolved_call_sites=recorded.get("resolved_call_sites", 0),
+        unresolved_call_sites=recorded.get("unresolved_call_sites", 0),
+        parsed_files=recorded.get("parsed_files", 0),
+        source_files=recorded.get("source_files", 0),
+        timed_out_files=tuple(recorded.get("timed_out_files", ())),
+        degraded_files=tuple(recorded.get("degraded_files", ())),
+    )
+    logger.info(coverage.summary_line())
+    if coverage.timed_out_files:
+        logger.warning(
+            "Partially analyzed, LSP requests timed out (raise --lsp-timeout to retry): %s",
+            ", ".join(coverage.timed_out_files),
+        )
+    if coverage.degraded_files:
+        logger.warning(
+            "Degraded (project did not build), declarations parsed without the language server and call edges "
+            "best-effort: %s",
+            ", ".join(coverage.degraded_files),
+        )
+    if minimum is not None and coverage.percent < minimum:
+        print(f"Analysis coverage {coverage.percent:.1f}% is below --min-coverage {minimum:g}%", file=sys.stderr)
+        raise SystemExit(EXIT_COVERAGE_BELOW_MINIMUM)
+
+
+def configure_llm_providers(
+    repo_path: Path | None = None,
+    llm_fallback: list[str] | None = None,
+    deterministic: bool = False,
+    agent_model: str | None = None,
+    ollama_host: str | None = None,
+    max_retries: int | None = None,
+) -> None:
+    """Select and validate the LLM provider(s) from user config, project ``[llm]`` and *llm_fallback*.
+
+    *agent_model* is ``--model``, else ``CODEBOARDING_MODEL``; it wins over the ``agent_model`` of either config
+    file, with a warning when the provider is not known to serve it.
+    *ollama_host* is ``--ollama-host``; it wins over ``OLLAMA_BASE_URL`` and pins the provider to Ollama.
+    *max_retries* is ``--max-retries``, the attempts per LLM request.
+    The LLM-only slice of :func:`bootstrap_environment`, for commands that never run static analysis.
+    """
+    if ollama_host:
+        if llm_fallback and "ollama" not in llm_fallback:
+            raise LLMConfigError(f"--ollama-host needs the ollama provider, not {', '.join(llm_fallback)}")
+        # Why: pinned, so keys for other providers in the environment do not make the choice ambiguous.
+        llm_fallback = llm_fallback or ["ollama"]
+        # Why: set before the config file is applied, which never overwrites the environment.
+        os.environ["OLLAMA_BASE_URL"] = ollama_host
     ensure_config_template()
     user_cfg = load_user_config()
     user_cfg.apply_to_env()
-    configure_models(agent_model=user_cfg.llm.agent_model, parsing_model=user_cfg.llm.parsing_model)
+    llm_cfg = load_project_config(repo_path).layer_llm(user_cfg.llm)
+    agent_model = agent_model or os.environ.get(MODEL_ENV)
+    configure_models(
+        agent_model=agent_model or llm_cfg.agent_model,
+        parsing_model=llm_cfg.parsing_model,
+        fallback_providers=llm_fallback,
+        deterministic=deterministic,
+        max_attempts=max_retries,
+    )
     validate_api_key_provided()
+    if agent_model:
+        validate_agent_model(agent_model)
+
+
+def pin_generated_at(repo_path: Path) -> None:
+    """Stamp report metadata with *repo_path*'s HEAD commit time via ``SOURCE_DATE_EPOCH``.
+
+    A value the caller exported wins, per the reproducible-builds convention; one
+    set here for an earlier repository of a batch is replaced.
+    """
+    global _pinned_epoch
+    current = os.environ.get("SOURCE_DATE_EPOCH")
+    if current is not None and current != _pinned_epoch:
+        return
+    epoch = get_commit_epoch(repo_path)
+    if epoch is None:
+        logger.warning(f"--deterministic: no commit time for {repo_path}; report timestamps use the clock")
+        return
+    _pinned_epoch = os.environ["SOURCE_DATE_EPOCH"] = str(epoch)
+
+
+def bootstrap_environment(
+    output_dir: Path,
+    binary_location: Path | None,
+    repo_path: Path | None = None,
+    llm_fallback: list[str] | None = None,
+    deterministic: bool = False,
+    agent_model: str | None = None,
+    ollama_host: str | None = None,
+    max_retries: int | None = None,
+    refresh_llm: bool = False,
+    configure_llm: bool = True,
+    log_level: str = "INFO",
+    log_file: Path | None = None,
+) -> None:
+    """Set up logging, providers, plugins and tools; *repo_path* enables the project's ``[llm]`` overrides.
+
+    *llm_fallback* is the ``--llm-fallback`` provider order; it replaces single-provider selection.
+    *agent_model* is ``--model``, the agent model for this run; *ollama_host* is ``--ollama-host``;
+    *max_retries* is ``--max-retries``.
+    *deterministic* is ``--deterministic``: pinned sampling, and report timestamps taken from *repo_path*'s HEAD.
+    *refresh_llm* is ``--refresh-llm``: bypass and overwrite the LLM response cache.
+    *configure_llm* False skips provider selection, for runs that make no LLM request (``--output-format json``).
+    *log_level* and *log_file* are ``--log-level`` and ``--log-file``.
+    """
+    setup_logging(default_level=log_level, log_dir=output_dir, log_file=log_file)
+    configure_response_cache(refresh=refresh_llm)
+    if configure_llm:
+        configure_llm_providers(repo_path, llm_fallback, deterministic, agent_model, ollama_host, max_retries)
+    if deterministic and repo_path is not None:
+        pin_generated_at(repo_path)
     load_plugins(get_registries())
     if binary_location is not None:
         update_config(binary_location)
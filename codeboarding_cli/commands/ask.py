@@ -0,0 +1,56 @@
+import argparse
+import json
+import logging
+import sys
+from pathlib import Path
+
+from agents.component_qa import ComponentNotFoundError, ask_component, build_component_context, unverified_citations
+from agents.llm_config import LLMConfigError, initialize_llms
+from codeboarding_cli.bootstrap import configure_llm_providers
+from diagram_analysis.analysis_json import parse_unified_analysis
+from utils import CODEBOARDING_DIR_NAME
+
+logger = logging.getLogger(__name__)
+
+
+def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse.ArgumentParser]) -> None:
+    parser = subparsers.add_parser(
+        "ask",
+        help="Ask the LLM a question about one component of an existing analysis.json (no re-analysis).",
+    )
+    parser.add_argument("analysis", type=Path, help="Path to analysis.json")
+    parser.add_argument("question", type=str, help="Question about the component")
+    parser.add_argument(
+        "--component",
+        type=str,
+        required=True,
+        help="Component ID (e.g. '1.2') or name (case-insensitive)",
+    )
+
+
+def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
+    try:
+        with open(args.analysis, encoding="utf-8") as f:
+            root_analysis, sub_analyses = parse_unified_analysis(json.load(f))
+    except (OSError, json.JSONDecodeError) as exc:
+        parser.error(f"cannot read {args.analysis}: {exc}")
+
+    try:
+        context = build_component_context(root_analysis, sub_analyses, args.component)
+    except ComponentNotFoundError as exc:
+        parser.error(str(exc))
+
+    # <repo>/.codeboarding/analysis.json -> the project's [llm] overrides apply.
+    analysis_dir = args.analysis.resolve().parent
+    repo_path = analysis_dir.parent if analysis_dir.name == CODEBOARDING_DIR_NAME else None
+    try:
+        configure_llm_providers(repo_path)
+    except LLMConfigError as exc:
+        logger.error("LLM provider not configured: %s", exc)
+        raise SystemExit(1) from exc
+
+    agent_llm, _ = initialize_llms()
+    answer = ask_component(agent_llm, context, args.question)
+    print(answer.answer)
+    if unknown := unverified_citations(answer, context):
+        print(f"\nNote: cited but not in the component's analysis data: {', '.join(unknown)}", file=sys.stderr)
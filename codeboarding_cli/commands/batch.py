@@ -0,0 +1,58 @@
+import argparse
+import logging
+from pathlib import Path
+
+from codeboarding_workflows.batch import BATCH_MANIFEST_FILENAME, RepoStatus, merge_shard_outputs
+from codeboarding_workflows.component_templates import (
+    COMPONENT_TEMPLATES_FILENAME,
+    DEFAULT_DEDUPE_THRESHOLD,
+    write_component_templates,
+)
+
+logger = logging.getLogger(__name__)
+
+
+def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse.ArgumentParser]) -> None:
+    parser = subparsers.add_parser(
+        "batch",
+        help="Utilities for sharded multi-repository batch runs.",
+    )
+    actions = parser.add_subparsers(dest="batch_action", required=True, metavar="ACTION")
+    merge = actions.add_parser(
+        "merge",
+        help=f"Combine the outputs of 'full --shard' runs (each dir must contain {BATCH_MANIFEST_FILENAME}).",
+    )
+    merge.add_argument("shard_dirs", nargs="+", type=Path, help="Workspace directories produced by each shard")
+    merge.add_argument("--output-dir", type=Path, required=True, help="Directory to write the merged outputs to")
+    merge.add_argument(
+        "--dedupe-threshold",
+        type=float,
+        nargs="?",
+        const=DEFAULT_DEDUPE_THRESHOLD,
+        metavar="SIMILARITY",
+        help=(
+            f"Also write {COMPONENT_TEMPLATES_FILENAME} (+ .md), listing components whose symbol sets are at least "
+            f"this similar (Jaccard, 0-1; default {DEFAULT_DEDUPE_THRESHOLD}) once as a templated component"
+        ),
+    )
+
+
+def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
+    if args.dedupe_threshold is not None and not 0 < args.dedupe_threshold <= 1:
+        parser.error("--dedupe-threshold must be in (0, 1]")
+    try:
+        manifest = merge_shard_outputs(args.shard_dirs, args.output_dir)
+    except FileNotFoundError as exc:
+        parser.error(str(exc))
+    failed = sorted(url for url, entry in manifest.repositories.items() if entry.get("status") == RepoStatus.FAILED)
+    for url in failed:
+        logger.warning(f"Repository failed in its shard: {url}")
+    print(f"Merged {len(manifest.repositories)} repositories into {args.output_dir} ({len(failed)} failed)")
+    if args.dedupe_threshold is not None:
+        projects = [
+            entry["project_name"]
+            for entry in manifest.repositories.values()
+            if entry.get("status") == RepoStatus.SUCCESS and entry.get("project_name")
+        ]
+        path = write_component_templates(args.output_dir, projects, args.dedupe_threshold)
+        print(f"Wrote de-duplicated component listing to {path}")
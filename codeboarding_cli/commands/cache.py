@@ -0,0 +1,92 @@
+import argparse
+import logging
+from pathlib import Path
+
+from caching.inventory import CacheEntry, CacheKind, clear_cache_entries, list_cache_entries
+from caching.stats import load_cache_stats
+from codeboarding_workflows.sources.remote import CLONE_ROOT
+from utils import CACHE_ROOT_ENV, get_cache_dir
+
+logger = logging.getLogger(__name__)
+
+
+def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse.ArgumentParser]) -> None:
+    parser = subparsers.add_parser(
+        "cache",
+        help=f"Inspect or clear CodeBoarding's caches (root: ${CACHE_ROOT_ENV}, else <repo>/.codeboarding/cache).",
+    )
+    shared = argparse.ArgumentParser(add_help=False)
+    shared.add_argument(
+        "--local", type=Path, default=Path("."), help="Repository whose caches to use (default: current directory)"
+    )
+    shared.add_argument(
+        "--clone-root",
+        type=Path,
+        default=CLONE_ROOT,
+        help=f"Directory holding cloned remote repositories (default: {CLONE_ROOT})",
+    )
+    actions = parser.add_subparsers(dest="cache_action", required=True, metavar="ACTION")
+    actions.add_parser("list", parents=[shared], help="Show cache entries with their sizes and ages.")
+    clear = actions.add_parser("clear", parents=[shared], help="Delete cache entries (all, or one --type).")
+    clear.add_argument("--type", choices=[kind.value for kind in CacheKind], help="Only clear this kind of cache")
+    actions.add_parser("stats", parents=[shared], help="Show cache hit/miss rates from the last run.")
+
+
+def _human_size(size: int) -> str:
+    value = float(size)
+    for unit in ("B", "KB", "MB", "GB"):
+        if value < 1024 or unit == "GB":
+            return f"{value:.0f} {unit}" if unit == "B" else f"{value:.1f} {unit}"
+        value /= 1024
+    return f"{value:.1f} GB"
+
+
+def _human_age(seconds: float) -> str:
+    for unit, span in (("d", 86400), ("h", 3600), ("m", 60)):
+        if seconds >= span:
+            return f"{int(seconds // span)}{unit}"
+    return f"{int(seconds)}s"
+
+
+def _print_entries(entries: list[CacheEntry]) -> None:
+    if not entries:
+        print("No cache entries found.")
+        return
+    print(f"{'TYPE':<7} {'SIZE':>9} {'AGE':>5}  NAME")
+    for entry in entries:
+        print(f"{entry.kind:<7} {_human_size(entry.size_bytes):>9} {_human_age(entry.age_seconds):>5}  {entry.name}")
+    print(f"Total: {_human_size(sum(e.size_bytes for e in entries))} in {len(entries)} entries")
+
+
+def _print_stats(repo_dir: Path) -> None:
+    stats = load_cache_stats(get_cache_dir(repo_dir))
+    if not stats:
+        print("No cache statistics recorded yet; run an analysis first.")
+        return
+    print(f"Last run: {stats.get('recorded_at', 'unknown')}")
+    print(f"{'CACHE':<28} {'HITS':>6} {'MISSES':>6} {'HIT RATE':>8}")
+    for name, counts in stats.get("caches", {}).items():
+        hits, misses = counts.get("hits", 0), counts.get("misses", 0)
+        rate = f"{hits / (hits + misses):.0%}" if hits + misses else "-"
+        print(f"{name:<28} {hits:>6} {misses:>6} {rate:>8}")
+
+
+def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
+    repo_dir = args.local.resolve()
+    if not repo_dir.is_dir():
+        parser.error(f"--local {args.local} is not a directory")
+
+    if args.cache_action == "stats":
+        _print_stats(repo_dir)
+        return
+
+    entries = list_cache_entries(repo_dir, args.clone_root)
+    if args.cache_action == "list":
+        _print_entries(entries)
+        return
+
+    if args.type:
+        entries = [e for e in entries if e.kind == args.type]
+    freed = clear_cache_entries(entries)
+    logger.info(f"Cleared {len(entries)} cache entries under {repo_dir}")
+    print(f"Cleared {len(entries)} cache entries ({_human_size(freed)})")
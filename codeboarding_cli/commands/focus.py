@@ -0,0 +1,61 @@
+import argparse
+import json
+import sys
+from pathlib import Path
+
+from diagram_analysis.analysis_json import parse_unified_analysis
+from diagram_analysis.focus import FOCUS_FORMATS, FocusError, load_symbol_graph, render_spotlight, spotlight
+
+
+def _hops(value: str) -> int:
+    try:
+        hops = int(value)
+    except ValueError:
+        hops = -1
+    if hops < 1:
+        raise argparse.ArgumentTypeError(f"expected a positive number of hops, got '{value}'")
+    return hops
+
+
+def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse.ArgumentParser]) -> None:
+    parser = subparsers.add_parser(
+        "focus",
+        help="Render one symbol with its callers and callees up to N hops, from an existing analysis.json.",
+    )
+    parser.add_argument("analysis", type=Path, help="Path to analysis.json (its static_analysis.pkl is read too)")
+    parser.add_argument(
+        "--symbol",
+        required=True,
+        help="Qualified name of the symbol (e.g. services.processor.dispatch), or a unique dotted suffix of it",
+    )
+    parser.add_argument(
+        "--hops", type=_hops, default=1, help="Call hops to follow upstream and downstream (default: 1)"
+    )
+    parser.add_argument(
+        "--format",
+        choices=FOCUS_FORMATS,
+        default="mermaid",
+        help="mermaid (flowchart grouped by component) or json (symbols with their distance, and edges)",
+    )
+    parser.add_argument("--output", type=Path, help="Write here instead of stdout")
+
+
+def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
+    try:
+        with open(args.analysis, encoding="utf-8") as f:
+            root_analysis, sub_analyses = parse_unified_analysis(json.load(f))
+    except (OSError, json.JSONDecodeError) as exc:
+        parser.error(f"cannot read {args.analysis}: {exc}")
+
+    graph = load_symbol_graph(args.analysis, root_analysis, sub_analyses)
+    try:
+        spot = spotlight(graph, root_analysis, args.symbol, args.hops)
+    except FocusError as exc:
+        parser.error(str(exc))
+
+    rendered = render_spotlight(spot, args.format)
+    if args.output is None:
+        sys.stdout.write(rendered)
+    else:
+        args.output.write_text(rendered, encoding="utf-8")
+        print(f"Wrote {len(spot.distances)} symbols and {len(spot.edges)} calls around {spot.symbol} to {args.output}")
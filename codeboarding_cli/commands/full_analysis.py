@@ -1,19 +1,53 @@
 import argparse
 import logging
+import subprocess
+import sys
+from dataclasses import replace
 from pathlib import Path
 
 from tqdm import tqdm
 
 from agents.llm_config import LLMConfigError
-from codeboarding_cli.bootstrap import bootstrap_environment, resolve_local_run_paths
+from codeboarding_cli.bootstrap import (
+    analysis_options_from_args,
+    bootstrap_environment,
+    doc_templates_from_args,
+    docs_preamble_from_args,
+    enforce_min_coverage,
+    llm_providers_from_args,
+    pin_generated_at,
+    resolve_local_run_paths,
+    write_requested_outputs,
+)
+from codeboarding_cli.commands.watch import watch_session
 from codeboarding_cli.view_instructions import print_view_instructions
-from codeboarding_workflows.analysis import run_full
+from codeboarding_workflows.analysis import (
+    AnalysisOptions,
+    diff_refs,
+    estimate_full,
+    export_full,
+    run_full,
+    run_static_only,
+)
+from codeboarding_workflows.batch import BATCH_MANIFEST_FILENAME, BatchManifest, RemoteCache, RepoStatus, Shard
 from codeboarding_workflows.orchestration import run_analysis_pipeline
 from codeboarding_workflows.rendering import render_docs
 from codeboarding_workflows.sources import SourceContext, local_source, remote_source
 from diagram_analysis import DEFAULT_DEPTH_LEVEL, RunContext, RunPaths
+from diagram_analysis.architecture_diff import DIFF_MARKDOWN_FILENAME, RefRange, write_diff
+from diagram_analysis.cost_estimate import EXIT_COST_LIMIT_EXCEEDED, CostEstimate
+from diagram_analysis.exceptions import CostLimitExceededError, UnpricedModelError
+from diagram_analysis.grouping import Grouping
+from diagram_analysis.subtree import Subtree, SubtreeError
+from health.fitness import EXIT_FITNESS_FAILED, FITNESS_FILENAME, load_fitness_report
 from monitoring import monitor_execution
 from monitoring.paths import get_monitoring_run_dir
+from output_generators.class_diagram import CLASS_DIAGRAM_FILENAME
+from output_generators.doc_templates import DocTemplates
+from output_generators.dot import DOT_FILENAME
+from output_generators.json_export import STATIC_JSON_FILENAME
+from output_generators.preamble import DocsPreamble
+from output_generators.snippets import SnippetSource
 from repo_utils import get_branch, store_token
 from repo_utils.git_ops import get_current_commit
 from repo_utils.ignore import initialize_codeboardingignore
@@ -40,8 +74,18 @@ def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse
     )
     parser.add_argument(
         "--force",
+        "--no-cache",
+        dest="force",
         action="store_true",
-        help="Force full reanalysis, skipping cached static analysis",
+        help="Force full reanalysis, skipping cached static analysis (including the per-file content hashes)",
+    )
+    parser.add_argument(
+        "--watch",
+        action="store_true",
+        help=(
+            "After the analysis, keep watching --local and update it incrementally after every burst of saves, "
+            "printing which components changed (as 'codeboarding watch' does)"
+        ),
     )
     parser.add_argument(
         "--depth-level",
@@ -53,6 +97,155 @@ def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse
             "not this cap; raise it only if a large repo's diagram is being cut short."
         ),
     )
+    parser.add_argument(
+        "--grouping",
+        type=Grouping,
+        choices=list(Grouping),
+        default=Grouping.SEMANTIC,
+        help=(
+            "How code is grouped into top-level components: semantic (default; call-graph clustering named by the "
+            "LLM), package (one component per package, e.g. per Go package) or directory (one per source "
+            "directory). package and directory give the same components and relations on every run and are not "
+            "expanded into subcomponents"
+        ),
+    )
+    subtree = parser.add_mutually_exclusive_group()
+    subtree.add_argument(
+        "--path",
+        dest="subtree",
+        type=_subtree_path,
+        default=None,
+        metavar="DIR",
+        help=(
+            "Analyze only the code under DIR (relative to the repository root) of a repository too large to "
+            "analyze whole. Calls out of DIR still resolve; the packages they reach appear as external "
+            "dependency components instead of being expanded"
+        ),
+    )
+    subtree.add_argument(
+        "--package",
+        dest="subtree",
+        type=_subtree_package,
+        metavar="PATTERN",
+        help="Like --path, with a Go package pattern: ./services/... for the tree, ./services for that package alone",
+    )
+    parser.add_argument(
+        "--estimate",
+        action="store_true",
+        help=(
+            "Run the static analysis only and print how many components and prompts a full run would produce, "
+            "their input tokens and the estimated price for the configured model; no LLM request is made "
+            "(local only)"
+        ),
+    )
+    parser.add_argument(
+        "--output-format",
+        choices=["docs", "json"],
+        default="docs",
+        help=(
+            "docs (default) runs the full, LLM-backed analysis; json runs the static analysis only and writes "
+            f"{STATIC_JSON_FILENAME}: every entity (kind, package, file, lines, receiver, signature) and edge "
+            "(call, implements, embeds, mutates, ...) under a schema_version. No LLM provider is needed (local only)"
+        ),
+    )
+    parser.add_argument(
+        "--no-llm",
+        action="store_true",
+        help=(
+            f"Skip every LLM prompt: run the static analysis only and write {STATIC_JSON_FILENAME}, "
+            f"{DOT_FILENAME}, the import graph and {CLASS_DIAGRAM_FILENAME}, with no narrative docs. "
+            "No LLM provider or API key is needed; --render also renders the call graph (local only)"
+        ),
+    )
+    parser.add_argument(
+        "--diff",
+        type=_ref_range,
+        metavar="BASE..HEAD",
+        help=(
+            "Analyse two git revisions in temporary worktrees and write the architecture delta to "
+            f"{DIFF_MARKDOWN_FILENAME} (Mermaid: added green, removed red, changed amber) and JSON: added/removed "
+            "entities and edges and changed signatures. BASE...HEAD diffs from their merge base; a lone BASE means "
+            "BASE..HEAD. Static analysis only, no LLM provider needed (local only)"
+        ),
+    )
+    parser.add_argument(
+        "--max-cost",
+        type=_max_cost,
+        metavar="USD",
+        help=(
+            f"Stop with code {EXIT_COST_LIMIT_EXCEEDED} before the first LLM request when the estimated price of "
+            "the run (as --estimate prints it) is above USD"
+        ),
+    )
+    parser.add_argument(
+        "--fitness-gate",
+        action="store_true",
+        help=(
+            f"Exit with code {EXIT_FITNESS_FAILED} when the architecture fitness score in {FITNESS_FILENAME} "
+            "is below the [fitness] threshold in .codeboarding/config.toml (local only)"
+        ),
+    )
+    parser.add_argument(
+        "--shard",
+        type=str,
+        help=(
+            "Process only shard i of n (1-based, e.g. '2/4') of the remote repositories. Progress is recorded in "
+            f"{BATCH_MANIFEST_FILENAME} so a re-run skips finished repos; combine shards with "
+            "'codeboarding batch merge'"
+        ),
+    )
+    parser.add_argument(
+        "--remote-cache",
+        type=str,
+        help="Shared cache root (directory or s3://bucket/prefix) for static-analysis and LLM caches (remote only)",
+    )
+    parser.add_argument(
+        "--snippets",
+        action="store_true",
+        help=(
+            "Show a syntax-highlighted excerpt under each key entity in the generated docs, subject to the "
+            "[snippets] policy in .codeboarding/config.toml (with --local: the --format pdf docs)"
+        ),
+    )
+    parser.add_argument(
+        "--collapsible-md",
+        action="store_true",
+        help=(
+            "Render each component and its source directories as collapsible <details> sections in the "
+            "Markdown docs, for GitHub/GitLab repo browsers (remote only)"
+        ),
+    )
+
+
+def _max_cost(value: str) -> float:
+    try:
+        usd = float(value)
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"expected an amount in USD, got '{value}'") from None
+    if usd < 0:
+        raise argparse.ArgumentTypeError(f"must not be negative, got {value}")
+    return usd
+
+
+def _subtree_path(value: str) -> Subtree:
+    try:
+        return Subtree.from_path(value)
+    except SubtreeError as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+
+
+def _subtree_package(value: str) -> Subtree:
+    try:
+        return Subtree.from_package(value)
+    except SubtreeError as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+
+
+def _ref_range(value: str) -> RefRange:
+    try:
+        return RefRange.parse(value)
+    except ValueError as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
 
 
 def validate_arguments(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
@@ -69,6 +262,48 @@ def validate_arguments(args: argparse.Namespace, parser: argparse.ArgumentParser
             parser.error("--project-name only works with --local")
     elif args.upload:
         parser.error("--upload only works with remote repositories")
+    elif args.shard or args.remote_cache:
+        parser.error("--shard and --remote-cache only work with remote repositories")
+    elif args.collapsible_md:
+        parser.error("--collapsible-md only works with remote repositories")
+    if args.fitness_gate and not has_local_repo:
+        parser.error("--fitness-gate only works with --local")
+    if args.snapshot and not has_local_repo:
+        parser.error("--snapshot only works with --local")
+    if args.format and not has_local_repo:
+        parser.error("--format only works with --local")
+    if args.watch and not has_local_repo:
+        parser.error("--watch only works with --local")
+    if args.estimate and not has_local_repo:
+        parser.error("--estimate only works with --local")
+    if args.estimate and args.watch:
+        parser.error("--estimate and --watch cannot be combined")
+    if args.output_format == "json":
+        if not has_local_repo:
+            parser.error("--output-format json only works with --local")
+        if args.estimate or args.watch:
+            parser.error("--output-format json cannot be combined with --estimate or --watch")
+    if args.no_llm:
+        if not has_local_repo:
+            parser.error("--no-llm only works with --local")
+        if args.estimate or args.watch or args.output_format == "json":
+            parser.error("--no-llm cannot be combined with --estimate, --watch or --output-format json")
+    if args.diff is not None:
+        if not has_local_repo:
+            parser.error("--diff only works with --local")
+        if args.estimate or args.watch or args.no_llm or args.output_format == "json":
+            parser.error("--diff cannot be combined with --estimate, --watch, --no-llm or --output-format json")
+    if (args.no_llm or args.diff is not None) and (args.snapshot or args.format or args.report or args.fitness_gate):
+        # Why: these read analysis.json, which only the LLM analysis writes.
+        parser.error("--snapshot, --format, --report and --fitness-gate need the LLM analysis")
+    if args.intro is not None and not args.intro.is_file():
+        parser.error(f"--intro file not found: {args.intro}")
+
+    if args.shard:
+        try:
+            Shard.parse(args.shard)
+        except ValueError as exc:
+            parser.error(str(exc))
 
 
 def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
@@ -84,7 +319,20 @@ def _run_local(args: argparse.Namespace) -> None:
     run_paths = resolve_local_run_paths(args)
 
     try:
-        bootstrap_environment(run_paths.output_dir, args.binary_location)
+        bootstrap_environment(
+            run_paths.output_dir,
+            args.binary_location,
+            run_paths.repo_path,
+            llm_providers_from_args(args),
+            deterministic=args.deterministic,
+            agent_model=getattr(args, "model", None),
+            ollama_host=getattr(args, "ollama_host", None),
+            max_retries=getattr(args, "max_retries", None),
+            refresh_llm=getattr(args, "refresh_llm", False),
+            log_level=getattr(args, "log_level", None) or "INFO",
+            log_file=getattr(args, "log_file", None),
+            configure_llm=args.output_format != "json" and not args.no_llm and args.diff is None,
+        )
     except LLMConfigError as exc:
         logger.error("LLM provider not configured: %s", exc)
         raise SystemExit(1) from exc
@@ -94,6 +342,20 @@ def _run_local(args: argparse.Namespace) -> None:
     run_paths.output_dir.mkdir(parents=True, exist_ok=True)
     initialize_codeboardingignore(run_paths.output_dir)
 
+    options = analysis_options_from_args(args)
+    if args.estimate:
+        _print_estimate(args, run_paths, options)
+        return
+    if args.output_format == "json":
+        _write_json(args, run_paths, options)
+        return
+    if args.no_llm:
+        _write_static_outputs(args, run_paths, options)
+        return
+    if args.diff is not None:
+        _write_diff(args, run_paths, options)
+        return
+
     def scope(src: SourceContext, run_context: RunContext) -> None:
         run_full(
             RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
@@ -102,9 +364,54 @@ def _run_local(args: argparse.Namespace) -> None:
             monitoring_enabled=should_monitor,
             force_full=args.force,
             source_sha=get_current_commit(src.repo_path),
+            options=options,
         )
 
-    run_analysis_pipeline(
+    try:
+        run_analysis_pipeline(
+            source=local_source(
+                repo_path=run_paths.repo_path,
+                project_name=run_paths.project_name,
+                artifact_dir=run_paths.output_dir,
+            ),
+            scope=scope,
+            # ``--deterministic``: keep the previous run's LLM responses so an unchanged commit replays them.
+            reuse_latest_run_id=args.deterministic,
+        )
+    except CostLimitExceededError as exc:
+        print(exc, file=sys.stderr)
+        raise SystemExit(EXIT_COST_LIMIT_EXCEEDED) from exc
+    except UnpricedModelError as exc:
+        print(exc, file=sys.stderr)
+        raise SystemExit(1) from exc
+    except SubtreeError as exc:
+        logger.error(f"--path/--package: {exc}")
+        raise SystemExit(1) from exc
+    logger.info(f"Documentation generated successfully in {run_paths.output_dir}")
+
+    print_view_instructions(run_paths.output_dir / ANALYSIS_FILENAME)
+    write_requested_outputs(args, run_paths.output_dir / ANALYSIS_FILENAME, run_paths.project_name, run_paths.repo_path)
+    if args.watch:
+        # A watch session ends with Ctrl+C; the CI gates below don't apply to it.
+        watch_session(args, run_paths)
+        return
+    enforce_min_coverage(args, run_paths.output_dir / ANALYSIS_FILENAME)
+    if args.fitness_gate:
+        _enforce_fitness_gate(run_paths.output_dir)
+
+
+def _print_estimate(args: argparse.Namespace, run_paths: RunPaths, options: AnalysisOptions) -> None:
+    def scope(src: SourceContext, run_context: RunContext) -> CostEstimate:
+        return estimate_full(
+            RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
+            run_context,
+            depth_level=args.depth_level,
+            force_full=args.force,
+            source_sha=get_current_commit(src.repo_path),
+            options=options,
+        )
+
+    estimate = run_analysis_pipeline(
         source=local_source(
             repo_path=run_paths.repo_path,
             project_name=run_paths.project_name,
@@ -112,9 +419,99 @@ def _run_local(args: argparse.Namespace) -> None:
         ),
         scope=scope,
     )
-    logger.info(f"Documentation generated successfully in {run_paths.output_dir}")
+    if estimate is not None:
+        print(estimate.summary())
+        if args.max_cost is not None and estimate.cost is not None and estimate.cost > args.max_cost:
+            print(f"Above --max-cost ${args.max_cost:.2f}; a run would stop before its first LLM request")
 
-    print_view_instructions(run_paths.output_dir / ANALYSIS_FILENAME)
+
+def _write_json(args: argparse.Namespace, run_paths: RunPaths, options: AnalysisOptions) -> None:
+    def scope(src: SourceContext, run_context: RunContext) -> Path:
+        return export_full(
+            RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
+            run_context,
+            force_full=args.force,
+            source_sha=get_current_commit(src.repo_path),
+            options=options,
+        )
+
+    json_path = run_analysis_pipeline(
+        source=local_source(
+            repo_path=run_paths.repo_path,
+            project_name=run_paths.project_name,
+            artifact_dir=run_paths.output_dir,
+        ),
+        scope=scope,
+    )
+    if json_path is not None:
+        print(f"Static analysis written to {json_path}")
+
+
+def _write_static_outputs(args: argparse.Namespace, run_paths: RunPaths, options: AnalysisOptions) -> None:
+    def scope(src: SourceContext, run_context: RunContext) -> list[Path]:
+        return run_static_only(
+            RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
+            run_context,
+            force_full=args.force,
+            source_sha=get_current_commit(src.repo_path),
+            options=options,
+            image_format=args.render,
+            collapse_chains=args.collapse_chains,
+        )
+
+    paths = run_analysis_pipeline(
+        source=local_source(
+            repo_path=run_paths.repo_path,
+            project_name=run_paths.project_name,
+            artifact_dir=run_paths.output_dir,
+        ),
+        scope=scope,
+    )
+    for path in paths or []:
+        print(f"Written {path}")
+
+
+def _write_diff(args: argparse.Namespace, run_paths: RunPaths, options: AnalysisOptions) -> None:
+    def scope(src: SourceContext, run_context: RunContext) -> list[Path]:
+        try:
+            diff = diff_refs(
+                RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
+                run_context,
+                args.diff,
+                options=options,
+            )
+        except subprocess.CalledProcessError as exc:
+            logger.error(f"--diff {args.diff}: git failed: {(exc.stderr or '').strip() or exc}")
+            raise SystemExit(1) from exc
+        print(diff.summary())
+        return write_diff(diff, src.artifact_dir)
+
+    paths = run_analysis_pipeline(
+        source=local_source(
+            repo_path=run_paths.repo_path,
+            project_name=run_paths.project_name,
+            artifact_dir=run_paths.output_dir,
+        ),
+        scope=scope,
+    )
+    for path in paths or []:
+        print(f"Written {path}")
+
+
+def _enforce_fitness_gate(output_dir: Path) -> None:
+    report = load_fitness_report(output_dir)
+    if report is None:
+        logger.warning(f"--fitness-gate: no {FITNESS_FILENAME} in {output_dir}; not gating")
+        return
+    if report.passed:
+        print(f"Architecture fitness {report.score:.3f} >= {report.threshold} (passed)")
+        return
+    print(
+        f"Architecture fitness {report.score:.3f} is below the threshold {report.threshold}; "
+        f"see {output_dir / FITNESS_FILENAME}",
+        file=sys.stderr,
+    )
+    raise SystemExit(EXIT_FITNESS_FAILED)
 
 
 def _run_remote(args: argparse.Namespace) -> None:
@@ -122,7 +519,18 @@ def _run_remote(args: argparse.Namespace) -> None:
     output_dir.mkdir(parents=True, exist_ok=True)
 
     try:
-        bootstrap_environment(output_dir, args.binary_location)
+        bootstrap_environment(
+            output_dir,
+            args.binary_location,
+            llm_fallback=llm_providers_from_args(args),
+            deterministic=args.deterministic,
+            agent_model=getattr(args, "model", None),
+            ollama_host=getattr(args, "ollama_host", None),
+            max_retries=getattr(args, "max_retries", None),
+            refresh_llm=getattr(args, "refresh_llm", False),
+            log_level=getattr(args, "log_level", None) or "INFO",
+            log_file=getattr(args, "log_file", None),
+        )
     except LLMConfigError as exc:
         logger.error("LLM provider not configured: %s", exc)
         raise SystemExit(1) from exc
@@ -141,19 +549,43 @@ def _run_remote(args: argparse.Namespace) -> None:
             logger.warning(f"Could not store GitHub token: {exc}")
 
     workspace_root = Path.cwd()
-
-    for repo_url in tqdm(args.repositories, desc="Generating docs for repos"):
+    repositories = args.repositories
+    manifest = None
+    if args.shard:
+        shard = Shard.parse(args.shard)
+        repositories = shard.select(repositories)
+        manifest = BatchManifest(workspace_root / BATCH_MANIFEST_FILENAME, shard)
+        logger.info(f"Shard {shard}: {len(repositories)} of {len(args.repositories)} repositories")
+    remote_cache = RemoteCache(args.remote_cache) if args.remote_cache else None
+    options = analysis_options_from_args(args)
+    preamble = docs_preamble_from_args(args)
+    doc_templates = doc_templates_from_args(args)
+
+    for repo_url in tqdm(repositories, desc="Generating docs for repos"):
+        if manifest is not None and manifest.is_done(repo_url):
+            logger.info(f"Skipping {repo_url}: already completed in {manifest.path}")
+            continue
         try:
-            _process_one_remote(
+            project_name = _process_one_remote(
                 repo_url=repo_url,
                 workspace_root=workspace_root,
                 depth_level=args.depth_level,
                 upload=args.upload,
                 should_monitor=should_monitor,
+                remote_cache=remote_cache,
+                options=options,
+                snippets=args.snippets,
+                collapsible_md=args.collapsible_md,
+                preamble=preamble,
+                doc_templates=doc_templates,
             )
         except Exception as exc:
             logger.error(f"Failed to process repository {repo_url}: {exc}")
+            if manifest is not None:
+                manifest.record(repo_url, RepoStatus.FAILED, error=str(exc))
             continue
+        if manifest is not None:
+            manifest.record(repo_url, RepoStatus.SUCCESS, project_name=project_name)
 
     logger.info("All repositories processed successfully!")
 
@@ -164,8 +596,16 @@ def _process_one_remote(
     depth_level: int,
     upload: bool,
     should_monitor: bool,
-) -> None:
-    def scope(src: SourceContext, run_context: RunContext) -> None:
+    remote_cache: RemoteCache | None = None,
+    options: AnalysisOptions = AnalysisOptions(),
+    snippets: bool = False,
+    collapsible_md: bool = False,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
+) -> str | None:
+    """Analyze one remote repo; returns its project name, or None when upstream materials already exist."""
+
+    def analyze(src: SourceContext, run_context: RunContext) -> None:
         repo_output_dir = workspace_root / src.project_name / CODEBOARDING_DIR_NAME
         repo_output_dir.mkdir(parents=True, exist_ok=True)
         initialize_codeboardingignore(repo_output_dir)
@@ -177,12 +617,20 @@ def _process_one_remote(
             enabled=should_monitor,
         ) as mon:
             mon.step(f"processing_{src.project_name}")
+            if options.deterministic:
+                pin_generated_at(src.repo_path)
             analysis_path = run_full(
                 RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
                 run_context,
                 depth_level=depth_level,
                 monitoring_enabled=should_monitor,
                 source_sha=get_current_commit(src.repo_path),
+                # One namespace per repository so a batch's dumps don't overwrite each other.
+                options=(
+                    replace(options, dump_lsp_dir=options.dump_lsp_dir / src.project_name)
+                    if options.dump_lsp_dir is not None
+                    else options
+                ),
             )
             render_docs(
                 analysis_path=analysis_path,
@@ -192,6 +640,10 @@ def _process_one_remote(
                 format=".md",
                 root_name="on_boarding",
                 demo_mode=True,
+                snippets=SnippetSource.for_repo(src.repo_path) if snippets else None,
+                collapsible_md=collapsible_md,
+                preamble=preamble,
+                doc_templates=doc_templates,
             )
 
             artifacts = [*src.artifact_dir.glob("*.md"), *src.artifact_dir.glob("*.json")]
@@ -200,7 +652,17 @@ def _process_one_remote(
             else:
                 logger.warning("No markdown or JSON files found in %s", src.artifact_dir)
 
-    run_analysis_pipeline(
+    def scope(src: SourceContext, run_context: RunContext) -> str:
+        if remote_cache is not None:
+            remote_cache.pull(src.project_name, src.repo_path, src.artifact_dir)
+        try:
+            analyze(src, run_context)
+        finally:
+            if remote_cache is not None:
+                remote_cache.push(src.project_name, src.repo_path, src.artifact_dir)
+        return src.project_name
+
+    return run_analysis_pipeline(
         source=remote_source(repo_url, upload=upload),
         scope=scope,
         reuse_latest_run_id=True,
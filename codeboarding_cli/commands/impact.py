@@ -0,0 +1,68 @@
+import argparse
+import json
+import sys
+from pathlib import Path
+
+from diagram_analysis.analysis_json import parse_unified_analysis
+from diagram_analysis.focus import FocusError, load_symbol_graph
+from diagram_analysis.impact import DEFAULT_LIMIT, IMPACT_FORMATS, impact, render_impact
+
+
+def _positive(value: str) -> int:
+    try:
+        number = int(value)
+    except ValueError:
+        number = -1
+    if number < 1:
+        raise argparse.ArgumentTypeError(f"expected a positive number, got '{value}'")
+    return number
+
+
+def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse.ArgumentParser]) -> None:
+    parser = subparsers.add_parser(
+        "impact",
+        help="List everything that transitively calls a symbol, and the components affected, from an analysis.json.",
+    )
+    parser.add_argument("analysis", type=Path, help="Path to analysis.json (its static_analysis.pkl is read too)")
+    parser.add_argument(
+        "--symbol",
+        required=True,
+        help="Qualified name of the symbol (e.g. utils.helpers.add), or a unique dotted suffix of it",
+    )
+    parser.add_argument(
+        "--max-depth", type=_positive, default=None, help="Follow callers at most this many calls away (default: all)"
+    )
+    parser.add_argument(
+        "--limit",
+        type=_positive,
+        default=DEFAULT_LIMIT,
+        help=f"Callers listed in the text report and drawn in the diagram, nearest first (default: {DEFAULT_LIMIT})",
+    )
+    parser.add_argument(
+        "--format",
+        choices=IMPACT_FORMATS,
+        default="text",
+        help="text (summary), json (every caller with its distance and component) or mermaid (caller subgraph)",
+    )
+    parser.add_argument("--output", type=Path, help="Write here instead of stdout")
+
+
+def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
+    try:
+        with open(args.analysis, encoding="utf-8") as f:
+            root_analysis, sub_analyses = parse_unified_analysis(json.load(f))
+    except (OSError, json.JSONDecodeError) as exc:
+        parser.error(f"cannot read {args.analysis}: {exc}")
+
+    graph = load_symbol_graph(args.analysis, root_analysis, sub_analyses)
+    try:
+        result = impact(graph, root_analysis, args.symbol, args.max_depth)
+    except FocusError as exc:
+        parser.error(str(exc))
+
+    rendered = render_impact(result, args.format, args.limit)
+    if args.output is None:
+        sys.stdout.write(rendered)
+    else:
+        args.output.write_text(rendered, encoding="utf-8")
+        print(f"Wrote the impact of {result.symbol} ({len(result.callers)} transitive callers) to {args.output}")
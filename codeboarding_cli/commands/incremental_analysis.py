@@ -2,14 +2,24 @@ import argparse
 import json
 import logging
 import sys
+from pathlib import Path
 from typing import Any
 
 from agents.llm_config import LLMConfigError
-from codeboarding_cli.bootstrap import bootstrap_environment, resolve_local_run_paths
+from codeboarding_cli.bootstrap import (
+    analysis_options_from_args,
+    bootstrap_environment,
+    enforce_min_coverage,
+    llm_providers_from_args,
+    resolve_local_run_paths,
+    write_requested_outputs,
+)
 from codeboarding_cli.view_instructions import print_view_instructions
 from codeboarding_workflows.analysis import BaselineUnavailableError, run_incremental
 from diagram_analysis import RunContext
+from diagram_analysis.run_context import RunPaths
 from diagram_analysis.run_mode import RunMode
+from static_analyzer import StaticAnalyzer
 from utils import monitoring_enabled
 
 logger = logging.getLogger(__name__)
@@ -26,6 +36,8 @@ def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse
 def validate_arguments(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
     if args.local is None:
         parser.error("incremental requires --local")
+    if args.intro is not None and not args.intro.is_file():
+        parser.error(f"--intro file not found: {args.intro}")
 
 
 def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
@@ -35,7 +47,19 @@ def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) ->
     run_paths.output_dir.mkdir(parents=True, exist_ok=True)
 
     try:
-        bootstrap_environment(run_paths.output_dir, args.binary_location)
+        bootstrap_environment(
+            run_paths.output_dir,
+            args.binary_location,
+            run_paths.repo_path,
+            llm_providers_from_args(args),
+            deterministic=args.deterministic,
+            agent_model=getattr(args, "model", None),
+            ollama_host=getattr(args, "ollama_host", None),
+            max_retries=getattr(args, "max_retries", None),
+            refresh_llm=getattr(args, "refresh_llm", False),
+            log_level=getattr(args, "log_level", None) or "INFO",
+            log_file=getattr(args, "log_file", None),
+        )
     except LLMConfigError as exc:
         logger.warning("Incremental bootstrap failed: LLM provider not configured: %s", exc)
         _emit({"mode": RunMode.INCREMENTAL, "error": str(exc), "kind": "api_key_missing"})
@@ -57,11 +81,7 @@ def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) ->
         return
 
     try:
-        analysis_path = run_incremental(
-            run_paths,
-            run_context,
-            monitoring_enabled=args.enable_monitoring or monitoring_enabled(),
-        )
+        analysis_path = run_incremental_from_args(args, run_paths, run_context)
     except BaselineUnavailableError as exc:
         # Expected: no baseline, or diff failed against the requested base ref.
         # The wire contract's ``requiresFullAnalysis: true`` tells the wrapper
@@ -81,10 +101,28 @@ def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) ->
         )
         # Human-facing hint (logs to stderr, so the stdout JSON contract stays clean).
         print_view_instructions(analysis_path)
+        write_requested_outputs(args, analysis_path, run_paths.project_name, run_paths.repo_path)
+        enforce_min_coverage(args, analysis_path)
     finally:
         run_context.finalize()
 
 
+def run_incremental_from_args(
+    args: argparse.Namespace,
+    run_paths: RunPaths,
+    run_context: RunContext,
+    static_analyzer: StaticAnalyzer | None = None,
+) -> Path:
+    """``run_incremental`` with the shared analysis options in *args* (also used by ``codeboarding watch``)."""
+    return run_incremental(
+        run_paths,
+        run_context,
+        monitoring_enabled=args.enable_monitoring or monitoring_enabled(),
+        static_analyzer=static_analyzer,
+        options=analysis_options_from_args(args),
+    )
+
+
 def _emit_error(message: str) -> None:
     _emit(
         {
@@ -2,7 +2,14 @@ import argparse
 import logging
 
 from agents.llm_config import LLMConfigError
-from codeboarding_cli.bootstrap import bootstrap_environment, resolve_local_run_paths
+from codeboarding_cli.bootstrap import (
+    analysis_options_from_args,
+    bootstrap_environment,
+    enforce_min_coverage,
+    llm_providers_from_args,
+    resolve_local_run_paths,
+    write_requested_outputs,
+)
 from codeboarding_cli.view_instructions import print_view_instructions
 from codeboarding_workflows.analysis import run_partial
 from codeboarding_workflows.orchestration import run_analysis_pipeline
@@ -39,7 +46,19 @@ def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) ->
     run_paths = resolve_local_run_paths(args)
 
     try:
-        bootstrap_environment(run_paths.output_dir, args.binary_location)
+        bootstrap_environment(
+            run_paths.output_dir,
+            args.binary_location,
+            run_paths.repo_path,
+            llm_providers_from_args(args),
+            deterministic=args.deterministic,
+            agent_model=getattr(args, "model", None),
+            ollama_host=getattr(args, "ollama_host", None),
+            max_retries=getattr(args, "max_retries", None),
+            refresh_llm=getattr(args, "refresh_llm", False),
+            log_level=getattr(args, "log_level", None) or "INFO",
+            log_file=getattr(args, "log_file", None),
+        )
     except LLMConfigError as exc:
         logger.error("LLM provider not configured: %s", exc)
         raise SystemExit(1) from exc
@@ -53,6 +72,7 @@ def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) ->
             RunPaths(repo_path=src.repo_path, output_dir=src.artifact_dir, project_name=src.project_name),
             run_context,
             component_id=args.component_id,
+            options=analysis_options_from_args(args),
         )
 
     run_analysis_pipeline(
@@ -67,3 +87,5 @@ def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) ->
     logger.info(f"Component '{args.component_id}' updated in {run_paths.output_dir}")
 
     print_view_instructions(run_paths.output_dir / ANALYSIS_FILENAME)
+    write_requested_outputs(args, run_paths.output_dir / ANALYSIS_FILENAME, run_paths.project_name, run_paths.repo_path)
+    enforce_min_coverage(args, run_paths.output_dir / ANALYSIS_FILENAME)
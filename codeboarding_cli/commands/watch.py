@@ -0,0 +1,189 @@
+import argparse
+import logging
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights
+from agents.llm_config import LLMConfigError
+from codeboarding_cli.bootstrap import (
+    analysis_options_from_args,
+    bootstrap_environment,
+    llm_providers_from_args,
+    resolve_local_run_paths,
+    write_requested_outputs,
+)
+from codeboarding_cli.commands.incremental_analysis import run_incremental_from_args
+from codeboarding_workflows.analysis import BaselineUnavailableError
+from codeboarding_workflows.live_server import DEFAULT_PORT, LiveReloadServer
+from codeboarding_workflows.rendering import render_docs
+from codeboarding_workflows.watch import DEFAULT_DEBOUNCE_SECONDS, RepoWatcher, component_changes
+from diagram_analysis import RunContext
+from diagram_analysis.io_utils import load_analysis_metadata, load_full_analysis
+from diagram_analysis.run_context import RunPaths
+from static_analyzer import StaticAnalyzer
+from utils import ANALYSIS_FILENAME
+
+logger = logging.getLogger(__name__)
+
+LIVE_HTML_DIR_NAME = "live"
+
+
+def _port(value: str) -> int:
+    try:
+        port = int(value)
+    except ValueError:
+        port = -1
+    if not 0 <= port <= 65535:
+        raise argparse.ArgumentTypeError(f"expected a port number (0-65535), got '{value}'")
+    return port
+
+
+def _seconds(value: str) -> float:
+    try:
+        seconds = float(value)
+    except ValueError:
+        seconds = -1.0
+    if seconds < 0:
+        raise argparse.ArgumentTypeError(f"expected a non-negative number of seconds, got '{value}'")
+    return seconds
+
+
+def add_arguments(subparsers: argparse._SubParsersAction, parents: list[argparse.ArgumentParser]) -> None:
+    parser = subparsers.add_parser(
+        "watch",
+        parents=parents,
+        help="Keep an existing analysis up to date: re-analyze incrementally whenever source files change.",
+    )
+    parser.add_argument(
+        "--serve",
+        action="store_true",
+        help="Also serve the HTML docs on http://127.0.0.1:PORT/ and reload open pages after every update",
+    )
+    parser.add_argument(
+        "--port", type=_port, default=DEFAULT_PORT, help=f"Port for --serve (default: {DEFAULT_PORT}; 0 picks one)"
+    )
+    parser.add_argument(
+        "--debounce",
+        type=_seconds,
+        default=DEFAULT_DEBOUNCE_SECONDS,
+        metavar="SECONDS",
+        help=f"Re-analyze once no file has changed for this long (default: {DEFAULT_DEBOUNCE_SECONDS:g})",
+    )
+
+
+def run_from_args(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None:
+    if args.local is None:
+        parser.error("watch requires --local")
+    run_paths = resolve_local_run_paths(args)
+    if load_analysis_metadata(run_paths.output_dir) is None:
+        parser.error(f"no analysis in {run_paths.output_dir}; run 'codeboarding full --local {args.local}' first")
+    try:
+        bootstrap_environment(
+            run_paths.output_dir,
+            args.binary_location,
+            run_paths.repo_path,
+            llm_providers_from_args(args),
+            deterministic=args.deterministic,
+            agent_model=getattr(args, "model", None),
+            ollama_host=getattr(args, "ollama_host", None),
+            max_retries=getattr(args, "max_retries", None),
+            refresh_llm=getattr(args, "refresh_llm", False),
+            log_level=getattr(args, "log_level", None) or "INFO",
+            log_file=getattr(args, "log_file", None),
+        )
+    except LLMConfigError as exc:
+        parser.error(str(exc))
+    watch_session(args, run_paths)
+
+
+def watch_session(args: argparse.Namespace, run_paths: RunPaths) -> None:
+    """Update the analysis on every burst of saves until Ctrl+C; also backs ``full --local ... --watch``."""
+    serve = getattr(args, "serve", False)
+    debounce = getattr(args, "debounce", DEFAULT_DEBOUNCE_SECONDS)
+    html_dir = run_paths.output_dir / LIVE_HTML_DIR_NAME
+    server = LiveReloadServer(html_dir, getattr(args, "port", DEFAULT_PORT)) if serve else None
+    watcher = RepoWatcher(run_paths.repo_path, exclude=(run_paths.output_dir,), debounce=debounce)
+    options = analysis_options_from_args(args)
+    # One analyzer for the whole session: the language servers stay up and only re-read changed files.
+    with StaticAnalyzer(
+        run_paths.repo_path,
+        frameworks=options.frameworks,
+        main_package=options.main_package,
+        resolve_interface_dispatch=options.resolve_interface_dispatch,
+        lsp_concurrency=options.lsp_concurrency,
+        lsp_timeout=options.lsp_timeout,
+        include=options.include,
+        exclude=options.exclude,
+    ) as analyzer:
+        try:
+            # Catch up on edits made since the baseline before waiting for new ones.
+            analysis_path = _update(args, run_paths, analyzer) or run_paths.output_dir / ANALYSIS_FILENAME
+            if server is not None:
+                _render_live_docs(analysis_path, run_paths, html_dir)
+                server.start()
+                logger.info(f"Serving live architecture docs at {server.url}")
+            logger.info(f"Watching {run_paths.repo_path} for changes (Ctrl+C to stop)")
+            while True:
+                changed = watcher.wait_for_changes()
+                listed = ", ".join(sorted(changed)[:5]) + (", ..." if len(changed) > 5 else "")
+                logger.info(f"{len(changed)} file(s) changed ({listed}); updating the analysis")
+                analysis_path = _update(args, run_paths, analyzer)
+                if server is not None and analysis_path is not None:
+                    _render_live_docs(analysis_path, run_paths, html_dir)
+                    server.notify_reload()
+        except KeyboardInterrupt:
+            logger.info("Stopped watching")
+        finally:
+            if server is not None:
+                server.stop()
+
+
+def _analyses(output_dir: Path) -> list[AnalysisInsights]:
+    loaded = load_full_analysis(output_dir)
+    if loaded is None:
+        return []
+    root, sub_analyses = loaded
+    return [root, *sub_analyses.values()]
+
+
+def _update(args: argparse.Namespace, run_paths: RunPaths, analyzer: StaticAnalyzer) -> Path | None:
+    """One incremental update; failures are logged and the session keeps watching.
+
+    A language server that crashed is restarted, before the update and, once,
+    when the update fails because of it.
+    """
+    analyzer.restart_crashed_clients()
+    before = _analyses(run_paths.output_dir)
+    for attempt in (1, 2):
+        run_context = RunContext.resolve(
+            repo_dir=run_paths.repo_path, project_name=run_paths.project_name, reuse_latest_run_id=True
+        )
+        try:
+            analysis_path = run_incremental_from_args(args, run_paths, run_context, static_analyzer=analyzer)
+            break
+        except BaselineUnavailableError as exc:
+            logger.error(f"Incremental update unavailable: {exc}")
+            return None
+        except Exception:
+            if attempt == 1 and analyzer.restart_crashed_clients():
+                logger.warning("The update failed with a crashed language server; retrying with a fresh one")
+                continue
+            logger.exception("Incremental update failed; will retry on the next change")
+            return None
+        finally:
+            run_context.finalize()
+    write_requested_outputs(args, analysis_path, run_paths.project_name, run_paths.repo_path)
+    logger.info(f"Analysis updated: {analysis_path}")
+    logger.info(component_changes(before, _analyses(run_paths.output_dir)).summary())
+    return analysis_path
+
+
+def _render_live_docs(analysis_path: Path, run_paths: RunPaths, html_dir: Path) -> None:
+    html_dir.mkdir(parents=True, exist_ok=True)
+    render_docs(
+        analysis_path,
+        repo_name=run_paths.project_name,
+        repo_ref="",
+        temp_dir=html_dir,
+        format=".html",
+        root_name="index",
+    )
@@ -11,17 +11,104 @@ baseline resolution.
 """
 
 import logging
+import tempfile
+from dataclasses import dataclass, field
 from pathlib import Path
 
+from agents.prompts.prompt_templates import prompt_templates
+from codeboarding_workflows.rendering import render_static_outputs
 from diagram_analysis import DiagramGenerator
+from diagram_analysis.architecture_diff import ArchitectureDiff, RefRange, diff_models
+from diagram_analysis.cost_estimate import CostEstimate
+from diagram_analysis.grouping import Grouping
 from diagram_analysis.io_utils import load_analysis_metadata, load_full_analysis
 from diagram_analysis.run_context import DEFAULT_DEPTH_LEVEL, RunContext, RunPaths
+from diagram_analysis.subtree import Subtree
+from output_generators.json_export import STATIC_JSON_FILENAME, build_json_model, write_json_model
 from repo_utils.fingerprint_diff import BaselineUnavailableError, detect_changes_from_fingerprint
+from repo_utils.git_ops import add_worktree, get_merge_base, remove_worktree, resolve_commit
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.framework_edges import Framework
+from static_analyzer.select_query import SelectQuery
 from telemetry.events import track_analysis
 
 logger = logging.getLogger(__name__)
 
-__all__ = ["BaselineUnavailableError", "run_full", "run_partial", "run_incremental", "run_incremental_workflow"]
+# Static-analysis cache shared by the two revisions of a ``--diff`` run.
+DIFF_CACHE_DIR_NAME = "diff"
+
+__all__ = [
+    "AnalysisOptions",
+    "BaselineUnavailableError",
+    "diff_refs",
+    "estimate_full",
+    "export_full",
+    "run_full",
+    "run_partial",
+    "run_incremental",
+    "run_static_only",
+    "run_incremental_workflow",
+]
+
+
+@dataclass(frozen=True)
+class AnalysisOptions:
+    """The analysis flags of a run, built once from the CLI arguments and handed to every scope."""
+
+    frameworks: tuple[Framework, ...] = ()
+    llm_edge_kinds: tuple[str, ...] | None = None
+    main_package: str | None = None
+    resolve_interface_dispatch: bool = False
+    lsp_concurrency: int | None = None
+    lsp_timeout: int | None = None
+    # ``--include`` / ``--exclude`` globs narrowing the analysed files.
+    include: tuple[str, ...] = ()
+    exclude: tuple[str, ...] = ()
+    dump_lsp_dir: Path | None = None
+    prompt_dir: Path | None = None
+    hide_deprecated: bool = False
+    use_codeowners: bool = False
+    select: SelectQuery | None = None
+    flags: dict[str, bool] = field(default_factory=dict)
+    deterministic: bool = False
+    test_coverage_graph: bool = False
+    test_map: bool = False
+    show_external: bool = False
+    api_only: bool = False
+    max_llm_calls: int | None = None
+    max_cost: float | None = None
+    grouping: Grouping = Grouping.SEMANTIC
+    subtree: Subtree | None = None
+
+    def configure_static(self, generator: DiagramGenerator) -> None:
+        """Set the options that shape the static analysis, for the scopes that make no LLM request."""
+        generator.frameworks = self.frameworks
+        generator.main_package = self.main_package
+        generator.resolve_interface_dispatch = self.resolve_interface_dispatch
+        generator.lsp_concurrency = self.lsp_concurrency
+        generator.lsp_timeout = self.lsp_timeout
+        generator.include = self.include
+        generator.exclude = self.exclude
+        generator.select = self.select
+        generator.flags = dict(self.flags)
+
+    def configure(self, generator: DiagramGenerator) -> None:
+        """Set every option on *generator*."""
+        self.configure_static(generator)
+        generator.llm_edge_kinds = self.llm_edge_kinds
+        generator.dump_lsp_dir = self.dump_lsp_dir
+        generator.prompt_templates = prompt_templates(self.prompt_dir)
+        generator.hide_deprecated = self.hide_deprecated
+        generator.use_codeowners = self.use_codeowners
+        generator.deterministic = self.deterministic
+        generator.test_coverage_graph = self.test_coverage_graph
+        generator.test_map = self.test_map
+        generator.show_external = self.show_external
+        generator.api_only = self.api_only
+        generator.max_llm_calls = self.max_llm_calls
+        generator.max_cost = self.max_cost
+        generator.grouping = self.grouping
+        generator.subtree = self.subtree
 
 
 def build_generator(
@@ -54,12 +141,15 @@ def run_full(
     force_full: bool = False,
     static_analyzer=None,
     source_sha: str | None = None,
+    options: AnalysisOptions = AnalysisOptions(),
 ) -> Path:
     """Full analysis scope — rebuild the whole diagram from scratch.
 
     ``source_sha`` is forwarded to ``StaticAnalyzer.analyze`` so the on-disk
     static-analysis run artifact (sibling of ``analysis.json``) gets a
     matching SHA tag — enabling the next run's SHA-gated cache reuse.
+    With ``options.max_cost`` set, raises ``CostLimitExceededError`` before the
+    first LLM request when the estimated price is above it.
     """
     logger.info(f"Running FULL analysis workflow for repo '{run_paths.project_name}'.")
     generator = build_generator(
@@ -71,13 +161,134 @@ def run_full(
     )
     generator.force_full_analysis = force_full
     generator.source_sha = source_sha
+    options.configure(generator)
     return generator.generate_analysis()
 
 
+def estimate_full(
+    run_paths: RunPaths,
+    run_context: RunContext,
+    depth_level: int = DEFAULT_DEPTH_LEVEL,
+    force_full: bool = False,
+    source_sha: str | None = None,
+    options: AnalysisOptions = AnalysisOptions(),
+) -> CostEstimate:
+    """``--estimate``: run the static analysis of a full run and price its prompts, without any LLM request."""
+    logger.info(f"Estimating a FULL analysis of repo '{run_paths.project_name}'.")
+    generator = build_generator(run_paths, run_context, depth_level=depth_level)
+    generator.force_full_analysis = force_full
+    generator.source_sha = source_sha
+    options.configure_static(generator)
+    generator.grouping = options.grouping
+    generator.max_llm_calls = options.max_llm_calls
+    generator.subtree = options.subtree
+    return generator.estimate_cost()
+
+
+def _run_static_analysis(
+    run_paths: RunPaths,
+    run_context: RunContext,
+    options: AnalysisOptions,
+    force_full: bool = False,
+    source_sha: str | None = None,
+) -> StaticAnalysisResults:
+    """The static analysis a full run would make, narrowed by ``--select`` and ``--flag``; no LLM client is created."""
+    generator = build_generator(run_paths, run_context, depth_level=DEFAULT_DEPTH_LEVEL)
+    generator.force_full_analysis = force_full
+    generator.source_sha = source_sha
+    options.configure_static(generator)
+    return generator.run_static_analysis()
+
+
+def export_full(
+    run_paths: RunPaths,
+    run_context: RunContext,
+    force_full: bool = False,
+    source_sha: str | None = None,
+    options: AnalysisOptions = AnalysisOptions(),
+) -> Path:
+    """``--output-format json``: run the static analysis of a full run and write it as JSON, without any LLM step."""
+    logger.info(f"Exporting the static analysis of repo '{run_paths.project_name}' as JSON.")
+    static_analysis = _run_static_analysis(
+        run_paths, run_context, options, force_full=force_full, source_sha=source_sha
+    )
+    model = build_json_model(static_analysis, run_paths.repo_path)
+    return write_json_model(model, run_paths.output_dir / STATIC_JSON_FILENAME)
+
+
+def run_static_only(
+    run_paths: RunPaths,
+    run_context: RunContext,
+    force_full: bool = False,
+    source_sha: str | None = None,
+    options: AnalysisOptions = AnalysisOptions(),
+    image_format: str | None = None,
+    collapse_chains: bool = False,
+) -> list[Path]:
+    """``--no-llm``: run the static analysis of a full run and write the JSON model and the static diagrams.
+
+    No LLM provider is configured or called; returns the written files.
+    """
+    logger.info(f"Running STATIC-ONLY analysis workflow for repo '{run_paths.project_name}' (no LLM).")
+    static_analysis = _run_static_analysis(
+        run_paths, run_context, options, force_full=force_full, source_sha=source_sha
+    )
+    return render_static_outputs(
+        static_analysis,
+        repo_name=run_paths.project_name,
+        repo_dir=run_paths.repo_path,
+        output_dir=run_paths.output_dir,
+        image_format=image_format,
+        collapse_chains=collapse_chains,
+    )
+
+
+def diff_refs(
+    run_paths: RunPaths,
+    run_context: RunContext,
+    ref_range: RefRange,
+    options: AnalysisOptions = AnalysisOptions(),
+) -> ArchitectureDiff:
+    """``--diff BASE..HEAD``: analyse both revisions in git worktrees and diff their static models.
+
+    Both share one static-analysis cache under ``diff/`` in the output directory,
+    so the head is a warm start from the base that re-analyses only the files
+    changed between them. Raises ``subprocess.CalledProcessError`` for a ref git
+    cannot resolve.
+    """
+    repo_path = run_paths.repo_path
+    head_sha = resolve_commit(repo_path, ref_range.head)
+    base_sha = (
+        get_merge_base(repo_path, ref_range.base, ref_range.head)
+        if ref_range.from_merge_base
+        else resolve_commit(repo_path, ref_range.base)
+    )
+    logger.info(f"Diffing the architecture of {ref_range} ({base_sha[:12]}..{head_sha[:12]}).")
+    cache_dir = run_paths.output_dir / DIFF_CACHE_DIR_NAME
+    cache_dir.mkdir(parents=True, exist_ok=True)
+    models = []
+    with tempfile.TemporaryDirectory(prefix="codeboarding-diff-") as worktrees:
+        for side, sha in (("base", base_sha), ("head", head_sha)):
+            worktree = Path(worktrees) / side
+            add_worktree(repo_path, sha, worktree)
+            try:
+                static_analysis = _run_static_analysis(
+                    RunPaths(repo_path=worktree, output_dir=cache_dir, project_name=run_paths.project_name),
+                    run_context,
+                    options,
+                    source_sha=sha,
+                )
+                models.append(build_json_model(static_analysis, worktree))
+            finally:
+                remove_worktree(repo_path, worktree)
+    return diff_models(models[0], models[1], ref_range.base, ref_range.head)
+
+
 def run_partial(
     run_paths: RunPaths,
     run_context: RunContext,
     component_id: str,
+    options: AnalysisOptions = AnalysisOptions(),
 ) -> None:
     """Partial scope — regenerate a single component within an existing analysis.
 
@@ -99,6 +310,7 @@ def run_partial(
 
     depth_level = int(metadata.get("depth_cap", metadata.get("depth_level", DEFAULT_DEPTH_LEVEL)))
     generator = build_generator(run_paths, run_context, depth_level=depth_level)
+    options.configure(generator)
     generator.pre_analysis()
 
     full_analysis = load_full_analysis(run_paths.output_dir)
@@ -156,6 +368,7 @@ def run_incremental(
     run_context: RunContext,
     monitoring_enabled: bool = False,
     static_analyzer=None,
+    options: AnalysisOptions = AnalysisOptions(),
 ) -> Path:
     """Incremental scope — cluster-driven update of an existing ``analysis.json``.
 
@@ -196,6 +409,7 @@ def run_incremental(
         static_analyzer=static_analyzer,
         changes=changes,
     )
+    options.configure(generator)
     return run_incremental_workflow(generator)
 
 
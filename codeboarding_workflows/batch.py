@@ -0,0 +1,186 @@
+"""Sharded, resumable batch runs over many remote repositories.
+
+A batch is split across CI jobs with ``--shard i/n``; each shard records what
+it finished in a manifest so a re-run skips completed repos, and may share a
+remote cache so static-analysis artifacts and LLM response caches are reused
+across shards and runs. ``codeboarding batch merge`` combines shard outputs.
+"""
+
+import hashlib
+import json
+import logging
+import shutil
+import subprocess
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from enum import StrEnum
+from pathlib import Path
+
+from static_analyzer.analysis_cache import STATIC_ANALYSIS_PKL, STATIC_ANALYSIS_SHA
+from utils import get_cache_dir
+
+logger = logging.getLogger(__name__)
+
+BATCH_MANIFEST_FILENAME = "batch_manifest.json"
+_CACHE_SUBDIR = "cache"
+_ARTIFACT_SUBDIR = "artifacts"
+_ARTIFACT_FILES = (STATIC_ANALYSIS_PKL, STATIC_ANALYSIS_SHA)
+
+
+class RepoStatus(StrEnum):
+    SUCCESS = "success"
+    FAILED = "failed"
+
+
+@dataclass(frozen=True)
+class Shard:
+    """One 1-based slice ``index`` of ``count`` in a sharded batch."""
+
+    index: int
+    count: int
+
+    @classmethod
+    def parse(cls, spec: str) -> "Shard":
+        """Parse ``"i/n"`` (1-based, e.g. ``"2/4"``)."""
+        try:
+            index_str, count_str = spec.split("/")
+            shard = cls(int(index_str), int(count_str))
+        except ValueError as e:
+            raise ValueError(f"Invalid shard {spec!r}; expected 'i/n', e.g. '1/4'") from e
+        if shard.count < 1 or not 1 <= shard.index <= shard.count:
+            raise ValueError(f"Invalid shard {spec!r}; need 1 <= i <= n")
+        return shard
+
+    def __str__(self) -> str:
+        return f"{self.index}/{self.count}"
+
+    def owns(self, repo_url: str) -> bool:
+        # Why: hash-based assignment keeps a repo on the same shard when the list grows or is reordered.
+        digest = hashlib.sha256(repo_url.strip().rstrip("/").encode("utf-8")).digest()
+        return int.from_bytes(digest[:8], "big") % self.count == self.index - 1
+
+    def select(self, repositories: list[str]) -> list[str]:
+        return [repo for repo in repositories if self.owns(repo)]
+
+
+class RemoteCache:
+    """Shared cache root (local/mounted path or ``s3://`` URI) keyed by project name.
+
+    Per project it stores the wipeable ``.codeboarding/cache`` dir (LLM
+    response caches) and the static-analysis run artifacts. ``s3://`` URIs go
+    through the ``aws`` CLI so no SDK dependency is needed.
+    """
+
+    def __init__(self, uri: str):
+        self.uri = uri.rstrip("/")
+        self.is_s3 = self.uri.startswith("s3://")
+        if self.is_s3 and shutil.which("aws") is None:
+            raise RuntimeError("An s3:// remote cache requires the AWS CLI ('aws') on PATH.")
+
+    def pull(self, project_name: str, repo_path: Path, artifact_dir: Path) -> None:
+        """Install the cached files for *project_name* into the local repo/artifact dirs."""
+        self._sync(self._remote(project_name, _CACHE_SUBDIR), str(get_cache_dir(repo_path)))
+        self._sync(self._remote(project_name, _ARTIFACT_SUBDIR), str(artifact_dir), only=_ARTIFACT_FILES)
+
+    def push(self, project_name: str, repo_path: Path, artifact_dir: Path) -> None:
+        """Upload the local cache and artifacts for *project_name*; failures are logged, not raised."""
+        cache_dir = get_cache_dir(repo_path)
+        if cache_dir.exists():
+            self._sync(str(cache_dir), self._remote(project_name, _CACHE_SUBDIR), exclude_suffix=".lock")
+        if artifact_dir.exists():
+            self._sync(str(artifact_dir), self._remote(project_name, _ARTIFACT_SUBDIR), only=_ARTIFACT_FILES)
+
+    def _remote(self, project_name: str, subdir: str) -> str:
+        return f"{self.uri}/{project_name}/{subdir}"
+
+    def _sync(self, src: str, dst: str, only: tuple[str, ...] = (), exclude_suffix: str = "") -> None:
+        try:
+            if self.is_s3:
+                cmd = ["aws", "s3", "sync", src, dst, "--only-show-errors"]
+                if only:
+                    cmd += ["--exclude", "*", *[arg for name in only for arg in ("--include", name)]]
+                if exclude_suffix:
+                    cmd += ["--exclude", f"*{exclude_suffix}"]
+                subprocess.run(cmd, check=True, capture_output=True, text=True)
+                return
+            src_dir, dst_dir = Path(src), Path(dst)
+            if not src_dir.is_dir():
+                return
+            for file in src_dir.iterdir():
+                if not file.is_file() or (only and file.name not in only):
+                    continue
+                if exclude_suffix and file.name.endswith(exclude_suffix):
+                    continue
+                dst_dir.mkdir(parents=True, exist_ok=True)
+                shutil.copy2(file, dst_dir / file.name)
+        except (OSError, subprocess.CalledProcessError) as e:
+            logger.warning(f"Remote cache sync {src} -> {dst} failed: {e}")
+
+
+class BatchManifest:
+    """Per-shard record of processed repos; lets an interrupted shard resume."""
+
+    def __init__(self, path: Path, shard: Shard | None = None):
+        self.path = path
+        self.shard = shard
+        self.repositories: dict[str, dict] = {}
+        if path.exists():
+            try:
+                self.repositories = json.loads(path.read_text(encoding="utf-8")).get("repositories", {})
+            except (OSError, json.JSONDecodeError) as e:
+                logger.warning(f"Ignoring unreadable batch manifest {path}: {e}")
+
+    def is_done(self, repo_url: str) -> bool:
+        return self.repositories.get(repo_url, {}).get("status") == RepoStatus.SUCCESS
+
+    def record(self, repo_url: str, status: RepoStatus, project_name: str | None = None, error: str = "") -> None:
+        entry: dict = {"status": str(status), "updated_at": datetime.now(timezone.utc).isoformat()}
+        if project_name:
+            entry["project_name"] = project_name
+        if error:
+            entry["error"] = error
+        self.repositories[repo_url] = entry
+        self.save()
+
+    def save(self) -> None:
+        payload = {"shard": str(self.shard) if self.shard else None, "repositories": self.repositories}
+        tmp = self.path.with_suffix(".tmp")
+        tmp.write_text(json.dumps(payload, indent=2, sort_keys=True), encoding="utf-8")
+        tmp.replace(self.path)
+
+
+def merge_shard_outputs(shard_dirs: list[Path], output_dir: Path) -> BatchManifest:
+    """Copy every shard's per-project outputs into *output_dir* and write a combined manifest.
+
+    Each shard dir is a workspace root holding ``batch_manifest.json`` plus one
+    ``<project>/.codeboarding`` dir per successful repo. When a repo appears in
+    several shards, the most recently updated entry wins.
+    """
+    output_dir.mkdir(parents=True, exist_ok=True)
+    merged = BatchManifest(output_dir / BATCH_MANIFEST_FILENAME)
+    merged.repositories = {}
+    sources: dict[str, Path] = {}
+    for shard_dir in shard_dirs:
+        manifest_path = shard_dir / BATCH_MANIFEST_FILENAME
+        if not manifest_path.exists():
+            raise FileNotFoundError(f"No {BATCH_MANIFEST_FILENAME} in shard output {shard_dir}")
+        for repo_url, entry in BatchManifest(manifest_path).repositories.items():
+            current = merged.repositories.get(repo_url)
+            if current is None or entry.get("updated_at", "") > current.get("updated_at", ""):
+                merged.repositories[repo_url] = entry
+                sources[repo_url] = shard_dir
+
+    for repo_url, entry in merged.repositories.items():
+        project_name = entry.get("project_name")
+        if entry.get("status") != RepoStatus.SUCCESS or not project_name:
+            continue
+        src = sources[repo_url] / project_name
+        if not src.is_dir():
+            logger.warning(f"Shard {sources[repo_url]} lists {repo_url} as done but has no {project_name}/ output")
+            continue
+        shutil.copytree(src, output_dir / project_name, dirs_exist_ok=True)
+
+    merged.save()
+    done = sum(1 for e in merged.repositories.values() if e.get("status") == RepoStatus.SUCCESS)
+    logger.info(f"Merged {len(shard_dirs)} shard(s): {done}/{len(merged.repositories)} repositories succeeded")
+    return merged
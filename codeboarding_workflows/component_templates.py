@@ -0,0 +1,138 @@
+"""Collapse near-identical components across a batch into templated components.
+
+Monorepos and fleets of microservices copy boilerplate (health checks, config
+loaders, client wrappers) into every service, so an org-wide listing repeats
+the same component dozens of times. Two top-level components are treated as
+copies when the Jaccard similarity of their symbol sets reaches a threshold; a
+symbol is the last two segments of a method's qualified name, lower-cased, so
+``svc_a.health.HealthHandler.check`` and ``svc_b.health.HealthHandler.check``
+match regardless of the package they were pasted into.
+
+Grouping is greedy over components sorted by ``(project, component_id)`` and
+compares against each group's first member, so the result depends only on the
+inputs and the threshold.
+"""
+
+import json
+import logging
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from agents.agent_responses import Component
+from diagram_analysis.analysis_json import parse_unified_analysis
+from utils import ANALYSIS_FILENAME, CODEBOARDING_DIR_NAME
+
+logger = logging.getLogger(__name__)
+
+COMPONENT_TEMPLATES_FILENAME = "component_templates.json"
+COMPONENT_TEMPLATES_MD = "component_templates.md"
+DEFAULT_DEDUPE_THRESHOLD = 0.8
+
+_SEGMENT_SPLIT_RE = re.compile(r"::|[./\\]")
+
+
+@dataclass(frozen=True)
+class ComponentRef:
+    project: str
+    component_id: str
+    name: str
+    symbols: frozenset[str]
+
+
+@dataclass
+class ComponentTemplate:
+    members: list[ComponentRef] = field(default_factory=list)
+
+    @property
+    def name(self) -> str:
+        return self.members[0].name
+
+    @property
+    def count(self) -> int:
+        return len(self.members)
+
+    def to_dict(self) -> dict:
+        shared = frozenset.intersection(*(m.symbols for m in self.members))
+        return {
+            "name": self.name,
+            "count": self.count,
+            "shared_symbols": sorted(shared),
+            "members": [{"project": m.project, "component_id": m.component_id, "name": m.name} for m in self.members],
+        }
+
+
+def component_symbols(component: Component) -> frozenset[str]:
+    symbols = set()
+    for group in component.file_methods:
+        for method in group.methods:
+            segments = [s for s in _SEGMENT_SPLIT_RE.split(method.qualified_name) if s]
+            symbols.add(".".join(segments[-2:]).lower())
+    return frozenset(symbols)
+
+
+def jaccard(a: frozenset[str], b: frozenset[str]) -> float:
+    if not a or not b:
+        return 0.0
+    return len(a & b) / len(a | b)
+
+
+def group_similar_components(refs: list[ComponentRef], threshold: float) -> list[ComponentTemplate]:
+    """Partition *refs* into groups whose members each reach *threshold* against the group's first member."""
+    groups: list[ComponentTemplate] = []
+    for ref in sorted(refs, key=lambda r: (r.project, r.component_id)):
+        for group in groups:
+            if jaccard(group.members[0].symbols, ref.symbols) >= threshold:
+                group.members.append(ref)
+                break
+        else:
+            groups.append(ComponentTemplate(members=[ref]))
+    return groups
+
+
+def load_component_refs(workspace: Path, project_names: list[str]) -> list[ComponentRef]:
+    """Top-level components of each ``<workspace>/<project>/.codeboarding/analysis.json``."""
+    refs: list[ComponentRef] = []
+    for project in sorted(project_names):
+        analysis_path = workspace / project / CODEBOARDING_DIR_NAME / ANALYSIS_FILENAME
+        try:
+            root_analysis, _ = parse_unified_analysis(json.loads(analysis_path.read_text(encoding="utf-8")))
+        except (OSError, json.JSONDecodeError) as e:
+            logger.warning(f"Skipping {project} for component de-duplication: {e}")
+            continue
+        refs.extend(
+            ComponentRef(project, c.component_id, c.name, component_symbols(c)) for c in root_analysis.components
+        )
+    return refs
+
+
+def write_component_templates(workspace: Path, project_names: list[str], threshold: float) -> Path:
+    """Write ``component_templates.json`` and a Markdown listing of the de-duplicated components."""
+    groups = group_similar_components(load_component_refs(workspace, project_names), threshold)
+    templated = [g for g in groups if g.count > 1]
+    payload = {
+        "threshold": threshold,
+        "templates": [g.to_dict() for g in templated],
+        "unique": [g.to_dict()["members"][0] for g in groups if g.count == 1],
+    }
+    json_path = workspace / COMPONENT_TEMPLATES_FILENAME
+    json_path.write_text(json.dumps(payload, indent=2), encoding="utf-8")
+
+    lines = [
+        "# Components across repositories\n",
+        f"Components with symbol-set similarity >= {threshold:g} are shown once, with the number of copies.\n",
+        "## Templated components\n",
+    ]
+    for group in templated:
+        projects = ", ".join(sorted({m.project for m in group.members}))
+        lines.append(f"- **{group.name}** ×{group.count} — {projects}")
+    if not templated:
+        lines.append("_None_")
+    lines.append("\n## Unique components\n")
+    for group in groups:
+        if group.count == 1:
+            lines.append(f"- {group.members[0].project}: {group.name}")
+    (workspace / COMPONENT_TEMPLATES_MD).write_text("\n".join(lines) + "\n", encoding="utf-8")
+
+    logger.info(f"Collapsed {sum(g.count for g in templated)} components into {len(templated)} templates")
+    return json_path
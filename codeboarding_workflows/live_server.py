@@ -0,0 +1,121 @@
+"""Local HTTP server for ``codeboarding watch --serve``: the HTML docs, reloaded on every update.
+
+Serves a directory of generated HTML pages and injects a few lines of
+JavaScript into each page that subscribe to ``/__livereload``, a
+Server-Sent Events stream. :meth:`LiveReloadServer.notify_reload` pushes one
+event to every open page, which then reloads itself. SSE is plain HTTP, so the
+standard library server is enough and no websocket dependency is needed.
+"""
+
+import logging
+import threading
+from functools import partial
+from http import HTTPStatus
+from http.server import SimpleHTTPRequestHandler, ThreadingHTTPServer
+from pathlib import Path
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_PORT = 8765
+LIVERELOAD_PATH = "/__livereload"
+RELOAD_SCRIPT = (
+    "<script>(function(){var s=new EventSource('" + LIVERELOAD_PATH + "');"
+    "s.onmessage=function(){s.close();location.reload();};})();</script>"
+)
+# SSE comment sent while idle, so proxies and browsers keep the stream open.
+_KEEPALIVE_SECONDS = 15.0
+
+
+class _LiveReloadHandler(SimpleHTTPRequestHandler):
+    server: "LiveReloadServer"
+
+    def do_GET(self) -> None:
+        path = self.path.split("?", 1)[0]
+        if path == LIVERELOAD_PATH:
+            self._stream_reloads()
+        elif path == "/" or path.endswith(".html"):
+            self._send_html(path)
+        else:
+            super().do_GET()
+
+    def _send_html(self, path: str) -> None:
+        page = Path(self.translate_path(path))
+        if page.is_dir():
+            page = page / "index.html"
+        if not page.is_file():
+            self.send_error(HTTPStatus.NOT_FOUND, "Page not generated yet")
+            return
+        html = page.read_text(encoding="utf-8")
+        if "</body>" in html:
+            html = html.replace("</body>", RELOAD_SCRIPT + "</body>", 1)
+        else:
+            html += RELOAD_SCRIPT
+        body = html.encode("utf-8")
+        self.send_response(HTTPStatus.OK)
+        self.send_header("Content-Type", "text/html; charset=utf-8")
+        self.send_header("Content-Length", str(len(body)))
+        self.send_header("Cache-Control", "no-store")
+        self.end_headers()
+        self.wfile.write(body)
+
+    def _stream_reloads(self) -> None:
+        self.send_response(HTTPStatus.OK)
+        self.send_header("Content-Type", "text/event-stream")
+        self.send_header("Cache-Control", "no-store")
+        self.end_headers()
+        seen = self.server.version
+        try:
+            while True:
+                version = self.server.wait_for_version(seen, _KEEPALIVE_SECONDS)
+                if self.server.closing:
+                    return
+                self.wfile.write(b"data: reload\n\n" if version != seen else b": keepalive\n\n")
+                self.wfile.flush()
+                seen = version
+        except (BrokenPipeError, ConnectionResetError):
+            return  # The page navigated away or reloaded.
+
+    def log_message(self, format: str, *args: object) -> None:
+        logger.debug(f"live server: {format % args}")
+
+
+class LiveReloadServer(ThreadingHTTPServer):
+    """Serves *directory* on ``127.0.0.1:<port>`` (0 picks a free port) from a background thread."""
+
+    daemon_threads = True
+
+    def __init__(self, directory: Path, port: int = DEFAULT_PORT) -> None:
+        super().__init__(("127.0.0.1", port), partial(_LiveReloadHandler, directory=str(directory)))
+        self.version = 0
+        self.closing = False
+        self._changed = threading.Condition()
+        self._thread: threading.Thread | None = None
+
+    @property
+    def url(self) -> str:
+        return f"http://127.0.0.1:{self.server_address[1]}/"
+
+    def start(self) -> "LiveReloadServer":
+        self._thread = threading.Thread(target=self.serve_forever, name="codeboarding-live-server", daemon=True)
+        self._thread.start()
+        return self
+
+    def wait_for_version(self, seen: int, timeout: float) -> int:
+        """The current version once it differs from *seen* or *timeout* passes."""
+        with self._changed:
+            self._changed.wait_for(lambda: self.version != seen or self.closing, timeout)
+            return self.version
+
+    def notify_reload(self) -> None:
+        """Tell every open page to reload."""
+        with self._changed:
+            self.version += 1
+            self._changed.notify_all()
+
+    def stop(self) -> None:
+        with self._changed:
+            self.closing = True
+            self._changed.notify_all()
+        if self._thread is not None:
+            self.shutdown()
+        self.server_close()
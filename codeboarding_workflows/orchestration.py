@@ -7,6 +7,7 @@ The two consumers (CLI, GitHub Action) both need the same lifecycle:
 3. run a scope callable that receives the source + run context
 4. finalize the run context even on failure
 5. honor "cache hit" short-circuits from the source
+6. record the run's cache hit/miss counts for ``codeboarding cache stats``
 
 This module owns that lifecycle so callers can't drift out of sync.
 """
@@ -15,8 +16,10 @@ from collections.abc import Callable
 from contextlib import AbstractContextManager
 from typing import TypeVar
 
+from caching.stats import reset_cache_stats, write_cache_stats
 from codeboarding_workflows.sources import SourceContext
 from diagram_analysis import RunContext
+from utils import get_cache_dir
 
 T = TypeVar("T")
 Scope = Callable[[SourceContext, RunContext], T]
@@ -34,6 +37,7 @@ def run_analysis_pipeline(
     returns the scope's return value. The RunContext is finalized in a
     ``finally`` block so it's released even if the scope raises.
     """
+    reset_cache_stats()
     with source as src:
         if src is None:
             return None
@@ -46,3 +50,4 @@ def run_analysis_pipeline(
             return scope(src, run_context)
         finally:
             run_context.finalize()
+            write_cache_stats(get_cache_dir(src.repo_path))
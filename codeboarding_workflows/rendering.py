@@ -14,10 +14,26 @@ from typing import Any
 from agents.agent_responses import AnalysisInsights, Relation
 from agents.relation_edges import append_or_merge_relation
 from diagram_analysis.analysis_json import build_id_to_name_map, parse_unified_analysis
+from diagram_analysis.dead_code import write_dead_code_report
+from diagram_analysis.import_graph import write_import_graph
+from monitoring.progress import Phase, get_progress
+from output_generators.c4 import build_c4_model, write_c4_file
+from output_generators.chord import write_chord_files
+from output_generators.class_diagram import CLASS_DIAGRAM_FILENAME, build_class_diagram, write_class_diagram
+from output_generators.doc_templates import DocTemplates
+from output_generators.dot import DOT_FILENAME, generate_dot, render_dot, write_dot_file
 from output_generators.html import generate_html_file
+from output_generators.json_export import STATIC_JSON_FILENAME, build_json_model, write_json_model
 from output_generators.markdown import generate_markdown_file
 from output_generators.mdx import generate_mdx_file
+from output_generators.neo4j import build_neo4j_graph, write_neo4j_files
+from output_generators.pdf import PdfSection, write_pdf
+from output_generators.preamble import DocsPreamble
+from output_generators.sequence import DEFAULT_MAX_DEPTH, SequenceError, write_sequence_diagram
+from output_generators.snippets import SnippetSource
 from output_generators.sphinx import generate_rst_file
+from static_analyzer.analysis_cache import StaticAnalysisCache
+from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.cluster_relations import iter_ancestor_ids
 from utils import sanitize
 
@@ -56,8 +72,8 @@ def project_relations_to_level(
                 dst_id=dst,
                 is_static=rel.is_static,
                 all_edges=rel.all_edges,
+                implements=rel.implements,
             ),
-            key=(src, dst),
         )
     return aggregated
 
@@ -70,6 +86,7 @@ _FORMAT_WRITERS: dict[str, tuple[str, bool]] = {
     ".mdx": ("generate_mdx_file", False),
     ".rst": ("generate_rst_file", False),
 }
+_SNIPPET_FORMATS = {".md", ".html"}
 
 
 def _load_entries(analysis_path: Path) -> list[tuple[str, AnalysisInsights, set[str]]]:
@@ -110,6 +127,10 @@ def render_docs(
     format: str = ".md",
     root_name: str = "overview",
     demo_mode: bool = False,
+    snippets: SnippetSource | None = None,
+    collapsible_md: bool = False,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
 ) -> None:
     """Render an ``analysis.json`` into *format* docs under *temp_dir*.
 
@@ -120,20 +141,271 @@ def render_docs(
       Action, ``"on_boarding"`` in the CLI workflow).
     - ``demo_mode`` is honored only by writers that accept it (currently
       markdown); it is silently ignored by others.
+    - ``snippets`` adds source excerpts under key entities in ``.md`` and
+      ``.html`` output; other formats render without them.
+    - ``collapsible_md`` nests each component and its source directories in
+      ``<details>`` sections; only ``.md`` honors it.
+    - ``preamble`` (``--title``/``--intro``) heads the top-level file only, in every format.
+    - ``doc_templates`` (``--doc-template``): the one for *format*, if any, lays
+      out every component section in every file.
     """
     if format not in _FORMAT_WRITERS:
         raise ValueError(f"Unsupported extension: {format}")
 
     writer_name, accepts_demo = _FORMAT_WRITERS[format]
     writer: Callable[..., Any] = globals()[writer_name]
-    for fname, analysis, expanded in _load_entries(analysis_path):
-        out_name = root_name if fname == "__root__" else fname
-        logger.info("Generating %s for: %s", format, out_name)
-        kwargs: dict[str, Any] = {
-            "repo_ref": repo_ref,
-            "expanded_components": expanded,
-            "temp_dir": temp_dir,
-        }
-        if accepts_demo:
-            kwargs["demo"] = demo_mode
-        writer(out_name, analysis, repo_name, **kwargs)
+    entries = _load_entries(analysis_path)
+    progress = get_progress()
+    with progress.phase(Phase.RENDERING, len(entries), unit="file"):
+        for fname, analysis, expanded in entries:
+            out_name = root_name if fname == "__root__" else fname
+            logger.info("Generating %s for: %s", format, out_name)
+            kwargs: dict[str, Any] = {
+                "repo_ref": repo_ref,
+                "expanded_components": expanded,
+                "temp_dir": temp_dir,
+            }
+            if accepts_demo:
+                kwargs["demo"] = demo_mode
+            if snippets is not None and format in _SNIPPET_FORMATS:
+                kwargs["snippets"] = snippets
+            if collapsible_md and format == ".md":
+                kwargs["collapsible"] = True
+            if preamble and fname == "__root__":
+                kwargs["preamble"] = preamble
+            if doc_templates is not None and (template := doc_templates.for_format(format)) is not None:
+                kwargs["doc_template"] = template
+            writer(out_name, analysis, repo_name, **kwargs)
+            progress.advance(Phase.RENDERING, unit="file")
+
+
+def render_chord(analysis_path: Path, *, repo_name: str, output_dir: Path) -> Path:
+    """Write the top-level dependency wheel (``chord.html`` + ``chord.json``) into *output_dir*."""
+    _, root_analysis, _ = _load_entries(analysis_path)[0]
+    chord_path = write_chord_files(repo_name, root_analysis, output_dir)
+    logger.info("Chord diagram written to %s", chord_path)
+    return chord_path
+
+
+def render_neo4j(analysis_path: Path, *, output_dir: Path) -> Path:
+    """Write the components, symbols and edges as a Neo4j import (CSV and Cypher) into *output_dir*.
+
+    Symbol edges come from the ``static_analysis.pkl`` next to *analysis_path*
+    when it is there, as in ``codeboarding focus``.
+    """
+    with open(analysis_path, "r", encoding="utf-8") as f:
+        root_analysis, sub_analyses = parse_unified_analysis(json.load(f))
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    if static_analysis is None:
+        logger.warning("No static_analysis.pkl next to %s; exporting cross-component calls only", analysis_path)
+    graph = build_neo4j_graph(root_analysis, sub_analyses, static_analysis, repo_dir=artifact_dir.parent)
+    neo4j_dir = write_neo4j_files(graph, output_dir)
+    logger.info(
+        "Neo4j import (%d nodes, %d relationships) written to %s", len(graph.nodes), len(graph.edges), neo4j_dir
+    )
+    return neo4j_dir
+
+
+def render_c4(analysis_path: Path, *, repo_name: str, output_path: Path, level: str) -> Path:
+    """Write the top-level analysis as a C4 Structurizr DSL workspace, down to *level*, to *output_path*.
+
+    Package imports come from the ``static_analysis.pkl`` next to *analysis_path*
+    when it is there; otherwise only the packages crossed by component calls are linked.
+    """
+    _, root_analysis, _ = _load_entries(analysis_path)[0]
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    model = build_c4_model(repo_name, root_analysis, static_analysis, repo_dir=artifact_dir.parent)
+    write_c4_file(model, output_path, level)
+    logger.info("C4 %s model (%d containers) written to %s", level, len(model.containers), output_path)
+    return output_path
+
+
+def render_call_graph_dot(
+    analysis_path: Path,
+    *,
+    repo_name: str,
+    output_path: Path,
+    image_format: str | None = None,
+    collapse_chains: bool = False,
+) -> Path | None:
+    """Write the static call graph as Graphviz DOT to *output_path*, and render it with ``dot`` when asked.
+
+    Needs the ``static_analysis.pkl`` next to *analysis_path*; without it nothing
+    is written and ``None`` is returned.
+    """
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    if static_analysis is None:
+        logger.warning("No static_analysis.pkl next to %s; skipping the DOT call graph", analysis_path)
+        return None
+    dot_source = generate_dot(
+        static_analysis, repo_dir=artifact_dir.parent, name=repo_name, collapse_chains=collapse_chains
+    )
+    write_dot_file(dot_source, output_path)
+    logger.info("DOT call graph written to %s", output_path)
+    if image_format is not None:
+        image_path = render_dot(output_path, image_format)
+        if image_path is not None:
+            logger.info("Call graph rendered to %s", image_path)
+    return output_path
+
+
+def render_dead_code_report(analysis_path: Path, *, output_path: Path) -> Path | None:
+    """Write the never-referenced symbols and never-imported packages to *output_path*.
+
+    Needs the ``static_analysis.pkl`` next to *analysis_path*; without it nothing
+    is written and ``None`` is returned.
+    """
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    if static_analysis is None:
+        logger.warning("No static_analysis.pkl next to %s; skipping the dead-code report", analysis_path)
+        return None
+    _, report = write_dead_code_report(static_analysis, artifact_dir.parent, output_path)
+    logger.info(
+        "Dead-code report (%d never referenced, %d only from tests, %d packages never imported) written to %s",
+        report.count(report.unreferenced),
+        report.count(report.test_only),
+        len(report.unimported_packages),
+        output_path,
+    )
+    return output_path
+
+
+def render_import_graph(analysis_path: Path, *, repo_name: str, output_dir: Path) -> list[Path]:
+    """Write the package import graph into *output_dir* as Mermaid (``imports.md``) and DOT (``imports.dot``).
+
+    Needs the ``static_analysis.pkl`` next to *analysis_path*; without it nothing
+    is written and an empty list is returned.
+    """
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    if static_analysis is None:
+        logger.warning("No static_analysis.pkl next to %s; skipping the import graph", analysis_path)
+        return []
+    paths, graph = write_import_graph(static_analysis, artifact_dir.parent, output_dir, name=repo_name)
+    logger.info(
+        "Import graph (%d packages, %d imports, %d in cycles) written to %s",
+        len(graph.packages),
+        len(graph.imports),
+        len(graph.cycle_edges()),
+        ", ".join(str(path) for path in paths),
+    )
+    return paths
+
+
+def render_class_diagram(analysis_path: Path, *, repo_name: str, output_path: Path) -> Path | None:
+    """Write the Mermaid class diagram of the analysed types to *output_path*.
+
+    Needs the ``static_analysis.pkl`` next to *analysis_path*; without it nothing
+    is written and ``None`` is returned.
+    """
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    if static_analysis is None:
+        logger.warning("No static_analysis.pkl next to %s; skipping the class diagram", analysis_path)
+        return None
+    diagram = build_class_diagram(static_analysis, repo_dir=artifact_dir.parent)
+    write_class_diagram(diagram, output_path, repo_name)
+    logger.info("Class diagram (%d types) written to %s", len(diagram.classes), output_path)
+    return output_path
+
+
+def render_static_outputs(
+    static_analysis: StaticAnalysisResults,
+    *,
+    repo_name: str,
+    repo_dir: Path,
+    output_dir: Path,
+    image_format: str | None = None,
+    collapse_chains: bool = False,
+) -> list[Path]:
+    """``--no-llm``: write the JSON model, call graph, import graph and class diagram of *static_analysis*.
+
+    Everything is built from the static analysis alone, so no ``analysis.json`` is needed.
+    """
+    output_dir.mkdir(parents=True, exist_ok=True)
+    with get_progress().phase(Phase.RENDERING):
+        paths = [write_json_model(build_json_model(static_analysis, repo_dir), output_dir / STATIC_JSON_FILENAME)]
+        dot_source = generate_dot(static_analysis, repo_dir=repo_dir, name=repo_name, collapse_chains=collapse_chains)
+        dot_path = write_dot_file(dot_source, output_dir / DOT_FILENAME)
+        paths.append(dot_path)
+        if image_format is not None and (image_path := render_dot(dot_path, image_format)) is not None:
+            paths.append(image_path)
+        import_paths, _ = write_import_graph(static_analysis, repo_dir, output_dir, name=repo_name)
+        paths.extend(import_paths)
+        diagram = build_class_diagram(static_analysis, repo_dir=repo_dir)
+        paths.append(write_class_diagram(diagram, output_dir / CLASS_DIAGRAM_FILENAME, repo_name))
+    logger.info("Static-analysis outputs written to %s", ", ".join(str(path) for path in paths))
+    return paths
+
+
+def render_sequence_diagram(
+    analysis_path: Path, *, entry: str, output_path: Path, max_depth: int = DEFAULT_MAX_DEPTH
+) -> Path | None:
+    """Write the PlantUML sequence diagram of the calls made from *entry* to *output_path*.
+
+    Needs the ``static_analysis.pkl`` next to *analysis_path* and *entry* in its
+    call graph; otherwise nothing is written and ``None`` is returned.
+    """
+    artifact_dir = analysis_path.resolve().parent
+    static_analysis = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    if static_analysis is None:
+        logger.warning("No static_analysis.pkl next to %s; skipping the sequence diagram", analysis_path)
+        return None
+    try:
+        _, sequence = write_sequence_diagram(static_analysis, entry, output_path, max_depth)
+    except SequenceError as e:
+        logger.error("--sequence-from: %s; no sequence diagram written", e)
+        return None
+    logger.info(
+        "Sequence diagram from %s (%d calls, %d participants) written to %s",
+        sequence.entry,
+        len(sequence.messages),
+        len(sequence.participants),
+        output_path,
+    )
+    return output_path
+
+
+def render_pdf(
+    analysis_path: Path,
+    *,
+    repo_name: str,
+    output_dir: Path,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
+    snippets: SnippetSource | None = None,
+) -> Path:
+    """Write the Markdown docs into *output_dir*, then merge them into one ``architecture.pdf`` there.
+
+    The ``--title`` goes on the cover page rather than atop the overview. The
+    Markdown is written first, so it is there even when ``write_pdf`` raises
+    ``PdfToolchainError``.
+    """
+    output_dir.mkdir(parents=True, exist_ok=True)
+    render_docs(
+        analysis_path,
+        repo_name=repo_name,
+        repo_ref="",
+        temp_dir=output_dir,
+        root_name="overview",
+        snippets=snippets,
+        preamble=DocsPreamble(intro=preamble.intro) if preamble else None,
+        doc_templates=doc_templates,
+    )
+    with open(analysis_path, "r", encoding="utf-8") as f:
+        root_analysis, sub_analyses = parse_unified_analysis(json.load(f))
+    id_to_name = build_id_to_name_map(root_analysis, sub_analyses)
+    sections = [PdfSection(anchor="overview", title="Overview", markdown_path=output_dir / "overview.md")]
+    # Same order and file names as render_docs' sub-analysis docs.
+    for comp_id in sub_analyses:
+        name = id_to_name.get(comp_id, comp_id)
+        stem = sanitize(name)
+        sections.append(PdfSection(anchor=stem, title=name, markdown_path=output_dir / f"{stem}.md"))
+    title = preamble.title if preamble and preamble.title else repo_name
+    pdf_path = write_pdf(title, f"Architecture of {repo_name}", sections, output_dir)
+    logger.info("PDF written to %s", pdf_path)
+    return pdf_path
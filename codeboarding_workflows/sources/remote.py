@@ -5,6 +5,7 @@ from pathlib import Path
 
 import requests
 
+from caching.stats import record_cache_access
 from codeboarding_workflows.sources.local import SourceContext
 from repo_utils import clone_repository, get_repo_name, upload_onboarding_materials
 from utils import copy_files, create_temp_repo_folder, remove_temp_repo_folder
@@ -13,6 +14,8 @@ logger = logging.getLogger(__name__)
 
 _GENERATED_ONBOARDINGS_URL = "https://github.com/CodeBoarding/GeneratedOnBoardings/tree/main"
 _UPLOAD_RESULTS_DIR = "results"
+# Remote repositories are cloned here and reused (pulled) by later runs.
+CLONE_ROOT = Path("repos")
 
 
 def onboarding_materials_exist(project_name: str) -> bool:
@@ -50,9 +53,9 @@ def remote_source(
         yield None
         return
 
-    repo_root = Path("repos")
-    cloned_name = clone_repository(repo_url, repo_root)
-    repo_path = repo_root / cloned_name
+    record_cache_access("clone", hit=(CLONE_ROOT / repo_name).is_dir())
+    cloned_name = clone_repository(repo_url, CLONE_ROOT)
+    repo_path = CLONE_ROOT / cloned_name
     temp_folder = create_temp_repo_folder()
 
     try:
@@ -0,0 +1,151 @@
+"""Filesystem watching for ``codeboarding watch``.
+
+Polls the repository's source files (``.gitignore``/``.codeboardingignore``
+apply, as in the analysis itself) instead of relying on OS notification APIs,
+so it needs no extra dependency and behaves the same on every platform and on
+network drives. Polling a few thousand ``stat`` calls every half second is
+cheap next to an incremental analysis.
+
+Editors often write a file several times per save (temp file, rename, format
+on save), so a change only counts once the tree has stayed quiet for the
+debounce period.
+
+After each update, :func:`component_changes` compares the components before
+and after, so the session can say what the edit did to the architecture.
+"""
+
+import logging
+import os
+import threading
+import time
+from collections.abc import Callable, Iterable
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer.constants import SOURCE_EXTENSION_TO_LANGUAGE
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_POLL_INTERVAL_SECONDS = 0.5
+DEFAULT_DEBOUNCE_SECONDS = 1.0
+
+# (mtime in ns, size) per source file, keyed by repo-relative POSIX path.
+Snapshot = dict[str, tuple[int, int]]
+
+
+def changed_paths(before: Snapshot, after: Snapshot) -> set[str]:
+    """Files added, removed or modified between two snapshots."""
+    return {path for path in before.keys() | after.keys() if before.get(path) != after.get(path)}
+
+
+class RepoWatcher:
+    """Reports batches of changed source files, one batch per burst of saves."""
+
+    def __init__(
+        self,
+        repo_dir: Path,
+        *,
+        exclude: tuple[Path, ...] = (),
+        poll_interval: float = DEFAULT_POLL_INTERVAL_SECONDS,
+        debounce: float = DEFAULT_DEBOUNCE_SECONDS,
+        clock: Callable[[], float] = time.monotonic,
+    ) -> None:
+        self.repo_dir = repo_dir.resolve()
+        self.exclude = tuple(path.resolve() for path in exclude)
+        self.poll_interval = poll_interval
+        self.debounce = debounce
+        self._clock = clock
+        self._ignore = RepoIgnoreManager(self.repo_dir)
+        self._snapshot = self.snapshot()
+
+    def snapshot(self) -> Snapshot:
+        files: Snapshot = {}
+        for dirpath, dirnames, filenames in os.walk(self.repo_dir):
+            directory = Path(dirpath)
+            dirnames[:] = [
+                name
+                for name in dirnames
+                if (directory / name) not in self.exclude and not self._ignore.should_ignore(directory / name)
+            ]
+            for name in filenames:
+                path = directory / name
+                if path.suffix.lower() not in SOURCE_EXTENSION_TO_LANGUAGE or self._ignore.should_ignore(path):
+                    continue
+                try:
+                    stat = path.stat()
+                except OSError:
+                    continue  # Deleted between listing and stat; the next poll sees it gone.
+                files[path.relative_to(self.repo_dir).as_posix()] = (stat.st_mtime_ns, stat.st_size)
+        return files
+
+    def poll(self) -> set[str]:
+        """Files changed since the previous poll (or since the watcher was created)."""
+        current = self.snapshot()
+        changed = changed_paths(self._snapshot, current)
+        self._snapshot = current
+        return changed
+
+    def wait_for_changes(self, stop: threading.Event | None = None) -> set[str]:
+        """Block until files changed and then stayed unchanged for ``debounce`` seconds; return them all.
+
+        Returns an empty set when *stop* is set first.
+        """
+        stop = stop or threading.Event()
+        pending: set[str] = set()
+        last_change = 0.0
+        while not stop.is_set():
+            changed = self.poll()
+            if changed:
+                pending |= changed
+                last_change = self._clock()
+            elif pending and self._clock() - last_change >= self.debounce:
+                return pending
+            stop.wait(self.poll_interval)
+        return set()
+
+
+@dataclass
+class ComponentChanges:
+    """Component names added, removed or changed (description, files or methods) between two analyses."""
+
+    added: list[str] = field(default_factory=list)
+    removed: list[str] = field(default_factory=list)
+    changed: list[str] = field(default_factory=list)
+
+    def __bool__(self) -> bool:
+        return bool(self.added or self.removed or self.changed)
+
+    def summary(self) -> str:
+        if not self:
+            return "No component changed since the last render"
+        parts = [
+            f"{label} {', '.join(names)}"
+            for label, names in (("added", self.added), ("changed", self.changed), ("removed", self.removed))
+            if names
+        ]
+        return "Components changed since the last render: " + "; ".join(parts)
+
+
+def _components(analyses: Iterable[AnalysisInsights]) -> dict[str, Component]:
+    return {c.component_id or c.name: c for analysis in analyses for c in analysis.components}
+
+
+def _signature(component: Component) -> tuple:
+    methods = sorted(
+        (group.file_path, method.qualified_name) for group in component.file_methods for method in group.methods
+    )
+    return component.name, component.description, tuple(methods)
+
+
+def component_changes(before: Iterable[AnalysisInsights], after: Iterable[AnalysisInsights]) -> ComponentChanges:
+    """What changed between the components of *before* and *after* (root and sub-analyses), matched by id."""
+    old, new = _components(before), _components(after)
+    return ComponentChanges(
+        added=sorted(new[key].name for key in new.keys() - old.keys()),
+        removed=sorted(old[key].name for key in old.keys() - new.keys()),
+        changed=sorted(
+            new[key].name for key in new.keys() & old.keys() if _signature(new[key]) != _signature(old[key])
+        ),
+    )
@@ -6,6 +6,8 @@ CODEBOARDING_DIR_NAME = ".codeboarding"
 GITIGNORE_FILENAME = ".gitignore"
 CODEBOARDINGIGNORE_FILENAME = ".codeboardingignore"
 DEFAULT_STATIC_RELATION_LABEL = "calls"
+# Label of relations derived from interface satisfaction rather than calls (drawn dashed).
+IMPLEMENTS_RELATION_LABEL = "implements"
 
 
 class AppConfig:
@@ -1,6 +1,5 @@
 import logging
 import json
-from datetime import datetime, timezone
 from pathlib import Path
 
 from pydantic import BaseModel, Field
@@ -13,9 +12,18 @@ from agents.agent_responses import (
     RelationEdge,
     SourceCodeReference,
 )
-from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from agents.file_index_models import (
+    ExternalPackageCalls,
+    FileEntry,
+    FileMethodGroup,
+    HttpRouteLink,
+    MethodEntry,
+    SpecOperationLink,
+)
 from agents.relation_edges import merge_relations_by_pair
 from repo_utils.path_utils import normalize_repo_path
+from static_analyzer.node import receiver_kind, visibility
+from utils import generated_at
 
 logger = logging.getLogger(__name__)
 
@@ -25,6 +33,9 @@ class RelationEdgeJson(BaseModel):
     target: str = Field(description="Key into methods_index for the target method.")
     call_sites: list[RelationCallSite] = Field(default_factory=list)
     description: str = Field(default="", description="Short explanation of how source reaches or configures target.")
+    flags: dict[str, str] | None = Field(
+        default=None, description="Feature flag states (on/off) every call site of this edge is guarded by."
+    )
 
 
 class RelationJson(Relation):
@@ -41,6 +52,11 @@ class RelationJson(Relation):
         default_factory=list,
         description="All known source-to-target edges for this relation.",
     )
+    # None (omitted from JSON) unless set, so analyses without interface links keep their shape.
+    implements: bool | None = Field(
+        default=None,
+        description="True when the relation is interface satisfaction (dashed in diagrams) rather than calls.",
+    )
 
 
 class ComponentJson(Component):
@@ -54,6 +70,43 @@ class ComponentJson(Component):
         description="Whether the component can be expanded in detail or not.",
         default=False,
     )
+    # None (omitted from JSON) unless set, so analyses without boundaries keep their shape.
+    external: bool | None = Field(
+        default=None,
+        description="True when the component is an external service client (an annotated boundary).",
+    )
+    spec_operations: list[SpecOperationLink] | None = Field(
+        default=None,
+        description="OpenAPI/Swagger operations implemented by the component's generated client methods.",
+    )
+    http_routes: list[HttpRouteLink] | None = Field(
+        default=None,
+        description="HTTP routes served by the component's handler functions (--framework http).",
+    )
+    external_calls: list[ExternalPackageCalls] | None = Field(
+        default=None,
+        description="Packages outside the project the component's functions call, with call counts.",
+    )
+    deprecated: bool | None = Field(
+        default=None,
+        description="True when every method of the component is marked deprecated.",
+    )
+    deprecated_symbols: list[str] | None = Field(
+        default=None,
+        description="Qualified names of the component's methods that are marked deprecated.",
+    )
+    owners: list[str] | None = Field(
+        default=None,
+        description="CODEOWNERS owners of the component's files, most files first.",
+    )
+    tests: list[str] | None = Field(
+        default=None,
+        description="IDs of the test functions that name the component's methods.",
+    )
+    not_described: str | None = Field(
+        default=None,
+        description="Why the component was not expanded into subcomponents, e.g. 'call budget reached'.",
+    )
     file_methods: list["ComponentFileMethodGroupJson"] = Field(
         description="Component method references grouped by file. Each methods entry stores only qualified_name.",
         default_factory=list,
@@ -84,6 +137,23 @@ class FileCoverageSummary(BaseModel):
     )
 
 
+class AnalysisCoverageSummary(BaseModel):
+    coverage: float = Field(description="Percent of the code both parsed and resolved: call-site ratio x file ratio.")
+    resolved_call_sites: int = Field(description="Call sites resolved to a known symbol.")
+    unresolved_call_sites: int = Field(description="References the language servers reported as undefined.")
+    parsed_files: int = Field(description="Source files that produced symbols and no syntax error.")
+    source_files: int = Field(description="Source files handed to a language server.")
+    timed_out_files: list[str] = Field(
+        default_factory=list,
+        description="Files left partially analyzed because their LSP requests timed out (--lsp-timeout).",
+    )
+    degraded_files: list[str] = Field(
+        default_factory=list,
+        description="Files the language server returned nothing for because the project did not build; "
+        "their declarations come from a syntax-only parse and their call edges are best-effort.",
+    )
+
+
 class FileCoverageReport(BaseModel):
     version: int = Field(default=1, description="Schema version of the file coverage report.")
     generated_at: str = Field(description="ISO timestamp of when the report was generated.")
@@ -111,6 +181,13 @@ class AnalysisMetadata(BaseModel):
         ),
         description="Lightweight file coverage counts.",
     )
+    llm_providers: dict[str, str] | None = Field(
+        default=None,
+        description="Component ID -> 'provider/model' that generated it; only recorded with --llm-fallback.",
+    )
+    analysis_coverage: AnalysisCoverageSummary | None = Field(
+        default=None, description="How complete the static analysis behind this run was."
+    )
 
 
 class MethodIndexEntry(BaseModel):
@@ -123,6 +200,22 @@ class MethodIndexEntry(BaseModel):
         default="",
         description="Truncated SHA-256 of the method's source lines; '' when unknown.",
     )
+    kind: str | None = Field(
+        default=None,
+        description="Canonical kind shared across languages (type, function, method, ...), see [symbol_kinds].",
+    )
+    receiver_kind: str | None = Field(
+        default=None,
+        description="Go method receiver: 'value' (works on a copy), 'pointer' (can mutate) or 'none'.",
+    )
+    visibility: str | None = Field(
+        default=None,
+        description="'exported' or 'unexported' from its package, read from the name (Go capitalisation).",
+    )
+    signature: str | None = Field(
+        default=None,
+        description="Declared signature with parameters and results, e.g. 'Clamp(value, min, max int) (result int)'.",
+    )
 
 
 class ComponentFileMethodGroupJson(BaseModel):
@@ -202,6 +295,7 @@ def _relation_edge_to_json(edge: RelationEdge, repo_dir: Path) -> RelationEdgeJs
         target=_source_reference_method_key(edge.target, repo_dir),
         call_sites=edge.call_sites,
         description=edge.description,
+        flags=edge.flags or None,
     )
 
 
@@ -243,6 +337,10 @@ def _build_methods_index_from_files(files_index: dict[str, FileEntry]) -> dict[s
                 end_line=method.end_line,
                 type=method.node_type,
                 content_hash=method.content_hash,
+                kind=method.kind or None,
+                receiver_kind=method.receiver_kind or receiver_kind(method.qualified_name).value,
+                visibility=method.visibility or visibility(method.qualified_name, file_path).value,
+                signature=method.signature or None,
             )
     return methods_index
 
@@ -282,6 +380,10 @@ def _hydrate_component_methods_from_refs(
                         end_line=indexed.end_line,
                         node_type=indexed.type,
                         content_hash=indexed.content_hash,
+                        kind=indexed.kind or "",
+                        receiver_kind=indexed.receiver_kind or "",
+                        visibility=indexed.visibility or "",
+                        signature=indexed.signature or "",
                     )
                 )
 
@@ -306,6 +408,7 @@ def _relation_to_json(r: Relation, repo_dir: Path) -> RelationJson:
         dst_id=r.dst_id,
         is_static=r.is_static,
         all_edges=[_relation_edge_to_json(edge, repo_dir) for edge in r.all_edges],
+        implements=r.implements or None,
     )
 
 
@@ -349,6 +452,15 @@ def from_component_to_json_component(
         source_cluster_ids=component.source_cluster_ids,
         file_methods=_to_component_file_method_refs(component.file_methods),
         can_expand=can_expand,
+        external=component.external or None,
+        spec_operations=component.spec_operations or None,
+        http_routes=component.http_routes or None,
+        external_calls=component.external_calls or None,
+        deprecated=component.deprecated or None,
+        deprecated_symbols=component.deprecated_symbols or None,
+        owners=component.owners or None,
+        tests=component.tests or None,
+        not_described=component.not_described or None,
         components=nested_components,
         components_relations=nested_relations,
     )
@@ -436,6 +548,8 @@ def build_unified_analysis_json(
     depth_cap: int,
     sub_analyses: dict[str, tuple[AnalysisInsights, list[Component]]] | None = None,
     file_coverage_summary: FileCoverageSummary | None = None,
+    llm_providers: dict[str, str] | None = None,
+    analysis_coverage: AnalysisCoverageSummary | None = None,
 ) -> str:
     """Build the full unified analysis JSON with metadata and nested sub-analyses.
 
@@ -465,12 +579,14 @@ def build_unified_analysis_json(
     ]
     unified = UnifiedAnalysisJson(
         metadata=AnalysisMetadata(
-            generated_at=datetime.now(timezone.utc).isoformat(),
+            generated_at=generated_at(),
             source_tree_hash=source_tree_hash,
             repo_name=repo_name,
             depth_level=_compute_depth_level(sub_analyses),
             depth_cap=depth_cap,
             file_coverage_summary=summary,
+            llm_providers=llm_providers or None,
+            analysis_coverage=analysis_coverage,
         ),
         description=analysis.description,
         files=_build_file_entry_json_from_files(files_index),
@@ -534,6 +650,10 @@ def _reconstruct_files_index(
                     end_line=indexed.end_line,
                     node_type=indexed.type,
                     content_hash=indexed.content_hash,
+                    kind=indexed.kind or "",
+                    receiver_kind=indexed.receiver_kind or "",
+                    visibility=indexed.visibility or "",
+                    signature=indexed.signature or "",
                 )
             )
         entry.merge_from(FileEntry(methods=indexed_methods))
@@ -595,6 +715,15 @@ def _extract_analysis_recursive(
             key_entities=key_entities,
             file_methods=file_methods,
             source_cluster_ids=comp_data.get("source_cluster_ids", []),
+            external=bool(comp_data.get("external", False)),
+            spec_operations=[SpecOperationLink(**op) for op in comp_data.get("spec_operations") or []],
+            http_routes=[HttpRouteLink(**route) for route in comp_data.get("http_routes") or []],
+            external_calls=[ExternalPackageCalls(**entry) for entry in comp_data.get("external_calls") or []],
+            deprecated=bool(comp_data.get("deprecated", False)),
+            deprecated_symbols=list(comp_data.get("deprecated_symbols") or []),
+            owners=list(comp_data.get("owners") or []),
+            tests=list(comp_data.get("tests") or []),
+            not_described=comp_data.get("not_described") or "",
         )
         components.append(component)
 
@@ -634,6 +763,7 @@ def _extract_analysis_recursive(
                 dst_id=r.get("dst_id", ""),
                 is_static=r.get("is_static", False),
                 all_edges=all_edges,
+                implements=bool(r.get("implements", False)),
             )
         )
 
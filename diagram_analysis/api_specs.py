@@ -0,0 +1,202 @@
+"""Link generated API client methods to the OpenAPI/Swagger operations they implement.
+
+Generated clients are where the contract (the spec) meets the implementation,
+but statically they are just more methods. A file counts as generated client
+code when its header carries a known generator marker (OpenAPI Generator,
+Swagger Codegen, oapi-codegen, ...). Its methods are matched by name to the
+``operationId`` of operations in the specs found for it:
+
+- every spec listed under ``[openapi] specs`` in ``.codeboarding/config.toml``;
+- ``openapi.*`` / ``swagger.*`` files in the client's directory or any parent
+  up to the repo root (directly, or in an ``api/``, ``spec/``, ``specs/`` or
+  ``openapi/`` subdirectory).
+
+Matches land on the owning component as ``spec_operations``, so the docs can
+say "this component calls operation X defined in spec Y".
+"""
+
+import json
+import logging
+import re
+from collections.abc import Iterable
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+
+import yaml
+
+from agents.agent_responses import AnalysisInsights
+from agents.file_index_models import SpecOperationLink
+from project_config import ProjectConfig
+
+logger = logging.getLogger(__name__)
+
+_GENERATOR_MARKER_RE = re.compile(
+    r"openapi[- ]generator|swagger[- ]codegen|oapi-codegen|openapi-typescript-codegen|autorest|nswag|"
+    r"generated by orval",
+    re.IGNORECASE,
+)
+_HEADER_LINES = 40
+_SPEC_FILENAMES = ("openapi.yaml", "openapi.yml", "openapi.json", "swagger.yaml", "swagger.yml", "swagger.json")
+_SPEC_SUBDIRS = ("", "api", "spec", "specs", "openapi")
+_HTTP_METHODS = ("get", "put", "post", "delete", "options", "head", "patch", "trace")
+# Variants generators emit per operation (``list_pets_with_http_info``, ``ListPetsWithResponse``,
+# ``NewListPetsRequest``, ``listPetsRaw``), normalized to lower-case alphanumerics.
+_NAME_SUFFIXES = ("withhttpinfo", "withoutpreloadcontent", "withbody", "withresponse", "execute", "async", "raw")
+
+
+@dataclass(frozen=True)
+class SpecOperation:
+    spec_file: str
+    method: str
+    path: str
+    operation_id: str
+
+
+def _match_key(name: str) -> str:
+    key = re.sub(r"[^a-z0-9]", "", name.lower())
+    if key.startswith("new") and key.endswith("request"):
+        key = key[3 : -len("request")]
+    stripped = True
+    while stripped:
+        stripped = False
+        for suffix in _NAME_SUFFIXES:
+            if key.endswith(suffix) and len(key) > len(suffix):
+                key = key[: -len(suffix)]
+                stripped = True
+    return key
+
+
+def is_generated_client(path: Path) -> bool:
+    """True when *path*'s header names a known OpenAPI/Swagger client generator."""
+    try:
+        with open(path, encoding="utf-8", errors="replace") as f:
+            header = "".join(line for _, line in zip(range(_HEADER_LINES), f))
+    except OSError:
+        return False
+    return bool(_GENERATOR_MARKER_RE.search(header))
+
+
+def load_spec_operations(repo_dir: Path, spec_file: str) -> list[SpecOperation]:
+    """Operations with an ``operationId`` in the YAML/JSON spec at *spec_file* (repo-relative)."""
+    path = repo_dir / spec_file
+    try:
+        text = path.read_text(encoding="utf-8")
+        spec = json.loads(text) if path.suffix == ".json" else yaml.safe_load(text)
+    except (OSError, ValueError, yaml.YAMLError) as e:
+        logger.warning(f"Skipping API spec {spec_file}: {e}")
+        return []
+    paths = spec.get("paths") if isinstance(spec, dict) else None
+    if not isinstance(paths, dict):
+        return []
+
+    operations = []
+    for route, item in paths.items():
+        if not isinstance(item, dict):
+            continue
+        for method in _HTTP_METHODS:
+            operation = item.get(method)
+            if isinstance(operation, dict) and operation.get("operationId"):
+                operations.append(SpecOperation(spec_file, method.upper(), str(route), str(operation["operationId"])))
+    return operations
+
+
+class _SpecFinder:
+    """Finds the specs for a generated file, probing each ancestor directory once."""
+
+    def __init__(self, repo_dir: Path, configured: list[str]):
+        self.repo_dir = repo_dir
+        self.configured = configured
+        self._by_dir: dict[PurePosixPath, list[str]] = {}
+
+    def specs_for(self, file_path: str) -> list[str]:
+        found = list(self.configured)
+        for directory in PurePosixPath(file_path).parents:
+            for spec in self._specs_in(directory):
+                if spec not in found:
+                    found.append(spec)
+        return found
+
+    def _specs_in(self, directory: PurePosixPath) -> list[str]:
+        if directory not in self._by_dir:
+            self._by_dir[directory] = [
+                (directory / subdir / name).as_posix()
+                for subdir in _SPEC_SUBDIRS
+                for name in _SPEC_FILENAMES
+                if (self.repo_dir / directory / subdir / name).is_file()
+            ]
+        return self._by_dir[directory]
+
+
+def _relative(repo_dir: Path, file_path: str) -> str | None:
+    path = Path(file_path)
+    if path.is_absolute():
+        try:
+            path = path.relative_to(repo_dir)
+        except ValueError:
+            return None
+    return path.as_posix()
+
+
+def link_spec_operations(
+    repo_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    project_config: ProjectConfig,
+    generated_files: Iterable[str] | None = None,
+) -> int:
+    """Set ``spec_operations`` on every component, at every level; returns the number of root-level links.
+
+    *generated_files* overrides marker detection (repo-relative paths); by default each
+    root-level component file is probed once.
+    """
+    configured = project_config.section("openapi").get("specs", [])
+    if isinstance(configured, str):
+        configured = [configured]
+    finder = _SpecFinder(repo_dir, [str(p).strip().strip("/") for p in configured if str(p).strip()])
+
+    # Sub-components only split their parent's files, so the root level covers every generated file.
+    root_files = {
+        file_path: rel
+        for component in root_analysis.components
+        for file_path in component.file_paths()
+        if (rel := _relative(repo_dir, file_path)) is not None
+    }
+    if generated_files is None:
+        generated = {rel for rel in root_files.values() if is_generated_client(repo_dir / rel)}
+    else:
+        generated = set(generated_files)
+
+    operations_by_spec: dict[str, dict[str, SpecOperation]] = {}
+    linked = 0
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for component in analysis.components:
+            component.spec_operations = []
+            for group in component.file_methods:
+                rel = root_files.get(group.file_path) or _relative(repo_dir, group.file_path)
+                if rel not in generated:
+                    continue
+                specs = finder.specs_for(rel)
+                for method in group.methods:
+                    key = _match_key(method.qualified_name.rsplit(".", 1)[-1])
+                    for spec in specs:
+                        if spec not in operations_by_spec:
+                            operations_by_spec[spec] = {
+                                _match_key(op.operation_id): op for op in load_spec_operations(repo_dir, spec)
+                            }
+                        if (op := operations_by_spec[spec].get(key)) is not None:
+                            component.spec_operations.append(
+                                SpecOperationLink(
+                                    qualified_name=method.qualified_name,
+                                    spec_file=op.spec_file,
+                                    method=op.method,
+                                    path=op.path,
+                                    operation_id=op.operation_id,
+                                )
+                            )
+                            break
+            if analysis is root_analysis:
+                linked += len(component.spec_operations)
+
+    if linked:
+        logger.info(f"Linked {linked} generated client methods to operations in {sorted(operations_by_spec)}")
+    return linked
@@ -0,0 +1,217 @@
+"""Architecture changes between two revisions (``codeboarding --diff BASE..HEAD``).
+
+Both revisions are analysed statically and exported as the ``--output-format
+json`` model (see :mod:`output_generators.json_export`); the diff compares the
+two models: entities and edges added or removed, and entities whose signature,
+receiver or kind changed. Entities are matched by qualified name, edges by
+``(source, target, kind)``, so a rename shows as one removal and one addition.
+
+The report is Markdown with an inline Mermaid flowchart: added elements are
+green, removed ones red, changed ones amber. Unchanged endpoints of a changed
+edge are drawn plain, for context.
+"""
+
+import json
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any
+
+DIFF_MARKDOWN_FILENAME = "architecture_diff.md"
+DIFF_JSON_FILENAME = "architecture_diff.json"
+# Edges drawn in the Mermaid chart; the Markdown lists still name every change.
+MAX_DIAGRAM_EDGES = 150
+
+_ENTITY_FIELDS = ("kind", "receiver", "signature")
+_STYLES = {
+    "added": "fill:#d4f8d4,stroke:#2da44e,color:#116329",
+    "removed": "fill:#ffd7d5,stroke:#cf222e,color:#82071e",
+    "changed": "fill:#fff1c2,stroke:#bf8700,color:#7d4e00",
+}
+
+
+@dataclass(frozen=True)
+class RefRange:
+    """``BASE..HEAD``; with ``BASE...HEAD`` the base is their merge base, as in ``git diff``."""
+
+    base: str
+    head: str
+    from_merge_base: bool = False
+
+    @classmethod
+    def parse(cls, text: str) -> "RefRange":
+        """``A..B``, ``A...B``, or a lone ``A`` meaning ``A..HEAD``; raises ValueError on an empty side."""
+        from_merge_base = "..." in text
+        base, sep, head = text.partition("..." if from_merge_base else "..")
+        if not sep:
+            head = "HEAD"
+        if not base.strip() or not head.strip():
+            raise ValueError(f"expected BASE..HEAD, got '{text}'")
+        return cls(base.strip(), head.strip(), from_merge_base)
+
+    def __str__(self) -> str:
+        return f"{self.base}{'...' if self.from_merge_base else '..'}{self.head}"
+
+
+def _mermaid_label(text: str) -> str:
+    return text.replace('"', "#quot;")
+
+
+def _edge_key(edge: dict[str, Any]) -> tuple[str, str, str]:
+    return edge["source"], edge["target"], edge["kind"]
+
+
+@dataclass
+class EntityChange:
+    qualified_name: str
+    before: dict[str, Any]
+    after: dict[str, Any]
+
+    def changed_fields(self) -> list[str]:
+        return [name for name in _ENTITY_FIELDS if self.before.get(name) != self.after.get(name)]
+
+
+@dataclass
+class ArchitectureDiff:
+    base: str
+    head: str
+    added_entities: list[dict[str, Any]] = field(default_factory=list)
+    removed_entities: list[dict[str, Any]] = field(default_factory=list)
+    changed_entities: list[EntityChange] = field(default_factory=list)
+    added_edges: list[dict[str, Any]] = field(default_factory=list)
+    removed_edges: list[dict[str, Any]] = field(default_factory=list)
+
+    def is_empty(self) -> bool:
+        return not (
+            self.added_entities
+            or self.removed_entities
+            or self.changed_entities
+            or self.added_edges
+            or self.removed_edges
+        )
+
+    def summary(self) -> str:
+        if self.is_empty():
+            return f"No architecture changes between {self.base} and {self.head}"
+        return (
+            f"{self.base}..{self.head}: "
+            f"+{len(self.added_entities)} -{len(self.removed_entities)} ~{len(self.changed_entities)} entities, "
+            f"+{len(self.added_edges)} -{len(self.removed_edges)} edges"
+        )
+
+
+def diff_models(base: dict[str, Any], head: dict[str, Any], base_ref: str, head_ref: str) -> ArchitectureDiff:
+    """Compare two ``static_analysis.json`` models, *base* the older revision."""
+    before = {entity["qualified_name"]: entity for entity in base["entities"]}
+    after = {entity["qualified_name"]: entity for entity in head["entities"]}
+    before_edges = {_edge_key(edge): edge for edge in base["edges"]}
+    after_edges = {_edge_key(edge): edge for edge in head["edges"]}
+    changed = [EntityChange(name, before[name], after[name]) for name in sorted(before.keys() & after.keys())]
+    return ArchitectureDiff(
+        base=base_ref,
+        head=head_ref,
+        added_entities=[after[name] for name in sorted(after.keys() - before.keys())],
+        removed_entities=[before[name] for name in sorted(before.keys() - after.keys())],
+        changed_entities=[change for change in changed if change.changed_fields()],
+        added_edges=[after_edges[key] for key in sorted(after_edges.keys() - before_edges.keys())],
+        removed_edges=[before_edges[key] for key in sorted(before_edges.keys() - after_edges.keys())],
+    )
+
+
+def _signature_text(entity: dict[str, Any]) -> str:
+    signature = entity.get("signature")
+    return signature["text"] if signature else entity["kind"]
+
+
+def render_diff_mermaid(diff: ArchitectureDiff, max_edges: int = MAX_DIAGRAM_EDGES) -> str:
+    """A flowchart of every changed entity and at most *max_edges* changed edges, coloured by status."""
+    status: dict[str, str] = {}
+    for entity in diff.added_entities:
+        status[entity["qualified_name"]] = "added"
+    for entity in diff.removed_entities:
+        status[entity["qualified_name"]] = "removed"
+    for change in diff.changed_entities:
+        status[change.qualified_name] = "changed"
+    edges = [(edge, "added") for edge in diff.added_edges] + [(edge, "removed") for edge in diff.removed_edges]
+    shown_edges = edges[:max_edges]
+    names = set(status)
+    for edge, _ in shown_edges:
+        names.update((edge["source"], edge["target"]))
+
+    ids = {name: f"N{index}" for index, name in enumerate(sorted(names))}
+    lines = ["graph LR"]
+    lines.extend(f'    {ids[name]}["{_mermaid_label(name)}"]' for name in sorted(names))
+    for index, (edge, edge_status) in enumerate(shown_edges):
+        arrow = "-->" if edge_status == "added" else "-.->"
+        lines.append(f"    {ids[edge['source']]} {arrow}|{edge['kind']}| {ids[edge['target']]}")
+        colour = "#2da44e" if edge_status == "added" else "#cf222e"
+        lines.append(f"    linkStyle {index} stroke:{colour}")
+    if len(edges) > len(shown_edges):
+        lines.append(f'    OMITTED["+{len(edges) - len(shown_edges)} more changed edges"]')
+    for name_status, style in _STYLES.items():
+        members = [ids[name] for name in sorted(names) if status.get(name) == name_status]
+        if members:
+            lines.append(f"    classDef {name_status} {style}")
+            lines.append(f"    class {','.join(members)} {name_status}")
+    return "\n".join(lines) + "\n"
+
+
+def _entity_line(entity: dict[str, Any]) -> str:
+    return f"- `{entity['qualified_name']}` ({_signature_text(entity)}) {entity['file']}:{entity['line_start']}"
+
+
+def _edge_line(edge: dict[str, Any]) -> str:
+    return f"- `{edge['source']}` -{edge['kind']}-> `{edge['target']}`"
+
+
+def render_diff_markdown(diff: ArchitectureDiff, max_edges: int = MAX_DIAGRAM_EDGES) -> str:
+    lines = [f"# Architecture changes: {diff.base}..{diff.head}", "", diff.summary()]
+    if diff.is_empty():
+        return "\n".join(lines) + "\n"
+    lines += ["", "```mermaid", render_diff_mermaid(diff, max_edges).rstrip("\n"), "```"]
+    sections: list[tuple[str, list[str]]] = [
+        ("Added entities", [_entity_line(entity) for entity in diff.added_entities]),
+        ("Removed entities", [_entity_line(entity) for entity in diff.removed_entities]),
+        (
+            "Changed entities",
+            [
+                f"- `{change.qualified_name}`: {_signature_text(change.before)} -> {_signature_text(change.after)}"
+                for change in diff.changed_entities
+            ],
+        ),
+        ("Added edges", [_edge_line(edge) for edge in diff.added_edges]),
+        ("Removed edges", [_edge_line(edge) for edge in diff.removed_edges]),
+    ]
+    for title, entries in sections:
+        if entries:
+            lines += ["", f"## {title} ({len(entries)})", "", *entries]
+    return "\n".join(lines) + "\n"
+
+
+def render_diff_json(diff: ArchitectureDiff) -> str:
+    payload = {
+        "base": diff.base,
+        "head": diff.head,
+        "added_entities": diff.added_entities,
+        "removed_entities": diff.removed_entities,
+        "changed_entities": [
+            {
+                "qualified_name": change.qualified_name,
+                "fields": change.changed_fields(),
+                "before": change.before,
+                "after": change.after,
+            }
+            for change in diff.changed_entities
+        ],
+        "added_edges": diff.added_edges,
+        "removed_edges": diff.removed_edges,
+    }
+    return json.dumps(payload, indent=2, ensure_ascii=False) + "\n"
+
+
+def write_diff(diff: ArchitectureDiff, output_dir: Path) -> list[Path]:
+    """Write ``architecture_diff.md`` and ``architecture_diff.json`` into *output_dir*."""
+    output_dir.mkdir(parents=True, exist_ok=True)
+    markdown_path, json_path = output_dir / DIFF_MARKDOWN_FILENAME, output_dir / DIFF_JSON_FILENAME
+    markdown_path.write_text(render_diff_markdown(diff), encoding="utf-8")
+    json_path.write_text(render_diff_json(diff), encoding="utf-8")
+    return [markdown_path, json_path]
@@ -0,0 +1,111 @@
+"""CODEOWNERS-based component ownership (``--use-codeowners``).
+
+Each component's files are matched against the repository's ``CODEOWNERS``
+(looked up where GitHub looks: ``.github/``, the root, then ``docs/``); the
+last matching rule owns a file, as on GitHub. A component is owned by every
+owner of its files, most files first, so ``analysis.json`` and the docs can
+say who to talk to and which teams' components a component depends on.
+"""
+
+import logging
+import re
+from collections import Counter
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+
+from agents.agent_responses import AnalysisInsights
+
+logger = logging.getLogger(__name__)
+
+CODEOWNERS_LOCATIONS = (".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS")
+
+
+def _pattern_regex(pattern: str) -> re.Pattern:
+    """Gitignore-style CODEOWNERS pattern -> regex over repo-relative POSIX paths.
+
+    A pattern with a leading or inner ``/`` is anchored at the root; otherwise it
+    matches at any depth. A match on a directory covers everything beneath it.
+    """
+    anchored = "/" in pattern.rstrip("/")
+    body = pattern.strip("/")
+    out = []
+    i = 0
+    while i < len(body):
+        if body.startswith("**/", i):
+            out.append("(?:.*/)?")
+            i += 3
+        elif body.startswith("**", i):
+            out.append(".*")
+            i += 2
+        elif body[i] == "*":
+            out.append("[^/]*")
+            i += 1
+        elif body[i] == "?":
+            out.append("[^/]")
+            i += 1
+        else:
+            out.append(re.escape(body[i]))
+            i += 1
+    prefix = "" if anchored else "(?:.*/)?"
+    return re.compile(f"^{prefix}{''.join(out)}(?:/.*)?$")
+
+
+@dataclass(frozen=True)
+class CodeOwners:
+    rules: tuple[tuple[re.Pattern, tuple[str, ...]], ...]
+
+    @classmethod
+    def parse(cls, text: str) -> "CodeOwners":
+        rules = []
+        for line in text.splitlines():
+            line = line.split(" #", 1)[0].strip()
+            if not line or line.startswith("#"):
+                continue
+            pattern, *owners = line.split()
+            # A rule without owners un-assigns the files it matches, so it still counts.
+            rules.append((_pattern_regex(pattern), tuple(owners)))
+        return cls(rules=tuple(rules))
+
+    def owners_for(self, rel_path: str) -> tuple[str, ...]:
+        for regex, owners in reversed(self.rules):
+            if regex.match(rel_path):
+                return owners
+        return ()
+
+
+def load_codeowners(repo_dir: Path) -> CodeOwners | None:
+    """The repository's CODEOWNERS, or None when it has none."""
+    for location in CODEOWNERS_LOCATIONS:
+        path = repo_dir / location
+        if path.is_file():
+            try:
+                return CodeOwners.parse(path.read_text(encoding="utf-8", errors="replace"))
+            except OSError as e:
+                logger.warning(f"Could not read {path}: {e}")
+                return None
+    logger.info(f"No CODEOWNERS file in {repo_dir}; components are left without owners")
+    return None
+
+
+def assign_component_owners(
+    repo_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    codeowners: CodeOwners | None,
+) -> None:
+    """Set ``owners`` on every component, at every level: owners of its files, most files first.
+
+    With no CODEOWNERS every component's owners are cleared, so a baseline loaded
+    from an earlier ``--use-codeowners`` run does not keep stale owners.
+    """
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for component in analysis.components:
+            counts: Counter[str] = Counter()
+            for file_path in set(component.file_paths()) if codeowners is not None else ():
+                path = Path(file_path)
+                if path.is_absolute():
+                    if not path.is_relative_to(repo_dir):
+                        continue
+                    path = path.relative_to(repo_dir)
+                counts.update(codeowners.owners_for(PurePosixPath(path).as_posix()))
+            component.owners = [owner for owner, _ in sorted(counts.items(), key=lambda item: (-item[1], item[0]))]
@@ -0,0 +1,214 @@
+"""Price a full analysis before it makes a single LLM request (``--estimate``, ``--max-cost``).
+
+The static analysis already fixes the shape of the run: the top-level
+components are the deterministic Leiden groups the abstraction agent only
+names, and every component above ``--depth-level`` is expanded by the details
+agent. From that shape this counts the prompts a run sends:
+
+* one project-metadata prompt;
+* three overview prompts (final analysis, API surfaces, relations) carrying
+  the summaries of the top-level groups;
+* the same three prompts for every expanded component, carrying its own
+  summary and those of its sub-groups. Below the first level the groups are
+  not known until the parent is analysed, so each expanded component is
+  assumed to split into ``SUBCOMPONENTS_MIN`` children summarised like it.
+  A ``--grouping`` by package or directory expands nothing.
+
+A run stops sending prompts at ``--max-llm-calls``, so the estimate keeps
+only that many.
+
+Input tokens are counted by Anthropic's token counting endpoint for its
+models (free, not a model request), with the model's tokenizer when tiktoken
+knows it, else at ``ModelCapabilities.CHARS_PER_TOKEN``; each response is assumed to be
+:data:`ASSUMED_OUTPUT_TOKENS` long. Tool-call turns (the agents reading
+source files), validation retries and parsing-model calls are not counted,
+and cached responses are not discounted: the figure is a floor for a cold
+run, not a bill.
+"""
+
+import logging
+from dataclasses import dataclass, field
+from functools import lru_cache
+
+import tiktoken
+from langchain_anthropic import ChatAnthropic
+from langchain_core.messages import HumanMessage
+
+from agents.cluster_methods_mixin import ClusterMethodsMixin
+from agents.constants import ModelCapabilities
+from agents.model_capabilities import ModelPricing, get_pricing, price_env_var
+from agents.prompts import LLMType, PromptFactory
+from diagram_analysis.grouping import Grouping
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.cluster_helpers import SUBCOMPONENTS_MIN, build_all_cluster_results
+
+logger = logging.getLogger(__name__)
+
+# Exit code of a run stopped by ``--max-cost``.
+EXIT_COST_LIMIT_EXCEEDED = 6
+# Typical length of one structured agent response (a component list or a relation list).
+ASSUMED_OUTPUT_TOKENS = 2_000
+
+
+@dataclass(frozen=True)
+class PromptEstimate:
+    # ``meta``, ``overview.final_analysis``, ``component.relations`` ...
+    step: str
+    input_tokens: int
+    output_tokens: int = ASSUMED_OUTPUT_TOKENS
+
+
+@dataclass
+class CostEstimate:
+    provider: str
+    model: str
+    depth_level: int
+    components: int = 0
+    prompts: list[PromptEstimate] = field(default_factory=list)
+    # None when no catalog prices the model.
+    pricing: ModelPricing | None = None
+
+    @property
+    def input_tokens(self) -> int:
+        return sum(prompt.input_tokens for prompt in self.prompts)
+
+    @property
+    def output_tokens(self) -> int:
+        return sum(prompt.output_tokens for prompt in self.prompts)
+
+    @property
+    def cost(self) -> float | None:
+        if self.pricing is None:
+            return None
+        return self.pricing.cost(self.input_tokens, self.output_tokens)
+
+    def summary(self) -> str:
+        lines = [
+            f"Estimate for {self.provider}/{self.model} (depth {self.depth_level}), no LLM requests made:",
+            f"  components: {self.components}",
+            f"  prompts:    {len(self.prompts)}",
+            f"  input:      {self.input_tokens:,} tokens",
+            f"  output:     ~{self.output_tokens:,} tokens ({ASSUMED_OUTPUT_TOKENS:,} per prompt)",
+        ]
+        if self.pricing is None:
+            lines.append(
+                f"  cost:       unknown, no price for {self.model}; "
+                f"set {price_env_var(self.provider, self.model)}='input,output' in USD per 1M tokens"
+            )
+        else:
+            lines.append(
+                f"  cost:       ~${self.cost:.2f} (${self.pricing.input_per_million:g} in / "
+                f"${self.pricing.output_per_million:g} out per 1M tokens)"
+            )
+        return "\n".join(lines)
+
+
+@lru_cache(maxsize=8)
+def _encoding(model_name: str) -> tiktoken.Encoding | None:
+    try:
+        return tiktoken.encoding_for_model(model_name)
+    except KeyError:
+        # Not an OpenAI model.
+        return None
+
+
+@lru_cache(maxsize=8)
+def _anthropic_model(model_name: str) -> ChatAnthropic:
+    return ChatAnthropic(model_name=model_name)
+
+
+def count_tokens(text: str, provider: str, model_name: str) -> int:
+    if provider == "anthropic":
+        # get_num_tokens would use a local GPT-2 tokenizer; the messages form asks Anthropic.
+        return _anthropic_model(model_name).get_num_tokens_from_messages([HumanMessage(content=text)])
+    encoding = _encoding(model_name)
+    if encoding is not None:
+        return len(encoding.encode(text, disallowed_special=()))
+    return int(len(text) / ModelCapabilities.CHARS_PER_TOKEN)
+
+
+def plan_prompts(
+    overview_tokens: int, group_tokens: list[int], depth_level: int, overhead: dict[str, int]
+) -> tuple[list[PromptEstimate], int]:
+    """The prompts of a run and the number of components it produces.
+
+    *overview_tokens* is the size of the top-level group listing and
+    *group_tokens* that of each group's summary. *overhead* holds the size of
+    each prompt's fixed text (system message plus template): ``meta``,
+    ``overview.<step>`` and ``component.<step>`` for the steps
+    ``final_analysis``, ``api_surfaces`` and ``relations``.
+    """
+    prompts = [PromptEstimate("meta", overhead["meta"])]
+    for step in ("final_analysis", "api_surfaces", "relations"):
+        prompts.append(PromptEstimate(f"overview.{step}", overhead[f"overview.{step}"] + overview_tokens))
+    components = len(group_tokens)
+
+    def expand(summary_tokens: int, level: int) -> None:
+        nonlocal components
+        if level >= depth_level:
+            return
+        children = SUBCOMPONENTS_MIN * summary_tokens
+        prompts.append(
+            PromptEstimate("component.final_analysis", overhead["component.final_analysis"] + summary_tokens + children)
+        )
+        for step in ("api_surfaces", "relations"):
+            prompts.append(PromptEstimate(f"component.{step}", overhead[f"component.{step}"] + children))
+        components += SUBCOMPONENTS_MIN
+        for _ in range(SUBCOMPONENTS_MIN):
+            expand(summary_tokens, level + 1)
+
+    for tokens in group_tokens:
+        expand(tokens, 1)
+    return prompts, components
+
+
+class _Grouping(ClusterMethodsMixin):
+    """The cluster helpers of the agents, without an agent (and its LLM) around them."""
+
+    def __init__(self, static_analysis: StaticAnalysisResults) -> None:
+        self.static_analysis = static_analysis
+
+
+def _overhead(provider: str, model_name: str) -> dict[str, int]:
+    factory = PromptFactory(LLMType.from_model_name(model_name))
+    system, details = factory.get_prompt("system_message"), factory.get_prompt("system_details_message")
+    texts = {
+        "meta": factory.get_prompt("system_meta_analysis_message") + factory.get_prompt("meta_information_prompt"),
+        "overview.final_analysis": system + factory.get_prompt("final_analysis_message"),
+        "component.final_analysis": details + factory.get_prompt("details_message"),
+    }
+    for scope, system_text in (("overview", system), ("component", details)):
+        texts[f"{scope}.api_surfaces"] = system_text + factory.get_prompt("api_surfaces_message")
+        texts[f"{scope}.relations"] = system_text + factory.get_prompt("relation_analysis_message")
+    return {step: count_tokens(text, provider, model_name) for step, text in texts.items()}
+
+
+def estimate_run(
+    static_analysis: StaticAnalysisResults,
+    depth_level: int,
+    provider: str,
+    model_name: str,
+    grouping: Grouping = Grouping.SEMANTIC,
+    max_llm_calls: int | None = None,
+) -> CostEstimate:
+    """Estimate the prompts, tokens and price of a full analysis of *static_analysis*."""
+    estimate = CostEstimate(provider=provider, model=model_name, depth_level=depth_level)
+    estimate.pricing = get_pricing(provider, model_name)
+    cluster_results = build_all_cluster_results(static_analysis)
+    if not any(result.get_cluster_ids() for result in cluster_results.values()):
+        logger.warning("No clusters in the static analysis; a run would stop before its first LLM request")
+        return estimate
+
+    cluster_analysis = _Grouping(static_analysis).deterministic_cluster_grouping(cluster_results)
+    overview_tokens = count_tokens(cluster_analysis.llm_str(), provider, model_name)
+    group_tokens = [
+        count_tokens(group.llm_str(), provider, model_name) for group in cluster_analysis.cluster_components
+    ]
+    # Only a semantic grouping expands its components; the run stops at the overview otherwise.
+    expanded_depth = depth_level if grouping == Grouping.SEMANTIC else 1
+    estimate.prompts, estimate.components = plan_prompts(
+        overview_tokens, group_tokens, expanded_depth, _overhead(provider, model_name)
+    )
+    if max_llm_calls is not None:
+        estimate.prompts = estimate.prompts[:max_llm_calls]
+    return estimate
@@ -0,0 +1,246 @@
+"""Dead-code report (``--report dead-code``): symbols and packages nothing refers to.
+
+A function, method, type or module-level constant is *never referenced* when
+no edge of the static analysis points at it: no call, inheritance, interface
+implementation, type reference or import. Containment (a method belongs to its
+class) does not count. Test files are excluded from the analysis itself, so
+they are scanned by name as for the structural test coverage (see
+:mod:`diagram_analysis.structural_coverage`): a symbol nothing in the project
+refers to but that a test names is listed separately, as *only referenced from
+tests*.
+
+Packages (source directories) that no other non-test package depends on are
+called out as a whole, unless they define an entry point (``main``).
+
+Entry points and the methods a type must have are never reported: ``main``,
+``init``, ``__dunder__`` methods, a Swift ``@main`` type and its members (a
+SwiftUI ``App``'s ``body``), and methods with the name of a method of a
+superclass or implemented interface (they are reached through it).
+
+Like every unused-code signal built on static references, dynamic dispatch,
+reflection and framework wiring can make a used symbol look dead; the report
+is a list of candidates to check.
+"""
+
+import os
+import re
+from collections import defaultdict
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+
+from diagram_analysis.structural_coverage import find_test_files, is_test_file, referenced_names
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import CLASS_TYPES, GRAPH_NODE_TYPES, Language, NodeType
+from static_analyzer.graph import EdgeKind
+from static_analyzer.node import Node, go_receiver_and_method
+
+DEAD_CODE_FILENAME = "dead_code.md"
+REPORTS = ("dead-code",)
+
+_ENTRY_POINT_NAMES = {"main", "init", "__main__"}
+_REPORTED_TYPES = GRAPH_NODE_TYPES | {NodeType.CONSTANT}
+_NON_REFERENCE_KINDS = {EdgeKind.CONTAINS}
+# ``@main struct App`` / ``@main`` above it: Swift's entry point is the type the attribute marks.
+_SWIFT_MAIN_RE = re.compile(r"@main\b")
+# Lines above a declaration searched for its attributes.
+_ATTRIBUTE_LINES = 3
+
+
+@dataclass
+class DeadSymbol:
+    qualified_name: str
+    kind: str
+    file_path: str
+    line: int
+
+    @property
+    def location(self) -> str:
+        return f"{self.file_path}:{self.line}"
+
+
+@dataclass
+class DeadCodeReport:
+    # Package -> symbols, in file and line order.
+    unreferenced: dict[str, list[DeadSymbol]] = field(default_factory=dict)
+    test_only: dict[str, list[DeadSymbol]] = field(default_factory=dict)
+    # Package -> number of symbols it defines.
+    unimported_packages: dict[str, int] = field(default_factory=dict)
+
+    def count(self, group: dict[str, list[DeadSymbol]]) -> int:
+        return sum(len(symbols) for symbols in group.values())
+
+
+def package_of(file_path: str) -> str:
+    """The file's directory, dot-joined; a root-level file is its own package (its stem)."""
+    path = PurePosixPath(file_path)
+    if path.parent.parts and path.parent != PurePosixPath("."):
+        return ".".join(path.parent.parts)
+    return path.stem
+
+
+def _relative(file_path: str, repo_dir: Path | None) -> str:
+    if repo_dir is None or not os.path.isabs(file_path):
+        return Path(file_path).as_posix()
+    relative = os.path.relpath(file_path, repo_dir)
+    return Path(file_path).as_posix() if relative.startswith("..") else Path(relative).as_posix()
+
+
+def _short_name(qualified_name: str) -> str:
+    return qualified_name.rsplit(".", 1)[-1]
+
+
+def _owner_key(node: Node, repo_dir: Path | None) -> tuple[str, str] | None:
+    """(package, type name) of the type a method belongs to, if it is a member."""
+    member = go_receiver_and_method(node.fully_qualified_name)
+    if member is not None:
+        return package_of(_relative(node.file_path, repo_dir)), member[0]
+    return None
+
+
+def _type_key(qualified_name: str, node: Node, repo_dir: Path | None) -> tuple[str, str]:
+    return package_of(_relative(node.file_path, repo_dir)), _short_name(qualified_name)
+
+
+def _is_entry_point(name: str) -> bool:
+    # ``init#2``: the second ``init`` of a Go file.
+    name = name.split("#", 1)[0]
+    return name in _ENTRY_POINT_NAMES or (name.startswith("__") and name.endswith("__"))
+
+
+def _is_swift_main(node: Node, repo_dir: Path | None) -> bool:
+    """Whether *node* is a Swift type declared ``@main``."""
+    if node.type not in CLASS_TYPES or not node.file_path.endswith(".swift"):
+        return False
+    path = Path(node.file_path)
+    if not path.is_absolute() and repo_dir is not None:
+        path = repo_dir / path
+    try:
+        lines = path.read_text(encoding="utf-8", errors="replace").splitlines()
+    except OSError:
+        return False
+    start = node.line_start - 1
+    return any(_SWIFT_MAIN_RE.search(line) for line in lines[max(start - _ATTRIBUTE_LINES, 0) : start + 1])
+
+
+def _inherited_method_names(
+    static_analysis: StaticAnalysisResults, language: Language, nodes: dict[str, Node], repo_dir: Path | None
+) -> set[str]:
+    """Qualified names of methods that share their name with a method of a supertype of their type."""
+    try:
+        hierarchy = static_analysis.get_hierarchy(language)
+    except ValueError:
+        return set()
+    members: dict[str, set[str]] = defaultdict(set)
+    owners: dict[str, str] = {}
+    type_by_key = {_type_key(q, n, repo_dir): q for q, n in nodes.items() if n.type in CLASS_TYPES}
+    for qname, node in nodes.items():
+        if node.type in CLASS_TYPES:
+            continue
+        key = _owner_key(node, repo_dir)
+        owner = type_by_key.get(key) if key is not None else None
+        if owner is None:
+            parent = qname.rsplit(".", 1)[0]
+            owner = parent if parent in nodes and nodes[parent].type in CLASS_TYPES else None
+        if owner is not None:
+            members[owner].add(_short_name(qname))
+            owners[qname] = owner
+    inherited: set[str] = set()
+    for qname, owner in owners.items():
+        info = hierarchy.get(owner, {})
+        supertypes = [*info.get("superclasses", []), *info.get("interfaces", [])]
+        if any(_short_name(qname) in members.get(supertype, ()) for supertype in supertypes):
+            inherited.add(qname)
+    return inherited
+
+
+def build_dead_code_report(
+    static_analysis: StaticAnalysisResults, repo_dir: Path | None = None, test_names: set[str] | None = None
+) -> DeadCodeReport:
+    """Never-referenced symbols and never-imported packages; *test_names* are identifiers the tests use."""
+    test_names = test_names or set()
+    report = DeadCodeReport()
+    for language in static_analysis.get_languages():
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        nodes: dict[str, Node] = dict(cfg.nodes)
+        for node in static_analysis.iter_reference_nodes(language):
+            nodes.setdefault(node.fully_qualified_name, node)
+
+        referenced = {edge.get_destination() for edge in cfg.edges if edge.get_source() != edge.get_destination()}
+        referenced.update(dst for src, dst, kind in cfg.reference_edges if kind not in _NON_REFERENCE_KINDS)
+        inherited = _inherited_method_names(static_analysis, language, nodes, repo_dir)
+        main_types = {qname for qname, node in nodes.items() if _is_swift_main(node, repo_dir)}
+
+        defined: dict[str, int] = defaultdict(int)
+        entry_packages: set[str] = set()
+        for qname, node in sorted(nodes.items(), key=lambda item: (item[1].file_path, item[1].line_start, item[0])):
+            file_path = _relative(node.file_path, repo_dir)
+            if node.type not in _REPORTED_TYPES or is_test_file(file_path):
+                continue
+            package = package_of(file_path)
+            defined[package] += 1
+            name = _short_name(qname)
+            main = qname in main_types or qname.rsplit(".", 1)[0] in main_types
+            if main or name.split("#", 1)[0] in _ENTRY_POINT_NAMES:
+                entry_packages.add(package)
+            if qname in referenced or qname in inherited or main or _is_entry_point(name):
+                continue
+            symbol = DeadSymbol(qname, node.type.label(), file_path, node.line_start)
+            group = report.test_only if name in test_names else report.unreferenced
+            group.setdefault(package, []).append(symbol)
+
+        try:
+            dependencies = static_analysis.get_package_dependencies(language)
+        except ValueError:
+            dependencies = {}
+        for package, count in defined.items():
+            if package in entry_packages or package not in dependencies:
+                continue
+            importers = [
+                importer
+                for importer in dependencies[package].get("imported_by", [])
+                if not is_test_file(f"{importer.replace('.', '/')}/_")
+            ]
+            if not importers:
+                report.unimported_packages[package] = report.unimported_packages.get(package, 0) + count
+    return report
+
+
+def dead_code_markdown(report: DeadCodeReport) -> str:
+    unreferenced, test_only = report.count(report.unreferenced), report.count(report.test_only)
+    lines = [
+        "# Dead code",
+        "",
+        f"{unreferenced} symbols are never referenced, {test_only} only from tests, and "
+        f"{len(report.unimported_packages)} packages are never imported. Dynamic dispatch, reflection and "
+        "framework wiring are invisible to static references: check each candidate before deleting it.",
+        "",
+        "## Packages never imported",
+        "",
+    ]
+    lines += [
+        f"- `{package}` ({count} symbol{'s' if count != 1 else ''})"
+        for package, count in sorted(report.unimported_packages.items())
+    ] or ["_None_"]
+    for title, group in (("Never referenced", report.unreferenced), ("Only referenced from tests", report.test_only)):
+        lines += ["", f"## {title}", ""]
+        if not group:
+            lines.append("_None_")
+        for package in sorted(group):
+            lines += [f"### `{package}`", ""]
+            lines += [f"- `{s.qualified_name}` ({s.kind}) at `{s.location}`" for s in group[package]]
+            lines.append("")
+    return "\n".join(lines).rstrip() + "\n"
+
+
+def write_dead_code_report(
+    static_analysis: StaticAnalysisResults, repo_dir: Path, output_path: Path
+) -> tuple[Path, DeadCodeReport]:
+    """Scan the tests under *repo_dir*, then write the Markdown report to *output_path*."""
+    suffixes = {Path(f).suffix for f in static_analysis.get_all_source_files() if Path(f).suffix}
+    test_names = referenced_names(find_test_files(repo_dir, suffixes))
+    report = build_dead_code_report(static_analysis, repo_dir, test_names)
+    output_path.write_text(dead_code_markdown(report), encoding="utf-8")
+    return output_path, report
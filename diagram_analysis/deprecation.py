@@ -0,0 +1,215 @@
+"""Deprecated-code annotations.
+
+Legacy code that is kept only for compatibility clutters the architecture
+view. A symbol counts as deprecated when the lines just above its definition
+(doc comments, decorators, annotations, attributes) carry a known marker:
+``@deprecated``, ``@Deprecated``, ``#[deprecated]``, ``[Obsolete]``, a
+``// deprecated`` / ``# deprecated`` comment or Go's ``// Deprecated:``
+paragraph. Its first lines are also checked for in-body conventions
+(``.. deprecated::`` docstrings, ``DeprecationWarning``). A repository can
+list more in ``.codeboarding/config.toml``::
+
+    [deprecated]
+    symbols = ["billing.legacy.*", "*.OldClient.*"]
+    paths = ["src/compat/**"]
+
+A component whose methods are all deprecated is flagged ``deprecated`` so the
+renderers de-emphasize it. With ``--hide-deprecated`` those components are
+collapsed into a single "Deprecated" component, which keeps the relations
+from the code that still depends on them.
+"""
+
+import logging
+import re
+from collections.abc import Iterable
+from dataclasses import dataclass, field
+from fnmatch import fnmatch
+from pathlib import Path, PurePosixPath
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from project_config import ProjectConfig
+
+logger = logging.getLogger(__name__)
+
+DEPRECATED_COMPONENT_NAME = "Deprecated"
+
+_HEADER_MARKER_RE = re.compile(
+    r"@deprecated\b|#\[deprecated\b|\[Obsolete\b|(?://+|#|\*|--)\s*deprecated\b|(?-i:\bDeprecated:)",
+    re.IGNORECASE,
+)
+_BODY_MARKER_RE = re.compile(r"\.\. deprecated::|\bDeprecationWarning\b")
+# Lines that may sit between a marker and the definition it annotates.
+_PREAMBLE_RE = re.compile(r"^\s*(//|#|\*|/\*|--|@|\[)")
+_MAX_PREAMBLE_LINES = 12
+_BODY_LINES = 6
+
+
+@dataclass
+class DeprecatedSymbols:
+    """Qualified names of deprecated symbols found in the repository."""
+
+    qualified_names: set[str] = field(default_factory=set)
+
+    def __bool__(self) -> bool:
+        return bool(self.qualified_names)
+
+    def __contains__(self, qualified_name: str) -> bool:
+        return qualified_name in self.qualified_names
+
+    def llm_str(self, limit: int = 50) -> str:
+        names = sorted(self.qualified_names)
+        listed = "\n".join(f"- `{name}`" for name in names[:limit])
+        more = f"\n- ... and {len(names) - limit} more" if len(names) > limit else ""
+        return (
+            "\n\n## Deprecated Code\n"
+            "These symbols are deprecated and kept only for compatibility:\n"
+            f"{listed}{more}\n"
+            f'Group them into one component named "{DEPRECATED_COMPONENT_NAME}" where the groups allow, and '
+            "leave them out of the overview description except to note which components still depend on them.\n"
+        )
+
+
+def _is_deprecated_definition(lines: list[str], start_line: int, end_line: int) -> bool:
+    """True when the 1-based definition at *start_line* carries a marker above it or in its first lines."""
+    index = start_line - 1
+    if not 0 <= index < len(lines):
+        return False
+    if _HEADER_MARKER_RE.search(lines[index]):
+        return True
+    above = index - 1
+    while above >= 0 and index - above <= _MAX_PREAMBLE_LINES and _PREAMBLE_RE.match(lines[above]):
+        if _HEADER_MARKER_RE.search(lines[above]):
+            return True
+        above -= 1
+    last = min(max(end_line, start_line), start_line + _BODY_LINES, len(lines))
+    return any(_BODY_MARKER_RE.search(line) for line in lines[index + 1 : last])
+
+
+class _ConfiguredDeprecations:
+    def __init__(self, project_config: ProjectConfig):
+        section = project_config.section("deprecated")
+        self.symbols = self._patterns(section.get("symbols", []))
+        self.paths = [p.strip("/") for p in self._patterns(section.get("paths", []))]
+
+    @staticmethod
+    def _patterns(value: str | list) -> list[str]:
+        values = [value] if isinstance(value, str) else value
+        return [str(v).strip() for v in values if str(v).strip()]
+
+    def matches(self, qualified_name: str, rel_path: str | None) -> bool:
+        if any(fnmatch(qualified_name, pattern) for pattern in self.symbols):
+            return True
+        return rel_path is not None and any(
+            fnmatch(rel_path, pattern) or rel_path.startswith(f"{pattern}/") for pattern in self.paths
+        )
+
+
+def find_deprecated_symbols(
+    repo_dir: Path, definitions: Iterable[tuple[str, str, int, int]], project_config: ProjectConfig
+) -> DeprecatedSymbols:
+    """Deprecated names among *definitions*: ``(qualified_name, file_path, start_line, end_line)`` tuples.
+
+    File paths may be absolute or repo-relative; each file is read at most once.
+    """
+    configured = _ConfiguredDeprecations(project_config)
+    sources: dict[str, list[str]] = {}
+    found: set[str] = set()
+    for qualified_name, file_path, start_line, end_line in definitions:
+        path = Path(file_path) if Path(file_path).is_absolute() else repo_dir / file_path
+        try:
+            rel_path: str | None = PurePosixPath(path.relative_to(repo_dir)).as_posix()
+        except ValueError:
+            rel_path = None
+        if configured.matches(qualified_name, rel_path):
+            found.add(qualified_name)
+            continue
+        key = str(path)
+        if key not in sources:
+            try:
+                sources[key] = path.read_text(encoding="utf-8", errors="replace").splitlines()
+            except OSError:
+                sources[key] = []
+        if _is_deprecated_definition(sources[key], start_line, end_line):
+            found.add(qualified_name)
+    if found:
+        logger.info(f"Found {len(found)} deprecated symbols")
+    return DeprecatedSymbols(qualified_names=found)
+
+
+def component_definitions(root_analysis: AnalysisInsights) -> list[tuple[str, str, int, int]]:
+    """Definitions of every root-level component method, for :func:`find_deprecated_symbols`."""
+    return [
+        (method.qualified_name, group.file_path, method.start_line, method.end_line)
+        for component in root_analysis.components
+        for group in component.file_methods
+        for method in group.methods
+    ]
+
+
+def mark_deprecated_components(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights], deprecated: DeprecatedSymbols
+) -> None:
+    """Set ``deprecated_symbols`` and ``deprecated`` on every component, at every level."""
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for component in analysis.components:
+            names = [m.qualified_name for group in component.file_methods for m in group.methods]
+            component.deprecated_symbols = sorted({n for n in names if n in deprecated})
+            component.deprecated = bool(names) and len(component.deprecated_symbols) == len(set(names))
+
+
+def collapse_deprecated_components(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]
+) -> Component | None:
+    """Merge the deprecated root-level components into one; returns it, or None when there are none.
+
+    Relations into and out of the merged components are re-pointed at the new component
+    (self-loops and duplicates dropped), so what still depends on deprecated code stays visible.
+    Their sub-analyses are dropped: the collapsed component is a single leaf.
+    """
+    merged = [c for c in root_analysis.components if c.deprecated]
+    if not merged:
+        return None
+    names = {c.name for c in merged}
+    ids = {c.component_id for c in merged}
+    dependents = sorted(
+        {r.src_name for r in root_analysis.components_relations if r.dst_name in names and r.src_name not in names}
+    )
+    description = f"Deprecated code kept for compatibility: {', '.join(sorted(names))}."
+    if dependents:
+        description += f" Still used by {', '.join(dependents)}."
+    collapsed = Component(
+        name=DEPRECATED_COMPONENT_NAME,
+        description=description,
+        key_entities=[entity for c in merged for entity in c.key_entities],
+        component_id=min(ids),
+        file_methods=[group for c in merged for group in c.file_methods],
+        source_cluster_ids=sorted({cid for c in merged for cid in c.source_cluster_ids}),
+        source_group_names=[name for c in merged for name in c.source_group_names],
+        deprecated=True,
+        deprecated_symbols=sorted({s for c in merged for s in c.deprecated_symbols}),
+    )
+    pending = list(ids)
+    while pending:
+        sub = sub_analyses.pop(pending.pop(), None)
+        if sub is not None:
+            pending.extend(c.component_id for c in sub.components)
+
+    relations: list[Relation] = []
+    seen: set[tuple[str, str, str]] = set()
+    for relation in root_analysis.components_relations:
+        if relation.src_name in names:
+            relation.src_name, relation.src_id = collapsed.name, collapsed.component_id
+        if relation.dst_name in names:
+            relation.dst_name, relation.dst_id = collapsed.name, collapsed.component_id
+        key = (relation.src_name, relation.dst_name, relation.relation)
+        if relation.src_name == relation.dst_name or key in seen:
+            continue
+        seen.add(key)
+        relations.append(relation)
+
+    position = root_analysis.components.index(merged[0])
+    kept = [c for c in root_analysis.components if not c.deprecated]
+    root_analysis.components = kept[:position] + [collapsed] + kept[position:]
+    root_analysis.components_relations = relations
+    logger.info(f"Collapsed {len(merged)} deprecated components into '{DEPRECATED_COMPONENT_NAME}'")
+    return collapsed
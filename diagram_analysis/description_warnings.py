@@ -0,0 +1,175 @@
+"""Flag component descriptions that contradict the deterministic relation graph.
+
+A cheap hallucination check run after descriptions are generated: a component
+described as having "no dependencies" while the graph shows outbound edges, or
+whose description says it calls/uses another component the graph never links
+to, is listed in ``description-warnings.json`` for the user to double-check.
+"""
+
+import logging
+import re
+from enum import StrEnum
+from pathlib import Path
+
+from pydantic import BaseModel, Field
+
+from agents.agent_responses import AnalysisInsights, Component, index_components_by_id
+from static_analyzer.cluster_relations import is_self_or_descendant
+from utils import generated_at
+
+logger = logging.getLogger(__name__)
+
+DESCRIPTION_WARNINGS_FILENAME = "description-warnings.json"
+
+_NO_OUTBOUND_RE = re.compile(
+    r"\b(has no (?:external |outgoing |outbound )?dependencies|does(?: not|n't) depend on (?:any|other)"
+    r"|self-contained|standalone|independent of (?:all |any )?other components)\b",
+    re.IGNORECASE,
+)
+_NO_INBOUND_RE = re.compile(
+    r"\b(not used by any|unused by other components|no (?:other )?components? (?:depends?|relies?) on it"
+    r"|has no dependents)\b",
+    re.IGNORECASE,
+)
+_DEPENDENCY_VERB = (
+    r"(?:depends on|relies on|calls|invokes|uses|delegates to|communicates with|talks to|"
+    r"sends [^.]{0,40}? to|receives [^.]{0,40}? from|queries|notifies|imports)"
+)
+# A dependency verb followed, within the same sentence, by the component name.
+_MENTION_TEMPLATE = r"\b{verb}\b[^.;]{{0,80}}?\b{name}\b"
+
+
+class WarningKind(StrEnum):
+    CLAIMS_NO_DEPENDENCIES = "claims_no_dependencies"
+    CLAIMS_NO_DEPENDENTS = "claims_no_dependents"
+    UNLINKED_DEPENDENCY = "unlinked_dependency"
+
+
+class DescriptionWarning(BaseModel):
+    component_id: str = Field(description="ID of the component whose description is suspect.")
+    component_name: str = Field(description="Name of the component whose description is suspect.")
+    kind: WarningKind = Field(description="Which contradiction was detected.")
+    message: str = Field(description="Human-readable explanation of the contradiction.")
+    excerpt: str = Field(description="Part of the description that triggered the warning.")
+
+
+class DescriptionWarningsReport(BaseModel):
+    version: int = Field(default=1, description="Schema version of the description warnings report.")
+    generated_at: str = Field(description="ISO timestamp of when the report was generated.")
+    warnings: list[DescriptionWarning] = Field(default_factory=list)
+
+
+def _relation_pairs(root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]) -> set[tuple[str, str]]:
+    pairs: set[tuple[str, str]] = set()
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for rel in analysis.components_relations:
+            if rel.src_id and rel.dst_id:
+                pairs.add((rel.src_id, rel.dst_id))
+    return pairs
+
+
+def _linked(a: str, b: str, pairs: set[tuple[str, str]]) -> bool:
+    """True when any relation connects the subtree of *a* with the subtree of *b*, in either direction."""
+    return any(
+        (is_self_or_descendant(src, a) and is_self_or_descendant(dst, b))
+        or (is_self_or_descendant(src, b) and is_self_or_descendant(dst, a))
+        for src, dst in pairs
+    )
+
+
+def _external_edges(component_id: str, pairs: set[tuple[str, str]]) -> tuple[list[str], list[str]]:
+    """Return (outbound targets, inbound sources) crossing the component's subtree boundary."""
+    outbound = sorted(
+        {
+            dst
+            for src, dst in pairs
+            if is_self_or_descendant(src, component_id) and not is_self_or_descendant(dst, component_id)
+        }
+    )
+    inbound = sorted(
+        {
+            src
+            for src, dst in pairs
+            if is_self_or_descendant(dst, component_id) and not is_self_or_descendant(src, component_id)
+        }
+    )
+    return outbound, inbound
+
+
+def _check_component(
+    component: Component, components: dict[str, Component], pairs: set[tuple[str, str]]
+) -> list[DescriptionWarning]:
+    cid = component.component_id
+    text = component.description or ""
+    outbound, inbound = _external_edges(cid, pairs)
+    warnings: list[DescriptionWarning] = []
+
+    match = _NO_OUTBOUND_RE.search(text)
+    if match and outbound:
+        names = ", ".join(components[t].name if t in components else t for t in outbound[:5])
+        warnings.append(
+            DescriptionWarning(
+                component_id=cid,
+                component_name=component.name,
+                kind=WarningKind.CLAIMS_NO_DEPENDENCIES,
+                message=f"Description claims no dependencies, but the graph shows outbound edges to: {names}",
+                excerpt=match.group(0),
+            )
+        )
+    match = _NO_INBOUND_RE.search(text)
+    if match and inbound:
+        names = ", ".join(components[s].name if s in components else s for s in inbound[:5])
+        warnings.append(
+            DescriptionWarning(
+                component_id=cid,
+                component_name=component.name,
+                kind=WarningKind.CLAIMS_NO_DEPENDENTS,
+                message=f"Description claims nothing depends on it, but the graph shows inbound edges from: {names}",
+                excerpt=match.group(0),
+            )
+        )
+
+    for other_id, other in components.items():
+        # Ancestors and descendants are containment, not dependencies.
+        if is_self_or_descendant(other_id, cid) or is_self_or_descendant(cid, other_id):
+            continue
+        pattern = _MENTION_TEMPLATE.format(verb=_DEPENDENCY_VERB, name=re.escape(other.name))
+        match = re.search(pattern, text, re.IGNORECASE)
+        if match and not _linked(cid, other_id, pairs):
+            warnings.append(
+                DescriptionWarning(
+                    component_id=cid,
+                    component_name=component.name,
+                    kind=WarningKind.UNLINKED_DEPENDENCY,
+                    message=f"Description names '{other.name}' as a dependency, but the graph has no edge between them",
+                    excerpt=match.group(0),
+                )
+            )
+    return warnings
+
+
+def find_description_warnings(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]
+) -> list[DescriptionWarning]:
+    """Check every component description in the tree against the relation graph."""
+    components = index_components_by_id(root_analysis, sub_analyses)
+    pairs = _relation_pairs(root_analysis, sub_analyses)
+    warnings: list[DescriptionWarning] = []
+    for cid in sorted(components):
+        warnings.extend(_check_component(components[cid], components, pairs))
+    return warnings
+
+
+def write_description_warnings(
+    output_dir: Path, root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]
+) -> Path:
+    """Write ``description-warnings.json`` (always, so a clean run clears stale warnings)."""
+    report = DescriptionWarningsReport(
+        generated_at=generated_at(),
+        warnings=find_description_warnings(root_analysis, sub_analyses),
+    )
+    path = output_dir / DESCRIPTION_WARNINGS_FILENAME
+    path.write_text(report.model_dump_json(indent=2), encoding="utf-8")
+    if report.warnings:
+        logger.warning(f"{len(report.warnings)} component description(s) contradict the graph; see {path}")
+    return path
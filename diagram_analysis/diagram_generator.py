@@ -7,7 +7,6 @@ from collections.abc import Iterable, Iterator
 from concurrent.futures import FIRST_COMPLETED, Future, ThreadPoolExecutor, wait
 from contextlib import nullcontext
 from dataclasses import dataclass, field
-from datetime import datetime, timezone
 from pathlib import Path
 from typing import Any
 
@@ -32,18 +31,28 @@ from agents.incremental_agent import (
 from agents.incremental_planning_agent import IncrementalPlanningAgent
 from agents.incremental_results import RecursiveScopeUpdateResult
 from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
-from agents.llm_config import initialize_llms
+from agents.llm_config import (
+    MONITORING_CALLBACK,
+    LLMConfigError,
+    get_current_agent_model,
+    initialize_llms,
+    provider_chain,
+)
 from agents.llm_errors import LLMAuthError
 from agents.meta_agent import MetaAgent
+from agents.model_capabilities import price_env_var
 from agents.planner_agent import component_is_separable, get_expandable_components
+from agents.prompts.prompt_templates import PromptTemplates
 from agents.relation_edges import index_relation_endpoints
 from agents.scope_ids import ROOT_SCOPE_ID
 from agents.content_hash import SourceCache, hash_repo_source_files, tree_hash_from_file_hashes
 from diagram_analysis.analysis_json import (
+    AnalysisCoverageSummary,
     FileCoverageReport,
     FileCoverageSummary,
     NotAnalyzedFile,
 )
+from diagram_analysis.api_specs import link_spec_operations
 from diagram_analysis.cluster_delta import (
     ChangedMembers,
     ClusterDelta,
@@ -57,28 +66,66 @@ from diagram_analysis.cluster_snapshot import (
     ClusterSnapshot,
     snapshot_from_static_analysis,
 )
-from diagram_analysis.exceptions import IncrementalCacheMissingError, ScopeContainmentError
+from diagram_analysis.codeowners import assign_component_owners, load_codeowners
+from diagram_analysis.cost_estimate import CostEstimate, estimate_run
+from diagram_analysis.deprecation import (
+    DeprecatedSymbols,
+    collapse_deprecated_components,
+    component_definitions,
+    find_deprecated_symbols,
+    mark_deprecated_components,
+)
+from diagram_analysis.description_warnings import write_description_warnings
+from diagram_analysis.external_boundaries import load_external_boundaries, mark_external_components
+from diagram_analysis.external_calls import assign_external_calls
+from diagram_analysis.exceptions import (
+    CostLimitExceededError,
+    IncrementalCacheMissingError,
+    ScopeContainmentError,
+    UnpricedModelError,
+)
 from diagram_analysis.file_coverage import FileCoverage
 from diagram_analysis.file_index import build_files_index, refresh_method_spans_from_cfg
+from diagram_analysis.grouping import Grouping
+from diagram_analysis.http_endpoints import assign_http_routes
 from diagram_analysis.io_utils import load_analysis_metadata, normalize_repo_path, save_analysis, write_fingerprint
+from diagram_analysis.stable_order import sort_analysis_tree, sort_static_analysis
+from diagram_analysis.structural_coverage import write_test_coverage_report
+from diagram_analysis.subtree import Subtree, SubtreeView, apply_subtree
+from diagram_analysis.test_map import assign_component_tests
 from health.config import initialize_health_dir, load_health_config
+from health.fitness import write_fitness_report
 from health.runner import run_health_checks
 from monitoring import StreamingStatsWriter
 from monitoring.mixin import MonitoringMixin
 from monitoring.paths import get_monitoring_run_dir
+from monitoring.progress import Phase, get_progress
+from project_config import ProjectConfig, load_project_config
 from repo_utils.change_detector import ChangeSet
 from repo_utils.ignore import RepoIgnoreManager
+from repo_utils.path_utils import to_relative_path
 from static_analyzer import StaticAnalyzer, get_static_analysis
 from static_analyzer.analysis_cache import StaticAnalysisCache
 from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.api_surface import apply_api_only
 from static_analyzer.cluster_relations import build_global_relations, is_self_or_descendant
+from static_analyzer.complexity import compute_function_complexity, write_metrics_markdown
 from static_analyzer.constants import Language
+from static_analyzer.framework_edges import Framework
 from static_analyzer.graph import ClusterResult
 from static_analyzer.scanner import ProjectScanner
+from static_analyzer.analysis_coverage import AnalysisCoverage, compute_analysis_coverage
+from static_analyzer.feature_flags import apply_flag_settings, load_flag_patterns, tag_flag_guarded_edges
+from static_analyzer.select_query import SelectQuery, apply_select_query
+from static_analyzer.symbol_kinds import assign_method_kinds, load_kind_map
 from telemetry.events import track_analysis
+from utils import generated_at
 
 logger = logging.getLogger(__name__)
 
+# ``Component.not_described`` of the components ``--max-llm-calls`` left unexpanded.
+CALL_BUDGET_REACHED = "call budget reached"
+
 
 def _component_depth(component_id: str | None) -> int:
     """Return the absolute diagram depth for a hierarchical component id."""
@@ -475,7 +522,7 @@ def _incremental_changed_component_ids(
 
 def _preserve_unchanged_global_relations(
     rebuilt_relations: list[Relation],
-    baseline_by_pair: dict[tuple[str, str], Relation],
+    baseline_by_pair: dict[tuple[str, ...], Relation],
     changed_component_ids: set[str],
     live_ids: set[str],
 ) -> list[Relation]:
@@ -487,19 +534,21 @@ def _preserve_unchanged_global_relations(
     changed component keep the fresh rebuild. A baseline relation between two unchanged,
     still-live components that the rebuild dropped is restored, and a spurious rebuilt edge
     between two unchanged components is discarded — so both relabel and structural drift
-    against untouched components is eliminated. Relations are keyed by ``(src_id, dst_id)``,
-    the stable component identity; the rebuild always populates both ids.
+    against untouched components is eliminated. Relations are keyed by ``Relation.pair_key``:
+    ``(src_id, dst_id)``, the stable component identity, plus a marker for interface
+    satisfaction; the rebuild always populates both ids.
     """
 
     def touches_change(src_id: str, dst_id: str) -> bool:
         return src_id in changed_component_ids or dst_id in changed_component_ids
 
     kept = [rel for rel in rebuilt_relations if touches_change(rel.src_id, rel.dst_id)]
-    for (src_id, dst_id), relation in baseline_by_pair.items():
+    for relation in baseline_by_pair.values():
+        src_id, dst_id = relation.src_id, relation.dst_id
         if touches_change(src_id, dst_id) or src_id not in live_ids or dst_id not in live_ids:
             continue
         kept.append(relation)
-    return sorted(kept, key=lambda rel: (rel.src_id, rel.dst_id))
+    return sorted(kept, key=lambda rel: (rel.src_id, rel.dst_id, rel.implements))
 
 
 class DiagramGenerator:
@@ -527,6 +576,57 @@ class DiagramGenerator:
         self.log_path = log_path
         self.monitoring_enabled = monitoring_enabled
         self.force_full_analysis = False  # Set to True to skip incremental updates
+        # ``--framework`` decorator-edge passes forwarded to static analysis.
+        self.frameworks: tuple[Framework, ...] = ()
+        # ``--main-package``: Go ``main`` package whose import closure bounds the Go analysis.
+        self.main_package: str | None = None
+        # ``--resolve-interface-dispatch``: link Go interface method calls to the concrete methods.
+        self.resolve_interface_dispatch: bool = False
+        # ``--concurrency``: LSP symbol requests in flight at once (None = the CPU count).
+        self.lsp_concurrency: int | None = None
+        # ``--lsp-timeout``: seconds one LSP request may take (None = the adapter's default).
+        self.lsp_timeout: int | None = None
+        # ``--include`` / ``--exclude``: globs narrowing the analysed files.
+        self.include: tuple[str, ...] = ()
+        self.exclude: tuple[str, ...] = ()
+        # ``--dump-lsp``: directory receiving the raw LSP responses of a fresh static-analysis pass.
+        self.dump_lsp_dir: Path | None = None
+        # ``--grouping``: top-level components from LLM clustering, or one per package or directory.
+        self.grouping = Grouping.SEMANTIC
+        # ``--prompt-dir``: system and component prompts replacing the built-in ones.
+        self.prompt_templates = PromptTemplates()
+        # ``--hide-deprecated``: collapse fully deprecated components into one "Deprecated" component.
+        self.hide_deprecated = False
+        # ``--use-codeowners``: annotate components with the CODEOWNERS owners of their files.
+        self.use_codeowners = False
+        # ``--path``/``--package``: the subtree the components are generated from; what it calls
+        # outside becomes external dependency components.
+        self.subtree: Subtree | None = None
+        # ``--select``: query narrowing the call graph the components are generated from.
+        self.select: SelectQuery | None = None
+        # ``--flag NAME=on|off``: drop the calls that only happen with the flag in the other state.
+        self.flags: dict[str, bool] = {}
+        # ``--deterministic``: sort the call graphs and the saved analysis by key, and analyse
+        # components one at a time so prompts and cache hits replay in order.
+        self.deterministic = False
+        # ``--test-coverage-graph``: write the structural test-coverage report on every save.
+        self.test_coverage_graph = False
+        # ``--test-map``: list the test functions that exercise each component.
+        self.test_map = False
+        # ``--show-external``: count each component's calls into packages outside the project.
+        self.show_external = False
+        # ``--api-only``: generate components from exported symbols only.
+        self.api_only = False
+        # ``--max-llm-calls``: model requests after which no further component is expanded (None = no limit).
+        self.max_llm_calls: int | None = None
+        # ``--max-cost``: estimated USD price above which the run stops before its first LLM request.
+        self.max_cost: float | None = None
+        # Process-wide request count when this generator was built, so the budget counts this run only.
+        self._llm_calls_at_start = MONITORING_CALLBACK.stats.llm_calls
+        # ``--llm-edge-kinds``: edge kinds the agents see in cluster strings (None = default).
+        self.llm_edge_kinds: tuple[str, ...] | None = None
+        # Component ID -> ``provider/model`` that generated it, kept when an ``--llm-fallback`` chain is set.
+        self.llm_providers: dict[str, str] = {}
         # Source-tree changeset for the iterative path. When set, the cluster
         # delta drops drift qnames whose file is outside the diff AND outside
         # the prior analysis (see ``compute_cluster_delta``). ``None`` runs
@@ -545,7 +645,7 @@ class DiagramGenerator:
         # so the save-time global relation rebuild can carry an edge between two unchanged
         # components over verbatim instead of re-deriving (and re-labelling) it.
         # ``None`` => full analysis: rebuild every relation. Keyed by ``(src_id, dst_id)``.
-        self._baseline_global_relations: dict[tuple[str, str], Relation] | None = None
+        self._baseline_global_relations: dict[tuple[str, ...], Relation] | None = None
         self._baseline_component_ids: set[str] = set()
         # Per-component baseline member-key set, captured with the membership baseline. A
         # component whose live member keys differ from these gained or lost a member (without
@@ -564,12 +664,21 @@ class DiagramGenerator:
 
         self.details_agent: DetailsAgent | None = None
         self.static_analysis: StaticAnalysisResults | None = None  # Cache static analysis for reuse
+        # The whole-repository results behind a ``--select``/``--flag`` view; what the static-analysis cache keeps.
+        self._unselected_static_analysis: StaticAnalysisResults | None = None
+        # The ``--path``/``--package`` cut of the static analysis, and the files of its dependency components.
+        self._subtree_view: SubtreeView | None = None
+        self._dependency_files: set[str] = set()
+        # Raw static analysis ``estimate_cost`` already ran, so ``pre_analysis`` doesn't run it twice.
+        self._estimated_static_analysis: StaticAnalysisResults | None = None
         self.abstraction_agent: AbstractionAgent | None = None
         self.meta_agent: MetaAgent | None = None
         self.incremental_planning_agent: IncrementalPlanningAgent | None = None
         self.incremental_agent: IncrementalAgent | None = None
         self.meta_context: MetaAnalysisInsights | None = None
         self.file_coverage_data: dict | None = None
+        # Call-site and file resolution of the whole-repository static analysis; None until pre_analysis.
+        self.analysis_coverage: AnalysisCoverage | None = None
 
         self._monitoring_agents: dict[str, MonitoringMixin] = {}
         self.stats_writer: StreamingStatsWriter | None = None
@@ -612,6 +721,7 @@ class DiagramGenerator:
             assert self.details_agent is not None
 
             analysis, _ = self.details_agent.run(component)
+            self._record_llm_provider(analysis, self.details_agent.served_by())
 
             # Track whether parent had clusters for expansion decision
             parent_had_clusters = bool(component.source_cluster_ids)
@@ -631,6 +741,29 @@ class DiagramGenerator:
             logging.error(f"Error processing component {component.name}: {e}")
             return None, None, []
 
+    def _call_budget_reached(self) -> bool:
+        if self.max_llm_calls is None:
+            return False
+        return MONITORING_CALLBACK.stats.llm_calls - self._llm_calls_at_start >= self.max_llm_calls
+
+    def _process_within_budget(
+        self, component: Component
+    ) -> tuple[str, AnalysisInsights, list[Component]] | tuple[None, None, list]:
+        """``_process_component``, unless ``--max-llm-calls`` is used up by the time the component starts.
+
+        Checked when the worker picks the component up rather than when it is queued, so
+        components already running finish and the run may overshoot the budget by their calls.
+        """
+        if self._call_budget_reached():
+            component.not_described = CALL_BUDGET_REACHED
+            return None, None, []
+        return self._process_component(component)
+
+    def _record_llm_provider(self, analysis: AnalysisInsights, model_ref: str) -> None:
+        """Remember which provider produced *analysis*'s components, when failover makes it ambiguous."""
+        if len(provider_chain()) > 1:
+            self.llm_providers.update({c.component_id: model_ref for c in analysis.components if c.component_id})
+
     def _run_health_report(self, static_analysis: StaticAnalysisResults) -> None:
         """Run health checks and write the report to the output directory."""
         health_config_dir = Path(self.output_dir) / "health"
@@ -668,14 +801,14 @@ class DiagramGenerator:
         Idempotent. Mutates in place. Empty components are kept (relations may
         reference them); downstream renderers handle zero-method components.
         """
-        ignore_manager = RepoIgnoreManager(self.repo_location)
+        ignore_manager = RepoIgnoreManager(self.repo_location, self.include, self.exclude)
         ignore_manager.strip_ignored(analysis)
         for sub in (sub_analyses or {}).values():
             ignore_manager.strip_ignored(sub)
 
     def _build_file_coverage(self, scanner: ProjectScanner, static_analysis: StaticAnalysisResults) -> dict:
         """Build file coverage data comparing all text files against analyzed files."""
-        ignore_manager = RepoIgnoreManager(self.repo_location)
+        ignore_manager = RepoIgnoreManager(self.repo_location, self.include, self.exclude)
         coverage = FileCoverage(self.repo_location, ignore_manager)
 
         # Convert to Path objects for set operations
@@ -691,7 +824,7 @@ class DiagramGenerator:
 
         report = FileCoverageReport(
             version=1,
-            generated_at=datetime.now(timezone.utc).isoformat(),
+            generated_at=generated_at(),
             analyzed_files=self.file_coverage_data["analyzed_files"],
             not_analyzed_files=[NotAnalyzedFile(**entry) for entry in self.file_coverage_data["not_analyzed_files"]],
             summary=FileCoverageSummary(**self.file_coverage_data["summary"]),
@@ -727,6 +860,7 @@ class DiagramGenerator:
         if disable_reuse:
             logger.info("CODEBOARDING_DISABLE_CACHE_REUSE set; skipping static analysis cache")
         self._static_analyzer.changed_files = self._changed_files_for_static_analysis()
+        self._static_analyzer.frameworks = self.frameworks
         result = self._static_analyzer.analyze(
             skip_cache=skip_cache,
             source_sha=self.source_sha,
@@ -743,14 +877,29 @@ class DiagramGenerator:
             logger.info("Force full analysis: skipping static analysis cache")
         if disable_reuse:
             logger.info("CODEBOARDING_DISABLE_CACHE_REUSE set; skipping static analysis cache")
+        if self.dump_lsp_dir is not None:
+            logger.info(f"Dumping raw LSP responses to {self.dump_lsp_dir}; skipping static analysis cache")
         return get_static_analysis(
             self.repo_location,
             skip_cache=skip_cache,
             source_sha=self.source_sha,
             cache_dir=self.output_dir,
             changed_files=self._changed_files_for_static_analysis(),
+            frameworks=self.frameworks,
+            main_package=self.main_package,
+            dump_lsp_dir=self.dump_lsp_dir,
+            resolve_interface_dispatch=self.resolve_interface_dispatch,
+            lsp_concurrency=self.lsp_concurrency,
+            lsp_timeout=self.lsp_timeout,
+            include=self.include,
+            exclude=self.exclude,
         )
 
+    def _get_static_from_estimate(self) -> StaticAnalysisResults:
+        """The static analysis ``estimate_cost`` already ran for ``--max-cost``."""
+        assert self._estimated_static_analysis is not None
+        return self._estimated_static_analysis
+
     def _seed_incremental_cluster_cache(self, cluster_results: dict[str, ClusterResult]) -> None:
         """Write post-delta ``cluster_results`` into each language CFG's ``_cluster_cache``.
 
@@ -775,7 +924,9 @@ class DiagramGenerator:
             return
         if self.static_analysis is None:
             return
-        StaticAnalysisCache(self.output_dir, self.repo_location).save(self.static_analysis, source_sha=self.source_sha)
+        StaticAnalysisCache(self.output_dir, self.repo_location).save(
+            self._unselected_static_analysis or self.static_analysis, source_sha=self.source_sha
+        )
 
     def _source_tree_fingerprint_map(self) -> dict[str, str]:
         """The whole-tree fingerprint, fingerprinting on first use if pre_analysis didn't."""
@@ -798,6 +949,18 @@ class DiagramGenerator:
         )
         self._monitoring_agents["MetaAgent"] = self.meta_agent
 
+    def _deprecated_symbols(
+        self, static_analysis: StaticAnalysisResults, project_config: ProjectConfig
+    ) -> DeprecatedSymbols | None:
+        """Deprecated symbols for the abstraction prompt; only looked up with ``--hide-deprecated``."""
+        if not self.hide_deprecated:
+            return None
+        definitions = (
+            (node.fully_qualified_name, node.file_path, node.line_start, node.line_end)
+            for node in static_analysis.iter_reference_nodes()
+        )
+        return find_deprecated_symbols(self.repo_location, definitions, project_config)
+
     def _initialize_agents(
         self,
         static_analysis: StaticAnalysisResults,
@@ -806,6 +969,7 @@ class DiagramGenerator:
         parsing_llm: BaseChatModel,
     ) -> None:
         """Initialize agents that depend on static analysis and project metadata."""
+        project_config = load_project_config(self.repo_location)
         self.details_agent = DetailsAgent(
             repo_dir=self.repo_location,
             project_name=self.repo_name,
@@ -814,6 +978,7 @@ class DiagramGenerator:
             agent_llm=agent_llm,
             parsing_llm=parsing_llm,
             run_id=self.run_id,
+            prompt_templates=self.prompt_templates,
         )
         self.abstraction_agent = AbstractionAgent(
             repo_dir=self.repo_location,
@@ -822,6 +987,12 @@ class DiagramGenerator:
             meta_context=meta_context,
             agent_llm=agent_llm,
             parsing_llm=parsing_llm,
+            external_boundaries=load_external_boundaries(
+                self.repo_location, project_config, static_analysis.get_all_source_files()
+            ),
+            deprecated_symbols=self._deprecated_symbols(static_analysis, project_config),
+            grouping=self.grouping,
+            prompt_templates=self.prompt_templates,
         )
         self.incremental_planning_agent = IncrementalPlanningAgent(
             repo_dir=self.repo_location,
@@ -831,6 +1002,7 @@ class DiagramGenerator:
             agent_llm=agent_llm,
             parsing_llm=parsing_llm,
             changes=self.changes,
+            prompt_templates=self.prompt_templates,
         )
         self.incremental_agent = IncrementalAgent(
             repo_dir=self.repo_location,
@@ -840,7 +1012,10 @@ class DiagramGenerator:
             agent_llm=agent_llm,
             parsing_llm=parsing_llm,
             changes=self.changes,
+            prompt_templates=self.prompt_templates,
         )
+        for agent in (self.details_agent, self.abstraction_agent, self.incremental_agent):
+            agent.llm_edge_kinds = self.llm_edge_kinds
         self._monitoring_agents.update(
             {
                 "DetailsAgent": self.details_agent,
@@ -850,6 +1025,62 @@ class DiagramGenerator:
             }
         )
 
+    def _narrow_static_analysis(self, static_analysis: StaticAnalysisResults) -> StaticAnalysisResults:
+        """Apply ``--path``/``--package``, ``--select``, ``--flag`` and ``--api-only`` to whole-repository results."""
+        if self.subtree is not None:
+            self._unselected_static_analysis = static_analysis
+            self._subtree_view = apply_subtree(static_analysis, self.subtree, self.repo_location)
+            static_analysis = self._subtree_view.static_analysis
+        if self.select is not None:
+            self._unselected_static_analysis = self._unselected_static_analysis or static_analysis
+            kind_map = load_kind_map(load_project_config(self.repo_location))
+            static_analysis = apply_select_query(static_analysis, self.select, self.repo_location, kind_map)
+        if self.flags:
+            flag_patterns = load_flag_patterns(load_project_config(self.repo_location))
+            if not flag_patterns:
+                logger.warning("--flag has no effect: no [feature_flags] patterns in .codeboarding/config.toml")
+            else:
+                self._unselected_static_analysis = self._unselected_static_analysis or static_analysis
+                static_analysis = apply_flag_settings(static_analysis, self.flags, flag_patterns)
+        if self.api_only:
+            self._unselected_static_analysis = self._unselected_static_analysis or static_analysis
+            static_analysis = apply_api_only(static_analysis)
+        return static_analysis
+
+    def estimate_cost(self) -> CostEstimate:
+        """Price a full run from its static analysis alone; no LLM client is created."""
+        selected = get_current_agent_model()
+        if selected is None:
+            raise LLMConfigError("No LLM provider configured; the estimate needs the model it would price")
+        provider, model_name = selected
+        return estimate_run(
+            self.run_static_analysis(), self.depth_level, provider, model_name, self.grouping, self.max_llm_calls
+        )
+
+    def run_static_analysis(self) -> StaticAnalysisResults:
+        """The static analysis of a full run, narrowed by ``--select`` and ``--flag``; no LLM client is created.
+
+        Run once: a later :meth:`pre_analysis` reuses it.
+        """
+        if self._estimated_static_analysis is None:
+            if self.source_sha is None:
+                self.source_sha = self._source_tree_hash() or None
+            if self._static_analyzer is not None:
+                self._estimated_static_analysis = self._get_static_with_injected_analyzer()
+            else:
+                self._estimated_static_analysis = self._get_static_with_new_analyzer()
+        return self._narrow_static_analysis(self._estimated_static_analysis)
+
+    def _enforce_max_cost(self) -> None:
+        if self.max_cost is None:
+            return
+        estimate = self.estimate_cost()
+        logger.info(estimate.summary())
+        if estimate.cost is None:
+            raise UnpricedModelError(estimate.model, price_env_var(estimate.provider, estimate.model))
+        if estimate.cost > self.max_cost:
+            raise CostLimitExceededError(estimate.cost, self.max_cost)
+
     def pre_analysis(self):
         analysis_start_time = time.time()
 
@@ -869,7 +1100,9 @@ class DiagramGenerator:
 
         # Decide how to obtain static analysis results, then run it in parallel
         # with the meta-context computation so neither blocks the other.
-        if self._static_analyzer is not None:
+        if self._estimated_static_analysis is not None:
+            static_callable = self._get_static_from_estimate
+        elif self._static_analyzer is not None:
             logger.info("Using injected StaticAnalyzer (clients already running)")
             static_callable = self._get_static_with_injected_analyzer
         else:
@@ -883,6 +1116,10 @@ class DiagramGenerator:
             static_analysis = static_future.result()
             meta_context = meta_future.result()
 
+        static_analysis = self._narrow_static_analysis(static_analysis)
+        if self.deterministic:
+            # Before the agents cluster it: LSP responses arrive in a different order each run.
+            sort_static_analysis(static_analysis)
         self.static_analysis = static_analysis
         self.meta_context = meta_context
 
@@ -899,6 +1136,8 @@ class DiagramGenerator:
 
         # Build file coverage data from scanner's all_text_files and analyzed files
         self.file_coverage_data = self._build_file_coverage(scanner, static_analysis)
+        self.analysis_coverage = compute_analysis_coverage(self._unselected_static_analysis or static_analysis)
+        logger.info(self.analysis_coverage.summary_line())
 
         self._run_health_report(static_analysis)
 
@@ -929,21 +1168,26 @@ class DiagramGenerator:
         root_components: list[Component],
     ) -> tuple[list[Component], dict[str, AnalysisInsights]]:
         """Generate subcomponents using absolute component depth and a frontier queue."""
-        max_workers = min(os.cpu_count() or 4, 8)
+        max_workers = 1 if self.deterministic else min(os.cpu_count() or 4, 8)
 
         expanded_components: list[Component] = []
         sub_analyses: dict[str, AnalysisInsights] = {}
 
         # Group stats to avoid cluttering the local variable scope
-        stats = {"submitted": 0, "completed": 0, "saves": 0, "errors": 0}
+        stats = {"submitted": 0, "completed": 0, "saves": 0, "errors": 0, "not_described": 0}
 
-        with ThreadPoolExecutor(max_workers=max_workers) as executor:
+        progress = get_progress()
+        with (
+            progress.phase(Phase.LLM, total=0, unit="component"),
+            ThreadPoolExecutor(max_workers=max_workers) as executor,
+        ):
             future_to_task: dict[Future, tuple[Component, int]] = {}
 
             def submit_component(comp: Component, lvl: int):
-                future = executor.submit(self._process_component, comp)
+                future = executor.submit(self._process_within_budget, comp)
                 future_to_task[future] = (comp, lvl)
                 stats["submitted"] += 1
+                progress.add_total(Phase.LLM, 1, unit="component")
                 logger.debug("Submitted component='%s' at level=%d", comp.name, lvl)
 
             # 1. Initial Seeding
@@ -961,10 +1205,13 @@ class DiagramGenerator:
                 for future in completed_futures:
                     component, level = future_to_task.pop(future)
                     stats["completed"] += 1
+                    progress.advance(Phase.LLM, unit="component")
 
                     try:
                         comp_name, sub_analysis, new_components = future.result()
 
+                        if component.not_described == CALL_BUDGET_REACHED:
+                            stats["not_described"] += 1
                         if comp_name and sub_analysis:
                             sub_analyses[comp_name] = sub_analysis
                             expanded_components.append(component)
@@ -1004,6 +1251,11 @@ class DiagramGenerator:
                 )
 
             logger.info("Subcomponent generation complete: %s", stats)
+            if stats["not_described"]:
+                logger.warning(
+                    f"LLM call budget of {self.max_llm_calls} reached; "
+                    f"{stats['not_described']} components were not expanded"
+                )
 
         return expanded_components, sub_analyses
 
@@ -1015,6 +1267,7 @@ class DiagramGenerator:
         Components are analyzed in parallel as soon as their parents complete.
         """
         if self.details_agent is None or self.abstraction_agent is None:
+            self._enforce_max_cost()
             self.pre_analysis()
 
         # Start monitoring (tracks start time)
@@ -1025,19 +1278,36 @@ class DiagramGenerator:
 
             assert self.abstraction_agent is not None
 
-            analysis, cluster_results = self.abstraction_agent.run()
-            # Get the initial components to analyze (deterministic, no LLM). The
-            # separability gate keeps cohesive top-level components as leaves.
-            root_components = get_expandable_components(analysis, separable=self._component_separable)
-            logger.info(f"Found {len(root_components)} components to analyze at level 1")
+            with get_progress().phase(Phase.LLM):
+                analysis, cluster_results = self.abstraction_agent.run()
+                self._record_llm_provider(analysis, self.abstraction_agent.served_by())
+                # Get the initial components to analyze (deterministic, no LLM). The
+                # separability gate keeps cohesive top-level components as leaves, and
+                # a package or directory grouping is not expanded at all.
+                root_components = (
+                    get_expandable_components(analysis, separable=self._component_separable)
+                    if self.grouping == Grouping.SEMANTIC
+                    else []
+                )
+                logger.info(f"Found {len(root_components)} components to analyze at level 1")
 
-            # Process components using a frontier queue: submit children as soon as parent finishes.
-            expanded_components, sub_analyses = self._generate_subcomponents(analysis, root_components)
+                # Process components using a frontier queue: submit children as soon as parent finishes.
+                expanded_components, sub_analyses = self._generate_subcomponents(analysis, root_components)
+            if self._subtree_view is not None:
+                self._add_dependency_components(analysis)
 
             analysis_path = self.finalize_and_save(analysis, sub_analyses)
             logger.info(f"Analysis complete. Written unified analysis to {analysis_path}")
             return analysis_path
 
+    def _add_dependency_components(self, analysis: AnalysisInsights) -> None:
+        """Append the packages the ``--path``/``--package`` subtree calls as external leaves."""
+        assert self._subtree_view is not None and self.abstraction_agent is not None
+        groups = self._subtree_view.dependencies(self.repo_location)
+        calls = [edge for edges in self._subtree_view.outside_calls.values() for edge in edges]
+        added = self.abstraction_agent.add_dependency_components(analysis, groups, calls, str(self.subtree))
+        self._dependency_files = {path for component in added for path in component.file_paths()}
+
     def rebuild_global_relations(
         self,
         root_analysis: AnalysisInsights,
@@ -1052,6 +1322,8 @@ class DiagramGenerator:
         if not self.static_analysis:
             return []
         cfg_graphs = {str(lang): self.static_analysis.get_cfg(lang) for lang in self.static_analysis.get_languages()}
+        if self._subtree_view is not None:
+            cfg_graphs = self._subtree_view.with_outside_calls(cfg_graphs)
         global_relations = build_global_relations(root_analysis, sub_analyses, cfg_graphs)
         if self._baseline_global_relations is not None:
             # Incremental: the wholesale rebuild would relabel edges between two untouched
@@ -1106,6 +1378,10 @@ class DiagramGenerator:
         incremental-only cluster baseline, seeded *after* the save so a crash in
         between re-does the delta (idempotent) rather than silently skipping it.
 
+        ``description-warnings.json``, ``fitness.json`` and ``metrics.md`` are
+        rewritten on every save since any flow may change descriptions, relations
+        and membership; ``metrics.md`` only when static analysis is loaded.
+
         ``persist_side_artifacts`` writes ``file_coverage.json``, the static-
         analysis cache, and the ``fingerprint.json`` sidecar. The partial flow
         sets it False: it regenerates one component, not the source state, so
@@ -1113,6 +1389,27 @@ class DiagramGenerator:
         the next incremental) and desync the sidecar from ``source_tree_hash``.
         """
         self.finalize_for_save(root_analysis, sub_analyses)
+        project_config = load_project_config(self.repo_location)
+        # Sub-components only split their parent's files, so the root level covers every marker directory.
+        root_files = [path for component in root_analysis.components for path in component.file_paths()]
+        boundaries = load_external_boundaries(self.repo_location, project_config, root_files)
+        boundaries.patterns.extend(sorted(self._dependency_files))
+        mark_external_components(root_analysis, sub_analyses, boundaries)
+        link_spec_operations(self.repo_location, root_analysis, sub_analyses, project_config)
+        deprecated = find_deprecated_symbols(self.repo_location, component_definitions(root_analysis), project_config)
+        mark_deprecated_components(root_analysis, sub_analyses, deprecated)
+        if self.hide_deprecated:
+            collapse_deprecated_components(root_analysis, sub_analyses)
+        codeowners = load_codeowners(self.repo_location) if self.use_codeowners else None
+        assign_component_owners(self.repo_location, root_analysis, sub_analyses, codeowners)
+        assign_component_tests(self.repo_location, root_analysis, sub_analyses, self.test_map)
+        assign_external_calls(self.repo_location, root_analysis, sub_analyses, self.show_external)
+        routed = self.static_analysis if Framework.HTTP in self.frameworks else None
+        assign_http_routes(root_analysis, sub_analyses, routed)
+        tag_flag_guarded_edges(self.repo_location, root_analysis, sub_analyses, load_flag_patterns(project_config))
+        assign_method_kinds([root_analysis, *sub_analyses.values()], load_kind_map(project_config))
+        if self.deterministic:
+            sort_analysis_tree(root_analysis, sub_analyses)
         if persist_side_artifacts:
             source_tree_hash = self._source_tree_hash()
         else:
@@ -1157,9 +1454,25 @@ class DiagramGenerator:
             expandable_component_ids=expandable_component_ids,
             sub_expandable_ids=sub_expandable_ids,
             depth_cap=self.depth_level,
+            llm_providers=self._merged_llm_providers(root_analysis, sub_analyses),
+            analysis_coverage=self._build_analysis_coverage_summary(),
         ).resolve()
         if seed_delta is not None:
             self._seed_incremental_cluster_cache(seed_delta)
+        write_description_warnings(Path(self.output_dir), root_analysis, sub_analyses)
+        write_fitness_report(Path(self.output_dir), root_analysis, sub_analyses, project_config)
+        if self.static_analysis is not None:
+            complexities = compute_function_complexity(self.static_analysis, self.repo_location)
+            write_metrics_markdown(Path(self.output_dir), root_analysis, sub_analyses, complexities)
+            if self.test_coverage_graph:
+                write_test_coverage_report(
+                    Path(self.output_dir),
+                    self.repo_location,
+                    root_analysis,
+                    sub_analyses,
+                    self.static_analysis.available_cfgs().values(),
+                    self.static_analysis.get_all_source_files(),
+                )
         if persist_side_artifacts:
             self._write_file_coverage()
             self._persist_static_analysis_artifact()
@@ -1168,6 +1481,19 @@ class DiagramGenerator:
             write_fingerprint(Path(self.output_dir), self._source_tree_fingerprint_map())
         return analysis_path
 
+    def _merged_llm_providers(
+        self, root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]
+    ) -> dict[str, str] | None:
+        """This run's provider per component over the on-disk record, limited to live components.
+
+        ``None`` (nothing generated under a fallback chain) keeps the on-disk value as is.
+        """
+        if not self.llm_providers:
+            return None
+        prior = (load_analysis_metadata(Path(self.output_dir)) or {}).get("llm_providers") or {}
+        live_ids = index_components_by_id(root_analysis, sub_analyses)
+        return {cid: ref for cid, ref in {**prior, **self.llm_providers}.items() if cid in live_ids}
+
     def _build_file_coverage_summary(self) -> FileCoverageSummary | None:
         if not self.file_coverage_data:
             return None
@@ -1179,6 +1505,20 @@ class DiagramGenerator:
             not_analyzed_by_reason=summary["not_analyzed_by_reason"],
         )
 
+    def _build_analysis_coverage_summary(self) -> AnalysisCoverageSummary | None:
+        coverage = self.analysis_coverage
+        if coverage is None:
+            return None
+        return AnalysisCoverageSummary(
+            coverage=coverage.percent,
+            resolved_call_sites=coverage.resolved_call_sites,
+            unresolved_call_sites=coverage.unresolved_call_sites,
+            parsed_files=coverage.parsed_files,
+            source_files=coverage.source_files,
+            timed_out_files=[to_relative_path(f, self.repo_location) for f in coverage.timed_out_files],
+            degraded_files=[to_relative_path(f, self.repo_location) for f in coverage.degraded_files],
+        )
+
     def _rescope_child_analyses(
         self,
         scope: AnalysisInsights,
@@ -1306,7 +1646,7 @@ class DiagramGenerator:
             if component.component_id
         }
         self._baseline_global_relations = {
-            (relation.src_id, relation.dst_id): relation.model_copy(deep=True)
+            relation.pair_key(): relation.model_copy(deep=True)
             for relation in root_analysis.components_relations
             if relation.src_id and relation.dst_id
         }
@@ -66,3 +66,26 @@ class ScopeContainmentError(RuntimeError):
     def __init__(self, violations: list[str]):
         super().__init__("Child scopes own methods outside their parent component: " + "; ".join(violations))
         self.violations = violations
+
+
+class CostLimitExceededError(RuntimeError):
+    """Raised before the first LLM request when the estimated price of a run is above ``--max-cost``."""
+
+    def __init__(self, estimated_cost: float, max_cost: float):
+        super().__init__(
+            f"Estimated cost ${estimated_cost:.2f} exceeds --max-cost ${max_cost:.2f}; "
+            "no LLM request was made (run with --estimate for the breakdown)"
+        )
+        self.estimated_cost = estimated_cost
+        self.max_cost = max_cost
+
+
+class UnpricedModelError(RuntimeError):
+    """Raised before the first LLM request when ``--max-cost`` is set but no catalog prices the model."""
+
+    def __init__(self, model: str, price_env_var: str):
+        super().__init__(
+            f"--max-cost needs a price for {model}, which no catalog lists; "
+            f"set {price_env_var}='input,output' in USD per 1M tokens"
+        )
+        self.model = model
@@ -0,0 +1,108 @@
+"""External-boundary annotations for API client packages.
+
+Calls into a generated HTTP/gRPC client leave the system, but statically they
+look like any other internal edge. A repository marks such packages either in
+``.codeboarding/config.toml``::
+
+    [boundaries]
+    external = ["gen/clients/**", "internal/billingclient"]
+
+or by dropping an empty ``.codeboarding-external`` file into the package
+directory (handy for generated code, whose sources get overwritten). A
+component whose files all sit behind a boundary is flagged ``external`` so the
+renderers draw it, and the calls into it, as crossing the system boundary.
+"""
+
+import logging
+from collections.abc import Iterable
+from dataclasses import dataclass, field
+from fnmatch import fnmatch
+from pathlib import Path, PurePosixPath
+
+from agents.agent_responses import AnalysisInsights, Component
+from project_config import ProjectConfig
+
+logger = logging.getLogger(__name__)
+
+EXTERNAL_MARKER_FILENAME = ".codeboarding-external"
+
+
+@dataclass
+class ExternalBoundaries:
+    """Repo-relative path patterns; a bare directory covers everything beneath it."""
+
+    patterns: list[str] = field(default_factory=list)
+
+    def __bool__(self) -> bool:
+        return bool(self.patterns)
+
+    def is_external(self, file_path: str) -> bool:
+        path = PurePosixPath(file_path).as_posix()
+        return any(fnmatch(path, pattern) or path.startswith(f"{pattern}/") for pattern in self.patterns)
+
+    def is_external_component(self, component: Component) -> bool:
+        files = component.file_paths()
+        return bool(files) and all(self.is_external(f) for f in files)
+
+    def llm_str(self) -> str:
+        paths = "\n".join(f"- `{pattern}`" for pattern in self.patterns)
+        return (
+            "\n\n## External Boundaries\n"
+            "Code under these paths is a client for an external service, not part of this system:\n"
+            f"{paths}\n"
+            "Keep such clients in their own components where the groups allow, and in the description "
+            "distinguish this project's own code from the calls it makes out to external services.\n"
+        )
+
+
+def _marked_directories(repo_dir: Path, file_paths: Iterable[str]) -> set[str]:
+    """Directories (repo-relative) holding a marker file, among the ancestors of *file_paths*."""
+    checked: dict[PurePosixPath, bool] = {}
+    marked: set[str] = set()
+    for file_path in file_paths:
+        for directory in PurePosixPath(file_path).parents:
+            if directory == PurePosixPath("."):
+                break
+            if directory not in checked:
+                checked[directory] = (repo_dir / directory / EXTERNAL_MARKER_FILENAME).is_file()
+            if checked[directory]:
+                marked.add(directory.as_posix())
+    return marked
+
+
+def load_external_boundaries(
+    repo_dir: Path, project_config: ProjectConfig, file_paths: Iterable[str]
+) -> ExternalBoundaries:
+    """Boundaries from ``[boundaries] external`` plus marker directories above *file_paths*.
+
+    *file_paths* may be absolute or repo-relative; only their ancestors are probed for
+    markers, so the repository is never walked.
+    """
+    configured = project_config.section("boundaries").get("external", [])
+    if isinstance(configured, str):
+        configured = [configured]
+    patterns = {str(p).strip().strip("/") for p in configured if str(p).strip().strip("/")}
+
+    relative: list[str] = []
+    for file_path in file_paths:
+        path = Path(file_path)
+        if path.is_absolute():
+            try:
+                path = path.relative_to(repo_dir)
+            except ValueError:
+                continue
+        relative.append(path.as_posix())
+    patterns |= _marked_directories(repo_dir, relative)
+
+    if patterns:
+        logger.info(f"External boundaries: {sorted(patterns)}")
+    return ExternalBoundaries(patterns=sorted(patterns))
+
+
+def mark_external_components(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights], boundaries: ExternalBoundaries
+) -> None:
+    """Set ``external`` on every component, at every level, from *boundaries*."""
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for component in analysis.components:
+            component.external = boundaries.is_external_component(component)
@@ -0,0 +1,133 @@
+"""The packages outside the project each component calls (``--show-external``).
+
+The call graph only holds the project's own symbols: ``fmt.Println``,
+``strings.Join`` or ``sb.WriteString`` on a ``strings.Builder`` are dropped,
+so a diagram cannot show which standard-library and third-party packages a
+component leans on. This pass reads the Go source of each component's
+functions and counts, per imported package outside the module, the distinct
+symbols each function calls:
+
+* ``fmt.Println(...)``: a qualifier bound by an import;
+* ``sb.WriteString(...)`` where ``sb`` is a parameter or local of an imported
+  type (``sb strings.Builder``, ``sb := &strings.Builder{}``,
+  ``sb := new(strings.Builder)``), counted as ``strings.Builder.WriteString``.
+
+Whether an import leaves the project is read from the file's ``go.mod``; with
+none, only standard-library paths (no dot in the first element) count. The
+result lands on each component as ``external_calls``, one entry per package
+with its call count, and the diagrams draw one collapsed boundary node per
+package rather than every symbol.
+"""
+
+import logging
+import re
+from collections import Counter
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.content_hash import SourceCache, read_source_lines
+from agents.file_index_models import ExternalPackageCalls
+from static_analyzer.constants import CALLABLE_TYPES
+from static_analyzer.go_imports import GoImportIndex, ImportTable
+from static_analyzer.go_source import clean
+
+logger = logging.getLogger(__name__)
+
+_CALLABLE_NODE_TYPES = {node_type.name for node_type in CALLABLE_TYPES}
+_IDENT = r"[A-Za-z_]\w*"
+# ``fmt.Println(`` / ``sb.WriteString(``; not ``a.b.c(`` past its first selector.
+_SELECTOR_CALL_RE = re.compile(rf"(?<![\w.])({_IDENT})\.({_IDENT})\s*\(")
+# ``sb strings.Builder`` / ``w *bufio.Writer`` in a parameter list or ``var`` declaration.
+_TYPED_NAME_RE = re.compile(rf"(?<![\w.])({_IDENT})\s+\*?({_IDENT})\.({_IDENT})\b(?!\s*[.(])")
+# ``sb := strings.Builder{}`` / ``sb = &strings.Builder{`` / ``sb := new(strings.Builder)``.
+_ASSIGNED_TYPE_RE = re.compile(
+    rf"(?<![\w.])({_IDENT})\s*:?=\s*(?:&\s*({_IDENT})\.({_IDENT})\s*\{{|({_IDENT})\.({_IDENT})\s*\{{|"
+    rf"new\(\s*({_IDENT})\.({_IDENT})\s*\))"
+)
+_KEYWORDS = frozenset(
+    "break case chan const continue default defer else fallthrough for func go goto if import interface map "
+    "package range return select struct switch type var".split()
+)
+
+
+def _external_import(table: ImportTable, qualifier: str) -> str | None:
+    """The import path *qualifier* stands for when the package is outside the project."""
+    go_import = table.import_for(qualifier)
+    if go_import is None or go_import.path in table.directories:
+        return None
+    if table.module is not None:
+        inside = go_import.path == table.module or go_import.path.startswith(f"{table.module}/")
+        return None if inside else go_import.path
+    return go_import.path if "." not in go_import.path.split("/", 1)[0] else None
+
+
+def external_symbols(body: str, table: ImportTable) -> dict[str, set[str]]:
+    """Import path -> the symbols of that package the cleaned function *body* calls."""
+    typed: dict[str, tuple[str, str]] = {}
+    for match in _TYPED_NAME_RE.finditer(body):
+        name, qualifier, type_name = match.groups()
+        if name not in _KEYWORDS and (path := _external_import(table, qualifier)) is not None:
+            typed[name] = (path, type_name)
+    for match in _ASSIGNED_TYPE_RE.finditer(body):
+        name = match.group(1)
+        qualifier, type_name = next((q, t) for q, t in zip(match.groups()[1::2], match.groups()[2::2]) if q)
+        if (path := _external_import(table, qualifier)) is not None:
+            typed[name] = (path, type_name)
+
+    found: dict[str, set[str]] = {}
+    for match in _SELECTOR_CALL_RE.finditer(body):
+        qualifier, member = match.groups()
+        if qualifier in typed:
+            path, type_name = typed[qualifier]
+            found.setdefault(path, set()).add(f"{type_name}.{member}")
+        elif (path := _external_import(table, qualifier)) is not None and not re.search(
+            rf"(?<![\w.]){qualifier}\s*:=|\bvar\s+{qualifier}\b", body
+        ):
+            found.setdefault(path, set()).add(member)
+    return found
+
+
+def component_external_calls(
+    repo_dir: Path, component: Component, index: GoImportIndex, source_cache: SourceCache
+) -> list[ExternalPackageCalls]:
+    """One entry per package outside the project: how many distinct symbols the component's functions call there."""
+    counts: Counter[str] = Counter()
+    for group in component.file_methods:
+        if not group.file_path.endswith(".go"):
+            continue
+        lines = read_source_lines(repo_dir, group.file_path, source_cache)
+        table = index.table(repo_dir / group.file_path)
+        if not lines or not table.imports:
+            continue
+        for method in group.methods:
+            if method.node_type not in _CALLABLE_NODE_TYPES:
+                continue
+            body = clean("\n".join(lines[method.start_line - 1 : method.end_line]))
+            for path, symbols in external_symbols(body, table).items():
+                counts[path] += len(symbols)
+    return [ExternalPackageCalls(package=path, calls=count) for path, count in sorted(counts.items())]
+
+
+def assign_external_calls(
+    repo_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    enabled: bool,
+) -> None:
+    """Set ``external_calls`` on every component, at every level.
+
+    When not *enabled* every component's list is cleared, so a baseline loaded
+    from an earlier ``--show-external`` run does not keep stale counts.
+    """
+    analyses = (root_analysis, *sub_analyses.values())
+    index = GoImportIndex()
+    source_cache: SourceCache = {}
+    packages: set[str] = set()
+    for analysis in analyses:
+        for component in analysis.components:
+            component.external_calls = (
+                component_external_calls(repo_dir, component, index, source_cache) if enabled else []
+            )
+            packages.update(entry.package for entry in component.external_calls)
+    if enabled:
+        logger.info(f"External calls: {len(packages)} packages outside the project")
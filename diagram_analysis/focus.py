@@ -0,0 +1,219 @@
+"""Symbol-level spotlight (``codeboarding focus``): one symbol, its callers and its callees.
+
+The component diagram answers "how does the system fit together"; when
+debugging one function the question is "what calls this, and what does it
+call". ``focus`` takes the symbol's ego network, every symbol within N call
+hops upstream or downstream, out of the call graph of an existing run and
+renders it grouped by the component each symbol belongs to.
+
+The call graph comes from the ``static_analysis.pkl`` run artifact saved next
+to ``analysis.json``. Without it, only the cross-component edges recorded in
+``analysis.json`` are available and calls inside a component are missing.
+"""
+
+import json
+import logging
+from collections import deque
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights
+from static_analyzer.analysis_cache import StaticAnalysisCache
+
+logger = logging.getLogger(__name__)
+
+FOCUS_FORMATS = ("mermaid", "json")
+
+
+class FocusError(ValueError):
+    pass
+
+
+@dataclass
+class SymbolGraph:
+    """Call edges between qualified names, plus where each known symbol is defined."""
+
+    callees: dict[str, set[str]] = field(default_factory=dict)
+    callers: dict[str, set[str]] = field(default_factory=dict)
+    locations: dict[str, tuple[str, int]] = field(default_factory=dict)
+    # False when only analysis.json's cross-component edges were available.
+    complete: bool = True
+
+    def add_edge(self, src: str, dst: str) -> None:
+        if src == dst:
+            return
+        self.callees.setdefault(src, set()).add(dst)
+        self.callers.setdefault(dst, set()).add(src)
+
+    def symbols(self) -> set[str]:
+        return set(self.callees) | set(self.callers) | set(self.locations)
+
+
+@dataclass
+class Spotlight:
+    symbol: str
+    hops: int
+    # Signed distance: negative for callers, positive for callees, 0 for the symbol.
+    distances: dict[str, int]
+    edges: list[tuple[str, str]]
+    components: dict[str, str]
+    locations: dict[str, tuple[str, int]]
+    complete: bool
+
+
+def load_symbol_graph(analysis_path: Path, root: AnalysisInsights, subs: dict[str, AnalysisInsights]) -> SymbolGraph:
+    """The run's call graph from ``static_analysis.pkl``, else the edges ``analysis.json`` records."""
+    artifact_dir = analysis_path.resolve().parent
+    results = StaticAnalysisCache(artifact_dir, artifact_dir.parent).get()
+    graph = SymbolGraph()
+    if results is not None:
+        for language in results.get_languages():
+            try:
+                cfg = results.get_cfg(language)
+            except ValueError:
+                continue
+            for name, node in cfg.nodes.items():
+                graph.locations[name] = (node.file_path, node.line_start)
+            for edge in cfg.edges:
+                graph.add_edge(edge.get_source(), edge.get_destination())
+        return graph
+
+    logger.warning(
+        f"No static_analysis.pkl next to {analysis_path}; using the cross-component edges in analysis.json only"
+    )
+    graph.complete = False
+    for analysis in (root, *subs.values()):
+        for relation in analysis.components_relations:
+            for edge in relation.all_edges or relation.key_edges:
+                graph.add_edge(edge.source.qualified_name, edge.target.qualified_name)
+                for reference in (edge.source, edge.target):
+                    if reference.reference_file:
+                        graph.locations.setdefault(
+                            reference.qualified_name, (reference.reference_file, reference.reference_start_line or 0)
+                        )
+    return graph
+
+
+def resolve_symbol(graph: SymbolGraph, symbol: str) -> str:
+    """*symbol* itself, or the one known name ending in ``.<symbol>``."""
+    known = graph.symbols()
+    if symbol in known:
+        return symbol
+    candidates = sorted(name for name in known if name.endswith(f".{symbol}"))
+    if len(candidates) == 1:
+        return candidates[0]
+    if candidates:
+        listed = ", ".join(candidates[:10]) + (", ..." if len(candidates) > 10 else "")
+        raise FocusError(f"'{symbol}' is ambiguous: {listed}")
+    raise FocusError(f"No symbol '{symbol}' in the call graph")
+
+
+def component_owners(root: AnalysisInsights) -> dict[str, str]:
+    """Top-level component name per method qualified name."""
+    owner: dict[str, str] = {}
+    for component in root.components:
+        for group in component.file_methods:
+            for method in group.methods:
+                owner.setdefault(method.qualified_name, component.name)
+    return owner
+
+
+def walk_calls(start: str, neighbours: dict[str, set[str]], hops: int | None) -> dict[str, int]:
+    """Breadth-first call distance from *start* of everything within *hops* (None: every hop)."""
+    distances = {start: 0}
+    queue = deque([start])
+    while queue:
+        name = queue.popleft()
+        if distances[name] == hops:
+            continue
+        for neighbour in sorted(neighbours.get(name, ())):
+            if neighbour not in distances:
+                distances[neighbour] = distances[name] + 1
+                queue.append(neighbour)
+    return distances
+
+
+def spotlight(graph: SymbolGraph, root: AnalysisInsights, symbol: str, hops: int) -> Spotlight:
+    """The symbols within *hops* calls of *symbol*, either way, and the call edges among them."""
+    symbol = resolve_symbol(graph, symbol)
+    callers = walk_calls(symbol, graph.callers, hops)
+    callees = walk_calls(symbol, graph.callees, hops)
+    distances = {name: -distance for name, distance in callers.items()}
+    # A symbol both calling and called through the focus keeps its callee distance.
+    distances.update(callees)
+    edges = sorted(
+        (src, dst) for src in distances for dst in graph.callees.get(src, ()) if dst in distances and src != dst
+    )
+    owner = component_owners(root)
+    return Spotlight(
+        symbol=symbol,
+        hops=hops,
+        distances=distances,
+        edges=edges,
+        components={name: owner[name] for name in distances if name in owner},
+        locations={name: graph.locations[name] for name in distances if name in graph.locations},
+        complete=graph.complete,
+    )
+
+
+def _mermaid_label(text: str) -> str:
+    return text.replace('"', "#quot;")
+
+
+def render_mermaid(spot: Spotlight, omitted: str | None = None) -> str:
+    """A left-to-right flowchart: callers on the left, callees on the right, grouped by component.
+
+    *omitted* labels a note attached to the symbol, for whatever was left out of the chart.
+    """
+    ids = {name: f"S{i}" for i, name in enumerate(sorted(spot.distances, key=lambda n: (spot.distances[n], n)))}
+    lines = ["graph LR"]
+    by_component: dict[str, list[str]] = {}
+    for name in ids:
+        by_component.setdefault(spot.components.get(name, ""), []).append(name)
+
+    def node(name: str) -> str:
+        label = _mermaid_label(name)
+        return f'{ids[name]}[["{label}"]]' if name == spot.symbol else f'{ids[name]}["{label}"]'
+
+    for index, (component, names) in enumerate(sorted(by_component.items())):
+        if component:
+            lines.append(f'    subgraph C{index}["{_mermaid_label(component)}"]')
+            lines.extend(f"        {node(name)}" for name in names)
+            lines.append("    end")
+        else:
+            lines.extend(f"    {node(name)}" for name in names)
+    lines.extend(f"    {ids[src]} --> {ids[dst]}" for src, dst in spot.edges)
+    if omitted:
+        lines.append(f'    OMITTED["{_mermaid_label(omitted)}"] -.- {ids[spot.symbol]}')
+    lines.append(f"    style {ids[spot.symbol]} stroke-width:3px")
+    return "\n".join(lines) + "\n"
+
+
+def render_json(spot: Spotlight) -> str:
+    symbols = [
+        {
+            "name": name,
+            "distance": distance,
+            "component": spot.components.get(name),
+            "file": spot.locations[name][0] if name in spot.locations else None,
+            "line": spot.locations[name][1] if name in spot.locations else None,
+        }
+        for name, distance in sorted(spot.distances.items(), key=lambda item: (item[1], item[0]))
+    ]
+    return (
+        json.dumps(
+            {
+                "symbol": spot.symbol,
+                "hops": spot.hops,
+                "complete": spot.complete,
+                "symbols": symbols,
+                "edges": [{"source": src, "target": dst} for src, dst in spot.edges],
+            },
+            indent=2,
+        )
+        + "\n"
+    )
+
+
+def render_spotlight(spot: Spotlight, fmt: str) -> str:
+    return render_json(spot) if fmt == "json" else render_mermaid(spot)
@@ -0,0 +1,108 @@
+"""How code is grouped into top-level components (``--grouping``).
+
+``semantic`` (the default) clusters the call graph and has the LLM name and
+describe the groups, so the cut can move between runs. The two static modes
+are read off the source tree instead and give the same components every time,
+which is what a CI check diffing the architecture needs:
+
+* ``package``: one component per package. A Go package is a directory and the
+  name its files' ``package`` clause declares (the root ``main.go`` is
+  ``main``); elsewhere it is the directory, as
+  :func:`diagram_analysis.dead_code.package_of` names it;
+* ``directory``: one component per source directory, named by its path.
+
+A static component carries every symbol of its package or directory, a short
+generated description and its most connected symbols as key entities. It is a
+leaf: no LLM expands it. The relations between components are the call edges
+crossing them, aggregated like any other static relation.
+"""
+
+import os
+from collections.abc import Mapping
+from dataclasses import dataclass, field
+from enum import StrEnum
+from pathlib import Path, PurePosixPath
+
+from diagram_analysis.dead_code import package_of
+from repo_utils.path_utils import normalize_repo_path
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES
+from static_analyzer.go_source import package_clause
+from static_analyzer.node import Node
+
+ROOT_DIRECTORY_NAME = "(root)"
+_KEY_ENTITIES = 5
+
+
+class Grouping(StrEnum):
+    SEMANTIC = "semantic"
+    PACKAGE = "package"
+    DIRECTORY = "directory"
+
+
+@dataclass
+class NodeGroup:
+    """The call-graph nodes of one package or directory."""
+
+    name: str
+    # Repo-relative POSIX path; "" for the repository root.
+    directory: str
+    nodes: list[Node] = field(default_factory=list)
+    files: set[str] = field(default_factory=set)
+
+    def key_nodes(self, degrees: Mapping[str, int], limit: int = _KEY_ENTITIES) -> list[Node]:
+        """The callables and types with the most call edges, ties broken by name."""
+        candidates = [node for node in self.nodes if node.type in CALLABLE_TYPES | CLASS_TYPES]
+        candidates.sort(key=lambda node: (-degrees.get(node.fully_qualified_name, 0), node.fully_qualified_name))
+        return candidates[:limit]
+
+    def description(self, grouping: Grouping) -> str:
+        where = f"`{self.directory}/`" if self.directory else "the repository root"
+        subject = f"Package `{self.name}` in {where}" if grouping == Grouping.PACKAGE else f"Directory {where}"
+        files = f"{len(self.files)} file{'s' if len(self.files) != 1 else ''}"
+        symbols = f"{len(self.nodes)} symbol{'s' if len(self.nodes) != 1 else ''}"
+        return f"{subject}: {files}, {symbols}."
+
+
+def _directory(relative: str) -> str:
+    parent = PurePosixPath(relative).parent.as_posix()
+    return "" if parent == "." else parent
+
+
+def group_nodes(static_analysis: StaticAnalysisResults, repo_dir: Path, grouping: Grouping) -> list[NodeGroup]:
+    """The call-graph nodes inside *repo_dir* by package or by directory, sorted by name."""
+    clauses: dict[str, str | None] = {}
+    groups: dict[tuple[str, str], NodeGroup] = {}
+    for cfg in static_analysis.available_cfgs().values():
+        for node in cfg.nodes.values():
+            relative = normalize_repo_path(node.file_path, repo_dir)
+            if os.path.isabs(relative):
+                continue
+            directory = _directory(relative)
+            if grouping == Grouping.DIRECTORY:
+                label = directory or ROOT_DIRECTORY_NAME
+            elif relative.endswith(".go"):
+                if node.file_path not in clauses:
+                    try:
+                        clauses[node.file_path] = package_clause(
+                            Path(node.file_path).read_text(encoding="utf-8", errors="replace")
+                        )
+                    except OSError:
+                        clauses[node.file_path] = None
+                label = clauses[node.file_path] or package_of(relative)
+            else:
+                label = package_of(relative)
+            group = groups.setdefault((directory, label), NodeGroup(label, directory))
+            group.nodes.append(node)
+            group.files.add(relative)
+
+    # ``cmd/app`` and the root can both be ``package main``: tell them apart by directory.
+    labels: dict[str, int] = {}
+    for _, label in groups:
+        labels[label] = labels.get(label, 0) + 1
+    for (directory, label), group in groups.items():
+        if labels[label] > 1:
+            group.name = f"{label} ({directory or ROOT_DIRECTORY_NAME})"
+    for group in groups.values():
+        group.nodes.sort(key=lambda node: (node.file_path, node.line_start, node.fully_qualified_name))
+    return sorted(groups.values(), key=lambda group: group.name)
@@ -0,0 +1,58 @@
+"""The HTTP endpoints each component serves (``--framework http``).
+
+The static analysis records on each Go handler the routes registered for it
+(see :mod:`static_analyzer.http_routes`). This pass lists them on every
+component whose methods include the handler, as ``http_routes``, so the docs
+can give an endpoints table: method, path and the handler serving it.
+"""
+
+import logging
+
+from agents.agent_responses import AnalysisInsights
+from agents.file_index_models import HttpRouteLink
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language
+
+logger = logging.getLogger(__name__)
+
+
+def handler_routes(static_analysis: StaticAnalysisResults) -> dict[str, list[tuple[str, str]]]:
+    """Handler qualified name -> its (method, path) routes."""
+    if Language.GO not in static_analysis.get_languages():
+        return {}
+    return {
+        qname: [(method, path) for method, _, path in (route.partition(" ") for route in node.http_routes)]
+        for qname, node in static_analysis.get_cfg(Language.GO).nodes.items()
+        if node.http_routes
+    }
+
+
+def assign_http_routes(
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    static_analysis: StaticAnalysisResults | None,
+) -> int:
+    """Set ``http_routes`` on every component, at every level; returns the number of root-level routes.
+
+    Without *static_analysis* (``--framework http`` off, or no analysis this
+    run) every component's list is cleared, so a baseline loaded from an
+    earlier run does not keep stale routes.
+    """
+    routes = handler_routes(static_analysis) if static_analysis is not None else {}
+    listed = 0
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for component in analysis.components:
+            component.http_routes = sorted(
+                (
+                    HttpRouteLink(method=method, path=path, qualified_name=method_entry.qualified_name)
+                    for group in component.file_methods
+                    for method_entry in group.methods
+                    for method, path in routes.get(method_entry.qualified_name, [])
+                ),
+                key=lambda route: (route.path, route.method, route.qualified_name),
+            )
+            if analysis is root_analysis:
+                listed += len(component.http_routes)
+    if listed:
+        logger.info(f"HTTP endpoints: {listed} routes across the components")
+    return listed
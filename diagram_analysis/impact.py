@@ -0,0 +1,190 @@
+"""Change impact of one symbol (``codeboarding impact``): everything that transitively calls it.
+
+Before changing a function, the question is what else could break. ``impact``
+walks the call graph of an existing run backwards from the symbol, following
+callers of callers up to ``--max-depth`` hops (every hop by default), and
+reports how many symbols are affected, in which components, and at what
+distance.
+
+Heavily used utilities can have thousands of transitive callers, so the text
+report and the diagram show the nearest ``--limit`` of them and summarize the
+rest per component; the JSON report always lists every caller.
+
+The call graph is the one ``codeboarding focus`` reads (see
+:func:`diagram_analysis.focus.load_symbol_graph`).
+"""
+
+import json
+from collections import Counter
+from dataclasses import dataclass
+
+from agents.agent_responses import AnalysisInsights
+from diagram_analysis.focus import (
+    Spotlight,
+    SymbolGraph,
+    component_owners,
+    render_mermaid,
+    resolve_symbol,
+    walk_calls,
+)
+
+IMPACT_FORMATS = ("text", "json", "mermaid")
+DEFAULT_LIMIT = 25
+UNASSIGNED = "(no component)"
+_EXAMPLES_PER_COMPONENT = 3
+
+
+@dataclass
+class Impact:
+    symbol: str
+    max_depth: int | None
+    # Call distance of every transitive caller (1 = calls the symbol directly).
+    callers: dict[str, int]
+    edges: list[tuple[str, str]]
+    components: dict[str, str]
+    locations: dict[str, tuple[str, int]]
+    complete: bool
+    # True when --max-depth stopped the walk with callers still beyond it.
+    depth_capped: bool
+
+    def nearest(self, limit: int | None) -> list[str]:
+        ordered = sorted(self.callers, key=lambda name: (self.callers[name], name))
+        return ordered if limit is None else ordered[:limit]
+
+    def by_component(self) -> list[tuple[str, list[str]]]:
+        """Affected components, most affected first, each with its callers nearest first."""
+        grouped: dict[str, list[str]] = {}
+        for name in self.nearest(None):
+            grouped.setdefault(self.components.get(name, UNASSIGNED), []).append(name)
+        return sorted(grouped.items(), key=lambda item: (-len(item[1]), item[0]))
+
+    def by_depth(self) -> dict[int, int]:
+        return dict(sorted(Counter(self.callers.values()).items()))
+
+
+def impact(graph: SymbolGraph, root: AnalysisInsights, symbol: str, max_depth: int | None = None) -> Impact:
+    """Every symbol that reaches *symbol* through at most *max_depth* calls, and the calls among them."""
+    symbol = resolve_symbol(graph, symbol)
+    distances = walk_calls(symbol, graph.callers, max_depth)
+    callers = {name: distance for name, distance in distances.items() if name != symbol}
+    edges = sorted(
+        (src, dst) for src in distances for dst in graph.callees.get(src, ()) if dst in distances and src != dst
+    )
+    depth_capped = max_depth is not None and any(
+        caller not in distances
+        for name, distance in distances.items()
+        if distance == max_depth
+        for caller in graph.callers.get(name, ())
+    )
+    owner = component_owners(root)
+    return Impact(
+        symbol=symbol,
+        max_depth=max_depth,
+        callers=callers,
+        edges=edges,
+        components={name: owner[name] for name in distances if name in owner},
+        locations={name: graph.locations[name] for name in distances if name in graph.locations},
+        complete=graph.complete,
+        depth_capped=depth_capped,
+    )
+
+
+def _plural(count: int, noun: str) -> str:
+    return f"{count} {noun}{'' if count == 1 else 's'}"
+
+
+def _headline(result: Impact) -> str:
+    components = len({result.components.get(name, UNASSIGNED) for name in result.callers})
+    depth = "" if result.max_depth is None else f" within {result.max_depth} hops"
+    line = (
+        f"Changing {result.symbol} affects {_plural(len(result.callers), 'transitive caller')}{depth} "
+        f"in {_plural(components, 'component')}"
+    )
+    if result.depth_capped:
+        line += "; more callers lie beyond the depth limit"
+    return line
+
+
+def _location(result: Impact, name: str) -> str:
+    if name not in result.locations:
+        return ""
+    file_path, line = result.locations[name]
+    return f"  {file_path}:{line}" if line else f"  {file_path}"
+
+
+def render_text(result: Impact, limit: int | None = DEFAULT_LIMIT) -> str:
+    lines = [_headline(result)]
+    if not result.complete:
+        lines.append("Only cross-component calls were available (no static_analysis.pkl); callers may be missing.")
+    if not result.callers:
+        return "\n".join(lines) + "\n"
+
+    lines.append("Callers by depth: " + ", ".join(f"{depth}: {count}" for depth, count in result.by_depth().items()))
+    lines.append("")
+    lines.append("Affected components:")
+    for component, names in result.by_component():
+        examples = ", ".join(names[:_EXAMPLES_PER_COMPONENT])
+        if len(names) > _EXAMPLES_PER_COMPONENT:
+            examples += ", ..."
+        lines.append(f"  {component}: {len(names)} ({examples})")
+
+    shown = result.nearest(limit)
+    lines.append("")
+    lines.append("Nearest callers:")
+    lines.extend(f"  [{result.callers[name]}] {name}{_location(result, name)}" for name in shown)
+    if len(shown) < len(result.callers):
+        lines.append(f"  ... and {len(result.callers) - len(shown)} more (--limit, or --format json for all)")
+    return "\n".join(lines) + "\n"
+
+
+def render_json(result: Impact) -> str:
+    callers = [
+        {
+            "name": name,
+            "distance": result.callers[name],
+            "component": result.components.get(name),
+            "file": result.locations[name][0] if name in result.locations else None,
+            "line": result.locations[name][1] if name in result.locations else None,
+        }
+        for name in result.nearest(None)
+    ]
+    payload = {
+        "symbol": result.symbol,
+        "max_depth": result.max_depth,
+        "complete": result.complete,
+        "depth_capped": result.depth_capped,
+        "caller_count": len(result.callers),
+        "by_depth": {str(depth): count for depth, count in result.by_depth().items()},
+        "components": [{"name": component, "callers": len(names)} for component, names in result.by_component()],
+        "callers": callers,
+        "edges": [{"source": src, "target": dst} for src, dst in result.edges],
+    }
+    return json.dumps(payload, indent=2) + "\n"
+
+
+def render_impact_mermaid(result: Impact, limit: int | None = DEFAULT_LIMIT) -> str:
+    """The nearest *limit* callers as a ``focus``-style flowchart; the rest become one note."""
+    shown = {result.symbol, *result.nearest(limit)}
+    spot = Spotlight(
+        symbol=result.symbol,
+        hops=max(result.callers.values(), default=0),
+        distances={name: -result.callers.get(name, 0) for name in shown},
+        edges=[(src, dst) for src, dst in result.edges if src in shown and dst in shown],
+        components={name: component for name, component in result.components.items() if name in shown},
+        locations={},
+        complete=result.complete,
+    )
+    hidden = [name for name in result.callers if name not in shown]
+    omitted = None
+    if hidden:
+        components = len({result.components.get(name, UNASSIGNED) for name in hidden})
+        omitted = f"+{len(hidden)} more callers in {_plural(components, 'component')}"
+    return render_mermaid(spot, omitted)
+
+
+def render_impact(result: Impact, fmt: str, limit: int | None = DEFAULT_LIMIT) -> str:
+    if fmt == "json":
+        return render_json(result)
+    if fmt == "mermaid":
+        return render_impact_mermaid(result, limit)
+    return render_text(result, limit)
@@ -0,0 +1,156 @@
+"""Package import graph (``--report imports``): which package depends on which.
+
+The component diagram groups code by responsibility; this report is the plain
+package view underneath it. Nodes are packages (source directories, named as
+in :func:`diagram_analysis.dead_code.package_of`), edges are the imports the
+static analysis recorded between them, and each edge is weighted by the
+number of call edges crossing from one package into the other. A package
+nothing imports stands alone, and the edges of an import cycle (packages that
+reach each other) are drawn in red.
+
+The graph is written twice: as a Mermaid flowchart in ``imports.md`` and as
+Graphviz DOT in ``imports.dot``. Test packages are left out.
+"""
+
+import os
+from collections import defaultdict
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from diagram_analysis.dead_code import package_of
+from diagram_analysis.structural_coverage import is_test_file
+from static_analyzer.analysis_result import StaticAnalysisResults
+
+IMPORT_GRAPH_FILENAME = "imports.md"
+IMPORT_GRAPH_DOT_FILENAME = "imports.dot"
+
+_CYCLE_COLOR = "red"
+
+
+@dataclass
+class ImportGraph:
+    packages: set[str] = field(default_factory=set)
+    # (importer, imported) -> number of calls from the importer into the imported package.
+    imports: dict[tuple[str, str], int] = field(default_factory=dict)
+
+    def reachable(self, package: str) -> set[str]:
+        successors: dict[str, list[str]] = defaultdict(list)
+        for src, dst in self.imports:
+            successors[src].append(dst)
+        seen: set[str] = set()
+        stack = list(successors[package])
+        while stack:
+            current = stack.pop()
+            if current not in seen:
+                seen.add(current)
+                stack.extend(successors[current])
+        return seen
+
+    def cycle_edges(self) -> set[tuple[str, str]]:
+        """Imports that close a cycle: the imported package imports its importer back, directly or not."""
+        reach = {package: self.reachable(package) for package in self.packages}
+        return {(src, dst) for src, dst in self.imports if src in reach.get(dst, set())}
+
+    def unimported(self) -> list[str]:
+        imported = {dst for _, dst in self.imports}
+        return sorted(self.packages - imported)
+
+
+def _relative(file_path: str, repo_dir: Path | None) -> str:
+    if repo_dir is None or not os.path.isabs(file_path):
+        return Path(file_path).as_posix()
+    relative = os.path.relpath(file_path, repo_dir)
+    return Path(file_path).as_posix() if relative.startswith("..") else Path(relative).as_posix()
+
+
+def _is_test_package(package: str) -> bool:
+    return is_test_file(f"{package.replace('.', '/')}/_")
+
+
+def build_import_graph(static_analysis: StaticAnalysisResults, repo_dir: Path | None = None) -> ImportGraph:
+    graph = ImportGraph()
+    for language in static_analysis.get_languages():
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        packages = {
+            name: package_of(relative)
+            for name, node in cfg.nodes.items()
+            if not os.path.isabs(relative := _relative(node.file_path, repo_dir))
+        }
+        calls: dict[tuple[str, str], int] = defaultdict(int)
+        for edge in cfg.edges:
+            src, dst = packages.get(edge.get_source()), packages.get(edge.get_destination())
+            if src is not None and dst is not None and src != dst:
+                calls[(src, dst)] += 1
+        try:
+            dependencies = static_analysis.get_package_dependencies(language)
+        except ValueError:
+            dependencies = {}
+        pairs = {(src, dst) for src, info in dependencies.items() for dst in info.get("imports", []) if src != dst}
+        # A call into another package implies an import, recorded or not.
+        pairs |= set(calls)
+        graph.packages.update(dependencies)
+        graph.packages.update(packages.values())
+        for pair in pairs:
+            graph.imports[pair] = graph.imports.get(pair, 0) + calls.get(pair, 0)
+    graph.packages = {package for package in graph.packages if not _is_test_package(package)}
+    graph.imports = {
+        pair: count for pair, count in graph.imports.items() if pair[0] in graph.packages and pair[1] in graph.packages
+    }
+    return graph
+
+
+def _calls(count: int) -> str:
+    return f"{count} call{'s' if count != 1 else ''}"
+
+
+def import_graph_mermaid(graph: ImportGraph) -> str:
+    ids = {package: f"P{index}" for index, package in enumerate(sorted(graph.packages))}
+    cycles = graph.cycle_edges()
+    lines = ["# Package imports", "", "```mermaid", "graph LR"]
+    lines.extend(f'    {ids[package]}["{package}"]' for package in sorted(graph.packages))
+    red: list[int] = []
+    for index, ((src, dst), count) in enumerate(sorted(graph.imports.items())):
+        lines.append(f'    {ids[src]} -- "{_calls(count)}" --> {ids[dst]}')
+        if (src, dst) in cycles:
+            red.append(index)
+    if red:
+        lines.append(f"    linkStyle {','.join(map(str, red))} stroke:{_CYCLE_COLOR},stroke-width:2px")
+    lines.append("```")
+    unimported = graph.unimported()
+    if unimported:
+        lines += ["", "Imported by no other package: " + ", ".join(f"`{package}`" for package in unimported)]
+    if cycles:
+        listed = ", ".join(f"`{src}` -> `{dst}`" for src, dst in sorted(cycles))
+        lines += ["", f"Import cycles (in red): {listed}"]
+    return "\n".join(lines) + "\n"
+
+
+def _quote(text: str) -> str:
+    return '"' + text.replace("\\", "\\\\").replace('"', '\\"') + '"'
+
+
+def import_graph_dot(graph: ImportGraph, name: str = "imports") -> str:
+    cycles = graph.cycle_edges()
+    lines = [f"digraph {_quote(name)} {{", "    rankdir=LR;", "    node [shape=box];"]
+    lines.extend(f"    {_quote(package)};" for package in sorted(graph.packages))
+    for (src, dst), count in sorted(graph.imports.items()):
+        attributes = f'label="{count}"'
+        if (src, dst) in cycles:
+            attributes += f", color={_CYCLE_COLOR}, fontcolor={_CYCLE_COLOR}"
+        lines.append(f"    {_quote(src)} -> {_quote(dst)} [{attributes}];")
+    lines.append("}")
+    return "\n".join(lines) + "\n"
+
+
+def write_import_graph(
+    static_analysis: StaticAnalysisResults, repo_dir: Path, output_dir: Path, name: str = "imports"
+) -> tuple[list[Path], ImportGraph]:
+    """Write ``imports.md`` and ``imports.dot`` into *output_dir*."""
+    graph = build_import_graph(static_analysis, repo_dir)
+    markdown_path, dot_path = output_dir / IMPORT_GRAPH_FILENAME, output_dir / IMPORT_GRAPH_DOT_FILENAME
+    markdown_path.write_text(import_graph_mermaid(graph), encoding="utf-8")
+    dot_path.write_text(import_graph_dot(graph, name), encoding="utf-8")
+    return [markdown_path, dot_path], graph
@@ -25,6 +25,7 @@ from filelock import FileLock
 from agents.agent_responses import AnalysisInsights, Component, index_components_by_id
 from agents.planner_agent import should_expand_component
 from diagram_analysis.analysis_json import (
+    AnalysisCoverageSummary,
     FileCoverageSummary,
     _compute_depth_level,
     build_unified_analysis_json,
@@ -123,13 +124,16 @@ class _AnalysisFileStore:
         file_coverage_summary: FileCoverageSummary | None = None,
         sub_expandable_ids: dict[str, list[str]] | None = None,
         depth_cap: int | None = None,
+        llm_providers: dict[str, str] | None = None,
+        analysis_coverage: AnalysisCoverageSummary | None = None,
     ) -> Path:
         """Write the full analysis to ``analysis.json`` with file locking.
 
         If *sub_analyses* is not provided, existing sub-analyses on disk are
         preserved. ``depth_cap`` is the run's configured depth ceiling; when
         omitted, the existing on-disk value is preserved (see
-        ``_write_with_lock_held``).
+        ``_write_with_lock_held``). The same holds for ``llm_providers`` and
+        ``analysis_coverage``.
         """
         with self._lock:
             return self._write_with_lock_held(
@@ -142,6 +146,8 @@ class _AnalysisFileStore:
                 file_coverage_summary,
                 sub_expandable_ids,
                 depth_cap,
+                llm_providers,
+                analysis_coverage,
             )
 
     def write_sub(
@@ -205,6 +211,8 @@ class _AnalysisFileStore:
         file_coverage_summary: FileCoverageSummary | None = None,
         sub_expandable_ids: dict[str, list[str]] | None = None,
         depth_cap: int | None = None,
+        llm_providers: dict[str, str] | None = None,
+        analysis_coverage: AnalysisCoverageSummary | None = None,
     ) -> Path:
         """Write ``analysis.json`` — caller must already hold ``self._lock``."""
         # A caller-provided set is authoritative: it already reflects the run's expansion
@@ -221,7 +229,14 @@ class _AnalysisFileStore:
         expandable = [c for c in analysis.components if c.component_id in expandable_ids]
 
         # Preserve existing metadata fields from disk when not explicitly provided
-        if sub_analyses is None or file_coverage_summary is None or not repo_name or depth_cap is None:
+        if (
+            sub_analyses is None
+            or file_coverage_summary is None
+            or not repo_name
+            or depth_cap is None
+            or llm_providers is None
+            or analysis_coverage is None
+        ):
             existing = self.read()
             if existing:
                 _, existing_subs, existing_data = existing
@@ -244,6 +259,10 @@ class _AnalysisFileStore:
                     # cap at the time (pre-separability-gate semantics), so it's the
                     # closest available approximation.
                     depth_cap = metadata.get("depth_cap", metadata.get("depth_level"))
+                if llm_providers is None:
+                    llm_providers = metadata.get("llm_providers")
+                if analysis_coverage is None and metadata.get("analysis_coverage"):
+                    analysis_coverage = AnalysisCoverageSummary.model_validate(metadata["analysis_coverage"])
         if depth_cap is None:
             depth_cap = DEFAULT_DEPTH_LEVEL
 
@@ -282,6 +301,8 @@ class _AnalysisFileStore:
             depth_cap=depth_cap,
             sub_analyses=sub_analyses_tuples,
             file_coverage_summary=file_coverage_summary,
+            llm_providers=llm_providers,
+            analysis_coverage=analysis_coverage,
         )
         write_text_atomic(self._analysis_path, payload)
         return self._analysis_path
@@ -388,13 +409,16 @@ def save_analysis(
     file_coverage_summary: FileCoverageSummary | None = None,
     sub_expandable_ids: dict[str, list[str]] | None = None,
     depth_cap: int | None = None,
+    llm_providers: dict[str, str] | None = None,
+    analysis_coverage: AnalysisCoverageSummary | None = None,
 ) -> Path:
     """Save the analysis to a unified analysis.json file with file locking.
 
     ``repo_dir`` relativizes paths; ``source_tree_hash`` is the precomputed
     whole-tree version key (reproducible by consumers that fingerprint the tree).
     ``depth_cap`` is the run's configured depth ceiling; omit to preserve the
-    existing on-disk value (e.g. for an intermediate save mid-run).
+    existing on-disk value (e.g. for an intermediate save mid-run). ``llm_providers``
+    (component ID -> ``provider/model``) and ``analysis_coverage`` are likewise preserved when omitted.
     """
     return _get_store(output_dir).write(
         analysis,
@@ -406,6 +430,8 @@ def save_analysis(
         file_coverage_summary,
         sub_expandable_ids,
         depth_cap,
+        llm_providers,
+        analysis_coverage,
     )
 
 
@@ -0,0 +1,61 @@
+"""Stable ordering for ``--deterministic`` runs.
+
+The language servers answer concurrently, so the call graph's nodes and edges
+arrive in a different order each run, and the order of everything derived from
+them follows: the graph Leiden partitions, the cluster strings in the prompts,
+the relations and their edges in ``analysis.json``. Leiden is seeded and the
+model samples at temperature 0, but a reordered input still moves the result.
+
+:func:`sort_static_analysis` puts the call graphs in name order before any
+clustering or prompting; :func:`sort_analysis_tree` puts the lists of the final
+analysis in a key order before it is saved and rendered. The order of the
+components themselves is left alone: their IDs are positional.
+"""
+
+from agents.agent_responses import AnalysisInsights, Relation, RelationEdge, SourceCodeReference
+from static_analyzer.analysis_result import StaticAnalysisResults
+
+
+def sort_static_analysis(static_analysis: StaticAnalysisResults) -> None:
+    """Order the nodes, edges and call sites of every call graph by name."""
+    for cfg in static_analysis.available_cfgs().values():
+        cfg.sort()
+
+
+def _reference_key(reference: SourceCodeReference) -> tuple[str, str, int, int]:
+    return (
+        reference.qualified_name,
+        reference.reference_file or "",
+        reference.reference_start_line or 0,
+        reference.reference_end_line or 0,
+    )
+
+
+def _sort_edges(edges: list[RelationEdge]) -> None:
+    for edge in edges:
+        edge.call_sites.sort(key=lambda site: (site.line, site.column))
+    edges.sort(key=lambda edge: (_reference_key(edge.source), _reference_key(edge.target), edge.description))
+
+
+def _relation_key(relation: Relation) -> tuple[str, str, str, str, str]:
+    return (relation.src_id, relation.dst_id, relation.src_name, relation.dst_name, relation.relation)
+
+
+def sort_analysis(analysis: AnalysisInsights) -> None:
+    """Order one level's key entities, methods, relations and file index by key."""
+    for component in analysis.components:
+        component.key_entities.sort(key=_reference_key)
+        component.file_methods.sort(key=lambda group: group.file_path)
+        for group in component.file_methods:
+            group.methods.sort(key=lambda method: (method.start_line, method.end_line, method.qualified_name))
+    for relation in analysis.components_relations:
+        _sort_edges(relation.key_edges)
+        _sort_edges(relation.all_edges)
+    analysis.components_relations.sort(key=_relation_key)
+    analysis.files = dict(sorted(analysis.files.items()))
+
+
+def sort_analysis_tree(root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]) -> None:
+    sort_analysis(root_analysis)
+    for sub_analysis in sub_analyses.values():
+        sort_analysis(sub_analysis)
@@ -0,0 +1,204 @@
+"""Structural test coverage: which components no test reaches (``--test-coverage-graph``).
+
+Test files are excluded from the analysis itself, so they are scanned on their
+own here: a production function or class is *directly tested* when a test file
+names it, and *reached* when it is reachable from a directly tested symbol over
+the production call graph. A component none of
+whose methods is reached is flagged "structurally untested".
+
+This is a coarse, name-based signal at the architecture level — a symbol named
+in a test is not necessarily exercised, and line coverage tools remain the
+authority on what actually runs — but it points at whole areas no test touches.
+
+Writes ``test_coverage.json`` and ``test_coverage.md`` (a Mermaid view with
+the untested components highlighted) to the output directory.
+"""
+
+import json
+import logging
+import os
+import re
+from collections import deque
+from collections.abc import Iterable
+from dataclasses import asdict, dataclass
+from pathlib import Path
+
+import pathspec
+
+from agents.agent_responses import AnalysisInsights
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer.graph import CallGraph
+from utils import sanitize
+
+logger = logging.getLogger(__name__)
+
+TEST_COVERAGE_FILENAME = "test_coverage.json"
+TEST_COVERAGE_MD = "test_coverage.md"
+
+# The test-file half of the default ``.codeboardingignore``.
+_TEST_SPEC = pathspec.PathSpec.from_lines(
+    "gitwildmatch",
+    [
+        "**/__tests__/**",
+        "**/tests/**",
+        "**/test/**",
+        "**/__test__/**",
+        "**/src/test/**",
+        "**/src/integration-test/**",
+        "**/e2e/**",
+        "**/integration-tests/**",
+        "*.test.*",
+        "*.spec.*",
+        "*_test.*",
+        "test_*.py",
+        "*Test.java",
+        "*Tests.java",
+        "*IT.java",
+        "*Test.kt",
+        "*Tests.cs",
+    ],
+)
+_IDENTIFIER_RE = re.compile(r"[A-Za-z_][A-Za-z0-9_]*")
+# Names too short to tell one symbol from another.
+_MIN_NAME_LENGTH = 3
+
+
+@dataclass
+class ComponentTestCoverage:
+    component_id: str
+    name: str
+    methods: int
+    reached: int
+    directly_tested: int
+
+    @property
+    def untested(self) -> bool:
+        return self.methods > 0 and self.reached == 0
+
+
+def is_test_file(rel_path: str) -> bool:
+    return _TEST_SPEC.match_file(rel_path)
+
+
+def find_test_files(repo_dir: Path, suffixes: set[str]) -> list[Path]:
+    """Test files with one of *suffixes* under *repo_dir*, skipping hidden, build and git-ignored directories."""
+    ignore = RepoIgnoreManager(repo_dir)
+    found = []
+    for root, dirs, files in os.walk(repo_dir):
+        rel_root = Path(root).relative_to(repo_dir)
+        dirs[:] = sorted(
+            d
+            for d in dirs
+            if ignore.categorize_file(rel_root / d) != "ignored_directory"
+            and not ignore.gitignore_spec.match_file(f"{(rel_root / d).as_posix()}/")
+        )
+        for name in sorted(files):
+            rel = (rel_root / name).as_posix()
+            if Path(name).suffix in suffixes and is_test_file(rel) and not ignore.gitignore_spec.match_file(rel):
+                found.append(repo_dir / rel)
+    return found
+
+
+def referenced_names(test_files: Iterable[Path]) -> set[str]:
+    names: set[str] = set()
+    for path in test_files:
+        try:
+            names.update(_IDENTIFIER_RE.findall(path.read_text(encoding="utf-8", errors="replace")))
+        except OSError as e:
+            logger.warning(f"Skipping test file {path}: {e}")
+    return {n for n in names if len(n) >= _MIN_NAME_LENGTH}
+
+
+def reachable_from_tests(cfgs: Iterable[CallGraph], names: set[str]) -> tuple[set[str], set[str]]:
+    """``(directly_tested, reached)`` qualified names across *cfgs* for the identifiers *names*."""
+    direct: set[str] = set()
+    callees: dict[str, set[str]] = {}
+    for cfg in cfgs:
+        for qualified_name in cfg.nodes:
+            if qualified_name.rsplit(".", 1)[-1] in names:
+                direct.add(qualified_name)
+        for edge in cfg.edges:
+            callees.setdefault(edge.get_source(), set()).add(edge.get_destination())
+
+    reached = set(direct)
+    queue = deque(direct)
+    while queue:
+        for callee in callees.get(queue.popleft(), ()):
+            if callee not in reached:
+                reached.add(callee)
+                queue.append(callee)
+    return direct, reached
+
+
+def component_test_coverage(
+    analysis: AnalysisInsights, direct: set[str], reached: set[str]
+) -> list[ComponentTestCoverage]:
+    coverage = []
+    for component in analysis.components:
+        names = {m.qualified_name for group in component.file_methods for m in group.methods}
+        coverage.append(
+            ComponentTestCoverage(
+                component_id=component.component_id,
+                name=component.name,
+                methods=len(names),
+                reached=len(names & reached),
+                directly_tested=len(names & direct),
+            )
+        )
+    return coverage
+
+
+def _coverage_markdown(analysis: AnalysisInsights, coverage: list[ComponentTestCoverage], test_files: int) -> str:
+    lines = ["```mermaid", "graph LR"]
+    for entry in coverage:
+        label = f"{entry.name}<br/>{entry.reached}/{entry.methods} reached"
+        lines.append(f'    {sanitize(entry.name)}["{label}"]')
+    for rel in analysis.components_relations:
+        lines.append(f"    {sanitize(rel.src_name)} --> {sanitize(rel.dst_name)}")
+    untested = [sanitize(e.name) for e in coverage if e.untested]
+    if untested:
+        lines.append("    classDef untested fill:#ffe3e3,stroke:#c92a2a,stroke-width:2px")
+        lines.append(f"    class {','.join(untested)} untested")
+    lines.append("```")
+
+    lines.append(f"\n# Structural test coverage\n\nScanned {test_files} test files.\n")
+    lines.append("## Structurally untested components\n")
+    lines.extend(f"- **{e.name}** ({e.methods} method{'s' if e.methods != 1 else ''})" for e in coverage if e.untested)
+    if not untested:
+        lines.append("_None_")
+    lines.append("\n| Component | Methods | Reached from tests | Named in tests |")
+    lines.append("|---|---|---|---|")
+    lines.extend(f"| {e.name} | {e.methods} | {e.reached} | {e.directly_tested} |" for e in coverage)
+    return "\n".join(lines) + "\n"
+
+
+def write_test_coverage_report(
+    output_dir: Path,
+    repo_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    cfgs: Iterable[CallGraph],
+    source_files: Iterable[str],
+) -> Path:
+    """Write ``test_coverage.json`` (every level) and ``test_coverage.md`` (root level)."""
+    suffixes = {Path(f).suffix for f in source_files if Path(f).suffix}
+    test_files = find_test_files(repo_dir, suffixes)
+    direct, reached = reachable_from_tests(cfgs, referenced_names(test_files))
+
+    root_coverage = component_test_coverage(root_analysis, direct, reached)
+    payload = {
+        "test_files": len(test_files),
+        "components": [asdict(e) | {"untested": e.untested} for e in root_coverage],
+        "sub_components": {
+            cid: [asdict(e) | {"untested": e.untested} for e in component_test_coverage(sub, direct, reached)]
+            for cid, sub in sorted(sub_analyses.items())
+        },
+    }
+    path = output_dir / TEST_COVERAGE_FILENAME
+    path.write_text(json.dumps(payload, indent=2), encoding="utf-8")
+    (output_dir / TEST_COVERAGE_MD).write_text(
+        _coverage_markdown(root_analysis, root_coverage, len(test_files)), encoding="utf-8"
+    )
+    untested = [e.name for e in root_coverage if e.untested]
+    logger.info(f"Test coverage graph: {len(untested)} structurally untested components {untested}; written to {path}")
+    return path
@@ -0,0 +1,150 @@
+"""Analysis of one subtree of a large repository (``--path`` / ``--package``).
+
+The language servers still index the whole repository, so the calls leaving
+the subtree resolve; only the call graph the components are generated from is
+cut down to the symbols under it. The symbols outside that the subtree calls
+are kept aside, grouped by package as :func:`diagram_analysis.grouping.group_nodes`
+groups them, and become external dependency components after the analysis:
+leaves with a generated description, drawn across the system boundary, whose
+relations are the calls into them. Whatever else lies outside is left out.
+"""
+
+import logging
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+
+from diagram_analysis.grouping import Grouping, NodeGroup, group_nodes
+from repo_utils.path_utils import normalize_repo_path
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language
+from static_analyzer.graph import CallGraph, Edge
+from static_analyzer.language_results import ControlFlowGraph, LanguageResults, SourceFiles
+
+logger = logging.getLogger(__name__)
+
+_RECURSIVE_SUFFIX = "..."
+
+
+class SubtreeError(ValueError):
+    pass
+
+
+def _relative_directory(value: str) -> str:
+    path = PurePosixPath(value.strip().replace("\\", "/"))
+    if path.is_absolute() or ".." in path.parts:
+        raise SubtreeError(f"expected a directory relative to the repository root, got '{value}'")
+    directory = path.as_posix()
+    return "" if directory == "." else directory
+
+
+@dataclass(frozen=True)
+class Subtree:
+    """A repo-relative directory, with (``recursive``) or without the directories below it."""
+
+    directory: str
+    recursive: bool = True
+
+    @classmethod
+    def from_path(cls, value: str) -> "Subtree":
+        return cls(_relative_directory(value))
+
+    @classmethod
+    def from_package(cls, pattern: str) -> "Subtree":
+        """A Go package pattern: ``./services/...`` for the tree, ``./services`` for that package alone."""
+        recursive = pattern.rstrip("/").endswith(_RECURSIVE_SUFFIX)
+        directory = pattern.rstrip("/").removesuffix(_RECURSIVE_SUFFIX) if recursive else pattern
+        if recursive and directory and not directory.endswith("/"):
+            raise SubtreeError(f"expected './DIR/...' or './DIR', got '{pattern}'")
+        return cls(_relative_directory(directory or "."), recursive)
+
+    def __str__(self) -> str:
+        prefix = f"./{self.directory}" if self.directory else "."
+        return f"{prefix}/{_RECURSIVE_SUFFIX}" if self.recursive else prefix
+
+    def contains(self, rel_path: str) -> bool:
+        parent = PurePosixPath(rel_path).parent.as_posix()
+        parent = "" if parent == "." else parent
+        if parent == self.directory:
+            return True
+        return self.recursive and (not self.directory or parent.startswith(f"{self.directory}/"))
+
+
+@dataclass
+class SubtreeView:
+    """The subtree's share of a static analysis, and what it calls outside."""
+
+    static_analysis: StaticAnalysisResults
+    # Per language: the symbols outside the subtree that it calls, and those calls.
+    outside: StaticAnalysisResults = field(default_factory=StaticAnalysisResults)
+    outside_calls: dict[str, list[Edge]] = field(default_factory=dict)
+
+    def dependencies(self, repo_dir: Path) -> list[NodeGroup]:
+        """The called outside symbols by package, sorted by name."""
+        return group_nodes(self.outside, repo_dir, Grouping.PACKAGE)
+
+    def with_outside_calls(self, cfg_graphs: dict[str, CallGraph]) -> dict[str, CallGraph]:
+        """*cfg_graphs* plus the called outside symbols and the calls into them from symbols still in it."""
+        joined: dict[str, CallGraph] = {}
+        for language, cfg in cfg_graphs.items():
+            calls = self.outside_calls.get(language, [])
+            if not calls:
+                joined[language] = cfg
+                continue
+            graph = cfg.filter(lambda node: True, lambda edge: None)
+            for edge in calls:
+                if graph.has_node(edge.get_source()):
+                    graph.add_node(edge.dst_node)
+                    graph.add_edge(edge.get_source(), edge.get_destination(), call_sites=edge.call_sites)
+            joined[language] = graph
+        return joined
+
+
+def apply_subtree(static_analysis: StaticAnalysisResults, subtree: Subtree, repo_path: Path) -> SubtreeView:
+    """A view of *static_analysis* whose call graphs keep the symbols under *subtree* only.
+
+    Like ``--select``, the input is left untouched so the static-analysis cache
+    still holds the whole repository; the source files are narrowed too.
+    """
+    if not (repo_path / subtree.directory).is_dir():
+        raise SubtreeError(f"{subtree}: no directory '{subtree.directory or '.'}' in {repo_path}")
+
+    def inside(file_path: str) -> bool:
+        return subtree.contains(normalize_repo_path(file_path, repo_path))
+
+    view = SubtreeView(
+        StaticAnalysisResults(
+            diagnostics=static_analysis.diagnostics,
+            incremental_base_results=static_analysis.incremental_base_results,
+        )
+    )
+    for language, bucket in static_analysis.results.items():
+        cfg = ControlFlowGraph()
+        if bucket.cfg.graph is not None:
+            graph = bucket.cfg.graph
+            leaving: list[Edge] = []
+            cfg.graph = graph.filter(
+                lambda node: inside(node.file_path),
+                lambda edge: leaving.append(edge) if inside(edge.src_node.file_path) else None,
+            )
+            if leaving:
+                outside = CallGraph(language=graph.language)
+                for edge in leaving:
+                    outside.add_node(edge.dst_node)
+                view.outside.add_cfg(Language(language), outside)
+                view.outside_calls[str(language)] = leaving
+            logger.info(
+                f"{subtree}: kept {len(cfg.graph.nodes)} of {len(graph.nodes)} {language} symbols, "
+                f"{len(leaving)} calls leave it"
+            )
+            if graph.nodes and not cfg.graph.nodes:
+                logger.warning(f"{subtree} holds no {language} symbols")
+        view.static_analysis.results[language] = LanguageResults(
+            cfg=cfg,
+            hierarchy=bucket.hierarchy,
+            references=bucket.references,
+            dependencies=bucket.dependencies,
+            source_files=SourceFiles(
+                None if bucket.source_files.paths is None else [p for p in bucket.source_files.paths if inside(p)]
+            ),
+        )
+    return view
@@ -0,0 +1,215 @@
+"""Per-component test map (``--test-map``): which test functions exercise each component.
+
+Test files are excluded from the analysis, so, like ``--test-coverage-graph``,
+they are scanned on their own: every test function (``def test_*``, Go
+``TestX``/``BenchmarkX``/``FuzzX``, JS/TS ``it(...)``/``test(...)``, and
+``@Test``/``[Fact]``-style methods) is cut out of its file together with the
+same-file helpers it calls, transitively. A test exercises a component when
+those bodies name one of the component's methods.
+
+Tests are listed by pytest-style IDs (``tests/test_x.py::TestY::test_z``) on
+every component at every level, in ``analysis.json`` and the component docs.
+Matching is by name, so a test naming a common method may be attributed to
+every component defining one; it answers "where are the tests for this" rather
+than proving coverage.
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights
+from diagram_analysis.structural_coverage import find_test_files
+
+logger = logging.getLogger(__name__)
+
+_IDENTIFIER_RE = re.compile(r"[A-Za-z_][A-Za-z0-9_]*")
+# Names too short to tell one symbol from another.
+_MIN_NAME_LENGTH = 3
+
+_PY_DEF_RE = re.compile(r"^([ \t]*)(?:async[ \t]+)?def[ \t]+(\w+)[ \t]*\(", re.MULTILINE)
+_PY_CLASS_RE = re.compile(r"^([ \t]*)class[ \t]+(\w+)", re.MULTILINE)
+_GO_FUNC_RE = re.compile(r"^func[ \t]+(?:\([^)]*\)[ \t]*)?(\w+)[ \t]*(?:\[[^\]]*\][ \t]*)?\(", re.MULTILINE)
+_GO_TEST_RE = re.compile(r"^(?:Test|Benchmark|Fuzz|Example)(?:[A-Z_0-9]|$)")
+_JS_TEST_RE = re.compile(r"\b(?:it|test)(?:\.\w+)?\(\s*(['\"`])(.*?)\1", re.DOTALL)
+_JS_FUNC_RE = re.compile(
+    r"\bfunction\*?\s+(\w+)\s*\(|\b(?:const|let|var)\s+(\w+)\s*=\s*(?:async\s*)?(?:\([^)]*\)|\w+)\s*=>"
+)
+# JUnit/TestNG ``@Test``, Kotlin ``@Test``, xUnit/NUnit/MSTest ``[Fact]``/``[Theory]``/``[Test]``/``[TestMethod]``.
+_ANNOTATED_TEST_RE = re.compile(
+    r"(?:@(?:Test|ParameterizedTest|RepeatedTest)\b|\[(?:Fact|Theory|Test|TestMethod|TestCase)\b[^\]]*\])"
+    r"[^{;]*?\b(\w+)\s*\([^)]*\)[^{;]*\{",
+    re.DOTALL,
+)
+
+_PY_SUFFIXES = {".py"}
+_GO_SUFFIXES = {".go"}
+_JS_SUFFIXES = {".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".mts", ".cts"}
+
+
+@dataclass
+class ScannedTest:
+    test_id: str
+    body: str
+    # Same-file helpers (name -> body) the test may call.
+    helpers: dict[str, str]
+
+    def names(self) -> set[str]:
+        """Identifiers in the test and, transitively, in the same-file helpers it calls."""
+        found: set[str] = set()
+        pending = [self.body]
+        seen_helpers: set[str] = set()
+        while pending:
+            identifiers = set(_IDENTIFIER_RE.findall(pending.pop()))
+            found |= identifiers
+            for helper in identifiers & self.helpers.keys() - seen_helpers:
+                seen_helpers.add(helper)
+                pending.append(self.helpers[helper])
+        return {name for name in found if len(name) >= _MIN_NAME_LENGTH}
+
+
+def _brace_body(text: str, open_idx: int) -> str:
+    """The text from the ``{`` at *open_idx* through its matching ``}`` (or the end of the file)."""
+    depth = 0
+    for i in range(open_idx, len(text)):
+        if text[i] == "{":
+            depth += 1
+        elif text[i] == "}":
+            depth -= 1
+            if depth == 0:
+                return text[open_idx : i + 1]
+    return text[open_idx:]
+
+
+def _indent(line: str) -> int:
+    expanded = line.expandtabs()
+    return len(expanded) - len(expanded.lstrip())
+
+
+def _python_functions(rel: str, text: str) -> list[ScannedTest]:
+    lines = text.splitlines(keepends=True)
+    offsets = [0]
+    for line in lines:
+        offsets.append(offsets[-1] + len(line))
+    classes = [(m.start(), _indent(m.group(1)), m.group(2)) for m in _PY_CLASS_RE.finditer(text)]
+
+    tests: list[tuple[str, str]] = []
+    helpers: dict[str, str] = {}
+    for match in _PY_DEF_RE.finditer(text):
+        indent = _indent(match.group(1))
+        end = text.count("\n", 0, match.start())
+        # Skip a wrapped signature: its closing ``) -> T:`` sits at the def's own indent.
+        while end < len(lines) - 1 and not lines[end].split("#", 1)[0].rstrip().endswith(":"):
+            end += 1
+        end += 1
+        while end < len(lines):
+            stripped = lines[end].strip()
+            if stripped and not stripped.startswith("#") and _indent(lines[end]) <= indent:
+                break
+            end += 1
+        body = text[match.start() : offsets[end]]
+        name = match.group(2)
+        if not name.startswith("test"):
+            helpers[name] = body
+            continue
+        # The enclosing classes, outermost first, for a pytest-style ID.
+        scope: list[str] = []
+        limit = indent
+        for start, class_indent, class_name in reversed(classes):
+            if start < match.start() and class_indent < limit:
+                scope.insert(0, class_name)
+                limit = class_indent
+        tests.append(("::".join([rel, *scope, name]), body))
+    return [ScannedTest(test_id, body, helpers) for test_id, body in tests]
+
+
+def _go_functions(rel: str, text: str) -> list[ScannedTest]:
+    tests: list[tuple[str, str]] = []
+    helpers: dict[str, str] = {}
+    for match in _GO_FUNC_RE.finditer(text):
+        open_idx = text.find("{", match.end())
+        if open_idx == -1:
+            continue
+        body = _brace_body(text, open_idx)
+        name = match.group(1)
+        if _GO_TEST_RE.match(name):
+            tests.append((f"{rel}::{name}", body))
+        else:
+            helpers[name] = body
+    return [ScannedTest(test_id, body, helpers) for test_id, body in tests]
+
+
+def _js_functions(rel: str, text: str) -> list[ScannedTest]:
+    helpers: dict[str, str] = {}
+    for match in _JS_FUNC_RE.finditer(text):
+        open_idx = text.find("{", match.end())
+        if open_idx != -1:
+            helpers[match.group(1) or match.group(2)] = _brace_body(text, open_idx)
+    tests = []
+    for match in _JS_TEST_RE.finditer(text):
+        open_idx = text.find("{", match.end())
+        if open_idx != -1:
+            name = " ".join(match.group(2).split())
+            tests.append(ScannedTest(f"{rel}::{name}", _brace_body(text, open_idx), helpers))
+    return tests
+
+
+def _annotated_functions(rel: str, text: str) -> list[ScannedTest]:
+    return [
+        ScannedTest(f"{rel}::{match.group(1)}", _brace_body(text, match.end() - 1), {})
+        for match in _ANNOTATED_TEST_RE.finditer(text)
+    ]
+
+
+def find_test_functions(repo_dir: Path, test_file: Path) -> list[ScannedTest]:
+    """The test functions of one test file, with their same-file helpers."""
+    try:
+        text = test_file.read_text(encoding="utf-8", errors="replace")
+    except OSError as e:
+        logger.warning(f"Skipping test file {test_file}: {e}")
+        return []
+    rel = test_file.relative_to(repo_dir).as_posix()
+    if test_file.suffix in _PY_SUFFIXES:
+        return _python_functions(rel, text)
+    if test_file.suffix in _GO_SUFFIXES:
+        return _go_functions(rel, text)
+    if test_file.suffix in _JS_SUFFIXES:
+        return _js_functions(rel, text)
+    return _annotated_functions(rel, text)
+
+
+def assign_component_tests(
+    repo_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    enabled: bool,
+) -> None:
+    """Set ``tests`` on every component, at every level, to the IDs of the test functions naming its methods.
+
+    When not *enabled* every component's tests are cleared, so a baseline loaded
+    from an earlier ``--test-map`` run does not keep a stale map.
+    """
+    analyses = (root_analysis, *sub_analyses.values())
+    if not enabled:
+        for analysis in analyses:
+            for component in analysis.components:
+                component.tests = []
+        return
+
+    # Sub-components only split their parent's files, so the root level covers every language.
+    suffixes = {Path(path).suffix for c in root_analysis.components for path in c.file_paths() if Path(path).suffix}
+    test_names = {
+        test.test_id: test.names()
+        for test_file in find_test_files(repo_dir, suffixes)
+        for test in find_test_functions(repo_dir, test_file)
+    }
+    for analysis in analyses:
+        for component in analysis.components:
+            method_names = {
+                m.qualified_name.rsplit(".", 1)[-1] for group in component.file_methods for m in group.methods
+            }
+            component.tests = sorted(test_id for test_id, names in test_names.items() if names & method_names)
+    tested = sum(1 for c in root_analysis.components if c.tests)
+    total = len(root_analysis.components)
+    logger.info(f"Test map: {len(test_names)} test functions; {tested}/{total} top-level components have tests")
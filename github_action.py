@@ -7,6 +7,9 @@ from codeboarding_workflows.analysis import run_incremental_workflow
 from codeboarding_workflows.rendering import render_docs
 from diagram_analysis import DEFAULT_DEPTH_LEVEL, DiagramGenerator, RunContext
 from diagram_analysis.io_utils import load_analysis_metadata
+from output_generators.doc_templates import DocTemplateError, DocTemplates
+from output_generators.preamble import DocsPreamble
+from output_generators.snippets import SnippetSource
 from repo_utils import checkout_repo, clone_repository
 from utils import ANALYSIS_FILENAME, CODEBOARDING_DIR_NAME, create_temp_repo_folder
 
@@ -20,6 +23,10 @@ def generate_markdown(
     target_branch: str,
     temp_repo_folder: Path,
     output_dir: str,
+    snippets: SnippetSource | None = None,
+    collapsible: bool = False,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
 ) -> None:
     render_docs(
         analysis_path=analysis_path,
@@ -27,11 +34,22 @@ def generate_markdown(
         repo_ref=f"{repo_url}/blob/{target_branch}/{output_dir}",
         temp_dir=temp_repo_folder,
         format=".md",
+        snippets=snippets,
+        collapsible_md=collapsible,
+        preamble=preamble,
+        doc_templates=doc_templates,
     )
 
 
 def generate_html(
-    analysis_path: Path, repo_name: str, repo_url: str, target_branch: str, temp_repo_folder: Path
+    analysis_path: Path,
+    repo_name: str,
+    repo_url: str,
+    target_branch: str,
+    temp_repo_folder: Path,
+    snippets: SnippetSource | None = None,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
 ) -> None:
     render_docs(
         analysis_path=analysis_path,
@@ -39,6 +57,9 @@ def generate_html(
         repo_ref=f"{repo_url}/blob/{target_branch}",
         temp_dir=temp_repo_folder,
         format=".html",
+        snippets=snippets,
+        preamble=preamble,
+        doc_templates=doc_templates,
     )
 
 
@@ -49,6 +70,8 @@ def generate_mdx(
     target_branch: str,
     temp_repo_folder: Path,
     output_dir: str,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
 ) -> None:
     render_docs(
         analysis_path=analysis_path,
@@ -56,6 +79,8 @@ def generate_mdx(
         repo_ref=f"{repo_url}/blob/{target_branch}/{output_dir}",
         temp_dir=temp_repo_folder,
         format=".mdx",
+        preamble=preamble,
+        doc_templates=doc_templates,
     )
 
 
@@ -66,6 +91,8 @@ def generate_rst(
     target_branch: str,
     temp_repo_folder: Path,
     output_dir: str,
+    preamble: DocsPreamble | None = None,
+    doc_templates: DocTemplates | None = None,
 ) -> None:
     render_docs(
         analysis_path=analysis_path,
@@ -73,6 +100,8 @@ def generate_rst(
         repo_ref=f"{repo_url}/blob/{target_branch}/{output_dir}",
         temp_dir=temp_repo_folder,
         format=".rst",
+        preamble=preamble,
+        doc_templates=doc_templates,
     )
 
 
@@ -96,6 +125,26 @@ def _resolve_depth_level(temp_repo_folder: Path) -> int:
     return DEFAULT_DEPTH_LEVEL
 
 
+def _docs_preamble(repo_dir: Path) -> DocsPreamble:
+    intro = os.getenv("DOCS_INTRO")
+    try:
+        return DocsPreamble.load(os.getenv("DOCS_TITLE"), repo_dir / intro if intro else None)
+    except OSError as e:
+        logger.warning(f"Could not read DOCS_INTRO, generating docs without it: {e}")
+        return DocsPreamble.load(os.getenv("DOCS_TITLE"), None)
+
+
+def _doc_templates(repo_dir: Path) -> DocTemplates | None:
+    paths = [repo_dir / path.strip() for path in os.getenv("DOCS_TEMPLATES", "").split(",") if path.strip()]
+    if not paths:
+        return None
+    try:
+        return DocTemplates.load(paths)
+    except (DocTemplateError, OSError) as e:
+        logger.warning(f"Could not load DOCS_TEMPLATES, generating docs with the built-in layout: {e}")
+        return None
+
+
 def generate_analysis(
     repo_url: str,
     source_branch: str,
@@ -127,16 +176,62 @@ def generate_analysis(
     )
 
     analysis_path = run_incremental_workflow(generator)
+    # SNIPPETS=true shows key-entity source excerpts, subject to the repo's [snippets] policy.
+    snippets = SnippetSource.for_repo(repo_dir) if os.getenv("SNIPPETS", "").lower() in ("1", "true") else None
+    # COLLAPSIBLE_MD=true nests components and source directories in <details> sections.
+    collapsible = os.getenv("COLLAPSIBLE_MD", "").lower() in ("1", "true")
+    # DOCS_TITLE / DOCS_INTRO (a path inside the repo) head the top-level doc.
+    preamble = _docs_preamble(repo_dir)
+    # DOCS_TEMPLATES: comma-separated <name>.<format>.j2 paths inside the repo laying out each component.
+    doc_templates = _doc_templates(repo_dir)
 
     match extension:
         case ".md":
-            generate_markdown(analysis_path, repo_name, repo_url, target_branch, temp_repo_folder, output_dir)
+            generate_markdown(
+                analysis_path,
+                repo_name,
+                repo_url,
+                target_branch,
+                temp_repo_folder,
+                output_dir,
+                snippets=snippets,
+                collapsible=collapsible,
+                preamble=preamble,
+                doc_templates=doc_templates,
+            )
         case ".html":
-            generate_html(analysis_path, repo_name, repo_url, target_branch, temp_repo_folder)
+            generate_html(
+                analysis_path,
+                repo_name,
+                repo_url,
+                target_branch,
+                temp_repo_folder,
+                snippets=snippets,
+                preamble=preamble,
+                doc_templates=doc_templates,
+            )
         case ".mdx":
-            generate_mdx(analysis_path, repo_name, repo_url, target_branch, temp_repo_folder, output_dir)
+            generate_mdx(
+                analysis_path,
+                repo_name,
+                repo_url,
+                target_branch,
+                temp_repo_folder,
+                output_dir,
+                preamble,
+                doc_templates,
+            )
         case ".rst":
-            generate_rst(analysis_path, repo_name, repo_url, target_branch, temp_repo_folder, output_dir)
+            generate_rst(
+                analysis_path,
+                repo_name,
+                repo_url,
+                target_branch,
+                temp_repo_folder,
+                output_dir,
+                preamble,
+                doc_templates,
+            )
         case _:
             raise ValueError(f"Unsupported extension: {extension}")
 
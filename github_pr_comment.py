@@ -0,0 +1,98 @@
+"""Sticky pull-request comment for the architecture-diff action (``.github/actions/architecture-diff``).
+
+Posts the ``architecture_diff.md`` that ``codeboarding --diff`` wrote, or a short
+note when the analysis could not run, as one PR comment that later pushes edit
+in place. The comment is found again by a hidden marker, so a re-run never
+stacks a second one.
+
+Reads the runner's ``GITHUB_TOKEN``, ``GITHUB_REPOSITORY``, ``GITHUB_EVENT_PATH``
+and ``GITHUB_API_URL``.
+"""
+
+import argparse
+import json
+import logging
+import os
+import sys
+from pathlib import Path
+
+import requests
+
+logger = logging.getLogger(__name__)
+
+COMMENT_MARKER = "<!-- codeboarding-architecture-diff -->"
+# GitHub rejects comment bodies over 65536 characters.
+MAX_COMMENT_CHARS = 65000
+_TRUNCATED_NOTE = "\n\n_Truncated: the full report is in the workflow run's `architecture_diff.md` artifact._\n"
+_TIMEOUT_S = 30
+
+
+def pull_request_number(event_path: Path) -> int | None:
+    """The PR the workflow runs for, from the event payload; None outside a pull_request event."""
+    event = json.loads(event_path.read_text(encoding="utf-8"))
+    number = (event.get("pull_request") or {}).get("number")
+    if number is None and (event.get("issue") or {}).get("pull_request"):
+        number = event["issue"]["number"]
+    return int(number) if number is not None else None
+
+
+def comment_body(markdown: str) -> str:
+    body = f"{COMMENT_MARKER}\n{markdown}"
+    if len(body) <= MAX_COMMENT_CHARS:
+        return body
+    return body[: MAX_COMMENT_CHARS - len(_TRUNCATED_NOTE)] + _TRUNCATED_NOTE
+
+
+def upsert_comment(api_url: str, repository: str, pr_number: int, body: str, token: str) -> str:
+    """Edit the PR's marked comment, or post one; returns its URL. Raises ``requests.HTTPError``."""
+    headers = {"Authorization": f"Bearer {token}", "Accept": "application/vnd.github+json"}
+    comments_url = f"{api_url}/repos/{repository}/issues/{pr_number}/comments"
+    page_url: str | None = f"{comments_url}?per_page=100"
+    while page_url is not None:
+        response = requests.get(page_url, headers=headers, timeout=_TIMEOUT_S)
+        response.raise_for_status()
+        for comment in response.json():
+            if COMMENT_MARKER in (comment.get("body") or ""):
+                response = requests.patch(comment["url"], headers=headers, json={"body": body}, timeout=_TIMEOUT_S)
+                response.raise_for_status()
+                return response.json()["html_url"]
+        page_url = response.links.get("next", {}).get("url")
+    response = requests.post(comments_url, headers=headers, json={"body": body}, timeout=_TIMEOUT_S)
+    response.raise_for_status()
+    return response.json()["html_url"]
+
+
+def main(argv: list[str] | None = None) -> None:
+    parser = argparse.ArgumentParser(description="Post or update the architecture-diff comment on a pull request")
+    source = parser.add_mutually_exclusive_group(required=True)
+    source.add_argument("--body-file", type=Path, help="Markdown to post, e.g. architecture_diff.md")
+    source.add_argument("--note", help="A one-line note to post instead, when the diff could not be computed")
+    args = parser.parse_args(argv)
+    logging.basicConfig(level=logging.INFO, format="%(levelname)s %(message)s")
+
+    token = os.getenv("GITHUB_TOKEN")
+    repository = os.getenv("GITHUB_REPOSITORY")
+    event_path = os.getenv("GITHUB_EVENT_PATH")
+    if not token or not repository or not event_path:
+        parser.error("GITHUB_TOKEN, GITHUB_REPOSITORY and GITHUB_EVENT_PATH must be set (run inside a workflow)")
+    pr_number = pull_request_number(Path(event_path))
+    if pr_number is None:
+        logger.warning("Not a pull request event; no comment posted")
+        return
+
+    if args.body_file is not None:
+        markdown = args.body_file.read_text(encoding="utf-8")
+    else:
+        markdown = f"### Architecture changes\n\n{args.note}\n"
+    try:
+        url = upsert_comment(
+            os.getenv("GITHUB_API_URL", "https://api.github.com"), repository, pr_number, comment_body(markdown), token
+        )
+    except requests.RequestException as exc:
+        logger.error(f"Could not post the architecture-diff comment on PR #{pr_number}: {exc}")
+        sys.exit(1)
+    logger.info(f"Architecture-diff comment: {url}")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,246 @@
+"""Architecture fitness function: one weighted, trend-able score with a pass/fail threshold.
+
+Combines package cycles and dead code (from the health report) with component
+coupling and layering violations (from the component graph) into
+``fitness.json``. Weights, per-metric limits, the pass threshold and the layer
+definitions come from ``[fitness]`` and ``[layers]`` in the project config.
+"""
+
+import fnmatch
+import logging
+from enum import StrEnum
+from pathlib import Path
+
+from pydantic import BaseModel, Field, ValidationError
+
+from agents.agent_responses import AnalysisInsights, index_components_by_id
+from health.models import CircularDependencyCheck, HealthReport, StandardCheckSummary
+from project_config import ProjectConfig
+from utils import generated_at
+
+logger = logging.getLogger(__name__)
+
+FITNESS_FILENAME = "fitness.json"
+# Process exit code for ``--fitness-gate`` when the score is below the threshold.
+EXIT_FITNESS_FAILED = 3
+HEALTH_REPORT_RELPATH = Path("health") / "health_report.json"
+
+
+class FitnessMetric(StrEnum):
+    CYCLES = "cycles"
+    MAX_COUPLING = "max_coupling"
+    LAYERING_VIOLATIONS = "layering_violations"
+    DEAD_CODE_RATIO = "dead_code_ratio"
+
+
+_DEFAULT_LIMITS: dict[FitnessMetric, float] = {
+    FitnessMetric.CYCLES: 10,
+    FitnessMetric.MAX_COUPLING: 10,
+    FitnessMetric.LAYERING_VIOLATIONS: 10,
+    FitnessMetric.DEAD_CODE_RATIO: 0.25,
+}
+
+
+class FitnessConfig(BaseModel):
+    """``[fitness]`` table of ``.codeboarding/config.toml``."""
+
+    threshold: float = Field(default=0.7, ge=0.0, le=1.0, description="Minimum score for the run to pass.")
+    weights: dict[FitnessMetric, float] = Field(
+        default_factory=lambda: {metric: 1.0 for metric in FitnessMetric},
+        description="Relative weight of each metric; omitted metrics keep weight 1.0, 0 disables one.",
+    )
+    limits: dict[FitnessMetric, float] = Field(
+        default_factory=dict,
+        description="Metric value at which its score drops to 0.0 (linear from 0 = perfect).",
+    )
+
+    def weight(self, metric: FitnessMetric) -> float:
+        return self.weights.get(metric, 1.0)
+
+    def limit(self, metric: FitnessMetric) -> float:
+        return self.limits.get(metric, _DEFAULT_LIMITS[metric])
+
+
+class FitnessMetricResult(BaseModel):
+    metric: FitnessMetric
+    value: float | None = Field(description="Measured value, or None when the input wasn't available.")
+    limit: float = Field(description="Value at which this metric scores 0.0.")
+    weight: float
+    score: float | None = Field(description="0.0 (at or past the limit) to 1.0 (ideal); None when unmeasured.")
+    details: list[str] = Field(default_factory=list, description="Offending cycles, components or edges.")
+
+
+class FitnessReport(BaseModel):
+    version: int = Field(default=1, description="Schema version of the fitness report.")
+    generated_at: str = Field(description="ISO timestamp of when the report was generated.")
+    score: float = Field(description="Weighted average of the measured metric scores, 0.0 to 1.0.")
+    threshold: float
+    passed: bool
+    metrics: list[FitnessMetricResult] = Field(default_factory=list)
+
+
+def _count_cycles(health_report: HealthReport | None) -> tuple[float | None, list[str]]:
+    if health_report is None:
+        return None, []
+    cycles = [
+        cycle
+        for summary in health_report.check_summaries
+        if isinstance(summary, CircularDependencyCheck)
+        for cycle in summary.cycles
+    ]
+    return len(cycles), cycles
+
+
+def _dead_code_ratio(health_report: HealthReport | None) -> tuple[float | None, list[str]]:
+    """Unused-code findings per function checked."""
+    if health_report is None:
+        return None, []
+    by_name: dict[str, list[StandardCheckSummary]] = {}
+    for summary in health_report.check_summaries:
+        if isinstance(summary, StandardCheckSummary):
+            by_name.setdefault(summary.check_name, []).append(summary)
+    functions = sum(s.total_entities_checked for s in by_name.get("function_size", []))
+    if functions == 0:
+        return None, []
+    unused = sum(s.findings_count for s in by_name.get("unused_code_diagnostics", []))
+    return unused / functions, []
+
+
+def _max_coupling(root_analysis: AnalysisInsights) -> tuple[float, list[str]]:
+    """Most distinct top-level components any one top-level component is related to."""
+    neighbours: dict[str, set[str]] = {c.component_id: set() for c in root_analysis.components}
+    for rel in root_analysis.components_relations:
+        if rel.src_id in neighbours and rel.dst_id in neighbours and rel.src_id != rel.dst_id:
+            neighbours[rel.src_id].add(rel.dst_id)
+            neighbours[rel.dst_id].add(rel.src_id)
+    if not neighbours:
+        return 0, []
+    peak = max(len(n) for n in neighbours.values())
+    names = {c.component_id: c.name for c in root_analysis.components}
+    worst = sorted(names[cid] for cid, n in neighbours.items() if len(n) == peak and peak > 0)
+    return peak, worst
+
+
+def _layer_of(component_id: str, assigned: dict[str, int]) -> int | None:
+    """Layer index of the component, inherited from its nearest assigned ancestor."""
+    cid = component_id
+    while cid:
+        if cid in assigned:
+            return assigned[cid]
+        cid = cid.rpartition(".")[0]
+    return None
+
+
+def _layering_violations(
+    root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights], layers: dict
+) -> tuple[float | None, list[str]]:
+    """Count relations where a component depends on a layer above its own.
+
+    ``[layers]`` lists ``order`` top-down and maps each layer to component-name
+    globs under ``[layers.members]``; unmatched components are not checked.
+    """
+    order: list[str] = layers.get("order", [])
+    members: dict[str, list[str]] = layers.get("members", {})
+    if not order or not members:
+        return None, []
+    components = index_components_by_id(root_analysis, sub_analyses)
+    assigned: dict[str, int] = {}
+    for cid, component in components.items():
+        for index, layer in enumerate(order):
+            if any(fnmatch.fnmatch(component.name, pattern) for pattern in members.get(layer, [])):
+                assigned[cid] = index
+                break
+
+    violations: list[str] = []
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for rel in analysis.components_relations:
+            src_layer = _layer_of(rel.src_id, assigned)
+            dst_layer = _layer_of(rel.dst_id, assigned)
+            if src_layer is not None and dst_layer is not None and dst_layer < src_layer:
+                violations.append(f"{rel.src_name} ({order[src_layer]}) -> {rel.dst_name} ({order[dst_layer]})")
+    return len(violations), sorted(violations)
+
+
+def evaluate_fitness(
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    health_report: HealthReport | None,
+    config: FitnessConfig,
+    layers: dict | None = None,
+) -> FitnessReport:
+    """Score every measurable metric and combine them; unmeasured metrics don't count."""
+    measured = {
+        FitnessMetric.CYCLES: _count_cycles(health_report),
+        FitnessMetric.MAX_COUPLING: _max_coupling(root_analysis),
+        FitnessMetric.LAYERING_VIOLATIONS: _layering_violations(root_analysis, sub_analyses, layers or {}),
+        FitnessMetric.DEAD_CODE_RATIO: _dead_code_ratio(health_report),
+    }
+    results: list[FitnessMetricResult] = []
+    for metric, (value, details) in measured.items():
+        limit = config.limit(metric)
+        score: float | None = None
+        if value is not None:
+            score = max(0.0, 1.0 - value / limit) if limit > 0 else float(value == 0)
+        results.append(
+            FitnessMetricResult(
+                metric=metric, value=value, limit=limit, weight=config.weight(metric), score=score, details=details
+            )
+        )
+
+    weighted = [(r.score, r.weight) for r in results if r.score is not None and r.weight > 0]
+    total_weight = sum(w for _, w in weighted)
+    score = sum(s * w for s, w in weighted) / total_weight if total_weight else 1.0
+    return FitnessReport(
+        generated_at=generated_at(),
+        score=round(score, 4),
+        threshold=config.threshold,
+        passed=score >= config.threshold,
+        metrics=results,
+    )
+
+
+def load_fitness_config(project_config: ProjectConfig) -> FitnessConfig:
+    try:
+        return FitnessConfig.model_validate(project_config.section("fitness"))
+    except ValidationError as e:
+        logger.warning(f"Invalid [fitness] project config, using defaults: {e}")
+        return FitnessConfig()
+
+
+def _load_health_report(output_dir: Path) -> HealthReport | None:
+    path = output_dir / HEALTH_REPORT_RELPATH
+    if not path.exists():
+        return None
+    try:
+        return HealthReport.model_validate_json(path.read_text(encoding="utf-8"))
+    except (OSError, ValidationError) as e:
+        logger.warning(f"Fitness: ignoring unreadable health report {path}: {e}")
+        return None
+
+
+def write_fitness_report(
+    output_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    project_config: ProjectConfig,
+) -> FitnessReport:
+    """Evaluate fitness against the latest health report on disk and write ``fitness.json``."""
+    report = evaluate_fitness(
+        root_analysis,
+        sub_analyses,
+        _load_health_report(output_dir),
+        load_fitness_config(project_config),
+        project_config.section("layers"),
+    )
+    path = output_dir / FITNESS_FILENAME
+    path.write_text(report.model_dump_json(indent=2), encoding="utf-8")
+    verdict = "passed" if report.passed else "FAILED"
+    logger.info(f"Fitness score {report.score:.3f} {verdict} (threshold {report.threshold}); written to {path}")
+    return report
+
+
+def load_fitness_report(output_dir: Path) -> FitnessReport | None:
+    path = output_dir / FITNESS_FILENAME
+    if not path.exists():
+        return None
+    return FitnessReport.model_validate_json(path.read_text(encoding="utf-8"))
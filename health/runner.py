@@ -1,7 +1,6 @@
 import fnmatch
 import logging
 import os
-from datetime import datetime, timezone
 from pathlib import Path
 
 from core import run_plugin_health_checks
@@ -25,6 +24,7 @@ from health.models import (
 )
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.constants import Language
+from utils import generated_at
 
 logger = logging.getLogger(__name__)
 
@@ -235,7 +235,7 @@ def run_health_checks(
 
     return HealthReport(
         repository_name=repo_name,
-        timestamp=datetime.now(timezone.utc).isoformat(),
+        timestamp=generated_at(),
         overall_score=overall_score,
         check_summaries=check_summaries,
         file_summaries=file_summaries,
@@ -13,6 +13,7 @@ from pathlib import Path
 
 import requests
 from static_analyzer.java_utils import find_java_21_or_later
+from static_analyzer.swift_utils import find_sourcekit_lsp
 from tool_registry import (
     PINNED_NODE_VERSION,
     TOOL_REGISTRY,
@@ -78,6 +79,54 @@ def check_rust_toolchain() -> tuple[bool, str | None]:
     return True, None
 
 
+def check_elixir_toolchain() -> tuple[bool, str | None]:
+    """Check whether Elixir can run; ElixirLS ships as BEAM files and uses the host's Elixir/Erlang."""
+    elixir_path = shutil.which("elixir")
+    if elixir_path is None:
+        return False, "elixir not found; Elixir analysis requires Elixir 1.14+ and Erlang/OTP on PATH"
+    try:
+        subprocess.run([elixir_path, "--version"], capture_output=True, text=True, check=True, timeout=30)
+    except (subprocess.SubprocessError, OSError) as exc:
+        detail = getattr(exc, "stderr", "") or str(exc)
+        detail = " ".join(str(detail).split())
+        if detail:
+            return False, f"elixir failed to run; Elixir analysis unavailable ({detail})"
+        return False, "elixir failed to run; Elixir analysis unavailable"
+    return True, None
+
+
+def check_swift_toolchain() -> tuple[bool, str | None]:
+    """Check whether Swift can run; sourcekit-lsp ships with the toolchain and indexes through it."""
+    swift_path = shutil.which("swift")
+    if swift_path is None:
+        return False, "swift not found; Swift analysis requires Xcode or a Swift toolchain from swift.org"
+    try:
+        subprocess.run([swift_path, "--version"], capture_output=True, text=True, check=True, timeout=30)
+    except (subprocess.SubprocessError, OSError) as exc:
+        detail = getattr(exc, "stderr", "") or str(exc)
+        detail = " ".join(str(detail).split())
+        if detail:
+            return False, f"swift failed to run; Swift analysis unavailable ({detail})"
+        return False, "swift failed to run; Swift analysis unavailable"
+    return True, None
+
+
+def check_scala_toolchain() -> tuple[bool, str | None]:
+    """Check whether Java can run; the Metals launcher Coursier writes starts it on the host's JDK."""
+    java_path = shutil.which("java")
+    if java_path is None:
+        return False, "java not found; Scala analysis requires Java 17+ on PATH for Metals"
+    try:
+        subprocess.run([java_path, "-version"], capture_output=True, text=True, check=True, timeout=30)
+    except (subprocess.SubprocessError, OSError) as exc:
+        detail = getattr(exc, "stderr", "") or str(exc)
+        detail = " ".join(str(detail).split())
+        if detail:
+            return False, f"java failed to run; Scala analysis unavailable ({detail})"
+        return False, "java failed to run; Scala analysis unavailable"
+    return True, None
+
+
 def check_npm(target_dir: Path | None = None) -> bool:
     """Check if npm is available via the configured Node.js runtime or PATH."""
     print("Step: npm check started")
@@ -610,7 +659,12 @@ def _language_checks_from_registry(target_dir: Path) -> list[LanguageSupportChec
         reason_requirement = f"{dep.binary_name} not installed"
         reason_binary = f"{dep.binary_name} binary not found"
 
-        if dep.kind is ToolKind.NATIVE:
+        if dep.kind is ToolKind.NATIVE and dep.key == "swift":
+            # sourcekit-lsp comes with the Swift toolchain rather than being downloaded.
+            fallback_available = bool(find_sourcekit_lsp())
+            reason_requirement = "sourcekit-lsp not found; install Xcode or a Swift toolchain"
+            reason_binary = reason_requirement
+        elif dep.kind is ToolKind.NATIVE:
             if platform_bin_dir is not None:
                 paths.append(platform_bin_dir / f"{dep.binary_name}{native_ext}")
             else:
@@ -627,8 +681,8 @@ def _language_checks_from_registry(target_dir: Path) -> list[LanguageSupportChec
                 reason_requirement = "pyright-langserver not found in node_modules or active environment"
                 reason_binary = reason_requirement
         elif dep.kind is ToolKind.ARCHIVE:
-            # JDTLS is validated by directory presence (+ plugins/ subdir),
-            # mirroring has_required_tools.
+            # JDTLS and ElixirLS are validated by directory presence (+ their
+            # archive marker), mirroring has_required_tools.
             subdir = dep.archive_subdir or dep.key
             paths.append(target_dir / "bin" / subdir)
             reason_requirement = f"{subdir} installation not found"
@@ -651,7 +705,12 @@ def _language_checks_from_registry(target_dir: Path) -> list[LanguageSupportChec
                 reason_requirement = f"{dep.binary_name} not installed ({manager} unavailable or install failed)"
             reason_binary = reason_requirement
 
-        health_check = check_rust_toolchain if dep.key == "rust" else None
+        health_check = {
+            "rust": check_rust_toolchain,
+            "elixir": check_elixir_toolchain,
+            "swift": check_swift_toolchain,
+            "scala": check_scala_toolchain,
+        }.get(dep.key)
         for lang in languages:
             checks.append(
                 LanguageSupportCheck(
@@ -10,6 +10,22 @@ _LOG_FORMATTER = logging.Formatter(
     datefmt="%Y-%m-%d %H:%M:%S",
 )
 
+# ``--log-level`` values; ``WARN`` is accepted for ``WARNING``.
+LOG_LEVELS = ("DEBUG", "INFO", "WARNING", "ERROR")
+
+# Third-party loggers held at WARNING unless the run logs at DEBUG: their INFO
+# lines (one per HTTP request, the OpenAI client's own retries) drown the run's.
+_NOISY_LOGGERS = ("git", "urllib3", "httpx", "httpcore", "openai", "anthropic")
+
+
+def normalize_log_level(level: str) -> str:
+    """``warn`` -> ``WARNING``; raises ``ValueError`` for a name not in :data:`LOG_LEVELS`."""
+    name = level.strip().upper()
+    name = "WARNING" if name == "WARN" else name
+    if name not in LOG_LEVELS:
+        raise ValueError(f"unknown log level '{level}'; choose from {', '.join(LOG_LEVELS)}")
+    return name
+
 
 def setup_logging(
     default_level: str = "INFO",
@@ -17,6 +33,7 @@ def setup_logging(
     backup_count: int = 5,
     log_dir: Path | None = None,
     log_filename: str | None = None,
+    log_file: Path | None = None,
 ):
     """Configure logging.
 
@@ -24,7 +41,14 @@ def setup_logging(
     (backwards-compatible behaviour).  When *log_dir* is ``None`` only the
     console handler is configured; call :func:`add_file_handler` later to
     attach the file handler once the output directory is known.
+
+    *log_file* (``--log-file``) receives every record at *default_level*,
+    while the console keeps to warnings and errors.
     """
+    level = normalize_log_level(default_level)
+    console_level = level
+    if log_file is not None and logging.getLevelNamesMapping()[level] < logging.WARNING:
+        console_level = "WARNING"
     handlers = ["console"]
 
     config: dict = {
@@ -39,19 +63,17 @@ def setup_logging(
         "handlers": {
             "console": {
                 "class": "logging.StreamHandler",
-                "level": default_level,
+                "level": console_level,
                 "formatter": "standard",
                 "stream": "ext://sys.stderr",
             },
         },
         "root": {
-            "level": default_level,
+            "level": level,
             "handlers": handlers,
         },
-        "loggers": {
-            "git": {"level": "WARNING"},
-            "urllib3": {"level": "WARNING"},
-        },
+        # NOTSET at DEBUG: set explicitly, since an earlier setup_logging() call's levels would persist.
+        "loggers": {name: {"level": "NOTSET" if level == "DEBUG" else "WARNING"} for name in _NOISY_LOGGERS},
     }
 
     if log_dir is not None:
@@ -67,6 +89,17 @@ def setup_logging(
         }
         handlers.append("file")
 
+    if log_file is not None:
+        log_file.parent.mkdir(parents=True, exist_ok=True)
+        config["handlers"]["log_file"] = {
+            "class": "logging.FileHandler",
+            "level": level,
+            "formatter": "standard",
+            "filename": str(log_file),
+            "encoding": "utf-8",
+        }
+        handlers.append("log_file")
+
     logging.config.dictConfig(config)
     _fix_console_encoding()
 
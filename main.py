@@ -1,12 +1,107 @@
 import argparse
 import os
 import sys
+from collections.abc import Callable
 from pathlib import Path
 
 from agents.llm_errors import EXIT_AUTH_ERROR, LLMAuthError
-from codeboarding_cli.commands import full_analysis, incremental_analysis, partial_analysis
+from agents.prompts.prompt_templates import PromptTemplateError, PromptTemplates
+from codeboarding_cli.commands import (
+    ask,
+    batch,
+    cache,
+    focus,
+    full_analysis,
+    impact,
+    incremental_analysis,
+    partial_analysis,
+    watch,
+)
+from logging_config import normalize_log_level
+from monitoring.progress import ProgressMode, configure_progress
+from project_config import load_project_config
+from output_generators.doc_templates import ComponentTemplate, DocTemplateError
+from output_generators.sequence import DEFAULT_MAX_DEPTH
+from static_analyzer.framework_edges import Framework
+from static_analyzer.graph import EdgeKind
+from static_analyzer.analysis_coverage import EXIT_COVERAGE_BELOW_MINIMUM
+from static_analyzer.feature_flags import FlagSettingError, parse_flag_setting
+from static_analyzer.select_query import SelectQuery, SelectQueryError
 
-_SUBCOMMANDS = {"full", "incremental", "partial"}
+_SUBCOMMANDS = {"full", "incremental", "partial", "watch", "batch", "ask", "focus", "impact", "cache"}
+
+
+def _comma_list(value: str) -> list[str]:
+    return [item.strip() for item in value.split(",") if item.strip()]
+
+
+def _edge_kind_list(value: str) -> list[str]:
+    kinds = _comma_list(value)
+    valid = [kind.value for kind in EdgeKind]
+    if unknown := [kind for kind in kinds if kind not in valid]:
+        raise argparse.ArgumentTypeError(f"unknown edge kind(s) {', '.join(unknown)}; choose from {', '.join(valid)}")
+    return kinds
+
+
+def _select_query(value: str) -> SelectQuery:
+    try:
+        return SelectQuery.parse(value)
+    except SelectQueryError as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+
+
+def _flag_setting(value: str) -> tuple[str, bool]:
+    try:
+        return parse_flag_setting(value)
+    except FlagSettingError as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+
+
+def _log_level(value: str) -> str:
+    try:
+        return normalize_log_level(value)
+    except ValueError as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+
+
+def _min_coverage(value: str) -> float:
+    try:
+        percent = float(value.strip().rstrip("%"))
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"expected a percentage, got '{value}'") from None
+    if not 0 <= percent <= 100:
+        raise argparse.ArgumentTypeError(f"must be between 0 and 100, got {value}")
+    return percent
+
+
+def _positive_int(flag_name: str) -> Callable[[str], int]:
+    """Argparse type for *flag_name*: a whole number of at least 1."""
+
+    def parse(value: str) -> int:
+        try:
+            number = int(value)
+        except ValueError:
+            raise argparse.ArgumentTypeError(f"{flag_name} expects a whole number, got '{value}'") from None
+        if number < 1:
+            raise argparse.ArgumentTypeError(f"{flag_name} must be at least 1, got {value}")
+        return number
+
+    return parse
+
+
+def _doc_template(value: str) -> ComponentTemplate:
+    try:
+        return ComponentTemplate.load(Path(value))
+    except (DocTemplateError, OSError) as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+
+
+def _prompt_dir(value: str) -> Path:
+    try:
+        PromptTemplates.load(Path(value))
+    except (PromptTemplateError, OSError) as e:
+        raise argparse.ArgumentTypeError(str(e)) from e
+    return Path(value).resolve()
 
 
 def _build_shared_parser() -> argparse.ArgumentParser:
@@ -20,17 +115,364 @@ def _build_shared_parser() -> argparse.ArgumentParser:
         help="Path to the binary directory for language servers (overrides ~/.codeboarding/servers/)",
     )
     shared.add_argument("--enable-monitoring", action="store_true", help="Enable monitoring")
+    shared.add_argument(
+        "--log-level",
+        type=_log_level,
+        metavar="LEVEL",
+        help="DEBUG, INFO (default), WARN or ERROR; at DEBUG, HTTP client logs and each LSP request's duration show",
+    )
+    shared.add_argument(
+        "--log-file",
+        type=Path,
+        metavar="PATH",
+        help="Also write the logs to PATH, keeping only warnings and errors on the console",
+    )
+    shared.add_argument(
+        "--progress",
+        choices=[mode.value for mode in ProgressMode],
+        default=ProgressMode.BAR.value,
+        help=(
+            "How to report run phases (LSP startup, symbols, references, LLM, rendering) on stderr: "
+            "progress lines with counts and elapsed time (bar), one JSON event per line (json), or nothing (quiet)"
+        ),
+    )
+    shared.add_argument(
+        "--framework",
+        action="append",
+        choices=[framework.value for framework in Framework],
+        help=(
+            "Add decorator-driven DI, module registration and route edges for a TS/JS framework, or HTTP route "
+            "edges and an endpoints table for Go net/http, gin, echo and chi routers (http); repeatable"
+        ),
+    )
+    shared.add_argument(
+        "--snapshot",
+        action="store_true",
+        help="Also write a sorted, LLM-free architecture.snapshot (components, files, edges) for committing",
+    )
+    providers = shared.add_mutually_exclusive_group()
+    providers.add_argument(
+        "--provider",
+        type=str,
+        metavar="NAME",
+        help="LLM provider to use (e.g. anthropic) when keys for several are configured",
+    )
+    providers.add_argument(
+        "--llm-fallback",
+        type=_comma_list,
+        metavar="PROVIDERS",
+        help="Ordered LLM providers to fail over between when one is down or rate-limited, e.g. anthropic,openai",
+    )
+    shared.add_argument(
+        "--model",
+        type=str,
+        metavar="NAME",
+        help=(
+            "Agent LLM model for this run (e.g. gpt-4o-mini; default: $CODEBOARDING_MODEL), overriding agent_model "
+            "from the config files"
+        ),
+    )
+    shared.add_argument(
+        "--max-retries",
+        type=_positive_int("--max-retries"),
+        metavar="N",
+        help=(
+            "Attempts per LLM request (default 5) with capped, jittered exponential backoff; a request still "
+            "rate-limited after N, or too large for the model, fails its component instead of hanging the run"
+        ),
+    )
+    shared.add_argument(
+        "--ollama-host",
+        type=str,
+        metavar="URL",
+        help="Ollama server to use for the LLM, pinning the provider (--provider ollama alone: http://localhost:11434)",
+    )
+    shared.add_argument(
+        "--main-package",
+        type=str,
+        metavar="DIR",
+        help=(
+            "Analyze only the Go binary built from this main package (e.g. ./cmd/server) and the module packages "
+            "it imports; use a separate --output-dir per binary"
+        ),
+    )
+    shared.add_argument(
+        "--resolve-interface-dispatch",
+        action="store_true",
+        help=(
+            "Go only: link calls through an interface to the methods of every type implementing it, or to the one "
+            "type a local assignment narrows the variable to"
+        ),
+    )
+    shared.add_argument(
+        "--include",
+        action="append",
+        metavar="GLOB",
+        help=(
+            "Analyze only files matching this glob, relative to the repository root, e.g. 'services/**' "
+            "(repeatable; .gitignore and .codeboardingignore still apply)"
+        ),
+    )
+    shared.add_argument(
+        "--exclude",
+        action="append",
+        metavar="GLOB",
+        help=(
+            "Leave files matching this glob out of the analysis, e.g. '**/vendor/**'; "
+            "wins over --include (repeatable)"
+        ),
+    )
+    shared.add_argument(
+        "--select",
+        type=_select_query,
+        metavar="QUERY",
+        help=(
+            "Generate components from the symbols this query selects only, combining path(GLOB), pkg(GLOB), "
+            "name(GLOB) and kind(KIND) with and/or/not and parentheses, e.g. "
+            "'pkg(example.com/app/**) and not name(*_test)'"
+        ),
+    )
+    shared.add_argument(
+        "--flag",
+        type=_flag_setting,
+        action="append",
+        metavar="NAME=on|off",
+        help=(
+            "Generate components as if feature flag NAME were on or off, dropping the calls guarded by the other "
+            "state; flag checks are the [feature_flags] patterns in .codeboarding/config.toml (repeatable)"
+        ),
+    )
+    shared.add_argument(
+        "--min-coverage",
+        type=_min_coverage,
+        metavar="PERCENT",
+        help=(
+            f"Exit with code {EXIT_COVERAGE_BELOW_MINIMUM} when the analysis coverage (resolved call sites x parsed "
+            "files, recorded as metadata.analysis_coverage in analysis.json) is below PERCENT"
+        ),
+    )
+    shared.add_argument(
+        "--max-llm-calls",
+        type=_positive_int("--max-llm-calls"),
+        metavar="N",
+        help=(
+            "Stop expanding components into subcomponents once the run has made N LLM requests; the rest are "
+            "marked 'not described (call budget reached)' and every static artifact is still written"
+        ),
+    )
+    shared.add_argument(
+        "--test-coverage-graph",
+        action="store_true",
+        help=(
+            "Write test_coverage.json/.md flagging components no test reaches, by following the symbols test "
+            "files name through the production call graph (a coarse, structural signal, not line coverage)"
+        ),
+    )
+    shared.add_argument(
+        "--test-map",
+        action="store_true",
+        help=(
+            "List under each component the test functions that exercise it (tests whose body, or a same-file "
+            "helper they call, names one of its methods) in analysis.json and the docs"
+        ),
+    )
+    shared.add_argument(
+        "--show-external",
+        action="store_true",
+        help=(
+            "Count each component's calls into packages outside the project (Go standard library and third-party "
+            "imports such as fmt or strings) and draw one collapsed boundary node per package, with the call "
+            "count on the edge, in analysis.json and the docs"
+        ),
+    )
+    shared.add_argument(
+        "--api-only",
+        action="store_true",
+        help=(
+            "Generate components and docs from exported symbols only (a leading capital in Go, no leading "
+            "underscore in Python); calls into unexported helpers become 'via internal' edges to the exported "
+            "symbols they reach"
+        ),
+    )
+    shared.add_argument(
+        "--hide-deprecated",
+        action="store_true",
+        help=(
+            "Collapse components whose code is all deprecated (@deprecated, // Deprecated:, [deprecated] in "
+            ".codeboarding/config.toml) into one 'Deprecated' component and keep them out of the overview"
+        ),
+    )
+    shared.add_argument(
+        "--use-codeowners",
+        action="store_true",
+        help=(
+            "Annotate each component with the CODEOWNERS owners of its files (and the owners of the components "
+            "it depends on) in analysis.json and the docs"
+        ),
+    )
+    shared.add_argument(
+        "--deterministic",
+        action="store_true",
+        help=(
+            "Make reruns on an unchanged commit reproducible: temperature 0, a fixed seed where the provider "
+            "supports one, call graphs and saved analyses sorted by a stable key, serial component analysis, LLM "
+            "responses reused from the previous run, and report timestamps taken from the HEAD commit "
+            "(SOURCE_DATE_EPOCH); with --grouping package or directory the output is byte-identical"
+        ),
+    )
+    shared.add_argument(
+        "--refresh-llm",
+        action="store_true",
+        help=(
+            "Ignore the LLM response cache (llm_responses.sqlite in .codeboarding/cache/, keyed by model, system "
+            "prompt and prompt) and the per-component docs (components/ there, keyed by the component's subgraph) "
+            "for this run and overwrite them with fresh completions"
+        ),
+    )
+    shared.add_argument(
+        "--dump-lsp",
+        type=Path,
+        metavar="DIR",
+        help=(
+            "Write the raw LSP responses (documentSymbol, references, ...) per source file as JSON under "
+            "DIR/<language>/ for debugging or custom tooling; forces a fresh static-analysis pass"
+        ),
+    )
+    shared.add_argument(
+        "--concurrency",
+        type=_positive_int("--concurrency"),
+        metavar="N",
+        help=(
+            "Document-symbol requests kept in flight at once against each language server (default: CPU count). "
+            "Use 1 for servers that misbehave under concurrent requests"
+        ),
+    )
+    shared.add_argument(
+        "--lsp-timeout",
+        type=_positive_int("--lsp-timeout"),
+        metavar="SECONDS",
+        help=(
+            "Seconds one language-server request may take before it is cancelled and its file marked partially "
+            "analyzed (default: 30; 120 for C# and Scala). The server is restarted after 3 timeouts in a row"
+        ),
+    )
+    shared.add_argument(
+        "--llm-edge-kinds",
+        type=_edge_kind_list,
+        metavar="KINDS",
+        help=(
+            "Edge kinds shown to the LLM as context, e.g. call,inherits "
+            "(default: call,spawns,returns,mutates,sends-to,receives-from). "
+            "Clustering and the diagram are unaffected"
+        ),
+    )
+    shared.add_argument(
+        "--format",
+        action="append",
+        choices=["chord", "rst", "pdf", "neo4j", "c4", "dot"],
+        help=(
+            "Extra output to write next to analysis.json: chord (D3 dependency wheel of component coupling), "
+            "rst (Sphinx reStructuredText docs with mermaid diagrams under sphinx/, rooted at sphinx/index.rst), "
+            "pdf (one paginated pdf/architecture.pdf with a cover page, contents and rendered diagrams; needs "
+            "WeasyPrint and the mermaid CLI), neo4j (components, symbols and edges under neo4j/ as CSV for "
+            "neo4j-admin import and as Cypher CREATE statements), c4 (c4.dsl, a Structurizr DSL C4 model: "
+            "packages as containers, components inside them) or dot (call_graph.dot, the static call graph for "
+            "Graphviz, one cluster per package)"
+        ),
+    )
+    shared.add_argument(
+        "--render",
+        choices=["svg", "png"],
+        help=(
+            "With --format dot or --no-llm, also run Graphviz (dot) to render call_graph.svg or call_graph.png "
+            "when installed"
+        ),
+    )
+    shared.add_argument(
+        "--collapse-chains",
+        action="store_true",
+        help=(
+            "In the DOT call graph, draw a fluent chain (b.Where().OrderBy().Build() on a type whose methods return "
+            "it) as one edge to the type labelled 'chain: Where,OrderBy,Build'; the JSON model keeps every call"
+        ),
+    )
+    shared.add_argument(
+        "--c4-level",
+        choices=["context", "container", "component"],
+        default="component",
+        help="Granularity of --format c4: context, container or component (default: component)",
+    )
+    shared.add_argument(
+        "--report",
+        action="append",
+        choices=["dead-code", "imports"],
+        help=(
+            "Extra report to write next to analysis.json, repeatable: dead-code (dead_code.md: symbols nothing "
+            "references, by package with file:line, those only tests name, and packages nothing imports); imports "
+            "(imports.md and imports.dot: the package import graph weighted by cross-package calls, cycles in red)"
+        ),
+    )
+    shared.add_argument(
+        "--sequence-from",
+        metavar="SYMBOL",
+        help=(
+            "Also write sequence.puml, a PlantUML sequence diagram of the calls made from this entry function "
+            "(e.g. main.main, or a unique dotted suffix of its qualified name), in source order"
+        ),
+    )
+    shared.add_argument(
+        "--max-depth",
+        type=_positive_int("--max-depth"),
+        metavar="N",
+        help=f"Calls below the --sequence-from entry point that are traced (default: {DEFAULT_MAX_DEPTH})",
+    )
+    shared.add_argument(
+        "--diagram-style",
+        choices=["component", "class"],
+        default="component",
+        help=(
+            "component (default): the architecture diagrams only; class: also write class_diagram.md, a Mermaid "
+            "classDiagram of every class, struct and interface with its fields, methods, inheritance/embedding "
+            "and associations"
+        ),
+    )
+    shared.add_argument("--title", help="Title of the top-level generated doc, in every output format")
+    shared.add_argument(
+        "--intro",
+        type=Path,
+        metavar="FILE",
+        help="Hand-written introduction (Markdown; reStructuredText for rst) placed before the generated content",
+    )
+    shared.add_argument(
+        "--doc-template",
+        action="append",
+        type=_doc_template,
+        metavar="FILE",
+        help=(
+            "Jinja2 layout for each component's section of the docs, named <name>.<format>.j2 (e.g. component.md.j2); "
+            "repeat for several formats. Starting points ship in output_generators/templates/ (needs Jinja2)"
+        ),
+    )
+    shared.add_argument(
+        "--prompt-dir",
+        type=_prompt_dir,
+        metavar="DIR",
+        help=(
+            "Directory with system.md and/or component.md replacing the built-in system prompt and per-component "
+            "prompt of the docs, with {variable} placeholders. Starting points ship in agents/prompts/templates/"
+        ),
+    )
     return shared
 
 
-def build_parser() -> argparse.ArgumentParser:
+def build_parser(project_defaults: dict | None = None) -> argparse.ArgumentParser:
     parser = argparse.ArgumentParser(
         prog="codeboarding",
         description="Generate onboarding documentation for Git repositories",
         formatter_class=argparse.RawDescriptionHelpFormatter,
         epilog="""
 `full` is the default command: when the first argument is not `full`,
-`incremental`, or `partial`, `full` is inserted automatically.
+`incremental`, `partial`, `watch`, `batch`, `ask`, `focus`, `impact`, or `cache`, `full` is inserted automatically.
 
 Examples:
   # Local full analysis (output to <repo>/.codeboarding/); `full` is implied
@@ -40,6 +482,10 @@ Examples:
   # is driven by structural separability, this only raises the safety-valve cap)
   codeboarding --local /path/to/repo --depth-level 5
 
+  # Price a full run from its static analysis before making any LLM request; cap the real run at $5
+  codeboarding --local /path/to/repo --estimate
+  codeboarding --local /path/to/repo --max-cost 5
+
   # Incremental update on a local repository (explicit subcommand required)
   codeboarding incremental --local /path/to/repo
 
@@ -49,8 +495,47 @@ Examples:
   # Partial update (single component by ID)
   codeboarding partial --local /path/to/repo --component-id "1.2"
 
+  # Re-analyze on every save and keep the HTML docs open at http://127.0.0.1:8765/ live
+  codeboarding watch --local /path/to/repo --serve
+
+  # NestJS service: add controller -> service injection and module registration edges
+  codeboarding --local /path/to/nest-app --framework nest
+
+  # Analyze one slice of the codebase, leaving out test helpers
+  codeboarding --local /path/to/repo --select "pkg(example.com/app/**) and not name(*_test)"
+
+  # Also write a chord diagram (dependency wheel) of component coupling
+  codeboarding --local /path/to/repo --format chord
+
+  # Also write a PlantUML sequence diagram of what main.main calls, three calls deep
+  codeboarding --local /path/to/go-service --sequence-from main.main --max-depth 3
+
+  # Ask a grounded question about one component of an existing analysis
+  codeboarding ask .codeboarding/analysis.json --component services "why does it depend on models?"
+
+  # Spotlight one function: its callers and callees two calls away, as a mermaid flowchart
+  codeboarding focus .codeboarding/analysis.json --symbol services.processor.dispatch --hops 2 --format mermaid
+
+  # Before changing a helper: every transitive caller and the components they sit in
+  codeboarding impact .codeboarding/analysis.json --symbol utils.helpers.add --max-depth 4
+
+  # See how much disk the caches use and how well they hit, then drop the LLM caches
+  codeboarding cache list --local /path/to/repo
+  codeboarding cache stats --local /path/to/repo
+  codeboarding cache clear --local /path/to/repo --type llm
+
   # Custom binary location (e.g. VS Code extension)
   codeboarding --local /path/to/repo --binary-location /path/to/binaries
+
+  # Sharded CI batch: run shard 2 of 4 with a shared cache, then merge the shard outputs
+  codeboarding https://github.com/a/x https://github.com/b/y --shard 2/4 --remote-cache s3://bucket/cb
+  codeboarding batch merge shard-1/ shard-2/ shard-3/ shard-4/ --output-dir merged/
+
+  # Org-wide listing where copy-pasted components across services appear once with a count
+  codeboarding batch merge shard-1/ shard-2/ --output-dir merged/ --dedupe-threshold 0.8
+
+Option defaults can be committed in <repo>/.codeboarding/config.toml under
+[options] (e.g. depth_level = 4). Precedence: built-in < project config < CLI flags.
         """,
     )
     shared = _build_shared_parser()
@@ -58,6 +543,15 @@ Examples:
     full_analysis.add_arguments(subparsers, parents=[shared])
     incremental_analysis.add_arguments(subparsers, parents=[shared])
     partial_analysis.add_arguments(subparsers, parents=[shared])
+    watch.add_arguments(subparsers, parents=[shared])
+    batch.add_arguments(subparsers, parents=[shared])
+    ask.add_arguments(subparsers, parents=[shared])
+    focus.add_arguments(subparsers, parents=[shared])
+    impact.add_arguments(subparsers, parents=[shared])
+    cache.add_arguments(subparsers, parents=[shared])
+    if project_defaults:
+        for subparser in subparsers.choices.values():
+            subparser.set_defaults(**project_defaults)
     return parser
 
 
@@ -83,6 +577,18 @@ def _dispatch(args: argparse.Namespace, parser: argparse.ArgumentParser) -> None
             incremental_analysis.run_from_args(args, parser)
         elif args.command == "partial":
             partial_analysis.run_from_args(args, parser)
+        elif args.command == "watch":
+            watch.run_from_args(args, parser)
+        elif args.command == "batch":
+            batch.run_from_args(args, parser)
+        elif args.command == "ask":
+            ask.run_from_args(args, parser)
+        elif args.command == "focus":
+            focus.run_from_args(args, parser)
+        elif args.command == "impact":
+            impact.run_from_args(args, parser)
+        elif args.command == "cache":
+            cache.run_from_args(args, parser)
         else:
             full_analysis.run_from_args(args, parser)
     except LLMAuthError as exc:
@@ -103,7 +609,31 @@ def main(argv: list[str] | None = None) -> None:
     argv = _inject_default_subcommand(list(argv))
     parser = build_parser()
     args = parser.parse_args(argv)
-    _dispatch(args, parser)
+    parser, args = _apply_project_config(parser, args, argv)
+    progress = configure_progress(getattr(args, "progress", None) or ProgressMode.QUIET)
+    try:
+        _dispatch(args, parser)
+    finally:
+        progress.summary()
+
+
+def _apply_project_config(
+    parser: argparse.ArgumentParser, args: argparse.Namespace, argv: list[str]
+) -> tuple[argparse.ArgumentParser, argparse.Namespace]:
+    """Re-parse with ``[options]`` from ``<repo>/.codeboarding/config.toml`` as defaults.
+
+    Why: installing the project values as argparse defaults (rather than patching
+    the namespace) keeps explicit CLI flags on top and still runs ``type=``
+    conversion on string values such as paths.
+    """
+    local = getattr(args, "local", None)
+    if local is None:
+        return parser, args
+    defaults = load_project_config(local.resolve()).cli_defaults(set(vars(args)))
+    if not defaults:
+        return parser, args
+    parser = build_parser(project_defaults=defaults)
+    return parser, parser.parse_args(argv)
 
 
 if __name__ == "__main__":
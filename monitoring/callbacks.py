@@ -42,6 +42,8 @@ class MonitoringCallback(BaseCallbackHandler):
 
     def on_llm_end(self, response: LLMResult, **_kwargs: Any) -> None:
         step_name = current_step.get()
+        with self.stats._lock:
+            self.stats.llm_calls += 1
 
         # Extract usage
         usage = self._extract_usage(response)
@@ -0,0 +1,222 @@
+"""
+Run progress for people and wrappers watching a long analysis.
+
+A run goes through phases (LSP startup, symbol extraction, reference
+resolution, LLM generation, rendering). Each reports its counts and elapsed
+time to the process-wide :class:`ProgressReporter`, which prints them in the
+``--progress`` mode:
+
+- ``bar``: a progress line per phase on stderr, a line when it ends, and the
+  time spent in each phase when the run ends;
+- ``json``: one JSON object per line on stderr (``phase_start``, ``progress``,
+  ``phase_end``, ``retry``, ``run_end``) for a wrapper or GUI to draw its own bar;
+- ``quiet``: nothing. The default outside the CLI.
+
+Waits between LLM retries are reported too, so a run sleeping through a rate
+limit does not look hung.
+"""
+
+import contextlib
+import json
+import sys
+import threading
+import time
+from collections.abc import Callable, Iterator
+from dataclasses import dataclass, field
+from enum import StrEnum
+from typing import TextIO
+
+# Seconds between two progress lines (bar) or events (json) of one phase.
+BAR_INTERVAL_SEC = 2.0
+JSON_INTERVAL_SEC = 0.5
+_BAR_WIDTH = 20
+
+
+class ProgressMode(StrEnum):
+    BAR = "bar"
+    JSON = "json"
+    QUIET = "quiet"
+
+
+class Phase(StrEnum):
+    LSP_STARTUP = "lsp-startup"
+    SYMBOLS = "symbols"
+    REFERENCES = "references"
+    LLM = "llm"
+    RENDERING = "rendering"
+
+
+@dataclass
+class _PhaseRecord:
+    """Counts and time of one phase, summed over every time it ran (one per language, say)."""
+
+    active: int = 0
+    started_at: float = 0.0
+    elapsed: float = 0.0
+    # unit -> [done, total]; ``None`` total: not known up front.
+    counts: dict[str, list[int | None]] = field(default_factory=dict)
+    unit: str = "item"
+    last_emit: float = float("-inf")
+
+    def count(self, unit: str) -> list[int | None]:
+        self.unit = unit
+        return self.counts.setdefault(unit, [0, None])
+
+
+def _plural(unit: str, n: int | None) -> str:
+    return unit if n == 1 or unit.endswith("s") else f"{unit}s"
+
+
+class ProgressReporter:
+    """Thread-safe progress sink; LLM workers and LSP phases report from several threads."""
+
+    def __init__(
+        self,
+        mode: ProgressMode = ProgressMode.QUIET,
+        stream: TextIO | None = None,
+        clock: Callable[[], float] = time.monotonic,
+    ) -> None:
+        self.mode = ProgressMode(mode)
+        self._stream = stream
+        self._clock = clock
+        self._lock = threading.Lock()
+        self._phases: dict[Phase, _PhaseRecord] = {}
+        self._run_start = clock()
+
+    def start(self, phase: Phase, total: int | None = None, unit: str = "item") -> None:
+        """Enter *phase*, expecting *total* more *unit*s; nested or repeated starts add up."""
+        with self._lock:
+            record = self._phases.setdefault(phase, _PhaseRecord())
+            self._add_total(record, total, unit)
+            record.active += 1
+            if record.active > 1:
+                return
+            record.started_at = self._clock()
+            self._emit("phase_start", phase, record, force=True)
+
+    def add_total(self, phase: Phase, n: int, unit: str = "item") -> None:
+        """*n* more *unit*s turned up in *phase*, e.g. a component expanded into children."""
+        with self._lock:
+            self._add_total(self._phases.setdefault(phase, _PhaseRecord()), n, unit)
+
+    def advance(self, phase: Phase, n: int = 1, unit: str = "item") -> None:
+        with self._lock:
+            record = self._phases.setdefault(phase, _PhaseRecord())
+            count = record.count(unit)
+            count[0] = (count[0] or 0) + n
+            self._emit("progress", phase, record)
+
+    def finish(self, phase: Phase) -> None:
+        """Leave *phase*; it ends when its last start is finished."""
+        with self._lock:
+            record = self._phases.get(phase)
+            if record is None or record.active == 0:
+                return
+            record.active -= 1
+            if record.active:
+                return
+            record.elapsed += self._clock() - record.started_at
+            self._emit("phase_end", phase, record, force=True)
+
+    @contextlib.contextmanager
+    def phase(self, phase: Phase, total: int | None = None, unit: str = "item") -> Iterator[None]:
+        self.start(phase, total, unit)
+        try:
+            yield
+        finally:
+            self.finish(phase)
+
+    def retry(self, operation: str, attempt: int, max_attempts: int, wait_s: float, error: object) -> None:
+        """*operation* failed on *attempt* (1-based) of *max_attempts*; the next try is *wait_s* away."""
+        with self._lock:
+            if self.mode == ProgressMode.JSON:
+                event = {"attempt": attempt, "max_attempts": max_attempts, "wait_s": round(wait_s, 1)}
+                self._write_json("retry", {"operation": operation, **event, "error": str(error)[:200]})
+            elif self.mode == ProgressMode.BAR:
+                self._write(
+                    f"[retry] {operation} failed (attempt {attempt}/{max_attempts}); "
+                    f"waiting {wait_s:.1f}s before trying again: {str(error)[:120]}"
+                )
+
+    def summary(self) -> None:
+        """Report the time spent in each phase; call once when the run ends."""
+        with self._lock:
+            now = self._clock()
+            timings = {phase: self._elapsed(record, now) for phase, record in self._phases.items()}
+            total = now - self._run_start
+            if self.mode == ProgressMode.JSON:
+                phases = {str(phase): round(elapsed, 1) for phase, elapsed in timings.items()}
+                self._write_json("run_end", {"elapsed_s": round(total, 1), "phases": phases})
+            elif self.mode == ProgressMode.BAR and timings:
+                width = max(len(phase) for phase in timings)
+                lines = ["Phase timings:"]
+                for phase, elapsed in timings.items():
+                    counts = self._counts(self._phases[phase])
+                    lines.append(f"  {phase:<{width}}  {elapsed:7.1f}s  {counts}".rstrip())
+                lines.append(f"  {'total':<{width}}  {total:7.1f}s")
+                self._write("\n".join(lines))
+
+    def _add_total(self, record: _PhaseRecord, total: int | None, unit: str) -> None:
+        count = record.count(unit)
+        if total is not None:
+            count[1] = (count[1] or 0) + total
+
+    def _elapsed(self, record: _PhaseRecord, now: float) -> float:
+        return record.elapsed + (now - record.started_at if record.active else 0.0)
+
+    def _emit(self, event: str, phase: Phase, record: _PhaseRecord, force: bool = False) -> None:
+        if self.mode == ProgressMode.QUIET:
+            return
+        now = self._clock()
+        interval = JSON_INTERVAL_SEC if self.mode == ProgressMode.JSON else BAR_INTERVAL_SEC
+        if not force and now - record.last_emit < interval:
+            return
+        record.last_emit = now
+        done, total = record.counts.get(record.unit, [0, None])
+        elapsed = self._elapsed(record, now)
+        if self.mode == ProgressMode.JSON:
+            payload = {"phase": str(phase), "done": done, "total": total, "unit": record.unit}
+            self._write_json(event, {**payload, "elapsed_s": round(elapsed, 1)})
+        elif event == "phase_start":
+            self._write(f"[{phase}] started")
+        elif event == "phase_end":
+            counts = self._counts(record)
+            self._write(f"[{phase}] done{': ' + counts if counts else ''} in {elapsed:.1f}s")
+        else:
+            self._write(f"[{phase}] {self._bar(done or 0, total, record.unit)}, {elapsed:.1f}s")
+
+    @staticmethod
+    def _bar(done: int, total: int | None, unit: str) -> str:
+        if not total:
+            return f"{done} {_plural(unit, done)}"
+        fraction = min(done / total, 1.0)
+        filled = int(fraction * _BAR_WIDTH)
+        bar = "#" * filled + "." * (_BAR_WIDTH - filled)
+        return f"[{bar}] {int(fraction * 100):3d}% {done}/{total} {_plural(unit, total)}"
+
+    @staticmethod
+    def _counts(record: _PhaseRecord) -> str:
+        return ", ".join(f"{done} {_plural(unit, done)}" for unit, (done, _) in record.counts.items() if done)
+
+    def _write_json(self, event: str, payload: dict) -> None:
+        self._write(json.dumps({"event": event, **payload, "time": round(time.time(), 3)}))
+
+    def _write(self, line: str) -> None:
+        # Why: resolved per write so a replaced ``sys.stderr`` (pytest capture, a wrapper) is honoured.
+        stream = self._stream or sys.stderr
+        stream.write(line + "\n")
+        stream.flush()
+
+
+_reporter = ProgressReporter()
+
+
+def configure_progress(mode: ProgressMode | str, stream: TextIO | None = None) -> ProgressReporter:
+    """Install the process-wide reporter for ``--progress``; the run's clock starts here."""
+    global _reporter
+    _reporter = ProgressReporter(ProgressMode(mode), stream)
+    return _reporter
+
+
+def get_progress() -> ProgressReporter:
+    return _reporter
@@ -18,6 +18,7 @@ class RunStats:
         """Reset all statistics to initial state."""
         with self._lock:
             self.model_name = None
+            self.llm_calls = 0
             self.total_tokens = 0
             self.input_tokens = 0
             self.output_tokens = 0
@@ -30,6 +31,7 @@ class RunStats:
         with self._lock:
             return {
                 "model_name": self.model_name,
+                "llm_calls": self.llm_calls,
                 "token_usage": {
                     "total_tokens": self.total_tokens,
                     "input_tokens": self.input_tokens,
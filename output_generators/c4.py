@@ -0,0 +1,250 @@
+"""C4 model of an analysis as a Structurizr DSL workspace (``--format c4``).
+
+The analysed repository is the C4 *software system*. Its packages (source
+directories, dot-joined like the static analysis names them) are the
+*containers*, and each top-level component sits in the package holding most of
+its files. Components marked external become separate software systems.
+
+* Container relationships: package imports from the static analysis
+  (``static_analysis.pkl``), plus any package boundary a component call crosses.
+* Component relationships: the calls recorded in ``analysis.json`` that cross a
+  package boundary, labelled with the called functions' names.
+
+``--c4-level`` picks how deep the model and its views go: ``context`` (the
+system and the external systems it uses), ``container`` (adds packages) or
+``component`` (adds components, with one component view per package).
+Render with the Structurizr CLI or Lite (``structurizr-cli export -w c4.dsl -f plantuml/c4plantuml``).
+"""
+
+import os
+import re
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+
+from agents.agent_responses import AnalysisInsights, Component
+from static_analyzer.analysis_result import StaticAnalysisResults
+
+C4_FILENAME = "c4.dsl"
+C4_LEVELS = ("context", "container", "component")
+DEFAULT_C4_LEVEL = "component"
+
+SYSTEM_ID = "system"
+# Called function names listed on one component relationship before "and N more".
+_MAX_LABEL_NAMES = 3
+
+
+@dataclass
+class C4Model:
+    name: str
+    description: str
+    # Container (package) -> components placed in it; external components are their own systems.
+    containers: dict[str, list[Component]] = field(default_factory=dict)
+    external: list[Component] = field(default_factory=list)
+    # (source package, destination package) of imports and of cross-package calls.
+    container_relations: set[tuple[str, str]] = field(default_factory=set)
+    # (source component id, destination component id) -> called function names.
+    component_relations: dict[tuple[str, str], set[str]] = field(default_factory=dict)
+
+
+def package_for_file(file_path: str) -> str:
+    """The file's directory, dot-joined; a root-level file is its own package (its stem)."""
+    path = PurePosixPath(file_path)
+    if path.parent.parts and path.parent != PurePosixPath("."):
+        return ".".join(path.parent.parts)
+    return path.stem
+
+
+def _relative(file_path: str, repo_dir: Path | None) -> str:
+    if repo_dir is None or not os.path.isabs(file_path):
+        return Path(file_path).as_posix()
+    relative = os.path.relpath(file_path, repo_dir)
+    return Path(file_path).as_posix() if relative.startswith("..") else Path(relative).as_posix()
+
+
+def _home_package(component: Component, repo_dir: Path | None) -> str | None:
+    """The package holding most of the component's files; ties go to the first in name order."""
+    counts: dict[str, int] = {}
+    for file_path in component.file_paths():
+        package = package_for_file(_relative(file_path, repo_dir))
+        counts[package] = counts.get(package, 0) + 1
+    if not counts:
+        return None
+    return min(counts, key=lambda package: (-counts[package], package))
+
+
+def _symbol_files(analysis: AnalysisInsights) -> dict[str, str]:
+    """Qualified name -> file for every method the analysis lists."""
+    files: dict[str, str] = {}
+    for file_path, entry in analysis.files.items():
+        for method in entry.methods:
+            files.setdefault(method.qualified_name, file_path)
+    for component in analysis.components:
+        for group in component.file_methods:
+            for method in group.methods:
+                files.setdefault(method.qualified_name, group.file_path)
+    return files
+
+
+def build_c4_model(
+    name: str,
+    analysis: AnalysisInsights,
+    static_analysis: StaticAnalysisResults | None = None,
+    repo_dir: Path | None = None,
+) -> C4Model:
+    """Containers, components and relationships of the top-level *analysis*."""
+    model = C4Model(name=name, description=analysis.description)
+    home: dict[str, str] = {}
+    for component in analysis.components:
+        package = _home_package(component, repo_dir)
+        if component.external or package is None:
+            model.external.append(component)
+            continue
+        model.containers.setdefault(package, []).append(component)
+        home[component.component_id] = package
+
+    if static_analysis is not None:
+        for language in static_analysis.get_languages():
+            try:
+                dependencies = static_analysis.get_package_dependencies(language)
+            except ValueError:
+                continue
+            for package, info in dependencies.items():
+                for imported in info.get("imports", []):
+                    if package != imported and package in model.containers and imported in model.containers:
+                        model.container_relations.add((package, imported))
+
+    files = _symbol_files(analysis)
+    external_ids = {component.component_id for component in model.external}
+    for relation in analysis.components_relations:
+        if relation.src_id == relation.dst_id or not relation.src_id or not relation.dst_id:
+            continue
+        # Calls into or out of an external system always cross the system boundary.
+        crosses_system = relation.src_id in external_ids or relation.dst_id in external_ids
+        for edge in relation.all_edges or relation.key_edges:
+            if not crosses_system:
+                source_file = edge.source.reference_file or files.get(edge.source.qualified_name)
+                target_file = edge.target.reference_file or files.get(edge.target.qualified_name)
+                if source_file is None or target_file is None:
+                    continue
+                source_package = package_for_file(_relative(source_file, repo_dir))
+                if source_package == package_for_file(_relative(target_file, repo_dir)):
+                    continue
+            called = re.split(r"[.:]", edge.target.qualified_name)[-1]
+            model.component_relations.setdefault((relation.src_id, relation.dst_id), set()).add(called)
+            src_home, dst_home = home.get(relation.src_id), home.get(relation.dst_id)
+            if src_home is not None and dst_home is not None and src_home != dst_home:
+                model.container_relations.add((src_home, dst_home))
+    return model
+
+
+def _quote(text: str) -> str:
+    return '"' + " ".join(text.split()).replace("\\", "\\\\").replace('"', '\\"') + '"'
+
+
+def _identifier(prefix: str, key: str) -> str:
+    return prefix + "_" + re.sub(r"\W", "_", key)
+
+
+def _container_id(package: str) -> str:
+    return _identifier("container", package)
+
+
+def _component_id(component_id: str) -> str:
+    return _identifier("component", component_id)
+
+
+def _external_id(component_id: str) -> str:
+    return _identifier("external", component_id)
+
+
+def _calls_label(names: set[str]) -> str:
+    ordered = sorted(names)
+    label = ", ".join(ordered[:_MAX_LABEL_NAMES])
+    if len(ordered) > _MAX_LABEL_NAMES:
+        label += f" and {len(ordered) - _MAX_LABEL_NAMES} more"
+    return f"Calls {label}"
+
+
+def generate_structurizr_dsl(model: C4Model, level: str = DEFAULT_C4_LEVEL) -> str:
+    """A Structurizr DSL workspace of *model*, down to *level* (see :data:`C4_LEVELS`)."""
+    if level not in C4_LEVELS:
+        raise ValueError(f"Unknown C4 level '{level}', expected one of: {', '.join(C4_LEVELS)}")
+    with_containers = level in ("container", "component")
+    with_components = level == "component"
+    component_homes = {
+        component.component_id: package
+        for package, components in model.containers.items()
+        for component in components
+    }
+    external_ids = {component.component_id for component in model.external}
+
+    def element(component_id: str) -> str | None:
+        """The model element a component's relationship attaches to at this level."""
+        if component_id in external_ids:
+            return _external_id(component_id)
+        package = component_homes.get(component_id)
+        if package is None:
+            return None
+        if with_components:
+            return _component_id(component_id)
+        return _container_id(package) if with_containers else SYSTEM_ID
+
+    lines = [f"workspace {_quote(model.name)} {_quote(model.description)} {{", "", "    model {"]
+    system = f"        {SYSTEM_ID} = softwareSystem {_quote(model.name)} {_quote(model.description)}"
+    if with_containers and model.containers:
+        lines.append(system + " {")
+        for package in sorted(model.containers):
+            container = f"            {_container_id(package)} = container {_quote(package)} {_quote('Package')}"
+            if not with_components:
+                lines.append(container)
+                continue
+            lines.append(container + " {")
+            for component in sorted(model.containers[package], key=lambda c: c.component_id):
+                lines.append(
+                    f"                {_component_id(component.component_id)} = component "
+                    f"{_quote(component.name)} {_quote(component.description)}"
+                )
+            lines.append("            }")
+        lines.append("        }")
+    else:
+        lines.append(system)
+    for component in sorted(model.external, key=lambda c: c.component_id):
+        lines.append(
+            f"        {_external_id(component.component_id)} = softwareSystem "
+            f'{_quote(component.name)} {_quote(component.description)} "External"'
+        )
+
+    relationships: dict[tuple[str, str], str] = {}
+    if with_containers:
+        for source, destination in sorted(model.container_relations):
+            relationships[(_container_id(source), _container_id(destination))] = "Uses"
+    for (source_id, destination_id), called in sorted(model.component_relations.items()):
+        source, destination = element(source_id), element(destination_id)
+        if source is None or destination is None or source == destination:
+            continue
+        if with_components or source_id in external_ids or destination_id in external_ids:
+            relationships.setdefault((source, destination), _calls_label(called))
+    if relationships:
+        lines.append("")
+    for (source, destination), label in sorted(relationships.items()):
+        lines.append(f"        {source} -> {destination} {_quote(label)}")
+    lines.append("    }")
+
+    lines += ["", "    views {"]
+    # View keys are identifiers: letters, digits, underscores and hyphens only.
+    views = [f'        systemContext {SYSTEM_ID} "Context" {{']
+    if with_containers and model.containers:
+        views.append(f'        container {SYSTEM_ID} "Containers" {{')
+    if with_components:
+        for package in sorted(model.containers):
+            views.append(f'        component {_container_id(package)} "{_identifier("Components", package)}" {{')
+    for view in views:
+        lines += [view, "            include *", "            autolayout lr", "        }"]
+    lines += ["    }", "}"]
+    return "\n".join(lines) + "\n"
+
+
+def write_c4_file(model: C4Model, output_path: Path, level: str = DEFAULT_C4_LEVEL) -> Path:
+    """Write the Structurizr DSL workspace to *output_path*; returns it."""
+    output_path.write_text(generate_structurizr_dsl(model, level), encoding="utf-8")
+    return output_path
@@ -0,0 +1,122 @@
+"""Dependency-wheel (chord) view of component coupling.
+
+A node-link graph of many components turns into a hairball; a chord diagram
+lays the components around a circle instead, so the overall coupling structure
+reads at a glance. Each arc is sized by the component's total coupling (in +
+out), and each ribbon by the number of static edges between two components.
+``chord.json`` carries the same data for any other chord renderer.
+"""
+
+import html
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Relation
+
+CHORD_HTML_FILENAME = "chord.html"
+CHORD_JSON_FILENAME = "chord.json"
+
+
+def _edge_weight(relation: Relation) -> int:
+    """Static edges behind the relation; an LLM-only relation still counts once."""
+    return max(1, len(relation.all_edges))
+
+
+def build_chord_data(analysis: AnalysisInsights) -> dict:
+    """Square ``matrix[src][dst]`` of edge weights over *analysis*'s components.
+
+    *analysis* must carry relations already projected onto its own components
+    (see ``codeboarding_workflows.rendering.project_relations_to_level``).
+    """
+    components = sorted(analysis.components, key=lambda c: c.component_id)
+    index = {c.component_id: i for i, c in enumerate(components)}
+    matrix = [[0] * len(components) for _ in components]
+    for rel in analysis.components_relations:
+        src, dst = index.get(rel.src_id), index.get(rel.dst_id)
+        if src is not None and dst is not None and src != dst:
+            matrix[src][dst] += _edge_weight(rel)
+
+    return {
+        "components": [
+            {
+                "id": c.component_id,
+                "name": c.name,
+                "outgoing": sum(matrix[i]),
+                "incoming": sum(row[i] for row in matrix),
+            }
+            for i, c in enumerate(components)
+        ],
+        "matrix": matrix,
+    }
+
+
+def generate_chord_html(project: str, chord_data: dict) -> str:
+    """Self-contained page rendering *chord_data* with ``d3.chordDirected``."""
+    title = html.escape(project)
+    # Why: ``</script>`` inside a component name would otherwise end the inline script.
+    data_json = json.dumps(chord_data).replace("</", "<\\/")
+    return f"""<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>CodeBoarding Coupling - {title}</title>
+    <script src="https://unpkg.com/d3@7.9.0/dist/d3.min.js"></script>
+    <style>
+        body {{ font-family: Arial, sans-serif; margin: 0 auto; padding: 20px; background-color: #f8f9fa; }}
+        #chord {{ display: block; margin: 0 auto; }}
+        .group-label {{ font-size: 12px; fill: #495057; }}
+        .ribbon {{ fill-opacity: 0.65; stroke: #ffffff; stroke-width: 0.5; }}
+        .faded {{ opacity: 0.08; }}
+    </style>
+</head>
+<body>
+    <h1>{title} - component coupling</h1>
+    <p>Arc size is a component's total coupling (incoming + outgoing edges); ribbons show the edges between
+    two components, colored by their source. Hover an arc to isolate its dependencies.</p>
+    <svg id="chord"></svg>
+    <script>
+        const data = {data_json};
+        const size = Math.max(640, Math.min(1100, 240 + 24 * data.components.length));
+        const outer = size / 2 - 140, inner = outer - 14;
+        const svg = d3.select("#chord").attr("width", size).attr("height", size)
+            .attr("viewBox", [-size / 2, -size / 2, size, size]);
+        const color = d3.scaleOrdinal(d3.schemeTableau10);
+        const chords = d3.chordDirected().padAngle(0.04).sortSubgroups(d3.descending)(data.matrix);
+        const arc = d3.arc().innerRadius(inner).outerRadius(outer);
+        const ribbon = d3.ribbonArrow().radius(inner - 1).padAngle(1 / inner);
+
+        const ribbons = svg.append("g").selectAll("path").data(chords).join("path")
+            .attr("class", "ribbon").attr("d", ribbon)
+            .attr("fill", d => color(d.source.index));
+        const names = data.components.map(c => c.name);
+        ribbons.append("title")
+            .text(d => `${{names[d.source.index]}} -> ${{names[d.target.index]}}: ${{d.source.value}}`);
+
+        const groups = svg.append("g").selectAll("g").data(chords.groups).join("g");
+        const isolate = g => ribbons.classed("faded", d => d.source.index !== g.index && d.target.index !== g.index);
+        groups.append("path").attr("d", arc).attr("fill", d => color(d.index))
+            .on("mouseover", (_, g) => isolate(g))
+            .on("mouseout", () => ribbons.classed("faded", false))
+            .append("title").text(d => {{
+                const c = data.components[d.index];
+                return `${{c.id}} ${{c.name}}\\noutgoing: ${{c.outgoing}}, incoming: ${{c.incoming}}`;
+            }});
+        groups.append("text").attr("class", "group-label").attr("dy", "0.35em")
+            .each(d => {{ d.angle = (d.startAngle + d.endAngle) / 2; }})
+            .attr("transform", d => `rotate(${{d.angle * 180 / Math.PI - 90}}) translate(${{outer + 6}})`
+                + (d.angle > Math.PI ? " rotate(180)" : ""))
+            .attr("text-anchor", d => d.angle > Math.PI ? "end" : null)
+            .text(d => names[d.index]);
+    </script>
+</body>
+</html>"""
+
+
+def write_chord_files(project: str, analysis: AnalysisInsights, output_dir: Path) -> Path:
+    """Write ``chord.json`` and ``chord.html`` into *output_dir*; returns the HTML path."""
+    chord_data = build_chord_data(analysis)
+    (output_dir / CHORD_JSON_FILENAME).write_text(json.dumps(chord_data, indent=2), encoding="utf-8")
+    html_path = output_dir / CHORD_HTML_FILENAME
+    html_path.write_text(generate_chord_html(project, chord_data), encoding="utf-8")
+    return html_path
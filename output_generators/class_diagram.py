@@ -0,0 +1,290 @@
+"""Mermaid class diagram of the analysed types (``--diagram-style class``), notation in PYPI.md.
+
+Drawn from the saved static analysis only: Go methods find their receiver type by the ``(Recv)`` /
+``(*Recv)`` part of their qualified name, within the receiver's package directory.
+"""
+
+import logging
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES, NodeType, ReceiverKind
+from static_analyzer.go_enums import enum_constants
+from static_analyzer.graph import EdgeKind
+from static_analyzer.node import Node, go_receiver_and_method
+
+logger = logging.getLogger(__name__)
+
+CLASS_DIAGRAM_FILENAME = "class_diagram.md"
+
+_FIELD_TYPES = {NodeType.FIELD, NodeType.PROPERTY}
+_ANNOTATIONS = {NodeType.INTERFACE: "interface", NodeType.ENUM: "enumeration"}
+_IDENT = r"[A-Za-z_]\w*"
+# ``Entity`` / ``*Entity`` / ``models.Entity``: a Go field that is only a type, i.e. an embedding.
+_EMBEDDED_RE = re.compile(rf"^\*?(?:{_IDENT}\.)?({_IDENT})$")
+# ``typeName string`` / ``a, b int``: a named Go field and its type.
+_GO_FIELD_RE = re.compile(rf"^{_IDENT}(?:\s*,\s*{_IDENT})*\s+(.+)$")
+
+
+@dataclass
+class ClassBox:
+    """One type of the diagram with its members, in source order."""
+
+    qualified_name: str
+    node_type: NodeType
+    fields: list[str] = field(default_factory=list)
+    methods: list[str] = field(default_factory=list)
+    # The constants of an enumeration with their values: ``+PriorityLow = 0``.
+    enum_members: list[str] = field(default_factory=list)
+    # A named function type (``type HandlerFunc func(int) int``); its signature is its one member.
+    function_type: bool = False
+    # Standard interfaces the type satisfies: ``fmt.Stringer``, ``error``.
+    standard_interfaces: tuple[str, ...] = ()
+
+    @property
+    def name(self) -> str:
+        return self.qualified_name.rsplit(".", 1)[-1]
+
+
+@dataclass
+class ClassDiagram:
+    classes: dict[str, ClassBox] = field(default_factory=dict)
+    # (base, derived) for ``<|--`` and (owner, target, label) for ``-->``.
+    inheritance: set[tuple[str, str]] = field(default_factory=set)
+    # The (base, derived) pairs of ``inheritance`` that are Go struct embeddings.
+    embeddings: set[tuple[str, str]] = field(default_factory=set)
+    associations: set[tuple[str, str, str]] = field(default_factory=set)
+
+    def add_association(self, owner: str, target: str, label: str = "") -> None:
+        if owner != target and owner in self.classes and target in self.classes:
+            self.associations.add((owner, target, label))
+
+
+def _is_go(node: Node) -> bool:
+    return node.file_path.endswith(".go")
+
+
+def visibility(node: Node) -> str:
+    """``+`` for an exported member, ``-`` for an unexported one."""
+    name = node.fully_qualified_name.rsplit(".", 1)[-1]
+    if _is_go(node):
+        return "+" if name[:1].isupper() else "-"
+    return "-" if name.startswith("_") else "+"
+
+
+def method_signature(node: Node, name: str) -> str:
+    """*name* with its parameters and results; ``Name()`` when no signature was read.
+
+    Mermaid takes what follows the ``)`` as the return type, so the results drop
+    Go's parentheses and keep their names: ``Clamp(value, min, max int) result int``.
+    """
+    signature = node.signature
+    if signature is None:
+        return f"{name}()"
+    results = ", ".join(map(str, signature.results))
+    return f"{name}({signature.parameters_str()}) {results}".rstrip()
+
+
+class _Sources:
+    """Lazily read source lines, by path."""
+
+    def __init__(self, repo_dir: Path | None) -> None:
+        self._repo_dir = repo_dir
+        self._lines: dict[str, list[str]] = {}
+
+    def path(self, file_path: str) -> Path:
+        path = Path(file_path)
+        return self._repo_dir / path if not path.is_absolute() and self._repo_dir is not None else path
+
+    def line(self, file_path: str, line_number: int) -> str:
+        if file_path not in self._lines:
+            path = self.path(file_path)
+            try:
+                self._lines[file_path] = path.read_text(encoding="utf-8", errors="replace").splitlines()
+            except OSError as e:
+                logger.debug(f"Class diagram: cannot read {file_path}: {e}")
+                self._lines[file_path] = []
+        lines = self._lines[file_path]
+        return lines[line_number - 1] if 0 < line_number <= len(lines) else ""
+
+
+def _go_field_declaration(line: str) -> str:
+    """The field declaration on *line* without its struct tag or trailing comment."""
+    return line.split("`", 1)[0].split("//", 1)[0].strip()
+
+
+def _package(node: Node) -> str:
+    return str(Path(node.file_path).parent)
+
+
+def _type_index(classes: dict[str, Node]) -> tuple[dict[tuple[str, str], str], dict[str, list[str]]]:
+    """(package dir, type name) -> qualified name, and type name -> qualified names."""
+    by_package: dict[tuple[str, str], str] = {}
+    by_name: dict[str, list[str]] = {}
+    for qname, node in classes.items():
+        name = qname.rsplit(".", 1)[-1]
+        by_package.setdefault((_package(node), name), qname)
+        by_name.setdefault(name, []).append(qname)
+    return by_package, by_name
+
+
+def _owner(node: Node, classes: dict[str, Node], by_package: dict[tuple[str, str], str]) -> str | None:
+    """Qualified name of the type *node* is a member of, if it is drawn."""
+    qname = node.fully_qualified_name
+    member = go_receiver_and_method(qname)
+    if member is not None:
+        return by_package.get((_package(node), member[0]))
+    parent = qname.rsplit(".", 1)[0]
+    return parent if parent in classes and parent != qname else None
+
+
+def _resolve_type(
+    name: str, near: Node, by_package: dict[tuple[str, str], str], by_name: dict[str, list[str]]
+) -> str | None:
+    """The drawn type a short *name* refers to: the one in *near*'s package, else the only one of that name."""
+    qname = by_package.get((_package(near), name))
+    if qname is not None:
+        return qname
+    candidates = by_name.get(name, [])
+    return candidates[0] if len(candidates) == 1 else None
+
+
+def _add_enum_members(
+    diagram: ClassDiagram, classes: dict[str, Node], by_package: dict[tuple[str, str], str], sources: _Sources
+) -> None:
+    """Group the typed constants of each Go package under their named type, which becomes an enumeration."""
+    packages = sorted({_package(node) for node in classes.values() if _is_go(node)})
+    for package in packages:
+        for go_file in sorted(sources.path(package).glob("*.go")):
+            if go_file.name.endswith("_test.go"):
+                continue
+            try:
+                source = go_file.read_text(encoding="utf-8", errors="replace")
+            except OSError as e:
+                logger.debug(f"Class diagram: cannot read {go_file}: {e}")
+                continue
+            for constant in enum_constants(source):
+                qname = by_package.get((package, constant.type_name))
+                if qname is None:
+                    continue
+                box = diagram.classes[qname]
+                if box.function_type or box.node_type == NodeType.INTERFACE:
+                    continue
+                box.node_type = NodeType.ENUM
+                member = f"{'+' if constant.name[:1].isupper() else '-'}{constant.name} = {constant.value}"
+                box.enum_members.append(member)
+
+
+def build_class_diagram(static_analysis: StaticAnalysisResults, repo_dir: Path | None = None) -> ClassDiagram:
+    """Types, members and relations from every language of *static_analysis*."""
+    diagram = ClassDiagram()
+    sources = _Sources(repo_dir)
+    for language in static_analysis.get_languages():
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        members: dict[str, Node] = {}
+        for node in [*cfg.nodes.values(), *static_analysis.iter_reference_nodes(language)]:
+            members.setdefault(node.fully_qualified_name, node)
+        classes = {qname: node for qname, node in members.items() if node.type in CLASS_TYPES}
+        for qname, node in classes.items():
+            box = diagram.classes[qname] = ClassBox(
+                qname,
+                node.type,
+                function_type=node.signature is not None,
+                standard_interfaces=node.standard_interfaces,
+            )
+            if box.function_type:
+                box.methods.append(f"+{method_signature(node, 'func')}")
+        by_package, by_name = _type_index(classes)
+        _add_enum_members(diagram, classes, by_package, sources)
+
+        for node in sorted(members.values(), key=lambda n: (n.file_path, n.line_start, n.fully_qualified_name)):
+            if node.type not in CALLABLE_TYPES and node.type not in _FIELD_TYPES:
+                continue
+            owner = _owner(node, classes, by_package)
+            if owner is None:
+                continue
+            box = diagram.classes[owner]
+            name = node.fully_qualified_name.rsplit(".", 1)[-1]
+            if node.type in CALLABLE_TYPES:
+                pointer = " «pointer»" if node.receiver_kind == ReceiverKind.POINTER else ""
+                box.methods.append(f"{visibility(node)}{method_signature(node, name)}{pointer}")
+                continue
+            if not _is_go(node):
+                box.fields.append(f"{visibility(node)}{name}")
+                continue
+            declaration = _go_field_declaration(sources.line(node.file_path, node.line_start))
+            embedded = _EMBEDDED_RE.match(declaration)
+            if embedded is not None and embedded.group(1) == name:
+                base = _resolve_type(name, node, by_package, by_name)
+                if base is not None and base != owner:
+                    diagram.inheritance.add((base, owner))
+                    diagram.embeddings.add((base, owner))
+                    continue
+            typed = _GO_FIELD_RE.match(declaration)
+            field_type = typed.group(1).strip() if typed is not None else ""
+            box.fields.append(f"{visibility(node)}{name} {field_type}".rstrip())
+            for type_name in re.findall(_IDENT, field_type):
+                target = _resolve_type(type_name, node, by_package, by_name)
+                if target is not None:
+                    diagram.add_association(owner, target, name)
+
+        for src, dst, kind in cfg.reference_edges:
+            if src not in diagram.classes or dst not in diagram.classes or src == dst:
+                continue
+            if kind in (EdgeKind.INHERITS, EdgeKind.IMPLEMENTS):
+                diagram.inheritance.add((dst, src))
+            elif kind == EdgeKind.EMBEDS:
+                diagram.inheritance.add((dst, src))
+                diagram.embeddings.add((dst, src))
+            elif kind == EdgeKind.TYPEREF:
+                diagram.add_association(src, dst)
+    # An association already drawn as inheritance adds nothing.
+    diagram.associations = {
+        (owner, target, label)
+        for owner, target, label in diagram.associations
+        if (target, owner) not in diagram.inheritance
+    }
+    return diagram
+
+
+def _class_id(qualified_name: str) -> str:
+    return re.sub(r"\W", "_", qualified_name)
+
+
+def _label(text: str) -> str:
+    return text.replace('"', "'")
+
+
+def generate_class_diagram(diagram: ClassDiagram) -> str:
+    """Mermaid ``classDiagram`` source for *diagram*."""
+    lines = ["classDiagram"]
+    for qname in sorted(diagram.classes):
+        box = diagram.classes[qname]
+        class_id = _class_id(qname)
+        lines.append(f'    class {class_id}["{_label(box.name)}"]')
+        annotation = "function" if box.function_type else _ANNOTATIONS.get(box.node_type)
+        # Mermaid takes one annotation per class, so the stereotypes share it.
+        stereotypes = [*([annotation] if annotation is not None else []), *box.standard_interfaces]
+        if stereotypes:
+            lines.append(f"    <<{', '.join(stereotypes)}>> {class_id}")
+        for member in [*box.enum_members, *box.fields, *box.methods]:
+            lines.append(f"    {class_id} : {_label(member)}")
+    for base, derived in sorted(diagram.inheritance):
+        lines.append(f"    {_class_id(base)} <|-- {_class_id(derived)}")
+    for owner, target, label in sorted(diagram.associations):
+        suffix = f" : {label}" if label else ""
+        lines.append(f"    {_class_id(owner)} --> {_class_id(target)}{suffix}")
+    return "\n".join(lines) + "\n"
+
+
+def write_class_diagram(diagram: ClassDiagram, output_path: Path, title: str) -> Path:
+    """Write the diagram as a Markdown page with one ``mermaid`` block; returns *output_path*."""
+    output_path.parent.mkdir(parents=True, exist_ok=True)
+    body = f"# {title} class diagram\n\n```mermaid\n{generate_class_diagram(diagram)}```\n"
+    output_path.write_text(body, encoding="utf-8")
+    return output_path
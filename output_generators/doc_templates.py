@@ -0,0 +1,186 @@
+"""User-supplied Jinja2 layouts for each component's section of the docs (``--doc-template``).
+
+A template is named after the format it renders, ``<anything>.<format>.j2``
+(``component.md.j2``, ``component.html.j2``, ``component.mdx.j2``,
+``component.rst.j2``), and replaces the built-in per-component section of every
+doc written in that format; the diagram, badges and ``--title``/``--intro``
+around it are unchanged. Starting points reproducing the built-in layout ship
+in ``output_generators/templates/``.
+
+Each render receives:
+
+- ``component``: ``name``, ``id``, ``slug`` (the file stem of its expanded doc),
+  ``description``, ``expanded``, ``external``, ``deprecated``, ``key_entities``
+  (``name``/``file``/``start_line``/``end_line``), ``files`` (``path`` plus
+  ``methods`` with ``name``/``kind``/``signature``/``start_line``/``end_line``),
+  ``depends_on`` and ``used_by`` (``name``/``relation``), ``metrics``
+  (``files``/``methods``/``lines``), ``owners``, ``tests`` and ``not_described``
+  (why it was not expanded, e.g. ``call budget reached``; empty otherwise).
+- ``analysis``: ``description`` and ``components`` (every component's name).
+- ``format`` (``.md``, ...) and ``repo_ref`` (the link prefix the docs use).
+
+Undefined variables are errors rather than silently empty, so a typo in a
+template fails the render instead of dropping content.
+"""
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any
+
+from agents.agent_responses import AnalysisInsights, Component
+from static_analyzer.symbol_kinds import kind_label
+from utils import sanitize
+
+# Jinja2 ships in the optional ``templates`` extra; only --doc-template needs it.
+try:
+    import jinja2
+
+    JINJA2_AVAILABLE = True
+except ImportError:
+    JINJA2_AVAILABLE = False
+    jinja2 = None  # type: ignore[assignment]
+
+TEMPLATE_FORMATS = (".md", ".mdx", ".html", ".rst")
+DEFAULT_TEMPLATES_DIR = Path(__file__).parent / "templates"
+
+
+class DocTemplateError(ValueError):
+    pass
+
+
+def template_format(path: Path) -> str:
+    """The output format a ``<name>.<format>.j2`` template renders."""
+    fmt = Path(path.stem).suffix
+    if path.suffix != ".j2" or fmt not in TEMPLATE_FORMATS:
+        raise DocTemplateError(
+            f"{path.name}: doc templates are named <name>.<format>.j2 with format one of {', '.join(TEMPLATE_FORMATS)}"
+        )
+    return fmt
+
+
+def component_context(insights: AnalysisInsights, comp: Component, expanded_components: set[str]) -> dict[str, Any]:
+    """The ``component`` variable: plain data, so templates don't depend on the model classes."""
+    files = [
+        {
+            "path": group.file_path,
+            "methods": [
+                {
+                    "name": method.qualified_name,
+                    "kind": kind_label(method.node_type, method.kind),
+                    "signature": method.signature,
+                    "start_line": method.start_line,
+                    "end_line": method.end_line,
+                }
+                for method in group.methods
+            ],
+        }
+        for group in comp.file_methods
+    ]
+    methods = [method for group in comp.file_methods for method in group.methods]
+    return {
+        "name": comp.name,
+        "id": comp.component_id,
+        "slug": sanitize(comp.name),
+        "description": comp.description,
+        "expanded": comp.component_id in expanded_components,
+        "external": comp.external,
+        "deprecated": comp.deprecated,
+        "key_entities": [
+            {
+                "name": reference.qualified_name,
+                "file": reference.reference_file,
+                "start_line": reference.reference_start_line,
+                "end_line": reference.reference_end_line,
+            }
+            for reference in comp.key_entities
+        ],
+        "files": files,
+        "depends_on": [
+            {"name": rel.dst_name, "relation": rel.relation}
+            for rel in insights.components_relations
+            if rel.src_name == comp.name and rel.dst_name != comp.name
+        ],
+        "used_by": [
+            {"name": rel.src_name, "relation": rel.relation}
+            for rel in insights.components_relations
+            if rel.dst_name == comp.name and rel.src_name != comp.name
+        ],
+        "metrics": {
+            "files": len(files),
+            "methods": len(methods),
+            "lines": sum(max(0, method.end_line - method.start_line + 1) for method in methods),
+        },
+        "owners": list(comp.owners),
+        "tests": list(comp.tests),
+        "not_described": comp.not_described,
+    }
+
+
+@dataclass
+class ComponentTemplate:
+    """One compiled ``--doc-template``."""
+
+    fmt: str
+    source: str
+    name: str
+    _template: Any = field(default=None, repr=False)
+
+    @classmethod
+    def load(cls, path: Path) -> "ComponentTemplate":
+        """Read and compile *path*; a bad name, syntax error or missing Jinja2 raises ``DocTemplateError``.
+
+        An unreadable file raises ``OSError``.
+        """
+        template = cls(fmt=template_format(path), source=path.read_text(encoding="utf-8"), name=path.name)
+        template.compile()
+        return template
+
+    def compile(self) -> None:
+        if not JINJA2_AVAILABLE:
+            raise DocTemplateError("--doc-template needs Jinja2: pip install 'codeboarding[templates]'")
+        env = jinja2.Environment(
+            autoescape=self.fmt == ".html",
+            keep_trailing_newline=True,
+            trim_blocks=True,
+            lstrip_blocks=True,
+            undefined=jinja2.StrictUndefined,
+        )
+        try:
+            self._template = env.from_string(self.source)
+        except jinja2.TemplateSyntaxError as e:
+            raise DocTemplateError(f"{self.name}, line {e.lineno}: {e.message}") from e
+
+    def render(
+        self, insights: AnalysisInsights, comp: Component, expanded_components: set[str], repo_ref: str = ""
+    ) -> str:
+        if self._template is None:
+            self.compile()
+        try:
+            return self._template.render(
+                component=component_context(insights, comp, expanded_components),
+                analysis={"description": insights.description, "components": [c.name for c in insights.components]},
+                format=self.fmt,
+                repo_ref=repo_ref,
+            )
+        except jinja2.TemplateError as e:
+            raise DocTemplateError(f"{self.name}: rendering {comp.name!r} failed: {e}") from e
+
+
+@dataclass(frozen=True)
+class DocTemplates:
+    """``--doc-template`` files keyed by the format they render."""
+
+    by_format: dict[str, ComponentTemplate] = field(default_factory=dict)
+
+    @classmethod
+    def of(cls, templates: list[ComponentTemplate]) -> "DocTemplates":
+        """Key *templates* by format; a later template for the same format wins."""
+        return cls(by_format={template.fmt: template for template in templates})
+
+    @classmethod
+    def load(cls, paths: list[Path]) -> "DocTemplates":
+        """``ComponentTemplate.load`` each of *paths*."""
+        return cls.of([ComponentTemplate.load(path) for path in paths])
+
+    def for_format(self, fmt: str) -> ComponentTemplate | None:
+        return self.by_format.get(fmt)
@@ -0,0 +1,168 @@
+"""Graphviz DOT export of the static call graph (``--format dot``), edge styles listed in PYPI.md.
+
+Mermaid gives up on graphs with thousands of nodes; Graphviz lays them out. The ``.dot`` file is
+always written; ``--render svg|png`` runs ``dot`` on it only when Graphviz is installed.
+"""
+
+import logging
+import os
+import shutil
+import subprocess
+from pathlib import Path
+
+from output_generators.c4 import package_for_file
+from output_generators.class_diagram import build_class_diagram
+from output_generators.fluent_chains import find_chains
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import CLASS_TYPES, NodeType
+from static_analyzer.graph import EdgeKind
+
+logger = logging.getLogger(__name__)
+
+DOT_FILENAME = "call_graph.dot"
+GRAPHVIZ_CLI = "dot"
+RENDER_FORMATS = ("svg", "png")
+_RENDER_TIMEOUT_S = 600
+
+# Keyed by ``EdgeKind`` value.
+EDGE_STYLES: dict[str, str] = {
+    EdgeKind.CALL: "",
+    EdgeKind.INHERITS: 'arrowhead=empty, label="inherits"',
+    EdgeKind.EMBEDS: 'style=bold, arrowhead=odiamond, label="embeds"',
+    EdgeKind.IMPLEMENTS: 'style=dashed, arrowhead=empty, label="implements"',
+    EdgeKind.TYPEREF: "style=dotted, color=gray40",
+    EdgeKind.SENDS_TO: 'style=dotted, color=blue, label="sends"',
+    EdgeKind.RECEIVES_FROM: 'style=dotted, color=blue, label="receives"',
+    EdgeKind.SPAWNS: 'style=bold, color=darkgreen, label="go"',
+    EdgeKind.RETURNS: 'style=dashed, arrowhead=vee, label="returns"',
+    EdgeKind.MUTATES: 'style=dashed, color=firebrick, label="mutates"',
+    EdgeKind.USES_GIVEN: 'style=dashed, color=purple, label="using"',
+    EdgeKind.ROUTE: 'style=dashed, color=teal, label="route"',
+}
+_NODE_SHAPES = {NodeType.INTERFACE: "ellipse"}
+_RECURSION_COLOR = "darkorange"
+
+
+def _quote(text: str) -> str:
+    return '"' + text.replace("\\", "\\\\").replace('"', '\\"').replace("\n", " ") + '"'
+
+
+def _relative(file_path: str, repo_dir: Path | None) -> str:
+    if repo_dir is None or not os.path.isabs(file_path):
+        return Path(file_path).as_posix()
+    relative = os.path.relpath(file_path, repo_dir)
+    return Path(file_path).as_posix() if relative.startswith("..") else Path(relative).as_posix()
+
+
+def _node_attributes(qualified_name: str, node_type: NodeType, recursive: bool = False) -> str:
+    label = qualified_name.rsplit(".", 1)[-1]
+    if node_type in CLASS_TYPES:
+        shape = _NODE_SHAPES.get(node_type, "box")
+        return f"label={_quote(label)}, shape={shape}, style=bold, tooltip={_quote(qualified_name)}"
+    highlight = f", color={_RECURSION_COLOR}, penwidth=2" if recursive else ""
+    return f"label={_quote(label)}, tooltip={_quote(qualified_name)}{highlight}"
+
+
+def generate_dot(
+    static_analysis: StaticAnalysisResults,
+    repo_dir: Path | None = None,
+    name: str = "codeboarding",
+    collapse_chains: bool = False,
+) -> str:
+    """The call graph of every language in *static_analysis* as a DOT digraph."""
+    packages: dict[str, list[str]] = {}
+    node_lines: dict[str, str] = {}
+    edges: dict[tuple[str, str], str] = {}
+    vias: dict[tuple[str, str], str] = {}
+    chain_labels: dict[tuple[str, str], str] = {}
+    # Function -> the first member of the recursive cycle it is part of.
+    cycle_of: dict[str, str] = {}
+    for language in static_analysis.get_languages():
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        for members in cfg.recursive_cycles():
+            cycle_of.update(dict.fromkeys(members, members[0]))
+        for qname, node in cfg.nodes.items():
+            if qname in node_lines:
+                continue
+            node_lines[qname] = f"{_quote(qname)} [{_node_attributes(qname, node.type, qname in cycle_of)}];"
+            packages.setdefault(package_for_file(_relative(node.file_path, repo_dir)), []).append(qname)
+        for edge in cfg.edges:
+            edges[(edge.get_source(), edge.get_destination())] = EdgeKind.CALL
+            if edge.via:
+                vias[(edge.get_source(), edge.get_destination())] = edge.via
+        for chain in find_chains(cfg) if collapse_chains else []:
+            if chain.receiver_type not in cfg.nodes:
+                continue
+            for method in chain.methods:
+                edges.pop((chain.caller, method), None)
+            edges[(chain.caller, chain.receiver_type)] = EdgeKind.CALL
+            chain_labels[(chain.caller, chain.receiver_type)] = chain.label()
+        for src, dst, kind in cfg.reference_edges:
+            try:
+                edge_kind = EdgeKind(kind)
+            except ValueError:
+                continue
+            # A call already links the pair; reference edges only add what calls miss.
+            if edge_kind in EDGE_STYLES and (src, dst) not in edges:
+                edges[(src, dst)] = edge_kind
+    for base, derived in build_class_diagram(static_analysis, repo_dir).embeddings:
+        edges[(derived, base)] = EdgeKind.EMBEDS
+
+    lines = [
+        f"digraph {_quote(name)} {{",
+        "    graph [rankdir=LR, compound=true, fontname=Helvetica];",
+        "    node [shape=box, style=rounded, fontname=Helvetica, fontsize=10];",
+        "    edge [fontname=Helvetica, fontsize=8];",
+    ]
+    for package in sorted(packages):
+        lines.append(f"    subgraph {_quote(f'cluster_{package}')} {{")
+        lines.append(f"        label={_quote(package)};")
+        lines.append("        style=rounded; color=gray60;")
+        lines.extend(f"        {node_lines[qname]}" for qname in sorted(packages[package]))
+        lines.append("    }")
+    for (src, dst), kind in sorted(edges.items()):
+        if src not in node_lines or dst not in node_lines or (src == dst and kind != EdgeKind.CALL):
+            continue
+        style = EDGE_STYLES[kind]
+        if kind == EdgeKind.CALL and src == dst:
+            style = f'color={_RECURSION_COLOR}, label="recursive"'
+        elif kind == EdgeKind.CALL and (src, dst) in chain_labels:
+            style = f"style=bold, label={_quote(chain_labels[(src, dst)])}"
+        elif kind == EdgeKind.CALL and (src, dst) in vias:
+            style = f"style=dashed, label={_quote(f'via {vias[(src, dst)]}')}"
+        if kind == EdgeKind.CALL and src != dst and src in cycle_of and cycle_of[src] == cycle_of.get(dst):
+            style = ", ".join(filter(None, [style, f"color={_RECURSION_COLOR}"]))
+        lines.append(f"    {_quote(src)} -> {_quote(dst)}" + (f" [{style}];" if style else ";"))
+    lines.append("}")
+    return "\n".join(lines) + "\n"
+
+
+def write_dot_file(dot_source: str, output_path: Path) -> Path:
+    output_path.parent.mkdir(parents=True, exist_ok=True)
+    output_path.write_text(dot_source, encoding="utf-8")
+    return output_path
+
+
+def render_dot(dot_path: Path, image_format: str) -> Path | None:
+    """Run Graphviz on *dot_path*, writing the image next to it; None (and a warning) when it fails."""
+    if image_format not in RENDER_FORMATS:
+        raise ValueError(f"Unknown render format '{image_format}', expected one of: {', '.join(RENDER_FORMATS)}")
+    if shutil.which(GRAPHVIZ_CLI) is None:
+        logger.warning(f"Graphviz ({GRAPHVIZ_CLI}) not found on PATH; only {dot_path.name} was written")
+        return None
+    image_path = dot_path.with_suffix(f".{image_format}")
+    try:
+        subprocess.run(
+            [GRAPHVIZ_CLI, f"-T{image_format}", str(dot_path), "-o", str(image_path)],
+            check=True,
+            capture_output=True,
+            timeout=_RENDER_TIMEOUT_S,
+        )
+    except (OSError, subprocess.SubprocessError) as e:
+        stderr = (getattr(e, "stderr", b"") or b"").decode(errors="replace").strip()
+        logger.warning(f"Could not render {dot_path.name} to {image_format}: {e} {stderr}")
+        return None
+    return image_path
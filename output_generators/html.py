@@ -1,10 +1,16 @@
 from pathlib import Path
 from typing import Dict, Any
 import json
+from html import escape
 
 from agents.agent_responses import AnalysisInsights
 from utils import sanitize
 from output_generators.html_template import populate_html_template
+from output_generators.ownership import ownership_sentence
+from output_generators.doc_templates import ComponentTemplate
+from output_generators.preamble import DocsPreamble
+from output_generators.snippets import SnippetSource, snippet_html
+from output_generators.test_listing import listed_tests, more_tests_note
 
 
 def generate_cytoscape_data(
@@ -15,6 +21,7 @@ def generate_cytoscape_data(
 
     # Add nodes (components)
     component_ids = set()
+    external_ids = {sanitize(comp.name) for comp in analysis.components if comp.external}
     for comp in analysis.components:
         node_key = sanitize(comp.name)
         component_ids.add(node_key)
@@ -22,7 +29,16 @@ def generate_cytoscape_data(
         # Determine if component has linked file for styling
         has_link = comp.component_id in expanded_components
 
-        node_data = {"data": {"id": node_key, "label": comp.name, "description": comp.description, "hasLink": has_link}}
+        node_data = {
+            "data": {
+                "id": node_key,
+                "label": comp.name,
+                "description": comp.description,
+                "hasLink": has_link,
+                "external": comp.external,
+                "deprecated": comp.deprecated,
+            }
+        }
 
         # Add link URL if component has linked file
         if has_link:
@@ -44,7 +60,14 @@ def generate_cytoscape_data(
         # Only add edge if both source and destination nodes exist
         if src_key in component_ids and dst_key in component_ids:
             edge_data = {
-                "data": {"id": f"edge_{edge_count}", "source": src_key, "target": dst_key, "label": rel.relation}
+                "data": {
+                    "id": f"edge_{edge_count}",
+                    "source": src_key,
+                    "target": dst_key,
+                    "label": rel.relation,
+                    "crossesBoundary": (src_key in external_ids) != (dst_key in external_ids),
+                    "implements": rel.implements,
+                }
             }
             elements.append(edge_data)
             edge_count += 1
@@ -63,9 +86,16 @@ def generate_html(
     expanded_components: set[str] | None = None,
     demo=False,
     repo_path: Path = Path(),
+    snippets: SnippetSource | None = None,
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> str:
     """
     Generate an HTML document with a Cytoscape.js diagram from an AnalysisInsights object.
+
+    With *snippets*, each key entity is followed by a highlighted excerpt of its source.
+    With *preamble*, its title replaces the default heading and its intro comes before the diagram.
+    With *doc_template* (``--doc-template``), it lays out each component section instead.
     """
     expanded_components = expanded_components or set()
 
@@ -78,6 +108,9 @@ def generate_html(
     components_html = ""
 
     for comp in insights.components:
+        if doc_template is not None:
+            components_html += doc_template.render(insights, comp, expanded_components, repo_ref)
+            continue
         component_id = sanitize(comp.name)
 
         # Build references HTML
@@ -85,14 +118,16 @@ def generate_html(
         if comp.key_entities:
             references_html = '<h4>Related Classes/Methods:</h4><ul class="references">'
             for reference in comp.key_entities:
+                snippet = snippets.extract(reference) if snippets is not None else None
+                snippet_block = snippet_html(snippet) if snippet else ""
                 if reference.reference_start_line is None or reference.reference_end_line is None:
-                    references_html += f"<li><code>{reference.llm_str()}</code></li>"
+                    references_html += f"<li><code>{reference.llm_str()}</code>{snippet_block}</li>"
                     continue
                 if not reference.reference_file:
-                    references_html += f"<li><code>{reference.llm_str()}</code></li>"
+                    references_html += f"<li><code>{reference.llm_str()}</code>{snippet_block}</li>"
                     continue
                 if not reference.reference_file.startswith(root_dir):
-                    references_html += f"<li><code>{reference.llm_str()}</code></li>"
+                    references_html += f"<li><code>{reference.llm_str()}</code>{snippet_block}</li>"
                     continue
                 # Handle case when root_dir is empty or reference file doesn't start with root_dir
                 if root_dir and reference.reference_file.startswith(root_dir):
@@ -102,11 +137,26 @@ def generate_html(
                 ref_url = (
                     repo_ref + relative_path + f"#L{reference.reference_start_line}-L{reference.reference_end_line}"
                 )
-                references_html += f'<li><a href="{ref_url}" target="_blank" rel="noopener noreferrer"><code>{reference.llm_str()}</code></a></li>'
+                references_html += f'<li><a href="{ref_url}" target="_blank" rel="noopener noreferrer"><code>{reference.llm_str()}</code></a>{snippet_block}</li>'
             references_html += "</ul>"
         else:
             references_html = "<h4>Related Classes/Methods:</h4><p><em>None</em></p>"
 
+        tests_html = ""
+        if comp.tests:
+            tests, hidden = listed_tests(comp)
+            tests_html = '<h4>Tests:</h4><ul class="tests">'
+            tests_html += "".join(f"<li><code>{escape(test_id)}</code></li>" for test_id in tests)
+            if hidden:
+                tests_html += f"<li><em>{more_tests_note(hidden)}</em></li>"
+            tests_html += "</ul>"
+
+        ownership = ownership_sentence(insights, comp)
+        owners_html = f'<p class="owners"><em>{ownership}</em></p>' if ownership else ""
+        not_described_html = ""
+        if comp.not_described:
+            not_described_html = f"<p><em>Not described ({escape(comp.not_described)}).</em></p>"
+
         # Check if there's a linked file for this component
         expand_link = ""
         if comp.component_id in expanded_components:
@@ -116,12 +166,19 @@ def generate_html(
         <div class="component">
             <h3 id="{component_id}">{comp.name}{expand_link}</h3>
             <p>{comp.description}</p>
+            {not_described_html}
+            {owners_html}
             {references_html}
+            {tests_html}
         </div>
         """
 
     return populate_html_template(
-        components_html=components_html, cytoscape_json=cytoscape_json, insights=insights, project=project
+        components_html=components_html,
+        cytoscape_json=cytoscape_json,
+        insights=insights,
+        project=project,
+        preamble=preamble,
     )
 
 
@@ -134,6 +191,9 @@ def generate_html_file(
     temp_dir: Path,
     demo: bool = False,
     repo_path: Path = Path(),
+    snippets: SnippetSource | None = None,
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> Path:
     """
     Generate an HTML file with the analysis insights.
@@ -145,6 +205,9 @@ def generate_html_file(
         expanded_components=expanded_components,
         demo=demo,
         repo_path=repo_path,
+        snippets=snippets,
+        preamble=preamble,
+        doc_template=doc_template,
     )
     html_file = temp_dir / f"{file_name}.html"
     with open(html_file, "w", encoding="utf-8") as f:
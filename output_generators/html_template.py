@@ -1,4 +1,5 @@
 from agents.agent_responses import AnalysisInsights
+from output_generators.preamble import DocsPreamble
 
 
 def _generate_css_styles() -> str:
@@ -86,9 +87,19 @@ def _generate_css_styles() -> str:
     """
 
 
-def _generate_html_body(project: str, insights: AnalysisInsights, components_html: str) -> str:
+def _page_title(project: str, preamble: DocsPreamble | None) -> str:
+    if preamble and preamble.title:
+        return preamble.escaped_title()
+    return f"CodeBoarding Analysis{' - ' + project if project else ''}"
+
+
+def _generate_html_body(
+    project: str, insights: AnalysisInsights, components_html: str, preamble: DocsPreamble | None = None
+) -> str:
+    intro = f'<div class="intro">{preamble.intro_html()}</div>' if preamble and preamble.intro else ""
     return f"""
-    <h1>CodeBoarding Analysis{' - ' + project if project else ''}</h1>
+    <h1>{_page_title(project, preamble)}</h1>
+    {intro}
 
     <div class="badges">
         <a href="https://github.com/CodeBoarding/GeneratedOnBoardings" class="badge">
@@ -187,6 +198,23 @@ def _get_cytoscape_style() -> str:
                                 'border-width': 3
                             }
                         },
+                        {
+                            selector: 'node[?external]',
+                            style: {
+                                'shape': 'hexagon',
+                                'border-style': 'dashed',
+                                'background-color': '#fff4e6'
+                            }
+                        },
+                        {
+                            selector: 'node[?deprecated]',
+                            style: {
+                                'border-style': 'dashed',
+                                'background-color': '#f5f5f5',
+                                'color': '#757575',
+                                'opacity': 0.7
+                            }
+                        },
                         {
                             selector: 'node:hover',
                             style: {
@@ -213,6 +241,19 @@ def _get_cytoscape_style() -> str:
                                 'text-background-padding': '2px',
                                 'text-background-shape': 'roundrectangle'
                             }
+                        },
+                        {
+                            selector: 'edge[?crossesBoundary]',
+                            style: {
+                                'line-style': 'dashed'
+                            }
+                        },
+                        {
+                            selector: 'edge[?implements]',
+                            style: {
+                                'line-style': 'dashed',
+                                'target-arrow-fill': 'hollow'
+                            }
                         }
                     ],
     """
@@ -357,10 +398,16 @@ def _generate_cytoscape_script(cytoscape_json: str) -> str:
     """
 
 
-def populate_html_template(project: str, insights: AnalysisInsights, components_html: str, cytoscape_json: str) -> str:
+def populate_html_template(
+    project: str,
+    insights: AnalysisInsights,
+    components_html: str,
+    cytoscape_json: str,
+    preamble: DocsPreamble | None = None,
+) -> str:
     """Populate an HTML template with project analysis data."""
     css = _generate_css_styles()
-    body = _generate_html_body(project, insights, components_html)
+    body = _generate_html_body(project, insights, components_html, preamble)
     script = _generate_cytoscape_script(cytoscape_json)
 
     return f"""<!DOCTYPE html>
@@ -368,7 +415,7 @@ def populate_html_template(project: str, insights: AnalysisInsights, components_
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>CodeBoarding Analysis - {project}</title>
+    <title>{_page_title(project, preamble)}</title>
     <!-- Load dagre first, then cytoscape, then cytoscape-dagre -->
     <script src="https://unpkg.com/dagre@0.8.5/dist/dagre.min.js"></script>
     <script src="https://unpkg.com/cytoscape@3.23.0/dist/cytoscape.min.js"></script>
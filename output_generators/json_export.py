@@ -0,0 +1,123 @@
+"""Machine-readable JSON export of the static analysis (``--output-format json``), schema in PYPI.md.
+
+Versioned by ``schema_version``: fields may be added within a version, none renamed or removed.
+"""
+
+import json
+import os
+from pathlib import Path
+from typing import Any
+
+from diagram_analysis.dead_code import package_of
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import ReceiverKind
+from static_analyzer.graph import EdgeKind
+from static_analyzer.node import Node, Parameter, go_receiver_and_method
+from static_analyzer.symbol_kinds import DEFAULT_KIND_MAP, CanonicalKind
+
+JSON_SCHEMA_VERSION = 1
+STATIC_JSON_FILENAME = "static_analysis.json"
+
+
+def _relative(file_path: str, repo_dir: Path | None) -> str:
+    if repo_dir is None or not os.path.isabs(file_path):
+        return Path(file_path).as_posix()
+    relative = os.path.relpath(file_path, repo_dir)
+    return Path(file_path).as_posix() if relative.startswith("..") else Path(relative).as_posix()
+
+
+def _parameter(parameter: Parameter) -> dict[str, Any]:
+    return {"name": parameter.name, "type": parameter.type, "variadic": parameter.is_variadic}
+
+
+def _receiver(node: Node) -> dict[str, str] | None:
+    kind = node.receiver_kind
+    member = go_receiver_and_method(node.fully_qualified_name)
+    if kind is ReceiverKind.NONE or member is None:
+        return None
+    return {"kind": kind.value, "type": member[0]}
+
+
+def _entity(node: Node, language: str, repo_dir: Path | None) -> dict[str, Any]:
+    name = node.fully_qualified_name.rsplit(".", 1)[-1]
+    file_path = _relative(node.file_path, repo_dir)
+    signature = node.signature
+    return {
+        "qualified_name": node.fully_qualified_name,
+        "name": name,
+        "kind": str(DEFAULT_KIND_MAP.get(node.type, CanonicalKind.FUNCTION)),
+        "symbol_kind": node.type.name.lower(),
+        "language": language,
+        "package": package_of(file_path),
+        "file": file_path,
+        "line_start": node.line_start,
+        "line_end": node.line_end,
+        "receiver": _receiver(node),
+        "visibility": node.visibility.value,
+        "signature": (
+            {
+                "text": signature.render(name),
+                "parameters": [_parameter(parameter) for parameter in signature.parameters],
+                "results": [_parameter(result) for result in signature.results],
+            }
+            if signature is not None
+            else None
+        ),
+    }
+
+
+def build_json_model(static_analysis: StaticAnalysisResults, repo_dir: Path | None = None) -> dict[str, Any]:
+    """The entities and edges of every language, following the schema in the module docstring."""
+    entities: dict[str, dict[str, Any]] = {}
+    edges: dict[tuple[str, str, str], dict[str, Any]] = {}
+    languages: list[str] = []
+    cycles: list[list[str]] = []
+    for language in static_analysis.get_languages():
+        languages.append(str(language))
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            cfg = None
+        nodes = list(cfg.nodes.values()) if cfg is not None else []
+        # References add the symbols no call touches: types, fields, constants.
+        nodes.extend(static_analysis.iter_reference_nodes(language))
+        for node in nodes:
+            entities.setdefault(node.fully_qualified_name, _entity(node, str(language), repo_dir))
+        if cfg is None:
+            continue
+        cycles.extend(cfg.recursive_cycles())
+        for edge in cfg.edges:
+            call_sites = []
+            for site in edge.call_sites:
+                if "file" in site:
+                    site["file"] = _relative(str(site["file"]), repo_dir)
+                call_sites.append(site)
+            source, target = edge.get_source(), edge.get_destination()
+            edges[(source, target, str(EdgeKind.CALL))] = {
+                "source": source,
+                "target": target,
+                "kind": str(EdgeKind.CALL),
+                "call_sites": call_sites,
+            }
+            if edge.recursive:
+                edges[(source, target, str(EdgeKind.CALL))]["recursive"] = True
+        for key in cfg.reference_edges:
+            source, target, kind = key
+            edge_json: dict[str, Any] = {"source": source, "target": target, "kind": kind}
+            if key in cfg.reference_confidence:
+                edge_json["confidence"] = cfg.reference_confidence[key]
+            edges.setdefault(key, edge_json)
+    return {
+        "schema_version": JSON_SCHEMA_VERSION,
+        "languages": sorted(languages),
+        "entities": [entities[name] for name in sorted(entities)],
+        "edges": [edges[key] for key in sorted(edges)],
+        "recursive_cycles": sorted(cycles),
+    }
+
+
+def write_json_model(model: dict[str, Any], output_path: Path) -> Path:
+    """Write *model* to *output_path*, indented and key-stable for diffing; returns the path."""
+    output_path.parent.mkdir(parents=True, exist_ok=True)
+    output_path.write_text(json.dumps(model, indent=2, ensure_ascii=False) + "\n", encoding="utf-8")
+    return output_path
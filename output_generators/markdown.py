@@ -1,8 +1,16 @@
+import html
 import os
-from pathlib import Path
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
 
-from agents.agent_responses import AnalysisInsights
-from static_analyzer.constants import NodeType
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup
+from output_generators.ownership import ownership_sentence
+from output_generators.doc_templates import ComponentTemplate
+from output_generators.preamble import DocsPreamble
+from output_generators.snippets import SnippetSource, snippet_markdown
+from output_generators.test_listing import listed_tests, more_tests_note
+from static_analyzer.symbol_kinds import kind_label
 from utils import sanitize
 
 
@@ -12,18 +20,49 @@ def generated_mermaid_str(
     lines = ["```mermaid", "graph LR"]
 
     # 1. Define each component as a node, including its description
+    external = {comp.name for comp in analysis.components if comp.external}
     for comp in analysis.components:
         node_key = sanitize(comp.name)
         # Show name and short description in the node label
         label = f"{comp.name}"
-        lines.append(f'    {node_key}["{label}"]')
+        if comp.name in external:
+            # Hexagon: an external service client, outside the system boundary
+            lines.append(f'    {node_key}{{{{"{label}"}}}}')
+        else:
+            lines.append(f'    {node_key}["{label}"]')
+
+    # Deprecated components are greyed out and dashed; they stay in the graph so their callers show
+    deprecated = [sanitize(comp.name) for comp in analysis.components if comp.deprecated]
+    if deprecated:
+        lines.append("    classDef deprecated fill:#f5f5f5,stroke:#9e9e9e,stroke-dasharray:4 4,color:#757575")
+        lines.append(f"    class {','.join(deprecated)} deprecated")
 
     # 2. Add relations as labeled edges
     for rel in analysis.components_relations:
         src_key = sanitize(rel.src_name)
         dst_key = sanitize(rel.dst_name)
-        # Use the relation phrase as the edge label
-        lines.append(f'    {src_key} -- "{rel.relation}" --> {dst_key}')
+        # Use the relation phrase as the edge label; calls crossing the boundary and
+        # interface satisfaction (not a call at all) are dotted
+        if rel.implements or (rel.src_name in external) != (rel.dst_name in external):
+            lines.append(f'    {src_key} -. "{rel.relation}" .-> {dst_key}')
+        else:
+            lines.append(f'    {src_key} -- "{rel.relation}" --> {dst_key}')
+    # Specs whose operations the components' generated client methods implement
+    for spec_file in sorted({op.spec_file for comp in analysis.components for op in comp.spec_operations}):
+        lines.append(f'    spec_{sanitize(spec_file)}[/"{spec_file}"/]')
+    for comp in analysis.components:
+        for spec_file in sorted({op.spec_file for op in comp.spec_operations}):
+            count = sum(1 for op in comp.spec_operations if op.spec_file == spec_file)
+            label = f"implements {count} operation{'s' if count != 1 else ''}"
+            lines.append(f'    {sanitize(comp.name)} -. "{label}" .-> spec_{sanitize(spec_file)}')
+    # ``--show-external``: one collapsed boundary node per package outside the project, not per symbol
+    for package in sorted({entry.package for comp in analysis.components for entry in comp.external_calls}):
+        lines.append(f'    ext_{sanitize(package)}{{{{"{package}"}}}}')
+        lines.append(f"    style ext_{sanitize(package)} stroke-dasharray:4 4")
+    for comp in analysis.components:
+        for entry in comp.external_calls:
+            label = f"{entry.calls} call{'s' if entry.calls != 1 else ''}"
+            lines.append(f'    {sanitize(comp.name)} -. "{label}" .-> ext_{sanitize(entry.package)}')
     # Linking to other files.
     for comp in analysis.components:
         node_key = sanitize(comp.name)
@@ -47,9 +86,19 @@ def generate_markdown(
     expanded_components: set[str] | None = None,
     demo=False,
     repo_path: Path = Path(),
+    snippets: SnippetSource | None = None,
+    collapsible: bool = False,
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> str:
     """
     Generate a Mermaid 'graph LR' diagram from an AnalysisInsights object.
+
+    With *snippets*, each key entity is followed by a highlighted excerpt of its source.
+    With *collapsible*, each component is a ``<details>`` section and its source files
+    are nested ``<details>`` per directory, so GitHub/GitLab readers expand only what they need.
+    With *preamble*, its title and intro come before the diagram.
+    With *doc_template* (``--doc-template``), it lays out each component section instead.
     """
     expanded_components = expanded_components or set()
 
@@ -58,6 +107,7 @@ def generate_markdown(
     )
 
     lines = [
+        *(preamble.markdown_lines() if preamble else []),
         mermaid_str,
         "\n[![CodeBoarding](https://img.shields.io/badge/Generated%20by-CodeBoarding-9cf?style=flat-square)](https://github.com/CodeBoarding/CodeBoarding)[![Demo](https://img.shields.io/badge/Try%20our-Demo-blue?style=flat-square)](https://www.codeboarding.org/diagrams)[![Contact](https://img.shields.io/badge/Contact%20us%20-%20contact@codeboarding.org-lightgrey?style=flat-square)](mailto:contact@codeboarding.org)",
     ]
@@ -67,15 +117,27 @@ def generate_markdown(
     root_dir = str(repo_path / project)
 
     for comp in insights.components:
-        detail_lines.append(component_header(comp.name, comp.component_id, expanded_components))
+        if doc_template is not None:
+            detail_lines.append(doc_template.render(insights, comp, expanded_components, repo_ref))
+            continue
+        if collapsible:
+            detail_lines.append(collapsible_component_header(comp.name, comp.component_id, expanded_components))
+        else:
+            detail_lines.append(component_header(comp.name, comp.component_id, expanded_components))
         detail_lines.append(f"{comp.description}")
+        if comp.deprecated or comp.deprecated_symbols:
+            detail_lines.append(_deprecation_note(insights, comp))
+        if comp.not_described:
+            detail_lines.append(f"\n\n_Not described ({comp.not_described})._")
+        if ownership := ownership_sentence(insights, comp):
+            detail_lines.append(f"\n\n_{ownership}_")
         if comp.key_entities:
             qn_list = []
             for reference in comp.key_entities:
                 if not reference.reference_file:
                     continue
                 if not os.path.exists(Path(root_dir) / reference.reference_file):
-                    qn_list.append(f"{reference}")
+                    qn_list.append((f"{reference}", reference))
                     continue
                 ref_url = repo_ref + reference.reference_file
                 if (
@@ -89,33 +151,47 @@ def generate_markdown(
                     )
                 ):
                     ref_url += f"#L{reference.reference_start_line}-L{reference.reference_end_line}"
-                qn_list.append(f'<a href="{ref_url}" target="_blank" rel="noopener noreferrer">{reference}</a>')
+                link = f'<a href="{ref_url}" target="_blank" rel="noopener noreferrer">{reference}</a>'
+                qn_list.append((link, reference))
             # Join the list into an unordered markdown list, without the leading dash
             references = ""
-            for item in qn_list:
+            for item, reference in qn_list:
                 references += f"- {item}\n"
+                if snippets is not None and (snippet := snippets.extract(reference)):
+                    references += snippet_markdown(snippet)
 
             detail_lines.append(f"\n\n**Related Classes/Methods**:\n\n{references}")
         else:
             detail_lines.append(f"\n\n**Related Classes/Methods**: _None_")
+        if comp.spec_operations:
+            op_lines = "".join(
+                f"- `{op.method} {op.path}` (`{op.operation_id}` in `{op.spec_file}`) via `{op.qualified_name}`\n"
+                for op in comp.spec_operations
+            )
+            detail_lines.append(f"\n\n**API Operations:**\n\n{op_lines}")
+        if comp.external_calls:
+            ext_lines = "".join(f"- `{entry.package}`: {entry.calls}\n" for entry in comp.external_calls)
+            detail_lines.append(f"\n\n**External Packages (calls):**\n\n{ext_lines}")
+        if comp.tests:
+            tests, hidden = listed_tests(comp)
+            test_lines = "".join(f"- `{test_id.replace('`', '')}`\n" for test_id in tests)
+            if hidden:
+                test_lines += f"- _{more_tests_note(hidden)}_\n"
+            detail_lines.append(f"\n\n**Tests:**\n\n{test_lines}")
         if comp.file_methods:
             fm_lines = "\n\n**Source Files:**\n\n"
-            for fg in comp.file_methods:
-                if repo_ref:
-                    fm_lines += f"- [`{fg.file_path}`]({repo_ref}{fg.file_path})\n"
-                else:
-                    fm_lines += f"- `{fg.file_path}`\n"
-                for method in fg.methods:
-                    label = NodeType.from_name(method.node_type).label()
-                    line_ref = f"L{method.start_line}-L{method.end_line}"
-                    if repo_ref:
-                        line_link = f"[{line_ref}]({repo_ref}{fg.file_path}#{line_ref})"
-                    else:
-                        line_link = line_ref
-                    fm_lines += f"  - `{method.qualified_name}` ({line_link}) - {label}\n"
+            if collapsible:
+                fm_lines += _collapsible_source_tree(comp.file_methods, repo_ref)
+            else:
+                fm_lines += "".join(_source_file_entry(fg, repo_ref) for fg in comp.file_methods)
             detail_lines.append(fm_lines)
+        if collapsible:
+            detail_lines.append("\n</details>")
         detail_lines.append("")  # blank line between components
 
+    if any(comp.http_routes for comp in insights.components):
+        detail_lines.append(_endpoints_table(insights))
+
     detail_lines.append(
         "\n\n### [FAQ](https://github.com/CodeBoarding/GeneratedOnBoardings/tree/main?tab=readme-ov-file#faq)"
     )
@@ -131,6 +207,10 @@ def generate_markdown_file(
     temp_dir: Path,
     demo: bool = False,
     repo_path: Path = Path(),
+    snippets: SnippetSource | None = None,
+    collapsible: bool = False,
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> Path:
     content = generate_markdown(
         insights,
@@ -139,6 +219,10 @@ def generate_markdown_file(
         expanded_components=expanded_components,
         demo=demo,
         repo_path=repo_path,
+        snippets=snippets,
+        collapsible=collapsible,
+        preamble=preamble,
+        doc_template=doc_template,
     )
     markdown_file = temp_dir / f"{file_name}.md"
     with open(markdown_file, "w", encoding="utf-8") as f:
@@ -146,6 +230,18 @@ def generate_markdown_file(
     return markdown_file
 
 
+def _endpoints_table(insights: AnalysisInsights) -> str:
+    """``--framework http``: every route the components serve, by path, with its handler."""
+    rows = sorted(
+        (route.path.replace("|", "\\|"), route.method, route.qualified_name, comp.name.replace("|", "\\|"))
+        for comp in insights.components
+        for route in comp.http_routes
+    )
+    lines = ["\n## HTTP Endpoints\n", "| Method | Path | Handler | Component |", "| --- | --- | --- | --- |"]
+    lines += [f"| {method} | `{path}` | `{handler}` | {component} |" for path, method, handler, component in rows]
+    return "\n".join(lines) + "\n"
+
+
 def component_header(component_name: str, component_id: str, expanded_components: set[str]) -> str:
     """
     Generate a header for a component with its name and a link to its details.
@@ -155,3 +251,77 @@ def component_header(component_name: str, component_id: str, expanded_components
         return f"### {component_name} [[Expand]](./{sanitized_name}.md)"
     else:
         return f"### {component_name}"
+
+
+def collapsible_component_header(component_name: str, component_id: str, expanded_components: set[str]) -> str:
+    """Open a ``<details>`` section for a component; the body must be closed with ``</details>``.
+
+    Why: GitHub only renders Markdown inside ``<details>`` when a blank line follows ``</summary>``.
+    """
+    summary = f"<b>{html.escape(component_name)}</b>"
+    if component_id in expanded_components:
+        summary += f' <a href="./{sanitize(component_name)}.md">[Expand]</a>'
+    return f"<details>\n<summary>{summary}</summary>\n"
+
+
+def _deprecation_note(insights: AnalysisInsights, comp: Component) -> str:
+    if not comp.deprecated:
+        symbols = ", ".join(f"`{name}`" for name in comp.deprecated_symbols)
+        return f"\n\n**Deprecated:** {symbols}"
+    deprecated = {c.name for c in insights.components if c.deprecated}
+    users = sorted({r.src_name for r in insights.components_relations if r.dst_name == comp.name} - deprecated)
+    note = "\n\n_Deprecated._"
+    if users:
+        note += f" Still used by {', '.join(users)}."
+    return note
+
+
+def _source_file_entry(fg: FileMethodGroup, repo_ref: str) -> str:
+    if repo_ref:
+        entry = f"- [`{fg.file_path}`]({repo_ref}{fg.file_path})\n"
+    else:
+        entry = f"- `{fg.file_path}`\n"
+    for method in fg.methods:
+        label = kind_label(method.node_type, method.kind)
+        line_ref = f"L{method.start_line}-L{method.end_line}"
+        if repo_ref:
+            line_link = f"[{line_ref}]({repo_ref}{fg.file_path}#{line_ref})"
+        else:
+            line_link = line_ref
+        signature = f" `{method.signature}`" if method.signature else ""
+        entry += f"  - `{method.qualified_name}` ({line_link}) - {label}{signature}\n"
+    return entry
+
+
+@dataclass
+class _DirNode:
+    dirs: dict[str, "_DirNode"] = field(default_factory=dict)
+    files: list[FileMethodGroup] = field(default_factory=list)
+
+    def file_count(self) -> int:
+        return len(self.files) + sum(child.file_count() for child in self.dirs.values())
+
+
+def _collapsible_source_tree(file_methods: list[FileMethodGroup], repo_ref: str) -> str:
+    """Source files as nested ``<details>`` keyed by directory; single-child directory chains are merged."""
+    root = _DirNode()
+    for fg in file_methods:
+        node = root
+        for part in PurePosixPath(fg.file_path.replace("\\", "/")).parent.parts:
+            node = node.dirs.setdefault(part, _DirNode())
+        node.files.append(fg)
+    return _render_dir(root, repo_ref)
+
+
+def _render_dir(node: _DirNode, repo_ref: str) -> str:
+    out = "".join(_source_file_entry(fg, repo_ref) for fg in node.files)
+    for name, child in sorted(node.dirs.items()):
+        while not child.files and len(child.dirs) == 1:
+            (sub_name, child), *_ = child.dirs.items()
+            name = f"{name}/{sub_name}"
+        count = child.file_count()
+        files = f"{count} file{'s' if count != 1 else ''}"
+        out += f"\n<details>\n<summary><code>{html.escape(name)}/</code> ({files})</summary>\n\n"
+        out += _render_dir(child, repo_ref)
+        out += "\n</details>\n"
+    return out
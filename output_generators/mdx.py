@@ -1,7 +1,11 @@
 from pathlib import Path
 
 from agents.agent_responses import AnalysisInsights
-from static_analyzer.constants import NodeType
+from output_generators.ownership import ownership_sentence
+from output_generators.doc_templates import ComponentTemplate
+from output_generators.preamble import DocsPreamble
+from output_generators.test_listing import listed_tests, more_tests_note
+from static_analyzer.symbol_kinds import kind_label
 from utils import sanitize
 
 
@@ -21,8 +25,11 @@ def generated_mermaid_str(
     for rel in analysis.components_relations:
         src_key = sanitize(rel.src_name)
         dst_key = sanitize(rel.dst_name)
-        # Use the relation phrase as the edge label
-        lines.append(f'    {src_key} -- "{rel.relation}" --> {dst_key}')
+        # Use the relation phrase as the edge label; interface satisfaction is dotted
+        if rel.implements:
+            lines.append(f'    {src_key} -. "{rel.relation}" .-> {dst_key}')
+        else:
+            lines.append(f'    {src_key} -- "{rel.relation}" --> {dst_key}')
 
     # Linking to other files with new MDX format
     for comp in analysis.components:
@@ -35,8 +42,16 @@ def generated_mermaid_str(
     return "\n".join(lines)
 
 
-def generate_frontmatter(file_name: str, component_name: str | None = None) -> str:
+def generate_frontmatter(file_name: str, component_name: str | None = None, title: str = "") -> str:
     """Generate frontmatter for MDX files."""
+    if title:
+        escaped = title.replace("\\", "\\\\").replace('"', '\\"')
+        return f"""---
+title: "{escaped}"
+icon: "network"
+---
+
+"""
     if file_name == "on_boarding" or file_name == "analysis" or not component_name:
         return """---
 title: "Architecture Overview"
@@ -57,6 +72,8 @@ def generate_mdx(
     demo=False,
     file_name: str = "on_boarding",
     repo_path: Path = Path(),
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> str:
     """
     Generate MDX content from an AnalysisInsights object.
@@ -64,13 +81,18 @@ def generate_mdx(
     expanded_components = expanded_components or set()
 
     # Generate frontmatter
-    frontmatter = generate_frontmatter(file_name, component_name=file_name.replace("_", " ").strip())
+    frontmatter = generate_frontmatter(
+        file_name, component_name=file_name.replace("_", " ").strip(), title=preamble.title if preamble else ""
+    )
 
     mermaid_str = generated_mermaid_str(
         insights, repo_ref=repo_ref, expanded_components=expanded_components, project=project, demo=demo
     )
 
-    lines = [frontmatter, mermaid_str]
+    lines = [frontmatter]
+    if preamble and preamble.intro:
+        lines.append(f"{preamble.intro}\n")
+    lines.append(mermaid_str)
 
     # Add Info component instead of badges
     if file_name == "on_boarding":
@@ -86,8 +108,15 @@ This documentation was generated by [CodeBoarding](https://github.com/CodeBoardi
     root_dir = str(repo_path / project)
 
     for comp in insights.components:
+        if doc_template is not None:
+            detail_lines.append(doc_template.render(insights, comp, expanded_components, repo_ref))
+            continue
         detail_lines.append(component_header(comp.name, comp.component_id, expanded_components, demo))
         detail_lines.append(f"{comp.description}")
+        if comp.not_described:
+            detail_lines.append(f"\n\n_Not described ({comp.not_described})._")
+        if ownership := ownership_sentence(insights, comp):
+            detail_lines.append(f"\n\n_{ownership}_")
         if comp.key_entities:
             qn_list = []
             for reference in comp.key_entities:
@@ -132,6 +161,12 @@ This documentation was generated by [CodeBoarding](https://github.com/CodeBoardi
             detail_lines.append(f"\n\n**Related Classes/Methods**:\n\n{references}")
         else:
             detail_lines.append(f"\n\n**Related Classes/Methods**: _None_")
+        if comp.tests:
+            tests, hidden = listed_tests(comp)
+            test_lines = "".join(f"- `{test_id.replace('`', '')}`\n" for test_id in tests)
+            if hidden:
+                test_lines += f"- _{more_tests_note(hidden)}_\n"
+            detail_lines.append(f"\n\n**Tests:**\n\n{test_lines}")
         if comp.file_methods:
             # https://github.com/owner/repo/blob/branch -> 7 segments; file path follows after
             base_url = "/".join(repo_ref.split("/")[:7])
@@ -148,7 +183,7 @@ This documentation was generated by [CodeBoarding](https://github.com/CodeBoardi
                 file_url = f"{base_url}/{rel_path.as_posix()}"
                 source_lines.append(f"- [`{rel_path.as_posix()}`]({file_url})")
                 for method in group.methods:
-                    label = NodeType.from_name(method.node_type).label()
+                    label = kind_label(method.node_type, method.kind)
                     line_ref = f"L{method.start_line}-L{method.end_line}"
                     method_url = f"{file_url}#{line_ref}"
                     source_lines.append(f"  - `{method.qualified_name}` ([{line_ref}]({method_url})) - {label}")
@@ -167,6 +202,8 @@ def generate_mdx_file(
     temp_dir: Path,
     demo: bool = False,
     repo_path: Path = Path(),
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> Path:
     content = generate_mdx(
         insights,
@@ -176,6 +213,8 @@ def generate_mdx_file(
         demo=demo,
         file_name=file_name,
         repo_path=repo_path,
+        preamble=preamble,
+        doc_template=doc_template,
     )
     mdx_file = temp_dir / f"{file_name}.mdx"
     with open(mdx_file, "w", encoding="utf-8") as f:
@@ -0,0 +1,305 @@
+"""Neo4j export of an analysis (``--format neo4j``): CSV for ``neo4j-admin`` import and ``import.cypher``.
+
+The schema, documented in PYPI.md, is stable: properties may be added, none renamed or removed.
+"""
+
+import csv
+import json
+import os
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights
+from agents.file_index_models import MethodEntry
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import EdgeKind
+from static_analyzer.symbol_kinds import DEFAULT_KIND_MAP, CanonicalKind
+
+NEO4J_DIR_NAME = "neo4j"
+NODES_FILENAME = "nodes.csv"
+EDGES_FILENAME = "edges.csv"
+CYPHER_FILENAME = "import.cypher"
+
+COMPONENT_LABEL = "Component"
+SYMBOL_LABEL = "Symbol"
+
+RELATIONSHIP_TYPES: dict[EdgeKind, str] = {
+    EdgeKind.CALL: "CALLS",
+    EdgeKind.CONTAINS: "CONTAINS",
+    EdgeKind.INHERITS: "INHERITS",
+    EdgeKind.IMPLEMENTS: "IMPLEMENTS",
+    EdgeKind.EMBEDS: "EMBEDS",
+    EdgeKind.TYPEREF: "REFERENCES_TYPE",
+    EdgeKind.IMPORT: "IMPORTS",
+    EdgeKind.SENDS_TO: "SENDS_TO",
+    EdgeKind.RECEIVES_FROM: "RECEIVES_FROM",
+    EdgeKind.SPAWNS: "SPAWNS",
+    EdgeKind.RETURNS: "RETURNS",
+    EdgeKind.MUTATES: "MUTATES",
+    EdgeKind.USES_GIVEN: "USES_GIVEN",
+    EdgeKind.ROUTE: "ROUTES",
+}
+
+# ``name:type`` headers as neo4j-admin expects them; untyped columns are strings.
+_NODE_COLUMNS = [
+    ("id", "id:ID"),
+    ("labels", ":LABEL"),
+    ("name", "name"),
+    ("qualified_name", "qualified_name"),
+    ("description", "description"),
+    ("file", "file"),
+    ("line", "line:int"),
+    ("component_id", "component_id"),
+]
+_EDGE_COLUMNS = [
+    ("start", ":START_ID"),
+    ("end", ":END_ID"),
+    ("type", ":TYPE"),
+    ("relation", "relation"),
+    ("edges", "edges:int"),
+]
+
+
+@dataclass
+class GraphNode:
+    id: str
+    labels: list[str]
+    properties: dict[str, str | int] = field(default_factory=dict)
+
+
+@dataclass
+class GraphEdge:
+    start: str
+    end: str
+    type: str
+    properties: dict[str, str | int] = field(default_factory=dict)
+
+
+@dataclass
+class Neo4jGraph:
+    nodes: dict[str, GraphNode] = field(default_factory=dict)
+    edges: list[GraphEdge] = field(default_factory=list)
+    _edge_keys: set[tuple[str, str, str]] = field(default_factory=set, repr=False)
+
+    def add_edge(self, start: str, end: str, relationship: str, **properties: str | int) -> None:
+        """Add a relationship between two known nodes, once per (start, end, relationship)."""
+        key = (start, end, relationship)
+        if start == end or key in self._edge_keys or start not in self.nodes or end not in self.nodes:
+            return
+        self._edge_keys.add(key)
+        self.edges.append(GraphEdge(start, end, relationship, dict(properties)))
+
+
+def component_node_id(component_id: str) -> str:
+    return f"component:{component_id}"
+
+
+def symbol_node_id(qualified_name: str) -> str:
+    return f"symbol:{qualified_name}"
+
+
+def _relative(file_path: str, repo_dir: Path | None) -> str:
+    if repo_dir is None or not os.path.isabs(file_path):
+        return file_path
+    relative = os.path.relpath(file_path, repo_dir)
+    return file_path if relative.startswith("..") else Path(relative).as_posix()
+
+
+def _symbol_node(qualified_name: str, kind: str, file_path: str, line: int) -> GraphNode:
+    return GraphNode(
+        id=symbol_node_id(qualified_name),
+        labels=[SYMBOL_LABEL, kind],
+        properties={
+            "name": qualified_name.rsplit(".", 1)[-1],
+            "qualified_name": qualified_name,
+            "file": file_path,
+            "line": line,
+        },
+    )
+
+
+def _method_kind(method: MethodEntry) -> str:
+    """The recorded canonical kind, or the default one for entries saved without it."""
+    try:
+        return CanonicalKind(method.kind).label()
+    except ValueError:
+        pass
+    try:
+        return DEFAULT_KIND_MAP[NodeType.from_name(method.node_type)].label()
+    except (KeyError, ValueError):
+        return CanonicalKind.FUNCTION.label()
+
+
+def _method_node(method: MethodEntry, file_path: str) -> GraphNode:
+    return _symbol_node(method.qualified_name, _method_kind(method), file_path, method.start_line)
+
+
+def build_neo4j_graph(
+    root: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    static_analysis: StaticAnalysisResults | None = None,
+    repo_dir: Path | None = None,
+) -> Neo4jGraph:
+    """Components, symbols and their relationships, following the schema in the module docstring."""
+    graph = Neo4jGraph()
+    analyses = [root, *sub_analyses.values()]
+
+    parents: dict[str, str] = {}
+    for parent_id, sub in sub_analyses.items():
+        for component in sub.components:
+            parents[component.component_id] = parent_id
+    for analysis in analyses:
+        for component in analysis.components:
+            graph.nodes[component_node_id(component.component_id)] = GraphNode(
+                id=component_node_id(component.component_id),
+                labels=[COMPONENT_LABEL],
+                properties={
+                    "name": component.name,
+                    "description": component.description,
+                    "component_id": component.component_id,
+                },
+            )
+
+    # The deepest component wins the symbol's component_id; a shallower one only when nothing deeper lists it.
+    owners: dict[str, str] = {}
+    memberships: list[tuple[str, str]] = []
+    for analysis in analyses:
+        for file_path, entry in analysis.files.items():
+            for method in entry.methods:
+                graph.nodes.setdefault(symbol_node_id(method.qualified_name), _method_node(method, file_path))
+        for component in analysis.components:
+            depth = component.component_id.count(".")
+            for group in component.file_methods:
+                for method in group.methods:
+                    node = graph.nodes.setdefault(
+                        symbol_node_id(method.qualified_name), _method_node(method, group.file_path)
+                    )
+                    memberships.append((node.id, component.component_id))
+                    current = owners.get(node.id)
+                    if current is None or current.count(".") < depth:
+                        owners[node.id] = component.component_id
+    for node_id, component_id in owners.items():
+        graph.nodes[node_id].properties["component_id"] = component_id
+
+    if static_analysis is not None:
+        _add_static_edges(graph, static_analysis, repo_dir)
+    else:
+        for analysis in analyses:
+            for relation in analysis.components_relations:
+                for edge in relation.all_edges or relation.key_edges:
+                    for reference in (edge.source, edge.target):
+                        graph.nodes.setdefault(
+                            symbol_node_id(reference.qualified_name),
+                            _symbol_node(
+                                reference.qualified_name,
+                                CanonicalKind.FUNCTION.label(),
+                                reference.reference_file or "",
+                                reference.reference_start_line or 0,
+                            ),
+                        )
+                    graph.add_edge(
+                        symbol_node_id(edge.source.qualified_name),
+                        symbol_node_id(edge.target.qualified_name),
+                        RELATIONSHIP_TYPES[EdgeKind.CALL],
+                    )
+
+    for child_id, parent_id in parents.items():
+        graph.add_edge(component_node_id(child_id), component_node_id(parent_id), "PART_OF")
+    for analysis in analyses:
+        for relation in analysis.components_relations:
+            if relation.src_id and relation.dst_id:
+                graph.add_edge(
+                    component_node_id(relation.src_id),
+                    component_node_id(relation.dst_id),
+                    "IMPLEMENTS" if relation.implements else "DEPENDS_ON",
+                    relation=relation.relation,
+                    edges=len(relation.all_edges),
+                )
+    for node_id, component_id in memberships:
+        graph.add_edge(node_id, component_node_id(component_id), "BELONGS_TO")
+    return graph
+
+
+def _add_static_edges(graph: Neo4jGraph, static_analysis: StaticAnalysisResults, repo_dir: Path | None) -> None:
+    for language in static_analysis.get_languages():
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        for name, node in cfg.nodes.items():
+            graph.nodes.setdefault(
+                symbol_node_id(name),
+                _symbol_node(
+                    name, DEFAULT_KIND_MAP[node.type].label(), _relative(node.file_path, repo_dir), node.line_start
+                ),
+            )
+        for edge in cfg.edges:
+            graph.add_edge(
+                symbol_node_id(edge.get_source()),
+                symbol_node_id(edge.get_destination()),
+                RELATIONSHIP_TYPES[EdgeKind.CALL],
+            )
+        for src, dst, kind in cfg.reference_edges:
+            try:
+                relationship = RELATIONSHIP_TYPES[EdgeKind(kind)]
+            except ValueError:
+                continue
+            graph.add_edge(symbol_node_id(src), symbol_node_id(dst), relationship)
+
+
+def _node_row(node: GraphNode) -> dict[str, str | int]:
+    return {"id": node.id, "labels": ";".join(node.labels), **node.properties}
+
+
+def _edge_row(edge: GraphEdge) -> dict[str, str | int]:
+    return {"start": edge.start, "end": edge.end, "type": edge.type, **edge.properties}
+
+
+def _write_csv(path: Path, columns: list[tuple[str, str]], rows: list[dict[str, str | int]]) -> None:
+    with open(path, "w", encoding="utf-8", newline="") as f:
+        writer = csv.writer(f)
+        writer.writerow(header for _, header in columns)
+        for row in rows:
+            writer.writerow(row.get(key, "") for key, _ in columns)
+
+
+def _cypher_map(properties: dict[str, str | int]) -> str:
+    # JSON string escapes are valid Cypher string escapes.
+    entries = [
+        f"{key}: {value if isinstance(value, int) else json.dumps(value, ensure_ascii=False)}"
+        for key, value in properties.items()
+        if value != ""
+    ]
+    return "{" + ", ".join(entries) + "}"
+
+
+def generate_cypher(graph: Neo4jGraph) -> str:
+    """``CREATE`` statements for every node and relationship, after uniqueness constraints on ``id``."""
+    lines = [
+        "// CodeBoarding architecture graph. Load with: cypher-shell -f import.cypher",
+        f"CREATE CONSTRAINT codeboarding_component_id IF NOT EXISTS FOR (n:{COMPONENT_LABEL}) REQUIRE n.id IS UNIQUE;",
+        f"CREATE CONSTRAINT codeboarding_symbol_id IF NOT EXISTS FOR (n:{SYMBOL_LABEL}) REQUIRE n.id IS UNIQUE;",
+    ]
+    for node in graph.nodes.values():
+        labels = "".join(f":{label}" for label in node.labels)
+        lines.append(f"CREATE ({labels} {_cypher_map({'id': node.id, **node.properties})});")
+    for edge in graph.edges:
+        start = graph.nodes[edge.start]
+        end = graph.nodes[edge.end]
+        properties = f" {_cypher_map(edge.properties)}" if edge.properties else ""
+        lines.append(
+            f"MATCH (a:{start.labels[0]} {{id: {json.dumps(edge.start, ensure_ascii=False)}}}), "
+            f"(b:{end.labels[0]} {{id: {json.dumps(edge.end, ensure_ascii=False)}}}) "
+            f"CREATE (a)-[:{edge.type}{properties}]->(b);"
+        )
+    return "\n".join(lines) + "\n"
+
+
+def write_neo4j_files(graph: Neo4jGraph, output_dir: Path) -> Path:
+    """Write ``nodes.csv``, ``edges.csv`` and ``import.cypher`` into *output_dir*; returns the directory."""
+    output_dir.mkdir(parents=True, exist_ok=True)
+    _write_csv(output_dir / NODES_FILENAME, _NODE_COLUMNS, [_node_row(node) for node in graph.nodes.values()])
+    _write_csv(output_dir / EDGES_FILENAME, _EDGE_COLUMNS, [_edge_row(edge) for edge in graph.edges])
+    (output_dir / CYPHER_FILENAME).write_text(generate_cypher(graph), encoding="utf-8")
+    return output_dir
@@ -0,0 +1,24 @@
+"""The "owned by" line shown under each component when ``--use-codeowners`` set owners."""
+
+from agents.agent_responses import AnalysisInsights, Component
+
+
+def ownership_sentence(insights: AnalysisInsights, comp: Component) -> str:
+    """``Owned by @a. Depends on components owned by @b.``; empty when no owners are involved.
+
+    Plain text so every format can wrap it in its own emphasis markup.
+    """
+    owners_by_name = {c.name: c.owners for c in insights.components}
+    depended = {
+        owner
+        for rel in insights.components_relations
+        if rel.src_name == comp.name and rel.dst_name != comp.name
+        for owner in owners_by_name.get(rel.dst_name, [])
+    }
+    other_teams = sorted(depended - set(comp.owners))
+    sentences = []
+    if comp.owners:
+        sentences.append(f"Owned by {', '.join(comp.owners)}.")
+    if other_teams:
+        sentences.append(f"Depends on components owned by {', '.join(other_teams)}.")
+    return " ".join(sentences)
@@ -0,0 +1,172 @@
+"""One paginated PDF of the component docs (``--format pdf``).
+
+The Markdown docs are rendered as usual into ``pdf/`` next to
+``analysis.json``; each mermaid diagram in them is rendered to SVG with the
+mermaid CLI (``mmdc``), and the pages are laid out as one HTML document
+(cover page with the ``--title``, table of contents with page numbers, then
+the overview and every expanded component) which WeasyPrint prints to
+``pdf/architecture.pdf``.
+
+Both tools are optional. When either is missing the Markdown is still
+written and :class:`PdfToolchainError` says what to install.
+"""
+
+import html
+import itertools
+import logging
+import re
+import shutil
+import subprocess
+from dataclasses import dataclass
+from pathlib import Path
+
+import markdown
+
+# WeasyPrint ships in the optional ``pdf`` extra; only --format pdf needs it.
+try:
+    import weasyprint
+
+    WEASYPRINT_AVAILABLE = True
+except (ImportError, OSError):
+    # OSError: the package is installed but its Pango/Cairo system libraries are not.
+    WEASYPRINT_AVAILABLE = False
+    weasyprint = None  # type: ignore[assignment]
+
+logger = logging.getLogger(__name__)
+
+PDF_DIR_NAME = "pdf"
+PDF_FILENAME = "architecture.pdf"
+MERMAID_CLI = "mmdc"
+EXIT_PDF_TOOLCHAIN_MISSING = 4
+_MERMAID_TIMEOUT_S = 120
+
+_MERMAID_BLOCK_RE = re.compile(r"^```mermaid\n(.*?)^```$", re.MULTILINE | re.DOTALL)
+# The badge row and FAQ link are for readers on GitHub; the PDF is read offline.
+_WEB_ONLY_RE = re.compile(r"^\s*(?:\[!\[CodeBoarding\]|### \[FAQ\]).*$", re.MULTILINE)
+_DOC_LINK_RE = re.compile(r'href="(?:\./)?([^"/#:]+)\.md"')
+_MD_LINK_RE = re.compile(r"\]\((?:\./)?([^)/#:]+)\.md\)")
+
+_PDF_CSS = """
+@page { size: A4; margin: 2cm 1.8cm; @bottom-center { content: counter(page); font-size: 9pt; color: #666; } }
+@page :first { @bottom-center { content: none; } }
+body { font-family: "Helvetica", "Arial", sans-serif; font-size: 10.5pt; line-height: 1.45; color: #222; }
+.cover { page-break-after: always; text-align: center; padding-top: 8cm; }
+.cover h1 { font-size: 30pt; margin-bottom: 0.4cm; }
+.cover p { font-size: 13pt; color: #555; }
+.toc { page-break-after: always; }
+.toc ul { list-style: none; padding-left: 0; }
+.toc li { margin: 0.2cm 0; }
+.toc a { color: #222; text-decoration: none; }
+.toc a::after { content: leader('.') target-counter(attr(href), page); }
+section.doc { page-break-before: always; }
+section.doc:first-of-type { page-break-before: auto; }
+img.diagram { display: block; max-width: 100%; max-height: 22cm; margin: 0.4cm auto; }
+code, pre { font-family: "Courier New", monospace; font-size: 9pt; }
+pre { background: #f6f8fa; padding: 0.3cm; white-space: pre-wrap; }
+a { color: #1a5fb4; }
+"""
+
+
+class PdfToolchainError(RuntimeError):
+    pass
+
+
+@dataclass(frozen=True)
+class PdfSection:
+    """One Markdown doc, in the order it appears in the PDF."""
+
+    anchor: str
+    title: str
+    markdown_path: Path
+
+
+def missing_toolchain() -> list[str]:
+    """Install hints for whatever of WeasyPrint and the mermaid CLI is missing."""
+    missing = []
+    if not WEASYPRINT_AVAILABLE:
+        missing.append(
+            "WeasyPrint: pip install 'codeboarding[pdf]' (it also needs Pango, "
+            "see https://doc.courtbouillon.org/weasyprint/stable/first_steps.html)"
+        )
+    if shutil.which(MERMAID_CLI) is None:
+        missing.append(f"the mermaid CLI ({MERMAID_CLI}): npm install -g @mermaid-js/mermaid-cli")
+    return missing
+
+
+def render_svg(mermaid_source: str, svg_path: Path) -> Path | None:
+    """Render one mermaid diagram to *svg_path* with ``mmdc``; None (and a warning) when it fails."""
+    source_path = svg_path.with_suffix(".mmd")
+    source_path.write_text(mermaid_source, encoding="utf-8")
+    try:
+        subprocess.run(
+            [MERMAID_CLI, "-i", str(source_path), "-o", str(svg_path), "-b", "white"],
+            check=True,
+            capture_output=True,
+            timeout=_MERMAID_TIMEOUT_S,
+        )
+    except (OSError, subprocess.SubprocessError) as e:
+        stderr = getattr(e, "stderr", b"") or b""
+        logger.warning(f"Could not render {source_path.name} to SVG: {e} {stderr.decode(errors='replace').strip()}")
+        return None
+    return svg_path
+
+
+def _section_html(section: PdfSection, diagrams_dir: Path) -> str:
+    text = _WEB_ONLY_RE.sub("", section.markdown_path.read_text(encoding="utf-8"))
+    numbers = itertools.count()
+
+    def diagram(match: re.Match) -> str:
+        svg = render_svg(match.group(1), diagrams_dir / f"{section.anchor}-{next(numbers)}.svg")
+        if svg is None:
+            return match.group(0)
+        alt = html.escape(f"{section.title} diagram")
+        return f'<img class="diagram" src="{html.escape(svg.resolve().as_uri())}" alt="{alt}"/>'
+
+    text = _MERMAID_BLOCK_RE.sub(diagram, text)
+    # Links between the docs become links between the PDF's sections.
+    text = _MD_LINK_RE.sub(r"](#\1)", text)
+    body = markdown.markdown(text, extensions=["tables", "fenced_code"])
+    body = _DOC_LINK_RE.sub(r'href="#\1"', body)
+    return f'<section class="doc" id="{html.escape(section.anchor)}">\n{body}\n</section>'
+
+
+def build_pdf_html(title: str, subtitle: str, sections: list[PdfSection], diagrams_dir: Path) -> str:
+    """The whole PDF as one HTML document: cover, table of contents, then each section."""
+    toc = "\n".join(
+        f'<li><a href="#{html.escape(section.anchor)}">{html.escape(section.title)}</a></li>' for section in sections
+    )
+    body = "\n".join(_section_html(section, diagrams_dir) for section in sections)
+    return f"""<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{html.escape(title)}</title>
+<style>{_PDF_CSS}</style>
+</head>
+<body>
+<section class="cover">
+<h1>{html.escape(title)}</h1>
+<p>{html.escape(subtitle)}</p>
+</section>
+<nav class="toc">
+<h1>Contents</h1>
+<ul>
+{toc}
+</ul>
+</nav>
+{body}
+</body>
+</html>
+"""
+
+
+def write_pdf(title: str, subtitle: str, sections: list[PdfSection], output_dir: Path) -> Path:
+    """Print *sections* into ``output_dir/architecture.pdf``; raises ``PdfToolchainError`` without the toolchain."""
+    if missing := missing_toolchain():
+        raise PdfToolchainError("--format pdf needs " + " and ".join(missing))
+    diagrams_dir = output_dir / "diagrams"
+    diagrams_dir.mkdir(parents=True, exist_ok=True)
+    document = build_pdf_html(title, subtitle, sections, diagrams_dir)
+    pdf_path = output_dir / PDF_FILENAME
+    weasyprint.HTML(string=document, base_url=str(output_dir)).write_pdf(str(pdf_path))
+    return pdf_path
@@ -0,0 +1,41 @@
+"""Hand-written title and introduction for the top-level generated doc (``--title``, ``--intro``).
+
+The intro is included as written: Markdown for ``.md``/``.mdx``, converted to
+HTML for ``.html``, and verbatim for ``.rst`` (plain paragraphs read the same
+in both markups; write it in reStructuredText for anything richer).
+"""
+
+import html
+from dataclasses import dataclass
+from pathlib import Path
+
+import markdown
+
+
+@dataclass(frozen=True)
+class DocsPreamble:
+    title: str = ""
+    intro: str = ""
+
+    def __bool__(self) -> bool:
+        return bool(self.title or self.intro)
+
+    @classmethod
+    def load(cls, title: str | None, intro_path: Path | None) -> "DocsPreamble":
+        """Read *intro_path* (if any); an unreadable intro file raises ``OSError``."""
+        intro = intro_path.read_text(encoding="utf-8").strip() if intro_path is not None else ""
+        return cls(title=(title or "").strip(), intro=intro)
+
+    def markdown_lines(self) -> list[str]:
+        lines = []
+        if self.title:
+            lines.append(f"# {self.title}\n")
+        if self.intro:
+            lines.append(f"{self.intro}\n")
+        return lines
+
+    def intro_html(self) -> str:
+        return markdown.markdown(self.intro) if self.intro else ""
+
+    def escaped_title(self) -> str:
+        return html.escape(self.title)
@@ -0,0 +1,148 @@
+"""PlantUML sequence diagram traced from one entry point (``--sequence-from``).
+
+The component diagram shows which parts depend on which, but not in what order
+things happen. Starting at an entry function such as ``main.main``, this walks
+the static call graph depth first and draws every call as a message, in the
+order the calls appear in the caller's source (the call sites recorded on each
+edge; calls without one come last, by name):
+
+* a callee is expanded at most ``--max-depth`` calls below the entry point;
+* a call back into a function still on the trace (recursion, direct or
+  through others) is drawn once, with a ``[recursion]`` note, and not
+  expanded again;
+* very large traces stop after :data:`MAX_MESSAGES` messages, with a note.
+
+Each traced function is a participant, labelled with its qualified name, in
+order of first appearance.
+"""
+
+import logging
+from dataclasses import dataclass
+from pathlib import Path
+
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.graph import CallGraph
+
+logger = logging.getLogger(__name__)
+
+SEQUENCE_FILENAME = "sequence.puml"
+DEFAULT_MAX_DEPTH = 3
+MAX_MESSAGES = 500
+
+
+class SequenceError(ValueError):
+    pass
+
+
+@dataclass(frozen=True)
+class Message:
+    caller: str
+    callee: str
+    # 1 for the entry point's own calls.
+    depth: int
+    recursion: bool = False
+
+
+@dataclass
+class Sequence:
+    entry: str
+    max_depth: int
+    messages: list[Message]
+    truncated: bool = False
+
+    @property
+    def participants(self) -> list[str]:
+        seen = dict.fromkeys([self.entry])
+        for message in self.messages:
+            seen.update(dict.fromkeys([message.caller, message.callee]))
+        return list(seen)
+
+
+def _find_graph(static_analysis: StaticAnalysisResults, entry: str) -> tuple[CallGraph, str]:
+    """The call graph holding *entry*, and its full name (*entry* may be a unique dotted suffix)."""
+    candidates: list[tuple[CallGraph, str]] = []
+    for language in static_analysis.get_languages():
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        if entry in cfg.nodes:
+            return cfg, entry
+        candidates.extend((cfg, name) for name in cfg.nodes if name.endswith(f".{entry}"))
+    if len(candidates) == 1:
+        return candidates[0]
+    if candidates:
+        listed = ", ".join(sorted(name for _, name in candidates)[:10])
+        raise SequenceError(f"'{entry}' is ambiguous: {listed}")
+    raise SequenceError(f"No symbol '{entry}' in the call graph")
+
+
+def _ordered_callees(cfg: CallGraph) -> dict[str, list[str]]:
+    """Callees of every caller, in call-site order."""
+    sites: dict[str, list[tuple[tuple, str]]] = {}
+    for edge in cfg.edges:
+        src, dst = edge.get_source(), edge.get_destination()
+        positions = [(site.get("line", 0), site.get("column", 0)) for site in edge.call_sites]
+        # Calls with no recorded site sort after every located one, by name.
+        key = (0, min(positions), dst) if positions else (1, (0, 0), dst)
+        sites.setdefault(src, []).append((key, dst))
+    return {src: [dst for _, dst in sorted(entries)] for src, entries in sites.items()}
+
+
+def build_sequence(
+    static_analysis: StaticAnalysisResults, entry: str, max_depth: int = DEFAULT_MAX_DEPTH
+) -> Sequence:
+    """Trace the calls made from *entry*, at most *max_depth* calls deep."""
+    cfg, entry = _find_graph(static_analysis, entry)
+    callees = _ordered_callees(cfg)
+    sequence = Sequence(entry=entry, max_depth=max_depth, messages=[])
+
+    def walk(caller: str, depth: int, stack: list[str]) -> None:
+        for callee in callees.get(caller, []):
+            if len(sequence.messages) >= MAX_MESSAGES:
+                sequence.truncated = True
+                return
+            recursion = callee in stack
+            sequence.messages.append(Message(caller, callee, depth, recursion))
+            if not recursion and depth < max_depth:
+                walk(callee, depth + 1, [*stack, callee])
+
+    walk(entry, 1, [entry])
+    return sequence
+
+
+def _label(qualified_name: str) -> str:
+    return qualified_name.replace('"', "'")
+
+
+def generate_plantuml(sequence: Sequence) -> str:
+    aliases = {name: f"P{index}" for index, name in enumerate(sequence.participants)}
+    lines = ["@startuml", f"title Calls from {_label(sequence.entry)} (max depth {sequence.max_depth})"]
+    lines.extend(f'participant "{_label(name)}" as {alias}' for name, alias in aliases.items())
+    # Callers whose activation is still open, innermost last.
+    active: list[tuple[str, int]] = []
+    for message in sequence.messages:
+        while active and active[-1][1] >= message.depth:
+            lines.append(f"deactivate {aliases[active.pop()[0]]}")
+        caller, callee = aliases[message.caller], aliases[message.callee]
+        lines.append(f"{caller} -> {callee} : {message.callee.rsplit('.', 1)[-1]}()")
+        if message.recursion:
+            lines.append(f"note right of {callee} : [recursion]")
+        elif message.depth < sequence.max_depth:
+            lines.append(f"activate {callee}")
+            active.append((message.callee, message.depth))
+    while active:
+        lines.append(f"deactivate {aliases[active.pop()[0]]}")
+    if sequence.truncated:
+        lines.append(f"note over {aliases[sequence.entry]} : trace cut at {MAX_MESSAGES} calls")
+    lines.append("@enduml")
+    return "\n".join(lines) + "\n"
+
+
+def write_sequence_diagram(
+    static_analysis: StaticAnalysisResults, entry: str, output_path: Path, max_depth: int = DEFAULT_MAX_DEPTH
+) -> tuple[Path, Sequence]:
+    sequence = build_sequence(static_analysis, entry, max_depth)
+    output_path.parent.mkdir(parents=True, exist_ok=True)
+    output_path.write_text(generate_plantuml(sequence), encoding="utf-8")
+    return output_path, sequence
@@ -0,0 +1,61 @@
+"""Deterministic, diff-friendly text snapshot of the component graph.
+
+Meant to be committed: one component, file or edge per line, sorted, with no
+LLM prose (descriptions and relation labels are left out), so a PR diff of the
+file shows exactly which components, file assignments and edges changed.
+"""
+
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from diagram_analysis.analysis_json import build_id_to_name_map, parse_unified_analysis
+
+SNAPSHOT_FILENAME = "architecture.snapshot"
+
+_HEADER = (
+    "# CodeBoarding architecture snapshot - generated, do not edit.\n"
+    "# Regenerate with: codeboarding --local <repo> --snapshot\n"
+)
+
+
+def _id_key(component_id: str) -> tuple[int | str, ...]:
+    """Sort ``1.10`` after ``1.9``."""
+    return tuple(int(part) if part.isdigit() else part for part in component_id.split("."))
+
+
+def generate_snapshot(root_analysis: AnalysisInsights, sub_analyses: dict[str, AnalysisInsights]) -> str:
+    """Render components, leaf file assignments and component edges as sorted lines."""
+    id_to_name = build_id_to_name_map(root_analysis, sub_analyses)
+    components: dict[str, Component] = {c.component_id: c for c in root_analysis.components}
+    for sub in sub_analyses.values():
+        components.update({c.component_id: c for c in sub.components})
+    ordered = sorted(components, key=_id_key)
+
+    lines = [_HEADER, "[components]"]
+    lines.extend(f"{cid} {components[cid].name}" for cid in ordered)
+
+    lines.append("\n[files]")
+    for cid in ordered:
+        if cid in sub_analyses:
+            continue  # expanded: its files are listed under its children
+        lines.extend(f"{cid} {path}" for path in sorted(set(components[cid].file_paths())))
+
+    lines.append("\n[edges]")
+    edges = {
+        (rel.src_id, rel.dst_id)
+        for analysis in (root_analysis, *sub_analyses.values())
+        for rel in analysis.components_relations
+        if rel.src_id and rel.dst_id and rel.src_id != rel.dst_id
+    }
+    for src, dst in sorted(edges, key=lambda e: (_id_key(e[0]), _id_key(e[1]))):
+        lines.append(f"{src} {id_to_name.get(src, src)} -> {dst} {id_to_name.get(dst, dst)}")
+    return "\n".join(lines) + "\n"
+
+
+def write_snapshot(analysis_path: Path, output_path: Path) -> Path:
+    """Write the snapshot of ``analysis.json`` at *analysis_path* to *output_path*."""
+    with open(analysis_path, "r", encoding="utf-8") as f:
+        root_analysis, sub_analyses = parse_unified_analysis(json.load(f))
+    output_path.write_text(generate_snapshot(root_analysis, sub_analyses), encoding="utf-8")
+    return output_path
@@ -0,0 +1,126 @@
+"""Syntax-highlighted source snippets for key entities in the rendered docs.
+
+With ``--snippets`` each key entity shows its signature and first few lines,
+read from the entity's recorded line range. What may be shown is governed by the
+project's ``[snippets]`` table in ``.codeboarding/config.toml``::
+
+    [snippets]
+    max_lines = 8                        # lines per snippet
+    exclude = ["config/**", "*secret*"]  # files whose code never appears in the docs
+    redact = true                        # mask string literals assigned to secret-looking names
+
+Highlighting uses Pygments: inline-styled HTML for ``.html`` output, and a
+language-tagged fence for markdown, which every markdown host highlights itself.
+"""
+
+import html
+import logging
+import re
+from dataclasses import dataclass, field
+from fnmatch import fnmatch
+from pathlib import Path
+
+from pygments import highlight
+from pygments.formatters import HtmlFormatter
+from pygments.lexers import get_lexer_for_filename
+from pygments.util import ClassNotFound
+
+from agents.agent_responses import SourceCodeReference
+from project_config import load_project_config
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_MAX_LINES = 8
+REDACTED = "<redacted>"
+
+# ``api_key = "..."``, ``"password": '...'``, ``token := "..."`` -> keep the name, mask the literal.
+_SECRET_ASSIGNMENT_RE = re.compile(
+    r"""(?P<name>[\w"']*(?:password|passwd|secret|token|api_?key|private_?key|credential)[\w"']*\s*(?::=|=|:)\s*)"""
+    r"""(?P<quote>["'`])(?P<value>[^"'`\n]+)(?P=quote)""",
+    re.IGNORECASE,
+)
+
+
+@dataclass
+class Snippet:
+    file_path: str
+    start_line: int
+    code: str
+    truncated: bool
+
+    @property
+    def language(self) -> str:
+        try:
+            return get_lexer_for_filename(self.file_path).aliases[0]
+        except ClassNotFound:
+            return ""
+
+
+def redact_secrets(code: str) -> str:
+    return _SECRET_ASSIGNMENT_RE.sub(lambda m: f"{m['name']}{m['quote']}{REDACTED}{m['quote']}", code)
+
+
+@dataclass
+class SnippetSource:
+    """Reads snippets from *repo_dir* under the project's ``[snippets]`` policy."""
+
+    repo_dir: Path
+    max_lines: int = DEFAULT_MAX_LINES
+    exclude: list[str] = field(default_factory=list)
+    redact: bool = True
+
+    @classmethod
+    def for_repo(cls, repo_dir: Path) -> "SnippetSource":
+        section = load_project_config(repo_dir).section("snippets")
+        return cls(
+            repo_dir=repo_dir,
+            max_lines=int(section.get("max_lines", DEFAULT_MAX_LINES)),
+            exclude=[str(p) for p in section.get("exclude", [])],
+            redact=bool(section.get("redact", True)),
+        )
+
+    def _relative(self, reference_file: str) -> str | None:
+        path = Path(reference_file)
+        if not path.is_absolute():
+            return path.as_posix()
+        try:
+            return path.relative_to(self.repo_dir).as_posix()
+        except ValueError:
+            return None
+
+    def extract(self, reference: SourceCodeReference) -> Snippet | None:
+        """The first ``max_lines`` of *reference*'s range, or None when unknown or excluded by policy."""
+        start, end = reference.reference_start_line, reference.reference_end_line
+        if not reference.reference_file or not start or not end or end < start:
+            return None
+        relative = self._relative(reference.reference_file)
+        if relative is None or any(fnmatch(relative, pattern) for pattern in self.exclude):
+            return None
+        try:
+            lines = (self.repo_dir / relative).read_text(encoding="utf-8", errors="replace").splitlines()
+        except OSError as e:
+            logger.debug(f"No snippet for {reference.qualified_name}: {e}")
+            return None
+        last = min(end, start + self.max_lines - 1, len(lines))
+        if last < start:
+            return None
+        code = "\n".join(lines[start - 1 : last])
+        if self.redact:
+            code = redact_secrets(code)
+        return Snippet(file_path=relative, start_line=start, code=code, truncated=last < end)
+
+
+def snippet_markdown(snippet: Snippet) -> str:
+    more = "\n  ..." if snippet.truncated else ""
+    body = "\n".join(f"  {line}" for line in snippet.code.splitlines())
+    return f"  ```{snippet.language}\n{body}{more}\n  ```\n"
+
+
+def snippet_html(snippet: Snippet) -> str:
+    try:
+        lexer = get_lexer_for_filename(snippet.file_path)
+    except ClassNotFound:
+        return f'<pre class="snippet"><code>{html.escape(snippet.code)}</code></pre>'
+    formatter = HtmlFormatter(noclasses=True, linenos="inline", linenostart=snippet.start_line, cssclass="snippet")
+    more = '<div class="snippet-more">...</div>' if snippet.truncated else ""
+    return highlight(snippet.code, lexer, formatter) + more
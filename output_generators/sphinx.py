@@ -1,9 +1,38 @@
+import re
+import unicodedata
 from pathlib import Path
 
 from agents.agent_responses import AnalysisInsights
-from static_analyzer.constants import NodeType
+from output_generators.ownership import ownership_sentence
+from output_generators.doc_templates import ComponentTemplate
+from output_generators.preamble import DocsPreamble
+from output_generators.test_listing import listed_tests, more_tests_note
+from static_analyzer.symbol_kinds import kind_label
 from utils import sanitize
 
+# Characters that start or end RST inline markup (emphasis, literals, substitutions, references).
+_RST_SPECIAL_RE = re.compile(r"([\\*`|_])")
+
+
+def escape_rst(text: str) -> str:
+    """Backslash-escape inline-markup characters so a component name renders literally."""
+    return _RST_SPECIAL_RE.sub(r"\\\1", text)
+
+
+def _underline(text: str, char: str) -> str:
+    """An underline at least as wide as *text*; docutils counts wide (e.g. CJK) characters as two columns."""
+    width = sum(2 if unicodedata.east_asian_width(c) in ("W", "F") else 1 for c in text)
+    return char * width
+
+
+def rst_label(name: str) -> str:
+    """The ``:ref:`` target for a generated document; namespaced so it can't clash with a host docs tree."""
+    return f"codeboarding-{sanitize(name).lower()}"
+
+
+def _mermaid_text(text: str) -> str:
+    return text.replace('"', "#quot;")
+
 
 def generated_mermaid_str(
     analysis: AnalysisInsights, expanded_components: set[str], repo_ref: str, project: str, demo=False
@@ -17,15 +46,18 @@ def generated_mermaid_str(
     for comp in analysis.components:
         node_key = sanitize(comp.name)
         # Show name in the node label
-        label = f"{comp.name}"
+        label = _mermaid_text(comp.name)
         lines.append(f'      {node_key}["{label}"]')
 
     # Add relations as labeled edges
     for rel in analysis.components_relations:
         src_key = sanitize(rel.src_name)
         dst_key = sanitize(rel.dst_name)
-        # Use the relation phrase as the edge label
-        lines.append(f'      {src_key} -- "{rel.relation}" --> {dst_key}')
+        # Use the relation phrase as the edge label; interface satisfaction is dotted
+        if rel.implements:
+            lines.append(f'      {src_key} -. "{_mermaid_text(rel.relation)}" .-> {dst_key}')
+        else:
+            lines.append(f'      {src_key} -- "{_mermaid_text(rel.relation)}" --> {dst_key}')
 
     # Linking to other files.
     for comp in analysis.components:
@@ -33,7 +65,9 @@ def generated_mermaid_str(
         if comp.component_id in expanded_components:
             # Create a link to the component's details file
             if not demo:
-                lines.append(f'      click {node_key} href "{repo_ref}/{node_key}.html" "Details"')
+                # Without a hosted repo_ref, link the sibling page Sphinx builds next to this one.
+                href = f"{repo_ref}/{node_key}.html" if repo_ref else f"{node_key}.html"
+                lines.append(f'      click {node_key} href "{href}" "Details"')
             else:
                 # For demo, link to a static URL
                 lines.append(
@@ -51,17 +85,26 @@ def generate_rst(
     demo=False,
     file_name: str = "",
     repo_path: Path = Path(),
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> str:
     """
     Generate a RST document from an AnalysisInsights object.
+
+    With *preamble*, its title replaces the one derived from *file_name* and its intro follows it verbatim.
+    With *doc_template* (``--doc-template``), it lays out each component section instead.
     """
     expanded_components = expanded_components or set()
 
-    # Use file_name to create a better title, replacing underscores with spaces
-    title = file_name.replace("_", " ").title()
-    title_underline = "=" * len(title)
+    if preamble and preamble.title:
+        title = escape_rst(preamble.title)
+    else:
+        # Use file_name to create a better title, replacing underscores with spaces
+        title = escape_rst(file_name.replace("_", " ").title())
 
-    lines = [title, title_underline, ""]
+    lines = [f".. _{rst_label(file_name)}:", "", title, _underline(title, "="), ""]
+    if preamble and preamble.intro:
+        lines.extend([preamble.intro, ""])
 
     # Add diagram
     diagram_str = generated_mermaid_str(
@@ -84,6 +127,12 @@ def generate_rst(
     )
     lines.append("   :target: mailto:contact@codeboarding.org")
 
+    # Expanded components have their own documents; list them so Sphinx links them into the tree.
+    expanded_names = [sanitize(c.name) for c in insights.components if c.component_id in expanded_components]
+    if expanded_names:
+        lines.extend(["", ".. toctree::", "   :hidden:", ""])
+        lines.extend(f"   {name}" for name in expanded_names)
+
     # Add project details
     lines.append("")
     lines.append("Details")
@@ -96,10 +145,19 @@ def generate_rst(
     root_dir = str(repo_path / project)
 
     for comp in insights.components:
+        if doc_template is not None:
+            lines.append(doc_template.render(insights, comp, expanded_components, repo_ref))
+            continue
         lines.append(component_header(comp.name, comp.component_id, expanded_components))
         lines.append("")
         lines.append(comp.description)
         lines.append("")
+        if comp.not_described:
+            lines.append(f"*Not described ({escape_rst(comp.not_described)}).*")
+            lines.append("")
+        if ownership := ownership_sentence(insights, comp):
+            lines.append(f"*{escape_rst(ownership)}*")
+            lines.append("")
 
         if comp.key_entities:
             lines.append("**Related Classes/Methods**:")
@@ -112,8 +170,8 @@ def generate_rst(
                 ref_file_normalized = str(Path(reference.reference_file)).replace("\\", "/")
                 root_dir_normalized = str(Path(root_dir)).replace("\\", "/")
 
-                if not ref_file_normalized.startswith(root_dir_normalized):
-                    lines.append(f"* {str(reference).replace('`', '')}")
+                if not repo_ref or not ref_file_normalized.startswith(root_dir_normalized):
+                    lines.append(f"* ``{str(reference).replace('`', '')}``")
                     continue
                 url = "/".join(repo_ref.split("/")[:7])
                 ref_url = url + ref_file_normalized.split(root_dir_normalized)[1]
@@ -131,6 +189,15 @@ def generate_rst(
             lines.append("**Related Classes/Methods**: *None*")
             lines.append("")
 
+        if comp.tests:
+            tests, hidden = listed_tests(comp)
+            lines.append("**Tests:**")
+            lines.append("")
+            lines.extend(f"* ``{test_id}``" for test_id in tests)
+            if hidden:
+                lines.append(f"* *{more_tests_note(hidden)}*")
+            lines.append("")
+
         if comp.file_methods:
             lines.append("**Source Files:**")
             lines.append("")
@@ -142,7 +209,7 @@ def generate_rst(
                     lines.append(f"* ``{group.file_path}``")
                 for method in group.methods:
                     # https://github.com/owner/repo/blob/branch -> 7 segments; file path follows after
-                    label = NodeType.from_name(method.node_type).label()
+                    label = kind_label(method.node_type, method.kind)
                     line_ref = f"L{method.start_line}-L{method.end_line}"
                     if url:
                         lines.append(
@@ -164,6 +231,8 @@ def generate_rst_file(
     temp_dir: Path,
     demo: bool = False,
     repo_path: Path = Path(),
+    preamble: DocsPreamble | None = None,
+    doc_template: ComponentTemplate | None = None,
 ) -> Path:
     """
     Generate a RST file with the given insights and save it to the specified directory.
@@ -176,6 +245,8 @@ def generate_rst_file(
         demo=demo,
         file_name=file_name,
         repo_path=repo_path,
+        preamble=preamble,
+        doc_template=doc_template,
     )
     rst_file = temp_dir / f"{file_name}.rst"
     with open(rst_file, "w", encoding="utf-8") as f:
@@ -187,11 +258,10 @@ def component_header(component_name: str, component_id: str, expanded_components
     """
     Generate a header for a component with its name and a reference to its details.
     """
-    sanitized_name = sanitize(component_name)
-    header_text = component_name
-    header_underline = "^" * len(header_text)
+    header_text = escape_rst(component_name)
+    header_underline = _underline(header_text, "^")
 
     if component_id in expanded_components:
-        return f"{header_text}\n{header_underline}\n\n:ref:`Expand <{sanitized_name}>`"
+        return f"{header_text}\n{header_underline}\n\n:ref:`Expand <{rst_label(component_name)}>`"
     else:
         return f"{header_text}\n{header_underline}"
@@ -0,0 +1,15 @@
+"""The "Tests" list shown under each component when ``--test-map`` mapped tests to it."""
+
+from agents.agent_responses import Component
+
+# Past this the docs point at analysis.json, which always has the full list.
+MAX_LISTED_TESTS = 15
+
+
+def listed_tests(comp: Component) -> tuple[list[str], int]:
+    """The test IDs to show and how many more are left out."""
+    return comp.tests[:MAX_LISTED_TESTS], max(0, len(comp.tests) - MAX_LISTED_TESTS)
+
+
+def more_tests_note(hidden: int) -> str:
+    return f"... and {hidden} more, listed in analysis.json"
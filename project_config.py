@@ -0,0 +1,112 @@
+"""Project-level configuration discovered under ``<repo>/.codeboarding/``.
+
+Everything a team customizes for one repository lives in that directory so it
+can be committed alongside the code:
+
+    .codeboarding/
+      config.toml          # this module: CLI option defaults, model overrides, feature tables
+      .codeboardingignore  # extra ignore patterns (repo_utils/ignore.py)
+      health/              # health check thresholds and exclusions (health/config.py)
+
+Precedence, lowest to highest: built-in defaults < ``~/.codeboarding/config.toml``
+(user) < ``<repo>/.codeboarding/`` (project) < CLI flags / environment variables.
+"""
+
+import logging
+import tomllib
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any
+
+from user_config import LLMUserConfig
+from utils import CODEBOARDING_DIR_NAME
+
+logger = logging.getLogger(__name__)
+
+PROJECT_CONFIG_FILENAME = "config.toml"
+
+# Options that locate the project itself, so the project config can't default them.
+_NON_DEFAULTABLE_OPTIONS = frozenset({"command", "local", "repositories"})
+
+
+@dataclass
+class ProjectConfig:
+    """Parsed ``<repo>/.codeboarding/config.toml``; an absent file yields an empty config.
+
+    ``options`` holds defaults for CLI flags (``depth_level = 4``), ``llm`` overrides
+    the user-level model choice, and every other table (``layers``, ``name_mappings``,
+    ``edge_exclusions``, ``dependency_rules``, ...) is kept verbatim for the feature
+    that owns it to read via :meth:`section`.
+    """
+
+    root: Path | None = None
+    options: dict[str, Any] = field(default_factory=dict)
+    llm: LLMUserConfig = field(default_factory=LLMUserConfig)
+    sections: dict[str, dict[str, Any]] = field(default_factory=dict)
+
+    def section(self, name: str) -> dict[str, Any]:
+        return self.sections.get(name, {})
+
+    def cli_defaults(self, known_options: set[str]) -> dict[str, Any]:
+        """Return ``[options]`` entries that map onto *known_options* (argparse dests).
+
+        Keys may use the flag spelling (``depth-level``) or the dest (``depth_level``).
+        Unknown keys are logged and skipped so a config shared across subcommands
+        doesn't break the ones that lack a flag.
+        """
+        defaults: dict[str, Any] = {}
+        for key, value in self.options.items():
+            dest = key.lstrip("-").replace("-", "_")
+            if dest in _NON_DEFAULTABLE_OPTIONS:
+                logger.warning(f"Ignoring '{key}' in project config: it cannot be set from the project itself")
+            elif dest in known_options:
+                defaults[dest] = value
+            else:
+                logger.debug(f"Project config option '{key}' does not apply to this command")
+        return defaults
+
+    def layer_llm(self, user_llm: LLMUserConfig) -> LLMUserConfig:
+        """Project ``[llm]`` model choices win over the user-level ones, field by field.
+
+        ``context_window`` stays user-level: it describes the provider account, not the project.
+        """
+        return LLMUserConfig(
+            agent_model=self.llm.agent_model or user_llm.agent_model,
+            parsing_model=self.llm.parsing_model or user_llm.parsing_model,
+            context_window=user_llm.context_window,
+        )
+
+
+def project_config_path(repo_root: Path) -> Path:
+    return repo_root / CODEBOARDING_DIR_NAME / PROJECT_CONFIG_FILENAME
+
+
+def load_project_config(repo_root: Path | None) -> ProjectConfig:
+    """Load ``<repo_root>/.codeboarding/config.toml``. Missing or unreadable file -> empty config."""
+    if repo_root is None:
+        return ProjectConfig()
+    path = project_config_path(repo_root)
+    root = path.parent
+    if not path.is_file():
+        return ProjectConfig(root=root if root.is_dir() else None)
+
+    try:
+        with open(path, "rb") as f:
+            data = tomllib.load(f)
+    except (OSError, tomllib.TOMLDecodeError) as e:
+        logger.warning(f"Ignoring unreadable project config {path}: {e}")
+        return ProjectConfig(root=root)
+
+    llm_data = data.pop("llm", {})
+    options = data.pop("options", {})
+    sections = {name: table for name, table in data.items() if isinstance(table, dict)}
+    logger.info(f"Loaded project config from {path}")
+    return ProjectConfig(
+        root=root,
+        options=options,
+        llm=LLMUserConfig(
+            agent_model=llm_data.get("agent_model") or None,
+            parsing_model=llm_data.get("parsing_model") or None,
+        ),
+        sections=sections,
+    )
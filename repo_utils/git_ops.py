@@ -62,6 +62,21 @@ def get_current_commit(repo_dir: Path) -> str | None:
         return None
 
 
+def get_commit_epoch(repo_dir: Path) -> int | None:
+    """Committer time of HEAD as a Unix timestamp, or ``None`` if git fails."""
+    try:
+        result = subprocess.run(
+            _git_argv("log", "-1", "--format=%ct", "HEAD"),
+            cwd=repo_dir,
+            capture_output=True,
+            **_GIT_TEXT_KWARGS,
+            check=True,
+        )
+        return int(result.stdout.strip())
+    except (OSError, subprocess.CalledProcessError, ValueError):
+        return None
+
+
 def require_current_commit(repo_dir: Path) -> str:
     """Return the current HEAD commit hash, raising on failure."""
     result = subprocess.run(
@@ -74,6 +89,52 @@ def require_current_commit(repo_dir: Path) -> str:
     return result.stdout.strip()
 
 
+def resolve_commit(repo_dir: Path, ref: str) -> str:
+    """Return the commit hash *ref* names, raising when it names none."""
+    result = subprocess.run(
+        _git_argv("rev-parse", "--verify", f"{ref}^{{commit}}"),
+        cwd=repo_dir,
+        capture_output=True,
+        **_GIT_TEXT_KWARGS,
+        check=True,
+    )
+    return result.stdout.strip()
+
+
+def get_merge_base(repo_dir: Path, first: str, second: str) -> str:
+    """Return the best common ancestor of two refs, raising when they share none."""
+    result = subprocess.run(
+        _git_argv("merge-base", first, second),
+        cwd=repo_dir,
+        capture_output=True,
+        **_GIT_TEXT_KWARGS,
+        check=True,
+    )
+    return result.stdout.strip()
+
+
+def add_worktree(repo_dir: Path, commit: str, worktree_dir: Path) -> None:
+    """Check *commit* out, detached, into a new linked worktree at *worktree_dir*."""
+    subprocess.run(
+        _git_argv("worktree", "add", "--detach", "--force", str(worktree_dir), commit),
+        cwd=repo_dir,
+        capture_output=True,
+        **_GIT_TEXT_KWARGS,
+        check=True,
+    )
+
+
+def remove_worktree(repo_dir: Path, worktree_dir: Path) -> None:
+    """Remove a worktree made by :func:`add_worktree`, with whatever the analysis wrote into it."""
+    subprocess.run(
+        _git_argv("worktree", "remove", "--force", str(worktree_dir)),
+        cwd=repo_dir,
+        capture_output=True,
+        **_GIT_TEXT_KWARGS,
+        check=True,
+    )
+
+
 def is_git_repository(repo_dir: Path) -> bool:
     """True iff *repo_dir* is inside a git work tree."""
     try:
@@ -1,4 +1,5 @@
 import logging
+from collections.abc import Sequence
 from pathlib import Path
 from typing import Any
 
@@ -170,10 +171,17 @@ class RepoIgnoreManager:
     patterns are defined in the CODEBOARDINGIGNORE_TEMPLATE and written to
     ``.codeboarding/.codeboardingignore`` on first run — users can then
     customize which patterns to keep, remove, or add.
+
+    The ``--include`` / ``--exclude`` globs (*include*, *exclude*) narrow the
+    analysis scope on top: matched against repo-relative paths, a file must
+    match one *include* glob (when any are given) and no *exclude* glob;
+    exclusions win.
     """
 
-    def __init__(self, repo_root: Path):
+    def __init__(self, repo_root: Path, include: Sequence[str] = (), exclude: Sequence[str] = ()):
         self.repo_root = repo_root.resolve()
+        self.include = tuple(include)
+        self.exclude = tuple(exclude)
         self.reload()
 
     def reload(self):
@@ -190,6 +198,22 @@ class RepoIgnoreManager:
         all_patterns.extend(codeboardingignore_patterns)
         self.spec = pathspec.PathSpec.from_lines("gitwildmatch", all_patterns)
 
+        self.include_spec = pathspec.PathSpec.from_lines("gitwildmatch", self.include) if self.include else None
+        self.exclude_spec = pathspec.PathSpec.from_lines("gitwildmatch", self.exclude)
+
+    def _outside_scope(self, rel_path: Path) -> bool:
+        """True when ``--exclude`` matches *rel_path*, or ``--include`` globs exist and none matches the file.
+
+        Include globs name files, so directories are never rejected by them:
+        ``src/**/*.go`` must not prune ``src/`` from a walk.
+        """
+        rel_str = rel_path.as_posix()
+        if self.exclude_spec.match_file(rel_str):
+            return True
+        if self.include_spec is None or (self.repo_root / rel_path).is_dir():
+            return False
+        return not self.include_spec.match_file(rel_str)
+
     def _load_gitignore_patterns(self) -> list[str]:
         """Load and parse .gitignore file if it exists."""
         gitignore_path = self.repo_root / GITIGNORE_FILENAME
@@ -247,7 +271,7 @@ class RepoIgnoreManager:
                     return True
 
             # Use pathspec for .gitignore + .codeboardingignore patterns
-            return self.spec.match_file(str(rel_path))
+            return self.spec.match_file(str(rel_path)) or self._outside_scope(rel_path)
         except Exception as e:
             logger.error(f"Error checking ignore status for {path}: {e}")
             return False
@@ -303,7 +327,7 @@ class RepoIgnoreManager:
     def categorize_file(self, path: Path) -> str:
         """Return the exclusion reason for a file.
 
-        Reasons for excluded files: "ignored_directory", "codeboardingignore", "gitignore".
+        Reasons for excluded files: "ignored_directory", "codeboardingignore", "gitignore", "analysis_scope".
         Returns "other" if the file is not excluded by any known rule.
         """
         try:
@@ -324,6 +348,8 @@ class RepoIgnoreManager:
                 return "codeboardingignore"
             if self.gitignore_spec.match_file(rel_str):
                 return "gitignore"
+            if self._outside_scope(rel_path):
+                return "analysis_scope"
 
             return "other"
         except Exception as e:
@@ -3,25 +3,45 @@ import time
 from dataclasses import dataclass, field
 from pathlib import Path
 
+from caching.stats import record_cache_access
+from monitoring.progress import Phase, get_progress
+from project_config import load_project_config
 from repo_utils.git_ops import get_changed_files_since
 from repo_utils.ignore import RepoIgnoreManager
-from static_analyzer.analysis_cache import StaticAnalysisCache
+from static_analyzer.analysis_cache import StaticAnalysisCache, changed_by_content_hash
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.constants import Language
 from static_analyzer.csharp_config_scanner import CSharpConfigScanner
+from static_analyzer.csharp_extensions import add_extension_edges
 from static_analyzer.engine.adapters import get_adapter
 from static_analyzer.engine.call_graph_builder import CallGraphBuilder
 from static_analyzer.engine.language_adapter import LanguageAdapter
 from static_analyzer.engine.lsp_client import LSPClient
+from static_analyzer.engine.lsp_dump import LSPDumpRecorder
 from static_analyzer.engine.result_converter import convert_to_codeboarding_format
 from static_analyzer.engine.source_inspector import SourceInspector
 from static_analyzer.engine.utils import uri_to_path
+from static_analyzer.framework_edges import Framework, add_framework_edges
+from static_analyzer.go_channels import add_channel_edges, add_spawn_edges
+from static_analyzer.go_embedding import add_embedding_edges
+from static_analyzer.go_generics import add_constraint_edges
+from static_analyzer.go_imports import add_import_edges
+from static_analyzer.go_main_package import reachable_go_files
+from static_analyzer.go_signatures import add_go_signatures
+from static_analyzer.go_syntax_fallback import DEGRADED_REASON, add_syntax_fallback
+from static_analyzer.go_variables import add_variable_edges
 from static_analyzer.graph import CallGraph
+from static_analyzer.http_routes import add_route_edges
 from static_analyzer.incremental_orchestrator import update_cfg_for_changed_files
+from static_analyzer.interface_dispatch import add_interface_dispatch_edges
 from static_analyzer.java_config_scanner import JavaConfigScanner
 from static_analyzer.lsp_client.diagnostics import FileDiagnosticsMap
+from static_analyzer.path_overrides import PathOverride, PathOverrides
 from static_analyzer.programming_language import ProgrammingLanguage
+from static_analyzer.scala_implicits import add_given_edges
 from static_analyzer.scanner import ProjectScanner
+from static_analyzer.schema_parser import build_schema_analysis, discover_schema_files
+from static_analyzer.standard_interfaces import load_standard_interfaces, tag_standard_interfaces
 from static_analyzer.typescript_config_scanner import TypeScriptConfigScanner
 from telemetry.events import track_lsp_result
 from tool_registry import ensure_node_on_path
@@ -37,11 +57,14 @@ class EngineConfig:
     ``source_files`` is non-empty only when a scanner has authoritatively
     resolved file membership (currently TypeScript via ``tsc --showConfig``);
     otherwise the adapter walks ``project_path`` itself in ``_run_full_analysis``.
+    ``path_override`` is the ``[[languages.paths]]`` entry the config was built
+    for, whose build tags and environment the client starts with.
     """
 
     adapter: LanguageAdapter
     project_path: Path
     source_files: list[Path] = field(default_factory=list)
+    path_override: PathOverride | None = None
 
 
 class StaticAnalysisFatalError(RuntimeError):
@@ -52,11 +75,16 @@ def _create_engine_configs(
     programming_languages: list[ProgrammingLanguage],
     repository_path: Path,
     ignore_manager: RepoIgnoreManager,
+    main_package: str | None = None,
+    path_overrides: PathOverrides | None = None,
 ) -> list[EngineConfig]:
     """Create one ``EngineConfig`` per sub-project from the detected languages.
 
     Handles monorepo support: for TypeScript/Java/C#, scans for multiple
-    project configurations and emits one entry per sub-project.
+    project configurations and emits one entry per sub-project. With
+    *main_package*, Go is restricted to that binary's import closure. With
+    *path_overrides*, each ``[[languages.paths]]`` entry gets its own config
+    and the detected ones give up the files it claims.
     """
     configs: list[EngineConfig] = []
 
@@ -139,15 +167,62 @@ def _create_engine_configs(
                 else:
                     logger.info("No C# projects detected")
 
+            elif lang_lower == Language.GO and main_package:
+                go_files = reachable_go_files(repository_path, main_package, ignore_manager)
+                configs.append(EngineConfig(adapter, repository_path, source_files=go_files))
+
             else:
                 configs.append(EngineConfig(adapter, repository_path))
 
         except RuntimeError as e:
             logger.error(f"Failed to create engine config for {pl.language}: {e}")
 
+    if path_overrides:
+        configs = _apply_path_overrides(configs, path_overrides, repository_path, ignore_manager)
     return configs
 
 
+def _apply_path_overrides(
+    configs: list[EngineConfig],
+    path_overrides: PathOverrides,
+    repository_path: Path,
+    ignore_manager: RepoIgnoreManager,
+) -> list[EngineConfig]:
+    """Hand the files claimed by ``[[languages.paths]]`` entries from the detected configs to one config per entry."""
+    result: list[EngineConfig] = []
+    for config in configs:
+        files = config.source_files or config.adapter.discover_source_files(config.project_path, ignore_manager)
+        kept = [f for f in files if path_overrides.owner(repository_path, f) is None]
+        if len(kept) == len(files):
+            result.append(config)
+        elif kept:
+            result.append(EngineConfig(config.adapter, config.project_path, source_files=kept))
+        else:
+            logger.info(f"Every {config.adapter.language} file in {config.project_path} is covered by a path override")
+
+    for entry in path_overrides.entries:
+        adapter_name = _lang_to_adapter_name(entry.language.value)
+        if adapter_name is None:
+            logger.warning(f"No engine adapter for language {entry.language.value}; skipping override {entry.glob}")
+            continue
+        adapter = get_adapter(adapter_name)
+        root = repository_path / entry.root
+        if not root.is_dir():
+            logger.warning(f"Path override {entry.glob}: root {entry.root} is not a directory; skipping")
+            continue
+        files = [
+            f
+            for f in adapter.discover_source_files(root, ignore_manager)
+            if path_overrides.owner(repository_path, f) is entry
+        ]
+        if not files:
+            logger.warning(f"Path override {entry.glob}: no {adapter.language} files matched; skipping")
+            continue
+        logger.info(f"Creating engine config for {adapter.language} at {entry.root} ({len(files)} files, {entry.glob})")
+        result.append(EngineConfig(adapter, root, source_files=files, path_override=entry))
+    return result
+
+
 def _lang_to_adapter_name(language: str) -> str | None:
     """Map a ProgrammingLanguage name to the engine adapter registry key."""
     mapping: dict[str, str] = {
@@ -162,6 +237,9 @@ def _lang_to_adapter_name(language: str) -> str | None:
         "java": "Java",
         "php": "PHP",
         "rust": "Rust",
+        "elixir": "Elixir",
+        "swift": "Swift",
+        "scala": "Scala",
     }
     return mapping.get(language.lower())
 
@@ -169,11 +247,30 @@ def _lang_to_adapter_name(language: str) -> str | None:
 class StaticAnalyzer:
     """Sole responsibility: Analyze the code using the engine LSP pipeline."""
 
-    def __init__(self, repository_path: Path, changed_files: set[Path] | None = None):
+    def __init__(
+        self,
+        repository_path: Path,
+        changed_files: set[Path] | None = None,
+        frameworks: tuple[Framework, ...] = (),
+        main_package: str | None = None,
+        dump_lsp_dir: Path | None = None,
+        resolve_interface_dispatch: bool = False,
+        lsp_concurrency: int | None = None,
+        lsp_timeout: int | None = None,
+        include: tuple[str, ...] = (),
+        exclude: tuple[str, ...] = (),
+    ):
         self.repository_path = repository_path.resolve()
-        self.ignore_manager = RepoIgnoreManager(self.repository_path)
+        self.ignore_manager = RepoIgnoreManager(self.repository_path, include, exclude)
         self.programming_langs = ProjectScanner(self.repository_path).scan()
-        self._engine_configs = _create_engine_configs(self.programming_langs, self.repository_path, self.ignore_manager)
+        # ``[[languages.paths]]`` in the project config: per-directory language and adapter settings.
+        project_config = load_project_config(self.repository_path)
+        self.path_overrides = PathOverrides.from_project_config(project_config)
+        # ``[standard_interfaces]``: the stdlib interfaces (``fmt.Stringer``, ...) types are tagged with.
+        self.standard_interfaces = load_standard_interfaces(project_config)
+        self._engine_configs = _create_engine_configs(
+            self.programming_langs, self.repository_path, self.ignore_manager, main_package, self.path_overrides
+        )
         self._engine_clients: list[tuple[EngineConfig, LSPClient]] = []
         self.collected_diagnostics: dict[Language, FileDiagnosticsMap] = {}
         self._clients_started: bool = False
@@ -189,6 +286,17 @@ class StaticAnalyzer:
         # e.g. the incremental fingerprint diff. ``None`` means "detect via git"
         # (the legacy CLI-on-a-real-checkout path); an empty set re-LSPs nothing.
         self.changed_files = changed_files
+        # Opt-in decorator/route edge passes (``--framework``) run over TS/JS, and over Go for
+        # ``http``, after every analyze().
+        self.frameworks = frameworks
+        # ``--dump-lsp``: each client records its raw responses; ``stop_clients`` writes them here.
+        self.dump_lsp_dir = dump_lsp_dir
+        # ``--resolve-interface-dispatch``: link Go interface method calls to the concrete methods.
+        self.resolve_interface_dispatch = resolve_interface_dispatch
+        # ``--concurrency``: symbol requests in flight at once (None = the CPU count).
+        self.lsp_concurrency = lsp_concurrency
+        # ``--lsp-timeout``: seconds one LSP request may take (None = the adapter's default).
+        self.lsp_timeout = lsp_timeout
         # ``stop_clients`` writes the pkl using ``_pending_source_sha`` as the
         # tag value (a diff-base for the next warm-start, NOT a cache gate).
         # ``analyze()`` updates it on every call so the latest run's SHA
@@ -230,6 +338,8 @@ class StaticAnalyzer:
         failed_languages: list[str] = []
         failed_details: list[str] = []
 
+        progress = get_progress()
+        progress.start(Phase.LSP_STARTUP, len(self._engine_configs), unit="server")
         for engine_config in self._engine_configs:
             adapter, project_path = engine_config.adapter, engine_config.project_path
             attempted.append(adapter.language)
@@ -247,18 +357,26 @@ class StaticAnalyzer:
                 # a Node-less host the embedded runtime's dir must be on PATH.
                 ensure_node_on_path(command, extra_env)
                 workspace_settings = adapter.get_workspace_settings()
+                if (override := engine_config.path_override) is not None:
+                    extra_env = {**extra_env, **override.env}
+                    if build_tag_settings := adapter.get_build_tag_settings(override.build_tags):
+                        init_options = {**init_options, **build_tag_settings}
+                        workspace_settings = {**(workspace_settings or {}), **build_tag_settings}
                 extra_capabilities = getattr(adapter, "extra_client_capabilities", {}) or {}
                 engine_client = LSPClient(
                     command=command,
                     project_root=project_path,
                     init_options=init_options,
-                    default_timeout=adapter.get_lsp_default_timeout(),
+                    default_timeout=self.lsp_timeout or adapter.get_lsp_default_timeout(),
                     collect_diagnostics=True,
                     extra_env=extra_env,
                     workspace_settings=workspace_settings,
                     extra_client_capabilities=extra_capabilities,
+                    dump_recorder=self._dump_recorder_for(engine_config, command),
                 )
-                engine_client.start()
+                init_result = engine_client.start()
+                if engine_client.dump_recorder is not None and isinstance(init_result, dict):
+                    engine_client.dump_recorder.server_info = init_result.get("serverInfo")
                 t_lsp_started = time.monotonic()
                 logger.info(f"{adapter.language} LSP start: {t_lsp_started - t_start:.1f}s")
 
@@ -288,6 +406,8 @@ class StaticAnalyzer:
                         logger.exception(
                             f"Error shutting down partially-started {adapter.language} client during cleanup"
                         )
+            progress.advance(Phase.LSP_STARTUP, unit="server")
+        progress.finish(Phase.LSP_STARTUP)
 
         if not started:
             self._clients_started = False
@@ -308,6 +428,16 @@ class StaticAnalyzer:
         self._engine_clients = started
         self._clients_started = True
 
+    def _dump_recorder_for(self, engine_config: EngineConfig, command: list[str]) -> LSPDumpRecorder | None:
+        """``--dump-lsp`` recorder namespaced by language, plus the sub-project for per-project servers."""
+        if self.dump_lsp_dir is None:
+            return None
+        out_dir = self.dump_lsp_dir / engine_config.adapter.language.lower()
+        project_path = engine_config.project_path.resolve()
+        if project_path != self.repository_path and project_path.is_relative_to(self.repository_path):
+            out_dir = out_dir / project_path.relative_to(self.repository_path)
+        return LSPDumpRecorder(out_dir, engine_config.adapter.language, project_path, command)
+
     def stop_clients(self) -> None:
         """Gracefully shut down all engine LSP server processes. Idempotent.
 
@@ -321,6 +451,8 @@ class StaticAnalyzer:
         if self._results_need_saving:
             self.flush_cache()
         for engine_config, client in self._engine_clients:
+            if client.dump_recorder is not None:
+                client.dump_recorder.write()
             try:
                 client.shutdown()
             except Exception as e:
@@ -329,6 +461,22 @@ class StaticAnalyzer:
         self._clients_started = False
         self._cached_results = None
 
+    def restart_crashed_clients(self) -> list[str]:
+        """Restart every client if any server process has exited; returns the languages whose server died.
+
+        For long-lived sessions (``codeboarding watch``): a crashed server
+        would fail every later query. Results analysed so far are saved by
+        ``stop_clients`` first, so the next ``analyze()`` warm-starts from them.
+        """
+        if not self._clients_started:
+            return []
+        crashed = [config.adapter.language for config, client in self._engine_clients if not client.is_running]
+        if crashed:
+            logger.warning(f"LSP server(s) for {', '.join(crashed)} exited; restarting the language servers")
+            self.stop_clients()
+            self.start_clients()
+        return crashed
+
     def flush_cache(self) -> None:
         """Write ``_cached_results`` to the SHA-tagged pkl at ``_pending_cache_dir``.
 
@@ -444,7 +592,7 @@ class StaticAnalyzer:
         for engine_config, client in self._engine_clients:
             if suffix in engine_config.adapter.file_extensions:
                 try:
-                    symbols = client.document_symbol(file_path)
+                    symbols = engine_config.adapter.normalize_document_symbols(client.document_symbol(file_path))
                     logger.debug(f"Got {len(symbols)} symbols for {file_path}")
                     return symbols
                 except Exception:
@@ -524,7 +672,8 @@ class StaticAnalyzer:
         1. In-memory cache hit -> return.
         2. ``skip_cache=True`` -> full LSP analysis.
         3. Pkl present -> load it, scope the warm-start to ``self.changed_files``
-           (or git when that is ``None``), re-LSP just those, merge in memory.
+           (or, when that is ``None``, the files whose content hash differs from
+           the saved manifest, else git), re-LSP just those, merge in memory.
         4. No pkl -> full LSP.
 
         Persistence is deferred to ``stop_clients`` so downstream mutations
@@ -542,6 +691,7 @@ class StaticAnalyzer:
 
         if not skip_cache and self._cached_results is not None:
             logger.info("static_analysis_cache: outcome=memhit")
+            record_cache_access("static_analysis", hit=True)
             return self._cached_results
 
         logger.info(f"analyze() called with skip_cache={skip_cache}, source_sha={'<set>' if source_sha else None}")
@@ -550,22 +700,43 @@ class StaticAnalyzer:
 
         if skip_cache:
             logger.info("static_analysis_cache: outcome=bypass (skip_cache=True)")
+            record_cache_access("static_analysis", hit=False)
             results = self._run_full_lsp_pass()
         else:
             warm_start = cache.load_with_sha()
             if warm_start is None:
                 logger.info("static_analysis_cache: outcome=miss_absent")
+                record_cache_access("static_analysis", hit=False)
                 results = self._run_full_lsp_pass()
             else:
                 cached_results, cached_sha = warm_start
+                file_hashes = cache.read_file_hashes() if self.changed_files is None else None
                 logger.info(
                     "static_analysis_cache: outcome=warmstart (cached_sha=%s, current_sha=%s, changes=%s)",
                     cached_sha,
                     source_sha or "<none>",
-                    "supplied" if self.changed_files is not None else "git",
+                    "supplied" if self.changed_files is not None else "hashes" if file_hashes is not None else "git",
                 )
-                results = self._update_cached_results(cached_results, cached_sha)
-
+                record_cache_access("static_analysis", hit=True)
+                results = self._update_cached_results(cached_results, cached_sha, file_hashes)
+
+        self._absorb_schema_files(results)
+        # Every pass re-runs after every analyze(), warm starts included, and skips the edges it already
+        # added, so re-LSPed files regain what their fresh call graph lacks. Order matters: the syntax
+        # fallback first, so parsed declarations get signatures and edges too; signatures and embedding
+        # before interface dispatch and the standard-interface tags, which match against them.
+        self._add_syntax_fallback(results)
+        self._add_framework_edges(results)
+        self._add_go_signatures(results)
+        self._add_channel_edges(results)
+        self._add_import_edges(results)
+        self._add_embedding_edges(results)
+        self._add_constraint_edges(results)
+        self._add_variable_edges(results)
+        self._add_given_edges(results)
+        self._add_extension_edges(results)
+        self._add_interface_dispatch_edges(results)
+        self._tag_standard_interfaces(results)
         self._validate_analysis_results(results)
         results.diagnostics = self.collected_diagnostics
         self._cached_results = results
@@ -594,6 +765,7 @@ class StaticAnalyzer:
                 duration_ms = round((time.monotonic() - t_lang_start) * 1000)
                 logger.info(f"Engine analysis for {adapter.language} completed in {duration_ms / 1000:.1f}s")
                 self._collect_diagnostics_for(adapter, engine_client, analysis)
+                self._record_timed_out_files(results, language, engine_client)
                 track_lsp_result(
                     language=adapter.language_enum.value,
                     loc=self._loc_for_adapter(adapter),
@@ -635,6 +807,7 @@ class StaticAnalyzer:
         self,
         cached_results: StaticAnalysisResults,
         cached_sha: str,
+        file_hashes: dict[str, str] | None = None,
     ) -> StaticAnalysisResults:
         """Bring *cached_results* up to date in-memory, scoped to the changed files.
 
@@ -646,7 +819,9 @@ class StaticAnalyzer:
         run still finds a cluster baseline.
 
         Changed-file source: ``self.changed_files`` when set at construction
-        (git-free — e.g. the wrapper's fingerprint diff), else ``git diff`` via
+        (git-free — e.g. the wrapper's fingerprint diff), else the content-hash
+        manifest saved with the pkl (*file_hashes*, see
+        ``_changed_files_by_hash``), else ``git diff`` via
         ``get_changed_files_since``. If git fails (*cached_sha* unreachable, a
         non-git frozen copy, or a content-hash SHA that isn't a git object), fall
         back to a full re-LSP for that language so the run still produces valid
@@ -658,18 +833,44 @@ class StaticAnalyzer:
             language = adapter.language_enum
             cached_lang_dict = self._extract_language_dict(cached_results, language)
             t_lang_start = time.monotonic()
-            changed_files = self._changed_files_for_language(project_path, cached_sha, adapter.language)
+            if self.changed_files is None and file_hashes is not None:
+                changed_files = self._changed_files_by_hash(engine_config, cached_lang_dict, file_hashes)
+            else:
+                changed_files = self._changed_files_for_language(project_path, cached_sha, adapter.language)
+            if changed_files is not None and self.path_overrides:
+                # Re-LSP each changed file only in the config that owns it.
+                changed_files = {
+                    f
+                    for f in changed_files
+                    if self.path_overrides.owner(self.repository_path, f) is engine_config.path_override
+                }
 
             if changed_files is None:
                 analysis = self._run_full_analysis(engine_config, engine_client)
             else:
                 logger.info(f"warmstart {adapter.language}: re-LSPing {len(changed_files)} changed file(s)")
                 analysis = update_cfg_for_changed_files(
-                    cached_lang_dict, changed_files, adapter, project_path, engine_client, self.ignore_manager
+                    cached_lang_dict,
+                    changed_files,
+                    adapter,
+                    project_path,
+                    engine_client,
+                    self.ignore_manager,
+                    self.lsp_concurrency,
                 )
 
             self._absorb_into_results(results, language, analysis)
             self._collect_diagnostics_for(adapter, engine_client, analysis)
+            # Files not re-LSPed keep what their last analysis missed.
+            carried: list[str] = []
+            if changed_files is not None:
+                changed = {str(f.resolve()) for f in changed_files}
+                cached_files = getattr(cached_results, "timed_out_files", {}).get(language, [])
+                carried = [f for f in cached_files if f not in changed]
+                cached_degraded = getattr(cached_results, "degraded_files", {}).get(language, [])
+                if degraded := [f for f in cached_degraded if f not in changed]:
+                    results.degraded_files[language] = degraded
+            self._record_timed_out_files(results, language, engine_client, carried)
             track_lsp_result(
                 language=adapter.language_enum.value,
                 loc=self._loc_for_adapter(adapter),
@@ -701,6 +902,44 @@ class StaticAnalyzer:
             )
             return None
 
+    def _changed_files_by_hash(
+        self, engine_config: EngineConfig, cached_lang_dict: dict, file_hashes: dict[str, str]
+    ) -> set[Path]:
+        """Files of one language whose content changed since the pkl, plus the files that import them.
+
+        A file is changed when it is new, deleted, or its hash differs from
+        *file_hashes*. Files of the packages that import a changed file's
+        package (per the cached package relations) are re-queried too, so
+        their references into the changed code are resolved afresh.
+        """
+        adapter, project_path = engine_config.adapter, engine_config.project_path
+        current = engine_config.source_files or adapter.discover_source_files(project_path, self.ignore_manager)
+        known = {
+            path: digest
+            for path, digest in file_hashes.items()
+            if Path(path).suffix in adapter.file_extensions and Path(path).is_relative_to(project_path)
+        }
+        changed = changed_by_content_hash(known, current)
+        if not changed:
+            return changed
+
+        relations = cached_lang_dict.get("package_relations") or {}
+        changed_packages = {adapter.get_package_for_file(f, project_path) for f in changed}
+        importers = {
+            importer
+            for package in changed_packages
+            for importer in relations.get(package, {}).get("imported_by", [])
+            if importer not in changed_packages
+        }
+        dependents = {
+            f for f in current if f not in changed and adapter.get_package_for_file(f, project_path) in importers
+        }
+        logger.info(
+            f"warmstart {adapter.language}: {len(changed)} file(s) changed by content hash, "
+            f"{len(dependents)} importing file(s) re-queried with them"
+        )
+        return changed | dependents
+
     def _extract_language_dict(self, cached_results: StaticAnalysisResults, language: Language) -> dict:
         """Project a single language's bucket out of ``StaticAnalysisResults`` into the dict shape ``update_cfg_for_changed_files`` expects."""
         try:
@@ -735,6 +974,125 @@ class StaticAnalyzer:
         results.add_package_dependencies(language, analysis.get("package_relations", {}))
         results.add_source_files(language, [str(f) for f in analysis.get("source_files", [])])
 
+    def _absorb_schema_files(self, results: StaticAnalysisResults) -> None:
+        """Parse ``.proto`` / ``.graphql`` files into their own language buckets.
+
+        Why: schemas are re-parsed on every run (cheap, no LSP) so warm starts
+        never need to merge stale schema graphs.
+        """
+        for language, files in discover_schema_files(self.repository_path, self.ignore_manager).items():
+            try:
+                self._absorb_into_results(results, language, build_schema_analysis(language, files))
+            except Exception as e:
+                logger.error(f"Error during schema analysis for {language}: {e}")
+
+    def _add_framework_edges(self, results: StaticAnalysisResults) -> None:
+        """Add NestJS/Angular injection and registration edges to the TS/JS call graphs, and Go HTTP routes."""
+        if Framework.HTTP in self.frameworks and Language.GO in results.get_languages():
+            add_route_edges(results.get_cfg(Language.GO), results.get_source_files(Language.GO))
+        decorator_frameworks = [framework for framework in self.frameworks if framework is not Framework.HTTP]
+        if not decorator_frameworks:
+            return
+        for language in (Language.TYPESCRIPT, Language.JAVASCRIPT):
+            if language not in results.get_languages():
+                continue
+            call_graph = results.get_cfg(language)
+            source_files = results.get_source_files(language)
+            for framework in decorator_frameworks:
+                add_framework_edges(call_graph, source_files, framework)
+
+    def _add_channel_edges(self, results: StaticAnalysisResults) -> None:
+        """Link Go functions that send on a channel to the ones receiving from it, and to the goroutines they start."""
+        if Language.GO in results.get_languages():
+            cfg, source_files = results.get_cfg(Language.GO), results.get_source_files(Language.GO)
+            add_channel_edges(cfg, source_files)
+            add_spawn_edges(cfg, source_files)
+
+    def _add_import_edges(self, results: StaticAnalysisResults) -> None:
+        """Add the Go calls made through dot imports and link importers to the ``init``s of blank imports."""
+        if Language.GO in results.get_languages():
+            add_import_edges(results.get_cfg(Language.GO), results.get_source_files(Language.GO))
+
+    def _add_syntax_fallback(self, results: StaticAnalysisResults) -> None:
+        """Parse the Go files gopls returned no symbols for, as when the project does not build, and flag them."""
+        if Language.GO not in results.get_languages():
+            return
+        try:
+            cfg = results.get_cfg(Language.GO)
+        except ValueError:
+            return
+        # Files an earlier run parsed come back from the cache: they stay flagged until gopls analyzes them.
+        known = results.degraded_files.get(Language.GO, [])
+        reference_nodes = list(results.iter_reference_nodes(Language.GO))
+        degraded: set[str] = set()
+        for engine_config, _ in self._engine_clients:
+            if engine_config.adapter.language_enum is not Language.GO:
+                continue
+            root = engine_config.project_path.resolve()
+            files = [
+                f
+                for f in results.get_source_files(Language.GO)
+                if Path(f).is_relative_to(root)
+                and (
+                    not self.path_overrides
+                    or self.path_overrides.owner(self.repository_path, Path(f)) is engine_config.path_override
+                )
+            ]
+            added, files_degraded = add_syntax_fallback(cfg, files, reference_nodes, engine_config.adapter, root, known)
+            results.add_references(Language.GO, added)
+            degraded.update(files_degraded)
+        if not degraded:
+            results.degraded_files.pop(Language.GO, None)
+            return
+        logger.warning(f"go: {len(degraded)} file(s) {DEGRADED_REASON}: parsed without gopls, edges best-effort")
+        results.degraded_files[Language.GO] = sorted(degraded)
+
+    def _add_go_signatures(self, results: StaticAnalysisResults) -> None:
+        """Record the declared parameters and results of Go functions and function types, and the ``returns`` edges."""
+        if Language.GO in results.get_languages():
+            add_go_signatures(results.get_cfg(Language.GO), results.iter_reference_nodes(Language.GO))
+
+    def _add_embedding_edges(self, results: StaticAnalysisResults) -> None:
+        """Link Go types to the types they embed, so promoted methods resolve to their definition."""
+        if Language.GO in results.get_languages():
+            add_embedding_edges(results.get_cfg(Language.GO), results.iter_reference_nodes(Language.GO))
+
+    def _add_constraint_edges(self, results: StaticAnalysisResults) -> None:
+        """Link Go generics to their constraint interfaces and explicit type arguments to the constraints."""
+        if Language.GO in results.get_languages():
+            add_constraint_edges(results.get_cfg(Language.GO), results.get_source_files(Language.GO))
+
+    def _add_variable_edges(self, results: StaticAnalysisResults) -> None:
+        """Link Go functions to the package variables they write, and follow calls through func-valued ones."""
+        if Language.GO in results.get_languages():
+            add_variable_edges(results.get_cfg(Language.GO), results.iter_reference_nodes(Language.GO))
+
+    def _add_given_edges(self, results: StaticAnalysisResults) -> None:
+        """Link Scala functions and classes to the given/implicit instances passed for their context parameters."""
+        if Language.SCALA in results.get_languages():
+            add_given_edges(results.get_cfg(Language.SCALA), results.iter_reference_nodes(Language.SCALA))
+
+    def _add_extension_edges(self, results: StaticAnalysisResults) -> None:
+        """Attribute C# extension methods to the project types they extend."""
+        if Language.CSHARP in results.get_languages():
+            add_extension_edges(results.get_cfg(Language.CSHARP), results.iter_reference_nodes(Language.CSHARP))
+
+    def _add_interface_dispatch_edges(self, results: StaticAnalysisResults) -> None:
+        """Link Go calls through an interface to the implementations' methods (``--resolve-interface-dispatch``)."""
+        if not self.resolve_interface_dispatch or Language.GO not in results.get_languages():
+            return
+        try:
+            hierarchy = results.get_hierarchy(Language.GO)
+        except ValueError:
+            return
+        add_interface_dispatch_edges(results.get_cfg(Language.GO), hierarchy)
+
+    def _tag_standard_interfaces(self, results: StaticAnalysisResults) -> None:
+        """Tag types with the standard-library interfaces their method sets satisfy."""
+        for language, interfaces in self.standard_interfaces.items():
+            if language in results.get_languages():
+                tag_standard_interfaces(results.get_cfg(language), results.iter_reference_nodes(language), interfaces)
+
     def _collect_diagnostics_for(self, adapter: LanguageAdapter, engine_client: LSPClient, analysis: dict) -> None:
         """Merge cached + live diagnostics for one adapter into ``self.collected_diagnostics``.
 
@@ -759,6 +1117,19 @@ class StaticAnalyzer:
             )
         self.collected_diagnostics[adapter.language_enum] = merged_diags
 
+    def _record_timed_out_files(
+        self,
+        results: StaticAnalysisResults,
+        language: Language,
+        engine_client: LSPClient,
+        carried: list[str] | None = None,
+    ) -> None:
+        """Record the files timed-out LSP requests left partially analyzed, for the run's coverage report."""
+        files = {str(f) for f in engine_client.pop_timed_out_files()} | set(carried or ())
+        if files:
+            logger.warning(f"{language.value}: {len(files)} file(s) partially analyzed after LSP timeouts")
+            results.timed_out_files[language] = sorted(files)
+
     def _loc_for_adapter(self, adapter: LanguageAdapter) -> int:
         """Return scanner LOC that should have been covered by this adapter."""
         adapter_name = adapter.language.lower()
@@ -797,7 +1168,7 @@ class StaticAnalyzer:
         logger.info(f"Analyzing {len(source_files)} {adapter.language} files")
 
         t_build_start = time.monotonic()
-        builder = CallGraphBuilder(engine_client, adapter, project_path)
+        builder = CallGraphBuilder(engine_client, adapter, project_path, self.lsp_concurrency)
         engine_result = builder.build(source_files)
         logger.info(f"CallGraphBuilder.build() for {adapter.language}: {time.monotonic() - t_build_start:.1f}s")
         if adapter.fail_on_empty_symbols is True and not builder.symbol_table.symbols:
@@ -840,6 +1211,14 @@ def get_static_analysis(
     skip_cache: bool = False,
     source_sha: str | None = None,
     changed_files: set[Path] | None = None,
+    frameworks: tuple[Framework, ...] = (),
+    main_package: str | None = None,
+    dump_lsp_dir: Path | None = None,
+    resolve_interface_dispatch: bool = False,
+    lsp_concurrency: int | None = None,
+    lsp_timeout: int | None = None,
+    include: tuple[str, ...] = (),
+    exclude: tuple[str, ...] = (),
 ) -> StaticAnalysisResults:
     """CLI orchestrator: get static analysis results with full LSP lifecycle management.
 
@@ -855,15 +1234,35 @@ def get_static_analysis(
         source_sha: Canonical source-state identifier (typically a git tree SHA)
             stamped onto the freshly-saved pkl as a diff base for the next
             warm-start.
+        frameworks: Frameworks whose decorator-driven edges to add (``--framework``).
+        main_package: Go ``main`` package whose import closure bounds the Go analysis (``--main-package``).
+        dump_lsp_dir: Write raw LSP responses here (``--dump-lsp``); implies ``skip_cache`` so every file is queried.
+        resolve_interface_dispatch: Link Go interface method calls to every implementation
+            (``--resolve-interface-dispatch``).
+        lsp_concurrency: Symbol requests in flight at once (``--concurrency``); None uses the CPU count.
+        lsp_timeout: Seconds one LSP request may take (``--lsp-timeout``); None uses the adapter's default.
+        include: ``--include`` globs; only matching files are analysed.
+        exclude: ``--exclude`` globs; matching files are not analysed.
 
     Returns:
         StaticAnalysisResults reflecting the live source state.
     """
-    analyzer = StaticAnalyzer(repo_path, changed_files=changed_files)
+    analyzer = StaticAnalyzer(
+        repo_path,
+        changed_files=changed_files,
+        frameworks=frameworks,
+        main_package=main_package,
+        dump_lsp_dir=dump_lsp_dir,
+        resolve_interface_dispatch=resolve_interface_dispatch,
+        lsp_concurrency=lsp_concurrency,
+        lsp_timeout=lsp_timeout,
+        include=include,
+        exclude=exclude,
+    )
     with analyzer:
         results = analyzer.analyze(
             cache_dir=cache_dir,
-            skip_cache=skip_cache,
+            skip_cache=skip_cache or dump_lsp_dir is not None,
             source_sha=source_sha,
         )
     results.diagnostics = analyzer.collected_diagnostics
@@ -11,6 +11,11 @@ Two layers, both backing the warm-start incremental flow:
   from a changed file, re-LSP just those files, and merge the fresh state
   back into the kept-from-cache state.
 
+A third file, ``static_analysis.hashes.json``, records the content hash of
+every analysed source file. Warm starts diff it against the working tree
+(:func:`changed_by_content_hash`), so uncommitted edits and non-git
+checkouts are scoped to the files that actually changed.
+
 ``copy_cache_files`` is the wrapper-side promotion primitive: an opaque
 atomic copy of the pkl + sha pair between two artifact directories.
 """
@@ -18,12 +23,15 @@ atomic copy of the pkl + sha pair between two artifact directories.
 from __future__ import annotations
 
 import copy
+import hashlib
+import json
 import logging
 import os
 import pickle
 import shutil
 import sys
 import tempfile
+from collections.abc import Iterable
 from pathlib import Path
 from typing import TYPE_CHECKING, Any
 
@@ -47,6 +55,7 @@ logger = logging.getLogger(__name__)
 STATIC_ANALYSIS_PKL = "static_analysis.pkl"
 STATIC_ANALYSIS_SHA = "static_analysis.sha"
 STATIC_ANALYSIS_LOCK = "static_analysis.lock"
+STATIC_ANALYSIS_HASHES = "static_analysis.hashes.json"
 # Legacy location ``StaticAnalysisCache`` wrote to before the run-artifact
 # split. Kept for one-time read fallback so CLI users transition smoothly.
 _LEGACY_PKL_NAME = "static_analysis_results.pkl"
@@ -55,6 +64,8 @@ _LEGACY_CACHE_SUBDIR = "cache"
 # v2: StaticAnalysisResults switched from dict-of-dicts to LanguageResults
 # dataclass storage. v1 pickles will be treated as cache misses and re-run.
 _TAG_VERSION = "v2"
+# Version stamp of the content-hash manifest; other versions are ignored.
+_HASHES_VERSION = 1
 
 
 class StaticAnalysisCache:
@@ -88,6 +99,12 @@ class StaticAnalysisCache:
             lang: {self._to_relative(fp): diags for fp, diags in file_map.items()}
             for lang, file_map in portable.diagnostics.items()
         }
+        portable.timed_out_files = {
+            lang: [self._to_relative(fp) for fp in files] for lang, files in portable.timed_out_files.items()
+        }
+        portable.degraded_files = {
+            lang: [self._to_relative(fp) for fp in files] for lang, files in portable.degraded_files.items()
+        }
         return portable
 
     def _absolutize(self, result: "StaticAnalysisResults") -> "StaticAnalysisResults":
@@ -98,6 +115,15 @@ class StaticAnalysisCache:
             lang: {self._to_absolute(fp): diags for fp, diags in file_map.items()}
             for lang, file_map in result.diagnostics.items()
         }
+        # Why: getattr — results pickled before timeouts and degraded files were recorded lack the fields.
+        result.timed_out_files = {
+            lang: [self._to_absolute(fp) for fp in files]
+            for lang, files in getattr(result, "timed_out_files", {}).items()
+        }
+        result.degraded_files = {
+            lang: [self._to_absolute(fp) for fp in files]
+            for lang, files in getattr(result, "degraded_files", {}).items()
+        }
         return result
 
     @property
@@ -112,6 +138,47 @@ class StaticAnalysisCache:
     def lock_path(self) -> Path:
         return self.artifact_dir / STATIC_ANALYSIS_LOCK
 
+    @property
+    def hashes_path(self) -> Path:
+        return self.artifact_dir / STATIC_ANALYSIS_HASHES
+
+    def read_file_hashes(self) -> dict[str, str] | None:
+        """Absolute path -> content hash of each file the pkl was built from.
+
+        ``None`` when the manifest is absent, unreadable or has another
+        version; the warm start then falls back to git for the changed files.
+        """
+        if not self.hashes_path.exists():
+            return None
+        with FileLock(self.lock_path, timeout=30):
+            try:
+                manifest = json.loads(self.hashes_path.read_text(encoding="utf-8"))
+            except (OSError, ValueError) as e:
+                logger.warning(f"Failed to read {STATIC_ANALYSIS_HASHES}: {e}")
+                return None
+        if not isinstance(manifest, dict) or manifest.get("version") != _HASHES_VERSION:
+            logger.info(f"{STATIC_ANALYSIS_HASHES} has an unknown version; ignoring it")
+            return None
+        return {self._to_absolute(path): digest for path, digest in manifest.get("files", {}).items()}
+
+    def _write_file_hashes_unlocked(self, result: "StaticAnalysisResults") -> None:
+        files: dict[str, str] = {}
+        for file_path in result.get_all_source_files():
+            digest = file_content_hash(Path(file_path))
+            if digest is not None:
+                files[self._to_relative(str(file_path))] = digest
+        manifest = {"version": _HASHES_VERSION, "files": dict(sorted(files.items()))}
+        fd, tmp = tempfile.mkstemp(dir=self.artifact_dir, suffix=".json.tmp")
+        try:
+            with open(fd, "w", encoding="utf-8", newline="\n") as f:
+                json.dump(manifest, f, indent=1)
+            Path(tmp).replace(self.hashes_path)
+        except Exception as e:
+            Path(tmp).unlink(missing_ok=True)
+            # A stale manifest would hide edits made since it was written.
+            self.hashes_path.unlink(missing_ok=True)
+            logger.warning(f"Failed to write {STATIC_ANALYSIS_HASHES}: {e}")
+
     def read_tag_sha(self) -> str | None:
         """Return the source SHA the pkl was saved at, or None if absent/unparsable.
 
@@ -248,6 +315,8 @@ class StaticAnalysisCache:
                 logger.warning(f"Failed to save static analysis cache: {e}")
                 return
 
+            self._write_file_hashes_unlocked(result)
+
             # Write the sibling tag last so a partially-written pkl never gets a
             # SHA stamp; readers that miss the tag treat it as no-cache.
             if source_sha is not None:
@@ -276,8 +345,28 @@ class StaticAnalysisCache:
                     pass
 
 
+def file_content_hash(path: Path) -> str | None:
+    """SHA-256 of the file's bytes, or None if it can't be read."""
+    try:
+        return hashlib.sha256(path.read_bytes()).hexdigest()
+    except OSError:
+        return None
+
+
+def changed_by_content_hash(file_hashes: dict[str, str], current_files: Iterable[Path]) -> set[Path]:
+    """Files of *current_files* that are new or whose content differs from *file_hashes*.
+
+    Also returns the files of *file_hashes* that are no longer among
+    *current_files* (deleted), so ``invalidate_files`` drops them.
+    """
+    current = {str(path): path for path in current_files}
+    changed = {path for key, path in current.items() if file_hashes.get(key) != file_content_hash(path)}
+    changed.update(Path(key) for key in file_hashes if key not in current)
+    return changed
+
+
 def copy_cache_files(src_dir: Path, dest_dir: Path) -> bool:
-    """Copy the static-analysis pkl + sha pair from *src_dir* to *dest_dir*.
+    """Copy the static-analysis pkl + sha pair (and hash manifest) from *src_dir* to *dest_dir*.
 
     Treats the cache as an opaque file pair (no unpickle, no relativization).
     Both files must exist in *src_dir*; a partial source is a no-op. Source
@@ -315,6 +404,16 @@ def copy_cache_files(src_dir: Path, dest_dir: Path) -> bool:
                 dest_pkl.unlink(missing_ok=True)
                 dest_sha.unlink(missing_ok=True)
                 return False
+            # The hash manifest is optional, but a stale one must not describe the new pkl.
+            src_hashes, dest_hashes = src_dir / STATIC_ANALYSIS_HASHES, dest_dir / STATIC_ANALYSIS_HASHES
+            try:
+                if src_hashes.exists():
+                    _atomic_copy(src_hashes, dest_hashes)
+                else:
+                    dest_hashes.unlink(missing_ok=True)
+            except OSError as e:
+                logger.warning("Failed to copy %s into %s: %s", STATIC_ANALYSIS_HASHES, dest_dir, e)
+                dest_hashes.unlink(missing_ok=True)
             return True
 
 
@@ -419,6 +518,7 @@ def merge_results(
     # an unchanged file emits no INHERITS edge during conversion (the superclass wasn't in that
     # partial graph). Re-derive INHERITS from the merged hierarchy against the merged node set so
     # the cross-file link is restored — otherwise incremental clustering loses class cohesion.
+    # IMPLEMENTS edges come from the same hierarchy and are re-derived alongside.
     _rederive_inherits_edges(merged_call_graph, merged_class_hierarchies)
 
     merged = AnalysisData(
@@ -434,17 +534,20 @@ def merge_results(
 
 
 def _rederive_inherits_edges(call_graph: CallGraph, class_hierarchies: dict[str, Any]) -> None:
-    """Add any missing INHERITS edge from the merged hierarchy, endpoints permitting.
+    """Add any missing INHERITS (and IMPLEMENTS) edge from the merged hierarchy, endpoints permitting.
 
     ``add_reference_edge`` only records an edge when both endpoints are live nodes, so a
     cross-file superclass now present in the merged graph gets its link; existing edges are
     skipped to avoid duplicates.
     """
-    existing = {(s, d) for s, d, k in call_graph.reference_edges if k == str(EdgeKind.INHERITS)}
+    existing = set(call_graph.reference_edges)
     for child, info in class_hierarchies.items():
         for superclass in info.get("superclasses", []):
-            if (child, superclass) not in existing:
+            if (child, superclass, str(EdgeKind.INHERITS)) not in existing:
                 call_graph.add_reference_edge(child, superclass, EdgeKind.INHERITS)
+        for interface in info.get("interfaces", []):
+            if (child, interface, str(EdgeKind.IMPLEMENTS)) not in existing:
+                call_graph.add_reference_edge(child, interface, EdgeKind.IMPLEMENTS)
 
 
 def _collect_invalidated_edge(
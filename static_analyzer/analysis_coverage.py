@@ -0,0 +1,180 @@
+"""How complete a static analysis is, as one number (``metadata.analysis_coverage``, ``--min-coverage``).
+
+Two ratios over data the analysis already holds:
+
+- call sites: the call sites recorded on call-graph edges (resolved to a known
+  symbol) against those plus the language servers' "undefined name" /
+  "unknown member" diagnostics (references nothing could be resolved to);
+- files: the source files that produced at least one symbol and no syntax
+  error, against every source file handed to a language server. Empty files
+  count as parsed.
+
+The coverage percentage is their product, the share of the code that was both read and
+linked up, so a run that resolves every call in half the files scores 50%.
+
+Files whose LSP requests timed out (``--lsp-timeout``), and Go files parsed
+without the language server because the project did not build, are listed
+alongside, so gaps in the diagram are explained.
+"""
+
+import re
+from dataclasses import dataclass
+from pathlib import Path
+
+from static_analyzer.analysis_result import StaticAnalysisResults
+
+EXIT_COVERAGE_BELOW_MINIMUM = 5
+
+# LSP DiagnosticSeverity: 1 = Error, 2 = Warning.
+_REPORTED_SEVERITIES = (1, 2)
+
+# Diagnostic codes meaning "this name/member refers to nothing known", per language server.
+_UNRESOLVED_CODES = frozenset(
+    {
+        # pyright
+        "reportUndefinedVariable",
+        "reportAttributeAccessIssue",
+        # TypeScript: cannot find name / property does not exist (with and without a suggestion)
+        "2304",
+        "2339",
+        "2551",
+        "2552",
+        # gopls
+        "UndeclaredName",
+        "MissingFieldOrMethod",
+        # C#: name does not exist / no such member
+        "CS0103",
+        "CS1061",
+        # rustc / rust-analyzer
+        "E0425",
+        "E0599",
+        "unresolved-method",
+        # intelephense: undefined function / method
+        "P1010",
+        "P1013",
+    }
+)
+_UNRESOLVED_MESSAGE_RE = re.compile(
+    r"\b(?:undefined|is not defined|cannot find name|unresolved|cannot be resolved to a|"
+    r"does not exist in the current context|has no attribute|is not a known attribute)\b",
+    re.IGNORECASE,
+)
+_SYNTAX_MESSAGE_RE = re.compile(r"\b(?:syntax error|invalid syntax|unexpected token|parse error|expected )", re.I)
+
+
+@dataclass(frozen=True)
+class AnalysisCoverage:
+    resolved_call_sites: int = 0
+    unresolved_call_sites: int = 0
+    parsed_files: int = 0
+    source_files: int = 0
+    # Files left partially analyzed by timed-out LSP requests.
+    timed_out_files: tuple[str, ...] = ()
+    # Files the language server returned nothing for, parsed for declarations alone.
+    degraded_files: tuple[str, ...] = ()
+
+    @property
+    def call_site_ratio(self) -> float:
+        total = self.resolved_call_sites + self.unresolved_call_sites
+        return self.resolved_call_sites / total if total else 1.0
+
+    @property
+    def file_ratio(self) -> float:
+        return self.parsed_files / self.source_files if self.source_files else 1.0
+
+    @property
+    def percent(self) -> float:
+        """The coverage number, 0-100."""
+        return round(100 * self.call_site_ratio * self.file_ratio, 1)
+
+    def summary_line(self) -> str:
+        total_sites = self.resolved_call_sites + self.unresolved_call_sites
+        return (
+            f"Analysis coverage: {self.percent:.1f}% "
+            f"(call sites resolved {self.resolved_call_sites}/{total_sites}, "
+            f"files parsed {self.parsed_files}/{self.source_files})"
+            + (f", {len(self.timed_out_files)} partially analyzed after LSP timeouts" if self.timed_out_files else "")
+            + (f", {len(self.degraded_files)} degraded (project did not build)" if self.degraded_files else "")
+        )
+
+
+def _field(diagnostic, name: str, default):
+    # Live diagnostics are LSPDiagnostic objects; ones read back from an older cache may be plain dicts.
+    if isinstance(diagnostic, dict):
+        return diagnostic.get(name, default)
+    return getattr(diagnostic, name, default)
+
+
+def is_unresolved_reference(diagnostic) -> bool:
+    if _field(diagnostic, "severity", 1) not in _REPORTED_SEVERITIES:
+        return False
+    return str(_field(diagnostic, "code", "")) in _UNRESOLVED_CODES or bool(
+        _UNRESOLVED_MESSAGE_RE.search(str(_field(diagnostic, "message", "")))
+    )
+
+
+def is_syntax_error(diagnostic) -> bool:
+    return _field(diagnostic, "severity", 1) == 1 and bool(
+        _SYNTAX_MESSAGE_RE.search(str(_field(diagnostic, "message", "")))
+    )
+
+
+def _is_empty(file_path: str) -> bool:
+    try:
+        return not Path(file_path).read_text(encoding="utf-8", errors="replace").strip()
+    except OSError:
+        return False
+
+
+def compute_analysis_coverage(static_analysis: StaticAnalysisResults) -> AnalysisCoverage:
+    resolved: set[tuple] = set()
+    symbol_files: set[str] = set()
+    source_files: set[str] = set()
+    unresolved = 0
+    syntax_error_files: set[str] = set()
+    # Why: getattr — results pickled before timeouts and degraded files were recorded lack the fields.
+    timed_out = {str(Path(f)) for files in getattr(static_analysis, "timed_out_files", {}).values() for f in files}
+    degraded = {str(Path(f)) for files in getattr(static_analysis, "degraded_files", {}).values() for f in files}
+
+    for language in static_analysis.get_languages():
+        language_files = {str(Path(f)) for f in static_analysis.get_source_files(language)}
+        source_files |= language_files
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            cfg = None
+        if cfg is not None:
+            symbol_files.update(str(Path(node.file_path)) for node in cfg.nodes.values())
+            for edge in cfg.edges:
+                if not edge.call_sites:
+                    # Adapters that don't record call sites still resolved at least one.
+                    resolved.add((edge.get_source(), edge.get_destination()))
+                for site in edge.call_sites:
+                    resolved.add((site.get("file") or edge.src_node.file_path, site.get("line"), site.get("column")))
+        symbol_files.update(str(Path(node.file_path)) for node in static_analysis.iter_reference_nodes(language))
+
+        for file_path, diagnostics in (static_analysis.diagnostics.get(language) or {}).items():
+            file_key = str(Path(file_path))
+            if file_key not in language_files:
+                continue
+            seen: set[tuple] = set()
+            for diagnostic in diagnostics:
+                if is_syntax_error(diagnostic):
+                    syntax_error_files.add(file_key)
+                if not is_unresolved_reference(diagnostic):
+                    continue
+                start = _field(_field(diagnostic, "range", {}), "start", {})
+                key = (str(_field(diagnostic, "message", "")), _field(start, "line", 0), _field(start, "character", 0))
+                if key not in seen:
+                    seen.add(key)
+                    unresolved += 1
+
+    parsed = {f for f in source_files if f not in syntax_error_files and (f in symbol_files or _is_empty(f))}
+    return AnalysisCoverage(
+        resolved_call_sites=len(resolved),
+        unresolved_call_sites=unresolved,
+        parsed_files=len(parsed),
+        source_files=len(source_files),
+        timed_out_files=tuple(sorted(timed_out)),
+        degraded_files=tuple(sorted(degraded)),
+    )
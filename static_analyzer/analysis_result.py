@@ -173,6 +173,10 @@ class StaticAnalysisResults:
 
     results: dict[Language, LanguageResults] = field(default_factory=dict)
     diagnostics: dict[Language, FileDiagnosticsMap] = field(default_factory=dict)
+    # Files whose LSP requests timed out (``--lsp-timeout``), so their symbols or edges are incomplete.
+    timed_out_files: dict[Language, list[str]] = field(default_factory=dict)
+    # Files the language server returned nothing for, parsed for declarations alone (the project did not build).
+    degraded_files: dict[Language, list[str]] = field(default_factory=dict)
     # Runtime-only warm-start base; never persisted into the static-analysis cache.
     incremental_base_results: "StaticAnalysisResults | None" = None
 
@@ -222,7 +226,8 @@ class StaticAnalysisResults:
         """Return the class hierarchy dict for ``language`` or raise ``ValueError``.
 
         Hierarchy values have shape ``{"superclasses": [...], "subclasses": [...],
-        "file_path": str, "line_start": int, "line_end": int}``.
+        "file_path": str, "line_start": int, "line_end": int}``. Go entries also
+        carry ``interfaces``/``implementations`` (implicit interface satisfaction).
         """
         bucket = self._get_bucket(language)
         if bucket is not None and bucket.hierarchy.entries is not None:
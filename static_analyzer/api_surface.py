@@ -0,0 +1,104 @@
+"""Exported-only view of a static analysis (``--api-only``).
+
+Public API docs are about what a package exports, not about ``entityCount``,
+``taskHandlers`` or the closures inside them. This view keeps the exported
+symbols only (see :func:`static_analyzer.node.visibility`), in the call graphs
+and in the references, so the components are generated from the public
+surface and the docs never name an unexported symbol.
+
+A call into an unexported helper is collapsed rather than lost: the helpers
+are followed, through any number of unexported calls, to the exported symbols
+they reach, and the caller gets a direct edge to each, its call sites marked
+``via: internal``. ``Handle -> validate -> Store.Save`` becomes
+``Handle -> Store.Save (via internal)``; a helper that reaches nothing exported
+leaves no edge.
+"""
+
+import logging
+from collections import defaultdict
+
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Visibility
+from static_analyzer.graph import CallGraph, Edge
+from static_analyzer.language_results import ControlFlowGraph, LanguageResults, References
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+INTERNAL_VIA = "internal"
+
+
+def _exported(node: Node) -> bool:
+    return node.visibility == Visibility.EXPORTED
+
+
+def _reached_through_internal(graph: CallGraph, callees: dict[str, list[str]], start: str) -> list[str]:
+    """The exported symbols *start*, an unexported one, reaches through unexported calls only, in name order."""
+    reached: set[str] = set()
+    seen = {start}
+    pending = [start]
+    while pending:
+        for callee in callees[pending.pop()]:
+            if callee in seen:
+                continue
+            seen.add(callee)
+            if _exported(graph.nodes[callee]):
+                reached.add(callee)
+            else:
+                pending.append(callee)
+    return sorted(reached)
+
+
+def exported_call_graph(graph: CallGraph) -> CallGraph:
+    """*graph* without its unexported symbols, calls through them collapsed into ``via: internal`` edges."""
+    into_internal: list[Edge] = []
+    exported = graph.filter(
+        _exported,
+        lambda edge: into_internal.append(edge) if _exported(edge.src_node) else None,
+    )
+    callees: dict[str, list[str]] = defaultdict(list)
+    for edge in graph.edges:
+        callees[edge.get_source()].append(edge.get_destination())
+    reached: dict[str, list[str]] = {}
+    for edge in into_internal:
+        helper = edge.get_destination()
+        if helper not in reached:
+            reached[helper] = _reached_through_internal(graph, callees, helper)
+        sites = [{**site, "via": INTERNAL_VIA} for site in edge.call_sites] or [{"via": INTERNAL_VIA}]
+        for target in reached[helper]:
+            if target != edge.get_source():
+                exported.add_edge(edge.get_source(), target, call_sites=sites)
+    return exported
+
+
+def apply_api_only(static_analysis: StaticAnalysisResults) -> StaticAnalysisResults:
+    """A view of *static_analysis* with the exported symbols only.
+
+    Like ``--select``, the input is left untouched so the static-analysis cache
+    still holds the whole repository.
+    """
+    narrowed = StaticAnalysisResults(
+        diagnostics=static_analysis.diagnostics,
+        incremental_base_results=static_analysis.incremental_base_results,
+    )
+    for language, bucket in static_analysis.results.items():
+        cfg = ControlFlowGraph()
+        if bucket.cfg.graph is not None:
+            graph = bucket.cfg.graph
+            cfg.graph = exported_call_graph(graph)
+            logger.info(f"--api-only: kept {len(cfg.graph.nodes)} of {len(graph.nodes)} {language} symbols")
+        references = References()
+        if bucket.references.by_qualified_name is not None:
+            references.by_qualified_name = {
+                qname: node
+                for qname, node in bucket.references.by_qualified_name.items()
+                if not isinstance(node, Node) or _exported(node)
+            }
+        narrowed.results[language] = LanguageResults(
+            cfg=cfg,
+            hierarchy=bucket.hierarchy,
+            references=references,
+            dependencies=bucket.dependencies,
+            source_files=bucket.source_files,
+        )
+    return narrowed
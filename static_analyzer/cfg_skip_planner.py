@@ -15,7 +15,7 @@ selects peel-safe nodes by measured character savings until the render fits.
 from __future__ import annotations
 
 import logging
-from typing import Callable
+from typing import Callable, Collection
 
 import networkx as nx
 
@@ -142,6 +142,7 @@ def plan_skip_set(
     char_budget: int,
     max_peel_frac: float = 0.5,
     min_keep_per_cluster: int = 5,
+    edge_kinds: Collection[str] | None = None,
 ) -> set[str]:
     """Decide which nodes ``cfg.to_cluster_string`` should omit to fit ``char_budget``.
 
@@ -155,7 +156,7 @@ def plan_skip_set(
             intentional: providers reject oversize input, so silently
             returning an over-budget render just defers the failure.
     """
-    full_str = cfg.to_cluster_string(cluster_result=cluster_result)
+    full_str = cfg.to_cluster_string(cluster_result=cluster_result, edge_kinds=edge_kinds)
     if len(full_str) <= char_budget:
         return set()
 
@@ -186,7 +187,7 @@ def plan_skip_set(
     allowed = _build_allowed_skip_list(peel_order, node_to_cluster, max_skip_per_cluster)
 
     def render(skip: set[str]) -> int:
-        return len(cfg.to_cluster_string(cluster_result=cluster_result, skip_nodes=skip))
+        return len(cfg.to_cluster_string(cluster_result=cluster_result, skip_nodes=skip, edge_kinds=edge_kinds))
 
     skip = _select_high_savings_fit(
         allowed,
@@ -10,10 +10,10 @@ from collections import defaultdict
 from collections.abc import Iterator
 from dataclasses import dataclass, field
 
-from constants import DEFAULT_STATIC_RELATION_LABEL
+from constants import DEFAULT_STATIC_RELATION_LABEL, IMPLEMENTS_RELATION_LABEL
 from agents.agent_responses import AnalysisInsights, Relation, RelationEdge
 from agents.relation_edges import append_or_merge_relation
-from static_analyzer.graph import CallGraph, Edge
+from static_analyzer.graph import CallGraph, Edge, EdgeKind
 
 logger = logging.getLogger(__name__)
 
@@ -95,6 +95,34 @@ def build_component_relations(
     return relations
 
 
+def build_implements_relations(
+    node_to_component: dict[str, str],
+    cfg_graphs: dict[str, CallGraph],
+) -> list[ClusterRelation]:
+    """Build inter-component relations from IMPLEMENTS reference edges.
+
+    A component whose types satisfy an interface owned by another component
+    relates to it even when no call crosses the boundary (Go's implicit
+    interfaces). Kept apart from call relations so diagrams can draw them
+    differently.
+    """
+    edge_pairs: dict[tuple[str, str], list[RelationEdge]] = defaultdict(list)
+    for cfg in cfg_graphs.values():
+        for src_name, dst_name, kind in cfg.reference_edges:
+            if kind != str(EdgeKind.IMPLEMENTS):
+                continue
+            src_comp = node_to_component.get(src_name)
+            dst_comp = node_to_component.get(dst_name)
+            if src_comp and dst_comp and src_comp != dst_comp:
+                edge = Edge(cfg.nodes[src_name], cfg.nodes[dst_name])
+                edge_pairs[(src_comp, dst_comp)].append(RelationEdge.from_edge(edge))
+
+    return [
+        ClusterRelation(src_cluster_id=src_c, dst_cluster_id=dst_c, all_edges=edges)
+        for (src_c, dst_c), edges in sorted(edge_pairs.items())
+    ]
+
+
 def iter_ancestor_ids(component_id: str) -> Iterator[str]:
     """Yield component_id then each shorter dotted-prefix ancestor."""
     parts = component_id.split(".")
@@ -127,7 +155,8 @@ def _collect_authoritative_relations(
     ]
     for analysis in analyses:
         for relation in analysis.components_relations:
-            if relation.src_id and relation.dst_id:
+            # Interface links from a previous save are re-derived, never treated as LLM labels.
+            if relation.src_id and relation.dst_id and not relation.implements:
                 relations_by_pair[(relation.src_id, relation.dst_id)] = relation
     return list(relations_by_pair.values())
 
@@ -225,7 +254,22 @@ def build_global_relations(
             continue
         global_relations[pair] = llm_rel.with_merged_edges()
 
-    return sorted(global_relations.values(), key=lambda rel: (rel.src_id, rel.dst_id))
+    implements_relations = [
+        Relation.from_edges(
+            IMPLEMENTS_RELATION_LABEL,
+            id_to_name.get(rel.src_cluster_id, rel.src_cluster_id),
+            id_to_name.get(rel.dst_cluster_id, rel.dst_cluster_id),
+            rel.src_cluster_id,
+            rel.dst_cluster_id,
+            rel.all_edges,
+            True,
+            implements=True,
+        )
+        for rel in build_implements_relations(node_to_component, cfg_graphs)
+    ]
+    return sorted(
+        [*global_relations.values(), *implements_relations], key=lambda rel: (rel.src_id, rel.dst_id, rel.implements)
+    )
 
 
 def merge_relations(
@@ -0,0 +1,188 @@
+"""Per-function cyclomatic complexity, approximated from source text.
+
+A function's complexity is 1 plus the decision points (branches, loops,
+handlers, short-circuit operators) in its line range, matched with the
+adapter's ``decision_point_pattern`` after comments and string literals are
+blanked out. It is a deterministic proxy rather than a parser-exact McCabe
+number, but it ranks functions the same way, which is all the metrics report
+and the component prompts need. Nested functions also count towards their
+enclosing function's range.
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language
+from static_analyzer.engine.adapters import get_all_adapters
+from static_analyzer.engine.language_adapter import LanguageAdapter
+
+logger = logging.getLogger(__name__)
+
+METRICS_FILENAME = "metrics.md"
+HOTSPOT_LIMIT = 3
+_TOP_FUNCTIONS = 20
+
+# Triple-quoted strings first so their quotes aren't taken as two empty literals.
+_STRING_RE = r'"""[\s\S]*?"""|\'\'\'[\s\S]*?\'\'\'|"(?:\\.|[^"\\\n])*"|\'(?:\\.|[^\'\\\n])*\'|`[^`]*`'
+_BLOCK_COMMENT_RE = r"/\*[\s\S]*?\*/"
+
+
+@dataclass(frozen=True)
+class FunctionComplexity:
+    qualified_name: str
+    file_path: str
+    line_start: int
+    line_end: int
+    complexity: int
+
+
+def _noise_pattern(adapter: LanguageAdapter) -> re.Pattern[str]:
+    line_comment = re.escape(adapter.line_comment_prefix) + r"[^\n]*"
+    parts = [_STRING_RE, line_comment]
+    if adapter.line_comment_prefix == "//":
+        parts.insert(0, _BLOCK_COMMENT_RE)
+    return re.compile("|".join(parts))
+
+
+def strip_comments_and_strings(source: str, adapter: LanguageAdapter) -> str:
+    """Blank out comments and string literals, keeping newlines so line numbers still line up."""
+    return _noise_pattern(adapter).sub(lambda m: "\n" * m.group(0).count("\n"), source)
+
+
+def count_complexity(lines: list[str], adapter: LanguageAdapter) -> int:
+    """1 + decision points in *lines* (already stripped of comments and strings)."""
+    return 1 + sum(len(adapter.decision_point_pattern.findall(line)) for line in lines)
+
+
+def _relative_path(file_path: str, repo_dir: Path) -> str:
+    try:
+        return Path(file_path).resolve().relative_to(repo_dir.resolve()).as_posix()
+    except ValueError:
+        return Path(file_path).as_posix()
+
+
+def compute_function_complexity(
+    static_analysis: StaticAnalysisResults, repo_dir: Path
+) -> dict[str, FunctionComplexity]:
+    """Complexity of every callable in the call graphs, keyed by qualified name."""
+    adapters = {adapter.language_enum: adapter for adapter in get_all_adapters().values()}
+    result: dict[str, FunctionComplexity] = {}
+    for language in static_analysis.get_languages():
+        adapter = adapters.get(Language(language))
+        try:
+            cfg = static_analysis.get_cfg(language)
+        except ValueError:
+            continue
+        if adapter is None:
+            continue
+        stripped_files: dict[str, list[str] | None] = {}
+        for fqn, node in cfg.nodes.items():
+            if node.is_class() or node.is_data() or node.line_end < node.line_start:
+                continue
+            if node.file_path not in stripped_files:
+                try:
+                    source = Path(node.file_path).read_text(encoding="utf-8", errors="replace")
+                    stripped_files[node.file_path] = strip_comments_and_strings(source, adapter).splitlines()
+                except OSError as e:
+                    logger.warning(f"Skipping complexity for {node.file_path}: {e}")
+                    stripped_files[node.file_path] = None
+            lines = stripped_files[node.file_path]
+            if lines is None:
+                continue
+            result[fqn] = FunctionComplexity(
+                qualified_name=fqn,
+                file_path=_relative_path(node.file_path, repo_dir),
+                line_start=node.line_start,
+                line_end=node.line_end,
+                complexity=count_complexity(lines[node.line_start - 1 : node.line_end], adapter),
+            )
+    return result
+
+
+def component_functions(
+    component: Component, complexities: dict[str, FunctionComplexity]
+) -> list[FunctionComplexity]:
+    """The component's functions, most complex first (ties broken by name for stable output)."""
+    functions = {
+        method.qualified_name: complexities[method.qualified_name]
+        for group in component.file_methods
+        for method in group.methods
+        if method.qualified_name in complexities
+    }
+    return sorted(functions.values(), key=lambda f: (-f.complexity, f.qualified_name))
+
+
+def hotspots_llm_str(hotspots: list[FunctionComplexity]) -> str:
+    return "\n".join(
+        f"- `{f.qualified_name}` ({f.file_path}:{f.line_start}-{f.line_end}): complexity {f.complexity}"
+        for f in hotspots
+    )
+
+
+def _component_sort_key(component_id: str) -> tuple:
+    return tuple(int(part) if part.isdigit() else 0 for part in component_id.split("."))
+
+
+def build_metrics_markdown(
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    complexities: dict[str, FunctionComplexity],
+) -> str:
+    components = sorted(
+        (c for analysis in (root_analysis, *sub_analyses.values()) for c in analysis.components),
+        key=lambda c: _component_sort_key(c.component_id),
+    )
+    owner: dict[str, str] = {}
+    lines = [
+        "# Complexity Metrics",
+        "",
+        "Cyclomatic complexity is approximated per function as 1 + its decision points "
+        "(branches, loops, handlers, short-circuit operators).",
+        "",
+        "## Components",
+        "",
+        "| Component | Functions | Total | Mean | Max | Hotspot |",
+        "|---|---|---|---|---|---|",
+    ]
+    for component in components:
+        functions = component_functions(component, complexities)
+        if not functions:
+            continue
+        for f in functions:
+            # Deepest component wins: sub-components sort after their parent.
+            owner[f.qualified_name] = component.name
+        total = sum(f.complexity for f in functions)
+        lines.append(
+            f"| {component.component_id} {component.name} | {len(functions)} | {total} | "
+            f"{total / len(functions):.1f} | {functions[0].complexity} | `{functions[0].qualified_name}` |"
+        )
+
+    top = sorted(complexities.values(), key=lambda f: (-f.complexity, f.qualified_name))[:_TOP_FUNCTIONS]
+    lines += [
+        "",
+        "## Most Complex Functions",
+        "",
+        "| Function | Location | Complexity | Component |",
+        "|---|---|---|---|",
+    ]
+    lines += [
+        f"| `{f.qualified_name}` | {f.file_path}:{f.line_start}-{f.line_end} | {f.complexity} | "
+        f"{owner.get(f.qualified_name, '-')} |"
+        for f in top
+    ]
+    return "\n".join(lines) + "\n"
+
+
+def write_metrics_markdown(
+    output_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    complexities: dict[str, FunctionComplexity],
+) -> Path:
+    path = output_dir / METRICS_FILENAME
+    path.write_text(build_metrics_markdown(root_analysis, sub_analyses, complexities), encoding="utf-8")
+    return path
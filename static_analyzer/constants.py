@@ -23,7 +23,13 @@ class Language(StrEnum):
     PHP = "php"
     RUST = "rust"
     CSHARP = "csharp"
+    ELIXIR = "elixir"
+    SWIFT = "swift"
+    SCALA = "scala"
     CPP = "cpp"
+    # Schema languages: parsed directly by ``schema_parser`` rather than through an LSP.
+    PROTOBUF = "protobuf"
+    GRAPHQL = "graphql"
 
 
 # File extensions per language. Every ``Language`` member appears here — keep
@@ -38,7 +44,12 @@ LANGUAGE_EXTENSIONS: dict[Language, tuple[str, ...]] = {
     Language.PHP: (".php",),
     Language.RUST: (".rs",),
     Language.CSHARP: (".cs",),
+    Language.ELIXIR: (".ex", ".exs"),
+    Language.SWIFT: (".swift",),
+    Language.SCALA: (".scala", ".sc"),
     Language.CPP: (".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx", ".h"),
+    Language.PROTOBUF: (".proto",),
+    Language.GRAPHQL: (".graphql", ".graphqls", ".gql"),
 }
 
 # Import-time invariant: every language has an extension list. Cheap check that
@@ -88,7 +99,16 @@ class ClusteringConfig:
     # relationships avoids grab-bag components. Values are ``graph.EdgeKind`` string
     # values. IMPORT is emitted but excluded by default — it over-merges (coarse,
     # dense, file-level). Change this tuple to analyze a different subset.
-    CLUSTERING_EDGE_KINDS = ("contains", "inherits", "typeref")
+    CLUSTERING_EDGE_KINDS = ("contains", "inherits", "implements", "typeref")
+
+    # Which edge kinds are listed as connections in the cluster strings the LLM
+    # reads. Call edges are the reliable ones; Go goroutine spawns and channel links
+    # (the latter with their confidence) are shown since no call edge covers that
+    # data flow, Go functions returning a function type since that is how
+    # higher-order code is wired, and the package variables Go functions write
+    # since shared mutable state shows in no call. ``--llm-edge-kinds`` overrides this without
+    # changing clustering or the relations drawn in the diagram.
+    LLM_CONTEXT_EDGE_KINDS = ("call", "spawns", "returns", "mutates", "sends-to", "receives-from")
 
 
 class NodeType(IntEnum):
@@ -197,3 +217,24 @@ ENTITY_LABELS: dict[NodeType, str] = {
     NodeType.OPERATOR: "Operator",
     NodeType.TYPE_PARAMETER: "TypeParameter",
 }
+
+
+class ReceiverKind(StrEnum):
+    """How a method binds its receiver. Only Go distinguishes the two: a pointer
+    receiver (``func (e *Entity) Dispose()``) can mutate the value, a value
+    receiver works on a copy. Everything that is not a Go method is ``NONE``.
+    """
+
+    VALUE = "value"
+    POINTER = "pointer"
+    NONE = "none"
+
+
+class Visibility(StrEnum):
+    """Whether a symbol is part of its package's public surface. Derived from the
+    name: a leading capital in Go, no leading underscore in Python. Languages
+    whose names do not say are taken as ``EXPORTED``.
+    """
+
+    EXPORTED = "exported"
+    UNEXPORTED = "unexported"
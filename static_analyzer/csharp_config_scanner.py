@@ -5,6 +5,8 @@ standalone C# source trees to support mono-repo analysis with csharp-ls.
 """
 
 import logging
+import os
+import re
 from pathlib import Path
 
 from repo_utils.ignore import RepoIgnoreManager
@@ -12,6 +14,7 @@ from repo_utils.ignore import RepoIgnoreManager
 logger = logging.getLogger(__name__)
 
 SOLUTION_GLOBS: tuple[str, ...] = ("*.sln", "*.slnx")
+_PROJECT_REFERENCE_RE = re.compile(r"<ProjectReference\s+Include\s*=\s*\"([^\"]+)\"")
 
 
 class CSharpProjectConfig:
@@ -38,6 +41,8 @@ class CSharpConfigScanner:
            ``.slnx`` is the XML-based replacement Visual Studio adopted in
            2024; modern .NET repos (e.g. Spectre.Console) ship only it.
         2. Standalone ``.csproj`` files not already covered by a solution.
+           Projects linked by ``<ProjectReference>`` share one root (their
+           common directory), so references across them resolve.
         3. Fallback to the repository root when ``.cs`` files exist but
            no solution or project files are found.
     """
@@ -82,8 +87,27 @@ class CSharpConfigScanner:
         return sorted(roots)
 
     def _find_project_roots(self) -> list[Path]:
-        """Find directories containing .csproj files."""
-        return sorted({p.parent for p in self.repo_path.rglob("*.csproj") if p.is_file()})
+        """Find directories containing .csproj files, one per group of projects referencing each other."""
+        projects = sorted(p.resolve() for p in self.repo_path.rglob("*.csproj") if p.is_file())
+        group = {project: project.parent for project in projects}
+        for project in projects:
+            for referenced in self._project_references(project):
+                if referenced in group and group[referenced] != group[project]:
+                    merged = Path(os.path.commonpath([group[project], group[referenced]]))
+                    old = {group[project], group[referenced]}
+                    group = {p: merged if root in old else root for p, root in group.items()}
+        repo = self.repo_path.resolve()
+        return sorted({self.repo_path / root.relative_to(repo) for root in group.values()})
+
+    @staticmethod
+    def _project_references(project: Path) -> list[Path]:
+        """The .csproj files *project* references, resolved."""
+        try:
+            content = project.read_text(encoding="utf-8", errors="replace")
+        except OSError:
+            return []
+        includes = _PROJECT_REFERENCE_RE.findall(content)
+        return [(project.parent / include.replace("\\", "/")).resolve() for include in includes]
 
     def _has_cs_files(self, directory: Path) -> bool:
         """Check if directory contains any .cs files."""
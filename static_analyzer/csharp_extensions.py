@@ -0,0 +1,82 @@
+"""C# extension methods, attributed to the type they extend.
+
+``static decimal Total(this Order order)`` is called as ``order.Total()`` but
+csharp-ls places it in its static class; a ``contains`` edge from ``Order``
+clusters it with the type whose calls it serves.
+"""
+
+import logging
+import re
+from collections.abc import Iterable
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+CSHARP_SUFFIX = ".cs"
+# The ``this`` modifier on the first parameter, with the extended type's name (generic arguments dropped).
+_THIS_PARAMETER_RE = re.compile(
+    r"\(\s*(?:\[[^\]]*\]\s*)*this\s+(?:(?:ref|in|scoped|readonly)\s+)*(?:global::)?(?P<type>@?\w+(?:\.\w+)*)"
+)
+# A declaration header ends where its body does not yet start.
+_BODY_START_RE = re.compile(r"\{|=>|;")
+_MAX_HEADER_LINES = 8
+
+
+def extended_type(lines: list[str], line_start: int) -> str | None:
+    """The simple name of the type the method declared at the 1-based *line_start* extends, if it is an extension."""
+    header: list[str] = []
+    for line in lines[line_start - 1 : line_start - 1 + _MAX_HEADER_LINES]:
+        end = _BODY_START_RE.search(line)
+        header.append(line[: end.start()] if end else line)
+        if end:
+            break
+    match = _THIS_PARAMETER_RE.search(" ".join(header))
+    return match.group("type").lstrip("@").rsplit(".", 1)[-1] if match else None
+
+
+def add_extension_edges(call_graph: CallGraph, nodes: Iterable[Node]) -> list[tuple[str, str, str]]:
+    """Link each project type to the extension methods declared for it; returns the ``contains`` edges added.
+
+    The extended type must be the only project type of that name; extensions of
+    framework types (``string``, ``IEnumerable<T>``) or type parameters are left alone.
+    """
+    types = {
+        node.fully_qualified_name: node
+        for node in [*call_graph.nodes.values(), *nodes]
+        if node.type in CLASS_TYPES and node.file_path.endswith(CSHARP_SUFFIX)
+    }
+    types_by_name: dict[str, list[Node]] = {}
+    for qname, node in types.items():
+        types_by_name.setdefault(qname.rsplit(".", 1)[-1], []).append(node)
+
+    sources: dict[str, list[str]] = {}
+    existing = set(call_graph.reference_edges)
+    added: list[tuple[str, str, str]] = []
+    for node in list(call_graph.nodes.values()):
+        if node.type not in CALLABLE_TYPES or not node.file_path.endswith(CSHARP_SUFFIX):
+            continue
+        if node.file_path not in sources:
+            try:
+                sources[node.file_path] = Path(node.file_path).read_text(encoding="utf-8", errors="replace").split("\n")
+            except OSError as e:
+                logger.debug(f"C# extensions: cannot read {node.file_path}: {e}")
+                sources[node.file_path] = []
+        name = extended_type(sources[node.file_path], node.line_start)
+        candidates = types_by_name.get(name, []) if name else []
+        if len(candidates) != 1:
+            continue
+        owner = candidates[0]
+        if owner.fully_qualified_name not in call_graph.nodes:
+            call_graph.add_node(owner)
+        edge = (owner.fully_qualified_name, node.fully_qualified_name, str(EdgeKind.CONTAINS))
+        if edge not in existing:
+            call_graph.add_reference_edge(edge[0], edge[1], EdgeKind.CONTAINS)
+            existing.add(edge)
+            added.append(edge)
+    if added:
+        logger.info(f"C# extensions: {len(added)} extension methods attributed to their types")
+    return added
@@ -0,0 +1,154 @@
+"""Elixir module directives: the names a module brings into scope and the behaviours it implements.
+
+A module refers to other modules through its directives, not through file paths:
+
+* ``alias MyApp.Accounts`` (or ``alias MyApp.{Accounts, Repo}``, or
+  ``alias MyApp.Accounts, as: Users``): ``Accounts.create_user/2`` is
+  ``MyApp.Accounts.create_user/2``;
+* ``import MyApp.Accounts``: plain ``create_user/2`` is
+  ``MyApp.Accounts.create_user/2``;
+* ``use GenServer``: runs the module's ``__using__`` macro, which for the OTP
+  behaviours declares ``@behaviour GenServer``;
+* ``@behaviour MyApp.Storage``: the module implements the callbacks
+  ``MyApp.Storage`` declares, Elixir's interface mechanism.
+
+:func:`parse_directives` reads them from a file with the line of each, and
+:func:`module_directives` hands each to the innermost module declaring it.
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from enum import StrEnum
+from pathlib import Path
+
+logger = logging.getLogger(__name__)
+
+# Comments and string literals, blanked before matching so ``# alias Foo`` is not a directive.
+_CLEAN_RE = re.compile(r'#[^\n]*|"""(?:.|\n)*?"""|"(?:\\.|[^"\\\n])*"')
+_MODULE_NAME = r":?[\w.]+"
+_MULTI_ALIAS_RE = re.compile(r"^[ \t]*alias[ \t]+([\w.]+)\.\{([^}]*)\}", re.MULTILINE)
+_ALIAS_RE = re.compile(rf"^[ \t]*alias[ \t]+({_MODULE_NAME})(?:[ \t]*,[ \t]*as:[ \t]*(\w+))?", re.MULTILINE)
+_SCOPE_RE = re.compile(rf"^[ \t]*(import|require|use)[ \t]+({_MODULE_NAME})", re.MULTILINE)
+_BEHAVIOUR_RE = re.compile(rf"^[ \t]*@behaviou?r[ \t]+({_MODULE_NAME})", re.MULTILINE)
+
+CURRENT_MODULE = "__MODULE__"
+
+# ``use X`` of these declares ``@behaviour X``: the OTP behaviours a module is built on.
+USE_DECLARES_BEHAVIOUR = frozenset({"GenServer", "Supervisor", "DynamicSupervisor", "Application"})
+
+
+class DirectiveKind(StrEnum):
+    ALIAS = "alias"
+    IMPORT = "import"
+    REQUIRE = "require"
+    USE = "use"
+    BEHAVIOUR = "behaviour"
+
+
+@dataclass(frozen=True)
+class ElixirDirective:
+    kind: DirectiveKind
+    # As written, ``__MODULE__.Cache`` included; see :meth:`expanded`.
+    module: str
+    # 0-based, as the language server counts.
+    line: int
+    # The name an ``alias`` binds: ``Users`` for ``as: Users``, else the last part of the module.
+    alias: str | None = None
+
+    def expanded(self, current_module: str) -> str:
+        """The module named, ``__MODULE__`` replaced by *current_module*."""
+        if self.module == CURRENT_MODULE or self.module.startswith(f"{CURRENT_MODULE}."):
+            return current_module + self.module[len(CURRENT_MODULE) :]
+        return self.module
+
+
+def _clean(source: str) -> str:
+    """Blank out comments and strings, keeping line breaks."""
+    return _CLEAN_RE.sub(lambda m: re.sub(r"[^\n]", " ", m.group(0)), source)
+
+
+def parse_directives(source: str) -> list[ElixirDirective]:
+    """The ``alias``/``import``/``require``/``use``/``@behaviour`` directives of an Elixir file, in line order."""
+    source = _clean(source)
+
+    def line_of(offset: int) -> int:
+        return source.count("\n", 0, offset)
+
+    found: list[ElixirDirective] = []
+    multi_lines: set[int] = set()
+    for match in _MULTI_ALIAS_RE.finditer(source):
+        line = line_of(match.start())
+        multi_lines.add(line)
+        for entry in match.group(2).split(","):
+            entry = entry.strip()
+            if entry:
+                found.append(
+                    ElixirDirective(DirectiveKind.ALIAS, f"{match.group(1)}.{entry}", line, entry.rsplit(".", 1)[-1])
+                )
+    for match in _ALIAS_RE.finditer(source):
+        line = line_of(match.start())
+        if line not in multi_lines:
+            module, alias = match.group(1), match.group(2) or match.group(1).rsplit(".", 1)[-1]
+            found.append(ElixirDirective(DirectiveKind.ALIAS, module, line, alias))
+    for match in _SCOPE_RE.finditer(source):
+        found.append(ElixirDirective(DirectiveKind(match.group(1)), match.group(2), line_of(match.start())))
+    for match in _BEHAVIOUR_RE.finditer(source):
+        found.append(ElixirDirective(DirectiveKind.BEHAVIOUR, match.group(1), line_of(match.start())))
+    return sorted(found, key=lambda directive: directive.line)
+
+
+def module_directives(
+    directives: list[ElixirDirective], modules: list[tuple[str, int, int]]
+) -> dict[str, list[ElixirDirective]]:
+    """The directives of each module, given as ``(name, start_line, end_line)``: the innermost one enclosing each."""
+    by_module: dict[str, list[ElixirDirective]] = {name: [] for name, _, _ in modules}
+    for directive in directives:
+        enclosing = [module for module in modules if module[1] <= directive.line <= module[2]]
+        if enclosing:
+            name = min(enclosing, key=lambda module: module[2] - module[1])[0]
+            by_module[name].append(directive)
+    return by_module
+
+
+def behaviours(declared: list[ElixirDirective], current_module: str, aliases: list[ElixirDirective]) -> list[str]:
+    """The behaviours a module declares, directly or through ``use`` of an OTP behaviour, by name.
+
+    *aliases* are the directives of the whole file: a nested module sees the aliases of the modules enclosing it.
+    """
+    found: list[str] = []
+    for directive in declared:
+        if directive.kind == DirectiveKind.BEHAVIOUR or (
+            directive.kind == DirectiveKind.USE and directive.module in USE_DECLARES_BEHAVIOUR
+        ):
+            name = expand_alias(directive.expanded(current_module), aliases, current_module)
+            if name not in found:
+                found.append(name)
+    return found
+
+
+def expand_alias(name: str, directives: list[ElixirDirective], current_module: str = "") -> str:
+    """*name* with a leading alias replaced by the module it stands for: ``Accounts.get`` to ``MyApp.Accounts.get``."""
+    head, dot, rest = name.partition(".")
+    for directive in directives:
+        if directive.kind == DirectiveKind.ALIAS and directive.alias == head:
+            return directive.expanded(current_module) + dot + rest
+    return name
+
+
+class ElixirDirectiveIndex:
+    """Directives of Elixir files, read lazily and cached by path."""
+
+    def __init__(self) -> None:
+        self._directives: dict[Path, list[ElixirDirective]] = {}
+
+    def directives(self, file_path: str | Path) -> list[ElixirDirective]:
+        path = Path(file_path)
+        if path not in self._directives:
+            try:
+                source = path.read_text(encoding="utf-8", errors="replace")
+            except OSError as e:
+                logger.debug(f"Elixir directives: cannot read {path}: {e}")
+                source = ""
+            self._directives[path] = parse_directives(source)
+        return self._directives[path]
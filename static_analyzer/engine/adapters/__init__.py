@@ -4,11 +4,14 @@ from __future__ import annotations
 
 from static_analyzer.engine.language_adapter import LanguageAdapter
 from static_analyzer.engine.adapters.csharp_adapter import CSharpAdapter
+from static_analyzer.engine.adapters.elixir_adapter import ElixirAdapter
 from static_analyzer.engine.adapters.go_adapter import GoAdapter
 from static_analyzer.engine.adapters.java_adapter import JavaAdapter
 from static_analyzer.engine.adapters.php_adapter import PHPAdapter
 from static_analyzer.engine.adapters.python_adapter import PythonAdapter
 from static_analyzer.engine.adapters.rust_adapter import RustAdapter
+from static_analyzer.engine.adapters.scala_adapter import ScalaAdapter
+from static_analyzer.engine.adapters.swift_adapter import SwiftAdapter
 from static_analyzer.engine.adapters.typescript_adapter import JavaScriptAdapter, TypeScriptAdapter
 
 ADAPTER_REGISTRY: dict[str, type[LanguageAdapter]] = {
@@ -20,6 +23,9 @@ ADAPTER_REGISTRY: dict[str, type[LanguageAdapter]] = {
     "Java": JavaAdapter,
     "PHP": PHPAdapter,
     "Rust": RustAdapter,
+    "Elixir": ElixirAdapter,
+    "Swift": SwiftAdapter,
+    "Scala": ScalaAdapter,
 }
 
 
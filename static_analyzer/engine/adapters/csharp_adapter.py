@@ -110,20 +110,27 @@ class CSharpAdapter(LanguageAdapter):
         Strategy: use namespace detail when available (for namespace
         symbols themselves), otherwise reconstruct from file path,
         skipping ``src/`` prefix and deduplicating filename/class.
+
+        Partial class parts split across ``User.cs``, ``User.Validation.cs``
+        and ``User.g.cs`` all qualify under ``User`` so their members merge
+        into one type instead of three.
         """
         # Namespace symbol itself — detail has the full namespace
         if detail and symbol_kind == NodeType.NAMESPACE:
             return detail
 
+        # Filter parents: skip File (kind=1) and Namespace (kind=3) —
+        # the namespace is already encoded in the file path for C#
+        code_parents = [name for name, kind in parent_chain if kind not in (NodeType.FILE, NodeType.NAMESPACE)]
+
         # Build from file path, stripping 'src' prefix
         rel = file_path.relative_to(project_root)
         parts = [p for p in rel.with_suffix("").parts if p != "src"]
+        outer_type = code_parents[0] if code_parents else symbol_name
+        if parts and "." in parts[-1] and parts[-1].split(".", 1)[0] == outer_type:
+            parts[-1] = outer_type
         module = ".".join(parts)
 
-        # Filter parents: skip File (kind=1) and Namespace (kind=3) —
-        # the namespace is already encoded in the file path for C#
-        code_parents = [name for name, kind in parent_chain if kind not in (NodeType.FILE, NodeType.NAMESPACE)]
-
         if code_parents:
             # Deduplicate first parent if it matches filename
             module_last = module.rsplit(".", 1)[-1] if "." in module else module
@@ -207,7 +214,9 @@ class CSharpAdapter(LanguageAdapter):
             target = next(iter(project_root.glob("*.csproj")), None)
         if target is None:
             target = next(iter(project_root.glob("*.fsproj")), None)
-        if target is None:
+        # A root shared by projects that reference each other has them below it
+        targets = [target] if target is not None else sorted(project_root.rglob("*.csproj"))
+        if not targets:
             logger.debug("No solution/project file found at %s; skipping restore", project_root)
             return
 
@@ -218,10 +227,15 @@ class CSharpAdapter(LanguageAdapter):
 
         env = os.environ.copy()
         env.update(resolution.env)
+        for target in targets:
+            self._restore(resolution.dotnet_path, target, env)
+
+    @staticmethod
+    def _restore(dotnet_path: str, target: Path, env: dict[str, str]) -> None:
         try:
             result = subprocess.run(
-                [resolution.dotnet_path, "restore", str(target.name), "--nologo", "--verbosity", "minimal"],
-                cwd=str(project_root),
+                [dotnet_path, "restore", str(target.name), "--nologo", "--verbosity", "minimal"],
+                cwd=str(target.parent),
                 env=env,
                 capture_output=True,
                 text=True,
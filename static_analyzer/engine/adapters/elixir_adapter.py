@@ -0,0 +1,196 @@
+"""Elixir language adapter using ElixirLS."""
+
+from __future__ import annotations
+
+import logging
+import re
+import shutil
+from pathlib import Path
+
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.engine.language_adapter import LanguageAdapter
+from static_analyzer.engine.lsp_constants import CLASS_LIKE_KINDS
+
+logger = logging.getLogger(__name__)
+
+# ElixirLS labels functions with their definition head, e.g. ``defp parse(data, opts)``.
+_DEF_PREFIX = re.compile(r"^(?:def|defp|defmacro|defmacrop|defguard|defguardp|defdelegate)\s+")
+# Already in ``name/arity`` form (older ElixirLS releases).
+_NAME_ARITY = re.compile(r"^[\w?!]+/\d+$")
+# ``defimpl Proto, for: Type`` is reported as ``Proto, for: Type``; the compiled module is ``Proto.Type``.
+_IMPL_NAME = re.compile(r"^(?:defimpl\s+)?([\w.]+)\s*,\s*for:\s*([\w.]+)$")
+# Each clause arrow (``case``/``cond``/``fn``/``with ... else``) is a branch.
+_DECISION_POINTS = re.compile(r"\b(?:if|unless|rescue|catch|and|or)\b|->|&&|\|\|")
+_OPENERS = "([{"
+_CLOSERS = ")]}"
+
+
+def _count_args(args: str) -> int:
+    """Number of top-level, comma-separated arguments in a definition head."""
+    if not args.strip():
+        return 0
+    depth = 0
+    count = 1
+    for char in args:
+        if char in _OPENERS:
+            depth += 1
+        elif char in _CLOSERS:
+            depth -= 1
+        elif char == "," and depth == 0:
+            count += 1
+    return count
+
+
+def function_signature(name: str) -> str | None:
+    """``name/arity`` for an ElixirLS function label, or ``None`` if *name* is not a function.
+
+    ``def fetch(id, opts \\\\ []) when is_binary(id)`` becomes ``fetch/2``;
+    ``def now`` becomes ``now/0``. Default arguments count toward the arity
+    of the full head, which is the one callers with every argument resolve to.
+    """
+    match = _DEF_PREFIX.match(name)
+    if match is None:
+        return name if _NAME_ARITY.match(name) else None
+    head = name[match.end() :]
+    paren = head.find("(")
+    if paren == -1:
+        return f"{head.split(' when ', 1)[0].strip()}/0"
+    depth = 0
+    for end in range(paren, len(head)):
+        if head[end] in _OPENERS:
+            depth += 1
+        elif head[end] in _CLOSERS:
+            depth -= 1
+            if depth == 0:
+                break
+    return f"{head[:paren].strip()}/{_count_args(head[paren + 1 : end])}"
+
+
+def _range_of(symbol: dict) -> dict:
+    return symbol.get("range") or symbol.get("location", {}).get("range", {})
+
+
+def _position(position: dict) -> tuple[int, int]:
+    return position.get("line", 0), position.get("character", 0)
+
+
+def _extend_range(target: dict, clause: dict) -> None:
+    """Grow *target*'s range so it also covers *clause*."""
+    target_range, clause_range = _range_of(target), _range_of(clause)
+    if not target_range or not clause_range:
+        return
+    if _position(clause_range.get("start", {})) < _position(target_range.get("start", {})):
+        target_range["start"] = dict(clause_range["start"])
+    if _position(clause_range.get("end", {})) > _position(target_range.get("end", {})):
+        target_range["end"] = dict(clause_range["end"])
+
+
+class ElixirAdapter(LanguageAdapter):
+    """Static-analysis adapter for Elixir projects backed by ElixirLS.
+
+    Modules are the class-like containers, so each becomes a component
+    candidate, and qualified names follow Elixir's own module naming
+    (``MyApp.Accounts.fetch/2``) rather than the file path. Calls through
+    ``alias``/``import`` and the pipe operator are resolved by ElixirLS's
+    references, which account for the implicit first argument of ``|>``.
+    """
+
+    @property
+    def language(self) -> str:
+        return "Elixir"
+
+    @property
+    def language_enum(self) -> Language:
+        return Language.ELIXIR
+
+    @property
+    def lsp_command(self) -> list[str]:
+        return ["language_server.sh"]
+
+    @property
+    def language_id(self) -> str:
+        return "elixir"
+
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        return _DECISION_POINTS
+
+    @property
+    def line_comment_prefix(self) -> str:
+        return "#"
+
+    @property
+    def declares_behaviours(self) -> bool:
+        """``@behaviour MyApp.Storage`` (or ``use GenServer``) makes a module implement that behaviour."""
+        return True
+
+    def get_lsp_command(self, project_root: Path) -> list[str]:
+        """Fail fast if Elixir is missing.
+
+        ElixirLS is distributed as compiled BEAM files and runs on the
+        project's own Elixir/Erlang install, like JDTLS needs Java.
+        """
+        if shutil.which("elixir") is None:
+            raise RuntimeError(
+                "elixir not found on PATH. ElixirLS runs on the project's Elixir "
+                "toolchain (1.14+ with Erlang/OTP). Install it from "
+                "https://elixir-lang.org/install.html and re-run the analysis."
+            )
+        return super().get_lsp_command(project_root)
+
+    def is_class_like(self, symbol_kind: int) -> bool:
+        return symbol_kind in CLASS_LIKE_KINDS or symbol_kind == NodeType.MODULE
+
+    def normalize_document_symbols(self, symbols: list[dict]) -> list[dict]:
+        """Name functions ``name/arity`` and merge the clauses of each function into one symbol.
+
+        ElixirLS reports every ``def`` clause separately; merged, the symbol
+        spans from the first clause to the last so calls in any clause are
+        attributed to it. ``defimpl`` modules are renamed to the module the
+        compiler creates (``Proto.Type``).
+        """
+        normalized: list[dict] = []
+        functions: dict[str, dict] = {}
+        for symbol in symbols:
+            symbol = dict(symbol)
+            name = symbol.get("name", "")
+            if symbol.get("children"):
+                symbol["children"] = self.normalize_document_symbols(symbol["children"])
+            impl = _IMPL_NAME.match(name)
+            if impl:
+                symbol["name"] = f"{impl.group(1)}.{impl.group(2)}"
+                normalized.append(symbol)
+                continue
+            signature = function_signature(name)
+            if signature is None:
+                normalized.append(symbol)
+                continue
+            symbol["name"] = signature
+            if signature in functions:
+                _extend_range(functions[signature], symbol)
+                continue
+            if "range" in symbol:
+                symbol["range"] = {key: dict(value) for key, value in symbol["range"].items()}
+            functions[signature] = symbol
+            normalized.append(symbol)
+        return normalized
+
+    def build_qualified_name(
+        self,
+        file_path: Path,
+        symbol_name: str,
+        symbol_kind: int,
+        parent_chain: list[tuple[str, int]],
+        project_root: Path,
+        detail: str = "",
+    ) -> str:
+        """Qualify by module name: ``defmodule MyApp.Repo`` with ``get/2`` is ``MyApp.Repo.get/2``.
+
+        Module names are already global in Elixir, so the file path only
+        prefixes definitions outside any module (e.g. in ``.exs`` scripts).
+        """
+        if parent_chain:
+            return ".".join([*(name for name, _ in parent_chain), symbol_name])
+        if self.is_class_like(symbol_kind):
+            return symbol_name
+        return super().build_qualified_name(file_path, symbol_name, symbol_kind, parent_chain, project_root, detail)
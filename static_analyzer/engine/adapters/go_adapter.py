@@ -8,7 +8,7 @@ import shutil
 from pathlib import Path
 
 from repo_utils.ignore import RepoIgnoreManager, _ALWAYS_IGNORED_DIRS
-from static_analyzer.constants import Language
+from static_analyzer.constants import Language, NodeType
 from static_analyzer.engine.language_adapter import LanguageAdapter
 
 logger = logging.getLogger(__name__)
@@ -17,6 +17,13 @@ logger = logging.getLogger(__name__)
 _RECURSIVE_DIR_RE = re.compile(r"^\*\*/([a-zA-Z0-9_\-]+)(?:/\*\*)?/?$")
 # Matches patterns like "dirname/" (bare directory)
 _BARE_DIR_RE = re.compile(r"^([a-zA-Z0-9_\-]+)/$")
+# Go has no ``while``/``catch``; ``select`` cases are counted through ``case``.
+_DECISION_POINTS = re.compile(r"\b(?:if|for|case)\b|&&|\|\|")
+# ``[T any]`` / ``[K comparable, V any]``: a type-parameter list, as in ``(*Stack[T]).Push``.
+_TYPE_PARAMS_RE = re.compile(r"\[[^\[\]]*\]")
+# A file may declare any number of ``func init()``; beyond the first they are told apart as ``init#2``, ``init#3``.
+INIT_FUNCTION = "init"
+INIT_ORDINAL_SEPARATOR = "#"
 
 
 def _directory_filters_from_ignore_manager(ignore_manager: RepoIgnoreManager | None) -> list[str]:
@@ -87,6 +94,11 @@ class GoAdapter(LanguageAdapter):
         """Apply request backpressure while gopls creates file overlays."""
         return True
 
+    @property
+    def resolves_interface_implementations(self) -> bool:
+        """Go types satisfy interfaces implicitly; ask gopls which ones do."""
+        return True
+
     @property
     def language(self) -> str:
         return "Go"
@@ -103,6 +115,10 @@ class GoAdapter(LanguageAdapter):
     def language_id(self) -> str:
         return "go"
 
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        return _DECISION_POINTS
+
     def get_lsp_command(self, project_root: Path) -> list[str]:
         """Fail fast if the Go toolchain is missing.
 
@@ -117,6 +133,25 @@ class GoAdapter(LanguageAdapter):
             )
         return super().get_lsp_command(project_root)
 
+    def normalize_document_symbols(self, symbols: list[dict]) -> list[dict]:
+        """Number the ``init`` functions of a file that declares several: ``init``, ``init#2``, ...
+
+        Each is a separate function with its own body; under one name the
+        symbol table would keep only the last, and the calls of the others
+        would be attributed to nothing.
+        """
+        inits = [s for s in symbols if s.get("name") == INIT_FUNCTION and s.get("kind") == NodeType.FUNCTION]
+        if len(inits) < 2:
+            return symbols
+        ordinals = {id(symbol): ordinal for ordinal, symbol in enumerate(inits, start=1)}
+        normalized = []
+        for symbol in symbols:
+            ordinal = ordinals.get(id(symbol), 1)
+            if ordinal > 1:
+                symbol = {**symbol, "name": f"{INIT_FUNCTION}{INIT_ORDINAL_SEPARATOR}{ordinal}"}
+            normalized.append(symbol)
+        return normalized
+
     def build_qualified_name(
         self,
         file_path: Path,
@@ -130,9 +165,12 @@ class GoAdapter(LanguageAdapter):
         dir_parts = list(rel.parent.parts) if rel.parent != Path(".") else []
         file_stem = rel.stem
         module = ".".join(dir_parts + [file_stem]) if dir_parts else file_stem
+        # A generic type's methods are named after the type, without its type parameters.
+        symbol_name = _TYPE_PARAMS_RE.sub("", symbol_name)
 
         if parent_chain:
             receiver_name, receiver_kind = parent_chain[-1]
+            receiver_name = _TYPE_PARAMS_RE.sub("", receiver_name)
             is_pointer = self._is_pointer_receiver(detail, receiver_name)
             if is_pointer:
                 return f"{module}.(*{receiver_name}).{symbol_name}"
@@ -190,6 +228,10 @@ class GoAdapter(LanguageAdapter):
             "ui.diagnostic.staticcheck": True,
         }
 
+    def get_build_tag_settings(self, build_tags: tuple[str, ...]) -> dict:
+        """gopls loads packages with ``go list``; ``-tags`` there selects the tagged files."""
+        return {"buildFlags": [f"-tags={','.join(build_tags)}"]} if build_tags else {}
+
     def get_lsp_env(self, project_root: Path | None = None) -> dict[str, str]:
         """Tune Go GC for lower peak memory at the cost of more CPU.
 
@@ -2,10 +2,14 @@
 
 from __future__ import annotations
 
+import re
+
 from repo_utils.ignore import RepoIgnoreManager
-from static_analyzer.constants import Language
+from static_analyzer.constants import Language, NodeType
 from static_analyzer.engine.language_adapter import LanguageAdapter
 
+_DECISION_POINTS = re.compile(r"\b(?:if|elif|for|while|except|case|and|or)\b")
+
 
 class PythonAdapter(LanguageAdapter):
 
@@ -25,6 +29,20 @@ class PythonAdapter(LanguageAdapter):
     def language_id(self) -> str:
         return "python"
 
+    @property
+    def symbol_kind_overrides(self) -> dict[NodeType, str]:
+        # pyright reports ``@property`` functions as Property; they are methods with a body.
+        return {NodeType.PROPERTY: "method"}
+
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        # Comprehension ``if``/``for`` clauses count too, as they branch the same way.
+        return _DECISION_POINTS
+
+    @property
+    def line_comment_prefix(self) -> str:
+        return "#"
+
     def get_lsp_init_options(self, ignore_manager: RepoIgnoreManager | None = None) -> dict:
         return {
             "python": {
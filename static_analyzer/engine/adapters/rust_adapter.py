@@ -3,6 +3,7 @@
 from __future__ import annotations
 
 import logging
+import re
 import shutil
 import subprocess
 from pathlib import Path
@@ -17,6 +18,8 @@ logger = logging.getLogger(__name__)
 # File stems implicit in the module path: ``mod.rs`` (directory module
 # entry), ``lib.rs`` (library crate root), ``main.rs`` (binary crate root).
 _IMPLICIT_MODULE_STEMS = {"mod", "lib", "main"}
+# Each ``match`` arm (``=>``) is a branch; ``?`` is an early-return path.
+_DECISION_POINTS = re.compile(r"\b(?:if|for|while|loop)\b|=>|&&|\|\||\?")
 
 
 def _skip_angle_block(s: str, start: int) -> int:
@@ -84,6 +87,10 @@ class RustAdapter(LanguageAdapter):
     def language_enum(self) -> Language:
         return Language.RUST
 
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        return _DECISION_POINTS
+
     @property
     def references_per_query_timeout(self) -> int:
         """Non-zero gates the Phase-1.5 warmup probe so rust-analyzer builds
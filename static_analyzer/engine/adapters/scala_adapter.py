@@ -0,0 +1,207 @@
+"""Scala language adapter using Metals."""
+
+from __future__ import annotations
+
+import logging
+import re
+import shutil
+from pathlib import Path
+
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.engine.language_adapter import LanguageAdapter
+from static_analyzer.engine.lsp_constants import CALLABLE_KINDS, CLASS_LIKE_KINDS
+from static_analyzer.scala_source import COMPANION_SUFFIX, body_start_column, package_of
+
+logger = logging.getLogger(__name__)
+
+# Set on an ``object`` sharing its name with a class, trait or enum beside it.
+COMPANION_DETAIL = "companion"
+# ``case`` clauses branch; ``case class`` / ``case object`` declare.
+_DECISION_POINTS = re.compile(r"\b(?:if|for|while|catch)\b|\bcase\b(?!\s+(?:class|object)\b)|&&|\|\|")
+# Metals reports the value definitions of a class or object as these.
+_VALUE_KINDS = {NodeType.VARIABLE, NodeType.CONSTANT, NodeType.FIELD, NodeType.PROPERTY}
+
+
+def _spans_lines(symbol: dict) -> bool:
+    symbol_range = symbol.get("range") or symbol.get("location", {}).get("range", {})
+    return symbol_range.get("end", {}).get("line", 0) > symbol_range.get("start", {}).get("line", 0)
+
+
+class ScalaAdapter(LanguageAdapter):
+    """Static-analysis adapter for Scala projects backed by Metals.
+
+    Qualified names follow the package a file declares, not its path
+    (``com.acme.orders.OrderService.place``). Objects are class-like; a
+    companion object's members land on its class (``User.apply`` is called as
+    such), while the object itself is ``User$``. Traits a class mixes in are
+    recorded as implemented, and traits mixing in traits as embedded.
+    """
+
+    def __init__(self) -> None:
+        self._packages: dict[Path, str] = {}
+
+    @property
+    def language(self) -> str:
+        return "Scala"
+
+    @property
+    def language_enum(self) -> Language:
+        return Language.SCALA
+
+    @property
+    def lsp_command(self) -> list[str]:
+        return ["metals"]
+
+    @property
+    def language_id(self) -> str:
+        return "scala"
+
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        return _DECISION_POINTS
+
+    @property
+    def interface_supertypes_are_implementations(self) -> bool:
+        """A class or object mixing in a trait implements it."""
+        return True
+
+    @property
+    def interface_supertypes_are_embedded(self) -> bool:
+        """A trait mixing in another trait takes on its members, like Go interface embedding."""
+        return True
+
+    @property
+    def has_companion_objects(self) -> bool:
+        """``object User`` beside ``class User`` holds what Java would make its static members."""
+        return True
+
+    @property
+    def wait_for_workspace_ready(self) -> bool:
+        """Metals imports the build (sbt, Mill, Gradle via Bloop) and compiles it before references resolve."""
+        return True
+
+    def get_lsp_default_timeout(self) -> int:
+        """Requests queue behind the build import and first compilation."""
+        return 120
+
+    def get_probe_timeout_minimum(self) -> int:
+        """Importing and compiling an sbt build for the first time can take several minutes."""
+        return 600
+
+    def get_lsp_command(self, project_root: Path) -> list[str]:
+        """Fail fast if Java is missing.
+
+        Metals runs on the JVM, like JDTLS; Coursier installs it as a
+        launcher that starts the ``java`` on PATH (or ``JAVA_HOME``).
+        """
+        if shutil.which("java") is None:
+            raise RuntimeError(
+                "java not found on PATH. Metals runs on the JVM (Java 17+). Install a JDK, "
+                "e.g. with `cs java --jvm 17 --setup`, and re-run the analysis."
+            )
+        return super().get_lsp_command(project_root)
+
+    def get_lsp_init_options(self, ignore_manager: RepoIgnoreManager | None = None) -> dict:
+        """Report status through log messages and keep Metals' HTTP UI off: there is no editor to show them."""
+        return {"statusBarProvider": "log-message", "isHttpEnabled": False}
+
+    def get_workspace_settings(self) -> dict | None:
+        """Import the build without asking: Metals otherwise waits on a prompt nobody answers."""
+        return {"metals": {"autoImportBuild": "all"}}
+
+    def is_class_like(self, symbol_kind: int) -> bool:
+        return symbol_kind in CLASS_LIKE_KINDS or symbol_kind == NodeType.MODULE
+
+    def declaration_body_start(self, declaration_line: str) -> int | None:
+        """Scala bodies are often one expression on the declaration's line: ``def total = items.map(price).sum``."""
+        return body_start_column(declaration_line)
+
+    def normalize_document_symbols(self, symbols: list[dict]) -> list[dict]:
+        """Mark companion objects and treat multi-line values of a class or object as methods.
+
+        A ``val`` whose right-hand side spans lines is code of its own: a
+        lambda (``val handler = (req: Request) => ...``) or a
+        for-comprehension (``val total = for { ... } yield ...``). As a
+        method, the calls in that body become its edges rather than its
+        class's. Values inside a method stay locals.
+        """
+        return self._normalize(symbols, in_callable=False)
+
+    def _normalize(self, symbols: list[dict], in_callable: bool) -> list[dict]:
+        types = {symbol.get("name") for symbol in symbols if symbol.get("kind") in CLASS_LIKE_KINDS}
+        normalized: list[dict] = []
+        for symbol in symbols:
+            symbol = dict(symbol)
+            kind = symbol.get("kind")
+            children = symbol.get("children") or []
+            if kind == NodeType.MODULE and symbol.get("name") in types:
+                symbol["detail"] = COMPANION_DETAIL
+            elif (
+                kind in _VALUE_KINDS
+                and not in_callable
+                and _spans_lines(symbol)
+                and not any(child.get("kind") in CALLABLE_KINDS for child in children)
+            ):
+                symbol["kind"] = NodeType.METHOD
+            if children:
+                symbol["children"] = self._normalize(
+                    children, in_callable or symbol["kind"] in CALLABLE_KINDS or symbol["kind"] in _VALUE_KINDS
+                )
+            normalized.append(symbol)
+        return normalized
+
+    def build_qualified_name(
+        self,
+        file_path: Path,
+        symbol_name: str,
+        symbol_kind: int,
+        parent_chain: list[tuple[str, int]],
+        project_root: Path,
+        detail: str = "",
+    ) -> str:
+        """Qualify by the declared package: ``package com.acme`` with ``User.apply`` gives ``com.acme.User.apply``.
+
+        When Metals nests the declarations under the package clause, the
+        chain already names it. A script (``.sc``) is wrapped in an object
+        named after the file, as scala-cli compiles it.
+        """
+        if detail == COMPANION_DETAIL and symbol_kind == NodeType.MODULE:
+            symbol_name += COMPANION_SUFFIX
+        names = [name for name, _ in parent_chain]
+        if not (parent_chain and parent_chain[0][1] == NodeType.PACKAGE) and symbol_kind != NodeType.PACKAGE:
+            module = file_path.stem if file_path.suffix == ".sc" else self._package(file_path)
+            names = [module, *names] if module else names
+        return ".".join([*names, symbol_name])
+
+    def _package(self, file_path: Path) -> str:
+        if file_path not in self._packages:
+            try:
+                source = file_path.read_text(encoding="utf-8", errors="replace")
+            except OSError as e:
+                logger.debug(f"Scala: cannot read {file_path}: {e}")
+                source = ""
+            self._packages[file_path] = package_of(source)
+        return self._packages[file_path]
+
+    def extract_package(self, qualified_name: str) -> str:
+        """The leading lowercase names: ``com.acme.orders`` for ``com.acme.orders.OrderService.place``.
+
+        Scala packages are lowercase and types capitalized, so the package
+        ends at the first type; a top-level definition (Scala 3) drops only
+        its own name.
+        """
+        parts = qualified_name.split(".")
+        package: list[str] = []
+        for part in parts[:-1]:
+            if not part[:1].islower():
+                break
+            package.append(part)
+        return ".".join(package) or parts[0]
+
+    def get_package_for_file(self, file_path: Path, project_root: Path) -> str:
+        """The package the file declares, else the directory-based default."""
+        return self._package(file_path) or super().get_package_for_file(file_path, project_root)
+
+    def get_all_packages(self, source_files: list[Path], project_root: Path) -> set[str]:
+        return {self.get_package_for_file(file_path, project_root) for file_path in source_files}
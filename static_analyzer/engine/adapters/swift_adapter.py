@@ -0,0 +1,135 @@
+"""Swift language adapter using sourcekit-lsp."""
+
+from __future__ import annotations
+
+import logging
+import re
+import shutil
+from pathlib import Path
+
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.engine.language_adapter import LanguageAdapter
+from static_analyzer.swift_utils import SOURCEKIT_LSP, find_sourcekit_lsp
+
+logger = logging.getLogger(__name__)
+
+# sourcekit-lsp reports ``extension Foo { ... }`` as a namespace named after the extended type.
+EXTENSION_DETAIL = "extension"
+# SwiftPM keeps each target's sources under ``Sources/<Target>/`` (tests under ``Tests/<Target>/``).
+_TARGET_ROOTS = {"Sources", "Tests"}
+# ``guard`` and ``catch`` add paths like ``if``; ``??`` falls back on ``nil``.
+_DECISION_POINTS = re.compile(r"\b(?:if|guard|for|while|repeat|case|catch)\b|&&|\|\||\?\?")
+# ``Array<Element>`` / ``Array where Element == Int``: the extended type is the leading name.
+_EXTENDED_TYPE_RE = re.compile(r"^[\w.]+")
+
+
+def _spans_lines(symbol: dict) -> bool:
+    symbol_range = symbol.get("range") or symbol.get("location", {}).get("range", {})
+    return symbol_range.get("end", {}).get("line", 0) > symbol_range.get("start", {}).get("line", 0)
+
+
+class SwiftAdapter(LanguageAdapter):
+    """Static-analysis adapter for Swift projects backed by sourcekit-lsp.
+
+    Swift names are global to their module, so qualified names follow the
+    SwiftPM target rather than the file (``App.Dog.bark``): the members of an
+    ``extension Dog`` in another file land on ``Dog`` itself. Protocol
+    conformance is recorded like an interface implementation, not inheritance.
+    """
+
+    @property
+    def language(self) -> str:
+        return "Swift"
+
+    @property
+    def language_enum(self) -> Language:
+        return Language.SWIFT
+
+    @property
+    def lsp_command(self) -> list[str]:
+        return [SOURCEKIT_LSP]
+
+    @property
+    def language_id(self) -> str:
+        return "swift"
+
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        return _DECISION_POINTS
+
+    @property
+    def interface_supertypes_are_implementations(self) -> bool:
+        """A struct, class or enum conforming to a protocol implements it; it does not inherit from it."""
+        return True
+
+    def get_lsp_command(self, project_root: Path) -> list[str]:
+        """Fail fast if the Swift toolchain is missing.
+
+        sourcekit-lsp ships with the toolchain (Xcode or swift.org) and
+        needs it to build the index, so there is nothing to download. On
+        macOS the server is found through ``xcrun`` when not on PATH.
+        """
+        if shutil.which("swift") is None:
+            raise RuntimeError(
+                "swift not found on PATH. sourcekit-lsp ships with the Swift "
+                "toolchain and needs it to index the project. Install Xcode or a "
+                "toolchain from https://www.swift.org/install/ and re-run the analysis."
+            )
+        command = super().get_lsp_command(project_root)
+        if shutil.which(command[0]) is None and (found := find_sourcekit_lsp()) is not None:
+            command = [found, *command[1:]]
+        return command
+
+    def normalize_document_symbols(self, symbols: list[dict]) -> list[dict]:
+        """Mark extensions and treat computed properties as methods.
+
+        An extension keeps the extended type's name (generics stripped) so
+        its members qualify onto that type. A property spanning several
+        lines has a body (a computed property such as SwiftUI's ``body``);
+        as a method, the views and calls it composes become its edges.
+        """
+        normalized: list[dict] = []
+        for symbol in symbols:
+            symbol = dict(symbol)
+            if symbol.get("children"):
+                symbol["children"] = self.normalize_document_symbols(symbol["children"])
+            if symbol.get("kind") == NodeType.NAMESPACE:
+                extended = _EXTENDED_TYPE_RE.match(symbol.get("name", "").strip())
+                if extended is not None:
+                    symbol["name"] = extended.group(0)
+                    symbol["detail"] = EXTENSION_DETAIL
+            elif symbol.get("kind") == NodeType.PROPERTY and _spans_lines(symbol):
+                symbol["kind"] = NodeType.METHOD
+            normalized.append(symbol)
+        return normalized
+
+    def build_qualified_name(
+        self,
+        file_path: Path,
+        symbol_name: str,
+        symbol_kind: int,
+        parent_chain: list[tuple[str, int]],
+        project_root: Path,
+        detail: str = "",
+    ) -> str:
+        """Qualify by module: ``Sources/App/Models/Dog.swift`` declaring ``Dog.bark`` gives ``App.Dog.bark``.
+
+        The module is the SwiftPM target, else the top-level directory (an
+        Xcode project's target folder), else the file stem. Each extension
+        gets a name of its own so it does not replace the type it extends.
+        """
+        module = self._module(file_path, project_root)
+        if detail == EXTENSION_DETAIL and symbol_kind == NodeType.NAMESPACE:
+            symbol_name = f"{symbol_name}<extension:{file_path.stem}>"
+        return ".".join([module, *(name for name, _ in parent_chain), symbol_name])
+
+    @staticmethod
+    def _module(file_path: Path, project_root: Path) -> str:
+        parts = file_path.relative_to(project_root).parts
+        directories = parts[:-1]
+        roots = [index for index, part in enumerate(directories) if part in _TARGET_ROOTS]
+        if roots and roots[-1] + 1 < len(directories):
+            return directories[roots[-1] + 1]
+        if directories:
+            return directories[0]
+        return file_path.stem
@@ -3,16 +3,21 @@
 from __future__ import annotations
 
 import logging
+import os
 import time
 from pathlib import Path
 
+from monitoring.progress import Phase
 from static_analyzer.engine.edge_build_context import EdgeBuildContext
 from static_analyzer.engine.edge_builder import EdgeMap, build_edges_via_definitions, build_edges_via_references
 from static_analyzer.engine.progress import ProgressLogger
 from static_analyzer.engine.hierarchy_builder import HierarchyBuilder
 from static_analyzer.engine.language_adapter import LanguageAdapter
 from static_analyzer.engine.lsp_client import LSPClient
-from static_analyzer.engine.lsp_constants import DID_OPEN_BATCH_SIZE, EdgeStrategy
+from static_analyzer.engine.lsp_constants import (
+    DID_OPEN_BATCH_SIZE,
+    EdgeStrategy,
+)
 from static_analyzer.engine.models import CallFlowGraph, LanguageAnalysisResult
 from static_analyzer.engine.source_inspector import SourceInspector
 from static_analyzer.engine.symbol_table import SymbolTable
@@ -28,10 +33,13 @@ class CallGraphBuilder:
         lsp_client: LSPClient,
         adapter: LanguageAdapter,
         project_root: Path,
+        concurrency: int | None = None,
     ) -> None:
         self._lsp = lsp_client
         self._adapter = adapter
         self._root = project_root.resolve()
+        # ``--concurrency``: symbol requests in flight at once, else the CPU count.
+        self._concurrency = max(1, concurrency or os.cpu_count() or 1)
 
         self._symbol_table = SymbolTable(adapter)
         self._source_inspector = SourceInspector()
@@ -155,30 +163,55 @@ class CallGraphBuilder:
             probe_result = self._send_sync_probe(source_files, probe_timeout)
 
         # Phase 1: extract symbols from each file
-        pbar = ProgressLogger("Phase 1 (symbols)", total, unit="file")
-        for idx, file_path in enumerate(source_files, 1):
-            if interleave_open:
+        pbar = ProgressLogger("Phase 1 (symbols)", total, unit="file", report=Phase.SYMBOLS)
+        if interleave_open:
+            # Interleaved adapters deliberately query again after didOpen so
+            # that each overlay notification has a response barrier: one file
+            # at a time.
+            for file_path in source_files:
                 self._lsp.did_open(file_path, self._adapter.language_id)
+                self._register_file_symbols(file_path, self._document_symbols(file_path, timeout=probe_timeout))
+                pbar.set_postfix(symbols=len(self._symbol_table.symbols))
+                pbar.update(1)
+        else:
+            pending = source_files
             # Reuse the sync probe result for the first file to avoid a
             # redundant document_symbol query (the probe can take minutes).
-            # Interleaved adapters deliberately query again after didOpen so
-            # that each overlay notification has a response barrier.
-            should_reuse_probe = idx == 1 and not interleave_open
-            if should_reuse_probe and probe_result is not None:
-                symbols = probe_result
-            elif interleave_open:
-                symbols = self._lsp.document_symbol(file_path, timeout=probe_timeout)
-            else:
-                symbols = self._lsp.document_symbol(file_path)
-            self._symbol_table.register_symbols(file_path, symbols, parent_chain=[], project_root=self._root)
-            pbar.set_postfix(symbols=len(self._symbol_table.symbols))
-            pbar.update(1)
+            if source_files and probe_result is not None:
+                self._register_file_symbols(source_files[0], probe_result)
+                pbar.set_postfix(symbols=len(self._symbol_table.symbols))
+                pbar.update(1)
+                pending = source_files[1:]
+            # Every file is open by now, so the queries are independent: keep
+            # up to ``concurrency`` of them in flight and let the server work
+            # on them in parallel. Symbols are registered in file order.
+            for start in range(0, len(pending), self._concurrency):
+                window = pending[start : start + self._concurrency]
+                if len(window) == 1:
+                    results = [self._document_symbols(window[0])]
+                else:
+                    results = self._lsp.send_document_symbol_batch(window)
+                for file_path, symbols in zip(window, results):
+                    self._register_file_symbols(file_path, symbols)
+                pbar.set_postfix(symbols=len(self._symbol_table.symbols))
+                pbar.update(len(window))
         pbar.finish()
 
         logger.info("Discovered %d symbols across %d files", len(self._symbol_table.symbols), len(source_files))
 
         self._warmup_references(source_files)
 
+    def _document_symbols(self, file_path: Path, timeout: int | None = None) -> list[dict]:
+        """Symbols of one file; none when the request times out, so one hung file doesn't stop the run."""
+        try:
+            return self._lsp.document_symbol(file_path, timeout=timeout)
+        except TimeoutError:
+            return []
+
+    def _register_file_symbols(self, file_path: Path, symbols: list[dict]) -> None:
+        symbols = self._adapter.normalize_document_symbols(symbols)
+        self._symbol_table.register_symbols(file_path, symbols, parent_chain=[], project_root=self._root)
+
     def _bulk_did_open(self, source_files: list[Path]) -> None:
         """Phase 0: Send didOpen for all files so the LSP server can index them."""
         total = len(source_files)
@@ -11,6 +11,7 @@ import logging
 from dataclasses import dataclass
 from pathlib import Path
 
+from monitoring.progress import Phase
 from static_analyzer.engine.edge_build_context import EdgeBuildContext
 from static_analyzer.engine.progress import ProgressLogger
 from static_analyzer.constants import NodeType
@@ -84,7 +85,7 @@ def build_edges_via_references(
     skip_files: set[str] = set()
     skipped_positions = 0
 
-    pbar = ProgressLogger("Phase 2 (edges)", total_unique, unit="pos")
+    pbar = ProgressLogger("Phase 2 (edges)", total_unique, unit="pos", report=Phase.REFERENCES)
     for batch_start in range(0, total_unique, batch_size):
         batch_positions = unique_positions[batch_start : batch_start + batch_size]
 
@@ -228,7 +229,12 @@ def _process_references_for_position(
             if not container:
                 continue
             container = st.lift_to_callable(container)
-            if not container or container.qualified_name == sym.qualified_name:
+            if not container:
+                continue
+            if container.qualified_name == sym.qualified_name and not (
+                adapter.is_callable(sym.kind) and si.is_invocation(ref_file, ref_line, ref_end_char)
+            ):
+                # Recursion is a function calling itself; one naming itself (passed as a callback) is no edge.
                 continue
             if ref_loc == container.definition_location:
                 continue
@@ -245,6 +251,9 @@ def _process_references_for_position(
                 ref_end_char,
                 include_expression_body=adapter.include_references_on_declaration_line,
             )
+            if is_declaration_line and not is_declaration_body:
+                body_start = adapter.declaration_body_start(si.get_source_line(ref_file, ref_line) or "")
+                is_declaration_body = body_start is not None and ref_char > body_start
             if is_declaration_line:
                 if not is_declaration_body:
                     continue
@@ -331,7 +340,7 @@ def _resolve_definitions(
     batch_size = 50
     impl_queries_pending: list[ImplementationQuery] = []
 
-    pbar = ProgressLogger("Phase 2 (definitions)", total_files, unit="file")
+    pbar = ProgressLogger("Phase 2 (definitions)", total_files, unit="file", report=Phase.REFERENCES)
     for file_path in source_files:
         call_sites = ctx.source_inspector.find_call_sites(file_path)
         if not call_sites:
@@ -368,7 +377,7 @@ def _resolve_definitions(
                         continue
                     total_resolved += 1
 
-                    if not _is_valid_edge(caller, target):
+                    if not (_is_valid_edge(caller, target) or _is_self_call(adapter, caller, target, call_site)):
                         continue
 
                     _add_edge_call_site(edge_set, caller.qualified_name, target.qualified_name, call_site)
@@ -435,7 +444,7 @@ def _resolve_implementations(
 
     total_impl_resolved = 0
 
-    pbar = ProgressLogger("Phase 2b (impl)", total_impl_queries, unit="target")
+    pbar = ProgressLogger("Phase 2b (impl)", total_impl_queries, unit="target", report=Phase.REFERENCES)
     for batch_start in range(0, len(unique_impl_targets), batch_size):
         batch_keys = unique_impl_targets[batch_start : batch_start + batch_size]
         queries = [(Path(fk), ln, ch) for fk, ln, ch in batch_keys]
@@ -504,6 +513,13 @@ def _is_valid_edge(caller: SymbolInfo, target: SymbolInfo) -> bool:
     return True
 
 
+def _is_self_call(adapter: EdgeBuildAdapter, caller: SymbolInfo, target: SymbolInfo, call_site: CallSite) -> bool:
+    """A function calling itself (direct recursion), not the name in its own declaration."""
+    if target.qualified_name != caller.qualified_name or not adapter.is_callable(target.kind):
+        return False
+    return (call_site.file, call_site.lsp_line) != (str(caller.file_path), caller.start_line)
+
+
 def _resolve_definition_to_symbol(
     def_result: dict,
     pos_to_sym: dict[tuple[str, int, int], SymbolInfo],
@@ -5,16 +5,25 @@ from __future__ import annotations
 import logging
 import re
 import time
+from collections import defaultdict
+from pathlib import Path
 
+from static_analyzer.constants import NodeType
+from static_analyzer.elixir_directives import behaviours, module_directives, parse_directives
 from static_analyzer.engine.language_adapter import LanguageAdapter
 from static_analyzer.engine.lsp_client import LSPClient, MethodNotFoundError
 from static_analyzer.engine.models import SymbolInfo
 from static_analyzer.engine.source_inspector import SourceInspector
 from static_analyzer.engine.symbol_table import SymbolTable
 from static_analyzer.engine.utils import uri_to_path
+from static_analyzer.scala_source import COMPANION_SUFFIX, SCALA_SUFFIXES, declaration_header, supertypes
 
 logger = logging.getLogger(__name__)
 
+_SWIFT_TYPE_RE = re.compile(
+    r"\b(?:class|struct|enum|protocol|actor|extension)\s+(\w+)\s*(?:<[^>{]*>)?\s*:\s*([^{]+?)\s*(?:\bwhere\b|\{|$)"
+)
+
 
 class HierarchyBuilder:
     """Builds class hierarchy using LSP type hierarchy, falling back to source parsing."""
@@ -101,15 +110,156 @@ class HierarchyBuilder:
             logger.info("Type hierarchy not supported, inferring from source code")
             self._infer_hierarchy_from_source(class_symbols, class_names, hierarchy)
 
+        if self._adapter.resolves_interface_implementations:
+            self._link_interface_implementations(class_symbols, hierarchy)
+        if self._adapter.interface_supertypes_are_implementations:
+            self._split_interface_supertypes(class_symbols, hierarchy)
+        if self._adapter.interface_supertypes_are_embedded:
+            self._split_embedded_interfaces(class_symbols, hierarchy)
+        if self._adapter.has_companion_objects:
+            self._link_companions(hierarchy)
+        if self._adapter.declares_behaviours:
+            self._link_behaviours(class_symbols, hierarchy)
+
         links = sum(len(h["superclasses"]) for h in hierarchy.values())
+        implementations = sum(len(h.get("interfaces", [])) for h in hierarchy.values())
         logger.info(
-            "Hierarchy complete: %d classes, %d inheritance links in %.1fs",
+            "Hierarchy complete: %d classes, %d inheritance links, %d interface implementations in %.1fs",
             len(hierarchy),
             links,
+            implementations,
             time.monotonic() - t_start,
         )
         return hierarchy
 
+    def _link_interface_implementations(self, class_symbols: list[SymbolInfo], hierarchy: dict[str, dict]) -> None:
+        """Record which concrete types satisfy each interface, asking the server via ``textDocument/implementation``.
+
+        Entries gain ``interfaces`` (on the implementing type) and
+        ``implementations`` (on the interface). Kept apart from
+        ``superclasses`` since satisfying an interface is not inheritance.
+        Interfaces that embed other interfaces are not counted as implementations.
+        """
+        interfaces = [sym for sym in class_symbols if sym.kind == NodeType.INTERFACE]
+        for sym in class_symbols:
+            hierarchy[sym.qualified_name].setdefault("interfaces", [])
+            hierarchy[sym.qualified_name].setdefault("implementations", [])
+        if not interfaces:
+            return
+        try:
+            results, errors = self._lsp.send_implementation_batch(
+                [(sym.file_path, sym.start_line, sym.start_char) for sym in interfaces]
+            )
+        except Exception as e:
+            logger.warning("Implementation batch failed, skipping interface links: %s", e)
+            return
+        for index, (iface, locations) in enumerate(zip(interfaces, results)):
+            if index in errors:
+                logger.debug("Failed to get implementations for %s", iface.qualified_name)
+                continue
+            for location in locations or []:
+                impl_name = self._resolve_implementation_location(location)
+                if impl_name is None or impl_name not in hierarchy or impl_name == iface.qualified_name:
+                    continue
+                if iface.qualified_name not in hierarchy[impl_name]["interfaces"]:
+                    hierarchy[impl_name]["interfaces"].append(iface.qualified_name)
+                if impl_name not in hierarchy[iface.qualified_name]["implementations"]:
+                    hierarchy[iface.qualified_name]["implementations"].append(impl_name)
+
+    def _split_interface_supertypes(self, class_symbols: list[SymbolInfo], hierarchy: dict[str, dict]) -> None:
+        """Move the interfaces a concrete type lists as supertypes to ``interfaces``/``implementations``.
+
+        A Swift type's supertypes mix its superclass with the protocols it
+        conforms to; conformance is implementation, not inheritance. A
+        protocol refining another protocol stays a superclass link.
+        """
+        interfaces = {sym.qualified_name for sym in class_symbols if sym.kind == NodeType.INTERFACE}
+        for info in hierarchy.values():
+            info.setdefault("interfaces", [])
+            info.setdefault("implementations", [])
+        for qname, info in hierarchy.items():
+            if qname in interfaces:
+                continue
+            for super_name in [name for name in info["superclasses"] if name in interfaces]:
+                info["superclasses"].remove(super_name)
+                hierarchy[super_name]["subclasses"].remove(qname)
+                if super_name not in info["interfaces"]:
+                    info["interfaces"].append(super_name)
+                if qname not in hierarchy[super_name]["implementations"]:
+                    hierarchy[super_name]["implementations"].append(qname)
+
+    def _split_embedded_interfaces(self, class_symbols: list[SymbolInfo], hierarchy: dict[str, dict]) -> None:
+        """Move the interfaces an interface lists as supertypes to ``embeds``.
+
+        A Scala trait mixing in another trait takes on its members, which is
+        embedding rather than inheritance; a trait extending a class keeps
+        the superclass link.
+        """
+        interfaces = {sym.qualified_name for sym in class_symbols if sym.kind == NodeType.INTERFACE}
+        for qname in interfaces:
+            info = hierarchy[qname]
+            info.setdefault("embeds", [])
+            for super_name in [name for name in info["superclasses"] if name in interfaces]:
+                info["superclasses"].remove(super_name)
+                hierarchy[super_name]["subclasses"].remove(qname)
+                if super_name not in info["embeds"]:
+                    info["embeds"].append(super_name)
+
+    @staticmethod
+    def _link_companions(hierarchy: dict[str, dict]) -> None:
+        """Record each type's companion object (``User$`` for ``User``) under its ``companion`` entry."""
+        for qname in hierarchy:
+            owner = qname.removesuffix(COMPANION_SUFFIX)
+            if owner != qname and owner in hierarchy:
+                hierarchy[owner]["companion"] = qname
+
+    def _link_behaviours(self, class_symbols: list[SymbolInfo], hierarchy: dict[str, dict]) -> None:
+        """Record the behaviours each module declares (``@behaviour``, ``use GenServer``).
+
+        A behaviour defined in the project is linked like an interface, through
+        ``interfaces``/``implementations``; one from outside it (``GenServer``)
+        is listed under the module's ``behaviours`` so callback modules stay
+        recognizable.
+        """
+        for info in hierarchy.values():
+            info.setdefault("interfaces", [])
+            info.setdefault("implementations", [])
+        modules_by_file: dict[Path, list[SymbolInfo]] = defaultdict(list)
+        for sym in class_symbols:
+            modules_by_file[sym.file_path].append(sym)
+        for file_path, modules in modules_by_file.items():
+            lines = self._source_inspector.get_file_lines(file_path)
+            if not lines:
+                continue
+            directives = parse_directives("\n".join(lines))
+            spans = [(sym.qualified_name, sym.start_line, sym.end_line) for sym in modules]
+            for qname, own in module_directives(directives, spans).items():
+                for behaviour in behaviours(own, qname, directives):
+                    if behaviour == qname:
+                        continue
+                    if behaviour not in hierarchy:
+                        external = hierarchy[qname].setdefault("behaviours", [])
+                        if behaviour not in external:
+                            external.append(behaviour)
+                        continue
+                    if behaviour not in hierarchy[qname]["interfaces"]:
+                        hierarchy[qname]["interfaces"].append(behaviour)
+                    if qname not in hierarchy[behaviour]["implementations"]:
+                        hierarchy[behaviour]["implementations"].append(qname)
+
+    def _resolve_implementation_location(self, location: dict) -> str | None:
+        """Resolve an implementation ``Location``/``LocationLink`` to a concrete type's qualified name."""
+        uri = location.get("uri") or location.get("targetUri", "")
+        loc_range = location.get("range") or location.get("targetSelectionRange", {})
+        line = loc_range.get("start", {}).get("line", -1)
+        file_path = uri_to_path(uri)
+        if file_path is None:
+            return None
+        for sym in self._symbol_table.file_symbols.get(str(file_path), []):
+            if sym.start_line == line and self._adapter.is_class_like(sym.kind) and sym.kind != NodeType.INTERFACE:
+                return sym.qualified_name
+        return None
+
     def _resolve_type_hierarchy_item(self, item: dict) -> str | None:
         """Resolve a type hierarchy item to a qualified name in our symbol table."""
         name = item.get("name", "")
@@ -145,6 +295,11 @@ class HierarchyBuilder:
         Parses class definition lines to extract base classes. Handles:
         - Python: ``class Dog(Animal):`` or ``class Duck(Animal, SwimmingMixin):``
         - PHP: ``class Dog extends Animal implements Speakable``
+        - Elixir: ``defimpl Speakable, for: Dog`` (both the impl module and
+          ``Dog`` implement the ``Speakable`` protocol)
+        - Swift: ``struct Dog: Animal, Speakable {`` and ``extension Dog: Codable {``
+        - Scala: ``class Dog(name: String) extends Animal(name) with Speakable``,
+          the header read across lines up to the body
         """
         # Build a name-to-qualified-names index for resolving short class names
         short_name_to_qnames: dict[str, list[str]] = {}
@@ -152,11 +307,39 @@ class HierarchyBuilder:
             short = qname.rsplit(".", 1)[-1]
             short_name_to_qnames.setdefault(short, []).append(qname)
 
-        for sym in class_symbols:
+        # Swift conformances are also declared on extensions, which are not types themselves.
+        extensions = [
+            sym
+            for syms in self._symbol_table.primary_file_symbols.values()
+            for sym in syms
+            if sym.kind == NodeType.NAMESPACE and sym.file_path.suffix == ".swift"
+        ]
+        for sym in [*class_symbols, *extensions]:
             line = self._source_inspector.get_source_line(sym.file_path, sym.start_line)
             if line is None:
                 continue
 
+            # Swift: [attributes] class|struct|enum|protocol|actor|extension Name<T>: Base, Proto where ... {
+            if sym.file_path.suffix == ".swift":
+                swift_match = _SWIFT_TYPE_RE.search(line)
+                if swift_match:
+                    extended = short_name_to_qnames.get(swift_match.group(1), [])
+                    types = extended if sym.kind == NodeType.NAMESPACE else [sym.qualified_name]
+                    for base in swift_match.group(2).split(","):
+                        base_name = base.strip().split("<", 1)[0].rsplit(".", 1)[-1]
+                        if not base_name:
+                            continue
+                        for qname in types:
+                            self._link_hierarchy(qname, base_name, short_name_to_qnames, hierarchy)
+                continue
+
+            # Scala: class|trait|object|enum Name[T](...) extends Base(args) with Trait, Other derives Codec
+            if sym.file_path.suffix in SCALA_SUFFIXES:
+                header = declaration_header(self._source_inspector.get_file_lines(sym.file_path) or [], sym.start_line)
+                for base_name in supertypes(header):
+                    self._link_hierarchy(sym.qualified_name, base_name, short_name_to_qnames, hierarchy)
+                continue
+
             # Python: class Name(Base1, Base2):
             match = re.search(r"\bclass\s+\w+\s*\(([^)]+)\)", line)
             if match:
@@ -168,6 +351,17 @@ class HierarchyBuilder:
                     self._link_hierarchy(sym.qualified_name, base_name, short_name_to_qnames, hierarchy)
                 continue
 
+            # Elixir: defimpl Protocol, for: Type
+            impl_match = re.search(r"\bdefimpl\s+([\w.]+)(?:\s*,\s*for:\s*([\w.]+))?", line)
+            if impl_match:
+                protocol = impl_match.group(1).rsplit(".", 1)[-1]
+                self._link_hierarchy(sym.qualified_name, protocol, short_name_to_qnames, hierarchy)
+                implementer = impl_match.group(2)
+                for qname in short_name_to_qnames.get((implementer or "").rsplit(".", 1)[-1], []):
+                    if qname == implementer or qname.endswith(f".{implementer}"):
+                        self._link_hierarchy(qname, protocol, short_name_to_qnames, hierarchy)
+                continue
+
             # PHP: class Name extends Base implements Interface1, Interface2
             extends_match = re.search(r"\bextends\s+([\w\\]+)", line)
             if extends_match:
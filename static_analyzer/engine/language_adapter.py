@@ -3,6 +3,7 @@
 from __future__ import annotations
 
 import logging
+import re
 from abc import ABC, abstractmethod
 from pathlib import Path
 
@@ -12,12 +13,16 @@ from static_analyzer.engine.lsp_client import LSPClient
 from static_analyzer.engine.lsp_constants import (
     CALLABLE_KINDS,
     CLASS_LIKE_KINDS,
+    DEFAULT_LSP_TIMEOUT,
     EdgeStrategy,
 )
 from utils import get_config
 
 logger = logging.getLogger(__name__)
 
+# Branches, loops, handlers and short-circuit operators shared by the C family.
+_C_FAMILY_DECISION_POINTS = re.compile(r"\b(?:if|elseif|for|foreach|while|case|catch)\b|&&|\|\||\?\?")
+
 
 class LanguageAdapter(ABC):
     """Strategy interface for language-specific behavior."""
@@ -105,6 +110,15 @@ class LanguageAdapter(ABC):
             return f"{module}.{parents}.{symbol_name}"
         return f"{module}.{symbol_name}"
 
+    def normalize_document_symbols(self, symbols: list[dict]) -> list[dict]:
+        """Reshape a ``textDocument/documentSymbol`` response before its symbols are registered.
+
+        Default: unchanged. Override for servers that report one logical
+        symbol as several entries or with display-only names (e.g. ElixirLS
+        function clauses).
+        """
+        return symbols
+
     def build_reference_key(self, qualified_name: str) -> str:
         """Build the reference key from a qualified name.
 
@@ -153,15 +167,23 @@ class LanguageAdapter(ABC):
         """
         return None
 
+    def get_build_tag_settings(self, build_tags: tuple[str, ...]) -> dict:
+        """Return server settings selecting *build_tags* (``[[languages.paths]] build_tags``).
+
+        Only languages with build tags (Go) override this; the settings are
+        merged into both the init options and the workspace settings.
+        """
+        return {}
+
     def get_lsp_default_timeout(self) -> int:
         """Return the default per-request timeout in seconds for the LSP client.
 
         Override for language servers that need more time to index large
         projects before they can respond to requests (e.g., csharp-ls
-        loading a Roslyn workspace).  The default (60s) is suitable for
-        most servers.
+        loading a Roslyn workspace).  The default (30s) is suitable for
+        most servers; ``--lsp-timeout`` overrides it for every language.
         """
-        return 60
+        return DEFAULT_LSP_TIMEOUT
 
     @property
     def wait_for_workspace_ready(self) -> bool:
@@ -201,6 +223,53 @@ class LanguageAdapter(ABC):
         """
         return 0
 
+    @property
+    def resolves_interface_implementations(self) -> bool:
+        """If True, link types to the interfaces they satisfy via ``textDocument/implementation``.
+
+        For languages with structural interfaces (Go), where no declaration
+        names the interface, so neither type hierarchy nor source parsing
+        can see the relationship.
+        """
+        return False
+
+    @property
+    def interface_supertypes_are_implementations(self) -> bool:
+        """If True, a concrete type's interface supertypes are recorded as ``interfaces``, not ``superclasses``.
+
+        For languages whose type hierarchy lists adopted protocols alongside
+        the superclass (Swift), so conformance maps to ``implements`` edges.
+        """
+        return False
+
+    @property
+    def interface_supertypes_are_embedded(self) -> bool:
+        """If True, an interface's interface supertypes are recorded as ``embeds``, not ``superclasses``.
+
+        For languages whose interfaces carry members into the interfaces
+        mixing them in (Scala traits), so the link maps to ``embeds`` edges.
+        """
+        return False
+
+    @property
+    def has_companion_objects(self) -> bool:
+        """If True, an object named like a type plus ``$`` is that type's companion (Scala).
+
+        The companion is recorded under the type's ``companion`` hierarchy
+        entry and contained in it.
+        """
+        return False
+
+    @property
+    def declares_behaviours(self) -> bool:
+        """If True, modules implement the behaviours their ``@behaviour`` directives name.
+
+        For Elixir, where a behaviour is the interface: the directive sits in
+        the module body, not on its declaration line, and no LSP request
+        reports it.
+        """
+        return False
+
     @property
     def fail_on_empty_symbols(self) -> bool:
         """If True, a non-empty project producing zero symbols is fatal."""
@@ -300,6 +369,22 @@ class LanguageAdapter(ABC):
     def should_track_for_edges(self, symbol_kind: int) -> bool:
         return symbol_kind in (CALLABLE_KINDS | CLASS_LIKE_KINDS | {NodeType.VARIABLE, NodeType.CONSTANT})
 
+    def declaration_body_start(self, declaration_line: str) -> int | None:
+        """Column where a declaration's body starts on its own line, when no tree-sitter grammar can tell.
+
+        References after it are in the body (``def total = sum(items)``) and
+        kept as edges; ``None`` drops every reference on the declaration line.
+        """
+        return None
+
+    @property
+    def symbol_kind_overrides(self) -> dict[NodeType, str]:
+        """Where this server's SymbolKinds mean something else than ``symbol_kinds.DEFAULT_KIND_MAP`` says.
+
+        Values are canonical kind names (``"method"``, ``"type"``, ...).
+        """
+        return {}
+
     @property
     def edge_strategy(self) -> EdgeStrategy:
         """Edge-building strategy for Phase 2.
@@ -317,6 +402,21 @@ class LanguageAdapter(ABC):
         """
         return {}
 
+    @property
+    def decision_point_pattern(self) -> re.Pattern[str]:
+        """Tokens that each add one path through a function, for the complexity proxy.
+
+        Matched against source with comments and string literals blanked out
+        (see ``static_analyzer.complexity``). The default covers the C family;
+        override for languages with different branching keywords.
+        """
+        return _C_FAMILY_DECISION_POINTS
+
+    @property
+    def line_comment_prefix(self) -> str:
+        """Token that starts a line comment."""
+        return "//"
+
     @property
     def references_batch_size(self) -> int:
         """Max number of references requests to send in a single batch."""
@@ -13,9 +13,11 @@ import queue
 import subprocess
 import threading
 import time
-from collections.abc import Callable
+from collections.abc import Callable, Iterable
 from pathlib import Path
 
+from static_analyzer.engine.lsp_constants import LSP_RESTART_AFTER_TIMEOUTS
+from static_analyzer.engine.lsp_dump import LSPDumpRecorder
 from static_analyzer.engine.utils import uri_to_path
 from static_analyzer.lsp_client.diagnostics import FileDiagnosticsMap, LSPDiagnostic
 
@@ -30,6 +32,13 @@ def _progress_indicates_failure(message: str) -> bool:
     return "failed" in message_lower or "failure" in message_lower or "error" in message_lower
 
 
+def _document_uri(params: object) -> str | None:
+    """The ``textDocument.uri`` a request is about, if any."""
+    if isinstance(params, dict) and isinstance(params.get("textDocument"), dict):
+        return params["textDocument"].get("uri")
+    return None
+
+
 def _progress_token(value: object) -> ProgressToken | None:
     """Return a valid LSP work-done progress token."""
     if isinstance(value, str) or (isinstance(value, int) and not isinstance(value, bool)):
@@ -50,6 +59,8 @@ class LSPClient:
     Enhanced features:
     - Collects textDocument/publishDiagnostics notifications
     - Tracks language/status notifications for JDTLS import-wait
+    - Cancels requests that time out, records their files in
+      ``timed_out_files`` and restarts a server that keeps timing out
     """
 
     def __init__(
@@ -62,6 +73,8 @@ class LSPClient:
         extra_env: dict[str, str] | None = None,
         workspace_settings: dict | None = None,
         extra_client_capabilities: dict | None = None,
+        dump_recorder: LSPDumpRecorder | None = None,
+        restart_after_timeouts: int = LSP_RESTART_AFTER_TIMEOUTS,
     ) -> None:
         self._command = command
         self._project_root = project_root
@@ -72,6 +85,8 @@ class LSPClient:
         self._workspace_settings = workspace_settings
         # Adapter-specific keys merged into capabilities at ``initialize`` time.
         self._extra_client_capabilities = extra_client_capabilities or {}
+        # ``--dump-lsp``: receives every raw query response before anything normalizes it.
+        self.dump_recorder = dump_recorder
         self._process: subprocess.Popen | None = None  # type: ignore[type-arg]
         self._stdout_fd: int | None = None
         self._request_id = 0
@@ -79,10 +94,19 @@ class LSPClient:
         self._reader_thread: threading.Thread | None = None
         self._shutdown_event = threading.Event()
         self._write_lock = threading.Lock()
-
-        # Track opened documents and their version counters.
+        # Request id -> (method, send time), kept at DEBUG only to log each response's duration.
+        self._in_flight: dict[int, tuple[str, float]] = {}
+        # Request id -> the document it is about, to tell which file a timed-out request leaves partial.
+        self._request_uris: dict[int, str] = {}
+        # Files with a request that timed out: their symbols or edges are incomplete.
+        self.timed_out_files: set[Path] = set()
+        self._restart_after_timeouts = restart_after_timeouts
+        self._consecutive_timeouts = 0
+
+        # Track opened documents, their version counters and language ids (to reopen them on restart).
         self._opened_uris: set[str] = set()
         self._doc_versions: dict[str, int] = {}
+        self._doc_languages: dict[str, str] = {}
 
         # Diagnostics collection
         self._diagnostics: FileDiagnosticsMap = {}
@@ -205,6 +229,11 @@ class LSPClient:
 
         return init_result
 
+    @property
+    def is_running(self) -> bool:
+        """True while the server process is alive."""
+        return self._process is not None and self._process.poll() is None
+
     def shutdown(self) -> None:
         """Send shutdown request and exit notification, then terminate process."""
         self._shutdown_event.set()
@@ -233,6 +262,37 @@ class LSPClient:
             self._stdout_fd = None
         self._opened_uris.clear()
         self._doc_versions.clear()
+        self._doc_languages.clear()
+
+    def restart(self) -> None:
+        """Replace a hung server with a fresh process and reopen the documents that were open in it.
+
+        Waits for the new server to be ready when the old one had signalled readiness
+        (JDTLS, Metals), so queries after the restart do not race its import.
+        """
+        documents = dict(self._doc_languages)
+        was_ready = self._server_ready.is_set()
+        logger.warning(
+            "Restarting LSP server %s after %d timeouts in a row", self._command[0], self._consecutive_timeouts
+        )
+        self.shutdown()
+        self._shutdown_event.clear()
+        self._msg_queue = queue.Queue()
+        self._server_ready.clear()
+        self._in_flight.clear()
+        self._request_uris.clear()
+        self._consecutive_timeouts = 0
+        self.start()
+        if was_ready:
+            self.wait_for_server_ready()
+        for uri, language_id in documents.items():
+            if (file_path := uri_to_path(uri)) is not None:
+                self.did_open(file_path, language_id)
+
+    def pop_timed_out_files(self) -> set[Path]:
+        """Return and forget the files left partially analyzed by timed-out requests."""
+        files, self.timed_out_files = self.timed_out_files, set()
+        return files
 
     # ---- Document management ----
 
@@ -262,6 +322,7 @@ class LSPClient:
         )
         self._opened_uris.add(uri)
         self._doc_versions[uri] = 1
+        self._doc_languages[uri] = language_id
 
     def did_change(self, file_path: Path, content: str) -> None:
         """Notify the server that a document's content has changed."""
@@ -285,6 +346,7 @@ class LSPClient:
         )
         self._opened_uris.discard(uri)
         self._doc_versions.pop(uri, None)
+        self._doc_languages.pop(uri, None)
 
     # ---- LSP queries ----
 
@@ -313,6 +375,30 @@ class LSPClient:
             return result
         return []
 
+    def send_document_symbol_batch(self, file_paths: list[Path], timeout: int | None = None) -> list[list[dict]]:
+        """Send documentSymbol requests for several open files without waiting between them.
+
+        Results are in *file_paths* order. A file whose request errored is
+        retried on its own; a timed-out one, or one that fails again, gets
+        ``[]``. The deadline defaults to the per-request timeout times the
+        number of files, the bound the same queries have when sent one by one.
+        """
+        if timeout is None:
+            timeout = self._default_timeout * len(file_paths)
+
+        def build_params(file_path: Path, _line: int, _character: int) -> dict:
+            return {"textDocument": {"uri": file_path.resolve().as_uri()}}
+
+        queries = [(file_path, 0, 0) for file_path in file_paths]
+        results, error_indices = self._send_batch("textDocument/documentSymbol", queries, build_params, timeout=timeout)
+        for index in sorted(error_indices):
+            logger.warning("documentSymbol failed for %s in a batch; retrying it alone", file_paths[index])
+            try:
+                results[index] = self.document_symbol(file_paths[index])
+            except TimeoutError:
+                logger.warning("documentSymbol timed out for %s", file_paths[index])
+        return results
+
     def send_references_batch(
         self, queries: list[tuple[Path, int, int]], per_query_timeout: int = 0
     ) -> tuple[list[list[dict]], set[int]]:
@@ -545,6 +631,10 @@ class LSPClient:
             if rid in error_ids:
                 error_indices.add(i)
 
+        if self.dump_recorder is not None:
+            for rid, (file_path, line, character) in zip(req_ids, queries):
+                self.dump_recorder.record(method, build_params(file_path, line, character), results.get(rid))
+
         parsed: list[list[dict]] = []
         for rid in req_ids:
             raw = results.get(rid, [])
@@ -579,6 +669,8 @@ class LSPClient:
             if msg.get("id") != req_id:
                 continue
 
+            self._log_duration(req_id, "error" if "error" in msg else "ok")
+            self._responded(req_id)
             if "error" in msg:
                 error = msg["error"]
                 if isinstance(error, dict) and error.get("code") == LSP_METHOD_NOT_FOUND:
@@ -590,8 +682,12 @@ class LSPClient:
                     # blocking for the full 300s readiness timeout.
                     self._init_failed = True
                 return None
+            if self.dump_recorder is not None:
+                self.dump_recorder.record(method, params, msg.get("result"))
             return msg.get("result")
 
+        self._log_duration(req_id, "timed out")
+        self._handle_timeouts([req_id])
         raise TimeoutError(f"Timeout waiting for LSP response to request {req_id}")
 
     def _send_notification(self, method: str, params: dict | list | None) -> None:
@@ -611,6 +707,11 @@ class LSPClient:
         """
         if not self._process or not self._process.stdin:
             raise RuntimeError("LSP server not running")
+        if "id" in message and "method" in message:
+            if (uri := _document_uri(message.get("params"))) is not None:
+                self._request_uris[message["id"]] = uri
+            if logger.isEnabledFor(logging.DEBUG):
+                self._in_flight[message["id"]] = (message["method"], time.monotonic())
         body = json.dumps(message)
         header = f"Content-Length: {len(body)}\r\n\r\n"
         data = (header + body).encode("utf-8")
@@ -618,6 +719,45 @@ class LSPClient:
             self._process.stdin.write(data)
             self._process.stdin.flush()
 
+    def _log_duration(self, req_id: int, outcome: str) -> None:
+        """At DEBUG, log the method and round-trip time of request *req_id*, so slow queries stand out."""
+        sent = self._in_flight.pop(req_id, None)
+        if sent is not None:
+            method, sent_at = sent
+            logger.debug("LSP %s #%d %s in %.1f ms", method, req_id, outcome, (time.monotonic() - sent_at) * 1000)
+
+    def _responded(self, req_id: int) -> None:
+        """The server answered *req_id*, so it is not hung."""
+        self._request_uris.pop(req_id, None)
+        self._consecutive_timeouts = 0
+
+    def _handle_timeouts(self, req_ids: Iterable[int]) -> None:
+        """Cancel *req_ids*, mark their files partially analyzed, and restart the server if it keeps timing out.
+
+        A batch counts once towards the restart threshold: its requests share one deadline.
+        """
+        files: set[Path] = set()
+        for req_id in req_ids:
+            uri = self._request_uris.pop(req_id, None)
+            if uri is not None and (file_path := uri_to_path(uri)) is not None:
+                files.add(file_path)
+            try:
+                self._send_notification("$/cancelRequest", {"id": req_id})
+            except (OSError, RuntimeError, ValueError):
+                # The server is gone; there is nothing left to cancel.
+                pass
+        if not files:
+            return
+        self.timed_out_files |= files
+        logger.warning(
+            "LSP request timed out; %d file(s) partially analyzed: %s",
+            len(files),
+            ", ".join(sorted(str(f) for f in files)[:5]) + (", ..." if len(files) > 5 else ""),
+        )
+        self._consecutive_timeouts += 1
+        if self._consecutive_timeouts >= self._restart_after_timeouts:
+            self.restart()
+
     def _next_response(self, deadline: float) -> dict | None:
         """Dequeue the next response message, handling protocol housekeeping.
 
@@ -670,6 +810,8 @@ class LSPClient:
                 continue
 
             pending.discard(msg_id)  # type: ignore[arg-type]
+            self._log_duration(msg_id, "error" if "error" in msg else "ok")  # type: ignore[arg-type]
+            self._responded(msg_id)  # type: ignore[arg-type]
 
             if "error" in msg:
                 error_ids.add(msg_id)  # type: ignore[arg-type]
@@ -689,7 +831,10 @@ class LSPClient:
         timed_out = set(pending)
         for req_id in pending:
             logger.warning("Timeout waiting for references request %d", req_id)
+            self._log_duration(req_id, "timed out")
             results[req_id] = []
+        if timed_out:
+            self._handle_timeouts(sorted(timed_out))
 
         return results, timed_out, error_ids
 
@@ -808,6 +953,10 @@ class LSPClient:
             if "Finished loading solution" in message_text:
                 self._server_ready.set()
                 logger.info("LSP server: solution loaded (%s)", message_text)
+            # Metals logs "time: indexed workspace in 4.2s" once the build is imported and indexed
+            if "indexed workspace" in message_text:
+                self._server_ready.set()
+                logger.info("LSP server: workspace indexed (%s)", message_text)
 
         elif method == "$/progress":
             # csharp-ls and gopls report workspace load through work-done
@@ -25,6 +25,12 @@ CALLABLE_KINDS: set[int] = {
 # Batch size for did_open to avoid overwhelming LSP servers
 DID_OPEN_BATCH_SIZE = 50
 
+# Seconds one LSP request may take before it is cancelled, unless the adapter or ``--lsp-timeout`` says otherwise.
+DEFAULT_LSP_TIMEOUT = 30
+
+# Timed-out file requests in a row after which the language server is restarted.
+LSP_RESTART_AFTER_TIMEOUTS = 3
+
 
 class EdgeStrategy(StrEnum):
     """Edge-building strategy selection for Phase 2."""
@@ -0,0 +1,114 @@
+"""Raw LSP response dump (``--dump-lsp DIR``) for debugging adapters and custom tooling.
+
+Every symbol/reference-style request the engine sends is recorded verbatim —
+before ``CallGraphBuilder`` normalizes anything — and written per source file:
+
+    DIR/<language>[/<sub-project>]/server.json          # format version, command, serverInfo
+    DIR/<language>[/<sub-project>]/files/<rel path>.json
+
+Responses differ between language servers, so each language (and each
+sub-project a language runs a separate server for) gets its own namespace,
+and ``server.json`` says which server produced them. The per-file
+document maps each LSP method to the list of ``{"params", "result"}`` pairs
+sent for that file. The engine derives call edges from ``textDocument/references``;
+``callHierarchy/*`` entries appear only for requests that were actually issued.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import threading
+from pathlib import Path
+
+from static_analyzer.engine.utils import uri_to_path
+
+logger = logging.getLogger(__name__)
+
+LSP_DUMP_FORMAT_VERSION = 1
+
+DUMPED_METHODS = frozenset(
+    {
+        "textDocument/documentSymbol",
+        "textDocument/references",
+        "textDocument/definition",
+        "textDocument/implementation",
+        "textDocument/prepareTypeHierarchy",
+        "typeHierarchy/supertypes",
+        "typeHierarchy/subtypes",
+        "textDocument/prepareCallHierarchy",
+        "callHierarchy/incomingCalls",
+        "callHierarchy/outgoingCalls",
+    }
+)
+
+
+def _uri_of(params: dict | list | None) -> str | None:
+    if not isinstance(params, dict):
+        return None
+    document = params.get("textDocument")
+    if isinstance(document, dict) and isinstance(document.get("uri"), str):
+        return document["uri"]
+    item = params.get("item")
+    if isinstance(item, dict) and isinstance(item.get("uri"), str):
+        return item["uri"]
+    return None
+
+
+class LSPDumpRecorder:
+    """Collects the raw responses of one language server, grouped by source file."""
+
+    def __init__(self, root: Path, language: str, project_root: Path, command: list[str]):
+        self.root = root
+        self.language = language
+        self.project_root = project_root.resolve()
+        self.command = command
+        self.server_info: dict | None = None
+        self._by_file: dict[str, dict[str, list[dict]]] = {}
+        self._lock = threading.Lock()
+
+    def record(self, method: str, params: dict | list | None, result: object) -> None:
+        if method not in DUMPED_METHODS:
+            return
+        uri = _uri_of(params)
+        if uri is None:
+            return
+        with self._lock:
+            self._by_file.setdefault(uri, {}).setdefault(method, []).append({"params": params, "result": result})
+
+    def _relative_path(self, uri: str) -> str:
+        path = uri_to_path(uri)
+        if path is None:
+            return f"_external/{uri.replace(':', '_').replace('/', '_')}"
+        try:
+            return path.resolve().relative_to(self.project_root).as_posix()
+        except ValueError:
+            # Outside the project (stdlib, dependencies): keep it apart from project files.
+            return f"_external/{path.as_posix().replace(':', '').lstrip('/')}"
+
+    def write(self) -> int:
+        """Write ``server.json`` and one JSON file per recorded source file; returns the file count."""
+        with self._lock:
+            by_file = dict(self._by_file)
+        files_dir = self.root / "files"
+        try:
+            files_dir.mkdir(parents=True, exist_ok=True)
+            server = {
+                "format_version": LSP_DUMP_FORMAT_VERSION,
+                "language": self.language,
+                "project_root": str(self.project_root),
+                "command": self.command,
+                "server_info": self.server_info,
+            }
+            (self.root / "server.json").write_text(json.dumps(server, indent=2), encoding="utf-8")
+            for uri, methods in sorted(by_file.items()):
+                rel = self._relative_path(uri)
+                target = files_dir / f"{rel}.json"
+                target.parent.mkdir(parents=True, exist_ok=True)
+                document = {"format_version": LSP_DUMP_FORMAT_VERSION, "uri": uri, "file": rel, "responses": methods}
+                target.write_text(json.dumps(document, indent=1), encoding="utf-8")
+        except OSError as e:
+            logger.warning(f"Could not write LSP dump for {self.language} to {self.root}: {e}")
+            return 0
+        logger.info(f"Wrote raw LSP responses for {len(by_file)} {self.language} files to {self.root}")
+        return len(by_file)
@@ -6,6 +6,9 @@ Rules:
 - Log at every percentage step that crosses a milestone boundary (computed from
   total: 1% for large totals, 5-10% for small ones, etc.).
 - Always log the final 100% completion line.
+
+A logger created with a run *report* phase also feeds the ``--progress``
+reporter (see :mod:`monitoring.progress`).
 """
 
 from __future__ import annotations
@@ -13,6 +16,8 @@ from __future__ import annotations
 import logging
 import time
 
+from monitoring.progress import Phase, get_progress
+
 logger = logging.getLogger(__name__)
 
 # Minimum seconds between progress log lines.
@@ -22,7 +27,7 @@ MIN_INTERVAL_SEC = 5.0
 class ProgressLogger:
     """Tracks and logs progress for a phase with adaptive frequency."""
 
-    def __init__(self, phase: str, total: int, *, unit: str = "item") -> None:
+    def __init__(self, phase: str, total: int, *, unit: str = "item", report: Phase | None = None) -> None:
         self._phase = phase
         self._total = max(total, 1)
         self._unit = unit
@@ -40,12 +45,18 @@ class ProgressLogger:
             self._pct_step = 10
 
         self._extra: dict[str, object] = {}
+        self._report = report
+        self._empty = total <= 0
+        if report is not None:
+            get_progress().start(report, total, unit)
 
     def set_postfix(self, **kwargs: object) -> None:
         self._extra = kwargs
 
     def update(self, n: int = 1) -> None:
-        self._done = min(self._done + n, self._total)
+        done = min(self._done + n, self._total)
+        self._report_done(done)
+        self._done = done
         pct = int(self._done * 100 / self._total)
         now = time.monotonic()
 
@@ -61,8 +72,16 @@ class ProgressLogger:
 
     def finish(self) -> None:
         """Log the final 100% line (call after the loop)."""
+        self._report_done(self._total)
         self._done = self._total
         self._log(100, time.monotonic())
+        if self._report is not None:
+            get_progress().finish(self._report)
+
+    def _report_done(self, done: int) -> None:
+        # ``_total`` is at least 1 for the percentages; an empty phase reports nothing done.
+        if self._report is not None and done > self._done and not self._empty:
+            get_progress().advance(self._report, done - self._done, self._unit)
 
     def _log(self, pct: int, now: float) -> None:
         elapsed = now - self._t_start
@@ -46,6 +46,8 @@ class EdgeBuildAdapter(Protocol):
 
     def should_track_for_edges(self, symbol_kind: int) -> bool: ...
 
+    def declaration_body_start(self, declaration_line: str) -> int | None: ...
+
     def is_class_like(self, symbol_kind: int) -> bool: ...
 
     def is_callable(self, symbol_kind: int) -> bool: ...
@@ -44,8 +44,9 @@ def convert_to_codeboarding_format(
     symbol_nodes: dict[str, Node] = {}
     for qname, sym in symbol_table.symbols.items():
         node_type = _map_symbol_kind(sym.kind)
-        # Include symbols that are graph node types OR that participate in edges
-        if node_type not in GRAPH_NODE_TYPES and qname not in edge_participants:
+        # Include symbols that are graph node types, class-like for the language
+        # (Elixir modules), OR that participate in edges
+        if node_type not in GRAPH_NODE_TYPES and not adapter.is_class_like(sym.kind) and qname not in edge_participants:
             continue
 
         node = Node(
@@ -59,6 +60,11 @@ def convert_to_codeboarding_format(
         symbol_nodes[qname] = node
         call_graph.add_node(node)
 
+    # The behaviours from outside the project a module declares (Elixir ``use GenServer``), see HierarchyBuilder.
+    for qname, info in (result.hierarchy or {}).items():
+        if info.get("behaviours") and qname in symbol_nodes:
+            symbol_nodes[qname].standard_interfaces = tuple(info["behaviours"])
+
     # Add edges from the engine's CFG
     edges_added = 0
     edges_skipped = 0
@@ -151,11 +157,13 @@ def convert_to_codeboarding_format(
 def _add_reference_edges(call_graph: CallGraph, result: LanguageAnalysisResult) -> None:
     """Complete the graph with non-call relationship edges (see ``EdgeKind``).
 
-    CONTAINS and INHERITS need no extra LSP work — they come from the qualified-name
-    hierarchy and the already-computed class hierarchy. TYPEREF and IMPORT are read
+    CONTAINS, INHERITS and IMPLEMENTS need no extra LSP work — they come from the
+    qualified-name hierarchy and the already-computed class hierarchy. TYPEREF and IMPORT are read
     from the engine result when the analyzer populated them.
     """
-    class_qnames = {qname for qname, node in call_graph.nodes.items() if node.type in CLASS_TYPES}
+    class_qnames = {
+        qname for qname, node in call_graph.nodes.items() if node.type in CLASS_TYPES or node.type == NodeType.MODULE
+    }
 
     # CONTAINS: each method / nested symbol -> its innermost enclosing class node.
     for qname in call_graph.nodes:
@@ -172,6 +180,15 @@ def _add_reference_edges(call_graph: CallGraph, result: LanguageAnalysisResult)
     for child, info in (result.hierarchy or {}).items():
         for superclass in info.get("superclasses", []):
             call_graph.add_reference_edge(child, superclass, EdgeKind.INHERITS)
+        # IMPLEMENTS: concrete type -> each interface it satisfies (Go, Swift, Elixir behaviours; see HierarchyBuilder).
+        for interface in info.get("interfaces", []):
+            call_graph.add_reference_edge(child, interface, EdgeKind.IMPLEMENTS)
+        # EMBEDS: interface -> each interface it mixes in (Scala traits).
+        for embedded in info.get("embeds", []):
+            call_graph.add_reference_edge(child, embedded, EdgeKind.EMBEDS)
+        # CONTAINS: a companion object belongs to its type (Scala ``User$`` -> ``User``).
+        if info.get("companion"):
+            call_graph.add_reference_edge(info["companion"], child, EdgeKind.CONTAINS)
 
     # TYPEREF / IMPORT: emitted by the analyzer when available (see engine models).
     for src, dst in getattr(result, "type_references", None) or ():
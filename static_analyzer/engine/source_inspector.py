@@ -75,8 +75,15 @@ _NAME_NODE_TYPES = frozenset(
     }
 )
 _GENERIC_TYPE_NODE_TYPES = frozenset({"generic_name", "generic_type"})
+# Go explicit instantiation, ``Map[int, string](xs, f)``: the callee is the generic
+# declaration, not the last type argument. Node type -> field holding the callee.
+_TYPE_INSTANTIATION_FIELDS = {"index_expression": "operand", "type_instantiation_expression": "type"}
 _CALL_TARGET_FIELD_NAMES = ("function", "constructor", "name", "field", "property", "attribute")
 _CONSTRUCTOR_FIELD_NAMES = ("type", "name")
+# Anonymous functions invoked in place (Go ``defer func() {...}()`` / ``go func() {...}()``, JS IIFEs):
+# the callee has no name to query, and the calls in its body are call sites of their own.
+_FUNCTION_LITERAL_NODE_TYPES = frozenset({"func_literal", "function_expression", "arrow_function", "lambda"})
+_PARENTHESIZED_NODE_TYPES = frozenset({"parenthesized_expression"})
 _DECLARATION_BLOCK_NODE_TYPES = frozenset({"block", "compound_statement", "statement_block"})
 _EXPRESSION_BODY_NODE_TYPES = frozenset({"arrow_expression_clause"})
 
@@ -259,6 +266,8 @@ class SourceInspector:
                 or node.child_by_field_name("constructor")
                 or node.child_by_field_name("name")
             )
+            if self._is_function_literal(function):
+                return None
             return self._select_query_node(function)
         if node.type in _CONSTRUCTOR_NODE_TYPES:
             for field_name in _CONSTRUCTOR_FIELD_NAMES:
@@ -270,9 +279,17 @@ class SourceInspector:
             return self._last_named_child_of_type(node, _NAME_NODE_TYPES)
         return None
 
+    @staticmethod
+    def _is_function_literal(node: TreeSitterNode | None) -> bool:
+        while node is not None and node.type in _PARENTHESIZED_NODE_TYPES:
+            node = node.named_children[0] if node.named_children else None
+        return node is not None and node.type in _FUNCTION_LITERAL_NODE_TYPES
+
     def _select_query_node(self, node: TreeSitterNode | None) -> TreeSitterNode | None:
         if node is None:
             return None
+        if node.type in _TYPE_INSTANTIATION_FIELDS:
+            return self._select_query_node(node.child_by_field_name(_TYPE_INSTANTIATION_FIELDS[node.type]))
         for field_name in _CALL_TARGET_FIELD_NAMES:
             child = node.child_by_field_name(field_name)
             selected = self._select_query_node(child)
@@ -0,0 +1,264 @@
+"""Feature-flag guarded call edges (``[feature_flags]``, ``--flag NAME=on|off``).
+
+Code behind a flag check is sometimes an alternative architecture. Given the
+project's flag-check patterns,
+
+    [feature_flags]
+    patterns = ['flags.Enabled("{flag}")', "is_enabled('{flag}')"]
+
+the ``if`` blocks whose condition calls one (``if flags.Enabled("x") {``, or
+``if not is_enabled("x"):`` for the off side) and their ``else`` blocks are
+found in each source file. A call edge whose every call site sits inside such
+a block is guarded by that flag state; the relation edges in ``analysis.json``
+carry it as ``"flags": {"x": "on"}``, and ``--flag x=off`` drops the edges
+that only exist with ``x`` on before the components are generated.
+
+This is block-level only: conditions joined with ``or``/``||``, early returns
+and flag values passed around are not followed.
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights
+from project_config import ProjectConfig
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.graph import Edge
+from static_analyzer.language_results import ControlFlowGraph, LanguageResults
+
+logger = logging.getLogger(__name__)
+
+FLAG_PLACEHOLDER = "{flag}"
+_FLAG_NAME = r"(?P<flag>[\w.:/-]+)"
+_CONDITION_RE = re.compile(r"^\s*(?:\}\s*)?(?:else\s+if|elif|if)\b")
+_DISJUNCTION_RE = re.compile(r"\|\||\bor\b")
+_NEGATION_RE = re.compile(r"(?:!|\bnot)$")
+_ELSE_BRACE_RE = re.compile(r"\s*else\s*\{")
+_PY_ELSE_RE = re.compile(r"^([ \t]*)else\s*:")
+
+
+class FlagSettingError(ValueError):
+    pass
+
+
+def parse_flag_setting(value: str) -> tuple[str, bool]:
+    """``NAME=on`` / ``NAME=off`` (also ``true``/``false``, ``1``/``0``)."""
+    name, sep, state = value.partition("=")
+    state = state.strip().lower()
+    if not sep or not name.strip() or state not in ("on", "off", "true", "false", "1", "0"):
+        raise FlagSettingError(f"expected NAME=on or NAME=off, got '{value}'")
+    return name.strip(), state in ("on", "true", "1")
+
+
+def flag_state(enabled: bool) -> str:
+    return "on" if enabled else "off"
+
+
+@dataclass(frozen=True)
+class FlagBlock:
+    flag: str
+    enabled: bool
+    # 1-based, inclusive.
+    start_line: int
+    end_line: int
+
+
+def compile_flag_patterns(patterns: list[str]) -> list[re.Pattern]:
+    """Flag-check patterns -> regexes; either quote style matches, and ``{flag}`` captures the name."""
+    compiled = []
+    for pattern in patterns:
+        if FLAG_PLACEHOLDER not in pattern:
+            logger.warning(f"Ignoring feature flag pattern {pattern!r}: it has no {FLAG_PLACEHOLDER} placeholder")
+            continue
+        before, _, after = pattern.partition(FLAG_PLACEHOLDER)
+        parts = [re.sub("[\"']", lambda _: "[\"']", re.escape(part)) for part in (before, after)]
+        compiled.append(re.compile(parts[0] + _FLAG_NAME + parts[1]))
+    return compiled
+
+
+def load_flag_patterns(project_config: ProjectConfig) -> list[re.Pattern]:
+    configured = project_config.section("feature_flags").get("patterns", [])
+    if isinstance(configured, str):
+        configured = [configured]
+    return compile_flag_patterns([str(p) for p in configured if str(p).strip()])
+
+
+def _line_of(text: str, offset: int) -> int:
+    return text.count("\n", 0, offset) + 1
+
+
+def _brace_end(text: str, open_idx: int) -> int:
+    depth = 0
+    for i in range(open_idx, len(text)):
+        if text[i] == "{":
+            depth += 1
+        elif text[i] == "}":
+            depth -= 1
+            if depth == 0:
+                return i
+    return len(text) - 1
+
+
+def _indent(line: str) -> int:
+    expanded = line.expandtabs()
+    return len(expanded) - len(expanded.lstrip())
+
+
+def _python_block_end(lines: list[str], header: int) -> int:
+    """Index of the last line of the block opened at *header* (0-based)."""
+    indent = _indent(lines[header])
+    end = header
+    for i in range(header + 1, len(lines)):
+        stripped = lines[i].strip()
+        if not stripped or stripped.startswith("#"):
+            continue
+        if _indent(lines[i]) <= indent:
+            break
+        end = i
+    return end
+
+
+def _guard(line: str, match: re.Match) -> bool | None:
+    """The flag state the block under *line* requires, or None when the condition doesn't guard it."""
+    condition = _CONDITION_RE.match(line)
+    if condition is None or _DISJUNCTION_RE.search(line):
+        return None
+    return not _NEGATION_RE.search(line[: match.start()].rstrip("( "))
+
+
+def find_flag_blocks(text: str, suffix: str, patterns: list[re.Pattern]) -> list[FlagBlock]:
+    """The flag-guarded ``if``/``else`` blocks of one source file."""
+    lines = text.splitlines()
+    offsets = [0]
+    for line in text.splitlines(keepends=True):
+        offsets.append(offsets[-1] + len(line))
+    blocks: list[FlagBlock] = []
+    for index, line in enumerate(lines):
+        for pattern in patterns:
+            match = pattern.search(line)
+            if match is None or (enabled := _guard(line, match)) is None:
+                continue
+            flag = match.group("flag")
+            if suffix == ".py":
+                end = _python_block_end(lines, index)
+                blocks.append(FlagBlock(flag, enabled, index + 2, end + 1))
+                else_match = _PY_ELSE_RE.match(lines[end + 1]) if end + 1 < len(lines) else None
+                if else_match is not None and _indent(else_match.group(1)) == _indent(line):
+                    blocks.append(FlagBlock(flag, not enabled, end + 3, _python_block_end(lines, end + 1) + 1))
+            else:
+                open_idx = text.find("{", offsets[index] + match.end())
+                if open_idx == -1:
+                    continue
+                close_idx = _brace_end(text, open_idx)
+                blocks.append(FlagBlock(flag, enabled, _line_of(text, open_idx), _line_of(text, close_idx)))
+                if else_match := _ELSE_BRACE_RE.match(text, close_idx + 1):
+                    else_close = _brace_end(text, else_match.end() - 1)
+                    blocks.append(
+                        FlagBlock(flag, not enabled, _line_of(text, else_match.end() - 1), _line_of(text, else_close))
+                    )
+            break
+    return blocks
+
+
+class FlagGuards:
+    """Flag blocks per file, read once, answering which flag states a line requires."""
+
+    def __init__(self, patterns: list[re.Pattern]):
+        self.patterns = patterns
+        self._blocks: dict[str, list[FlagBlock]] = {}
+
+    def _file_blocks(self, file_path: str) -> list[FlagBlock]:
+        if file_path not in self._blocks:
+            try:
+                text = Path(file_path).read_text(encoding="utf-8", errors="replace")
+            except OSError:
+                text = ""
+            self._blocks[file_path] = find_flag_blocks(text, Path(file_path).suffix, self.patterns) if text else []
+        return self._blocks[file_path]
+
+    def at(self, file_path: str, line: int) -> dict[str, bool]:
+        """Flag states required to reach *line*; inner blocks win over outer ones."""
+        containing = [b for b in self._file_blocks(file_path) if b.start_line <= line <= b.end_line]
+        containing.sort(key=lambda b: b.end_line - b.start_line, reverse=True)
+        return {block.flag: block.enabled for block in containing}
+
+    def for_sites(self, file_path: str, lines: list[int]) -> dict[str, bool]:
+        """Flag states every one of *lines* requires; empty without call sites."""
+        if not lines:
+            return {}
+        guards = [self.at(file_path, line) for line in lines]
+        return {flag: state for flag, state in guards[0].items() if all(g.get(flag) == state for g in guards[1:])}
+
+
+def _edge_guards(edge: Edge, guards: FlagGuards) -> dict[str, bool]:
+    by_file: dict[str, list[int]] = {}
+    for site in edge.call_sites:
+        line = site.get("line")
+        if isinstance(line, int):
+            by_file.setdefault(str(site.get("file") or edge.src_node.file_path), []).append(line)
+    if len(by_file) != 1:
+        return {}
+    file_path, lines = next(iter(by_file.items()))
+    return guards.for_sites(file_path, lines)
+
+
+def apply_flag_settings(
+    static_analysis: StaticAnalysisResults, settings: dict[str, bool], patterns: list[re.Pattern]
+) -> StaticAnalysisResults:
+    """A view of *static_analysis* without the call edges that need a flag state other than *settings*.
+
+    Like ``--select``, only the call graphs are narrowed and the input is left
+    untouched for the static-analysis cache.
+    """
+    guards = FlagGuards(patterns)
+
+    def keep(edge: Edge) -> bool:
+        required = _edge_guards(edge, guards)
+        return all(settings.get(flag, state) == state for flag, state in required.items())
+
+    narrowed = StaticAnalysisResults(
+        diagnostics=static_analysis.diagnostics,
+        incremental_base_results=static_analysis.incremental_base_results,
+    )
+    for language, bucket in static_analysis.results.items():
+        cfg = ControlFlowGraph()
+        if bucket.cfg.graph is not None:
+            graph = bucket.cfg.graph
+            cfg.graph = graph.filter(lambda node: True, lambda edge: None, keep_edge=keep)
+            dropped = len(graph.edges) - len(cfg.graph.edges)
+            logger.info(f"--flag: dropped {dropped} of {len(graph.edges)} {language} calls behind other flag states")
+        narrowed.results[language] = LanguageResults(
+            cfg=cfg,
+            hierarchy=bucket.hierarchy,
+            references=bucket.references,
+            dependencies=bucket.dependencies,
+            source_files=bucket.source_files,
+        )
+    return narrowed
+
+
+def tag_flag_guarded_edges(
+    repo_dir: Path,
+    root_analysis: AnalysisInsights,
+    sub_analyses: dict[str, AnalysisInsights],
+    patterns: list[re.Pattern],
+) -> None:
+    """Set ``flags`` on every relation edge, at every level, from the flag blocks around its call sites."""
+    guards = FlagGuards(patterns)
+    tagged = 0
+    for analysis in (root_analysis, *sub_analyses.values()):
+        for relation in analysis.components_relations:
+            for edge in (*relation.key_edges, *relation.all_edges):
+                edge.flags = {}
+                if not patterns or not edge.source.reference_file:
+                    continue
+                file_path = Path(edge.source.reference_file)
+                if not file_path.is_absolute():
+                    file_path = repo_dir / file_path
+                required = guards.for_sites(str(file_path), [site.line for site in edge.call_sites])
+                edge.flags = {flag: flag_state(state) for flag, state in sorted(required.items())}
+                tagged += bool(edge.flags)
+    if patterns:
+        logger.info(f"Feature flags: {tagged} relation edges only occur behind a flag check")
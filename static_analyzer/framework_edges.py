@@ -0,0 +1,222 @@
+"""Decorator-driven wiring for NestJS and Angular, added on top of the LSP call graph.
+
+In these frameworks the architecture lives in decorators: a ``@Controller``
+gets its services through constructor injection, and ``@Module`` /
+``@NgModule`` metadata registers controllers, providers and routes. None of
+that is a call, so plain call analysis leaves such a service nearly edgeless.
+This pass reads the TS/JS sources and adds class -> class call edges for
+injections and registrations. It is opt-in via ``--framework``, which also
+takes ``http`` for the Go HTTP route pass (``static_analyzer.http_routes``).
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from enum import StrEnum
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph
+
+logger = logging.getLogger(__name__)
+
+
+class Framework(StrEnum):
+    NEST = "nest"
+    ANGULAR = "angular"
+    # Go HTTP routers (net/http, gin, echo, chi); see ``static_analyzer.http_routes``.
+    HTTP = "http"
+
+
+@dataclass(frozen=True)
+class _FrameworkSpec:
+    # Class decorators whose constructor parameters / inject() calls are DI injections.
+    injectable_decorators: frozenset[str]
+    # Decorator -> metadata keys whose array entries the decorated class registers.
+    registration_keys: dict[str, tuple[str, ...]]
+    # Object keys inside route tables that name a routed class.
+    route_keys: tuple[str, ...]
+
+
+_SPECS: dict[Framework, _FrameworkSpec] = {
+    Framework.NEST: _FrameworkSpec(
+        injectable_decorators=frozenset(
+            {"Controller", "Injectable", "Resolver", "Gateway", "WebSocketGateway", "Processor", "Module"}
+        ),
+        registration_keys={"Module": ("controllers", "providers", "imports")},
+        route_keys=("module",),
+    ),
+    Framework.ANGULAR: _FrameworkSpec(
+        injectable_decorators=frozenset({"Component", "Directive", "Pipe", "Injectable", "NgModule"}),
+        registration_keys={
+            "NgModule": ("declarations", "imports", "providers", "bootstrap"),
+            "Component": ("imports", "providers"),
+        },
+        route_keys=("component", "loadComponent", "loadChildren"),
+    ),
+}
+
+_DECORATOR_RE = re.compile(r"@(\w+)\s*\(")
+_NEXT_DECORATOR_RE = re.compile(r"\s*@\w+\s*")
+_CLASS_HEADER_RE = re.compile(r"\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(\w+)")
+_CONSTRUCTOR_RE = re.compile(r"\bconstructor\s*\(")
+_PARAM_TYPE_RE = re.compile(r":\s*([A-Z][\w$]*)")
+_INJECT_CALL_RE = re.compile(r"\binject\s*\(\s*([A-Z][\w$]*)")
+_LEADING_IDENT_RE = re.compile(r"^\s*(?:forwardRef\s*\(\s*\(\s*\)\s*=>\s*)?([A-Za-z_$][\w$]*)")
+# ``component: Foo`` / ``loadComponent: () => import('./x').then((m) => m.Foo)``
+_ROUTE_TARGET_TEMPLATE = r"\b{key}\s*:\s*(?:[^,{{}}]*?\bm\.)?([A-Z][\w$]*)"
+
+
+def _matching(text: str, open_idx: int) -> int:
+    """Index of the bracket closing the one at *open_idx* (``len(text)`` if unbalanced)."""
+    pairs = {"(": ")", "[": "]", "{": "}"}
+    opener, closer = text[open_idx], pairs[text[open_idx]]
+    depth = 0
+    for i in range(open_idx, len(text)):
+        if text[i] == opener:
+            depth += 1
+        elif text[i] == closer:
+            depth -= 1
+            if depth == 0:
+                return i
+    return len(text)
+
+
+def _split_top_level(text: str) -> list[str]:
+    parts, depth, start = [], 0, 0
+    for i, ch in enumerate(text):
+        if ch in "([{<":
+            depth += 1
+        elif ch in ")]}" or (ch == ">" and text[i - 1 : i] != "="):  # ``=>`` is not a closing bracket
+            depth -= 1
+        elif ch == "," and depth == 0:
+            parts.append(text[start:i])
+            start = i + 1
+    parts.append(text[start:])
+    return [p for p in parts if p.strip()]
+
+
+@dataclass
+class _DecoratedClass:
+    name: str
+    decorators: dict[str, str]  # decorator name -> raw argument text
+    body: str
+
+
+def _class_after(text: str, pos: int) -> re.Match | None:
+    """Match the class header following position *pos*, skipping any further stacked decorators."""
+    while decorator := _NEXT_DECORATOR_RE.match(text, pos):
+        pos = decorator.end()
+        if text[pos : pos + 1] == "(":
+            pos = _matching(text, pos) + 1
+    return _CLASS_HEADER_RE.match(text, pos)
+
+
+def _decorated_classes(text: str) -> list[_DecoratedClass]:
+    classes: dict[int, _DecoratedClass] = {}
+    for match in _DECORATOR_RE.finditer(text):
+        args_end = _matching(text, match.end() - 1)
+        class_match = _class_after(text, args_end + 1)
+        if class_match is None:
+            continue  # a member/parameter decorator, not a class decorator
+        start = class_match.start(1)
+        if start not in classes:
+            body_open = text.find("{", class_match.end())
+            body = text[body_open : _matching(text, body_open) + 1] if body_open != -1 else ""
+            classes[start] = _DecoratedClass(class_match.group(1), {}, body)
+        classes[start].decorators[match.group(1)] = text[match.end() : args_end]
+    return list(classes.values())
+
+
+def _injected_types(body: str) -> list[str]:
+    injected: list[str] = []
+    ctor = _CONSTRUCTOR_RE.search(body)
+    if ctor:
+        params = body[ctor.end() : _matching(body, ctor.end() - 1)]
+        for param in _split_top_level(params):
+            # ``@Inject(forwardRef(() => Foo)) foo`` names the dependency in the decorator.
+            token = ""
+            while decorator := _DECORATOR_RE.search(param):
+                args_end = _matching(param, decorator.end() - 1)
+                token = token or param[decorator.end() : args_end]
+                param = param[: decorator.start()] + param[args_end + 1 :]
+            type_match = _PARAM_TYPE_RE.search(param)
+            if type_match:
+                injected.append(type_match.group(1))
+            elif ident := _LEADING_IDENT_RE.match(token):
+                injected.append(ident.group(1))
+    injected.extend(_INJECT_CALL_RE.findall(body))
+    return injected
+
+
+def _registered_names(decorator_args: str, keys: tuple[str, ...]) -> list[str]:
+    names: list[str] = []
+    for key in keys:
+        for key_match in re.finditer(rf"\b{key}\s*:\s*\[", decorator_args):
+            open_idx = key_match.end() - 1
+            for entry in _split_top_level(decorator_args[open_idx + 1 : _matching(decorator_args, open_idx)]):
+                ident = _LEADING_IDENT_RE.match(entry)
+                if ident:
+                    names.append(ident.group(1))
+    return names
+
+
+def _route_targets(text: str, keys: tuple[str, ...]) -> list[str]:
+    return [name for key in keys for name in re.findall(_ROUTE_TARGET_TEMPLATE.format(key=key), text)]
+
+
+def _class_index(call_graph: CallGraph) -> dict[str, list[str]]:
+    """Short class name -> qualified names of class nodes with that name."""
+    index: dict[str, list[str]] = {}
+    for qname, node in call_graph.nodes.items():
+        if node.type == NodeType.CLASS:
+            index.setdefault(qname.rsplit(call_graph.delimiter, 1)[-1], []).append(qname)
+    return index
+
+
+def _resolve(name: str, index: dict[str, list[str]], call_graph: CallGraph, file_path: str) -> str | None:
+    candidates = index.get(name, [])
+    if len(candidates) == 1:
+        return candidates[0]
+    same_file = [q for q in candidates if call_graph.nodes[q].file_path == file_path]
+    return same_file[0] if len(same_file) == 1 else None
+
+
+def add_framework_edges(call_graph: CallGraph, source_files: list[str], framework: Framework) -> int:
+    """Add injection, registration and route edges for *framework*; returns how many were added."""
+    spec = _SPECS[framework]
+    index = _class_index(call_graph)
+    before = len(call_graph.edges)
+
+    def link(src_name: str, dst_name: str, file_path: str) -> None:
+        src = _resolve(src_name, index, call_graph, file_path)
+        dst = _resolve(dst_name, index, call_graph, file_path)
+        if src and dst and src != dst:
+            call_graph.add_edge(src, dst)
+
+    for file_path in source_files:
+        try:
+            text = Path(file_path).read_text(encoding="utf-8", errors="replace")
+        except OSError as e:
+            logger.debug(f"Framework pass: cannot read {file_path}: {e}")
+            continue
+        if "@" not in text:
+            continue
+        classes = _decorated_classes(text)
+        for cls in classes:
+            if spec.injectable_decorators.intersection(cls.decorators):
+                for dependency in _injected_types(cls.body):
+                    link(cls.name, dependency, file_path)
+            for decorator, keys in spec.registration_keys.items():
+                if decorator in cls.decorators:
+                    for registered in _registered_names(cls.decorators[decorator], keys):
+                        link(cls.name, registered, file_path)
+        # Route tables belong to the module that declares them (e.g. an AppRoutingModule).
+        owners = [c.name for c in classes if set(c.decorators) & set(spec.registration_keys)]
+        if owners:
+            for target in _route_targets(text, spec.route_keys):
+                link(owners[0], target, file_path)
+
+    added = len(call_graph.edges) - before
+    logger.info(f"{framework} framework pass added {added} decorator/route edges")
+    return added
@@ -0,0 +1,354 @@
+"""Go channel data flow, added on top of the LSP call graph.
+
+A goroutine that sends on a channel and one that receives from it are
+connected, yet neither calls the other, so call analysis shows them as
+unrelated. This pass finds the send (``ch <- v``) and receive (``<-ch``,
+``range ch``) sites in Go sources, works out which channel each one uses, and
+links every function that sends on a channel to every function that receives
+from it: a ``sends-to`` reference edge from producer to consumer and a
+``receives-from`` edge back.
+
+Which channel a site uses is a guess (flow analysis is undecidable in
+general), so each edge records how it was traced:
+
+* ``high``: a package-level channel variable;
+* ``medium``: a channel followed through calls (``go worker(jobs)``, closures
+  invoked with arguments) into the callee's channel parameter;
+* ``low``: a struct field or other selector, matched by name within the package.
+
+Goroutines are linked too: ``go worker(jobs)`` adds a ``spawns`` reference
+edge from the function holding the statement to ``worker``. ``go func() {
+... }()`` literals run code of the enclosing function and add nothing.
+"""
+
+import logging
+import re
+from collections import defaultdict
+from dataclasses import dataclass
+from pathlib import Path
+
+from static_analyzer.go_source import FUNC_LITERAL_RE, clean
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+CONFIDENCE_RANK = {"low": 0, "medium": 1, "high": 2}
+
+_IDENT = r"[A-Za-z_]\w*"
+_OPERAND = rf"{_IDENT}(?:\.{_IDENT})*"
+_LHS_RE = re.compile(rf"({_OPERAND})\s*$")
+_OPERAND_RE = re.compile(rf"\s*({_OPERAND})\s*(\(?)")
+_RANGE_RE = re.compile(rf"\brange\s+({_OPERAND})\s*\{{")
+_CHAN_TYPE_RE = re.compile(r"(?:<-\s*)?chan\b")
+# ``jobs := make(chan T)`` / ``var jobs = make(chan T)`` and ``jobs chan T`` (var, field or parameter).
+_CHAN_DECL_RE = re.compile(rf"\b({_IDENT})\s*:?=\s*make\(\s*(?:<-\s*)?chan\b|\b({_IDENT})\s+(?:<-\s*)?chan\b")
+_FUNC_DECL_RE = re.compile(rf"\bfunc\s*(?:\([^)]*\)\s*)?{_IDENT}\s*(?:\[[^\]]*\]\s*)?\(")
+_CALL_RE = re.compile(rf"\b({_OPERAND})\s*\(")
+_STRUCT_RE = re.compile(r"\bstruct\s*\{")
+# ``go worker(`` / ``go h.serve(`` / ``go pool.Run[T](``: a goroutine started on a named function.
+_GO_STMT_RE = re.compile(rf"(?<![\w.])go\s+({_OPERAND})\s*(?:\[[^\]]*\]\s*)?\(")
+# Words that can precede ``<-`` without being the channel of a send.
+_NOT_OPERANDS = frozenset({"return", "case", "chan", "go", "defer", "if", "else", "for", "switch", "select", "range"})
+_NOT_CALLS = frozenset({"func", "make", "len", "cap", "close", "append", "new", "panic", "if", "for", "switch"})
+
+
+@dataclass(frozen=True)
+class ChannelLink:
+    producer: str
+    consumer: str
+    channel: str
+    confidence: str
+
+
+def _matching(text: str, open_idx: int) -> int:
+    """Index of the bracket closing the one at *open_idx* (``len(text)`` if unbalanced)."""
+    opener, closer = text[open_idx], {"(": ")", "[": "]", "{": "}"}[text[open_idx]]
+    depth = 0
+    for i in range(open_idx, len(text)):
+        if text[i] == opener:
+            depth += 1
+        elif text[i] == closer:
+            depth -= 1
+            if depth == 0:
+                return i
+    return len(text)
+
+
+def _split_args(text: str) -> list[str]:
+    parts, depth, start = [], 0, 0
+    for i, ch in enumerate(text):
+        if ch in "([{":
+            depth += 1
+        elif ch in ")]}":
+            depth -= 1
+        elif ch == "," and depth == 0:
+            parts.append(text[start:i].strip())
+            start = i + 1
+    parts.append(text[start:].strip())
+    return [p for p in parts if p]
+
+
+def _chan_params(params: str) -> list[str | None]:
+    """Parameter names by position, ``None`` where the parameter is not a channel.
+
+    Go shares a type across grouped names (``in, out chan int``), so types are
+    carried backwards to the names that precede them.
+    """
+    entries = _split_args(params)
+    names: list[str | None] = [None] * len(entries)
+    param_type = ""
+    for i in range(len(entries) - 1, -1, -1):
+        parts = entries[i].split(None, 1)
+        if len(parts) == 2:
+            param_type = parts[1]
+        if _CHAN_TYPE_RE.match(param_type) and re.fullmatch(_IDENT, parts[0]):
+            names[i] = parts[0]
+    return names
+
+
+class _ChannelClasses:
+    """Union-find over channel keys; a class remembers whether a call joined it."""
+
+    def __init__(self) -> None:
+        self.parent: dict[tuple, tuple] = {}
+        self.via_call: set[tuple] = set()
+
+    def find(self, key: tuple) -> tuple:
+        self.parent.setdefault(key, key)
+        while self.parent[key] != key:
+            self.parent[key] = self.parent[self.parent[key]]
+            key = self.parent[key]
+        return key
+
+    def union(self, a: tuple, b: tuple) -> None:
+        ra, rb = self.find(a), self.find(b)
+        if ra != rb:
+            self.parent[rb] = ra
+        self.via_call.add(ra)
+
+    def confidence(self, root: tuple, members: list[tuple]) -> str:
+        if any(key[0] == "field" for key in members):
+            return "low"
+        return "medium" if root in self.via_call else "high"
+
+
+@dataclass
+class _GoFile:
+    path: str
+    package: str
+    text: str
+    line_offsets: list[int]
+    owners: list[Node]
+
+    def line_of(self, offset: int) -> int:
+        lo, hi = 0, len(self.line_offsets) - 1
+        while lo < hi:
+            mid = (lo + hi + 1) // 2
+            if self.line_offsets[mid] <= offset:
+                lo = mid
+            else:
+                hi = mid - 1
+        return lo + 1
+
+    def owner_at(self, offset: int) -> Node | None:
+        """Innermost callable node whose (1-based) line range contains *offset*."""
+        line = self.line_of(offset)
+        containing = [n for n in self.owners if n.line_start <= line <= n.line_end]
+        return min(containing, key=lambda n: n.line_end - n.line_start, default=None)
+
+    def span(self, node: Node) -> tuple[int, int]:
+        start = self.line_offsets[min(node.line_start, len(self.line_offsets)) - 1]
+        end = self.line_offsets[node.line_end] if node.line_end < len(self.line_offsets) else len(self.text)
+        return start, end
+
+
+def _load(call_graph: CallGraph, source_files: list[str], markers: tuple[str, ...] = ("chan", "<-")) -> list[_GoFile]:
+    owners: dict[str, list[Node]] = defaultdict(list)
+    for node in call_graph.nodes.values():
+        if node.is_callable():
+            owners[node.file_path].append(node)
+    files: list[_GoFile] = []
+    for file_path in source_files:
+        if not file_path.endswith(".go"):
+            continue
+        try:
+            text = clean(Path(file_path).read_text(encoding="utf-8", errors="replace"))
+        except OSError as e:
+            logger.debug(f"Channel pass: cannot read {file_path}: {e}")
+            continue
+        if not any(marker in text for marker in markers):
+            continue
+        offsets = [0] + [m.end() for m in re.finditer(r"\n", text)]
+        files.append(_GoFile(file_path, str(Path(file_path).parent), text, offsets, owners[file_path]))
+    return files
+
+
+def find_channel_links(call_graph: CallGraph, source_files: list[str]) -> list[ChannelLink]:
+    """Producer -> consumer links over channels shared by functions of *call_graph*."""
+    files = _load(call_graph, source_files)
+    package_vars: set[tuple[str, str]] = set()
+    fields: set[tuple[str, str]] = set()
+    locals_: set[tuple[str, str]] = set()
+    for go_file in files:
+        structs = [(m.end() - 1, _matching(go_file.text, m.end() - 1)) for m in _STRUCT_RE.finditer(go_file.text)]
+        for match in _CHAN_DECL_RE.finditer(go_file.text):
+            name = match.group(1) or match.group(2)
+            owner = go_file.owner_at(match.start())
+            if any(start < match.start() < end for start, end in structs):
+                fields.add((go_file.package, name))
+            elif owner is not None:
+                locals_.add((owner.fully_qualified_name, name))
+            else:
+                package_vars.add((go_file.package, name))
+
+    def key_for(go_file: _GoFile, owner: Node, operand: str) -> tuple:
+        if "." in operand:
+            return ("field", go_file.package, operand.rsplit(".", 1)[-1])
+        if (owner.fully_qualified_name, operand) not in locals_ and (go_file.package, operand) in package_vars:
+            return ("var", go_file.package, operand)
+        return ("local", owner.fully_qualified_name, operand)
+
+    def is_channel(key: tuple) -> bool:
+        return (key[1], key[2]) in (fields if key[0] == "field" else package_vars if key[0] == "var" else locals_)
+
+    classes = _ChannelClasses()
+    senders: dict[tuple, set[str]] = defaultdict(set)
+    receivers: dict[tuple, set[str]] = defaultdict(set)
+    functions: dict[tuple[str, str], list[tuple[str, list[str | None]]]] = defaultdict(list)
+    for go_file in files:
+        for owner in go_file.owners:
+            start, end = go_file.span(owner)
+            decl = _FUNC_DECL_RE.search(go_file.text, start, end)
+            if decl is not None:
+                params = go_file.text[decl.end() : _matching(go_file.text, decl.end() - 1)]
+                short = owner.fully_qualified_name.rsplit(call_graph.delimiter, 1)[-1]
+                functions[(go_file.package, short)].append((owner.fully_qualified_name, _chan_params(params)))
+
+    for go_file in files:
+        text = go_file.text
+        for arrow in re.finditer(r"<-", text):
+            owner = go_file.owner_at(arrow.start())
+            if owner is None:
+                continue
+            line_start = text.rfind("\n", 0, arrow.start()) + 1
+            lhs = _LHS_RE.search(text, line_start, arrow.start())
+            if lhs is not None and lhs.group(1) not in _NOT_OPERANDS:
+                senders[key_for(go_file, owner, lhs.group(1))].add(owner.fully_qualified_name)
+                continue
+            if lhs is not None and lhs.group(1) == "chan":
+                continue  # ``chan<- T``, a type
+            operand = _OPERAND_RE.match(text, arrow.end())
+            if operand is not None and operand.group(1) != "chan" and not operand.group(2):
+                receivers[key_for(go_file, owner, operand.group(1))].add(owner.fully_qualified_name)
+        for ranged in _RANGE_RE.finditer(text):
+            owner = go_file.owner_at(ranged.start())
+            if owner is not None and is_channel(key := key_for(go_file, owner, ranged.group(1))):
+                receivers[key].add(owner.fully_qualified_name)
+
+        for owner in go_file.owners:
+            start, end = go_file.span(owner)
+            # ``go func(out chan<- int) { ... }(results)``: the literal's parameter is the argument.
+            for literal in FUNC_LITERAL_RE.finditer(text, start, end):
+                params_end = _matching(text, literal.end() - 1)
+                if re.match(rf"\s*{_IDENT}\s*\(", text[params_end + 1 : params_end + 200]):
+                    continue  # a method declaration's receiver
+                body = text.find("{", params_end)
+                if body == -1 or body >= end:
+                    continue
+                after = _matching(text, body) + 1
+                call = re.match(r"\s*\(", text[after:end])
+                if call is None:
+                    continue
+                args_open = after + call.end() - 1
+                args = _split_args(text[args_open + 1 : _matching(text, args_open)])
+                for arg, param in zip(args, _chan_params(text[literal.end() : params_end])):
+                    if param is not None and re.fullmatch(_OPERAND, arg):
+                        inner = go_file.owner_at(literal.start()) or owner
+                        classes.union(key_for(go_file, owner, arg), key_for(go_file, inner, param))
+            for call in _CALL_RE.finditer(text, start, end):
+                short = call.group(1).rsplit(".", 1)[-1]
+                candidates = functions.get((go_file.package, short), [])
+                if short in _NOT_CALLS or len(candidates) != 1 or go_file.owner_at(call.start()) is not owner:
+                    continue
+                callee, params = candidates[0]
+                if callee == owner.fully_qualified_name:
+                    continue
+                args = _split_args(text[call.end() : _matching(text, call.end() - 1)])
+                for arg, param in zip(args, params):
+                    if param is not None and re.fullmatch(_OPERAND, arg):
+                        classes.union(key_for(go_file, owner, arg), ("local", callee, param))
+
+    members: dict[tuple, list[tuple]] = defaultdict(list)
+    for key in set(senders) | set(receivers):
+        members[classes.find(key)].append(key)
+    links: dict[tuple[str, str], ChannelLink] = {}
+    for root, keys in members.items():
+        confidence = classes.confidence(root, keys)
+        channel = sorted(k[2] for k in keys)[0]
+        producers = set().union(*(senders.get(k, set()) for k in keys))
+        consumers = set().union(*(receivers.get(k, set()) for k in keys))
+        for producer in producers:
+            for consumer in consumers - {producer}:
+                existing = links.get((producer, consumer))
+                if existing is None or CONFIDENCE_RANK[confidence] > CONFIDENCE_RANK[existing.confidence]:
+                    links[(producer, consumer)] = ChannelLink(producer, consumer, channel, confidence)
+    return sorted(links.values(), key=lambda link: (link.producer, link.consumer))
+
+
+def add_channel_edges(call_graph: CallGraph, source_files: list[str]) -> list[ChannelLink]:
+    """Add ``sends-to``/``receives-from`` reference edges for every channel link; returns the links."""
+    links = find_channel_links(call_graph, source_files)
+    existing = set(call_graph.reference_edges)
+    for link in links:
+        for src, dst, kind in (
+            (link.producer, link.consumer, EdgeKind.SENDS_TO),
+            (link.consumer, link.producer, EdgeKind.RECEIVES_FROM),
+        ):
+            if (src, dst, str(kind)) not in existing:
+                call_graph.add_reference_edge(src, dst, kind, confidence=link.confidence)
+    if links:
+        logger.info(f"Go channel pass linked {len(links)} producer/consumer pairs")
+    return links
+
+
+def find_spawns(call_graph: CallGraph, source_files: list[str]) -> list[tuple[str, str]]:
+    """(spawner, spawned) for every ``go f(...)`` statement whose function is in *call_graph*.
+
+    The callee is the call edge of the spawner that names the same function, else
+    the only callable of that name in the spawner's package.
+    """
+    by_package: dict[tuple[str, str], list[str]] = defaultdict(list)
+    for node in call_graph.nodes.values():
+        if node.is_callable():
+            short = node.fully_qualified_name.rsplit(".", 1)[-1]
+            by_package[(str(Path(node.file_path).parent), short)].append(node.fully_qualified_name)
+    callees: dict[str, set[str]] = defaultdict(set)
+    for edge in call_graph.edges:
+        callees[edge.get_source()].add(edge.get_destination())
+
+    spawns: set[tuple[str, str]] = set()
+    for go_file in _load(call_graph, source_files, markers=("go ", "go\t")):
+        for statement in _GO_STMT_RE.finditer(go_file.text):
+            owner = go_file.owner_at(statement.start())
+            short = statement.group(1).rsplit(".", 1)[-1]
+            if owner is None or short == "func":
+                continue
+            spawner = owner.fully_qualified_name
+            called = sorted(c for c in callees.get(spawner, ()) if c.rsplit(".", 1)[-1] == short)
+            candidates = called or by_package.get((go_file.package, short), [])
+            if len(candidates) == 1 and candidates[0] != spawner:
+                spawns.add((spawner, candidates[0]))
+    return sorted(spawns)
+
+
+def add_spawn_edges(call_graph: CallGraph, source_files: list[str]) -> list[tuple[str, str]]:
+    """Add a ``spawns`` reference edge for every goroutine started on a known function; returns the pairs."""
+    spawns = find_spawns(call_graph, source_files)
+    existing = set(call_graph.reference_edges)
+    for spawner, spawned in spawns:
+        if (spawner, spawned, str(EdgeKind.SPAWNS)) not in existing:
+            call_graph.add_reference_edge(spawner, spawned, EdgeKind.SPAWNS)
+    if spawns:
+        logger.info(f"Go channel pass linked {len(spawns)} goroutine spawns")
+    return spawns
@@ -0,0 +1,179 @@
+"""Go struct and interface embedding, added on top of the LSP call graph.
+
+``type Task struct { Entity }`` gives ``Task`` every method of ``Entity``:
+``task.GetType()`` runs ``Entity.GetType``. gopls reports the embedded type as
+a field named after it and nothing links the two types, so this pass reads the
+field's declaration and adds an ``embeds`` reference edge from the outer type
+to the embedded one. Embedded interfaces (``type ReadCloser interface {
+Reader; Closer }``, or an interface embedded in a struct) are linked the same
+way.
+
+:class:`Promotions` follows those edges to resolve a promoted method named on
+the outer type, ``models.task.(Task).GetType``, to the method that defines it,
+``models.base.(Entity).GetType``. A method of the outer type itself shadows
+promoted ones, and, as in Go, a name promoted from two types at the same depth
+is ambiguous and resolves to nothing.
+"""
+
+import logging
+import re
+from collections import defaultdict
+from collections.abc import Iterable
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+_IDENT = r"[A-Za-z_]\w*"
+# ``pkg.file.(Task).Entity``: a member of ``Task`` as the Go adapter names it.
+_MEMBER_RE = re.compile(rf"^(.*)\.\(\*?({_IDENT})\)\.(?:{_IDENT}\.)*({_IDENT})$")
+# ``Entity`` / ``*Entity`` / ``models.Entity``: a declaration that is only a type, i.e. an embedding.
+_EMBEDDED_RE = re.compile(rf"^\*?(?:{_IDENT}\.)?({_IDENT})$")
+# How many embedding levels a promoted method is looked up through.
+_MAX_DEPTH = 8
+
+
+def _package(node: Node) -> str:
+    return str(Path(node.file_path).parent)
+
+
+def _declaration(line: str) -> str:
+    """The declaration on *line* without its struct tag or trailing comment."""
+    return line.split("`", 1)[0].split("//", 1)[0].strip().rstrip(";")
+
+
+class _Sources:
+    """Lazily read source lines, by path."""
+
+    def __init__(self) -> None:
+        self._lines: dict[str, list[str]] = {}
+
+    def line(self, file_path: str, line_number: int) -> str:
+        if file_path not in self._lines:
+            try:
+                self._lines[file_path] = Path(file_path).read_text(encoding="utf-8", errors="replace").splitlines()
+            except OSError as e:
+                logger.debug(f"Go embedding: cannot read {file_path}: {e}")
+                self._lines[file_path] = []
+        lines = self._lines[file_path]
+        return lines[line_number - 1] if 0 < line_number <= len(lines) else ""
+
+
+class _Types:
+    """The graph's types, by (package dir, name) and by name."""
+
+    def __init__(self, call_graph: CallGraph) -> None:
+        self.by_package: dict[tuple[str, str], str] = {}
+        self.by_name: dict[str, list[str]] = defaultdict(list)
+        for qname, node in call_graph.nodes.items():
+            if node.type not in CLASS_TYPES or _MEMBER_RE.match(qname):
+                continue
+            name = qname.rsplit(".", 1)[-1]
+            self.by_package.setdefault((_package(node), name), qname)
+            self.by_name[name].append(qname)
+
+    def resolve(self, name: str, package: str) -> str | None:
+        """The type *name* refers to from *package*: the package's own, else the only one of that name."""
+        qname = self.by_package.get((package, name))
+        if qname is not None:
+            return qname
+        candidates = self.by_name.get(name, [])
+        return candidates[0] if len(candidates) == 1 else None
+
+
+def find_embeddings(call_graph: CallGraph, members: Iterable[Node]) -> list[tuple[str, str]]:
+    """(outer type, embedded type) for every member of *members* whose declaration is only a type name."""
+    types = _Types(call_graph)
+    sources = _Sources()
+    embeddings: set[tuple[str, str]] = set()
+    for node in members:
+        match = _MEMBER_RE.match(node.fully_qualified_name)
+        if match is None or node.type in CALLABLE_TYPES or not node.file_path.endswith(".go"):
+            continue
+        embedded = _EMBEDDED_RE.match(_declaration(sources.line(node.file_path, node.line_start)))
+        if embedded is None or embedded.group(1) != match.group(3):
+            continue
+        outer = types.resolve(match.group(2), _package(node))
+        base = types.resolve(embedded.group(1), _package(node))
+        if outer is not None and base is not None and outer != base:
+            embeddings.add((outer, base))
+    return sorted(embeddings)
+
+
+def add_embedding_edges(call_graph: CallGraph, members: Iterable[Node]) -> list[tuple[str, str]]:
+    """Add an ``embeds`` reference edge from each outer type to each type it embeds; returns the pairs."""
+    embeddings = find_embeddings(call_graph, members)
+    existing = set(call_graph.reference_edges)
+    for outer, base in embeddings:
+        if (outer, base, str(EdgeKind.EMBEDS)) not in existing:
+            call_graph.add_reference_edge(outer, base, EdgeKind.EMBEDS)
+    if embeddings:
+        logger.info(f"Go embedding pass linked {len(embeddings)} embedded types")
+    return embeddings
+
+
+class Promotions:
+    """Resolve methods promoted through the ``embeds`` edges of *call_graph*."""
+
+    def __init__(self, call_graph: CallGraph) -> None:
+        self._call_graph = call_graph
+        self._types = _Types(call_graph)
+        self._embeds: dict[str, list[str]] = defaultdict(list)
+        for src, dst, kind in call_graph.reference_edges:
+            if kind == EdgeKind.EMBEDS:
+                self._embeds[src].append(dst)
+        # (package dir, receiver type, method name) -> method qualified name.
+        self._methods: dict[tuple[str, str, str], str] = {}
+        for qname, node in call_graph.nodes.items():
+            match = _MEMBER_RE.match(qname)
+            if match is not None and node.type in CALLABLE_TYPES:
+                self._methods.setdefault((_package(node), match.group(2), match.group(3)), qname)
+
+    def __bool__(self) -> bool:
+        return bool(self._embeds)
+
+    def method(self, type_qname: str, method: str) -> str | None:
+        """The method *method* of the type *type_qname*, declared on it or promoted from an embedded type."""
+        level = [type_qname]
+        seen: set[str] = set()
+        for _ in range(_MAX_DEPTH):
+            found = set()
+            for qname in level:
+                node = self._call_graph.nodes.get(qname)
+                if node is not None:
+                    declared = self._methods.get((_package(node), qname.rsplit(".", 1)[-1], method))
+                    if declared is not None:
+                        found.add(declared)
+            if found:
+                return found.pop() if len(found) == 1 else None
+            seen.update(level)
+            level = [base for qname in level for base in self._embeds.get(qname, []) if base not in seen]
+            if not level:
+                return None
+        return None
+
+    def resolve(self, qualified_name: str) -> str | None:
+        """The definition of the promoted method *qualified_name* (``pkg.file.(Task).GetType``), if it is one.
+
+        Names of methods that exist in the graph are not promoted and give ``None``.
+        """
+        if qualified_name in self._call_graph.nodes:
+            return None
+        match = _MEMBER_RE.match(qualified_name)
+        if match is None:
+            # ``models.task.Task.GetType``: the type's own qualified name, then the method.
+            outer, _, method = qualified_name.rpartition(".")
+            node = self._call_graph.nodes.get(outer)
+            return self.method(outer, method) if node is not None and node.type in CLASS_TYPES else None
+        prefix, type_name, method = match.groups()
+        outer = f"{prefix}.{type_name}"
+        if outer not in self._call_graph.nodes:
+            candidates = self._types.by_name.get(type_name, [])
+            if len(candidates) != 1:
+                return None
+            outer = candidates[0]
+        target = self.method(outer, method)
+        return target if target != qualified_name else None
@@ -0,0 +1,143 @@
+"""Go enumerations: the typed constants declared for a named type.
+
+Go has no ``enum``; a domain enumeration is a named type plus a ``const``
+block of values of that type::
+
+    type Priority int
+
+    const (
+        PriorityLow Priority = iota
+        PriorityMedium
+        PriorityHigh
+    )
+
+gopls reports each constant as a loose symbol. This pass reads the ``const``
+declarations of a file and returns, for every constant whose type is named
+(``Name Type = ...`` or a conversion ``Name = Type(...)``), its type and value,
+so the constants can be grouped under their type. Inside a block ``iota``
+counts the specs and a spec without a value repeats the previous type and
+expression, as the compiler does; ``iota`` arithmetic (``1 << iota``,
+``iota + 1``) is evaluated, and any other value (``"pending"``, ``time.Second``)
+is kept as written. Untyped constants belong to no type and are left out.
+"""
+
+import ast
+import logging
+import operator
+import re
+from dataclasses import dataclass
+
+logger = logging.getLogger(__name__)
+
+_IDENT = r"[A-Za-z_]\w*"
+# String literals are matched so that a ``//`` inside one is not taken for a comment; only comments are blanked.
+_COMMENT_RE = re.compile(r"\"(?:\\.|[^\"\\\n])*\"|`[^`]*`|'(?:\\.|[^'\\\n])*'|//[^\n]*|/\*.*?\*/", re.DOTALL)
+# ``const (`` opening a block, which gofmt closes with a ``)`` at the start of a line.
+_BLOCK_RE = re.compile(r"^const\s*\(", re.MULTILINE)
+_BLOCK_END_RE = re.compile(r"^\)", re.MULTILINE)
+# ``const Name Type = expr``: a single declaration.
+_SINGLE_RE = re.compile(rf"^const\s+({_IDENT}\s.*)$", re.MULTILINE)
+# ``Name``, ``Name = expr``, ``Name Type = expr``: one spec of a declaration.
+_SPEC_RE = re.compile(rf"^({_IDENT})(?:\s+(\*?(?:{_IDENT}\.)?{_IDENT}))?\s*(?:=\s*(.+))?$")
+# ``Priority(iota)``: a conversion that gives an otherwise untyped value its type.
+_CONVERSION_RE = re.compile(rf"^((?:{_IDENT}\.)?{_IDENT})\((.*)\)$")
+_OPERATORS = {
+    ast.Add: operator.add,
+    ast.Sub: operator.sub,
+    ast.Mult: operator.mul,
+    ast.FloorDiv: operator.floordiv,
+    ast.Mod: operator.mod,
+    ast.LShift: operator.lshift,
+    ast.RShift: operator.rshift,
+    ast.BitOr: operator.or_,
+    ast.BitAnd: operator.and_,
+    ast.BitXor: operator.xor,
+}
+
+
+@dataclass(frozen=True)
+class EnumConstant:
+    """A constant of a named type: ``PriorityHigh`` of ``Priority``, valued ``2``."""
+
+    type_name: str
+    name: str
+    value: str
+
+
+def _strip_comments(text: str) -> str:
+    """Blank out comments, keeping string literals, offsets and line breaks."""
+    return _COMMENT_RE.sub(lambda m: m.group(0) if m.group(0)[0] in "\"`'" else re.sub(r"[^\n]", " ", m.group(0)), text)
+
+
+def _evaluate(node: ast.AST) -> int:
+    if isinstance(node, ast.Expression):
+        return _evaluate(node.body)
+    if isinstance(node, ast.Constant) and type(node.value) is int:
+        return node.value
+    if isinstance(node, ast.UnaryOp) and isinstance(node.op, ast.USub):
+        return -_evaluate(node.operand)
+    if isinstance(node, ast.BinOp) and type(node.op) in _OPERATORS:
+        return _OPERATORS[type(node.op)](_evaluate(node.left), _evaluate(node.right))
+    raise ValueError(f"not an integer expression: {ast.dump(node)}")
+
+
+def constant_value(expression: str, iota: int) -> str:
+    """*expression* with ``iota`` substituted, evaluated when it is integer arithmetic, else as written."""
+    if not re.search(r"\biota\b", expression):
+        return expression
+    # Why: Go's ``/`` on integer constants truncates; Python's would give a float.
+    substituted = re.sub(r"\biota\b", str(iota), expression).replace("&^", "& ~").replace("/", "//")
+    try:
+        return str(_evaluate(ast.parse(substituted, mode="eval")))
+    except (SyntaxError, ValueError, ZeroDivisionError, TypeError):
+        return expression
+
+
+def _typed(declared_type: str | None, expression: str) -> tuple[str | None, str]:
+    """The type of a spec and its value expression, unwrapping a conversion: ``Priority(iota)``."""
+    if declared_type is not None:
+        return declared_type.lstrip("*").rsplit(".", 1)[-1], expression
+    conversion = _CONVERSION_RE.match(expression)
+    if conversion is not None:
+        return conversion.group(1).rsplit(".", 1)[-1], conversion.group(2).strip()
+    return None, expression
+
+
+def _specs(lines: list[str]) -> list[EnumConstant]:
+    """The typed constants of one declaration, one spec per line, counting ``iota`` along."""
+    constants: list[EnumConstant] = []
+    previous: tuple[str | None, str] | None = None
+    iota = 0
+    for line in lines:
+        spec = line.strip()
+        if not spec:
+            continue
+        match = _SPEC_RE.match(spec)
+        if match is None:
+            # ``A, B = 1, 2`` or a value wrapped over lines: not an enumeration member, but a spec all the same.
+            previous = None
+            iota += 1
+            continue
+        name, declared_type, expression = match.groups()
+        if expression is not None:
+            previous = _typed(declared_type, expression.strip())
+        type_name, value = previous if previous is not None else (None, "")
+        if type_name is not None and name != "_":
+            constants.append(EnumConstant(type_name, name, constant_value(value, iota)))
+        iota += 1
+    return constants
+
+
+def enum_constants(source: str) -> list[EnumConstant]:
+    """The constants of named types declared in a Go file's *source*, in source order."""
+    text = _strip_comments(source)
+    found: list[tuple[int, list[EnumConstant]]] = []
+    for block in _BLOCK_RE.finditer(text):
+        end = _BLOCK_END_RE.search(text, block.end())
+        if end is None:
+            logger.debug("Go enums: unterminated const block")
+            continue
+        found.append((block.start(), _specs(text[block.end() : end.start()].splitlines())))
+    for single in _SINGLE_RE.finditer(text):
+        found.append((single.start(), _specs([single.group(1)])))
+    return [constant for _, constants in sorted(found, key=lambda item: item[0]) for constant in constants]
@@ -0,0 +1,206 @@
+"""Go type-parameter constraints, added on top of the LSP call graph.
+
+``func Sum[T Number](xs []T) T`` only accepts type arguments that satisfy
+``Number``, a relationship no call or reference records. This pass reads the
+type-parameter lists of generic functions and types and adds:
+
+* a ``typeref`` edge from the generic declaration to each constraint interface
+  of the project (``Sum`` -> ``Number``);
+* an ``implements`` edge from each project type given as an explicit type
+  argument to the constraint it must satisfy: ``Sum[Score](scores)`` or
+  ``Stack[Score]{}`` with ``Stack[T Number]`` gives ``Score`` -> ``Number``.
+
+Type arguments the compiler infers (``Sum(scores)``) are not written in the
+source and are not seen. Built-in constraints (``any``, ``comparable``) and
+type sets of built-in types (``~int | ~float64``) name no project type and add
+nothing.
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from pathlib import Path
+
+from static_analyzer.constants import CLASS_TYPES, NodeType
+from static_analyzer.go_source import clean
+from static_analyzer.graph import CallGraph, EdgeKind
+
+logger = logging.getLogger(__name__)
+
+_IDENT = r"[A-Za-z_]\w*"
+# ``func Map[`` / ``type Stack[`` / ``Stack[`` (in a ``type ( ... )`` block): a generic declaration.
+_DECLARATION_RE = re.compile(rf"^\s*(?:func\s+|type\s+)?({_IDENT})\s*\[")
+# ``Sum[`` / ``util.Sum[``: a possible instantiation; the name is checked against the declarations.
+_INSTANTIATION_RE = re.compile(rf"(?<![\w.])(?:{_IDENT}\.)?({_IDENT})\s*\[")
+_KEYWORD_BEFORE_RE = re.compile(r"\b(?:func|type)\s*$")
+# ``Score`` / ``*Score`` / ``models.Score``: a type argument that names one type.
+_TYPE_ARGUMENT_RE = re.compile(rf"^\*?(?:{_IDENT}\.)?({_IDENT})$")
+
+
+@dataclass(frozen=True)
+class GenericDeclaration:
+    qualified_name: str
+    # Constraint interfaces of the project, one tuple per type parameter, in order.
+    constraints: tuple[tuple[str, ...], ...]
+
+
+def _bracketed(text: str, open_idx: int) -> str | None:
+    """The text between ``text[open_idx]`` (``[``) and its matching ``]``."""
+    depth = 0
+    for index in range(open_idx, len(text)):
+        if text[index] in "[({":
+            depth += 1
+        elif text[index] in "])}":
+            depth -= 1
+            if depth == 0:
+                return text[open_idx + 1 : index]
+    return None
+
+
+def _split(text: str) -> list[str]:
+    """*text* split at the commas outside brackets and parentheses."""
+    parts, depth, start = [], 0, 0
+    for index, char in enumerate(text):
+        if char in "[({":
+            depth += 1
+        elif char in "])}":
+            depth -= 1
+        elif char == "," and depth == 0:
+            parts.append(text[start:index].strip())
+            start = index + 1
+    parts.append(text[start:].strip())
+    return [part for part in parts if part]
+
+
+def type_parameters(parameter_list: str) -> list[str]:
+    """The constraint of each type parameter of ``K comparable, V Number`` / ``T, U any``, in order."""
+    constraints: list[str] = []
+    pending = 0
+    for entry in _split(parameter_list):
+        name, _, constraint = entry.partition(" ")
+        if not constraint.strip():
+            pending += 1
+            continue
+        constraints.extend([constraint.strip()] * (pending + 1))
+        pending = 0
+    return constraints + ["any"] * pending
+
+
+class _Types:
+    """The graph's types, by (package dir, name) and by name."""
+
+    def __init__(self, call_graph: CallGraph) -> None:
+        self.nodes = call_graph.nodes
+        self.by_package: dict[tuple[str, str], str] = {}
+        self.by_name: dict[str, list[str]] = {}
+        for qname, node in call_graph.nodes.items():
+            if node.type in CLASS_TYPES and ".(" not in qname:
+                name = qname.rsplit(".", 1)[-1]
+                self.by_package.setdefault((str(Path(node.file_path).parent), name), qname)
+                self.by_name.setdefault(name, []).append(qname)
+
+    def resolve(self, name: str, package: str) -> str | None:
+        qname = self.by_package.get((package, name))
+        if qname is not None:
+            return qname
+        candidates = self.by_name.get(name, [])
+        return candidates[0] if len(candidates) == 1 else None
+
+    def interfaces_in(self, constraint: str, package: str) -> tuple[str, ...]:
+        """The project interfaces a constraint expression (``Number``, ``~int | fmt.Stringer``) names."""
+        resolved = (self.resolve(name, package) for name in re.findall(rf"(?:{_IDENT}\.)?({_IDENT})", constraint))
+        return tuple(dict.fromkeys(q for q in resolved if q is not None and self.nodes[q].type == NodeType.INTERFACE))
+
+
+class _Sources:
+    """Lazily read and clean Go sources, by path."""
+
+    def __init__(self) -> None:
+        self._text: dict[str, str] = {}
+        self._lines: dict[str, list[str]] = {}
+
+    def text(self, file_path: str) -> str:
+        if file_path not in self._text:
+            try:
+                self._text[file_path] = clean(Path(file_path).read_text(encoding="utf-8", errors="replace"))
+            except OSError as e:
+                logger.debug(f"Go generics: cannot read {file_path}: {e}")
+                self._text[file_path] = ""
+        return self._text[file_path]
+
+    def from_line(self, file_path: str, line_number: int, count: int = 8) -> str:
+        """*count* lines from *line_number* on: enough for a declaration's type parameters."""
+        if file_path not in self._lines:
+            self._lines[file_path] = self.text(file_path).split("\n")
+        lines = self._lines[file_path]
+        return "\n".join(lines[line_number - 1 : line_number - 1 + count]) if 0 < line_number <= len(lines) else ""
+
+
+def find_generic_declarations(call_graph: CallGraph, sources: _Sources | None = None) -> list[GenericDeclaration]:
+    """Generic functions and types of *call_graph* with the project interfaces that constrain them."""
+    sources = sources or _Sources()
+    types = _Types(call_graph)
+    declarations = []
+    for qname, node in call_graph.nodes.items():
+        if ".(" in qname or not node.file_path.endswith(".go"):
+            continue
+        if node.type not in CLASS_TYPES and node.type != NodeType.FUNCTION:
+            continue
+        text = sources.from_line(node.file_path, node.line_start)
+        match = _DECLARATION_RE.match(text)
+        if match is None or match.group(1) != qname.rsplit(".", 1)[-1]:
+            continue
+        parameter_list = _bracketed(text, match.end() - 1)
+        if parameter_list is None:
+            continue
+        package = str(Path(node.file_path).parent)
+        constraints = tuple(types.interfaces_in(c, package) for c in type_parameters(parameter_list))
+        declarations.append(GenericDeclaration(qname, constraints))
+    return declarations
+
+
+def add_constraint_edges(call_graph: CallGraph, source_files: list[str]) -> list[tuple[str, str, str]]:
+    """Add the constraint ``typeref`` and type-argument ``implements`` edges; returns the new edges."""
+    sources = _Sources()
+    declarations = [d for d in find_generic_declarations(call_graph, sources) if any(d.constraints)]
+    if not declarations:
+        return []
+    types = _Types(call_graph)
+    by_name: dict[str, list[GenericDeclaration]] = {}
+    for declaration in declarations:
+        by_name.setdefault(declaration.qualified_name.rsplit(".", 1)[-1], []).append(declaration)
+    by_qname = {declaration.qualified_name: declaration for declaration in declarations}
+
+    edges: set[tuple[str, str, str]] = set()
+    for declaration in declarations:
+        for constraint in {c for constraints in declaration.constraints for c in constraints}:
+            edges.add((declaration.qualified_name, constraint, str(EdgeKind.TYPEREF)))
+    for file_path in source_files:
+        if not file_path.endswith(".go"):
+            continue
+        text = sources.text(file_path)
+        package = str(Path(file_path).parent)
+        for match in _INSTANTIATION_RE.finditer(text):
+            name = match.group(1)
+            if name not in by_name or _KEYWORD_BEFORE_RE.search(text[max(0, match.start() - 8) : match.start()]):
+                continue
+            declaration = by_qname.get(types.resolve(name, package) or "")
+            if declaration is None and len(by_name[name]) == 1:
+                declaration = by_name[name][0]
+            arguments = _bracketed(text, match.end() - 1)
+            if declaration is None or arguments is None:
+                continue
+            for argument, constraints in zip(_split(arguments), declaration.constraints):
+                named = _TYPE_ARGUMENT_RE.match(argument)
+                target = types.resolve(named.group(1), package) if named is not None else None
+                for constraint in constraints:
+                    if target is not None and target != constraint:
+                        edges.add((target, constraint, str(EdgeKind.IMPLEMENTS)))
+
+    existing = set(call_graph.reference_edges)
+    added = sorted(edge for edge in edges if edge not in existing)
+    for src, dst, kind in added:
+        call_graph.add_reference_edge(src, dst, EdgeKind(kind))
+    if added:
+        logger.info(f"Go generics pass added {len(added)} type-parameter constraint edges")
+    return added
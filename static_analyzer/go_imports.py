@@ -0,0 +1,263 @@
+"""Go import tables: the package each qualifier of a file stands for.
+
+A file names another package through the qualifier its import binds, which is
+not always the package's own name:
+
+* ``import u "example.com/edgecases/utils"``: ``u.Add`` is ``utils.Add``;
+* ``import . "example.com/edgecases/utils"`` (a dot import): plain ``Add`` is
+  ``utils.Add``;
+* ``import _ "example.com/edgecases/drivers"`` (a blank import): the package
+  only runs its ``init``; no name refers to it.
+
+An :class:`ImportTable` keeps the imports of one file with, for packages of the
+file's own module, the package directory (the canonical path in the
+repository) and the name its ``package`` clause declares, which is the
+qualifier of an import that does not rename it.
+
+gopls records a call through a dot import as a plain local call, and nothing
+for a blank import, so :func:`add_import_edges` adds both to the Go call
+graph: a call edge for each ``Add(...)`` a function makes into a dot-imported
+package, and an ``import`` reference edge (never a call) from the importing
+file's entry points to each ``init`` of a blank-imported package.
+"""
+
+import logging
+import re
+from collections.abc import Iterable
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, NodeType
+from static_analyzer.go_source import clean, go_module_path, package_clause
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+# ``init``, ``init#2``: the Go adapter keeps each ``init`` of a file a node of its own.
+_INIT_RE = re.compile(r"^init(?:#\d+)?$")
+# ``import "x"`` / ``import alias "x"`` and the parenthesised block form; the alias may be ``.`` or ``_``.
+_SINGLE_IMPORT_RE = re.compile(r'^\s*import\s+(?:([\w.]+)\s+)?"([^"]+)"', re.MULTILINE)
+_IMPORT_BLOCK_RE = re.compile(r"^\s*import\s*\((.*?)\)", re.MULTILINE | re.DOTALL)
+_BLOCK_ENTRY_RE = re.compile(r'^\s*(?:([\w.]+)\s+)?"([^"]+)"', re.MULTILINE)
+
+DOT_IMPORT = "."
+BLANK_IMPORT = "_"
+
+
+@dataclass(frozen=True)
+class GoImport:
+    path: str
+    # ``u`` in ``import u "..."``, ``.`` for a dot import, ``_`` for a blank one; None when not renamed.
+    alias: str | None = None
+
+
+def parse_imports(source: str) -> list[GoImport]:
+    """The imports of a Go file, in both the single and the parenthesised form."""
+    source = clean(source, keep_strings=True)
+    found = [GoImport(path, alias or None) for alias, path in _SINGLE_IMPORT_RE.findall(source)]
+    for block in _IMPORT_BLOCK_RE.findall(source):
+        found.extend(GoImport(path, alias or None) for alias, path in _BLOCK_ENTRY_RE.findall(block))
+    return found
+
+
+@dataclass
+class ImportTable:
+    """The imports of one Go file."""
+
+    imports: list[GoImport] = field(default_factory=list)
+    # Import path -> package directory, for the packages of the file's own module.
+    directories: dict[str, Path] = field(default_factory=dict)
+    # Import path -> the name in the package's ``package`` clause, where it was read.
+    package_names: dict[str, str] = field(default_factory=dict)
+    # Path of the module the file belongs to (its nearest ``go.mod``); None outside one.
+    module: str | None = None
+
+    def qualifier(self, go_import: GoImport) -> str:
+        """The name *go_import* binds in the file: its alias, else the package's name."""
+        if go_import.alias is not None:
+            return go_import.alias
+        return self.package_names.get(go_import.path) or go_import.path.rsplit("/", 1)[-1]
+
+    def import_for(self, qualifier: str) -> GoImport | None:
+        """The import that binds *qualifier*; never a dot or blank import."""
+        if qualifier in (DOT_IMPORT, BLANK_IMPORT):
+            return None
+        return next((imp for imp in self.imports if self.qualifier(imp) == qualifier), None)
+
+    def package_dir(self, qualifier: str) -> Path | None:
+        """The directory of the module package *qualifier* refers to; None for other modules and the stdlib."""
+        go_import = self.import_for(qualifier)
+        return self.directories.get(go_import.path) if go_import is not None else None
+
+    def dot_imported_dirs(self) -> list[Path]:
+        """Directories of the module packages whose names the file uses unqualified."""
+        return [
+            self.directories[imp.path]
+            for imp in self.imports
+            if imp.alias == DOT_IMPORT and imp.path in self.directories
+        ]
+
+
+class GoImportIndex:
+    """Import tables of Go files, read lazily and cached by path."""
+
+    def __init__(self) -> None:
+        self._tables: dict[Path, ImportTable] = {}
+        self._modules: dict[Path, tuple[Path, str] | None] = {}
+        self._package_names: dict[Path, str | None] = {}
+
+    def table(self, file_path: str | Path) -> ImportTable:
+        path = Path(file_path)
+        if path not in self._tables:
+            try:
+                source = path.read_text(encoding="utf-8", errors="replace")
+            except OSError as e:
+                logger.debug(f"Go imports: cannot read {path}: {e}")
+                source = ""
+            module = self._module(path.parent)
+            table = ImportTable(parse_imports(source), module=module[1] if module is not None else None)
+            for go_import in table.imports:
+                directory = _module_package_dir(module, go_import.path)
+                if directory is None:
+                    continue
+                table.directories[go_import.path] = directory
+                if (name := self._package_name(directory)) is not None:
+                    table.package_names[go_import.path] = name
+            self._tables[path] = table
+        return self._tables[path]
+
+    def _module(self, directory: Path) -> tuple[Path, str] | None:
+        """The root and path of the module *directory* belongs to: its nearest ``go.mod``."""
+        if directory not in self._modules:
+            module = None
+            go_mod = directory / "go.mod"
+            if go_mod.is_file():
+                module_path = go_module_path(go_mod)
+                module = (directory, module_path) if module_path is not None else None
+            elif directory.parent != directory:
+                module = self._module(directory.parent)
+            self._modules[directory] = module
+        return self._modules[directory]
+
+    def _package_name(self, directory: Path) -> str | None:
+        if directory not in self._package_names:
+            name = None
+            for go_file in sorted(directory.glob("*.go")):
+                if go_file.name.endswith("_test.go"):
+                    continue
+                name = package_clause(go_file.read_text(encoding="utf-8", errors="replace"))
+                if name is not None:
+                    break
+            self._package_names[directory] = name
+        return self._package_names[directory]
+
+
+def _module_package_dir(module: tuple[Path, str] | None, import_path: str) -> Path | None:
+    if module is None:
+        return None
+    root, module_path = module
+    if import_path != module_path and not import_path.startswith(f"{module_path}/"):
+        return None
+    directory = root / import_path[len(module_path) :].lstrip("/")
+    return directory if directory.is_dir() else None
+
+
+def _short_name(qualified_name: str) -> str:
+    return qualified_name.rsplit(".", 1)[-1]
+
+
+def _is_function(node: Node) -> bool:
+    return node.type == NodeType.FUNCTION and ".(" not in node.fully_qualified_name
+
+
+def _is_init(node: Node) -> bool:
+    return _is_function(node) and _INIT_RE.match(_short_name(node.fully_qualified_name)) is not None
+
+
+def _entry_points(functions: list[Node]) -> list[Node]:
+    """The ``main`` and ``init`` functions of a file, or all its functions when it has neither."""
+    entries = [node for node in functions if _short_name(node.fully_qualified_name) == "main" or _is_init(node)]
+    return entries or functions
+
+
+def _dot_import_calls(caller: Node, body: str, exported: dict[str, Node]) -> list[tuple[Node, dict[str, str | int]]]:
+    """The calls *body* (cleaned source of *caller*) makes to the dot-imported *exported* functions."""
+    found = []
+    for name, callee in exported.items():
+        if re.search(rf"(?<![\w.]){re.escape(name)}\s*:=|\bvar\s+{re.escape(name)}\b", body):
+            continue
+        for match in re.finditer(rf"(?<![\w.]){re.escape(name)}\s*\(", body):
+            line_start = body.rfind("\n", 0, match.start()) + 1
+            site: dict[str, str | int] = {
+                "file": caller.file_path,
+                "line": caller.line_start + body.count("\n", 0, match.start()),
+                "column": match.start() - line_start + 1,
+            }
+            found.append((callee, site))
+    return found
+
+
+def add_import_edges(
+    call_graph: CallGraph, source_files: Iterable[str | Path]
+) -> tuple[list[tuple[str, str]], list[tuple[str, str]]]:
+    """Add Go calls through dot imports and ``import`` edges to blank-imported ``init``s; returns the new edges of both.
+
+    A dot-imported ``Add`` only counts where no local or function of the
+    caller's own package takes the name.
+    """
+    by_file: dict[Path, list[Node]] = {}
+    by_dir: dict[Path, list[Node]] = {}
+    for node in call_graph.nodes.values():
+        if node.file_path.endswith(".go") and node.type in CALLABLE_TYPES:
+            by_file.setdefault(Path(node.file_path), []).append(node)
+            by_dir.setdefault(Path(node.file_path).parent, []).append(node)
+    index = GoImportIndex()
+    existing = set(call_graph.reference_edges)
+    calls: list[tuple[str, str]] = []
+    imports: list[tuple[str, str]] = []
+    for file_path in sorted({Path(path) for path in source_files if str(path).endswith(".go")}):
+        callers = by_file.get(file_path, [])
+        table = index.table(file_path)
+        if not callers or not table.imports:
+            continue
+
+        package = by_dir.get(file_path.parent, [])
+        own = {_short_name(node.fully_qualified_name) for node in package if _is_function(node)}
+        exported = {
+            _short_name(node.fully_qualified_name): node
+            for directory in table.dot_imported_dirs()
+            for node in by_dir.get(directory, [])
+            if _is_function(node) and _short_name(node.fully_qualified_name)[:1].isupper()
+        }
+        exported = {name: node for name, node in exported.items() if name not in own}
+        if exported:
+            lines = clean(file_path.read_text(encoding="utf-8", errors="replace")).split("\n")
+            for caller in callers:
+                body = "\n".join(lines[caller.line_start - 1 : caller.line_end])
+                for callee, site in _dot_import_calls(caller, body, exported):
+                    before = len(call_graph.edges)
+                    call_graph.add_edge(caller.fully_qualified_name, callee.fully_qualified_name, [site])
+                    if len(call_graph.edges) > before:
+                        calls.append((caller.fully_qualified_name, callee.fully_qualified_name))
+
+        # A blank import runs the package's ``init``s when the importer's package loads: not a call.
+        inits = [
+            node
+            for imp in table.imports
+            if imp.alias == BLANK_IMPORT and imp.path in table.directories
+            for node in by_dir.get(table.directories[imp.path], [])
+            if _is_init(node)
+        ]
+        if not inits:
+            continue
+        for entry in _entry_points([node for node in callers if _is_function(node)]):
+            for init in inits:
+                edge = (entry.fully_qualified_name, init.fully_qualified_name, str(EdgeKind.IMPORT))
+                if edge not in existing:
+                    call_graph.add_reference_edge(edge[0], edge[1], EdgeKind.IMPORT)
+                    existing.add(edge)
+                    imports.append(edge[:2])
+    if calls or imports:
+        logger.info(f"Go imports: {len(calls)} calls through dot imports, {len(imports)} blank-import init edges")
+    return calls, imports
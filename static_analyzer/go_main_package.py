@@ -0,0 +1,75 @@
+"""Scope Go analysis to one binary (``--main-package ./cmd/server``).
+
+A Go module often builds several programs (``cmd/a``, ``cmd/b``). The files
+that make up one of them are the ``main`` package plus every package of the
+same module it imports, transitively. That import closure becomes the Go
+engine config's authoritative file list, so the call graph — and hence the
+architecture — covers that binary only instead of the union of all of them.
+
+Imports of other modules (stdlib, third-party) are not followed; ``_test.go``
+files are not part of a binary and are left out.
+"""
+
+import logging
+from collections import deque
+from pathlib import Path
+
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer.go_imports import parse_imports
+from static_analyzer.go_source import go_module_path, package_clause
+
+logger = logging.getLogger(__name__)
+
+
+class GoMainPackageError(ValueError):
+    pass
+
+
+def _go_files(package_dir: Path) -> list[Path]:
+    return sorted(p for p in package_dir.glob("*.go") if p.is_file() and not p.name.endswith("_test.go"))
+
+
+def _find_module(package_dir: Path, repo_path: Path) -> tuple[Path, str]:
+    """The nearest ``go.mod`` at or above *package_dir* (within the repo) and its module path."""
+    for directory in (package_dir, *package_dir.parents):
+        if module_path := go_module_path(directory / "go.mod"):
+            return directory, module_path
+        if directory == repo_path:
+            break
+    raise GoMainPackageError(f"No go.mod found above {package_dir}")
+
+
+def reachable_go_files(
+    repo_path: Path, main_package: str, ignore_manager: RepoIgnoreManager | None = None
+) -> list[Path]:
+    """Files of *main_package* (repo-relative, e.g. ``./cmd/server``) and every module package it imports."""
+    repo_path = repo_path.resolve()
+    main_dir = (repo_path / main_package).resolve()
+    if not main_dir.is_relative_to(repo_path) or not main_dir.is_dir():
+        raise GoMainPackageError(f"--main-package {main_package} is not a directory inside {repo_path}")
+    main_files = _go_files(main_dir)
+    if not any(package_clause(f.read_text(encoding="utf-8", errors="replace")) == "main" for f in main_files):
+        raise GoMainPackageError(f"--main-package {main_package} does not contain a Go 'package main'")
+
+    module_root, module_path = _find_module(main_dir, repo_path)
+    files: list[Path] = []
+    seen = {main_dir}
+    queue = deque([main_dir])
+    while queue:
+        package_dir = queue.popleft()
+        for go_file in _go_files(package_dir):
+            if ignore_manager is not None and ignore_manager.should_ignore(go_file):
+                continue
+            files.append(go_file)
+            # Aliased, dot and blank imports are all part of the binary.
+            for go_import in parse_imports(go_file.read_text(encoding="utf-8", errors="replace")):
+                imported = go_import.path
+                if imported != module_path and not imported.startswith(f"{module_path}/"):
+                    continue
+                target = (module_root / imported[len(module_path) :].lstrip("/")).resolve()
+                if target not in seen and target.is_dir():
+                    seen.add(target)
+                    queue.append(target)
+
+    logger.info(f"--main-package {main_package}: {len(files)} Go files across {len(seen)} packages of {module_path}")
+    return files
@@ -0,0 +1,308 @@
+"""Go function signatures, read from the declarations of the call graph's callables.
+
+The LSP names a function but not what it takes and returns, so the docs
+described ``func Clamp(value, min, max int) (result int)`` as returning an
+anonymous ``int``. This pass reads the parameter and result lists of every Go
+function and method node and records them as ``Node.signature``, keeping
+named results (``result int``) apart from unnamed ones (``(string, int)``)
+and marking a variadic last parameter (``fns ...HandlerFunc``) as such.
+
+Named function types are types in their own right: ``type HandlerFunc
+func(int) int``, which gopls reports like a function, becomes a type node
+carrying its underlying signature; a package variable holding a function
+(``var DefaultHandler HandlerFunc = double``) gets the signature too, plus a
+``typeref`` edge to its function type; and a function returning one
+(``CreateMultiplier(n int) HandlerFunc``) gets a ``returns`` edge to it, so
+the higher-order structure of a package shows in the graph.
+
+Only the declaration itself is parsed: comments and literals are blanked
+first, a type-parameter list is skipped, and the results end at the body's
+``{`` (or the line, for a body-less declaration).
+"""
+
+import logging
+import re
+from collections.abc import Iterable
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES, NodeType
+from static_analyzer.go_source import clean
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node, Parameter, Signature
+
+logger = logging.getLogger(__name__)
+
+_IDENT = r"[A-Za-z_]\w*"
+# Words that start a type, not a result name: ``chan int``, ``func() error``, ``map[string]int``.
+_TYPE_KEYWORDS = {"chan", "func", "map", "struct", "interface"}
+# ``name T`` / ``fns ...T``: a typed entry of a named parameter or result list.
+_NAMED_ENTRY_RE = re.compile(rf"^({_IDENT})\s+(.+)$", re.DOTALL)
+# ``HandlerFunc`` / ``*HandlerFunc`` / ``utils.HandlerFunc``: a result or variable type that names one type.
+_TYPE_NAME_RE = re.compile(rf"^\*?(?:{_IDENT}\.)?({_IDENT})$")
+# ``HandlerFunc = double`` / ``utils.HandlerFunc``: the declared type of a package variable.
+_VALUE_TYPE_RE = re.compile(rf"(\*?(?:{_IDENT}\.)?{_IDENT})\b")
+# Lines read from the declaration: enough for a parameter list wrapped by gofmt.
+_DECLARATION_LINES = 12
+
+
+def _closing(text: str, open_idx: int) -> int | None:
+    """Index of the bracket closing ``text[open_idx]``."""
+    depth = 0
+    for index in range(open_idx, len(text)):
+        if text[index] in "[({":
+            depth += 1
+        elif text[index] in "])}":
+            depth -= 1
+            if depth == 0:
+                return index
+    return None
+
+
+def _split(text: str) -> list[str]:
+    """*text* split at the commas outside brackets and parentheses."""
+    parts, depth, start = [], 0, 0
+    for index, char in enumerate(text):
+        if char in "[({":
+            depth += 1
+        elif char in "])}":
+            depth -= 1
+        elif char == "," and depth == 0:
+            parts.append(text[start:index].strip())
+            start = index + 1
+    parts.append(text[start:].strip())
+    return [part for part in parts if part]
+
+
+def _squash(text: str) -> str:
+    return " ".join(text.split())
+
+
+def _parameter(parameter_type: str, name: str | None = None) -> Parameter:
+    if parameter_type.startswith("..."):
+        return Parameter(parameter_type[3:].strip(), name, is_variadic=True)
+    return Parameter(parameter_type, name)
+
+
+def parse_parameters(text: str) -> tuple[Parameter, ...]:
+    """The entries of a parameter list without its parentheses: ``value, min, max int`` / ``string, int``."""
+    entries = [_squash(entry) for entry in _split(text)]
+    named = [_NAMED_ENTRY_RE.match(entry) for entry in entries]
+    if not any(match is not None and match.group(1) not in _TYPE_KEYWORDS for match in named):
+        return tuple(_parameter(entry) for entry in entries)
+    # ``a, b int, fns ...F``: a bare name takes the type of the next typed entry.
+    parameters: list[Parameter] = []
+    pending: list[str] = []
+    for entry, match in zip(entries, named):
+        if match is None:
+            pending.append(entry)
+            continue
+        name, parameter_type = match.group(1), match.group(2)
+        parameters.extend(_parameter(parameter_type, other) for other in pending)
+        parameters.append(_parameter(parameter_type, name))
+        pending = []
+    return tuple(parameters)
+
+
+def parse_results(text: str) -> tuple[Parameter, ...]:
+    """The results declared by *text*, what follows a parameter list: ``int``, ``(string, int)``, ``(a, b int)``."""
+    text = text.strip()
+    if not text:
+        return ()
+    if not text.startswith("("):
+        return (Parameter(_squash(text)),)
+    return parse_parameters(text[1:-1])
+
+
+def _signature_at(text: str, index: int) -> Signature | None:
+    """The signature whose parameter list opens at ``text[index]``, optionally after a type-parameter list."""
+    if text[index : index + 1] == "[":
+        close = _closing(text, index)
+        if close is None:
+            return None
+        index = close + 1
+        while text[index : index + 1].isspace():
+            index += 1
+    if text[index : index + 1] != "(":
+        return None
+    close = _closing(text, index)
+    if close is None:
+        return None
+    parameters = parse_parameters(text[index + 1 : close])
+    rest = text[close + 1 :]
+    end = len(rest)
+    depth = 0
+    for position, char in enumerate(rest):
+        if char in "[(":
+            depth += 1
+        elif char in "])":
+            depth -= 1
+        elif depth == 0 and char in "{\n":
+            end = position
+            break
+    return Signature(parameters=parameters, results=parse_results(rest[:end]))
+
+
+def parse_signature(declaration: str, name: str) -> Signature | None:
+    """The signature of function or method *name* declared at the start of *declaration* (cleaned source)."""
+    match = re.match(rf"\s*func\s*(?:\([^()]*\)\s*)?{re.escape(name)}\s*", declaration)
+    return _signature_at(declaration, match.end()) if match is not None else None
+
+
+def parse_function_type(declaration: str, name: str) -> Signature | None:
+    """The underlying signature of ``type HandlerFunc func(int) int``, an alias or a line of a ``type`` block."""
+    match = re.match(rf"\s*(?:type\s+)?{re.escape(name)}\s*(?:=\s*)?func\s*", declaration)
+    return _signature_at(declaration, match.end()) if match is not None else None
+
+
+def parse_function_value(declaration: str, name: str) -> Signature | str | None:
+    """What package variable *name* holds: the signature of its ``func`` type or literal, or its declared type name."""
+    match = re.match(rf"\s*(?:var\s+)?{re.escape(name)}\b\s*", declaration)
+    if match is None:
+        return None
+    rest = declaration[match.end() :]
+    literal = re.match(r"(?:=\s*)?func\s*(?=\()", rest)
+    if literal is not None:
+        return _signature_at(rest, literal.end())
+    typed = _VALUE_TYPE_RE.match(rest)
+    return typed.group(1) if typed is not None else None
+
+
+class _Sources:
+    """Lazily read and clean Go sources, by path."""
+
+    def __init__(self) -> None:
+        self._lines: dict[str, list[str]] = {}
+
+    def declaration(self, node: Node) -> str:
+        """The lines from *node*'s own: enough for a declaration wrapped by gofmt."""
+        if node.file_path not in self._lines:
+            try:
+                text = Path(node.file_path).read_text(encoding="utf-8", errors="replace")
+            except OSError as e:
+                logger.debug(f"Go signatures: cannot read {node.file_path}: {e}")
+                text = ""
+            self._lines[node.file_path] = clean(text).split("\n")
+        start = node.line_start - 1
+        return "\n".join(self._lines[node.file_path][max(start, 0) : start + _DECLARATION_LINES])
+
+
+def _package(node: Node) -> str:
+    return str(Path(node.file_path).parent)
+
+
+def _short_name(qualified_name: str) -> str:
+    return qualified_name.rsplit(".", 1)[-1]
+
+
+class _FunctionTypes:
+    """The named function types found so far, by (package dir, name) and by name."""
+
+    def __init__(self) -> None:
+        self.by_package: dict[tuple[str, str], Node] = {}
+        self.by_name: dict[str, list[Node]] = {}
+        self.qualified_names: set[str] = set()
+
+    def add(self, node: Node) -> None:
+        self.qualified_names.add(node.fully_qualified_name)
+        name = _short_name(node.fully_qualified_name)
+        self.by_package.setdefault((_package(node), name), node)
+        self.by_name.setdefault(name, []).append(node)
+
+    def resolve(self, type_name: str, near: Node) -> Node | None:
+        """The function type *type_name* (``HandlerFunc``, ``*utils.HandlerFunc``) names from *near*'s package."""
+        match = _TYPE_NAME_RE.match(type_name)
+        if match is None:
+            return None
+        name = match.group(1)
+        node = self.by_package.get((_package(near), name))
+        if node is not None:
+            return node
+        candidates = self.by_name.get(name, [])
+        return candidates[0] if len(candidates) == 1 else None
+
+
+def _go_nodes(call_graph: CallGraph, reference_nodes: Iterable[Node]) -> list[Node]:
+    """The Go nodes of the graph and the references, the graph's where both hold one name; members excluded."""
+    nodes = dict.fromkeys(node for node in reference_nodes if node.file_path.endswith(".go"))
+    by_name = {node.fully_qualified_name: node for node in nodes}
+    for qname, node in call_graph.nodes.items():
+        if node.file_path.endswith(".go"):
+            by_name[qname] = node
+    return [node for qname, node in by_name.items() if ".(" not in qname]
+
+
+def mark_function_types(call_graph: CallGraph, reference_nodes: Iterable[Node] = ()) -> _FunctionTypes:
+    """Turn named function types into type nodes carrying their signature, and give func-valued variables theirs.
+
+    gopls reports ``type HandlerFunc func(int) int`` as a function (or a class),
+    and a ``var`` holding a function as a plain variable; neither had a signature.
+    """
+    sources = _Sources()
+    types = _FunctionTypes()
+    variables: list[Node] = []
+    reference_nodes = list(reference_nodes)
+    for node in _go_nodes(call_graph, reference_nodes):
+        if node.type == NodeType.VARIABLE:
+            variables.append(node)
+            continue
+        if node.type not in CLASS_TYPES and node.type not in CALLABLE_TYPES:
+            continue
+        signature = parse_function_type(sources.declaration(node), _short_name(node.fully_qualified_name))
+        if signature is None:
+            continue
+        node.type = NodeType.CLASS
+        node.signature = signature
+        types.add(node)
+    # The same symbol may be held by the graph and the references as two objects.
+    for reference in reference_nodes:
+        typed = call_graph.nodes.get(reference.fully_qualified_name)
+        if typed is not None and typed is not reference and typed.fully_qualified_name in types.qualified_names:
+            reference.type, reference.signature = typed.type, typed.signature
+
+    for node in variables:
+        value = parse_function_value(sources.declaration(node), _short_name(node.fully_qualified_name))
+        if isinstance(value, Signature):
+            node.signature = value
+        elif value is not None and (function_type := types.resolve(value, node)) is not None:
+            node.signature = function_type.signature
+            type_name = function_type.fully_qualified_name
+            call_graph.add_reference_edge(node.fully_qualified_name, type_name, EdgeKind.TYPEREF)
+    return types
+
+
+def add_returns_edges(call_graph: CallGraph, types: _FunctionTypes) -> list[tuple[str, str, str]]:
+    """Add a ``returns`` edge from each function to the named function types it returns; returns the new edges."""
+    existing = set(call_graph.reference_edges)
+    edges: list[tuple[str, str, str]] = []
+    for qname, node in call_graph.nodes.items():
+        if node.type not in CALLABLE_TYPES or node.signature is None:
+            continue
+        for result in node.signature.results:
+            function_type = types.resolve(result.type, node)
+            if function_type is None:
+                continue
+            edge = (qname, function_type.fully_qualified_name, str(EdgeKind.RETURNS))
+            if edge not in existing:
+                call_graph.add_reference_edge(qname, function_type.fully_qualified_name, EdgeKind.RETURNS)
+                existing.add(edge)
+                edges.append(edge)
+    return edges
+
+
+def add_go_signatures(call_graph: CallGraph, reference_nodes: Iterable[Node] = ()) -> int:
+    """Record ``Node.signature`` on the Go functions, methods and function types of *call_graph*.
+
+    Also adds the ``returns`` edges to function types; returns how many function and method signatures were read.
+    """
+    types = mark_function_types(call_graph, reference_nodes)
+    sources = _Sources()
+    count = 0
+    for qname, node in call_graph.nodes.items():
+        if node.type not in CALLABLE_TYPES or not node.file_path.endswith(".go"):
+            continue
+        signature = parse_signature(sources.declaration(node), _short_name(qname))
+        if signature is not None:
+            node.signature = signature
+            count += 1
+    add_returns_edges(call_graph, types)
+    return count
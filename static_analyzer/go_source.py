@@ -0,0 +1,40 @@
+"""Go source helpers shared by the regex-based Go passes."""
+
+import re
+from pathlib import Path
+
+# Comments and string/rune literals, blanked before matching so ``// go worker()`` is not a goroutine.
+_CLEAN_RE = re.compile(r"//[^\n]*|/\*.*?\*/|\"(?:\\.|[^\"\\\n])*\"|`[^`]*`|'(?:\\.|[^'\\\n])*'", re.DOTALL)
+_MODULE_RE = re.compile(r"^\s*module\s+(\S+)", re.MULTILINE)
+_PACKAGE_RE = re.compile(r"^\s*package\s+(\w+)", re.MULTILINE)
+# ``func(`` opening a function literal, or a method declaration's receiver list.
+FUNC_LITERAL_RE = re.compile(r"\bfunc\s*\(")
+
+
+def clean(source: str, keep_strings: bool = False) -> str:
+    """Blank out comments and string/rune literals, keeping offsets and line breaks.
+
+    With *keep_strings*, only comments are blanked: import paths are strings.
+    """
+
+    def blank(match: re.Match[str]) -> str:
+        text = match.group(0)
+        if keep_strings and not text.startswith("/"):
+            return text
+        return re.sub(r"[^\n]", " ", text)
+
+    return _CLEAN_RE.sub(blank, source)
+
+
+def package_clause(source: str) -> str | None:
+    """The name a Go file's ``package`` clause declares."""
+    match = _PACKAGE_RE.search(clean(source))
+    return match.group(1) if match is not None else None
+
+
+def go_module_path(go_mod: Path) -> str | None:
+    """The module path a ``go.mod`` declares; None when the file is missing or declares none."""
+    if not go_mod.is_file():
+        return None
+    match = _MODULE_RE.search(go_mod.read_text(encoding="utf-8", errors="replace"))
+    return match.group(1) if match else None
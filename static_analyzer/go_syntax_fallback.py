@@ -0,0 +1,228 @@
+"""Declarations and best-effort call edges for Go files gopls returned no symbols for.
+
+gopls answers from type-checked packages; when the project does not build (a
+work-in-progress branch, a broken dependency) it can come back empty for whole
+packages. Those files are parsed with tree-sitter instead: functions, methods
+and types become nodes named the way gopls would name them, and a call whose
+name matches one declaration (in the caller's package, or the imported one)
+becomes a call edge. Nothing was type-checked, so the files are reported as
+degraded rather than passed off as analyzed.
+"""
+
+import functools
+import logging
+from collections.abc import Iterable
+from dataclasses import dataclass, field
+from pathlib import Path
+
+import tree_sitter_go
+from tree_sitter import Language as TreeSitterLanguage
+from tree_sitter import Node as TreeSitterNode
+from tree_sitter import Parser
+
+from static_analyzer.constants import NodeType
+from static_analyzer.engine.language_adapter import LanguageAdapter
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+DEGRADED_REASON = "degraded (project did not build)"
+
+_TYPE_KINDS = {"struct_type": NodeType.STRUCT, "interface_type": NodeType.INTERFACE}
+
+
+@dataclass(frozen=True)
+class GoCall:
+    """A call in a declaration's body: ``helper()``, or ``store.Save()`` with qualifier ``store``. 0-based."""
+
+    name: str
+    qualifier: str | None
+    line: int
+    column: int
+
+
+@dataclass
+class GoDeclaration:
+    """A function, method or type as gopls would report it in ``textDocument/documentSymbol``."""
+
+    symbol: dict
+    calls: list[GoCall] = field(default_factory=list)
+
+
+@functools.cache
+def _go_parser() -> Parser:
+    parser = Parser()
+    parser.language = TreeSitterLanguage(tree_sitter_go.language())
+    return parser
+
+
+def _text(node: TreeSitterNode | None) -> str:
+    return node.text.decode(errors="replace") if node is not None and node.text is not None else ""
+
+
+def _range(node: TreeSitterNode) -> dict:
+    return {
+        "start": {"line": node.start_point.row, "character": node.start_point.column},
+        "end": {"line": node.end_point.row, "character": node.end_point.column},
+    }
+
+
+def _symbol(name: str, kind: NodeType, node: TreeSitterNode, name_node: TreeSitterNode) -> dict:
+    return {"name": name, "kind": kind, "range": _range(node), "selectionRange": _range(name_node)}
+
+
+def _receiver(method: TreeSitterNode) -> str | None:
+    """``(*Store)`` for ``func (s *Store) Save()``; type parameters are dropped, as gopls does."""
+    receiver = method.child_by_field_name("receiver")
+    parameter = next((child for child in receiver.named_children if child.type == "parameter_declaration"), None)
+    receiver_type = parameter.child_by_field_name("type") if parameter is not None else None
+    pointer = receiver_type is not None and receiver_type.type == "pointer_type"
+    if pointer:
+        receiver_type = receiver_type.named_children[0] if receiver_type.named_children else None
+    if receiver_type is not None and receiver_type.type == "generic_type":
+        receiver_type = receiver_type.child_by_field_name("type")
+    name = _text(receiver_type)
+    return f"({'*' if pointer else ''}{name})" if name else None
+
+
+def _calls(body: TreeSitterNode | None) -> list[GoCall]:
+    calls: list[GoCall] = []
+    stack = [body] if body is not None else []
+    while stack:
+        node = stack.pop()
+        if node.type == "call_expression":
+            function = node.child_by_field_name("function")
+            if function is not None and function.type == "identifier":
+                calls.append(GoCall(_text(function), None, function.start_point.row, function.start_point.column))
+            elif function is not None and function.type == "selector_expression":
+                operand = function.child_by_field_name("operand")
+                name = function.child_by_field_name("field")
+                if name is not None:
+                    qualifier = _text(operand) if operand is not None and operand.type == "identifier" else None
+                    calls.append(GoCall(_text(name), qualifier, name.start_point.row, name.start_point.column))
+        stack.extend(reversed(node.named_children))
+    return calls
+
+
+def parse_declarations(source: bytes) -> tuple[list[GoDeclaration], dict[str, str]]:
+    """The declarations of a Go file and its imports (the name each is used by -> import path)."""
+    root = _go_parser().parse(source).root_node
+    declarations: list[GoDeclaration] = []
+    imports: dict[str, str] = {}
+    for node in root.named_children:
+        if node.type == "import_declaration":
+            for spec in (child for child in _walk(node) if child.type == "import_spec"):
+                path = _text(spec.child_by_field_name("path")).strip('"`')
+                alias = _text(spec.child_by_field_name("name")) or path.rsplit("/", 1)[-1]
+                if alias not in ("_", "."):
+                    imports[alias] = path
+        elif node.type == "function_declaration" and (name := node.child_by_field_name("name")) is not None:
+            symbol = _symbol(_text(name), NodeType.FUNCTION, node, name)
+            declarations.append(GoDeclaration(symbol, _calls(node.child_by_field_name("body"))))
+        elif node.type == "method_declaration" and (name := node.child_by_field_name("name")) is not None:
+            if (receiver := _receiver(node)) is not None:
+                symbol = _symbol(f"{receiver}.{_text(name)}", NodeType.METHOD, node, name)
+                declarations.append(GoDeclaration(symbol, _calls(node.child_by_field_name("body"))))
+        elif node.type == "type_declaration":
+            for spec in node.named_children:
+                name = spec.child_by_field_name("name")
+                if spec.type not in ("type_spec", "type_alias") or name is None:
+                    continue
+                kind = _TYPE_KINDS.get(getattr(spec.child_by_field_name("type"), "type", ""), NodeType.CLASS)
+                declarations.append(GoDeclaration(_symbol(_text(name), kind, spec, name)))
+    return declarations, imports
+
+
+def _walk(node: TreeSitterNode) -> Iterable[TreeSitterNode]:
+    stack = [node]
+    while stack:
+        current = stack.pop()
+        yield current
+        stack.extend(reversed(current.named_children))
+
+
+def _short_name(qualified_name: str) -> str:
+    return qualified_name.rsplit(".", 1)[-1]
+
+
+class _Resolver:
+    """Matches a call to the one declaration it can name, by package directory and name."""
+
+    def __init__(self, call_graph: CallGraph) -> None:
+        self._callables: dict[str, list[Node]] = {}
+        for node in call_graph.nodes.values():
+            if node.is_callable() and node.file_path.endswith(".go"):
+                self._callables.setdefault(_short_name(node.fully_qualified_name), []).append(node)
+
+    def resolve(self, call: GoCall, caller_dir: Path, imports: dict[str, str]) -> Node | None:
+        candidates = self._callables.get(call.name, [])
+        if call.qualifier is None:
+            # ``helper()``: a function of the caller's own package.
+            matches = [n for n in candidates if n.type == NodeType.FUNCTION and Path(n.file_path).parent == caller_dir]
+        elif call.qualifier in imports:
+            # ``store.Open()``: a function of the imported package, matched on the import path's tail.
+            tail = Path(imports[call.qualifier])
+            matches = [
+                n
+                for n in candidates
+                if n.type == NodeType.FUNCTION and Path(n.file_path).parent.parts[-len(tail.parts) :] == tail.parts
+            ]
+        else:
+            # ``s.Save()``: a method; the receiver's type is unknown, so only an unambiguous name links.
+            methods = [n for n in candidates if n.type == NodeType.METHOD]
+            local = [n for n in methods if Path(n.file_path).parent == caller_dir]
+            matches = local if len(local) == 1 else methods
+        return matches[0] if len(matches) == 1 else None
+
+
+def add_syntax_fallback(
+    call_graph: CallGraph,
+    source_files: Iterable[str],
+    reference_nodes: Iterable[Node],
+    adapter: LanguageAdapter,
+    project_root: Path,
+    known_degraded: Iterable[str] = (),
+) -> tuple[list[Node], list[str]]:
+    """Parse the Go files of *source_files* with no symbols into *call_graph*.
+
+    *known_degraded* are files an earlier run already filled in (their nodes
+    come back from the cache), re-parsed so they stay flagged. Returns the new
+    nodes, to add as references, and the degraded files.
+    """
+    with_symbols = {node.file_path for node in call_graph.nodes.values()}
+    with_symbols.update(node.file_path for node in reference_nodes)
+    known = set(known_degraded)
+    parsed: list[tuple[Path, list[tuple[Node, GoDeclaration]], dict[str, str]]] = []
+    for file_path in source_files:
+        if not file_path.endswith(".go") or (file_path in with_symbols and file_path not in known):
+            continue
+        try:
+            declarations, imports = parse_declarations(Path(file_path).read_bytes())
+        except OSError as e:
+            logger.debug(f"Go syntax fallback: cannot read {file_path}: {e}")
+            continue
+        if not declarations:
+            continue
+        symbols = adapter.normalize_document_symbols([declaration.symbol for declaration in declarations])
+        nodes: list[tuple[Node, GoDeclaration]] = []
+        for symbol, declaration in zip(symbols, declarations):
+            start, end = symbol["selectionRange"]["start"], symbol["range"]["end"]
+            qname = adapter.build_qualified_name(Path(file_path), symbol["name"], symbol["kind"], [], project_root)
+            node = Node(qname, symbol["kind"], file_path, start["line"] + 1, end["line"] + 1, start["character"])
+            call_graph.add_node(node)
+            nodes.append((node, declaration))
+        parsed.append((Path(file_path), nodes, imports))
+
+    resolver = _Resolver(call_graph)
+    added: list[Node] = []
+    for file_path, nodes, imports in parsed:
+        for node, declaration in nodes:
+            added.append(node)
+            for call in declaration.calls:
+                target = resolver.resolve(call, file_path.parent, imports)
+                if target is None or target.fully_qualified_name == node.fully_qualified_name:
+                    continue
+                site = {"file": str(file_path), "line": call.line + 1, "column": call.column + 1}
+                call_graph.add_edge(node.fully_qualified_name, target.fully_qualified_name, call_sites=[site])
+    return added, sorted(str(file_path) for file_path, _, _ in parsed)
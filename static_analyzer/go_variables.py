@@ -0,0 +1,320 @@
+"""Go package-level variables: ``mutates`` edges to the ones a function writes, calls followed through func values.
+
+Bodies are matched by pattern, not type-checked: a name the body redeclares (``x := ...``)
+is taken for a local, a multiple assignment (``a, b = f()``) counts its first name only, and a
+call through a func value is followed only when the values it may hold are in the source.
+"""
+
+import logging
+import re
+from collections.abc import Iterable
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, NodeType
+from static_analyzer.go_source import FUNC_LITERAL_RE, clean
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+_IDENT = r"[A-Za-z_]\w*"
+_INDEX = r"\s*\[[^\]\n]*\]"
+# ``[key]`` / ``.field`` after the variable: the write lands in it all the same.
+_SELECTORS = rf"(?:{_INDEX}|\s*\.{_IDENT})*"
+# An identifier that is not a selector (``x.f``) nor called (``f()``): a function stored, not run.
+_VALUE_RE = re.compile(rf"(?<![\w.])({_IDENT})\b(?!\s*\()")
+# gopls may give a variable the range of its name only: read on for a wrapped initializer.
+_DECLARATION_LINES = 40
+
+
+def _package(node: Node) -> str:
+    return str(Path(node.file_path).parent)
+
+
+def _short_name(qualified_name: str) -> str:
+    return qualified_name.rsplit(".", 1)[-1]
+
+
+def _is_local(body: str, name: str) -> bool:
+    return re.search(rf"(?<![\w.]){re.escape(name)}\s*:=|\bvar\s+{re.escape(name)}\b", body) is not None
+
+
+def mutated_variables(body: str, names: Iterable[str]) -> list[str]:
+    """The *names* the function *body* (cleaned source) writes, in order of first write."""
+    mutated = []
+    for name in names:
+        if _is_local(body, name):
+            continue
+        word = rf"(?<![\w.]){re.escape(name)}\b"
+        assignment = rf"{word}{_SELECTORS}\s*(?:[-+*/%&|^]|<<|>>|&\^)?=(?!=)"
+        increment = rf"{word}{_SELECTORS}\s*(?:\+\+|--)"
+        builtin = rf"\b(?:delete|clear)\(\s*{re.escape(name)}\b"
+        match = re.search(rf"{assignment}|{increment}|{builtin}", body)
+        if match is not None:
+            mutated.append((match.start(), name))
+    return [name for _, name in sorted(mutated)]
+
+
+def variable_calls(body: str, names: Iterable[str]) -> list[tuple[str, int, bool]]:
+    """The calls the function *body* (cleaned source) makes through the *names*: (name, offset, through an index).
+
+    ``name(x)`` is a plain call; ``name[key](x)``, and ``h(x)`` after ``h, ok :=
+    name[key]``, dispatch through a map or slice of functions.
+    """
+    calls = []
+    for name in names:
+        if _is_local(body, name):
+            continue
+        word = rf"(?<![\w.]){re.escape(name)}\b"
+        for match in re.finditer(rf"{word}((?:{_INDEX})*)\s*\(", body):
+            calls.append((name, match.start(), bool(match.group(1))))
+        lookup = rf"(?<![\w.])({_IDENT})(?:\s*,\s*{_IDENT})?\s*:?=\s*{word}(?:{_INDEX})+\s*(?=$|;)"
+        for match in re.finditer(lookup, body, re.MULTILINE):
+            alias = re.escape(match.group(1))
+            for call in re.finditer(rf"(?<![\w.]){alias}\s*\(", body[match.end() :]):
+                calls.append((name, match.end() + call.start(), True))
+    return sorted(calls, key=lambda call: call[1])
+
+
+def stored_values(text: str, name: str) -> list[str]:
+    """The identifiers the assignments to *name* in *text* (cleaned source) store: ``name[k] = fn`` gives ``fn``."""
+    values = []
+    assignment = rf"(?<![\w.]){re.escape(name)}(?:{_INDEX})*\s*=(?!=)\s*({_IDENT})\s*(?=$|[;}}])"
+    for match in re.finditer(assignment, text, re.MULTILINE):
+        values.append(match.group(1))
+    return values
+
+
+def _closing(text: str, open_idx: int) -> int | None:
+    """Index of the bracket closing the one at *open_idx*, or ``None`` if it is not closed in *text*."""
+    depth = 0
+    for index in range(open_idx, len(text)):
+        if text[index] in "([{":
+            depth += 1
+        elif text[index] in ")]}":
+            depth -= 1
+            if depth == 0:
+                return index
+    return None
+
+
+def _initializer_span(declaration: str, name: str) -> tuple[int, int] | None:
+    match = re.search(rf"(?<![\w.]){re.escape(name)}\b[^=\n]*?(?<![=!<>:])=(?!=)", declaration)
+    if match is None:
+        return None
+    depth = 0
+    for index in range(match.end(), len(declaration)):
+        char = declaration[index]
+        if char in "([{":
+            depth += 1
+        elif char in ")]}":
+            depth -= 1
+            if depth < 0:
+                return match.end(), index
+        elif char in "\n;" and depth == 0 and declaration[match.end() : index].strip():
+            return match.end(), index
+    return match.end(), len(declaration)
+
+
+def initializer(declaration: str, name: str) -> str | None:
+    """The expression a ``var name [T] = ...`` declaration (cleaned) gives *name*, or ``None`` if it has none."""
+    span = _initializer_span(declaration, name)
+    return declaration[span[0] : span[1]] if span is not None else None
+
+
+def _literal_end(text: str, func_idx: int) -> int | None:
+    """End of the function literal whose ``func`` keyword is at *func_idx*: just past its body's ``}``."""
+    params = text.find("(", func_idx)
+    params_end = _closing(text, params) if params != -1 else None
+    if params_end is None:
+        return None
+    # Skip the results, parenthesized or not, to the body.
+    index = params_end + 1
+    while index < len(text) and text[index] != "{":
+        if text[index] in "([":
+            closing = _closing(text, index)
+            if closing is None:
+                return None
+            index = closing
+        index += 1
+    body_end = _closing(text, index) if index < len(text) else None
+    return body_end + 1 if body_end is not None else None
+
+
+def function_literals(value: str) -> list[tuple[int, int]]:
+    """The (start, end) offsets of the function literals among the elements of the composite literal *value*.
+
+    ``map[string]func(int){"a": func(x int) {...}}``: the ``func`` of the type
+    comes before the literal's ``{`` and is not one.
+    """
+    open_idx = value.find("{")
+    close_idx = _closing(value, open_idx) if open_idx != -1 else None
+    if close_idx is None:
+        return []
+    literals = []
+    index = open_idx + 1
+    while (match := FUNC_LITERAL_RE.search(value, index, close_idx)) is not None:
+        end = _literal_end(value, match.start())
+        if end is None or end > close_idx:
+            break
+        literals.append((match.start(), end))
+        index = end
+    return literals
+
+
+class _Sources:
+    """Lazily read and clean Go sources, by path."""
+
+    def __init__(self) -> None:
+        self._lines: dict[str, list[str]] = {}
+
+    def lines(self, node: Node, at_least: int = 0) -> str:
+        """The cleaned source of *node*'s range, extended to *at_least* lines."""
+        if node.file_path not in self._lines:
+            try:
+                text = Path(node.file_path).read_text(encoding="utf-8", errors="replace")
+            except OSError as e:
+                logger.debug(f"Go variables: cannot read {node.file_path}: {e}")
+                text = ""
+            self._lines[node.file_path] = clean(text).split("\n")
+        end = max(node.line_end, node.line_start - 1 + at_least)
+        return "\n".join(self._lines[node.file_path][node.line_start - 1 : end])
+
+
+class _Edges:
+    """Edges added to *call_graph*, skipping those it already has."""
+
+    def __init__(self, call_graph: CallGraph) -> None:
+        self.call_graph = call_graph
+        self.existing = set(call_graph.reference_edges)
+        self.mutates: list[tuple[str, str, str]] = []
+        self.calls: list[tuple[str, str]] = []
+
+    def _ensure(self, node: Node) -> None:
+        if node.fully_qualified_name not in self.call_graph.nodes:
+            self.call_graph.add_node(node)
+
+    def add_mutates(self, function: Node, variable: Node) -> None:
+        self._ensure(variable)
+        edge = (function.fully_qualified_name, variable.fully_qualified_name, str(EdgeKind.MUTATES))
+        if edge not in self.existing:
+            self.call_graph.add_reference_edge(edge[0], edge[1], EdgeKind.MUTATES)
+            self.existing.add(edge)
+            self.mutates.append(edge)
+
+    def add_call(self, caller: Node, callee: Node, site: dict[str, str | int]) -> None:
+        if caller is callee:
+            return
+        self._ensure(callee)
+        before = len(self.call_graph.edges)
+        self.call_graph.add_edge(caller.fully_qualified_name, callee.fully_qualified_name, [site])
+        if len(self.call_graph.edges) > before:
+            self.calls.append((caller.fully_qualified_name, callee.fully_qualified_name))
+
+
+class _Package:
+    """What one Go package's variables hold, read from their declarations and the assignments of its functions."""
+
+    def __init__(self, sources: _Sources, bodies: dict[str, str], functions: dict[str, Node]) -> None:
+        self.sources = sources
+        self.bodies = bodies
+        self.functions = functions
+        self._wrapped: dict[str, list[Node]] = {}
+        self.literals: list[Node] = []
+
+    def wrapped(self, variable: Node) -> list[Node]:
+        """The functions *variable* is known to hold: named ones of the package and its own function literals.
+
+        A variable holding one function literal is that literal; each literal
+        of a map or slice becomes a ``<variable>.func1``, ``func2``, ... node.
+        """
+        qname = variable.fully_qualified_name
+        if qname not in self._wrapped:
+            self._wrapped[qname] = self._read(variable)
+        return self._wrapped[qname]
+
+    def _read(self, variable: Node) -> list[Node]:
+        qname = variable.fully_qualified_name
+        name = _short_name(qname)
+        declaration = self.sources.lines(variable, _DECLARATION_LINES)
+        span = _initializer_span(declaration, name)
+        value = declaration[span[0] : span[1]] if span is not None else ""
+        if re.match(r"\s*func\s*\(", value):
+            return [variable]
+        held: list[Node] = []
+        for ordinal, (start, end) in enumerate(function_literals(value), start=1):
+            offset = span[0] + start
+            first = variable.line_start + declaration.count("\n", 0, offset)
+            last = first + declaration.count("\n", offset, span[0] + end)
+            literal = Node(f"{qname}.func{ordinal}", NodeType.FUNCTION, variable.file_path, first, last)
+            self.literals.append(literal)
+            held.append(literal)
+            value = value[:start] + " " * (end - start) + value[end:]
+        candidates = [match.group(1) for match in _VALUE_RE.finditer(value)]
+        for body in self.bodies.values():
+            candidates.extend(stored_values(body, name))
+        held.extend(self.functions[candidate] for candidate in dict.fromkeys(candidates) if candidate in self.functions)
+        return held
+
+
+def _call_site(caller: Node, body: str, offset: int, via: str | None = None) -> dict[str, str | int]:
+    line_start = body.rfind("\n", 0, offset) + 1
+    site: dict[str, str | int] = {
+        "file": caller.file_path,
+        "line": caller.line_start + body.count("\n", 0, offset),
+        "column": offset - line_start + 1,
+    }
+    if via is not None:
+        site["via"] = via
+    return site
+
+
+def add_variable_edges(
+    call_graph: CallGraph, reference_nodes: Iterable[Node]
+) -> tuple[list[tuple[str, str, str]], list[tuple[str, str]]]:
+    """Add ``mutates`` edges and calls through func-valued variables for Go; returns the new edges of both."""
+    variables: dict[str, dict[str, Node]] = {}
+    for node in reference_nodes:
+        if node.type == NodeType.VARIABLE and node.file_path.endswith(".go") and ".(" not in node.fully_qualified_name:
+            variables.setdefault(_package(node), {}).setdefault(_short_name(node.fully_qualified_name), node)
+    callables = [
+        node for node in call_graph.nodes.values() if node.file_path.endswith(".go") and node.type in CALLABLE_TYPES
+    ]
+    edges = _Edges(call_graph)
+    if not variables or not callables:
+        return edges.mutates, edges.calls
+
+    sources = _Sources()
+    by_package: dict[str, list[Node]] = {}
+    for node in callables:
+        by_package.setdefault(_package(node), []).append(node)
+    for package_dir, package_callables in by_package.items():
+        package_variables = variables.get(package_dir, {})
+        if not package_variables:
+            continue
+        bodies = {node.fully_qualified_name: sources.lines(node) for node in package_callables}
+        functions = {
+            _short_name(node.fully_qualified_name): node
+            for node in package_callables
+            if node.type == NodeType.FUNCTION and ".(" not in node.fully_qualified_name
+        }
+        package = _Package(sources, bodies, functions)
+        for function in package_callables:
+            body = bodies[function.fully_qualified_name]
+            for name in mutated_variables(body, package_variables):
+                edges.add_mutates(function, package_variables[name])
+            for name, offset, indexed in variable_calls(body, package_variables):
+                site = _call_site(function, body, offset, "dispatch" if indexed else None)
+                for callee in package.wrapped(package_variables[name]):
+                    edges.add_call(function, callee, site)
+        # A literal's body runs as its own function: link it to the package functions it calls.
+        for literal in package.literals:
+            body = sources.lines(literal)
+            for name, callee in functions.items():
+                for match in re.finditer(rf"(?<![\w.]){re.escape(name)}\s*\(", body):
+                    edges.add_call(literal, callee, _call_site(literal, body, match.start()))
+    if edges.mutates or edges.calls:
+        logger.info(
+            f"Go variables: {len(edges.mutates)} mutates edges, {len(edges.calls)} calls through func-valued variables"
+        )
+    return edges.mutates, edges.calls
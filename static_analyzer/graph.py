@@ -22,6 +22,11 @@ logger = logging.getLogger(__name__)
 _EMPTY_NODES: Mapping[str, Node] = MappingProxyType({})
 
 
+def _signature_suffix(node: Node) -> str:
+    """``(value, min, max int) (result int)`` when the node's declared signature was read, else empty."""
+    return node.signature.render("") if node.signature is not None else ""
+
+
 def detect_communities[T](
     graph: nx.Graph | nx.DiGraph,
     *,
@@ -44,17 +49,35 @@ class EdgeKind(StrEnum):
     ``CALL`` edges live in ``CallGraph.edges`` and drive component *relations*.
     The rest are *reference edges* (``CallGraph.reference_edges``): structural
     relationships the pure call graph misses — a method belongs to its class
-    (CONTAINS), a class extends another (INHERITS), code names a type (TYPEREF),
-    a module imports another (IMPORT). They complete the graph for *clustering*
+    (CONTAINS), a class extends another (INHERITS), a type satisfies an interface
+    it never names (IMPLEMENTS, Go), a Go type embeds another (EMBEDS), code names
+    a type (TYPEREF), a module imports another (IMPORT; for a Go blank import, the
+    importer's entry points to the package's ``init``s). They complete the graph for *clustering*
     (so constructors/dunders/DI/interface methods aren't graph-isolated) without
-    polluting the call-relation semantics.
+    polluting the call-relation semantics. SENDS_TO/RECEIVES_FROM are heuristic
+    Go channel links (``go_channels``) and carry a confidence; SPAWNS links a
+    function to the one it starts as a goroutine (``go f()``); RETURNS links a Go
+    function to the named function type it returns (``go_signatures``); MUTATES
+    links a Go function to the package variables it writes (``go_variables``).
+    USES_GIVEN links a Scala function or class to the given/implicit instance
+    passed for its context parameters (``scala_implicits``). ROUTE links a Go
+    function registering an HTTP route to the route's handler (``http_routes``).
     """
 
     CALL = "call"
     CONTAINS = "contains"
     INHERITS = "inherits"
+    IMPLEMENTS = "implements"
+    EMBEDS = "embeds"
     TYPEREF = "typeref"
     IMPORT = "import"
+    SENDS_TO = "sends-to"
+    RECEIVES_FROM = "receives-from"
+    SPAWNS = "spawns"
+    RETURNS = "returns"
+    MUTATES = "mutates"
+    USES_GIVEN = "uses-given"
+    ROUTE = "route"
 
 
 @dataclass(frozen=True)
@@ -110,6 +133,17 @@ class Edge:
     def call_sites(self) -> list[dict[str, Hashable]]:
         return [dict(site) for site in self._call_sites]
 
+    @property
+    def via(self) -> str | None:
+        """How every call site makes the call, when they agree (``"dispatch"``: through a map or slice of functions)."""
+        vias = {site.get("via") for site in self._call_sites}
+        return vias.pop() if len(vias) == 1 else None
+
+    @property
+    def recursive(self) -> bool:
+        """A function calling itself directly; mutual recursion is found by ``CallGraph.recursive_cycles``."""
+        return self.get_source() == self.get_destination()
+
     def get_source(self) -> str:
         return self.src_node.fully_qualified_name
 
@@ -133,6 +167,12 @@ class Edge:
             normalized["file"] = normalized.pop("file_path")
         return normalized
 
+    def sort_call_sites(self) -> None:
+        """Order the call sites by file, line and column instead of discovery order."""
+        self._call_sites.sort(
+            key=lambda site: (str(site.get("file", "")), site.get("line") or 0, site.get("column") or 0, str(site))
+        )
+
     def visit_paths(self, fn: Callable[[str], str]) -> None:
         for site in self._call_sites:
             if "file" in site:
@@ -174,6 +214,8 @@ class CallGraph:
         # Merged into the graph only for clustering (``clustering_networkx``).
         # Each entry: (src_qname, dst_qname, EdgeKind value).
         self.reference_edges: list[tuple[str, str, str]] = []
+        # Confidence ("high"/"medium"/"low") of heuristic reference edges, keyed like them.
+        self.reference_confidence: dict[tuple[str, str, str], str] = {}
 
     def add_node(self, node: Node) -> None:
         loc_key = LocationKey(node.file_path, node.line_start, node.line_end, node.type.value, node.col_start)
@@ -240,8 +282,16 @@ class CallGraph:
 
         self.nodes[src_name].added_method_called_by_me(self.nodes[dst_name])
 
-    def add_reference_edge(self, src_name: str, dst_name: str, kind: EdgeKind) -> None:
-        """Record a non-call relationship edge (CONTAINS/INHERITS/TYPEREF/IMPORT).
+    def sort(self) -> None:
+        """Order nodes, edges, call sites and reference edges by name rather than LSP discovery order."""
+        self.nodes = dict(sorted(self.nodes.items()))
+        self.edges.sort(key=lambda edge: (edge.get_source(), edge.get_destination()))
+        for edge in self.edges:
+            edge.sort_call_sites()
+        self.reference_edges.sort()
+
+    def add_reference_edge(self, src_name: str, dst_name: str, kind: EdgeKind, confidence: str | None = None) -> None:
+        """Record a non-call relationship edge (CONTAINS/INHERITS/TYPEREF/IMPORT/...).
 
         Stored separately from call edges; used only to complete the graph for
         clustering. Silently ignores endpoints that aren't nodes or self-loops.
@@ -250,6 +300,8 @@ class CallGraph:
         dst_name = self._resolve_name(dst_name)
         if src_name in self.nodes and dst_name in self.nodes and src_name != dst_name:
             self.reference_edges.append((src_name, dst_name, str(kind)))
+            if confidence is not None:
+                self.reference_confidence[(src_name, dst_name, str(kind))] = confidence
 
     def _carry_reference_edges(self, out: "CallGraph", *extra_sources: "CallGraph") -> None:
         """Copy reference edges whose both endpoints survive into a derived graph.
@@ -260,7 +312,9 @@ class CallGraph:
         """
         seen: set[tuple[str, str, str]] = set()
         carried: list[tuple[str, str, str]] = []
+        confidence: dict[tuple[str, str, str], str] = {}
         for source in (self, *extra_sources):
+            source_confidence = getattr(source, "reference_confidence", {})
             for s, d, k in getattr(source, "reference_edges", ()):
                 # Resolve through the SOURCE's alias map: an endpoint stored under a short
                 # alias must map to the canonical name ``out`` promoted it to, or a call edge
@@ -269,12 +323,16 @@ class CallGraph:
                 if rs in out.nodes and rd in out.nodes and rs != rd and (rs, rd, k) not in seen:
                     seen.add((rs, rd, k))
                     carried.append((rs, rd, k))
+                    if (s, d, k) in source_confidence:
+                        confidence[(rs, rd, k)] = source_confidence[(s, d, k)]
         out.reference_edges = carried
+        out.reference_confidence = confidence
 
     def filter(
         self,
         keep_node: Callable[[Node], bool],
         on_dropped_edge: Callable[[Edge], None],
+        keep_edge: Callable[[Edge], bool] | None = None,
     ) -> "CallGraph":
         """Return a new CallGraph keeping only nodes matching ``keep_node`` and connecting edges.
 
@@ -282,6 +340,8 @@ class CallGraph:
         a warm-start invalidation/filter step doesn't silently drop the prior
         clustering. Edges whose endpoints both survive are re-added; edges
         with a dropped endpoint are cascaded out and optionally collected.
+        ``keep_edge``, when given, additionally drops edges between surviving
+        nodes; those are not reported to ``on_dropped_edge``.
         """
         out = CallGraph(language=self.language)
         for node in self.nodes.values():
@@ -290,6 +350,8 @@ class CallGraph:
         for edge in self.edges:
             src, dst = edge.get_source(), edge.get_destination()
             if out.has_node(src) and out.has_node(dst):
+                if keep_edge is not None and not keep_edge(edge):
+                    continue
                 try:
                     out.add_edge(src, dst, call_sites=edge.call_sites)
                 except ValueError as e:
@@ -390,6 +452,44 @@ class CallGraph:
             nx_graph.add_edge(edge.get_source(), edge.get_destination())
         return nx_graph
 
+    def recursive_cycles(self) -> list[list[str]]:
+        """The functions that reach themselves through calls, one sorted group per cycle.
+
+        A group is a strongly connected component of the call edges: several
+        functions calling each other round (mutual recursion), or one with a
+        ``recursive`` self-edge. Renderers collapse or highlight these, and a
+        walk down the calls stops at them.
+        """
+        calls = nx.DiGraph([(edge.get_source(), edge.get_destination()) for edge in self.edges])
+        cycles = [
+            sorted(component)
+            for component in nx.strongly_connected_components(calls)
+            if len(component) > 1 or any(calls.has_edge(name, name) for name in component)
+        ]
+        return sorted(cycles)
+
+    def llm_context_networkx(self, edge_kinds: Collection[str] | None = None) -> nx.DiGraph:
+        """Graph whose edges are shown to the LLM; each edge carries its ``kind``.
+
+        ``edge_kinds`` defaults to ``ClusteringConfig.LLM_CONTEXT_EDGE_KINDS`` and is
+        independent of both the clustering kinds and the call edges behind diagram
+        relations, so heuristic kinds can be kept out of the prompt yet still drawn.
+        """
+        kinds = set(ClusteringConfig.LLM_CONTEXT_EDGE_KINDS if edge_kinds is None else edge_kinds)
+        nx_graph = self.to_networkx()
+        if str(EdgeKind.CALL) not in kinds:
+            nx_graph.remove_edges_from(list(nx_graph.edges()))
+        else:
+            nx.set_edge_attributes(nx_graph, str(EdgeKind.CALL), "kind")
+        confidence = getattr(self, "reference_confidence", {})
+        for src, dst, kind in getattr(self, "reference_edges", ()):
+            rsrc, rdst = self._resolve_name(src), self._resolve_name(dst)
+            if kind in kinds and rsrc in self.nodes and rdst in self.nodes and not nx_graph.has_edge(rsrc, rdst):
+                nx_graph.add_edge(rsrc, rdst, kind=kind)
+                if (src, dst, kind) in confidence:
+                    nx_graph.edges[rsrc, rdst]["confidence"] = confidence[(src, dst, kind)]
+        return nx_graph
+
     def clustering_networkx(self, reference_kinds: Collection[str] | None = None) -> nx.DiGraph:
         """Graph used for clustering: call edges plus configured reference-edge kinds.
 
@@ -531,6 +631,7 @@ class CallGraph:
         cluster_ids: Collection[int] = frozenset(),
         cluster_result: ClusterResult | None = None,
         skip_nodes: Collection[str] = frozenset(),
+        edge_kinds: Collection[str] | None = None,
     ) -> str:
         """
         Generate a human-readable string representation of clusters.
@@ -545,6 +646,8 @@ class CallGraph:
                 cluster members and edges). The graph itself is not mutated;
                 this is a serialization-layer filter used by ``cfg_skip_planner``
                 to keep the LLM prompt under budget.
+            edge_kinds: ``EdgeKind`` values whose edges are listed as connections
+                (see ``llm_context_networkx``). Non-call edges are tagged with their kind.
 
         Returns:
             Formatted string with cluster definitions and inter-cluster connections
@@ -555,7 +658,7 @@ class CallGraph:
         if not cluster_result.clusters:
             return cluster_result.strategy if cluster_result.strategy in ("empty", "none") else "No clusters found."
 
-        cfg_graph_x = self.to_networkx()
+        cfg_graph_x = self.llm_context_networkx(edge_kinds)
         skip = set(skip_nodes)
 
         # Filter clusters if specific IDs requested
@@ -826,13 +929,17 @@ class CallGraph:
 
         # Aggregate inter-cluster edges: (src_cluster_id, dst_cluster_id) -> count + sample edges
         inter_cluster_summary: dict[tuple[int, int], list[str]] = defaultdict(list)
-        for src, dst in cfg_graph_x.edges():
+        for src, dst, data in cfg_graph_x.edges(data=True):
             if src in skip or dst in skip:
                 continue
             src_cluster = node_to_cluster.get(src)
             dst_cluster = node_to_cluster.get(dst)
             if src_cluster is not None and dst_cluster is not None and src_cluster != dst_cluster:
-                inter_cluster_summary[(src_cluster, dst_cluster)].append(f"{src} -> {dst}")
+                kind = data.get("kind", str(EdgeKind.CALL))
+                tag = "" if kind == EdgeKind.CALL else f" [{kind}]"
+                if "confidence" in data:
+                    tag = f" [{kind}, {data['confidence']} confidence]"
+                inter_cluster_summary[(src_cluster, dst_cluster)].append(f"{src} -> {dst}{tag}")
 
         inter_cluster_str = "Inter-Cluster Connections:\n\n"
         if inter_cluster_summary:
@@ -923,11 +1030,16 @@ class CallGraph:
             for node in nodes:
                 if node.methods_called_by_me:
                     label = node.entity_label()
-                    targets = ", ".join(sorted(node.methods_called_by_me))
-                    result += f"{label} {node.fully_qualified_name} calls: {targets}\n"
+                    targets = ", ".join(self._call_target_str(node, t) for t in sorted(node.methods_called_by_me))
+                    result += f"{label} {node.fully_qualified_name}{_signature_suffix(node)} calls: {targets}\n"
 
         return result
 
+    def _call_target_str(self, node: Node, target: str) -> str:
+        edge = self._edge_by_key.get((node.fully_qualified_name, target))
+        via = edge.via if edge is not None else None
+        return f"{target} (via {via})" if via else target
+
     def _llm_str_class_level(self, skip_set: set[Node]) -> str:
         """Level 2: Class-to-class summary with call counts and top edges."""
         class_calls: dict[str, dict[str, list[str]]] = defaultdict(lambda: defaultdict(list))
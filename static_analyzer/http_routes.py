@@ -0,0 +1,342 @@
+"""HTTP routes a Go service registers, added on top of the LSP call graph (``--framework http``).
+
+The router, not the registering function, calls a handler, so gopls has no edge for it: routes are
+read from net/http, gin, echo and chi registrations (see PYPI.md), recorded on the handler's node and
+linked to it by a ``routes`` edge from the function holding the registration.
+"""
+
+import logging
+import re
+from collections.abc import Iterable
+from dataclasses import dataclass
+from enum import StrEnum
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, NodeType
+from static_analyzer.go_imports import GoImport, GoImportIndex, ImportTable
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node, go_receiver_and_method
+
+logger = logging.getLogger(__name__)
+
+ANY_METHOD = "ANY"
+
+
+class Router(StrEnum):
+    NET_HTTP = "net/http"
+    GIN = "gin"
+    ECHO = "echo"
+    CHI = "chi"
+
+
+_ROUTER_IMPORTS: dict[Router, re.Pattern] = {
+    Router.NET_HTTP: re.compile(r"^net/http$"),
+    Router.GIN: re.compile(r"^github\.com/gin-gonic/gin$"),
+    Router.ECHO: re.compile(r"^github\.com/labstack/echo(?:/v\d+)?$"),
+    Router.CHI: re.compile(r"^github\.com/go-chi/chi(?:/v\d+)?$"),
+}
+_HTTP_METHODS = frozenset({"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE"})
+# chi's ``r.Get`` / ``r.Post``: the method in title case.
+_CHI_METHODS = frozenset(method.title() for method in _HTTP_METHODS)
+_HANDLE_METHODS = frozenset({"Handle", "HandleFunc"})
+_REGISTRATION_NAMES = (
+    _HTTP_METHODS | _CHI_METHODS | _HANDLE_METHODS | {"Any", "Add", "Method", "MethodFunc", "Group", "Route"}
+)
+
+_IDENT = r"[A-Za-z_]\w*"
+_OPERAND = rf"{_IDENT}(?:\.{_IDENT})*"
+_COMMENT_OR_STRING_RE = re.compile(
+    r"(\"(?:\\.|[^\"\\\n])*\"|`[^`]*`|'(?:\\.|[^'\\\n])*')|//[^\n]*|/\*.*?\*/", re.DOTALL
+)
+_CALL_RE = re.compile(rf"\.\s*({_IDENT})\s*\(")
+_RECEIVER_RE = re.compile(rf"(?<![\w.])({_OPERAND})\s*$")
+_WITH_RE = re.compile(r"\.\s*With\s*$")
+_ASSIGNED_RE = re.compile(rf"(?<![\w.])({_IDENT})\s*:?=\s*$")
+_STRING_RE = re.compile(r'^"((?:\\.|[^"\\])*)"$|^`([^`]*)`$', re.DOTALL)
+_HANDLER_FUNC_RE = re.compile(rf"^(?:http\.)?HandlerFunc\s*\(\s*({_OPERAND})\s*\)$")
+_HANDLER_RE = re.compile(rf"^{_OPERAND}$")
+_FUNC_LITERAL_RE = re.compile(rf"^func\s*\(\s*({_IDENT})\b")
+
+
+@dataclass(frozen=True)
+class HttpRoute:
+    """One registration: ``GET /v1/users/{id}`` served by the handler expression ``h.getUser``; 1-based line."""
+
+    method: str
+    path: str
+    handler: str
+    line: int
+
+    def __str__(self) -> str:
+        return f"{self.method} {self.path}"
+
+
+def routers(imports: Iterable[GoImport]) -> set[Router]:
+    """The routers a file imports."""
+    return {router for imp in imports for router, pattern in _ROUTER_IMPORTS.items() if pattern.match(imp.path)}
+
+
+def _strip_comments(text: str) -> str:
+    """Blank out comments, keeping string literals (the paths), offsets and line breaks."""
+    return _COMMENT_OR_STRING_RE.sub(lambda m: m.group(1) or re.sub(r"[^\n]", " ", m.group(0)), text)
+
+
+def _skip_string(text: str, i: int) -> int:
+    """Index of the last character of the string or rune literal opening at *i*."""
+    quote = text[i]
+    i += 1
+    while i < len(text) and text[i] != quote:
+        i += 2 if quote != "`" and text[i] == "\\" else 1
+    return i
+
+
+def _arguments(text: str, open_idx: int) -> tuple[list[tuple[int, str]], int]:
+    """The (offset, text) of each argument of the call whose ``(`` is at *open_idx*, and the closing ``)``."""
+    arguments: list[tuple[int, str]] = []
+    depth, start, i = 0, open_idx + 1, open_idx
+    while i < len(text):
+        ch = text[i]
+        if ch in "\"`'":
+            i = _skip_string(text, i)
+        elif ch in "([{":
+            depth += 1
+        elif ch in ")]}":
+            depth -= 1
+            if depth == 0:
+                break
+        elif ch == "," and depth == 1:
+            arguments.append((start, text[start:i]))
+            start = i + 1
+        i += 1
+    arguments.append((start, text[start:i]))
+    return [(offset + len(arg) - len(arg.lstrip()), arg.strip()) for offset, arg in arguments if arg.strip()], i
+
+
+def _receiver(text: str, dot_idx: int) -> str | None:
+    """``r`` for ``r.Get(`` and ``r.With(mw).Get(``, ``s.router`` for ``s.router.GET(``."""
+    end = dot_idx
+    while True:
+        before = text[max(0, end - 200) : end].rstrip()
+        if not before.endswith(")"):
+            match = _RECEIVER_RE.search(before)
+            return match.group(1) if match is not None else None
+        # ``r.With(mw)``: step back over the call to the router it is made on.
+        depth, i = 0, max(0, end - 200) + len(before) - 1
+        while i >= 0:
+            depth += {")": 1, "(": -1}.get(text[i], 0)
+            if depth == 0:
+                break
+            i -= 1
+        with_match = _WITH_RE.search(text[max(0, i - 200) : i])
+        if with_match is None:
+            return None
+        end = max(0, i - 200) + with_match.start()
+
+
+def _string(argument: str) -> str | None:
+    match = _STRING_RE.match(argument)
+    if match is None:
+        return None
+    return match.group(2) if match.group(2) is not None else match.group(1).replace('\\"', '"')
+
+
+def _handler(argument: str) -> str | None:
+    """The function or method *argument* names; None for literals and calls."""
+    if (wrapped := _HANDLER_FUNC_RE.match(argument)) is not None:
+        return wrapped.group(1)
+    return argument if _HANDLER_RE.match(argument) else None
+
+
+def _pattern(pattern: str) -> tuple[str, str]:
+    """``GET /users/{id}`` -> (``GET``, ``/users/{id}``); a pattern without a method matches any."""
+    method, _, rest = pattern.partition(" ")
+    if rest and method in _HTTP_METHODS:
+        return method, rest.strip()
+    return ANY_METHOD, pattern
+
+
+def _registration(name: str, arguments: list[str], found: set[Router]) -> tuple[str, str | None, str] | None:
+    """The (method, path, handler expression) a call registers, if *found* routers define it."""
+    strings = [_string(argument) for argument in arguments]
+    # gin and echo both spell methods ``GET``; only one of them may be imported to tell where the handler is.
+    gin_or_echo = found & {Router.GIN, Router.ECHO}
+    if (name in _HTTP_METHODS or name == "Any") and len(gin_or_echo) == 1 and len(arguments) >= 2:
+        handler = arguments[-1] if Router.GIN in gin_or_echo else arguments[1]
+        return (ANY_METHOD if name == "Any" else name), strings[0], handler
+    if name in _CHI_METHODS and Router.CHI in found and len(arguments) == 2:
+        return name.upper(), strings[0], arguments[1]
+    if name in ("Method", "MethodFunc") and Router.CHI in found and len(arguments) == 3:
+        return (strings[0] or "").upper(), strings[1], arguments[2]
+    if name == "Add" and Router.ECHO in found and len(arguments) >= 3:
+        return (strings[0] or "").upper(), strings[1], arguments[2]
+    if name == "Handle" and Router.GIN in found and len(arguments) >= 3:
+        return (strings[0] or "").upper(), strings[1], arguments[-1]
+    if name in _HANDLE_METHODS and found & {Router.NET_HTTP, Router.CHI} and len(arguments) == 2:
+        if strings[0] is None:
+            return None
+        method, path = _pattern(strings[0])
+        return method, path, arguments[1]
+    return None
+
+
+def _join(prefix: str, path: str) -> str:
+    """``/v1`` + ``/users``; a group's own ``""`` or ``"/"`` route is the prefix itself."""
+    if not prefix:
+        return path or "/"
+    return f"{prefix.rstrip('/')}/{path.lstrip('/')}" if path.strip("/") else prefix
+
+
+def find_routes(source: str, imports: Iterable[GoImport]) -> list[HttpRoute]:
+    """The routes the Go *source* registers with the routers among its *imports*, in source order."""
+    found = routers(imports)
+    if not found:
+        return []
+    text = _strip_comments(source)
+    routes: list[HttpRoute] = []
+    # ``v1 := r.Group("/v1")``: (offset, variable, prefix), in source order.
+    groups: list[tuple[int, str, str]] = []
+    # ``r.Route("/api", func(r chi.Router) {...})``: (start, end, parameter, prefix) of the literal.
+    scopes: list[tuple[int, int, str, str]] = []
+
+    def prefix(receiver: str, offset: int) -> str:
+        for start, end, name, scope_prefix in reversed(scopes):
+            if start <= offset < end and name == receiver:
+                return scope_prefix
+        return next((p for at, name, p in reversed(groups) if at < offset and name == receiver), "")
+
+    for match in _CALL_RE.finditer(text):
+        name = match.group(1)
+        if name not in _REGISTRATION_NAMES or (receiver := _receiver(text, match.start())) is None:
+            continue
+        arguments, close_idx = _arguments(text, match.end() - 1)
+        if not arguments or (path := _string(arguments[0][1])) is None:
+            continue
+        offset = match.start()
+        if name == "Group" and found & {Router.GIN, Router.ECHO}:
+            receiver_start = text.rfind(receiver, 0, offset)
+            if (assigned := _ASSIGNED_RE.search(text[max(0, receiver_start - 200) : receiver_start])) is not None:
+                groups.append((close_idx, assigned.group(1), _join(prefix(receiver, offset), path)))
+            continue
+        if name == "Route" and Router.CHI in found and len(arguments) == 2:
+            literal_offset, literal = arguments[1]
+            if (parameter := _FUNC_LITERAL_RE.match(literal)) is not None:
+                scope = (literal_offset, literal_offset + len(literal), parameter.group(1))
+                scopes.append((*scope, _join(prefix(receiver, offset), path)))
+            continue
+        registration = _registration(name, [argument for _, argument in arguments], found)
+        if registration is None:
+            continue
+        method, route_path, handler_argument = registration
+        handler = _handler(handler_argument)
+        if method not in _HTTP_METHODS | {ANY_METHOD} or route_path is None or handler is None:
+            continue
+        full_path = _join(prefix(receiver, offset), route_path)
+        if "/" not in full_path:
+            continue
+        routes.append(HttpRoute(method, full_path, handler, text.count("\n", 0, offset) + 1))
+    return routes
+
+
+class _HandlerResolver:
+    """Matches a handler expression to the one function or method of the project it can name."""
+
+    def __init__(self, call_graph: CallGraph) -> None:
+        self._functions: dict[tuple[Path, str], list[Node]] = {}
+        self._methods: dict[str, list[Node]] = {}
+        for node in call_graph.nodes.values():
+            if not node.file_path.endswith(".go"):
+                continue
+            short_name = node.fully_qualified_name.rsplit(".", 1)[-1]
+            if node.type == NodeType.FUNCTION:
+                self._functions.setdefault((Path(node.file_path).parent, short_name), []).append(node)
+            elif node.type == NodeType.METHOD:
+                self._methods.setdefault(short_name, []).append(node)
+
+    def resolve(self, handler: str, file_path: Path, table: ImportTable, registrar_body: str) -> Node | None:
+        operand, _, name = handler.rpartition(".")
+        if not operand:
+            # ``listUsers``: a function of the registering file's package.
+            matches = self._functions.get((file_path.parent, name), [])
+        elif "." not in operand and (package_dir := table.package_dir(operand)) is not None:
+            # ``handlers.ListUsers``: a function of an imported package of the module.
+            matches = self._functions.get((package_dir, name), [])
+        elif "." not in operand and table.import_for(operand) is not None:
+            return None  # a function of another module or the standard library
+        else:
+            matches = self._method(operand.rsplit(".", 1)[-1], name, file_path, registrar_body)
+        return matches[0] if len(matches) == 1 else None
+
+    def _method(self, variable: str, name: str, file_path: Path, registrar_body: str) -> list[Node]:
+        """``h.getUser``: typed by ``h *UserHandler`` / ``h := &UserHandler{`` in the registrar, else by name."""
+        candidates = self._methods.get(name, [])
+        declared = re.search(
+            rf"(?<![\w.]){re.escape(variable)}[ \t]+\*?(?:{_IDENT}\.)?({_IDENT})\b|"
+            rf"(?<![\w.]){re.escape(variable)}\s*:?=\s*&?(?:{_IDENT}\.)?({_IDENT})\s*\{{",
+            registrar_body,
+        )
+        if declared is not None:
+            type_name = declared.group(1) or declared.group(2)
+            typed = [
+                n
+                for n in candidates
+                if (member := go_receiver_and_method(n.fully_qualified_name)) is not None and member[0] == type_name
+            ]
+            if typed:
+                return typed
+        local = [n for n in candidates if Path(n.file_path).parent == file_path.parent]
+        return local if len(local) == 1 else candidates
+
+
+def _registrar(functions: list[Node], line: int) -> Node | None:
+    """The innermost function holding *line*."""
+    holding = [node for node in functions if node.line_start <= line <= node.line_end]
+    return min(holding, key=lambda node: node.line_end - node.line_start, default=None)
+
+
+def add_route_edges(call_graph: CallGraph, source_files: Iterable[str | Path]) -> list[tuple[str, str, str]]:
+    """Record the routes of every Go file on their handlers and link registrars to them.
+
+    Returns (registrar, handler, ``METHOD path``) per route; the registrar is
+    empty when no function holds the registration.
+    """
+    by_file: dict[Path, list[Node]] = {}
+    for node in call_graph.nodes.values():
+        if node.file_path.endswith(".go"):
+            if node.http_routes:
+                # Re-run after every analyze(): start over so a removed registration drops its route.
+                node.http_routes = ()
+            if node.type in CALLABLE_TYPES:
+                by_file.setdefault(Path(node.file_path), []).append(node)
+    resolver = _HandlerResolver(call_graph)
+    index = GoImportIndex()
+    existing = set(call_graph.reference_edges)
+    linked: list[tuple[str, str, str]] = []
+    skipped = 0
+    for file_path in sorted({Path(path) for path in source_files if str(path).endswith(".go")}):
+        table = index.table(file_path)
+        if not routers(table.imports):
+            continue
+        try:
+            source = file_path.read_text(encoding="utf-8", errors="replace")
+        except OSError as e:
+            logger.debug(f"HTTP routes: cannot read {file_path}: {e}")
+            continue
+        lines = source.split("\n")
+        for route in find_routes(source, table.imports):
+            registrar = _registrar(by_file.get(file_path, []), route.line)
+            body = "\n".join(lines[registrar.line_start - 1 : registrar.line_end]) if registrar is not None else ""
+            handler = resolver.resolve(route.handler, file_path, table, body)
+            if handler is None:
+                skipped += 1
+                continue
+            if str(route) not in handler.http_routes:
+                handler.http_routes = (*handler.http_routes, str(route))
+            source_name = registrar.fully_qualified_name if registrar is not None else ""
+            linked.append((source_name, handler.fully_qualified_name, str(route)))
+            edge = (source_name, handler.fully_qualified_name, str(EdgeKind.ROUTE))
+            if registrar is not None and registrar is not handler and edge not in existing:
+                call_graph.add_reference_edge(edge[0], edge[1], EdgeKind.ROUTE)
+                existing.add(edge)
+    if linked or skipped:
+        logger.info(f"HTTP routes: {len(linked)} routes linked to handlers, {skipped} with no project handler skipped")
+    return linked
@@ -40,6 +40,7 @@ def update_cfg_for_changed_files(
     project_path: Path,
     engine_client: LSPClient,
     ignore_manager: RepoIgnoreManager,
+    concurrency: int | None = None,
 ) -> dict[str, Any]:
     """Apply *changed_files* to *cached_analysis* via re-LSP-and-merge.
 
@@ -79,7 +80,7 @@ def update_cfg_for_changed_files(
     ]
 
     if changed_source_files:
-        builder = CallGraphBuilder(engine_client, adapter, project_path)
+        builder = CallGraphBuilder(engine_client, adapter, project_path, concurrency)
         engine_result = builder.build(changed_source_files)
         new_analysis = convert_to_codeboarding_format(builder.symbol_table, engine_result, adapter)
     else:
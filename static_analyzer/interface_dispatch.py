@@ -0,0 +1,177 @@
+"""Go interface dispatch, added on top of the LSP call graph (``--resolve-interface-dispatch``).
+
+``var speaker Speaker = dog; speaker.Speak()`` resolves, for gopls, to the
+interface method ``(Speaker).Speak``: the call graph stops there and the
+concrete methods look unused. This pass adds a call edge from the caller to
+the matching method of every type that implements the interface (the
+``implementations`` the hierarchy builder records for Go). A method a type only
+has through embedding is linked to the embedded type's definition (see
+:mod:`static_analyzer.go_embedding`).
+
+When the receiver's dynamic type can be read from a local assignment in the
+calling function (``speaker = dog`` after ``dog := Dog{...}``, ``s := &Cat{}``),
+only that type's method is linked; otherwise the call fans out to every
+implementation. The edge to the interface method itself is kept.
+"""
+
+import logging
+import re
+from collections import defaultdict
+from pathlib import Path
+
+from static_analyzer.go_embedding import Promotions
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node, go_receiver_and_method
+
+logger = logging.getLogger(__name__)
+
+_IDENT = r"[A-Za-z_]\w*"
+# ``&pkg.Dog{`` / ``Dog{`` / ``new(Dog)``: a composite literal or allocation naming the type.
+_LITERAL_TYPE_RE = re.compile(rf"^&?\s*(?:{_IDENT}\.)?({_IDENT})\s*\{{|^new\(\s*\*?(?:{_IDENT}\.)?({_IDENT})\s*\)")
+# How far an identifier's own assignment is followed (``speaker = dog`` -> ``dog := Dog{}``).
+_MAX_HOPS = 3
+
+
+def _assignment_patterns(name: str) -> tuple[re.Pattern[str], re.Pattern[str]]:
+    """(``name = rhs`` / ``name := rhs`` / ``var name T = rhs``, ``var name T``) for *name*."""
+    ident = re.escape(name)
+    assigned = re.compile(rf"(?:^|[^\w.])(?:var\s+)?{ident}(?:\s+[\w.*\[\]]+)?\s*:?=(?!=)\s*(.+?)\s*$")
+    declared = re.compile(rf"\bvar\s+{ident}\s+\*?(?:{_IDENT}\.)?({_IDENT})\s*$")
+    return assigned, declared
+
+
+def narrowed_type(lines: list[str], first_line: int, call_line: int, receiver: str) -> str | None:
+    """Type name the *receiver* variable was last assigned before *call_line*, or ``None`` if unknown.
+
+    *lines* is the file's text split into lines; *first_line* and *call_line*
+    are 1-based and bound the search to the calling function.
+    """
+    for _ in range(_MAX_HOPS):
+        assigned, declared = _assignment_patterns(receiver)
+        for index in range(call_line - 2, first_line - 2, -1):
+            line = lines[index] if 0 <= index < len(lines) else ""
+            match = assigned.search(line)
+            if match is not None:
+                rhs = match.group(1)
+                break
+            match = declared.search(line)
+            if match is not None:
+                return match.group(1)
+        else:
+            return None
+        literal = _LITERAL_TYPE_RE.match(rhs)
+        if literal is not None:
+            return literal.group(1) or literal.group(2)
+        if not re.fullmatch(_IDENT, rhs):
+            return None  # A call or expression: its type is not visible from here.
+        receiver, call_line = rhs, index + 1
+    return None
+
+
+def _receiver_at(line: str, column: int, method: str) -> str | None:
+    """The plain identifier ``x`` in ``x.method(`` at the 1-based *column*, if the receiver is one."""
+    for match in re.finditer(rf"({_IDENT})\s*\.\s*{re.escape(method)}\s*\(", line):
+        if match.start() <= column - 1 <= match.end():
+            return match.group(1)
+    return None
+
+
+class _Sources:
+    """Lazily read source lines, by path."""
+
+    def __init__(self) -> None:
+        self._lines: dict[str, list[str]] = {}
+
+    def lines(self, file_path: str) -> list[str]:
+        if file_path not in self._lines:
+            try:
+                self._lines[file_path] = Path(file_path).read_text(encoding="utf-8", errors="replace").splitlines()
+            except OSError as e:
+                logger.debug(f"Interface dispatch: cannot read {file_path}: {e}")
+                self._lines[file_path] = []
+        return self._lines[file_path]
+
+
+def _package(node: Node) -> str:
+    return str(Path(node.file_path).parent)
+
+
+def _method_index(call_graph: CallGraph) -> dict[tuple[str, str, str], str]:
+    """(package dir, receiver type, method name) -> method qualified name."""
+    index: dict[tuple[str, str, str], str] = {}
+    for qname, node in call_graph.nodes.items():
+        member = go_receiver_and_method(qname)
+        if member is not None:
+            index.setdefault((_package(node), *member), qname)
+    return index
+
+
+def _dispatch_targets(call_graph: CallGraph, hierarchy: dict[str, dict]) -> dict[str, dict[str, str]]:
+    """Interface method qualified name -> {implementing type name: concrete method qualified name}."""
+    methods = _method_index(call_graph)
+    promotions = Promotions(call_graph)
+    by_type: dict[tuple[str, str], dict[str, str]] = defaultdict(dict)
+    for (package, type_name, method), qname in methods.items():
+        by_type[(package, type_name)][method] = qname
+
+    targets: dict[str, dict[str, str]] = {}
+    for interface, info in hierarchy.items():
+        implementations = info.get("implementations") or []
+        interface_node = call_graph.nodes.get(interface)
+        if not implementations or interface_node is None:
+            continue
+        interface_name = interface.rsplit(".", 1)[-1]
+        for method, method_qname in by_type.get((_package(interface_node), interface_name), {}).items():
+            concrete: dict[str, str] = {}
+            for implementation in implementations:
+                node = call_graph.nodes.get(implementation)
+                if node is None:
+                    continue
+                type_name = implementation.rsplit(".", 1)[-1]
+                target = by_type.get((_package(node), type_name), {}).get(method)
+                if target is None and promotions:
+                    target = promotions.method(implementation, method)
+                if target is not None:
+                    concrete[type_name] = target
+            if concrete:
+                targets[method_qname] = concrete
+    return targets
+
+
+def add_interface_dispatch_edges(call_graph: CallGraph, hierarchy: dict[str, dict]) -> int:
+    """Add call edges from interface method call sites to the concrete methods; returns how many were added."""
+    targets = _dispatch_targets(call_graph, hierarchy)
+    if not targets:
+        return 0
+    sources = _Sources()
+    added: set[tuple[str, str]] = set()
+    existing = {(edge.get_source(), edge.get_destination()) for edge in call_graph.edges}
+    for edge in list(call_graph.edges):
+        concrete = targets.get(edge.get_destination())
+        if not concrete:
+            continue
+        caller = edge.src_node
+        method = edge.get_destination().rsplit(".", 1)[-1]
+        # Sites grouped by the concrete methods they resolve to; a site-less edge fans out.
+        sites_by_targets: dict[tuple[str, ...], list[dict]] = defaultdict(list)
+        for site in edge.call_sites or [{}]:
+            resolved = None
+            if site:
+                line = int(site.get("line", 0))
+                lines = sources.lines(str(site.get("file", caller.file_path)))
+                text = lines[line - 1] if 0 < line <= len(lines) else ""
+                receiver = _receiver_at(text, int(site.get("column", 0)), method)
+                if receiver is not None:
+                    resolved = narrowed_type(lines, caller.line_start, line, receiver)
+            chosen = (concrete[resolved],) if resolved in concrete else tuple(sorted(concrete.values()))
+            sites_by_targets[chosen].append(site)
+        for chosen, sites in sites_by_targets.items():
+            for target in chosen:
+                if target == caller.fully_qualified_name:
+                    continue
+                call_graph.add_edge(caller.fully_qualified_name, target, [site for site in sites if site])
+                if (caller.fully_qualified_name, target) not in existing:
+                    added.add((caller.fully_qualified_name, target))
+    if added:
+        logger.info(f"Interface dispatch pass added {len(added)} call edges to concrete implementations")
+    return len(added)
@@ -53,8 +53,9 @@ class ControlFlowGraph:
         # Carry the other graph's reference edges (CONTAINS/INHERITS/TYPEREF/IMPORT) so a
         # same-language sub-project's completed graph isn't reduced to call-only after merge.
         # Re-added via the API so alias-resolution and node-existence guards apply post-merge.
+        confidence = getattr(other, "reference_confidence", {})
         for src, dst, kind in getattr(other, "reference_edges", ()):
-            self.graph.add_reference_edge(src, dst, EdgeKind(kind))
+            self.graph.add_reference_edge(src, dst, EdgeKind(kind), confidence.get((src, dst, kind)))
         self.graph.method_cluster_paths.merge(other.method_cluster_paths)
 
     def visit_paths(self, fn: Callable[[str], str]) -> None:
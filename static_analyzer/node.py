@@ -3,12 +3,134 @@
 Extracted from constants.py so that module contains only constants.
 """
 
-from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES, DATA_TYPES, ENTITY_LABELS, NodeType
+import re
+from dataclasses import dataclass
+
+from static_analyzer.constants import (
+    CALLABLE_TYPES,
+    CLASS_TYPES,
+    DATA_TYPES,
+    ENTITY_LABELS,
+    NodeType,
+    ReceiverKind,
+    Visibility,
+)
+
+# ``pkg.file.(Task).Serialize`` / ``pkg.file.(*Store).Save``: the Go adapter's method and field names,
+# capturing the pointer star, the receiver type and the member.
+_GO_MEMBER_RE = re.compile(r"\.\((\*?)([A-Za-z_]\w*)\)\.([A-Za-z_]\w*)$")
+
+
+def go_receiver_and_method(qualified_name: str) -> tuple[str, str] | None:
+    """``("Store", "Save")`` for the Go method or field ``pkg.file.(*Store).Save``; None for anything else."""
+    match = _GO_MEMBER_RE.search(qualified_name)
+    return (match.group(2), match.group(3)) if match else None
+
+
+def receiver_kind(qualified_name: str) -> ReceiverKind:
+    """The receiver kind encoded in a Go method's qualified name; ``NONE`` for anything else."""
+    match = _GO_MEMBER_RE.search(qualified_name)
+    if match is None:
+        return ReceiverKind.NONE
+    return ReceiverKind.POINTER if match.group(1) else ReceiverKind.VALUE
+
+
+def visibility(qualified_name: str, file_path: str) -> Visibility:
+    """Whether the symbol is exported, read from its name the way its language does.
+
+    Go: the name, and a method's or field's receiver type, start with a capital;
+    closures (``Handle.func1``) never do. Python: no part of the name has a
+    leading underscore, dunders aside. Anything else counts as exported.
+    """
+    if file_path.endswith(".go"):
+        names = go_receiver_and_method(qualified_name) or (qualified_name.rsplit(".", 1)[-1],)
+        return Visibility.EXPORTED if all(name[:1].isupper() for name in names) else Visibility.UNEXPORTED
+    if file_path.endswith(".py"):
+        private = any(
+            part.startswith("_") and not (part.startswith("__") and part.endswith("__"))
+            for part in qualified_name.split(".")
+        )
+        return Visibility.UNEXPORTED if private else Visibility.EXPORTED
+    return Visibility.EXPORTED
+
+
+@dataclass(frozen=True)
+class Parameter:
+    """One declared parameter or result of a function: ``value int``, ``fns ...HandlerFunc`` or just ``int``."""
+
+    # The element type of a variadic parameter: ``HandlerFunc`` for ``...HandlerFunc``.
+    type: str
+    # None when unnamed; a Go parameter or result list is either all named or all unnamed.
+    name: str | None = None
+    # Only the last parameter can be; it takes any number of arguments, as a slice of ``type``.
+    is_variadic: bool = False
+
+    @property
+    def type_str(self) -> str:
+        return f"...{self.type}" if self.is_variadic else self.type
+
+    def __str__(self) -> str:
+        return f"{self.name} {self.type_str}" if self.name else self.type_str
+
+
+def _list_str(parameters: tuple[Parameter, ...]) -> str:
+    """*parameters* as Go writes them, consecutive named ones of one type sharing it: ``value, min, max int``."""
+    parts: list[str] = []
+    for index, parameter in enumerate(parameters):
+        following = parameters[index + 1] if index + 1 < len(parameters) else None
+        if following is not None and parameter.name and following.name and following.type_str == parameter.type_str:
+            parts.append(parameter.name)
+        else:
+            parts.append(str(parameter))
+    return ", ".join(parts)
+
+
+@dataclass(frozen=True)
+class Signature:
+    """Parameters and results of a callable, as declared in its source."""
+
+    parameters: tuple[Parameter, ...] = ()
+    results: tuple[Parameter, ...] = ()
+
+    @property
+    def named_results(self) -> bool:
+        return any(result.name for result in self.results)
+
+    @property
+    def is_variadic(self) -> bool:
+        return bool(self.parameters) and self.parameters[-1].is_variadic
+
+    def accepts(self, argument_count: int) -> bool:
+        """Whether a call with *argument_count* arguments matches; ``Compose(a, b, c)`` does ``Compose(fns ...F)``."""
+        if self.is_variadic:
+            return argument_count >= len(self.parameters) - 1
+        return argument_count == len(self.parameters)
+
+    def parameters_str(self) -> str:
+        return _list_str(self.parameters)
+
+    def results_str(self) -> str:
+        """``int``, ``(string, int)`` or ``(result int)``, as Go writes them; empty for none."""
+        if len(self.results) == 1 and not self.named_results:
+            return self.results[0].type
+        return f"({_list_str(self.results)})" if self.results else ""
+
+    def render(self, name: str) -> str:
+        return f"{name}({self.parameters_str()}) {self.results_str()}".rstrip()
 
 
 class Node:
     """Call-graph node for LSP SymbolKind. Use NodeType for type constants."""
 
+    # Declared signature, where a source pass read one (Go callables); a class
+    # attribute so nodes pickled before it existed still load.
+    signature: Signature | None = None
+    # Standard-library interfaces a type satisfies (``fmt.Stringer``), see
+    # ``static_analyzer.standard_interfaces``; a class attribute for the same reason.
+    standard_interfaces: tuple[str, ...] = ()
+    # ``GET /users/{id}``: HTTP routes a Go handler serves, see ``static_analyzer.http_routes``.
+    http_routes: tuple[str, ...] = ()
+
     def __init__(
         self,
         fully_qualified_name: str,
@@ -38,6 +160,16 @@ class Node:
         """Return True if this node represents a class."""
         return self.type in CLASS_TYPES
 
+    @property
+    def receiver_kind(self) -> ReceiverKind:
+        """Whether this is a Go method with a value or a pointer receiver."""
+        return receiver_kind(self.fully_qualified_name)
+
+    @property
+    def visibility(self) -> Visibility:
+        """Whether this symbol is exported from its package, see :func:`visibility`."""
+        return visibility(self.fully_qualified_name, self.file_path)
+
     def is_data(self) -> bool:
         """Return True if this node represents a data entity (property, field, variable, constant)."""
         return self.type in DATA_TYPES
@@ -0,0 +1,127 @@
+"""Per-directory language and adapter settings for monorepos (``[[languages.paths]]``).
+
+One global language setting is wrong for a monorepo whose subtrees are
+different languages built differently. ``.codeboarding/config.toml`` can assign
+a language, and options for its adapter, to directory globs:
+
+    [[languages.paths]]
+    glob = "services/go/**"
+    language = "go"
+    root = "services/go"            # LSP project root; defaults to the glob's directory prefix
+    build_tags = ["integration"]    # Go only
+    env = { GOFLAGS = "-mod=vendor" }
+
+    [[languages.paths]]
+    glob = "web/**"
+    language = "typescript"
+
+Each entry becomes its own engine config: a language server for that language
+rooted at ``root`` and fed exactly the files under ``glob`` with the language's
+extensions. A file belongs to the first entry matching it; files no entry
+claims (other languages, or files outside the entry's ``root``) are analyzed
+as before, so a Python script inside ``services/go/`` is still picked up by
+the repository-wide Python pass.
+"""
+
+import logging
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+
+from project_config import ProjectConfig
+from static_analyzer.constants import LANGUAGE_EXTENSIONS, Language
+from static_analyzer.select_query import glob_regex
+
+logger = logging.getLogger(__name__)
+
+# Languages whose adapter understands ``build_tags``.
+_BUILD_TAG_LANGUAGES = frozenset({Language.GO})
+
+
+@dataclass(frozen=True)
+class PathOverride:
+    glob: str
+    language: Language
+    # Repo-relative LSP project root.
+    root: str
+    build_tags: tuple[str, ...] = ()
+    env: dict[str, str] = field(default_factory=dict)
+
+    def matches(self, rel_path: str) -> bool:
+        """True for a repo-relative *rel_path* under the glob and the root with one of the language's extensions."""
+        return (
+            PurePosixPath(rel_path).suffix in LANGUAGE_EXTENSIONS[self.language]
+            and (self.root == "." or rel_path.startswith(f"{self.root}/"))
+            and glob_regex(self.glob).match(rel_path) is not None
+        )
+
+
+def _glob_root(glob: str) -> str:
+    """The directory prefix of *glob* before its first wildcard (``services/go/**`` -> ``services/go``)."""
+    parts: list[str] = []
+    for part in PurePosixPath(glob).parts:
+        if any(c in part for c in "*?["):
+            break
+        parts.append(part)
+    return PurePosixPath(*parts).as_posix() if parts else "."
+
+
+def _parse_entry(entry: object) -> PathOverride | None:
+    if not isinstance(entry, dict):
+        logger.warning(f"Ignoring [[languages.paths]] entry {entry!r}: expected a table")
+        return None
+    glob = str(entry.get("glob", "")).strip().strip("/")
+    language_name = str(entry.get("language", "")).strip().lower()
+    if not glob or not language_name:
+        logger.warning(f"Ignoring [[languages.paths]] entry {entry!r}: 'glob' and 'language' are required")
+        return None
+    try:
+        language = Language(language_name)
+    except ValueError:
+        logger.warning(f"Ignoring [[languages.paths]] entry for '{glob}': unknown language '{language_name}'")
+        return None
+    build_tags = entry.get("build_tags", [])
+    if isinstance(build_tags, str):
+        build_tags = [build_tags]
+    if build_tags and language not in _BUILD_TAG_LANGUAGES:
+        logger.warning(f"Ignoring build_tags for '{glob}': {language.value} has no build tags")
+        build_tags = []
+    env = entry.get("env", {})
+    if not isinstance(env, dict):
+        logger.warning(f"Ignoring env for '{glob}': expected a table of variables")
+        env = {}
+    return PathOverride(
+        glob=glob,
+        language=language,
+        root=str(entry.get("root", "")).strip().strip("/") or _glob_root(glob),
+        build_tags=tuple(str(tag).strip() for tag in build_tags if str(tag).strip()),
+        env={str(k): str(v) for k, v in env.items()},
+    )
+
+
+@dataclass(frozen=True)
+class PathOverrides:
+    """The repository's ``[[languages.paths]]`` entries, in file order."""
+
+    entries: tuple[PathOverride, ...] = ()
+
+    @classmethod
+    def from_project_config(cls, project_config: ProjectConfig) -> "PathOverrides":
+        configured = project_config.section("languages").get("paths", [])
+        entries = tuple(entry for entry in map(_parse_entry, configured) if entry is not None)
+        for entry in entries:
+            logger.info(
+                f"Path override: {entry.glob} -> {entry.language.value} at {entry.root}"
+                + (f" (build tags {','.join(entry.build_tags)})" if entry.build_tags else "")
+            )
+        return cls(entries=entries)
+
+    def __bool__(self) -> bool:
+        return bool(self.entries)
+
+    def owner(self, repo_path: Path, file_path: Path) -> PathOverride | None:
+        """The first entry claiming *file_path* (absolute), or None when it is left to auto-detection."""
+        try:
+            rel_path = file_path.relative_to(repo_path).as_posix()
+        except ValueError:
+            return None
+        return next((entry for entry in self.entries if entry.matches(rel_path)), None)
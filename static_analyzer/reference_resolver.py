@@ -7,6 +7,9 @@ from typing import Any
 from agents.agent_responses import AnalysisInsights, RelationCallSite, RelationEdge, SourceCodeReference
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.constants import LANGUAGE_EXTENSIONS, Language
+from static_analyzer.elixir_directives import DirectiveKind, ElixirDirectiveIndex, expand_alias
+from static_analyzer.go_embedding import Promotions
+from static_analyzer.go_imports import GoImportIndex
 from static_analyzer.internal_references import looks_internal_reference, reference_tokens
 from static_analyzer.node import Node
 
@@ -38,6 +41,9 @@ class StaticReferenceResolver:
     def __init__(self, repo_dir: Path, static_analysis: StaticAnalysisResults):
         self.repo_dir = repo_dir
         self.static_analysis = static_analysis
+        self._promotions: Promotions | None = None
+        self._go_imports = GoImportIndex()
+        self._elixir_directives = ElixirDirectiveIndex()
 
     def fix_source_code_reference_lines(self, analysis: AnalysisInsights) -> AnalysisInsights:
         logger.info(f"Fixing source code reference lines for the analysis: {analysis.llm_str()}")
@@ -149,6 +155,13 @@ class StaticReferenceResolver:
                 exact_matches.append(self.static_analysis.get_reference(lang, qname))
             except (ValueError, FileExistsError):
                 continue
+        if not exact_matches:
+            promoted = (
+                self._promoted_reference(qname)
+                or self._imported_reference(qname, allowed_files)
+                or self._elixir_reference(qname, allowed_files)
+            )
+            exact_matches = [promoted] if promoted is not None else []
 
         if exact_matches:
             node = next(
@@ -187,6 +200,88 @@ class StaticReferenceResolver:
         self._apply_resolved_node(reference, node)
         return True
 
+    def _promoted_reference(self, qname: str) -> Node | None:
+        """The Go method a promoted name such as ``models.task.(Task).GetType`` really is (see ``go_embedding``)."""
+        if Language.GO not in self.static_analysis.get_languages():
+            return None
+        if self._promotions is None:
+            try:
+                self._promotions = Promotions(self.static_analysis.get_cfg(Language.GO))
+            except ValueError:
+                return None
+        target = self._promotions.resolve(qname) if self._promotions else None
+        if target is None:
+            return None
+        try:
+            return self.static_analysis.get_reference(Language.GO, target)
+        except (ValueError, FileExistsError):
+            return self.static_analysis.get_cfg(Language.GO).nodes.get(target)
+
+    def _imported_reference(self, qname: str, scope_files: set[Path] | None = None) -> Node | None:
+        """The Go symbol *qname* names through an import of the Go files in scope (all of them by default).
+
+        ``u.Add`` with ``import u "example.com/edgecases/utils"``, or plain ``Add``
+        with a dot import of it, is ``utils.Add``: the one symbol named so in that
+        package's directory. See ``go_imports``.
+        """
+        if Language.GO not in self.static_analysis.get_languages():
+            return None
+        files = [
+            Path(file_path)
+            for file_path in (scope_files or self.static_analysis.get_source_files(Language.GO))
+            if str(file_path).endswith(".go")
+        ]
+        qualifier, _, name = qname.partition(".")
+        directories: set[Path] = set()
+        for file_path in files:
+            table = self._go_imports.table(self._absolute_reference_path(str(file_path)))
+            if not name:
+                directories.update(table.dot_imported_dirs())
+            elif (directory := table.package_dir(qualifier)) is not None:
+                directories.add(directory)
+        if not directories:
+            return None
+        # ``(*Server).Start`` and ``Server.Start`` name the same method.
+        wanted = _receiverless(name or qualifier)
+        matches = {
+            node.fully_qualified_name: node
+            for node in self.static_analysis.iter_reference_nodes(Language.GO)
+            if self._absolute_reference_path(node.file_path).parent in directories
+            and _receiverless(node.fully_qualified_name).endswith(f".{Path(node.file_path).stem}.{wanted}")
+        }
+        return next(iter(matches.values())) if len(matches) == 1 else None
+
+    def _elixir_reference(self, qname: str, scope_files: set[Path] | None = None) -> Node | None:
+        """The Elixir symbol *qname* names through the aliases and imports of the files in scope, arity optional.
+
+        ``Accounts.create_user/2`` with ``alias MyApp.Accounts``, or plain
+        ``create_user`` with ``import MyApp.Accounts``, is
+        ``MyApp.Accounts.create_user/2``: the one symbol named so. See ``elixir_directives``.
+        """
+        if Language.ELIXIR not in self.static_analysis.get_languages():
+            return None
+        files = [
+            Path(file_path)
+            for file_path in (scope_files or self.static_analysis.get_source_files(Language.ELIXIR))
+            if str(file_path).endswith(LANGUAGE_EXTENSIONS[Language.ELIXIR])
+        ]
+        candidates = {qname}
+        unqualified = "." not in qname.split("/", 1)[0]
+        for file_path in files:
+            directives = self._elixir_directives.directives(self._absolute_reference_path(str(file_path)))
+            candidates.add(expand_alias(qname, directives))
+            if unqualified:
+                candidates.update(
+                    f"{directive.module}.{qname}" for directive in directives if directive.kind == DirectiveKind.IMPORT
+                )
+        # ``create_user`` names every arity of the function; it resolves when there is only one.
+        matches = {
+            node.fully_qualified_name: node
+            for node in self.static_analysis.iter_reference_nodes(Language.ELIXIR)
+            if node.fully_qualified_name in candidates or node.fully_qualified_name.rsplit("/", 1)[0] in candidates
+        }
+        return next(iter(matches.values())) if len(matches) == 1 else None
+
     def resolve_node(self, reference: SourceCodeReference):
         """Resolve a source reference to a static-analysis node without mutating it."""
         qname = reference.qualified_name.replace(os.sep, ".")
@@ -196,6 +291,10 @@ class StaticReferenceResolver:
             except (ValueError, FileExistsError):
                 pass
 
+        promoted = self._promoted_reference(qname) or self._imported_reference(qname) or self._elixir_reference(qname)
+        if promoted is not None:
+            return promoted
+
         for lang in self.static_analysis.get_languages():
             _, node = self.static_analysis.get_loose_reference(lang, qname)
             if node is not None:
@@ -479,3 +578,7 @@ class StaticReferenceResolver:
                         )
                         return True
         return False
+
+
+def _receiverless(qualified_name: str) -> str:
+    return qualified_name.replace("(*", "").replace("(", "").replace(")", "")
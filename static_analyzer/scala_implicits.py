@@ -0,0 +1,129 @@
+"""Scala context parameters, added on top of the LSP call graph.
+
+``def place(order: Order)(using repo: OrderRepo)`` is called as ``place(order)``:
+the compiler passes the ``given`` (or Scala 2 ``implicit``) instance of
+``OrderRepo`` in scope, so neither a call nor a reference names it. This pass
+reads the ``using``/``implicit`` parameters of each Scala function and class and
+adds a ``uses-given`` edge from it to what provides each one:
+
+* the ``given``/implicit instance of the project declared with that exact type
+  (``given repo: OrderRepo = PostgresOrderRepo()``), when there is exactly one;
+  an instance Metals reports no symbol for is stood in for by the class or
+  object declaring it;
+* else the project type the parameter names (``OrderRepo``), so the dependency
+  is kept even when the instance comes from outside the project or is ambiguous.
+
+Givens resolved by scope (imports, companion objects) are not told apart: two
+instances of one type in the project link to the type rather than guess.
+"""
+
+import logging
+from collections.abc import Iterable
+from pathlib import Path
+
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+from static_analyzer.scala_source import (
+    SCALA_SUFFIXES,
+    context_parameters,
+    declaration_header,
+    given_type,
+    type_head,
+)
+
+logger = logging.getLogger(__name__)
+
+# Objects are modules to Metals; they declare most givens.
+_CONTAINER_TYPES = CLASS_TYPES | {NodeType.MODULE}
+
+
+class _Sources:
+    """Lazily read Scala sources, by path."""
+
+    def __init__(self) -> None:
+        self._lines: dict[str, list[str]] = {}
+
+    def lines(self, file_path: str) -> list[str]:
+        if file_path not in self._lines:
+            try:
+                self._lines[file_path] = Path(file_path).read_text(encoding="utf-8", errors="replace").split("\n")
+            except OSError as e:
+                logger.debug(f"Scala implicits: cannot read {file_path}: {e}")
+                self._lines[file_path] = []
+        return self._lines[file_path]
+
+    def header(self, node: Node) -> str:
+        return declaration_header(self.lines(node.file_path), node.line_start - 1)
+
+
+def _innermost_container(nodes: list[Node], line: int) -> Node | None:
+    """The smallest class or object of *nodes* whose range holds the 1-based *line*."""
+    enclosing = [node for node in nodes if node.type in _CONTAINER_TYPES and node.line_start <= line <= node.line_end]
+    return min(enclosing, key=lambda node: node.line_end - node.line_start, default=None)
+
+
+def find_givens(nodes: list[Node], sources: _Sources | None = None) -> dict[str, list[Node]]:
+    """The project's given/implicit instances by the type they provide, each as its own node or its container's."""
+    sources = sources or _Sources()
+    by_file: dict[str, list[Node]] = {}
+    for node in nodes:
+        by_file.setdefault(node.file_path, []).append(node)
+    givens: dict[str, list[Node]] = {}
+    for file_path, file_nodes in by_file.items():
+        lines = sources.lines(file_path)
+        declared_at = {node.line_start: node for node in file_nodes if node.type not in _CONTAINER_TYPES}
+        for index, line in enumerate(lines):
+            if "given" not in line and "implicit" not in line:
+                continue
+            provided = given_type(declaration_header(lines, index))
+            if provided is None:
+                continue
+            provider = declared_at.get(index + 1) or _innermost_container(file_nodes, index + 1)
+            if provider is not None and provider not in givens.setdefault(provided, []):
+                givens[provided].append(provider)
+    return givens
+
+
+def add_given_edges(call_graph: CallGraph, nodes: Iterable[Node]) -> list[tuple[str, str, str]]:
+    """Link each Scala function and class to the providers of its context parameters (see module docstring).
+
+    *nodes* are the language's reference nodes; a provider missing from the
+    graph is added to it. Returns the ``uses-given`` edges added.
+    """
+    scala_nodes = {
+        node.fully_qualified_name: node
+        for node in [*call_graph.nodes.values(), *nodes]
+        if node.file_path.endswith(SCALA_SUFFIXES)
+    }
+    if not scala_nodes:
+        return []
+    sources = _Sources()
+    givens = find_givens(list(scala_nodes.values()), sources)
+    types_by_name: dict[str, list[Node]] = {}
+    for node in scala_nodes.values():
+        if node.type in _CONTAINER_TYPES:
+            types_by_name.setdefault(node.fully_qualified_name.rsplit(".", 1)[-1], []).append(node)
+
+    existing = set(call_graph.reference_edges)
+    added: list[tuple[str, str, str]] = []
+    for node in list(call_graph.nodes.values()):
+        if node.type not in CALLABLE_TYPES | _CONTAINER_TYPES or not node.file_path.endswith(SCALA_SUFFIXES):
+            continue
+        for parameter_type in context_parameters(sources.header(node)):
+            providers = givens.get(parameter_type, [])
+            if len(providers) != 1:
+                providers = types_by_name.get(type_head(parameter_type), [])
+            if len(providers) != 1 or providers[0] is node:
+                continue
+            provider = providers[0]
+            if provider.fully_qualified_name not in call_graph.nodes:
+                call_graph.add_node(provider)
+            edge = (node.fully_qualified_name, provider.fully_qualified_name, str(EdgeKind.USES_GIVEN))
+            if edge not in existing:
+                call_graph.add_reference_edge(edge[0], edge[1], EdgeKind.USES_GIVEN)
+                existing.add(edge)
+                added.append(edge)
+    if added:
+        logger.info(f"Scala implicits: {len(added)} uses-given edges")
+    return added
@@ -0,0 +1,203 @@
+"""Scala declarations read from source: packages, supertypes and context parameters.
+
+Metals reports what a file declares but not the package it declares it in,
+what a class mixes in, or what its context parameters ask for. These helpers
+read them off the declaration *header*, the text from the start of the
+declaration to its body (``{``, ``=``, or a Scala 3 ``:``), which may span lines:
+
+* ``package com.acme`` then ``package orders``: the file declares ``com.acme.orders``;
+* ``class Shop(db: Db) extends Store(db) with Logging, Metrics``: supertypes
+  ``Store``, ``Logging`` and ``Metrics`` (Scala 2 ``with`` or Scala 3 commas);
+* ``def place(o: Order)(using repo: OrderRepo)`` or ``(implicit ec:
+  ExecutionContext)``: context parameters of types ``OrderRepo`` and ``ExecutionContext``;
+* ``given repo: OrderRepo = ...``, ``given Ord[Int] with``, ``implicit val ec:
+  ExecutionContext = ...`` or ``implicit object Db extends Database``: instances
+  the compiler passes for a context parameter of that type.
+"""
+
+import re
+
+SCALA_SUFFIXES = (".scala", ".sc")
+# The JVM name of an object, ``User$``: keeps a companion apart from its class ``User``.
+COMPANION_SUFFIX = "$"
+
+# Comments and string literals, blanked before matching so ``// extends Foo`` is not a supertype.
+_CLEAN_RE = re.compile(r'//[^\n]*|/\*.*?\*/|"""(?:.|\n)*?"""|"(?:\\.|[^"\\\n])*"', re.DOTALL)
+# Unindented, so ``package object`` members and nested ``package x { }`` blocks are left out.
+_PACKAGE_RE = re.compile(r"^package[ \t]+(?!object\b)([\w.]+)", re.MULTILINE)
+# A line starting with one of these continues the header of the line before it.
+_CONTINUATION_RE = re.compile(r"^\s*(?:extends|with|derives|using|implicit|[(\[:,)])")
+_EXTENDS_RE = re.compile(r"\bextends\b")
+_DERIVES_RE = re.compile(r"\bderives\b")
+_WITH_RE = re.compile(r"\bwith\b")
+_CONTEXT_LIST_RE = re.compile(r"\(\s*(using|implicit)\b")
+_GIVEN_RE = re.compile(r"\bgiven\b")
+_IMPLICIT_VALUE_RE = re.compile(r"\bimplicit\s+(?:(?:final|override|private|protected|lazy)\s+)*(val|def|object)\b")
+_TYPE_NAME_RE = re.compile(r"^[\w.]+")
+_PARENT_SEPARATOR_RE = re.compile(r",|\bwith\b")
+_COMMA_RE = re.compile(",")
+_COLON_RE = re.compile(":")
+_DEFAULT_RE = re.compile(r"=(?!>)")
+# ``implicit def toJson(user: User)``: ordinary parameters make an implicit ``def`` a conversion.
+_CONVERSION_RE = re.compile(r"^\s*\w+\s*(?:\[[^\]]*\])?\s*\((?!\s*(?:using|implicit)\b)")
+_OPENERS = "([{"
+_CLOSERS = ")]}"
+# Headers longer than this are not declarations worth following.
+_MAX_HEADER_LINES = 12
+
+
+def clean(source: str) -> str:
+    """Blank out comments and string literals, keeping offsets and line breaks."""
+    return _CLEAN_RE.sub(lambda m: re.sub(r"[^\n]", " ", m.group(0)), source)
+
+
+def package_of(source: str) -> str:
+    """The package a file declares, its chained clauses joined: ``com.acme.orders``; ``""`` for none."""
+    return ".".join(_PACKAGE_RE.findall(clean(source)))
+
+
+def _depths(text: str) -> list[int]:
+    """The bracket depth before each character of *text*."""
+    depths: list[int] = []
+    depth = 0
+    for char in text:
+        if char in _CLOSERS:
+            depth = max(depth - 1, 0)
+        depths.append(depth)
+        if char in _OPENERS:
+            depth += 1
+    return depths
+
+
+def _body_start(text: str) -> int:
+    """Offset where the body of a declaration header starts, or ``len(text)``."""
+    depths = _depths(text)
+    for index, char in enumerate(text):
+        if depths[index]:
+            continue
+        if char == "{":
+            return index
+        if char == "=" and text[index + 1 : index + 2] not in ("=", ">") and text[index - 1 : index] not in "=!<>":
+            return index
+    stripped = text.rstrip()
+    return len(stripped) - 1 if stripped.endswith(":") else len(text)
+
+
+def body_start_column(line: str) -> int | None:
+    """Column of the body a declaration on *line* opens (``def total = sum(items)``), or ``None``."""
+    cleaned = clean(line)
+    start = _body_start(cleaned)
+    return start if start < len(cleaned) else None
+
+
+def declaration_header(lines: list[str], start_line: int) -> str:
+    """The cleaned header of the declaration on *start_line* (0-based), up to its body."""
+    text = ""
+    for index in range(start_line, min(start_line + _MAX_HEADER_LINES, len(lines))):
+        text = f"{text} {clean(lines[index]).strip()}" if text else clean(lines[index]).strip()
+        if _body_start(text) < len(text):
+            break
+        open_depth = _depths(text + " ")[-1]
+        following = lines[index + 1] if index + 1 < len(lines) else ""
+        if not open_depth and not _CONTINUATION_RE.match(following):
+            break
+    return text[: _body_start(text)].strip()
+
+
+def _split_top_level(text: str, separator: re.Pattern[str]) -> list[str]:
+    """*text* split at each *separator* match outside brackets."""
+    depths = _depths(text)
+    parts: list[str] = []
+    start = 0
+    for match in separator.finditer(text):
+        if depths[match.start()] == 0:
+            parts.append(text[start : match.start()])
+            start = match.end()
+    parts.append(text[start:])
+    return [part.strip() for part in parts if part.strip()]
+
+
+def _top_level(pattern: re.Pattern[str], text: str) -> re.Match[str] | None:
+    depths = _depths(text)
+    return next((match for match in pattern.finditer(text) if depths[match.start()] == 0), None)
+
+
+def supertypes(header: str) -> list[str]:
+    """Short names of the supertypes a class, trait, object or enum header lists after ``extends``, in order."""
+    extends = _top_level(_EXTENDS_RE, header)
+    if extends is None:
+        return []
+    parents = header[extends.end() :]
+    derives = _top_level(_DERIVES_RE, parents)
+    if derives is not None:
+        parents = parents[: derives.start()]
+    names: list[str] = []
+    for part in _split_top_level(parents, _PARENT_SEPARATOR_RE):
+        name = _TYPE_NAME_RE.match(part)
+        if name is not None:
+            names.append(name.group(0).rsplit(".", 1)[-1])
+    return names
+
+
+def normalize_type(type_text: str) -> str:
+    """*type_text* without whitespace or a by-name ``=>``, so ``Ord[ Int ]`` and ``Ord[Int]`` compare equal."""
+    type_text = type_text.strip()
+    if type_text.startswith("=>"):
+        type_text = type_text[2:]
+    return re.sub(r"\s+", "", type_text)
+
+
+def type_head(type_text: str) -> str:
+    """The short name of the type constructor: ``Encoder`` for ``io.circe.Encoder[User]``."""
+    name = _TYPE_NAME_RE.match(normalize_type(type_text))
+    return name.group(0).rsplit(".", 1)[-1] if name is not None else ""
+
+
+def _parameter_type(parameter: str) -> str:
+    """The type of ``name: Type = default`` (or of an anonymous ``using Type``)."""
+    default = _top_level(_DEFAULT_RE, parameter)
+    if default is not None:
+        parameter = parameter[: default.start()]
+    colon = _top_level(_COLON_RE, parameter)
+    return normalize_type(parameter[colon.end() :] if colon is not None else parameter)
+
+
+def context_parameters(header: str) -> list[str]:
+    """Types of the ``using``/``implicit`` parameters a ``def``, ``class`` or ``given`` header declares."""
+    types: list[str] = []
+    depths = _depths(header)
+    for match in _CONTEXT_LIST_RE.finditer(header):
+        closers = (index for index in range(match.end(), len(header)) if header[index] == ")")
+        end = next((index for index in closers if depths[index] == depths[match.start()]), len(header))
+        for parameter in _split_top_level(header[match.end() : end], _COMMA_RE):
+            parameter_type = _parameter_type(parameter)
+            if parameter_type:
+                types.append(parameter_type)
+    return types
+
+
+def given_type(header: str) -> str | None:
+    """The type a ``given`` or implicit value/object header provides, or ``None`` if it provides none.
+
+    An ``implicit def`` with ordinary parameters is a conversion, not an
+    instance, and an ``implicit class`` adds methods; neither provides one.
+    """
+    given = _GIVEN_RE.search(header)
+    if given is not None:
+        declared = header[given.end() :]
+        body = _top_level(_WITH_RE, declared)
+        if body is not None:
+            declared = declared[: body.start()]
+        colon = _top_level(_COLON_RE, declared)
+        return normalize_type(declared[colon.end() :] if colon is not None else declared) or None
+    implicit = _IMPLICIT_VALUE_RE.search(header)
+    if implicit is None:
+        return None
+    declared = header[implicit.end() :]
+    if implicit.group(1) == "object":
+        parents = supertypes(declared)
+        return parents[0] if parents else None
+    if implicit.group(1) == "def" and _CONVERSION_RE.match(declared):
+        return None
+    colon = _top_level(_COLON_RE, declared)
+    return normalize_type(declared[colon.end() :]) or None if colon is not None else None
@@ -0,0 +1,364 @@
+"""Parse ``.proto`` and ``.graphql`` schema files into call-graph nodes and edges.
+
+Schemas define the API contract of a service but are not code any language
+server indexes, so they are parsed directly: services and types become
+class-like nodes, RPCs and root operation fields become methods, and the
+message/type names they reference become edges. The output uses the same
+analysis-dict shape ``StaticAnalyzer`` absorbs for LSP languages.
+"""
+
+import logging
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer.constants import LANGUAGE_EXTENSIONS, Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+logger = logging.getLogger(__name__)
+
+SCHEMA_LANGUAGES: tuple[Language, ...] = (Language.PROTOBUF, Language.GRAPHQL)
+
+_PROTO_COMMENT_RE = re.compile(r"//[^\n]*|/\*.*?\*/", re.DOTALL)
+_PROTO_STRING_RE = re.compile(r'"(?:\\.|[^"\\])*"' + r"|'(?:\\.|[^'\\])*'")
+_PROTO_PACKAGE_RE = re.compile(r"\bpackage\s+([\w.]+)\s*;")
+_PROTO_BLOCK_RE = re.compile(r"\b(message|enum|service)\s+(\w+)\s*\{")
+_PROTO_RPC_RE = re.compile(
+    r"\brpc\s+(\w+)\s*\(\s*(?:stream\s+)?([\w.]+)\s*\)\s*returns\s*\(\s*(?:stream\s+)?([\w.]+)\s*\)"
+)
+_PROTO_FIELD_RE = re.compile(
+    r"(?:^|[;{}])\s*(?:repeated\s+|optional\s+|required\s+)?"
+    r"(?:map\s*<\s*([\w.]+)\s*,\s*([\w.]+)\s*>|([\w.]+))\s+\w+\s*=\s*\d+"
+)
+_PROTO_SCALARS = frozenset(
+    {
+        "double",
+        "float",
+        "int32",
+        "int64",
+        "uint32",
+        "uint64",
+        "sint32",
+        "sint64",
+        "fixed32",
+        "fixed64",
+        "sfixed32",
+        "sfixed64",
+        "bool",
+        "string",
+        "bytes",
+    }
+)
+
+_GRAPHQL_COMMENT_RE = re.compile(r"#[^\n]*")
+_GRAPHQL_STRING_RE = re.compile(r'"""(?:.|\n)*?"""|"(?:\\.|[^"\\\n])*"')
+_GRAPHQL_BLOCK_RE = re.compile(
+    r"\b(?:extend\s+)?(type|interface|input|enum)\s+(\w+)"
+    r"(?:\s+implements\s+&?\s*([\w\s&]+?))?(?:\s+@\w+(?:\([^)]*\))?)*\s*\{"
+)
+_GRAPHQL_UNION_RE = re.compile(r"\b(?:extend\s+)?union\s+(\w+)(?:\s+@\w+(?:\([^)]*\))?)*\s*=\s*\|?\s*([\w\s|]+)")
+_GRAPHQL_SCALAR_RE = re.compile(r"\bscalar\s+(\w+)")
+_GRAPHQL_SCHEMA_RE = re.compile(r"\bschema(?:\s+@\w+(?:\([^)]*\))?)*\s*\{([^}]*)\}")
+_GRAPHQL_ROOT_OP_RE = re.compile(r"\b(query|mutation|subscription)\s*:\s*(\w+)")
+_GRAPHQL_FIELD_RE = re.compile(r"(\w+)\s*(\([^)]*\))?\s*:\s*\[*\s*(\w+)")
+_GRAPHQL_ARG_TYPE_RE = re.compile(r":\s*\[*\s*(\w+)")
+_GRAPHQL_BUILTIN_SCALARS = frozenset({"Int", "Float", "String", "Boolean", "ID"})
+_GRAPHQL_DEFAULT_ROOTS = frozenset({"Query", "Mutation", "Subscription"})
+
+_GRAPHQL_KIND: dict[str, NodeType] = {
+    "type": NodeType.CLASS,
+    "interface": NodeType.INTERFACE,
+    "input": NodeType.STRUCT,
+    "enum": NodeType.ENUM,
+}
+_PROTO_KIND: dict[str, NodeType] = {
+    "message": NodeType.STRUCT,
+    "enum": NodeType.ENUM,
+    "service": NodeType.INTERFACE,
+}
+
+
+@dataclass
+class _SchemaDefinition:
+    """One named schema definition plus the raw type names it references."""
+
+    qualified_name: str
+    kind: NodeType
+    file_path: Path
+    line_start: int
+    line_end: int
+    package: str
+    operations: list[tuple[str, int, list[str]]] = field(default_factory=list)
+    field_types: list[str] = field(default_factory=list)
+    supertypes: list[str] = field(default_factory=list)
+
+
+def _blank(match: re.Match) -> str:
+    """Replace a comment/string with spaces, keeping newlines so offsets still map to lines."""
+    return re.sub(r"[^\n]", " ", match.group(0))
+
+
+def _line_of(text: str, offset: int) -> int:
+    return text.count("\n", 0, offset) + 1
+
+
+def _matching_brace(text: str, open_idx: int) -> int:
+    """Return the index of the ``}`` closing the ``{`` at *open_idx* (or end of text)."""
+    depth = 0
+    for idx in range(open_idx, len(text)):
+        if text[idx] == "{":
+            depth += 1
+        elif text[idx] == "}":
+            depth -= 1
+            if depth == 0:
+                return idx
+    return len(text) - 1
+
+
+def _parse_proto(path: Path, text: str) -> list[_SchemaDefinition]:
+    text = _PROTO_STRING_RE.sub(_blank, _PROTO_COMMENT_RE.sub(_blank, text))
+    package_match = _PROTO_PACKAGE_RE.search(text)
+    package = package_match.group(1) if package_match else path.stem
+    definitions: list[_SchemaDefinition] = []
+    _parse_proto_blocks(path, text, 0, len(text), package, package, definitions)
+    return definitions
+
+
+def _parse_proto_blocks(
+    path: Path, text: str, start: int, end: int, prefix: str, package: str, out: list[_SchemaDefinition]
+) -> list[tuple[int, int]]:
+    """Collect message/enum/service blocks in ``text[start:end]``, recursing into nested messages.
+
+    Returns the ``(start, end)`` spans of the blocks found so a parent can skip them.
+    """
+    spans: list[tuple[int, int]] = []
+    pos = start
+    while True:
+        match = _PROTO_BLOCK_RE.search(text, pos, end)
+        if match is None:
+            return spans
+        keyword, name = match.group(1), match.group(2)
+        open_idx = match.end() - 1
+        close_idx = _matching_brace(text, open_idx)
+        spans.append((match.start(), close_idx + 1))
+        definition = _SchemaDefinition(
+            qualified_name=f"{prefix}.{name}",
+            kind=_PROTO_KIND[keyword],
+            file_path=path,
+            line_start=_line_of(text, match.start()),
+            line_end=_line_of(text, close_idx),
+            package=package,
+        )
+        out.append(definition)
+        body_start = open_idx + 1
+        if keyword == "service":
+            for rpc in _PROTO_RPC_RE.finditer(text, body_start, close_idx):
+                definition.operations.append((rpc.group(1), _line_of(text, rpc.start()), [rpc.group(2), rpc.group(3)]))
+        elif keyword == "message":
+            nested = _parse_proto_blocks(path, text, body_start, close_idx, definition.qualified_name, package, out)
+            # Why: nested blocks are skipped so their fields aren't attributed to the outer message.
+            body_parts, cursor = [], body_start
+            for nested_start, nested_end in nested:
+                body_parts.append(text[cursor:nested_start])
+                cursor = nested_end
+            body_parts.append(text[cursor:close_idx])
+            for field_match in _PROTO_FIELD_RE.finditer(";".join(body_parts)):
+                for type_name in field_match.groups():
+                    if type_name and type_name not in _PROTO_SCALARS:
+                        definition.field_types.append(type_name)
+        pos = close_idx + 1
+
+
+def _parse_graphql(path: Path, text: str) -> list[_SchemaDefinition]:
+    text = _GRAPHQL_COMMENT_RE.sub(_blank, _GRAPHQL_STRING_RE.sub(_blank, text))
+    package = path.stem
+    roots = set(_GRAPHQL_DEFAULT_ROOTS)
+    schema_match = _GRAPHQL_SCHEMA_RE.search(text)
+    if schema_match:
+        roots |= {op.group(2) for op in _GRAPHQL_ROOT_OP_RE.finditer(schema_match.group(1))}
+
+    definitions: list[_SchemaDefinition] = []
+    for match in _GRAPHQL_BLOCK_RE.finditer(text):
+        keyword, name, implements = match.group(1), match.group(2), match.group(3)
+        open_idx = match.end() - 1
+        close_idx = _matching_brace(text, open_idx)
+        definition = _SchemaDefinition(
+            qualified_name=f"{package}.{name}",
+            kind=_GRAPHQL_KIND[keyword],
+            file_path=path,
+            line_start=_line_of(text, match.start()),
+            line_end=_line_of(text, close_idx),
+            package=package,
+            supertypes=[t for t in re.split(r"[\s&]+", implements or "") if t],
+        )
+        if keyword != "enum":
+            body_start = open_idx + 1
+            for field_match in _GRAPHQL_FIELD_RE.finditer(text, body_start, close_idx):
+                field_name, args, return_type = field_match.groups()
+                arg_types = _GRAPHQL_ARG_TYPE_RE.findall(args or "")
+                referenced = [t for t in (*arg_types, return_type) if t not in _GRAPHQL_BUILTIN_SCALARS]
+                if keyword == "type" and name in roots:
+                    definition.operations.append((field_name, _line_of(text, field_match.start()), referenced))
+                else:
+                    definition.field_types.extend(referenced)
+        definitions.append(definition)
+
+    for match in _GRAPHQL_UNION_RE.finditer(text):
+        line = _line_of(text, match.start())
+        members = [t for t in re.split(r"[\s|]+", match.group(2)) if t]
+        definitions.append(
+            _SchemaDefinition(
+                qualified_name=f"{package}.{match.group(1)}",
+                kind=NodeType.INTERFACE,
+                file_path=path,
+                line_start=line,
+                line_end=line,
+                package=package,
+                field_types=members,
+            )
+        )
+    for match in _GRAPHQL_SCALAR_RE.finditer(text):
+        line = _line_of(text, match.start())
+        definitions.append(
+            _SchemaDefinition(
+                qualified_name=f"{package}.{match.group(1)}",
+                kind=NodeType.STRUCT,
+                file_path=path,
+                line_start=line,
+                line_end=line,
+                package=package,
+            )
+        )
+    return definitions
+
+
+def _resolve(type_name: str, scope: str, by_qname: dict[str, str], by_short: dict[str, list[str]]) -> str | None:
+    """Resolve a schema type reference from *scope* using protobuf-style innermost-first lookup."""
+    if type_name.startswith("."):
+        return type_name[1:] if type_name[1:] in by_qname else None
+    parts = scope.split(".")
+    for i in range(len(parts), 0, -1):
+        candidate = ".".join(parts[:i] + [type_name])
+        if candidate in by_qname:
+            return candidate
+    if type_name in by_qname:
+        return type_name
+    candidates = by_short.get(type_name.rsplit(".", 1)[-1], [])
+    return candidates[0] if len(candidates) == 1 else None
+
+
+def build_schema_analysis(language: Language, source_files: list[Path]) -> dict:
+    """Parse *source_files* of one schema language into the dict shape ``StaticAnalyzer`` absorbs."""
+    parse = _parse_proto if language == Language.PROTOBUF else _parse_graphql
+    definitions: list[_SchemaDefinition] = []
+    for path in source_files:
+        try:
+            text = path.read_text(encoding="utf-8", errors="replace")
+        except OSError as e:
+            logger.warning(f"Skipping unreadable schema file {path}: {e}")
+            continue
+        definitions.extend(parse(path, text))
+
+    call_graph = CallGraph(language=language)
+    by_qname: dict[str, str] = {}
+    by_short: dict[str, list[str]] = {}
+    for definition in definitions:
+        if definition.qualified_name in by_qname:
+            continue
+        by_qname[definition.qualified_name] = definition.package
+        by_short.setdefault(definition.qualified_name.rsplit(".", 1)[1], []).append(definition.qualified_name)
+        call_graph.add_node(
+            Node(
+                definition.qualified_name,
+                definition.kind,
+                str(definition.file_path),
+                definition.line_start,
+                definition.line_end,
+            )
+        )
+
+    class_hierarchies: dict[str, dict] = {}
+    package_relations: dict[str, dict] = {
+        pkg: {"imports": [], "imported_by": []} for pkg in sorted(set(by_qname.values()))
+    }
+
+    def link_packages(src: str, dst: str) -> None:
+        src_pkg, dst_pkg = by_qname[src], by_qname[dst]
+        if src_pkg == dst_pkg:
+            return
+        if dst_pkg not in package_relations[src_pkg]["imports"]:
+            package_relations[src_pkg]["imports"].append(dst_pkg)
+        if src_pkg not in package_relations[dst_pkg]["imported_by"]:
+            package_relations[dst_pkg]["imported_by"].append(src_pkg)
+
+    for definition in definitions:
+        owner = definition.qualified_name
+        for op_name, op_line, type_names in definition.operations:
+            op_qname = f"{owner}.{op_name}"
+            call_graph.add_node(Node(op_qname, NodeType.METHOD, str(definition.file_path), op_line, op_line))
+            by_qname.setdefault(op_qname, definition.package)
+            call_graph.add_reference_edge(owner, op_qname, EdgeKind.CONTAINS)
+            for type_name in type_names:
+                target = _resolve(type_name, owner, by_qname, by_short)
+                if target is not None and target != op_qname:
+                    call_graph.add_edge(op_qname, target)
+                    link_packages(op_qname, target)
+        for type_name in definition.field_types:
+            target = _resolve(type_name, owner, by_qname, by_short)
+            if target is not None:
+                call_graph.add_reference_edge(owner, target, EdgeKind.TYPEREF)
+                link_packages(owner, target)
+        supers = [s for s in (_resolve(t, owner, by_qname, by_short) for t in definition.supertypes) if s]
+        if supers:
+            for sup in supers:
+                call_graph.add_reference_edge(owner, sup, EdgeKind.INHERITS)
+        class_hierarchies.setdefault(
+            owner,
+            {
+                "superclasses": supers,
+                "subclasses": [],
+                "file_path": str(definition.file_path),
+                "line_start": definition.line_start,
+                "line_end": definition.line_end,
+            },
+        )
+    for owner, info in class_hierarchies.items():
+        for sup in info["superclasses"]:
+            if sup in class_hierarchies:
+                class_hierarchies[sup]["subclasses"].append(owner)
+
+    logger.info(
+        f"Schema analysis for {language}: {len(source_files)} files, "
+        f"{len(call_graph.nodes)} nodes, {len(call_graph.edges)} edges"
+    )
+    return {
+        "call_graph": call_graph,
+        "class_hierarchies": class_hierarchies,
+        "package_relations": package_relations,
+        "references": list(call_graph.nodes.values()),
+        "source_files": sorted(source_files),
+        "diagnostics": {},
+    }
+
+
+def discover_schema_files(repo_root: Path, ignore_manager: RepoIgnoreManager) -> dict[Language, list[Path]]:
+    """Return schema files under *repo_root* grouped by schema language, skipping ignored paths."""
+    suffix_to_language = {ext: lang for lang in SCHEMA_LANGUAGES for ext in LANGUAGE_EXTENSIONS[lang]}
+    found: dict[Language, list[Path]] = {}
+    pending = [repo_root.resolve()]
+    while pending:
+        directory = pending.pop()
+        try:
+            entries = sorted(directory.iterdir())
+        except PermissionError:
+            continue
+        for entry in entries:
+            if ignore_manager.should_ignore(entry):
+                continue
+            if entry.is_dir():
+                pending.append(entry)
+            elif entry.suffix in suffix_to_language and entry.is_file():
+                found.setdefault(suffix_to_language[entry.suffix], []).append(entry)
+    return {language: sorted(paths) for language, paths in found.items()}
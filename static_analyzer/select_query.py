@@ -0,0 +1,339 @@
+"""Declarative analysis scope (``--select``): predicates are documented in PYPI.md.
+
+Grammar (keywords are case-insensitive, ``not`` binds tightest, then ``and``, then ``or``)::
+
+    expr      := term ("or" term)*
+    term      := factor ("and" factor)*
+    factor    := "not" factor | "(" expr ")" | predicate
+    predicate := ("path" | "pkg" | "name" | "kind") "(" pattern ")"
+"""
+
+import logging
+import re
+from dataclasses import dataclass, field
+from fnmatch import fnmatchcase
+from pathlib import Path, PurePosixPath
+
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES, DATA_TYPES, NodeType
+from static_analyzer.go_source import go_module_path
+from static_analyzer.language_results import ControlFlowGraph, LanguageResults
+from static_analyzer.node import Node
+from static_analyzer.symbol_kinds import CanonicalKind, KindMap
+
+logger = logging.getLogger(__name__)
+
+PREDICATES = ("path", "pkg", "name", "kind")
+KIND_GROUPS: dict[str, frozenset[NodeType]] = {
+    "callable": frozenset(CALLABLE_TYPES),
+    "type": frozenset(CLASS_TYPES),
+    "data": frozenset(DATA_TYPES),
+}
+_KEYWORDS = ("and", "or", "not")
+_TOKEN_RE = re.compile(r"\s*(?:(?P<paren>[()])|(?P<word>[A-Za-z_]\w*))")
+
+
+class SelectQueryError(ValueError):
+    """A ``--select`` expression that does not parse; ``str()`` points at the offending column."""
+
+    def __init__(self, message: str, text: str, position: int) -> None:
+        self.message = message
+        self.text = text
+        self.position = position
+        super().__init__(f"{message} at column {position + 1}\n  {text}\n  {' ' * position}^")
+
+
+def glob_regex(pattern: str) -> re.Pattern:
+    """Path glob -> regex: ``*`` and ``?`` stay within one segment, ``**`` spans segments."""
+    out = []
+    i = 0
+    while i < len(pattern):
+        if pattern.startswith("**/", i):
+            out.append("(?:.*/)?")
+            i += 3
+        elif pattern.startswith("/**", i) and i + 3 == len(pattern):
+            out.append("(?:/.*)?")
+            i += 3
+        elif pattern.startswith("**", i):
+            out.append(".*")
+            i += 2
+        elif pattern[i] == "*":
+            out.append("[^/]*")
+            i += 1
+        elif pattern[i] == "?":
+            out.append("[^/]")
+            i += 1
+        else:
+            out.append(re.escape(pattern[i]))
+            i += 1
+    return re.compile(f"^{''.join(out)}$")
+
+
+@dataclass(frozen=True)
+class SelectedSymbol:
+    """The attributes of one call-graph node that predicates look at."""
+
+    path: str
+    package: str
+    name: str
+    qualified_name: str
+    kind: NodeType
+    canonical: CanonicalKind | None = None
+
+
+@dataclass(frozen=True)
+class _Predicate:
+    field: str
+    pattern: str
+    regex: re.Pattern | None = None
+    kinds: frozenset[NodeType] = frozenset()
+    canonical: CanonicalKind | None = None
+
+    def matches(self, symbol: SelectedSymbol) -> bool:
+        if self.field == "path":
+            return bool(self.regex and self.regex.match(symbol.path))
+        if self.field == "pkg":
+            return bool(self.regex and self.regex.match(symbol.package))
+        if self.field == "name":
+            return fnmatchcase(symbol.name, self.pattern) or fnmatchcase(symbol.qualified_name, self.pattern)
+        return symbol.kind in self.kinds or (self.canonical is not None and symbol.canonical == self.canonical)
+
+
+@dataclass(frozen=True)
+class _Not:
+    operand: "_Expr"
+
+    def matches(self, symbol: SelectedSymbol) -> bool:
+        return not self.operand.matches(symbol)
+
+
+@dataclass(frozen=True)
+class _And:
+    operands: tuple["_Expr", ...]
+
+    def matches(self, symbol: SelectedSymbol) -> bool:
+        return all(operand.matches(symbol) for operand in self.operands)
+
+
+@dataclass(frozen=True)
+class _Or:
+    operands: tuple["_Expr", ...]
+
+    def matches(self, symbol: SelectedSymbol) -> bool:
+        return any(operand.matches(symbol) for operand in self.operands)
+
+
+_Expr = _Predicate | _Not | _And | _Or
+
+
+def _kinds(name: str) -> frozenset[NodeType] | None:
+    if name in KIND_GROUPS:
+        return KIND_GROUPS[name]
+    try:
+        return frozenset({NodeType[name.upper()]})
+    except KeyError:
+        return None
+
+
+class _Parser:
+    """Recursive-descent parser over the raw text; positions are kept for error carets."""
+
+    def __init__(self, text: str) -> None:
+        self.text = text
+        self.pos = 0
+
+    def error(self, message: str, position: int | None = None) -> SelectQueryError:
+        return SelectQueryError(message, self.text, self.pos if position is None else position)
+
+    def skip_space(self) -> None:
+        while self.pos < len(self.text) and self.text[self.pos].isspace():
+            self.pos += 1
+
+    def peek(self) -> tuple[str, str, int] | None:
+        """The next token as ``(kind, value, start)`` without consuming it."""
+        self.skip_space()
+        if self.pos >= len(self.text):
+            return None
+        match = _TOKEN_RE.match(self.text, self.pos)
+        if match is None:
+            raise self.error(f"unexpected {self.text[self.pos]!r}")
+        if match.group("paren"):
+            return "paren", match.group("paren"), match.start("paren")
+        return "word", match.group("word"), match.start("word")
+
+    def advance(self) -> tuple[str, str, int]:
+        token = self.peek()
+        assert token is not None
+        self.pos = token[2] + len(token[1])
+        return token
+
+    def at_keyword(self, keyword: str) -> bool:
+        token = self.peek()
+        return token is not None and token[0] == "word" and token[1].lower() == keyword
+
+    def parse(self) -> _Expr:
+        if not self.text.strip():
+            raise self.error("empty expression", 0)
+        expr = self.parse_or()
+        if (token := self.peek()) is not None:
+            raise self.error(f"expected 'and', 'or' or the end of the expression, found {token[1]!r}", token[2])
+        return expr
+
+    def parse_or(self) -> _Expr:
+        operands = [self.parse_and()]
+        while self.at_keyword("or"):
+            self.advance()
+            operands.append(self.parse_and())
+        return operands[0] if len(operands) == 1 else _Or(tuple(operands))
+
+    def parse_and(self) -> _Expr:
+        operands = [self.parse_factor()]
+        while self.at_keyword("and"):
+            self.advance()
+            operands.append(self.parse_factor())
+        return operands[0] if len(operands) == 1 else _And(tuple(operands))
+
+    def parse_factor(self) -> _Expr:
+        token = self.peek()
+        if token is None:
+            raise self.error("expected a predicate, 'not' or '(' but the expression ended")
+        kind, value, start = token
+        if kind == "paren":
+            if value == ")":
+                raise self.error("expected a predicate, 'not' or '(' but found ')'", start)
+            self.advance()
+            expr = self.parse_or()
+            closing = self.peek()
+            if closing is None or closing[1] != ")":
+                raise self.error("expected ')' to close the group opened", start)
+            self.advance()
+            return expr
+        if value.lower() == "not":
+            self.advance()
+            return _Not(self.parse_factor())
+        return self.parse_predicate()
+
+    def parse_predicate(self) -> _Predicate:
+        _, name, start = self.advance()
+        predicate = name.lower()
+        if predicate in _KEYWORDS:
+            raise self.error(f"expected a predicate before {name!r}", start)
+        if predicate not in PREDICATES:
+            raise self.error(f"unknown predicate {name!r}; choose from {', '.join(PREDICATES)}", start)
+        self.skip_space()
+        if not self.text.startswith("(", self.pos):
+            raise self.error(f"expected '(' after {name}")
+        close = self.text.find(")", self.pos + 1)
+        if close == -1:
+            raise self.error(f"unclosed '(' in {name}(...)")
+        pattern = self.text[self.pos + 1 : close].strip()
+        if not pattern:
+            raise self.error(f"{name}() needs a pattern", self.pos + 1)
+        pattern_start = self.pos + 1
+        self.pos = close + 1
+        if predicate == "kind":
+            kinds = _kinds(pattern.lower())
+            canonical = next((kind for kind in CanonicalKind if kind.value == pattern.lower()), None)
+            if kinds is None and canonical is None:
+                names = dict.fromkeys([*KIND_GROUPS, *CanonicalKind, *(kind.name.lower() for kind in NodeType)])
+                raise self.error(f"unknown kind {pattern!r}; choose from {', '.join(names)}", pattern_start)
+            return _Predicate(predicate, pattern, kinds=kinds or frozenset(), canonical=canonical)
+        if predicate in ("path", "pkg"):
+            return _Predicate(predicate, pattern, regex=glob_regex(pattern.strip("/") or pattern))
+        return _Predicate(predicate, pattern)
+
+
+@dataclass(frozen=True)
+class SelectQuery:
+    """A parsed ``--select`` expression."""
+
+    text: str
+    root: _Expr = field(repr=False)
+
+    @classmethod
+    def parse(cls, text: str) -> "SelectQuery":
+        return cls(text=text, root=_Parser(text).parse())
+
+    def matches(self, symbol: SelectedSymbol) -> bool:
+        return self.root.matches(symbol)
+
+
+class _PackageResolver:
+    """Package of a repo-relative file: Go import path under a ``go.mod``, else its directory."""
+
+    def __init__(self, repo_path: Path) -> None:
+        self.repo_path = repo_path
+        self._modules: dict[PurePosixPath, tuple[PurePosixPath, str] | None] = {}
+
+    def _module(self, directory: PurePosixPath) -> tuple[PurePosixPath, str] | None:
+        if directory not in self._modules:
+            module_path = go_module_path(self.repo_path / directory / "go.mod")
+            if module_path is not None:
+                self._modules[directory] = (directory, module_path)
+            elif directory == directory.parent:
+                self._modules[directory] = None
+            else:
+                self._modules[directory] = self._module(directory.parent)
+        return self._modules[directory]
+
+    def package(self, rel_path: str) -> str:
+        directory = PurePosixPath(rel_path).parent
+        if rel_path.endswith(".go") and (module := self._module(directory)) is not None:
+            module_dir, module_path = module
+            below = directory.relative_to(module_dir).as_posix()
+            return module_path if below == "." else f"{module_path}/{below}"
+        return "" if directory.as_posix() == "." else directory.as_posix()
+
+
+def _symbol(
+    node: Node, repo_path: Path, packages: _PackageResolver, kind_map: KindMap, language: str
+) -> SelectedSymbol:
+    path = Path(node.file_path)
+    if path.is_absolute() and path.is_relative_to(repo_path):
+        path = path.relative_to(repo_path)
+    rel_path = PurePosixPath(path).as_posix()
+    name = node.fully_qualified_name
+    return SelectedSymbol(
+        path=rel_path,
+        package=packages.package(rel_path),
+        name=name.rsplit(".", 1)[-1],
+        qualified_name=name,
+        kind=node.type,
+        canonical=kind_map.canonical(node.type, language),
+    )
+
+
+def apply_select_query(
+    static_analysis: StaticAnalysisResults, query: SelectQuery, repo_path: Path, kind_map: KindMap | None = None
+) -> StaticAnalysisResults:
+    """A view of *static_analysis* whose call graphs keep only the symbols *query* selects.
+
+    Only the call graphs are narrowed; references, hierarchies and source files
+    are shared with the input, which is left untouched so the static-analysis
+    cache still holds the whole repository.
+    """
+    packages = _PackageResolver(repo_path)
+    kind_map = kind_map or KindMap()
+    selected = StaticAnalysisResults(
+        diagnostics=static_analysis.diagnostics,
+        incremental_base_results=static_analysis.incremental_base_results,
+    )
+    for language, bucket in static_analysis.results.items():
+        cfg = ControlFlowGraph()
+        if bucket.cfg.graph is not None:
+            graph = bucket.cfg.graph
+            cfg.graph = graph.filter(
+                lambda node: query.matches(_symbol(node, repo_path, packages, kind_map, language)), lambda edge: None
+            )
+            kept = len(cfg.graph.nodes)
+            logger.info(f"--select {query.text!r}: kept {kept} of {len(graph.nodes)} {language} symbols")
+            if graph.nodes and not kept:
+                logger.warning(f"--select {query.text!r} matches no {language} symbols")
+        selected.results[language] = LanguageResults(
+            cfg=cfg,
+            hierarchy=bucket.hierarchy,
+            references=bucket.references,
+            dependencies=bucket.dependencies,
+            source_files=bucket.source_files,
+        )
+    return selected
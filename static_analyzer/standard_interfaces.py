@@ -0,0 +1,183 @@
+"""Well-known standard-library interfaces a type satisfies (``[standard_interfaces]``).
+
+``func (e Entity) String() string`` makes ``Entity`` a ``fmt.Stringer``, but the
+interface lives in the standard library, outside the analysed project, so no
+edge links the two. This pass matches the method set of every type, promoted
+methods included, against a per-language list of standard interfaces and
+records the ones it satisfies on the type's node (``Node.standard_interfaces``).
+The abstraction prompt states them ("``models.base.Entity`` is a fmt.Stringer")
+and the class diagram draws them as a stereotype.
+
+A method is written as Go declares it, ``Read([]byte) (int, error)``. It
+matches on its parameter and result types when the analysis read the method's
+signature (Go) and on its name otherwise; a bare name matches by name only. A
+project adds interfaces, or drops a default one with an empty list, per
+language::
+
+    [standard_interfaces]
+    go = { "json.Marshaler" = ["MarshalJSON() ([]byte, error)"], "sort.Interface" = [] }
+    python = { "Sized" = ["__len__"] }
+"""
+
+import logging
+import re
+from collections.abc import Iterable, Mapping
+from dataclasses import dataclass, field
+
+from project_config import ProjectConfig
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import CALLABLE_TYPES, CLASS_TYPES, Language, NodeType
+from static_analyzer.go_embedding import Promotions
+from static_analyzer.go_signatures import parse_signature
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node, Parameter
+
+logger = logging.getLogger(__name__)
+
+_METHOD_NAME_RE = re.compile(r"^\s*([A-Za-z_]\w*)\s*")
+
+
+@dataclass(frozen=True)
+class StandardInterface:
+    # As the language spells it where it is used: ``fmt.Stringer``, ``error``.
+    name: str
+    # ``String() string``, or a bare method name.
+    methods: tuple[str, ...]
+
+
+DEFAULT_STANDARD_INTERFACES: dict[Language, tuple[StandardInterface, ...]] = {
+    Language.GO: (
+        StandardInterface("fmt.Stringer", ("String() string",)),
+        StandardInterface("error", ("Error() string",)),
+        StandardInterface("io.Reader", ("Read([]byte) (int, error)",)),
+        StandardInterface("io.Writer", ("Write([]byte) (int, error)",)),
+        StandardInterface("sort.Interface", ("Len() int", "Less(int, int) bool", "Swap(int, int)")),
+    ),
+    Language.PYTHON: (
+        StandardInterface("Iterator", ("__iter__", "__next__")),
+        StandardInterface("ContextManager", ("__enter__", "__exit__")),
+    ),
+}
+
+
+def load_standard_interfaces(project_config: ProjectConfig) -> dict[Language, tuple[StandardInterface, ...]]:
+    """:data:`DEFAULT_STANDARD_INTERFACES` layered with the project's ``[standard_interfaces]`` table."""
+    tables = {
+        language: {iface.name: iface for iface in interfaces}
+        for language, interfaces in DEFAULT_STANDARD_INTERFACES.items()
+    }
+    for key, table in project_config.section("standard_interfaces").items():
+        try:
+            language = Language(str(key).lower())
+        except ValueError:
+            logger.warning(f"Ignoring [standard_interfaces] {key}: not a language ({', '.join(Language)})")
+            continue
+        if not isinstance(table, Mapping):
+            logger.warning(f"Ignoring [standard_interfaces] {key}: expected a table of interface = [methods]")
+            continue
+        interfaces = tables.setdefault(language, {})
+        for name, methods in table.items():
+            if isinstance(methods, str):
+                methods = [methods]
+            if not isinstance(methods, list) or not all(isinstance(method, str) for method in methods):
+                logger.warning(f"Ignoring [standard_interfaces] {key}.{name}: expected a list of methods")
+            elif methods:
+                interfaces[str(name)] = StandardInterface(str(name), tuple(methods))
+            else:
+                interfaces.pop(str(name), None)
+    return {language: tuple(interfaces.values()) for language, interfaces in tables.items() if interfaces}
+
+
+def _types(parameters: Iterable[Parameter]) -> tuple[str, ...]:
+    return tuple("".join(parameter.type_str.split()) for parameter in parameters)
+
+
+def method_matches(spec: str, node: Node) -> bool:
+    """Whether the method *node* has the signature *spec* asks for; its name is already known to match."""
+    match = _METHOD_NAME_RE.match(spec)
+    if match is None or not spec[match.end() :].strip() or node.signature is None:
+        return True
+    expected = parse_signature(f"func {spec}", match.group(1))
+    if expected is None:
+        return True
+    actual = node.signature
+    same_parameters = _types(expected.parameters) == _types(actual.parameters)
+    return same_parameters and _types(expected.results) == _types(actual.results)
+
+
+def _method_name(spec: str) -> str:
+    match = _METHOD_NAME_RE.match(spec)
+    return match.group(1) if match is not None else spec.strip()
+
+
+def tag_standard_interfaces(
+    call_graph: CallGraph, members: Iterable[Node], interfaces: Iterable[StandardInterface]
+) -> dict[str, tuple[str, ...]]:
+    """Set ``standard_interfaces`` on every concrete type of *call_graph*; returns the non-empty tags by type."""
+    interfaces = tuple(interfaces)
+    nodes: dict[str, Node] = {}
+    for node in [*call_graph.nodes.values(), *members]:
+        nodes.setdefault(node.fully_qualified_name, node)
+    # Go methods hang off their receiver (``pkg.file.(*Entity).String``), possibly promoted from an embedded type.
+    promotions = Promotions(call_graph) if call_graph.language == Language.GO else None
+
+    def method(type_qname: str, name: str) -> Node | None:
+        if promotions is not None:
+            qname = promotions.method(type_qname, name)
+        else:
+            qname = f"{type_qname}.{name}"
+        node = nodes.get(qname) if qname is not None else None
+        return node if node is not None and node.type in CALLABLE_TYPES else None
+
+    tags: dict[str, tuple[str, ...]] = {}
+    for qname, node in nodes.items():
+        if node.type not in CLASS_TYPES or node.type == NodeType.INTERFACE:
+            continue
+        satisfied = []
+        for iface in interfaces:
+            found = [(spec, method(qname, _method_name(spec))) for spec in iface.methods]
+            if all(target is not None and method_matches(spec, target) for spec, target in found):
+                satisfied.append(iface.name)
+        if satisfied or node.standard_interfaces:
+            node.standard_interfaces = tuple(satisfied)
+        if satisfied:
+            tags[qname] = tuple(satisfied)
+    if tags:
+        logger.info(f"Standard interfaces ({call_graph.language}): tagged {len(tags)} types")
+    return tags
+
+
+@dataclass
+class StandardInterfaceTags:
+    """The standard interfaces each type satisfies, by qualified name, for the abstraction prompt."""
+
+    tags: dict[str, tuple[str, ...]] = field(default_factory=dict)
+
+    def __bool__(self) -> bool:
+        return bool(self.tags)
+
+    def llm_str(self, limit: int = 50) -> str:
+        names = sorted(self.tags)
+        listed = "\n".join(f"- `{name}` is a {', '.join(self.tags[name])}" for name in names[:limit])
+        more = f"\n- ... and {len(names) - limit} more" if len(names) > limit else ""
+        return (
+            "\n\n## Standard Interfaces\n"
+            "These types satisfy well-known standard-library interfaces:\n"
+            f"{listed}{more}\n"
+            "Mention the interface where it explains a component's role (a Stringer for display, an io.Reader "
+            "for streaming input), rather than listing the methods that implement it.\n"
+        )
+
+
+def collect_standard_interface_tags(static_analysis: StaticAnalysisResults) -> StandardInterfaceTags:
+    """The tags the analysis recorded on its type nodes, in every language."""
+    tags: dict[str, tuple[str, ...]] = {}
+    for language in static_analysis.get_languages():
+        try:
+            nodes = static_analysis.get_cfg(language).nodes.values()
+        except ValueError:
+            continue
+        for node in [*nodes, *static_analysis.iter_reference_nodes(language)]:
+            if node.standard_interfaces:
+                tags[node.fully_qualified_name] = node.standard_interfaces
+    return StandardInterfaceTags(tags)
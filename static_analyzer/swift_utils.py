@@ -0,0 +1,27 @@
+import logging
+import platform
+import shutil
+import subprocess
+
+logger = logging.getLogger(__name__)
+
+SOURCEKIT_LSP = "sourcekit-lsp"
+
+
+def find_sourcekit_lsp() -> str | None:
+    """Path of ``sourcekit-lsp``: on PATH, or inside the active Xcode toolchain on macOS.
+
+    The server ships with every Swift toolchain rather than as a download;
+    Xcode installs it without putting it on PATH, where ``xcrun`` finds it.
+    """
+    found = shutil.which(SOURCEKIT_LSP)
+    if found is not None or platform.system() != "Darwin":
+        return found
+    try:
+        result = subprocess.run(
+            ["xcrun", "--find", SOURCEKIT_LSP], check=True, capture_output=True, text=True, timeout=30
+        )
+    except (subprocess.SubprocessError, OSError) as e:
+        logger.debug(f"xcrun could not find {SOURCEKIT_LSP}: {e}")
+        return None
+    return result.stdout.strip() or None
@@ -0,0 +1,183 @@
+"""Canonical symbol kinds shared by every language (``[symbol_kinds]``).
+
+Language servers name the same idea differently: gopls reports a Go struct as
+``Struct`` where TypeScript and Python report ``Class``, Java and C# have
+constructors where Python has plain methods, and pyright reports a
+``@property`` as ``Property``. :class:`KindMap` folds each LSP ``SymbolKind``
+into one :class:`CanonicalKind`, so ``--select kind(...)``, the ``kind`` of
+every entry in the ``methods_index`` of ``analysis.json`` and the labels in
+the generated docs read the same whatever the source language.
+
+The mapping is layered: :data:`DEFAULT_KIND_MAP`, then the language adapter's
+``symbol_kind_overrides``, then the project's own table, where a plain key
+applies to every language and a sub-table to one::
+
+    [symbol_kinds]
+    event = "variable"
+    go = { class = "type" }
+    python.property = "method"
+
+Keys are LSP kind names (``struct``, ``enum_member``, ...), values canonical
+kinds.
+"""
+
+import logging
+from collections.abc import Iterable, Mapping
+from enum import StrEnum
+from pathlib import PurePosixPath
+
+from agents.agent_responses import AnalysisInsights
+from project_config import ProjectConfig
+from static_analyzer.constants import SOURCE_EXTENSION_TO_LANGUAGE, Language, NodeType
+from static_analyzer.engine.adapters import get_all_adapters
+
+logger = logging.getLogger(__name__)
+
+
+class CanonicalKind(StrEnum):
+    TYPE = "type"
+    FUNCTION = "function"
+    METHOD = "method"
+    CONSTANT = "constant"
+    VARIABLE = "variable"
+    INTERFACE = "interface"
+    ENUM = "enum"
+    MODULE = "module"
+
+    def label(self) -> str:
+        """Return a human-readable label (e.g. ``'Type'``)."""
+        return self.value.capitalize()
+
+
+DEFAULT_KIND_MAP: dict[NodeType, CanonicalKind] = {
+    NodeType.FILE: CanonicalKind.MODULE,
+    NodeType.MODULE: CanonicalKind.MODULE,
+    NodeType.NAMESPACE: CanonicalKind.MODULE,
+    NodeType.PACKAGE: CanonicalKind.MODULE,
+    NodeType.CLASS: CanonicalKind.TYPE,
+    NodeType.METHOD: CanonicalKind.METHOD,
+    NodeType.PROPERTY: CanonicalKind.VARIABLE,
+    NodeType.FIELD: CanonicalKind.VARIABLE,
+    NodeType.CONSTRUCTOR: CanonicalKind.METHOD,
+    NodeType.ENUM: CanonicalKind.ENUM,
+    NodeType.INTERFACE: CanonicalKind.INTERFACE,
+    NodeType.FUNCTION: CanonicalKind.FUNCTION,
+    NodeType.VARIABLE: CanonicalKind.VARIABLE,
+    NodeType.CONSTANT: CanonicalKind.CONSTANT,
+    NodeType.STRING: CanonicalKind.CONSTANT,
+    NodeType.NUMBER: CanonicalKind.CONSTANT,
+    NodeType.BOOLEAN: CanonicalKind.CONSTANT,
+    NodeType.ARRAY: CanonicalKind.CONSTANT,
+    NodeType.OBJECT: CanonicalKind.TYPE,
+    NodeType.KEY: CanonicalKind.VARIABLE,
+    NodeType.NULL: CanonicalKind.CONSTANT,
+    NodeType.ENUM_MEMBER: CanonicalKind.CONSTANT,
+    NodeType.STRUCT: CanonicalKind.TYPE,
+    NodeType.EVENT: CanonicalKind.VARIABLE,
+    NodeType.OPERATOR: CanonicalKind.METHOD,
+    NodeType.TYPE_PARAMETER: CanonicalKind.TYPE,
+}
+
+# Same invariant as ``LANGUAGE_EXTENSIONS``: every SymbolKind has a canonical kind.
+assert set(DEFAULT_KIND_MAP) == set(NodeType), f"DEFAULT_KIND_MAP missing: {set(NodeType) - set(DEFAULT_KIND_MAP)}"
+
+
+def _adapter_overrides() -> dict[Language, dict[NodeType, CanonicalKind]]:
+    return {
+        adapter.language_enum: {node_type: CanonicalKind(kind) for node_type, kind in overrides.items()}
+        for adapter in get_all_adapters().values()
+        if (overrides := adapter.symbol_kind_overrides)
+    }
+
+
+class KindMap:
+    """Native ``NodeType`` (plus the symbol's language) -> :class:`CanonicalKind`."""
+
+    def __init__(
+        self,
+        overrides: Mapping[Language | None, Mapping[NodeType, CanonicalKind]] | None = None,
+        adapter_overrides: Mapping[Language, Mapping[NodeType, CanonicalKind]] | None = None,
+    ) -> None:
+        overrides = overrides or {}
+        adapter_overrides = _adapter_overrides() if adapter_overrides is None else adapter_overrides
+        self._global = {**DEFAULT_KIND_MAP, **overrides.get(None, {})}
+        self._by_language: dict[Language, dict[NodeType, CanonicalKind]] = {
+            language: {
+                **DEFAULT_KIND_MAP,
+                **adapter_overrides.get(language, {}),
+                **overrides.get(None, {}),
+                **overrides.get(language, {}),
+            }
+            for language in Language
+        }
+
+    def canonical(self, node_type: NodeType | int, language: Language | str | None = None) -> CanonicalKind:
+        try:
+            table = self._by_language[Language(language)] if language else self._global
+        except ValueError:
+            table = self._global
+        return table[NodeType(node_type)]
+
+
+def language_of(file_path: str) -> Language | None:
+    return SOURCE_EXTENSION_TO_LANGUAGE.get(PurePosixPath(file_path).suffix.lower())
+
+
+def _parse_node_type(name: str) -> NodeType | None:
+    try:
+        return NodeType[name.strip().upper().replace("-", "_")]
+    except KeyError:
+        return None
+
+
+def _parse_table(table: Mapping, where: str) -> dict[NodeType, CanonicalKind]:
+    parsed: dict[NodeType, CanonicalKind] = {}
+    for key, value in table.items():
+        node_type = _parse_node_type(str(key))
+        if node_type is None:
+            logger.warning(f"Ignoring [symbol_kinds] {where}{key}: not an LSP symbol kind")
+            continue
+        try:
+            parsed[node_type] = CanonicalKind(str(value).strip().lower())
+        except ValueError:
+            choices = ", ".join(kind.value for kind in CanonicalKind)
+            logger.warning(f"Ignoring [symbol_kinds] {where}{key} = {value!r}: choose from {choices}")
+    return parsed
+
+
+def load_kind_map(project_config: ProjectConfig) -> KindMap:
+    """Build the project's :class:`KindMap` from its ``[symbol_kinds]`` table."""
+    overrides: dict[Language | None, dict[NodeType, CanonicalKind]] = {}
+    section = project_config.section("symbol_kinds")
+    overrides[None] = _parse_table({k: v for k, v in section.items() if not isinstance(v, Mapping)}, "")
+    for key, table in section.items():
+        if not isinstance(table, Mapping):
+            continue
+        try:
+            language = Language(str(key).lower())
+        except ValueError:
+            logger.warning(f"Ignoring [symbol_kinds] {key}: not a language ({', '.join(Language)})")
+            continue
+        overrides[language] = _parse_table(table, f"{key}.")
+    return KindMap(overrides)
+
+
+def kind_label(node_type: str, kind: str = "") -> str:
+    """Doc label for a method entry: its canonical kind, or the LSP kind for entries saved without one."""
+    try:
+        return CanonicalKind(kind).label()
+    except ValueError:
+        return NodeType.from_name(node_type).label()
+
+
+def assign_method_kinds(analyses: Iterable[AnalysisInsights], kind_map: KindMap) -> None:
+    """Set the canonical ``kind`` of every indexed method, from its LSP kind and its file's language."""
+    for analysis in analyses:
+        groups = [(path, entry.methods) for path, entry in analysis.files.items()]
+        groups += [(group.file_path, group.methods) for c in analysis.components for group in c.file_methods]
+        for file_path, methods in groups:
+            language = language_of(file_path)
+            for method in methods:
+                node_type = _parse_node_type(method.node_type)
+                if node_type is not None:
+                    method.kind = kind_map.canonical(node_type, language).value
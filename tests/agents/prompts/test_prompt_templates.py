@@ -0,0 +1,74 @@
+from pathlib import Path
+
+import pytest
+
+from agents.prompts.prompt_templates import (
+    DEFAULT_TEMPLATES_DIR,
+    PromptTemplateError,
+    PromptTemplates,
+    edge_summary,
+    languages_str,
+    prompt_templates,
+)
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+
+def test_shipped_templates_load():
+    templates = PromptTemplates.load(DEFAULT_TEMPLATES_DIR)
+
+    assert templates.system is not None and "{project_name}" in templates.system
+    assert templates.component is not None and "{cluster_analysis}" in templates.component
+
+
+def test_a_missing_file_keeps_the_built_in_prompt(tmp_path: Path):
+    (tmp_path / "system.md").write_text("Document {project_name} ({language}).")
+
+    templates = PromptTemplates.load(tmp_path)
+
+    assert templates.component is None
+    assert templates.system.format(project_name="shop", language="go") == "Document shop (go)."
+
+
+@pytest.mark.parametrize(
+    "name, text, message",
+    [
+        ("system.md", "About {project}.", "unknown variable"),
+        ("component.md", "Describe {component_name}.", r"must include \{cluster_analysis\}"),
+        ("component.md", "Return } for {cluster_analysis}", "literal braces"),
+    ],
+)
+def test_invalid_templates_are_rejected(tmp_path: Path, name: str, text: str, message: str):
+    (tmp_path / name).write_text(text)
+
+    with pytest.raises(PromptTemplateError, match=message):
+        PromptTemplates.load(tmp_path)
+
+
+def test_a_directory_without_templates_is_rejected(tmp_path: Path):
+    with pytest.raises(PromptTemplateError, match="has neither"):
+        PromptTemplates.load(tmp_path)
+    with pytest.raises(PromptTemplateError, match="not a directory"):
+        PromptTemplates.load(tmp_path / "missing")
+
+
+def test_prompt_templates_of_the_run_directory(tmp_path: Path):
+    (tmp_path / "component.md").write_text("{cluster_analysis}")
+
+    assert prompt_templates(None) == PromptTemplates()
+    assert prompt_templates(tmp_path).component == "{cluster_analysis}"
+
+
+def test_languages_and_edge_summary():
+    cfg = CallGraph(language="go")
+    for line, name in enumerate(("a", "b", "c"), start=1):
+        cfg.add_node(Node(name, NodeType.FUNCTION, "x.go", line, line))
+    cfg.add_edge("a", "c")
+    cfg.add_edge("b", "c")
+    cfg.add_edge("c", "c")
+
+    assert languages_str(["python", "go", "go"]) == "go, python"
+    assert languages_str([]) == "unknown"
+    assert edge_summary({"go": cfg}) == "3 calls between 3 functions; most called (callers): c (2)."
+    assert edge_summary({}) == "No calls between the 0 functions of this component."
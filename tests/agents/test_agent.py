@@ -1,6 +1,7 @@
 import os
 import shutil
 import tempfile
+import time
 import unittest
 from pathlib import Path
 from unittest.mock import Mock, MagicMock, patch
@@ -140,6 +141,33 @@ class TestCodeBoardingAgent(unittest.TestCase):
         self.assertEqual(result.value, "repaired")
         self.assertEqual(events, ["repair", "validate"])
 
+    @patch("agents.agent.create_agent")
+    def test_streamed_answer_times_out_only_when_the_stream_stalls(self, mock_create_agent):
+        mock_agent_executor = Mock()
+        mock_create_agent.return_value = mock_agent_executor
+        agent = CodeBoardingAgent(
+            repo_dir=self.repo_dir,
+            static_analysis=self.mock_analysis,
+            system_message="Test",
+            agent_llm=self.mock_llm,
+            parsing_llm=Mock(spec=BaseChatModel),
+        )
+
+        def streaming(_inputs, config):
+            activity = config["callbacks"][-1]
+            for token in ("a", "b", "c", "d"):
+                time.sleep(0.4)
+                activity.on_llm_new_token(token)
+            return {"messages": [AIMessage(content="abcd")]}
+
+        mock_agent_executor.invoke.side_effect = streaming
+        response = agent._invoke_with_timeout(timeout_seconds=1, callback_list=[], prompt="p")
+        self.assertEqual(response["messages"][-1].content, "abcd")
+
+        mock_agent_executor.invoke.side_effect = lambda _inputs, config: time.sleep(2)
+        with self.assertRaises(TimeoutError):
+            agent._invoke_with_timeout(timeout_seconds=1, callback_list=[], prompt="p")
+
     @patch("agents.agent.create_agent")
     def test_invoke_success(self, mock_create_agent):
         # Test successful invocation
@@ -490,6 +518,35 @@ class TestCodeBoardingAgent(unittest.TestCase):
         self.assertEqual(result.value, "repaired")
         repair.assert_called_once()
 
+    @patch("agents.agent.create_extractor")
+    @patch("agents.agent.create_agent")
+    def test_parse_response_prefers_native_structured_output(self, mock_create_agent, mock_create_extractor):
+        mock_create_agent.return_value = Mock()
+        mock_parsing_llm = Mock(spec=BaseChatModel)
+        structured = Mock()
+        structured.invoke.side_effect = [{"value": "native"}, RuntimeError("response_format is not supported")]
+        mock_parsing_llm.with_structured_output.return_value = structured
+        agent = CodeBoardingAgent(
+            repo_dir=self.repo_dir,
+            static_analysis=self.mock_analysis,
+            system_message="Test",
+            agent_llm=self.mock_llm,
+            parsing_llm=mock_parsing_llm,
+        )
+
+        with patch("agents.agent.structured_output_method", return_value="json_schema"):
+            result = agent._parse_response("Test prompt", "The value is native.", TestResponse)
+            self.assertEqual(result, TestResponse(value="native"))
+            mock_parsing_llm.with_structured_output.assert_called_once_with(TestResponse, method="json_schema")
+            mock_create_extractor.assert_not_called()
+
+            # The provider rejecting the mode switches this agent to text parsing for good.
+            with patch.object(agent, "_structured_parse", return_value=TestResponse(value="text")) as text_parse:
+                self.assertEqual(agent._parse_response("Test prompt", "x", TestResponse).value, "text")
+                self.assertEqual(agent._parse_response("Test prompt", "y", TestResponse).value, "text")
+            self.assertEqual(text_parse.call_count, 2)
+            self.assertEqual(structured.invoke.call_count, 2)
+
     @patch("agents.agent.create_extractor")
     @patch("agents.agent.create_agent")
     def test_parse_response_empty_raises(self, mock_create_agent, mock_create_extractor):
@@ -565,6 +622,74 @@ class TestCodeBoardingAgent(unittest.TestCase):
         self.assertEqual(mock_agent_executor.invoke.call_count, 1)
         mock_sleep.assert_not_called()
 
+    @patch("agents.agent.create_agent")
+    @patch("time.sleep")
+    def test_too_large_prompt_is_split_until_each_batch_fits(self, mock_sleep, mock_create_agent):
+        """A token-limit error reruns each half of the batch; a single item that still does not fit raises."""
+        from agents.llm_errors import LLMRequestTooLargeError
+
+        class _TooLargeError(Exception):
+            status_code = 429
+
+        def answer(payload, **_kwargs):
+            prompt = payload["messages"][-1].content
+            if len(prompt.split()) > 2 or "huge" in prompt.split():
+                raise _TooLargeError("Error code: 429 - Request too large for gpt-4o on tokens per min (TPM)")
+            return {"messages": [AIMessage(content=prompt)]}
+
+        mock_agent_executor = Mock()
+        mock_agent_executor.invoke.side_effect = answer
+        mock_create_agent.return_value = mock_agent_executor
+        agent = CodeBoardingAgent(
+            repo_dir=self.repo_dir,
+            static_analysis=self.mock_analysis,
+            system_message="Test",
+            agent_llm=self.mock_llm,
+            parsing_llm=Mock(spec=BaseChatModel),
+        )
+
+        def invoke(items: list[str]) -> list[str]:
+            return [agent._invoke(" ".join(items))]
+
+        merge = lambda first, second: first + second  # noqa: E731
+        self.assertEqual(agent._invoke_in_batches(["a", "b", "c", "d", "e"], invoke, merge), ["a b", "c", "d e"])
+        with self.assertRaises(LLMRequestTooLargeError):
+            agent._invoke_in_batches(["a", "huge"], invoke, merge)
+        mock_sleep.assert_not_called()
+
+    @patch("agents.agent.initialize_llms", return_value=(MagicMock(), MagicMock()))
+    @patch("agents.agent.create_agent")
+    @patch("time.sleep")
+    def test_invoke_fails_over_to_next_provider(self, mock_sleep, mock_create_agent, mock_initialize_llms):
+        """A hard error on the primary provider reruns the call on the next --llm-fallback provider."""
+        from agents.llm_config import configure_models
+
+        error = Exception("model not found")
+        error.status_code = 404  # type: ignore[attr-defined]
+        primary, secondary = Mock(), Mock()
+        primary.invoke.side_effect = error
+        secondary.invoke.return_value = {"messages": [AIMessage(content="from fallback")]}
+        mock_create_agent.side_effect = [primary, secondary]
+
+        os.environ["ANTHROPIC_API_KEY"] = "sk-ant-test"
+        configure_models(fallback_providers=["openai", "anthropic"])
+        try:
+            agent = CodeBoardingAgent(
+                repo_dir=self.repo_dir,
+                static_analysis=self.mock_analysis,
+                system_message="Test",
+                agent_llm=MagicMock(),
+                parsing_llm=MagicMock(),
+            )
+            result = agent._invoke("Test prompt")
+        finally:
+            configure_models()
+
+        self.assertEqual(result, "from fallback")
+        self.assertEqual(primary.invoke.call_count, 1)
+        mock_initialize_llms.assert_called_once()
+        self.assertTrue(agent.served_by().startswith("anthropic/"))
+
     @patch("agents.agent.create_agent")
     def test_agent_created_with_tools(self, mock_create_agent):
         # Test that agent is created with correct tools
@@ -0,0 +1,42 @@
+from pathlib import Path
+
+from agents.agent_responses import ClusterAnalysis, ClustersComponent
+from caching.component_cache import hash_cluster_groups, hash_subgraph
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+
+def _subgraph(repo: Path, walk_line: int = 3, extra_edge: bool = False) -> dict[str, CallGraph]:
+    cfg = CallGraph(language="go")
+    cfg.add_node(Node("services.Walk", NodeType.FUNCTION, str(repo / "services/walker.go"), walk_line, walk_line + 4))
+    cfg.add_node(Node("services.Feed", NodeType.FUNCTION, str(repo / "services/walker.go"), 20, 24))
+    cfg.add_node(Node("services.Dog", NodeType.CLASS, str(repo / "services/dog.go"), 1, 5))
+    cfg.add_edge("services.Walk", "services.Feed")
+    cfg.add_reference_edge("services.Feed", "services.Dog", EdgeKind.TYPEREF)
+    if extra_edge:
+        cfg.add_edge("services.Feed", "services.Walk")
+    return {"go": cfg}
+
+
+def test_subgraph_hash_ignores_line_moves_but_not_new_edges(tmp_path: Path):
+    baseline = hash_subgraph(_subgraph(tmp_path), tmp_path)
+
+    assert hash_subgraph(_subgraph(tmp_path, walk_line=9), tmp_path) == baseline
+    assert hash_subgraph(_subgraph(tmp_path, extra_edge=True), tmp_path) != baseline
+    # The checkout's location is not part of the key.
+    assert hash_subgraph(_subgraph(tmp_path / "clone"), tmp_path / "clone") == baseline
+
+
+def _grouping(*groups: tuple[str, list[int]]) -> ClusterAnalysis:
+    return ClusterAnalysis(
+        cluster_components=[ClustersComponent(name=name, cluster_ids=ids, description="") for name, ids in groups]
+    )
+
+
+def test_cluster_groups_hash_follows_names_and_ids_not_order():
+    baseline = hash_cluster_groups(_grouping(("Walking", [1, 3]), ("Feeding", [2])))
+
+    assert hash_cluster_groups(_grouping(("Feeding", [2]), ("Walking", [3, 1]))) == baseline
+    assert hash_cluster_groups(_grouping(("Walking", [1]), ("Feeding", [2, 3]))) != baseline
+    assert hash_cluster_groups(_grouping(("Walks", [1, 3]), ("Feeding", [2]))) != baseline
@@ -0,0 +1,122 @@
+from unittest.mock import MagicMock, patch
+
+import pytest
+from langchain_core.messages import AIMessage
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, RelationEdge, SourceCodeReference
+from agents.component_qa import (
+    ComponentAnswer,
+    ComponentNotFoundError,
+    ask_component,
+    build_component_context,
+    find_component,
+    unverified_citations,
+)
+from agents.file_index_models import FileMethodGroup, MethodEntry
+
+
+def _component(cid: str, name: str, methods: list[str]) -> Component:
+    return Component(
+        name=name,
+        description=f"{name} description",
+        key_entities=[SourceCodeReference(qualified_name=methods[0], reference_file=f"{name.lower()}.py")],
+        component_id=cid,
+        file_methods=[
+            FileMethodGroup(
+                file_path=f"{name.lower()}.py",
+                methods=[
+                    MethodEntry(qualified_name=m, start_line=1, end_line=2, node_type="FUNCTION")
+                    for m in methods
+                ],
+            )
+        ],
+    )
+
+
+@pytest.fixture
+def analyses() -> tuple[AnalysisInsights, dict[str, AnalysisInsights]]:
+    services = _component("1", "Services", ["services.create_user", "services.delete_user"])
+    models = _component("2", "Models", ["models.User.save"])
+    cli = _component("3", "CLI", ["cli.main"])
+    edge = RelationEdge(
+        source=SourceCodeReference(qualified_name="services.create_user"),
+        target=SourceCodeReference(qualified_name="models.User.save"),
+    )
+    root = AnalysisInsights(
+        description="A user admin tool.",
+        components=[services, models, cli],
+        components_relations=[
+            Relation(
+                relation="persists via",
+                src_name="Services",
+                dst_name="Models",
+                src_id="1",
+                dst_id="2",
+                all_edges=[edge],
+            ),
+            Relation(relation="invokes", src_name="CLI", dst_name="Models", src_id="3", dst_id="2"),
+        ],
+    )
+    sub = AnalysisInsights(
+        description="", components=[_component("1.1", "Writers", ["services.create_user"])], components_relations=[]
+    )
+    return root, {"1": sub}
+
+
+def test_find_component_by_id_or_name(analyses):
+    root, subs = analyses
+
+    assert find_component(root, subs, "1.1")[0].name == "Writers"
+    component, scope = find_component(root, subs, "services")
+    assert component.component_id == "1" and scope is root
+    with pytest.raises(ComponentNotFoundError, match="Available: 1 Services"):
+        find_component(root, subs, "nope")
+
+
+def test_context_holds_symbols_edges_and_cited_neighbors_only(analyses):
+    root, subs = analyses
+
+    context = build_component_context(root, subs, "Services")
+
+    assert "`services.create_user` -> `models.User.save`" in context.text
+    assert "2 Models: Models description" in context.text
+    assert "1.1 Writers" in context.text
+    # CLI is not related to Services, so it stays out of the prompt.
+    assert "CLI" not in context.text
+    assert {"services.delete_user", "models.User.save"} <= context.symbols
+
+
+def test_ask_component_prompts_with_context_and_flags_unknown_citations(analyses):
+    root, subs = analyses
+    context = build_component_context(root, subs, "1")
+    llm = MagicMock()
+    llm.invoke.return_value = AIMessage(content="It saves via `models.User.save` and `models.User.load`.")
+
+    with patch("agents.component_qa.get_component_question_message", return_value="{component_context}\nQ: {question}"):
+        answer = ask_component(llm, context, "why models?")
+
+    prompt = llm.invoke.call_args.args[0][0].content
+    assert prompt.endswith("Q: why models?") and "## Component 1: Services" in prompt
+    assert unverified_citations(answer, context) == ["models.User.load"]
+
+
+def test_ask_component_uses_typed_citations_when_the_provider_supports_them(analyses):
+    root, subs = analyses
+    context = build_component_context(root, subs, "1")
+    llm = MagicMock()
+    llm.with_structured_output.return_value.invoke.return_value = {
+        "answer": "It persists users through the model layer.",
+        "citations": ["models.User.save", "models.User.load", "services.py"],
+    }
+
+    with (
+        patch("agents.component_qa.get_component_question_message", return_value="{component_context}\nQ: {question}"),
+        patch("agents.component_qa.structured_output_method", return_value="function_calling"),
+    ):
+        answer = ask_component(llm, context, "why models?")
+
+    llm.with_structured_output.assert_called_once_with(ComponentAnswer, method="function_calling")
+    llm.invoke.assert_not_called()
+    assert answer.answer == "It persists users through the model layer."
+    # No backticks in the prose: the citations come from the typed field alone.
+    assert unverified_citations(answer, context) == ["models.User.load"]
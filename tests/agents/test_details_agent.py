@@ -20,7 +20,7 @@ from agents.file_index_models import FileMethodGroup, MethodEntry
 
 from diagram_analysis.file_index import build_files_index
 from static_analyzer.analysis_result import StaticAnalysisResults
-from static_analyzer.constants import NodeType
+from static_analyzer.constants import NodeType, ReceiverKind
 from static_analyzer.graph import CallGraph, ClusterResult
 from static_analyzer.node import Node
 
@@ -80,13 +80,13 @@ class TestDetailsAgent(unittest.TestCase):
         if hasattr(self, "temp_dir"):
             shutil.rmtree(self.temp_dir, ignore_errors=True)
 
-    def _make_agent(self):
+    def _make_agent(self, agent_llm=None):
         return DetailsAgent(
             repo_dir=self.repo_dir,
             static_analysis=self.mock_static_analysis,
             project_name=self.project_name,
             meta_context=self.mock_meta_context,
-            agent_llm=MagicMock(),
+            agent_llm=agent_llm or MagicMock(),
             parsing_llm=MagicMock(),
             run_id="test-run-id",
         )
@@ -114,6 +114,42 @@ class TestDetailsAgent(unittest.TestCase):
         )
         return cr, graph
 
+    def _mock_component_subgraph(self):
+        """Static analysis whose CFG filters to a six-cluster subgraph for ``self.test_component``."""
+        abs_assigned = {str(self.repo_dir / fg.file_path) for fg in self.test_component.file_methods}
+        mock_cluster_result = MagicMock()
+        mock_cluster_result.get_cluster_ids.return_value = {1}
+        mock_cluster_result.get_files_for_cluster.return_value = abs_assigned
+
+        # Real subgraph cluster result + graph so deterministic grouping has structure.
+        sub_cluster_result, subgraph_graph = self._clustered_graph(range(1, 7))
+
+        mock_node = MagicMock()
+        mock_node.file_path = str(self.repo_dir / "src" / "main.py")
+        mock_node.fully_qualified_name = "n1"
+        mock_node.type = NodeType.FUNCTION
+        mock_node.line_start = 1
+        mock_node.line_end = 10
+        mock_node.signature = None
+        mock_node.receiver_kind = ReceiverKind.NONE
+
+        mock_subgraph = MagicMock()
+        mock_subgraph.nodes = {"n1": mock_node}
+        mock_subgraph.cluster.return_value = sub_cluster_result
+        mock_subgraph.to_cluster_string.return_value = "Component CFG String"
+        mock_subgraph.to_networkx.return_value = subgraph_graph
+
+        mock_cfg = MagicMock()
+        mock_cfg.cluster.return_value = mock_cluster_result
+        mock_cfg.filter_by_nodes.return_value = mock_subgraph
+        # _build_cluster_string calls cfg.to_cluster_string on the original cfg
+        mock_cfg.to_cluster_string.return_value = "Cluster 1: method_a, method_b"
+        # deterministic_cluster_grouping reads the (super-)graph via get_cfg(...).to_networkx()
+        mock_cfg.to_networkx.return_value = subgraph_graph
+
+        self.mock_static_analysis.get_languages.return_value = ["python"]
+        self.mock_static_analysis.get_cfg.return_value = mock_cfg
+
     def _assert_partition(self, result, expected_ids):
         self.assertIsInstance(result, ClusterAnalysis)
         self.assertGreaterEqual(len(result.cluster_components), 1)
@@ -376,38 +412,7 @@ class TestDetailsAgent(unittest.TestCase):
             parsing_llm=mock_parsing_llm,
             run_id="test-run-id",
         )
-        # Mock StaticAnalysis and CFG behavior for run
-        abs_assigned = {str(self.repo_dir / fg.file_path) for fg in self.test_component.file_methods}
-        mock_cluster_result = MagicMock()
-        mock_cluster_result.get_cluster_ids.return_value = {1}
-        mock_cluster_result.get_files_for_cluster.return_value = abs_assigned
-
-        # Real subgraph cluster result + graph so deterministic grouping has structure.
-        sub_cluster_result, subgraph_graph = self._clustered_graph(range(1, 7))
-
-        mock_node = MagicMock()
-        mock_node.file_path = str(self.repo_dir / "src" / "main.py")
-        mock_node.fully_qualified_name = "n1"
-        mock_node.type = NodeType.FUNCTION
-        mock_node.line_start = 1
-        mock_node.line_end = 10
-
-        mock_subgraph = MagicMock()
-        mock_subgraph.nodes = {"n1": mock_node}
-        mock_subgraph.cluster.return_value = sub_cluster_result
-        mock_subgraph.to_cluster_string.return_value = "Component CFG String"
-        mock_subgraph.to_networkx.return_value = subgraph_graph
-
-        mock_cfg = MagicMock()
-        mock_cfg.cluster.return_value = mock_cluster_result
-        mock_cfg.filter_by_nodes.return_value = mock_subgraph
-        # _build_cluster_string calls cfg.to_cluster_string on the original cfg
-        mock_cfg.to_cluster_string.return_value = "Cluster 1: method_a, method_b"
-        # deterministic_cluster_grouping reads the (super-)graph via get_cfg(...).to_networkx()
-        mock_cfg.to_networkx.return_value = subgraph_graph
-
-        self.mock_static_analysis.get_languages.return_value = ["python"]
-        self.mock_static_analysis.get_cfg.return_value = mock_cfg
+        self._mock_component_subgraph()
 
         # Mock responses for final analysis. Grouping is now deterministic, so the
         # only _invoke_validate call in the pipeline is for relations.
@@ -444,6 +449,34 @@ class TestDetailsAgent(unittest.TestCase):
         mock_parse_invoke.assert_called_once_with(ANY, ComponentApiSurfaces)
         mock_fix_ref.assert_called_once()
 
+    @patch("agents.details_agent.DetailsAgent._parse_invoke")
+    @patch("agents.details_agent.DetailsAgent._invoke_validate")
+    @patch("agents.details_agent.DetailsAgent._invoke_repair_validate")
+    def test_run_reuses_cached_docs_for_an_unchanged_subgraph(
+        self, mock_invoke_repair_validate, mock_invoke_validate, mock_parse_invoke
+    ):
+        self._mock_component_subgraph()
+        mock_invoke_repair_validate.return_value = AnalysisInsights(
+            description="Final",
+            components=[Component(name="SubComp", description="A sub-component", key_entities=[])],
+            components_relations=[],
+        )
+        mock_parse_invoke.return_value = ComponentApiSurfaces(api_surfaces=[])
+        mock_invoke_validate.return_value = ComponentRelations(components_relations=[])
+
+        agent_llm = MagicMock(model_name="gpt-4o")
+
+        first, _ = self._make_agent(agent_llm).run(self.test_component)
+        # The parent's description is regenerated; the component's own subgraph is not.
+        self.test_component.description = "Reworded by the parent's analysis"
+        second, _ = self._make_agent(agent_llm).run(self.test_component)
+
+        self.assertEqual(second.description, "Final")
+        self.assertEqual([c.name for c in second.components], [c.name for c in first.components])
+        mock_invoke_repair_validate.assert_called_once()
+        mock_parse_invoke.assert_called_once()
+        mock_invoke_validate.assert_called_once()
+
     def test_populate_file_methods(self):
         # Test deterministic file population from cluster results
         mock_llm = MagicMock()
@@ -1,5 +1,6 @@
 """Tests for LLM configuration and model detection."""
 
+import logging
 import os
 from unittest.mock import MagicMock, patch
 
@@ -9,9 +10,16 @@ from agents.llm_config import (
     LLM_PROVIDERS,
     LLMConfigError,
     _model_accepts_temperature,
+    _resolve_selected_provider,
+    active_provider,
+    configure_models,
+    fail_over,
     initialize_agent_llm,
     initialize_llms,
     initialize_parsing_llm,
+    selected_providers,
+    structured_output_method,
+    validate_agent_model,
     validate_api_key_provided,
 )
 from agents.model_capabilities import ContextWindow
@@ -104,6 +112,20 @@ class TestProviderSelection:
             assert aws.has_real_api_key() is False
 
 
+class TestStructuredOutput:
+    def test_native_mode_follows_the_active_provider(self):
+        anthropic_llm = MagicMock(spec=LLM_PROVIDERS["anthropic"].chat_class)
+        with patch.dict(os.environ, {"ANTHROPIC_API_KEY": "sk-ant-test"}, clear=True):
+            assert structured_output_method(anthropic_llm) == "function_calling"
+            # A model of another class than the active provider's (e.g. a test double) parses text.
+            assert structured_output_method(MagicMock()) is None
+        with patch.dict(os.environ, {"OPENAI_API_KEY": "sk-test"}, clear=True):
+            assert structured_output_method(MagicMock(spec=LLM_PROVIDERS["openai"].chat_class)) == "json_schema"
+        # OpenAI-compatible endpoints share ChatOpenAI but not reliable JSON-schema support.
+        with patch.dict(os.environ, {"DEEPSEEK_API_KEY": "sk-test"}, clear=True):
+            assert structured_output_method(MagicMock(spec=LLM_PROVIDERS["deepseek"].chat_class)) is None
+
+
 class TestLLMConfigKeyless:
     def test_openai_is_keyless_capable(self):
         assert LLM_PROVIDERS["openai"].keyless_capable is True
@@ -159,6 +181,90 @@ class TestAgentContextWindow:
         assert ctx == ContextWindow(256_000, 64_000, is_fallback=True)
 
 
+class TestLLMFallback:
+    """``--llm-fallback`` selects an ordered provider chain instead of a single provider."""
+
+    @pytest.fixture(autouse=True)
+    def _reset_models(self):
+        yield
+        configure_models()
+
+    def test_configured_chain_is_not_ambiguous(self):
+        env = {"ANTHROPIC_API_KEY": "sk-ant-test", "OPENAI_API_KEY": "sk-test"}
+        with patch.dict(os.environ, env, clear=True):
+            configure_models(fallback_providers=["anthropic", "openai"])
+            validate_api_key_provided()  # should not raise
+            assert active_provider() == "anthropic"
+
+    def test_single_provider_picks_one_of_several_keys(self):
+        env = {"ANTHROPIC_API_KEY": "sk-ant-test", "OPENAI_API_KEY": "sk-test"}
+        with patch.dict(os.environ, env, clear=True):
+            configure_models(fallback_providers=["anthropic"])  # ``--provider anthropic``
+            validate_api_key_provided()  # should not raise
+            assert active_provider() == "anthropic"
+
+    def test_unknown_or_unconfigured_provider_raises(self):
+        with patch.dict(os.environ, {"ANTHROPIC_API_KEY": "sk-ant-test"}, clear=True):
+            configure_models(fallback_providers=["anthropic", "nope"])
+            with pytest.raises(LLMConfigError, match="Unknown provider"):
+                validate_api_key_provided()
+            configure_models(fallback_providers=["anthropic", "openai"])
+            with pytest.raises(LLMConfigError, match="openai needs OPENAI_API_KEY"):
+                validate_api_key_provided()
+
+    def test_ollama_needs_no_environment_when_named(self):
+        """``--provider ollama`` talks to the local server without OLLAMA_BASE_URL/OLLAMA_HOST."""
+        with patch.dict(os.environ, {}, clear=True):
+            configure_models(agent_model="llama3.1", fallback_providers=["ollama"])
+            validate_api_key_provided()  # should not raise
+            assert active_provider() == "ollama"
+
+    def test_fail_over_walks_the_chain_once(self):
+        env = {"ANTHROPIC_API_KEY": "sk-ant-test", "OPENAI_API_KEY": "sk-test"}
+        with patch.dict(os.environ, env, clear=True):
+            configure_models(agent_model="claude-custom", fallback_providers=["anthropic", "openai"])
+            assert _resolve_selected_provider("claude-custom", "agent_model")[2] == "claude-custom"
+
+            assert fail_over("anthropic") is True
+            assert fail_over("anthropic") is True  # a concurrent caller just picks up the new provider
+            assert active_provider() == "openai"
+            # The primary's model override does not carry over to the fallback.
+            assert _resolve_selected_provider("claude-custom", "agent_model")[2] == LLM_PROVIDERS["openai"].agent_model
+
+            assert fail_over("openai") is False
+            assert active_provider() == "openai"
+
+
+class TestValidateAgentModel:
+    """``--model`` warns when it names a model the primary provider is not known to serve."""
+
+    @pytest.fixture(autouse=True)
+    def _reset_models(self):
+        yield
+        configure_models()
+
+    @pytest.mark.parametrize("model_name", ["gpt-4o", "gpt-4o-mini", "gpt-4.1"])
+    def test_known_openai_models_pass(self, model_name):
+        with patch.dict(os.environ, {"OPENAI_API_KEY": "sk-test"}, clear=True):
+            validate_agent_model(model_name)  # should not raise
+
+    def test_unknown_model_warns_with_the_known_ones(self, caplog):
+        with patch.dict(os.environ, {"OPENAI_API_KEY": "sk-test"}, clear=True):
+            with caplog.at_level(logging.WARNING, logger="agents.llm_config"):
+                validate_agent_model("gpt-5")  # should not raise
+        assert "Unknown openai model 'gpt-5'; trying it anyway" in caplog.text
+        assert "Known models: gpt-4o, gpt-4o-mini, gpt-4.1," in caplog.text
+
+    def test_custom_endpoints_and_unlisted_providers_take_any_model(self):
+        with patch.dict(os.environ, {"OPENAI_BASE_URL": "http://localhost:8000/v1"}, clear=True):
+            validate_agent_model("my-finetune")
+        with patch.dict(os.environ, {"ANTHROPIC_API_KEY": "sk-ant-test"}, clear=True):
+            validate_agent_model("claude-custom")
+        with patch.dict(os.environ, {"ANTHROPIC_API_KEY": "sk-ant-test", "OPENAI_API_KEY": "sk-test"}, clear=True):
+            configure_models(fallback_providers=["anthropic", "openai"])
+            validate_agent_model("claude-custom")  # only the primary provider's models are checked
+
+
 class TestLiteLLMProvider:
     """The litellm provider proxies an OpenAI-compatible server via base_url."""
 
@@ -206,6 +312,72 @@ class TestLiteLLMProvider:
                 initialize_llms()
 
 
+class TestGeminiProvider:
+    """``GEMINI_API_KEY``, the key the Gemini SDKs read, selects the google provider too."""
+
+    @pytest.fixture(autouse=True)
+    def _reset_models(self):
+        yield
+        configure_models()
+
+    @patch("agents.prompts.prompt_factory.initialize_global_factory")
+    @patch("agents.agent.MONITORING_CALLBACK")
+    def test_selected_by_gemini_key_with_model_override(self, mock_monitoring_callback, mock_init_factory):
+        env = {"GEMINI_API_KEY": "AIza-test", "AGENT_MODEL": "gemini-1.5-pro"}
+        with patch.dict(os.environ, env, clear=True):
+            validate_api_key_provided()
+            assert active_provider() == "google"
+            with patch.object(LLM_PROVIDERS["google"], "chat_class", return_value=MagicMock()) as mock_chat_class:
+                initialize_llms()
+
+            agent_kwargs = mock_chat_class.call_args_list[0][1]
+            assert agent_kwargs["model"] == "gemini-1.5-pro"
+            assert agent_kwargs["api_key"] == "AIza-test"
+
+    def test_both_google_keys_select_one_provider(self):
+        env = {"GOOGLE_API_KEY": "AIza-google", "GEMINI_API_KEY": "AIza-gemini"}
+        with patch.dict(os.environ, env, clear=True):
+            validate_api_key_provided()  # should not raise
+            assert selected_providers() == ["google"]
+            assert LLM_PROVIDERS["google"].get_api_key() == "AIza-google"
+
+    def test_gemini_is_an_alias_of_google(self):
+        with patch.dict(os.environ, {"GEMINI_API_KEY": "AIza-test"}, clear=True):
+            configure_models(fallback_providers=["gemini"])
+            validate_api_key_provided()  # should not raise
+            assert active_provider() == "google"
+
+
+class TestDeterministic:
+    """``--deterministic`` pins temperature and, for clients that take one, the seed."""
+
+    @patch("agents.prompts.prompt_factory.initialize_global_factory")
+    @patch("agents.agent.MONITORING_CALLBACK")
+    def test_pins_temperature_and_seed(self, mock_monitoring_callback, mock_init_factory):
+        class SeedableChat:
+            def __init__(self, **kwargs):
+                self.kwargs = kwargs
+
+        env = {"LITELLM_BASE_URL": "http://localhost:4000", "AGENT_MODEL": "my-proxy-model"}
+        with patch.dict(os.environ, env, clear=True):
+            litellm_config = LLM_PROVIDERS["litellm"]
+            with (
+                patch.object(litellm_config, "chat_class", SeedableChat),
+                patch("agents.llm_config._SEEDABLE_CHAT_CLASSES", (SeedableChat,)),
+            ):
+                try:
+                    configure_models(deterministic=True)
+                    agent_llm = initialize_agent_llm()
+                finally:
+                    configure_models()
+
+                assert agent_llm.kwargs["temperature"] == 0
+                assert agent_llm.kwargs["seed"] == 42
+
+                agent_llm = initialize_agent_llm()
+                assert "seed" not in agent_llm.kwargs
+
+
 class TestDetectLLMTypeFromModel:
     """Test the LLMType.from_model_name function with various model names."""
 
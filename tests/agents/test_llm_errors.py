@@ -1,10 +1,17 @@
-"""Tests for provider-agnostic LLM auth-error detection and typing."""
+"""Tests for provider-agnostic LLM auth-error and rate-limit detection."""
 
 import os
 from unittest.mock import patch
 
 from agents.llm_config import current_provider_key_context
-from agents.llm_errors import LLMAuthError, detect_auth_error
+from agents.llm_errors import (
+    LLMAuthError,
+    detect_auth_error,
+    is_rate_limited,
+    is_request_too_large,
+    is_safety_blocked,
+    safety_finish_reason,
+)
 
 
 class _FakeStatusError(Exception):
@@ -90,6 +97,56 @@ class TestDetectAuthError:
         assert len(result.telemetry_properties["error_message"]) <= 500
 
 
+class OverloadedError(Exception):
+    """Named like anthropic's 529 error class."""
+
+
+class TestIsRateLimited:
+    def test_429_and_anthropic_overloaded_status(self):
+        assert is_rate_limited(_FakeStatusError("rate_limit_error", status_code=429))
+        assert is_rate_limited(_FakeStatusError("overloaded_error", status_code=529))
+
+    def test_error_class_names(self):
+        assert is_rate_limited(OverloadedError("Overloaded"))
+
+    def test_gemini_messages_rewrapped_without_a_status(self):
+        assert is_rate_limited(RuntimeError("Error calling model 'gemini-1.5-pro' (RESOURCE_EXHAUSTED): quota"))
+        assert is_rate_limited(RuntimeError("503 UNAVAILABLE. The model is overloaded. Please try again later."))
+
+    def test_other_errors_are_not_rate_limits(self):
+        assert not is_rate_limited(_FakeStatusError("server error", status_code=500))
+        assert not is_rate_limited(_FakeStatusError("invalid api key", status_code=401))
+        assert not is_rate_limited(TimeoutError())
+
+
+class TestIsRequestTooLarge:
+    def test_token_limit_messages(self):
+        openai = "Error code: 429 - Request too large for gpt-4o on tokens per min (TPM): Limit 30000, Requested 45000"
+        assert is_request_too_large(_FakeStatusError(openai, status_code=429))
+        assert is_request_too_large(_FakeStatusError("prompt is too long: 210000 tokens > 200000", status_code=400))
+
+    def test_gemini_input_token_count(self):
+        gemini = "400 The input token count (2100000) exceeds the maximum number of tokens allowed (2097152)."
+        assert is_request_too_large(_FakeGoogleError(gemini, code=400))
+
+    def test_transient_rate_limit_is_not_too_large(self):
+        assert not is_request_too_large(_FakeStatusError("Rate limit reached, please try again", status_code=429))
+
+
+class TestSafetyBlocks:
+    def test_gemini_block_messages(self):
+        assert is_safety_blocked(RuntimeError("Invalid argument provided to Gemini: block_reason: SAFETY"))
+        assert is_safety_blocked(RuntimeError("Response stopped: finish_reason=FinishReason.PROHIBITED_CONTENT"))
+        assert not is_safety_blocked(RuntimeError("finish_reason: STOP"))
+        assert not is_safety_blocked(_FakeStatusError("rate_limit_error", status_code=429))
+
+    def test_finish_reason_of_an_answer(self):
+        assert safety_finish_reason({"finish_reason": "SAFETY"}) == "SAFETY"
+        assert safety_finish_reason({"finish_reason": "FinishReason.BLOCKLIST"}) == "BLOCKLIST"
+        assert safety_finish_reason({"finish_reason": "STOP"}) is None
+        assert safety_finish_reason({}) is None
+
+
 class TestCurrentProviderKeyContext:
     def test_masks_all_but_last_four(self):
         with patch.dict(os.environ, {"OPENAI_API_KEY": "sk-secret-abcd"}, clear=True):
@@ -6,18 +6,25 @@ import json
 import pytest
 
 from agents.model_capabilities import (
+    _GEMINI_CACHE,
     _OLLAMA_CACHE,
     ContextWindow,
+    ModelPricing,
     _parse_num_ctx,
+    _resolve_gemini,
     _resolve_ollama,
     get_context_window,
+    get_pricing,
 )
 from utils import CODEBOARDING_DIR_NAME
 
 _FAKE_MODELSDEV = {
     "openai": {
         "models": {
-            "gpt-5": {"limit": {"context": 400_000, "input": 272_000, "output": 128_000}},
+            "gpt-5": {
+                "limit": {"context": 400_000, "input": 272_000, "output": 128_000},
+                "cost": {"input": 1.25, "output": 10},
+            },
             "gpt-4o": {"limit": {"context": 128_000, "output": 16_384}},
         }
     },
@@ -36,13 +43,19 @@ _FAKE_MODELSDEV = {
 }
 
 _FAKE_LITELLM = {
-    "anthropic.claude-3-haiku-20240307-v1:0": {"max_input_tokens": 200_000, "max_output_tokens": 4_096},
+    "anthropic.claude-3-haiku-20240307-v1:0": {
+        "max_input_tokens": 200_000,
+        "max_output_tokens": 4_096,
+        "input_cost_per_token": 2.5e-07,
+        "output_cost_per_token": 1.25e-06,
+    },
 }
 
 _FAKE_OPENROUTER = {
     "anthropic/claude-opus-4-7": {
         "context_length": 1_000_000,
         "top_provider": {"max_completion_tokens": 128_000},
+        "pricing": {"prompt": "0.000005", "completion": "0.000025"},
     },
 }
 
@@ -112,6 +125,26 @@ class TestLitellmResolution:
         assert cw.input_tokens == 200_000
 
 
+class TestPricing:
+    def test_modelsdev_prices_per_million(self, fake_catalogs):
+        pricing = get_pricing("openai", "gpt-5")
+        assert pricing == ModelPricing(1.25, 10)
+        assert pricing.cost(1_000_000, 100_000) == pytest.approx(2.25)
+
+    def test_litellm_prices_per_token_are_scaled(self, fake_catalogs):
+        pricing = get_pricing("aws", "us.anthropic.claude-3-haiku-20240307-v1:0")
+        assert pricing == ModelPricing(pytest.approx(0.25), pytest.approx(1.25))
+
+    def test_openrouter_string_prices(self, fake_catalogs):
+        assert get_pricing("anthropic", "claude-opus-4-7") == ModelPricing(pytest.approx(5), pytest.approx(25))
+
+    def test_env_override_and_unpriced_models(self, fake_catalogs, monkeypatch):
+        monkeypatch.setenv("CB_PRICE_OPENAI_GPT_5", "2,8")
+        assert get_pricing("openai", "gpt-5") == ModelPricing(2, 8)
+        assert get_pricing("openai", "gpt-4o") is None
+        assert get_pricing("ollama", "llama3") == ModelPricing(0, 0)
+
+
 class TestOpenrouterResolution:
     def test_resolves_via_aggregator_id(self, fake_catalogs):
         cw = get_context_window("anthropic", "claude-opus-4-7")
@@ -120,10 +153,19 @@ class TestOpenrouterResolution:
 
 
 class TestOllamaResolver:
-    def test_short_circuits_without_base_url(self, monkeypatch):
+    def test_defaults_to_the_local_server(self, monkeypatch):
         monkeypatch.delenv("OLLAMA_BASE_URL", raising=False)
+        monkeypatch.delenv("OLLAMA_HOST", raising=False)
         _OLLAMA_CACHE.clear()
-        assert _resolve_ollama("ollama", "qwen3:30b") is None
+        urls = []
+
+        def fake_urlopen(req, timeout=None):
+            urls.append(req.full_url)
+            return io.BytesIO(json.dumps({"parameters": "num_ctx 8192", "model_info": {}}).encode())
+
+        monkeypatch.setattr("agents.model_capabilities.urllib.request.urlopen", fake_urlopen)
+        assert _resolve_ollama("ollama", "llama3.1") == (8192, 64_000)
+        assert urls == ["http://localhost:11434/api/show"]
 
     def test_short_circuits_for_non_ollama_provider(self):
         assert _resolve_ollama("openai", "gpt-4o") is None
@@ -170,6 +212,33 @@ class TestOllamaResolver:
         assert _resolve_ollama("ollama", "llama3:8b") == (8192, 64_000)
 
 
+class TestGeminiResolver:
+    def test_short_circuits_without_a_key(self, monkeypatch):
+        monkeypatch.delenv("GEMINI_API_KEY", raising=False)
+        monkeypatch.delenv("GOOGLE_API_KEY", raising=False)
+        _GEMINI_CACHE.clear()
+        assert _resolve_gemini("google", "gemini-1.5-pro") is None
+        assert _resolve_gemini("openai", "gpt-4o") is None
+
+    def test_advertised_limits_win_over_the_catalogs(self, fake_catalogs, monkeypatch):
+        monkeypatch.setenv("GEMINI_API_KEY", "AIza-test")
+        monkeypatch.delenv("GOOGLE_API_KEY", raising=False)
+        _GEMINI_CACHE.clear()
+        requests = []
+        payload = {"name": "models/gemini-1.5-pro", "inputTokenLimit": 2_097_152, "outputTokenLimit": 8_192}
+
+        def fake_urlopen(req, timeout=None):
+            requests.append(req)
+            return io.BytesIO(json.dumps(payload).encode())
+
+        monkeypatch.setattr("agents.model_capabilities.urllib.request.urlopen", fake_urlopen)
+        assert get_context_window("google", "gemini-1.5-pro") == ContextWindow(2_097_152, 8_192)
+        assert get_context_window("google", "gemini-1.5-pro") == ContextWindow(2_097_152, 8_192)
+        assert len(requests) == 1
+        assert requests[0].full_url.endswith("/v1beta/models/gemini-1.5-pro")
+        assert requests[0].get_header("X-goog-api-key") == "AIza-test"
+
+
 class TestCorruptCache:
     def test_corrupt_cache_file_triggers_refetch_instead_of_crashing(self, tmp_path, monkeypatch):
         # Regression: a half-written cache file used to crash the first resolver call
@@ -0,0 +1,97 @@
+import shutil
+import tempfile
+import unittest
+from pathlib import Path
+from unittest.mock import Mock, patch
+
+from langchain_core.language_models import BaseChatModel
+from pydantic import BaseModel
+
+import caching.response_cache as response_cache
+from agents.agent import CodeBoardingAgent
+from caching.cache import ModelSettings
+from caching.response_cache import RESPONSE_CACHE_FILENAME, ResponseCache, configure_response_cache
+from static_analyzer.analysis_result import StaticAnalysisResults
+from utils import get_cache_dir
+
+
+class Answer(BaseModel):
+    value: str
+
+
+class TestResponseCache(unittest.TestCase):
+    def setUp(self):
+        self.repo_dir = Path(tempfile.mkdtemp())
+        configure_response_cache(refresh=False)
+
+    def tearDown(self):
+        configure_response_cache(refresh=False)
+        shutil.rmtree(self.repo_dir, ignore_errors=True)
+
+    def _key(self, prompt: str, model_name: str = "gpt-4o"):
+        settings = ModelSettings(provider="openai", chat_class="ChatOpenAI", model_name=model_name)
+        return ResponseCache.build_key(settings, "system", prompt)
+
+    def test_lookup_returns_saved_text_for_the_same_request_only(self):
+        cache = ResponseCache(self.repo_dir)
+        cache.save(self._key("prompt"), "answer")
+
+        self.assertEqual(cache.lookup(self._key("prompt")), "answer")
+        self.assertIsNone(cache.lookup(self._key("other prompt")))
+        self.assertIsNone(cache.lookup(self._key("prompt", model_name="gpt-4o-mini")))
+        self.assertTrue((get_cache_dir(self.repo_dir) / RESPONSE_CACHE_FILENAME).exists())
+
+    def test_refresh_skips_lookups_but_still_saves(self):
+        cache = ResponseCache(self.repo_dir)
+        cache.save(self._key("prompt"), "old")
+
+        configure_response_cache(refresh=True)
+        self.assertTrue(response_cache._refresh)
+        self.assertIsNone(cache.lookup(self._key("prompt")))
+        cache.save(self._key("prompt"), "new")
+
+        configure_response_cache(refresh=False)
+        self.assertEqual(cache.lookup(self._key("prompt")), "new")
+
+    @patch("agents.agent.create_agent")
+    def test_agent_replays_cached_answer_and_parse(self, mock_create_agent):
+        mock_create_agent.return_value = Mock()
+        agent = CodeBoardingAgent(
+            repo_dir=self.repo_dir,
+            static_analysis=Mock(spec=StaticAnalysisResults),
+            system_message="Test",
+            agent_llm=Mock(spec=BaseChatModel),
+            parsing_llm=Mock(spec=BaseChatModel),
+        )
+
+        with (
+            patch.object(agent, "_invoke_uncached", return_value="the answer") as invoke,
+            patch.object(agent, "_parse_response_uncached", return_value=Answer(value="x")) as parse,
+        ):
+            for _ in range(2):
+                self.assertEqual(agent._invoke("prompt"), "the answer")
+                self.assertEqual(agent._parse_response("prompt", "the answer", Answer), Answer(value="x"))
+
+        invoke.assert_called_once()
+        parse.assert_called_once()
+
+    @patch("agents.agent.create_agent")
+    def test_failed_invocation_is_not_cached(self, mock_create_agent):
+        mock_create_agent.return_value = Mock()
+        agent = CodeBoardingAgent(
+            repo_dir=self.repo_dir,
+            static_analysis=Mock(spec=StaticAnalysisResults),
+            system_message="Test",
+            agent_llm=Mock(spec=BaseChatModel),
+            parsing_llm=Mock(spec=BaseChatModel),
+        )
+
+        with patch.object(agent, "_invoke_uncached", return_value="Could not get response from the agent.") as invoke:
+            agent._invoke("prompt")
+            agent._invoke("prompt")
+
+        self.assertEqual(invoke.call_count, 2)
+
+
+if __name__ == "__main__":
+    unittest.main()
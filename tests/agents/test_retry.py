@@ -19,6 +19,11 @@ class TestDefaultBackoff(unittest.TestCase):
         self.assertEqual(default_backoff(4, initial_s=10, multiplier=2.0, max_s=120), 120)
         self.assertEqual(default_backoff(10, initial_s=30, multiplier=2.0, max_s=300), 300)
 
+    def test_jitter_only_shortens_the_capped_delay(self):
+        for _ in range(20):
+            delay = default_backoff(10, initial_s=30, multiplier=2.0, max_s=300, jitter=0.25)
+            self.assertTrue(225 <= delay <= 300)
+
 
 class TestWithRetries(unittest.TestCase):
     def test_returns_on_first_success(self):
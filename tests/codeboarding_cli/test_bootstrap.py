@@ -0,0 +1,88 @@
+import logging
+import os
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from agents.llm_config import LLMConfigError, active_provider, configure_models
+from codeboarding_cli.bootstrap import MODEL_ENV, configure_llm_providers
+from project_config import ProjectConfig
+from user_config import LLMUserConfig
+
+
+def _configure(agent_model: str | None, project_model: str | None, ollama_host: str | None = None) -> dict:
+    project = ProjectConfig(llm=LLMUserConfig(agent_model=project_model))
+    with (
+        patch("codeboarding_cli.bootstrap.ensure_config_template"),
+        patch("codeboarding_cli.bootstrap.load_user_config") as load_user_config,
+        patch("codeboarding_cli.bootstrap.load_project_config", return_value=project),
+        patch("codeboarding_cli.bootstrap.validate_api_key_provided"),
+        patch("codeboarding_cli.bootstrap.validate_agent_model"),
+        patch("codeboarding_cli.bootstrap.configure_models") as configure_models,
+    ):
+        load_user_config.return_value.llm = LLMUserConfig(agent_model="gpt-4o", parsing_model="gpt-4o-mini")
+        configure_llm_providers(Path("/tmp/repo"), agent_model=agent_model, ollama_host=ollama_host)
+    return configure_models.call_args.kwargs
+
+
+def test_model_flag_wins_over_config_files():
+    kwargs = _configure("gpt-4.1", "gpt-4o")
+
+    assert kwargs["agent_model"] == "gpt-4.1"
+    assert kwargs["parsing_model"] == "gpt-4o-mini"
+
+
+def test_config_model_used_without_the_flag():
+    with patch.dict(os.environ):
+        os.environ.pop(MODEL_ENV, None)
+        assert _configure(None, "o4-mini")["agent_model"] == "o4-mini"
+        assert _configure(None, None)["agent_model"] == "gpt-4o"
+
+
+def test_model_environment_variable_applies_without_the_flag():
+    with patch.dict(os.environ, {MODEL_ENV: "gpt-4o-mini"}):
+        assert _configure(None, "gpt-4o")["agent_model"] == "gpt-4o-mini"
+        assert _configure("gpt-4.1", "gpt-4o")["agent_model"] == "gpt-4.1"
+
+
+def test_unknown_model_warns_and_still_runs(caplog):
+    with (
+        patch.dict(os.environ, {"OPENAI_API_KEY": "sk-test"}, clear=True),
+        patch("codeboarding_cli.bootstrap.ensure_config_template"),
+        patch("codeboarding_cli.bootstrap.load_user_config") as load_user_config,
+        patch("codeboarding_cli.bootstrap.load_project_config", return_value=ProjectConfig()),
+        caplog.at_level(logging.WARNING, logger="agents.llm_config"),
+    ):
+        load_user_config.return_value.llm = LLMUserConfig()
+        configure_llm_providers(Path("/tmp/repo"), agent_model="gpt-5")
+    configure_models()
+
+    assert "Unknown openai model 'gpt-5'" in caplog.text
+
+
+def test_ollama_host_flag_wins_over_the_environment_and_pins_the_provider():
+    with patch.dict(os.environ, {"OLLAMA_BASE_URL": "http://gpu-box:11434"}):
+        kwargs = _configure("llama3.1", None, ollama_host="http://localhost:11434")
+
+        assert os.environ["OLLAMA_BASE_URL"] == "http://localhost:11434"
+    assert kwargs["fallback_providers"] == ["ollama"]
+
+
+def test_ollama_host_needs_the_ollama_provider():
+    with pytest.raises(LLMConfigError, match="--ollama-host needs the ollama provider"):
+        configure_llm_providers(Path("/tmp/repo"), llm_fallback=["openai"], ollama_host="http://localhost:11434")
+
+
+def test_ollama_host_with_other_keys_set_is_not_ambiguous():
+    env = {"OPENAI_API_KEY": "sk-test"}
+    with (
+        patch.dict(os.environ, env, clear=True),
+        patch("codeboarding_cli.bootstrap.ensure_config_template"),
+        patch("codeboarding_cli.bootstrap.load_user_config") as load_user_config,
+        patch("codeboarding_cli.bootstrap.load_project_config", return_value=ProjectConfig()),
+    ):
+        load_user_config.return_value.llm = LLMUserConfig()
+        configure_llm_providers(Path("/tmp/repo"), agent_model="llama3.1", ollama_host="http://localhost:11434")
+        assert active_provider() == "ollama"
+    configure_models()
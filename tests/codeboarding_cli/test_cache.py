@@ -0,0 +1,60 @@
+from pathlib import Path
+
+import pytest
+
+from caching.stats import record_cache_access, reset_cache_stats, write_cache_stats
+from main import main
+from utils import CACHE_ROOT_ENV, get_artifact_dir, get_cache_dir
+
+
+@pytest.fixture
+def repo_with_caches(tmp_path: Path, monkeypatch: pytest.MonkeyPatch) -> Path:
+    monkeypatch.delenv(CACHE_ROOT_ENV, raising=False)
+    repo = tmp_path / "repo"
+    cache_dir = get_cache_dir(repo)
+    cache_dir.mkdir(parents=True)
+    (cache_dir / "final_analysis_llm.sqlite").write_text("llm-data")
+    (cache_dir / "final_analysis_llm.sqlite-wal").write_text("wal")
+    (cache_dir / "incremental_cache_python.json").write_text("{}")
+    (get_artifact_dir(repo) / "static_analysis.pkl").write_text("pickle")
+    return repo
+
+
+def test_cache_list_groups_sidecars_and_clear_by_type(repo_with_caches: Path, tmp_path: Path, capsys) -> None:
+    clones = tmp_path / "repos"
+    (clones / "some-repo").mkdir(parents=True)
+
+    main(["cache", "list", "--local", str(repo_with_caches), "--clone-root", str(clones)])
+    out = capsys.readouterr().out
+    assert "final_analysis_llm.sqlite" in out and "-wal" not in out
+    assert "static_analysis.pkl" in out and "some-repo" in out
+    assert "in 4 entries" in out
+
+    main(["cache", "clear", "--local", str(repo_with_caches), "--clone-root", str(clones), "--type", "llm"])
+    cache_dir = get_cache_dir(repo_with_caches)
+    assert not (cache_dir / "final_analysis_llm.sqlite").exists()
+    assert not (cache_dir / "final_analysis_llm.sqlite-wal").exists()
+    assert (cache_dir / "incremental_cache_python.json").exists()
+    assert (clones / "some-repo").exists()
+
+
+def test_cache_stats_reports_last_run_hit_rate(repo_with_caches: Path, capsys) -> None:
+    main(["cache", "stats", "--local", str(repo_with_caches)])
+    assert "No cache statistics" in capsys.readouterr().out
+
+    reset_cache_stats()
+    for hit in (True, True, True, False):
+        record_cache_access("final_analysis_llm", hit)
+    write_cache_stats(get_cache_dir(repo_with_caches))
+    reset_cache_stats()
+
+    main(["cache", "stats", "--local", str(repo_with_caches)])
+    out = capsys.readouterr().out
+    assert "final_analysis_llm" in out and "75%" in out
+
+
+def test_cache_root_env_relocates_cache_dir(tmp_path: Path, monkeypatch: pytest.MonkeyPatch) -> None:
+    monkeypatch.setenv(CACHE_ROOT_ENV, str(tmp_path / "shared"))
+    cache_dir = get_cache_dir(tmp_path / "repo")
+    assert cache_dir.parent == tmp_path / "shared"
+    assert cache_dir.name.startswith("repo-")
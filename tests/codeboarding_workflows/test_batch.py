@@ -0,0 +1,92 @@
+import json
+from pathlib import Path
+
+import pytest
+
+from codeboarding_workflows.batch import (
+    BATCH_MANIFEST_FILENAME,
+    BatchManifest,
+    RemoteCache,
+    RepoStatus,
+    Shard,
+    merge_shard_outputs,
+)
+from static_analyzer.analysis_cache import STATIC_ANALYSIS_PKL
+from utils import get_cache_dir
+
+REPOS = [f"https://github.com/org/repo{i}" for i in range(40)]
+
+
+@pytest.mark.parametrize("spec", ["0/4", "5/4", "1/0", "abc", "1/2/3", "1-4"])
+def test_shard_parse_rejects_invalid_specs(spec: str):
+    with pytest.raises(ValueError):
+        Shard.parse(spec)
+
+
+def test_shards_partition_the_repo_list():
+    selections = [Shard.parse(f"{i}/4").select(REPOS) for i in range(1, 5)]
+
+    assert sorted(repo for sel in selections for repo in sel) == sorted(REPOS)
+    assert all(selections)
+
+
+def test_shard_assignment_is_stable_under_reordering_and_growth():
+    shard = Shard.parse("2/3")
+    before = set(shard.select(REPOS))
+    after = set(shard.select(list(reversed(REPOS)) + ["https://github.com/org/new"]))
+
+    assert before <= after
+
+
+def test_manifest_round_trips_and_marks_done(tmp_path: Path):
+    path = tmp_path / BATCH_MANIFEST_FILENAME
+    manifest = BatchManifest(path, Shard.parse("1/2"))
+    manifest.record(REPOS[0], RepoStatus.SUCCESS, project_name="repo0")
+    manifest.record(REPOS[1], RepoStatus.FAILED, error="boom")
+
+    reloaded = BatchManifest(path)
+    assert reloaded.is_done(REPOS[0])
+    assert not reloaded.is_done(REPOS[1])
+    assert json.loads(path.read_text())["shard"] == "1/2"
+
+
+def test_merge_combines_shard_outputs(tmp_path: Path):
+    for i, (url, name) in enumerate([(REPOS[0], "repo0"), (REPOS[1], "repo1")]):
+        shard_dir = tmp_path / f"shard{i}"
+        (shard_dir / name / ".codeboarding").mkdir(parents=True)
+        (shard_dir / name / ".codeboarding" / "analysis.json").write_text("{}")
+        BatchManifest(shard_dir / BATCH_MANIFEST_FILENAME).record(url, RepoStatus.SUCCESS, project_name=name)
+
+    merged = merge_shard_outputs([tmp_path / "shard0", tmp_path / "shard1"], tmp_path / "merged")
+
+    assert set(merged.repositories) == {REPOS[0], REPOS[1]}
+    assert (tmp_path / "merged" / "repo0" / ".codeboarding" / "analysis.json").exists()
+    assert (tmp_path / "merged" / "repo1" / ".codeboarding" / "analysis.json").exists()
+    assert (tmp_path / "merged" / BATCH_MANIFEST_FILENAME).exists()
+
+
+def test_merge_requires_shard_manifest(tmp_path: Path):
+    (tmp_path / "shard").mkdir()
+
+    with pytest.raises(FileNotFoundError):
+        merge_shard_outputs([tmp_path / "shard"], tmp_path / "merged")
+
+
+def test_directory_remote_cache_round_trip(tmp_path: Path):
+    cache = RemoteCache(str(tmp_path / "remote"))
+    repo_a, artifacts_a = tmp_path / "a", tmp_path / "a_out"
+    get_cache_dir(repo_a).mkdir(parents=True)
+    (get_cache_dir(repo_a) / "final_analysis_llm.sqlite").write_text("llm")
+    (get_cache_dir(repo_a) / "final_analysis_llm.sqlite.lock").write_text("")
+    artifacts_a.mkdir()
+    (artifacts_a / STATIC_ANALYSIS_PKL).write_text("pkl")
+    (artifacts_a / "analysis.json").write_text("{}")
+
+    cache.push("proj", repo_a, artifacts_a)
+    repo_b, artifacts_b = tmp_path / "b", tmp_path / "b_out"
+    cache.pull("proj", repo_b, artifacts_b)
+
+    assert (get_cache_dir(repo_b) / "final_analysis_llm.sqlite").read_text() == "llm"
+    assert not (get_cache_dir(repo_b) / "final_analysis_llm.sqlite.lock").exists()
+    assert (artifacts_b / STATIC_ANALYSIS_PKL).read_text() == "pkl"
+    assert not (artifacts_b / "analysis.json").exists()
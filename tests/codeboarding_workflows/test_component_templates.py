@@ -0,0 +1,79 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from codeboarding_workflows.component_templates import (
+    COMPONENT_TEMPLATES_MD,
+    ComponentRef,
+    component_symbols,
+    group_similar_components,
+    write_component_templates,
+)
+from diagram_analysis.analysis_json import build_unified_analysis_json
+
+
+def _component(cid: str, name: str, qualified_names: list[str]) -> Component:
+    methods = [MethodEntry(qualified_name=q, start_line=1, end_line=2, node_type="METHOD") for q in qualified_names]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path="x.py", methods=methods)],
+    )
+
+
+def _ref(project: str, symbols: set[str]) -> ComponentRef:
+    return ComponentRef(project, "1", f"{project} comp", frozenset(symbols))
+
+
+def test_symbols_ignore_the_package_a_copy_lives_in():
+    a = _component("1", "Health", ["svc_a.health.HealthHandler.check", "svc_a.health.HealthHandler.ready"])
+    b = _component("1", "Health", ["svc_b::health::HealthHandler::check", "svc_b.health.HealthHandler.Ready"])
+    assert component_symbols(a) == component_symbols(b) == {"healthhandler.check", "healthhandler.ready"}
+
+
+def test_grouping_is_deterministic_and_respects_the_threshold():
+    refs = [
+        _ref("svc-c", {"a", "b", "c", "d"}),
+        _ref("svc-a", {"a", "b", "c", "d"}),
+        _ref("svc-b", {"a", "b", "c", "e"}),  # 3/5 = 0.6 against svc-a
+        _ref("svc-d", set()),
+    ]
+
+    loose = group_similar_components(refs, 0.6)
+    strict = group_similar_components(list(reversed(refs)), 0.8)
+
+    assert [[m.project for m in g.members] for g in loose] == [["svc-a", "svc-b", "svc-c"], ["svc-d"]]
+    assert [[m.project for m in g.members] for g in strict] == [["svc-a", "svc-c"], ["svc-b"], ["svc-d"]]
+
+
+def test_write_component_templates_from_merged_workspace(tmp_path: Path):
+    for project in ("svc-a", "svc-b"):
+        analysis = AnalysisInsights(
+            description="",
+            components=[
+                _component("1", "Health", [f"{project}.health.Handler.check", f"{project}.health.Handler.ready"]),
+                _component("2", f"{project} Orders", [f"{project}.orders.{project}Service.place"]),
+            ],
+            components_relations=[],
+        )
+        analysis.files = {
+            "x.py": FileEntry(methods=[m for c in analysis.components for m in c.file_methods[0].methods])
+        }
+        out = tmp_path / project / ".codeboarding"
+        out.mkdir(parents=True)
+        (out / "analysis.json").write_text(
+            build_unified_analysis_json(
+                analysis, [], project, repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+            )
+        )
+
+    path = write_component_templates(tmp_path, ["svc-b", "svc-a"], 0.8)
+
+    payload = json.loads(path.read_text())
+    assert [(t["name"], t["count"]) for t in payload["templates"]] == [("Health", 2)]
+    assert payload["templates"][0]["shared_symbols"] == ["handler.check", "handler.ready"]
+    assert [u["project"] for u in payload["unique"]] == ["svc-a", "svc-b"]
+    assert "- **Health** ×2 — svc-a, svc-b" in (tmp_path / COMPONENT_TEMPLATES_MD).read_text()
@@ -16,7 +16,13 @@ from codeboarding_workflows.rendering import (
     _load_entries,
     project_relations_to_level,
     render_docs,
+    render_static_outputs,
 )
+from output_generators.preamble import DocsPreamble
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
 
 
 # ---------------------------------------------------------------------------
@@ -334,3 +340,51 @@ def test_load_entries_projects_per_level(tmp_path: Path):
     # 1->2 (aggregated); 1.1.1->3 collapses to 1->3; 1.1.1->1.1.2 collapses to a
     # 1->1 self-loop and is dropped.
     assert root_pairs == {("1", "2"), ("1", "3")}
+
+
+def test_render_docs_preamble_heads_only_the_root_doc(tmp_path: Path):
+    """``--title``/``--intro`` replace the root heading and land above its diagram, not on sub-pages."""
+    analysis_path = tmp_path / "analysis.json"
+    analysis_path.write_text(json.dumps(_make_depth3_unified_json()))
+    preamble = DocsPreamble(title="Fake Platform", intro="Read this *first*.")
+
+    for fmt in (".md", ".html"):
+        render_docs(
+            analysis_path,
+            repo_name="fake",
+            repo_ref="",
+            temp_dir=tmp_path,
+            format=fmt,
+            root_name="overview",
+            preamble=preamble,
+        )
+
+    md = (tmp_path / "overview.md").read_text()
+    assert md.startswith("# Fake Platform\n\nRead this *first*.\n")
+    assert md.index("Read this *first*.") < md.index("```mermaid")
+    assert "Fake Platform" not in (tmp_path / "Public.md").read_text()
+    html = (tmp_path / "overview.html").read_text()
+    assert html.index("<title>Fake Platform</title>") < html.index('<div class="intro">')
+
+
+def test_render_static_outputs_needs_no_analysis_json(tmp_path: Path):
+    repo = tmp_path / "repo"
+    cfg = CallGraph(language="go")
+    cfg.add_node(Node("app.server.Serve", NodeType.FUNCTION, str(repo / "app" / "server.go"), 1, 5))
+    cfg.add_node(Node("store.db.Open", NodeType.FUNCTION, str(repo / "store" / "db.go"), 1, 5))
+    cfg.add_edge("app.server.Serve", "store.db.Open")
+    static_analysis = StaticAnalysisResults()
+    static_analysis.add_cfg(Language.GO, cfg)
+
+    paths = render_static_outputs(static_analysis, repo_name="demo", repo_dir=repo, output_dir=tmp_path / "out")
+
+    assert sorted(path.name for path in paths) == [
+        "call_graph.dot",
+        "class_diagram.md",
+        "imports.dot",
+        "imports.md",
+        "static_analysis.json",
+    ]
+    assert all(path.is_file() for path in paths)
+    assert not (tmp_path / "out" / "analysis.json").exists()
+    assert '"app.server.Serve" -> "store.db.Open"' in (tmp_path / "out" / "call_graph.dot").read_text()
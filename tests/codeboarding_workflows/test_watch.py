@@ -0,0 +1,74 @@
+import threading
+import urllib.request
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from codeboarding_workflows.live_server import LIVERELOAD_PATH, RELOAD_SCRIPT, LiveReloadServer
+from codeboarding_workflows.watch import RepoWatcher, changed_paths, component_changes
+
+
+def test_watcher_batches_a_burst_of_saves_and_skips_ignored_files(tmp_path: Path):
+    (tmp_path / ".gitignore").write_text("build/\n")
+    (tmp_path / "app.py").write_text("x = 1\n")
+    (tmp_path / "build").mkdir()
+    (tmp_path / "notes.txt").write_text("")
+    ticks = iter(range(100))
+    watcher = RepoWatcher(tmp_path, poll_interval=0, debounce=3, clock=lambda: float(next(ticks)))
+
+    (tmp_path / "app.py").write_text("x = 22\n")
+    (tmp_path / "util.py").write_text("")
+    (tmp_path / "build" / "gen.py").write_text("")
+    (tmp_path / "notes.txt").write_text("edited")
+
+    assert watcher.wait_for_changes() == {"app.py", "util.py"}
+
+    (tmp_path / "util.py").unlink()
+    stop = threading.Event()
+    assert watcher.poll() == {"util.py"}
+    stop.set()
+    assert watcher.wait_for_changes(stop) == set()
+    assert changed_paths({"a.py": (1, 1), "b.py": (1, 1)}, {"a.py": (2, 1), "b.py": (1, 1)}) == {"a.py"}
+
+
+def test_live_server_injects_reload_script_and_streams_reloads(tmp_path: Path):
+    (tmp_path / "index.html").write_text("<html><body><h1>Overview</h1></body></html>")
+    server = LiveReloadServer(tmp_path, port=0).start()
+    try:
+        page = urllib.request.urlopen(server.url, timeout=5).read().decode()
+        assert page == f"<html><body><h1>Overview</h1>{RELOAD_SCRIPT}</body></html>"
+
+        with urllib.request.urlopen(server.url.rstrip("/") + LIVERELOAD_PATH, timeout=5) as stream:
+            threading.Timer(0.1, server.notify_reload).start()
+            assert stream.readline() == b"data: reload\n"
+    finally:
+        server.stop()
+
+
+def test_component_changes_since_the_last_render():
+    def component(cid: str, name: str, methods: list[str], description: str = "does things") -> Component:
+        group = FileMethodGroup(
+            file_path="app.py",
+            methods=[MethodEntry(qualified_name=q, start_line=1, end_line=2, node_type="FUNCTION") for q in methods],
+        )
+        return Component(name=name, description=description, key_entities=[], component_id=cid, file_methods=[group])
+
+    before = AnalysisInsights(
+        description="app",
+        components=[component("1", "API", ["app.get"]), component("2", "Store", ["app.save"])],
+        components_relations=[],
+    )
+    routes = [component("1.1", "Routes", ["app.get"])]
+    sub = AnalysisInsights(description="api", components=routes, components_relations=[])
+    after = AnalysisInsights(
+        description="app",
+        components=[component("1", "API", ["app.get", "app.post"]), component("3", "Cache", ["app.hit"])],
+        components_relations=[],
+    )
+
+    changes = component_changes([before, sub], [after, sub])
+
+    assert (changes.added, changes.changed, changes.removed) == (["Cache"], ["API"], ["Store"])
+    assert changes.summary() == "Components changed since the last render: added Cache; changed API; removed Store"
+    assert not component_changes([sub], [sub])
+    assert component_changes([sub], [sub]).summary() == "No component changed since the last render"
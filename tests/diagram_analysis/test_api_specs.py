@@ -0,0 +1,87 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.api_specs import is_generated_client, link_spec_operations
+from output_generators.markdown import generate_markdown
+from project_config import ProjectConfig
+
+_SPEC = {
+    "openapi": "3.0.0",
+    "paths": {
+        "/pets": {"get": {"operationId": "listPets"}, "post": {"summary": "no operationId"}},
+        "/pets/{petId}": {"get": {"operationId": "getPetById"}},
+    },
+}
+
+
+def _component(cid: str, name: str, file_path: str, methods: list[str]) -> Component:
+    entries = [MethodEntry(qualified_name=m, start_line=1, end_line=2, node_type="METHOD") for m in methods]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=file_path, methods=entries)],
+    )
+
+
+def test_generated_client_methods_link_to_spec_operations(tmp_path: Path):
+    (tmp_path / "api").mkdir()
+    (tmp_path / "api" / "openapi.json").write_text(json.dumps(_SPEC))
+    (tmp_path / "gen" / "petstore").mkdir(parents=True)
+    (tmp_path / "gen" / "petstore" / "pets_api.py").write_text(
+        "# coding: utf-8\n# NOTE: This class is auto generated by OpenAPI Generator\nclass PetsApi: ...\n"
+    )
+    (tmp_path / "app").mkdir()
+    (tmp_path / "app" / "main.py").write_text("def list_pets(): ...\n")
+    assert is_generated_client(tmp_path / "gen" / "petstore" / "pets_api.py")
+    assert not is_generated_client(tmp_path / "app" / "main.py")
+
+    client = _component(
+        "1",
+        "Pets Client",
+        "gen/petstore/pets_api.py",
+        ["petstore.PetsApi.list_pets_with_http_info", "petstore.PetsApi.get_pet_by_id", "petstore.PetsApi.helper"],
+    )
+    app = _component("2", "App", "app/main.py", ["app.main.list_pets"])
+    analysis = AnalysisInsights(description="", components=[client, app], components_relations=[])
+
+    linked = link_spec_operations(tmp_path, analysis, {}, ProjectConfig())
+
+    assert linked == 2
+    assert [(op.method, op.path, op.operation_id) for op in client.spec_operations] == [
+        ("GET", "/pets", "listPets"),
+        ("GET", "/pets/{petId}", "getPetById"),
+    ]
+    assert {op.spec_file for op in client.spec_operations} == {"api/openapi.json"}
+    assert app.spec_operations == []
+
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert 'spec_api_openapi_json[/"api/openapi.json"/]' in markdown
+    assert 'Pets_Client -. "implements 2 operations" .-> spec_api_openapi_json' in markdown
+    assert "- `GET /pets` (`listPets` in `api/openapi.json`) via `petstore.PetsApi.list_pets_with_http_info`" in markdown
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert loaded.components[0].spec_operations == client.spec_operations
+    assert loaded.components[1].spec_operations == []
+
+
+def test_configured_spec_and_go_client_name_variants(tmp_path: Path):
+    (tmp_path / "contracts").mkdir()
+    (tmp_path / "contracts" / "pets.json").write_text(json.dumps(_SPEC))
+    client = _component(
+        "1", "Client", "internal/client/client.gen.go", ["client.NewListPetsRequest", "client.GetPetByIdWithResponse"]
+    )
+    analysis = AnalysisInsights(description="", components=[client], components_relations=[])
+    config = ProjectConfig(sections={"openapi": {"specs": "contracts/pets.json"}})
+
+    link_spec_operations(tmp_path, analysis, {}, config, generated_files=["internal/client/client.gen.go"])
+
+    assert [op.operation_id for op in client.spec_operations] == ["listPets", "getPetById"]
+    assert {op.spec_file for op in client.spec_operations} == {"contracts/pets.json"}
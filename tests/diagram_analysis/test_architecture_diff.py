@@ -0,0 +1,83 @@
+import json
+from pathlib import Path
+
+import pytest
+
+from diagram_analysis.architecture_diff import (
+    DIFF_JSON_FILENAME,
+    DIFF_MARKDOWN_FILENAME,
+    RefRange,
+    diff_models,
+    render_diff_markdown,
+    render_diff_mermaid,
+    write_diff,
+)
+
+
+def _entity(name: str, signature: str | None = None) -> dict:
+    return {
+        "qualified_name": name,
+        "name": name.rsplit(".", 1)[-1],
+        "kind": "function",
+        "file": "app/server.go",
+        "line_start": 3,
+        "receiver": None,
+        "signature": {"text": signature, "parameters": [], "results": []} if signature else None,
+    }
+
+
+def _edge(source: str, target: str, kind: str = "call") -> dict:
+    return {"source": source, "target": target, "kind": kind}
+
+
+BASE = {
+    "entities": [_entity("app.Serve", "Serve()"), _entity("app.legacy"), _entity("db.Open", "Open()")],
+    "edges": [_edge("app.Serve", "app.legacy"), _edge("app.Serve", "db.Open")],
+}
+HEAD = {
+    "entities": [_entity("app.Serve", "Serve(port int)"), _entity("app.route"), _entity("db.Open", "Open()")],
+    "edges": [_edge("app.Serve", "app.route"), _edge("app.Serve", "db.Open")],
+}
+
+
+def test_ref_range_parses_two_and_three_dot_ranges():
+    assert RefRange.parse("main..HEAD") == RefRange("main", "HEAD")
+    assert RefRange.parse("main...feature") == RefRange("main", "feature", from_merge_base=True)
+    assert RefRange.parse("v1.2") == RefRange("v1.2", "HEAD")
+    assert str(RefRange.parse("main...feature")) == "main...feature"
+    with pytest.raises(ValueError):
+        RefRange.parse("..HEAD")
+
+
+def test_diff_reports_added_removed_and_changed_signatures():
+    diff = diff_models(BASE, HEAD, "main", "HEAD")
+
+    assert [entity["qualified_name"] for entity in diff.added_entities] == ["app.route"]
+    assert [entity["qualified_name"] for entity in diff.removed_entities] == ["app.legacy"]
+    assert [(change.qualified_name, change.changed_fields()) for change in diff.changed_entities] == [
+        ("app.Serve", ["signature"])
+    ]
+    assert diff.added_edges == [_edge("app.Serve", "app.route")]
+    assert diff.removed_edges == [_edge("app.Serve", "app.legacy")]
+    assert diff.summary() == "main..HEAD: +1 -1 ~1 entities, +1 -1 edges"
+
+
+def test_mermaid_colours_nodes_and_edges_by_status():
+    mermaid = render_diff_mermaid(diff_models(BASE, HEAD, "main", "HEAD"))
+
+    assert 'N1["app.legacy"]' in mermaid
+    assert "N0 -->|call| N2" in mermaid
+    assert "N0 -.->|call| N1" in mermaid
+    assert "class N2 added" in mermaid
+    assert "class N1 removed" in mermaid
+    assert "class N0 changed" in mermaid
+
+
+def test_identical_models_have_no_diagram(tmp_path: Path):
+    diff = diff_models(BASE, BASE, "main", "main")
+
+    assert diff.is_empty()
+    assert "```mermaid" not in render_diff_markdown(diff)
+    paths = write_diff(diff, tmp_path)
+    assert [path.name for path in paths] == [DIFF_MARKDOWN_FILENAME, DIFF_JSON_FILENAME]
+    assert json.loads(paths[1].read_text())["added_edges"] == []
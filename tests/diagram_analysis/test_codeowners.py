@@ -0,0 +1,74 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from agents.file_index_models import FileMethodGroup
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.codeowners import CodeOwners, assign_component_owners, load_codeowners
+from output_generators.markdown import generate_markdown
+
+CODEOWNERS = """\
+# Default owners
+*                       @acme/platform
+/src/billing/           @acme/payments @alice
+*.proto                 @acme/api  # wire formats
+docs/**/generated/      @acme/docs
+/src/billing/vendor/
+"""
+
+
+def _component(cid: str, name: str, files: list[str]) -> Component:
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=path, methods=[]) for path in files],
+    )
+
+
+def test_last_matching_rule_owns_a_file():
+    owners = CodeOwners.parse(CODEOWNERS)
+
+    assert owners.owners_for("README.md") == ("@acme/platform",)
+    assert owners.owners_for("src/billing/invoice.py") == ("@acme/payments", "@alice")
+    assert owners.owners_for("src/billing/api/v1/invoice.proto") == ("@acme/api",)
+    assert owners.owners_for("docs/guide/generated/index.md") == ("@acme/docs",)
+    assert owners.owners_for("src/billing/vendor/lib.py") == ()
+    assert owners.owners_for("lib/src/billing/x.py") == ("@acme/platform",)
+
+
+def test_components_are_owned_by_their_files_owners(tmp_path: Path):
+    (tmp_path / ".github").mkdir()
+    (tmp_path / ".github" / "CODEOWNERS").write_text(CODEOWNERS)
+    analysis = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "Billing", ["src/billing/invoice.py", "src/billing/tax.py", str(tmp_path / "main.py")]),
+            _component("2", "Platform", ["src/app.py"]),
+        ],
+        components_relations=[Relation(relation="runs on", src_name="Billing", dst_name="Platform")],
+    )
+
+    assign_component_owners(tmp_path, analysis, {}, load_codeowners(tmp_path))
+
+    assert [c.owners for c in analysis.components] == [
+        ["@acme/payments", "@alice", "@acme/platform"],
+        ["@acme/platform"],
+    ]
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert "_Owned by @acme/payments, @alice, @acme/platform._" in markdown
+    assert "_Owned by @acme/platform._" in markdown
+
+    analysis.components[0].owners = ["@acme/payments"]
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert "_Owned by @acme/payments. Depends on components owned by @acme/platform._" in markdown
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert [c.owners for c in loaded.components] == [["@acme/payments"], ["@acme/platform"]]
+
+    assign_component_owners(tmp_path, loaded, {}, None)
+    assert [c.owners for c in loaded.components] == [[], []]
@@ -0,0 +1,131 @@
+from unittest.mock import Mock, patch
+
+import pytest
+
+from agents.model_capabilities import ModelPricing
+from diagram_analysis import cost_estimate
+from diagram_analysis.cost_estimate import (
+    ASSUMED_OUTPUT_TOKENS,
+    CostEstimate,
+    count_tokens,
+    estimate_run,
+    plan_prompts,
+)
+from diagram_analysis.diagram_generator import DiagramGenerator
+from diagram_analysis.exceptions import UnpricedModelError
+from diagram_analysis.grouping import Grouping
+
+_OVERHEAD = {
+    "meta": 100,
+    "overview.final_analysis": 1_000,
+    "overview.api_surfaces": 800,
+    "overview.relations": 900,
+    "component.final_analysis": 1_200,
+    "component.api_surfaces": 700,
+    "component.relations": 750,
+}
+
+
+def test_prompts_follow_the_depth_level():
+    prompts, components = plan_prompts(300, [40, 60], depth_level=1, overhead=_OVERHEAD)
+
+    # Depth 1 stops at the overview: metadata plus its three prompts.
+    assert [p.step for p in prompts] == [
+        "meta",
+        "overview.final_analysis",
+        "overview.api_surfaces",
+        "overview.relations",
+    ]
+    assert [p.input_tokens for p in prompts] == [100, 1_300, 1_100, 1_200]
+    assert components == 2
+
+    prompts, components = plan_prompts(300, [40, 60], depth_level=3, overhead=_OVERHEAD)
+
+    # Each group is expanded, and each of its three assumed children once more.
+    assert len(prompts) == 4 + 3 * (2 + 2 * 3)
+    assert components == 2 + 2 * 3 + 2 * 3 * 3
+    first_component = prompts[4]
+    assert first_component.step == "component.final_analysis"
+    assert first_component.input_tokens == 1_200 + 40 + 3 * 40
+
+
+def _one_group_analysis(monkeypatch) -> None:
+    clusters = Mock()
+    clusters.get_cluster_ids.return_value = {1}
+    group = Mock()
+    group.llm_str.return_value = "group"
+    grouped = Mock(cluster_components=[group])
+    grouped.llm_str.return_value = "groups"
+    monkeypatch.setattr(cost_estimate, "build_all_cluster_results", lambda static_analysis: {"python": clusters})
+    monkeypatch.setattr(cost_estimate._Grouping, "deterministic_cluster_grouping", lambda self, results: grouped)
+    monkeypatch.setattr(cost_estimate, "_overhead", lambda provider, model_name: _OVERHEAD)
+    monkeypatch.setattr(cost_estimate, "_encoding", lambda model_name: None)
+
+
+def test_only_a_semantic_grouping_is_expanded(monkeypatch):
+    _one_group_analysis(monkeypatch)
+
+    semantic = estimate_run(Mock(), 2, "ollama", "llama3.1")
+    by_package = estimate_run(Mock(), 2, "ollama", "llama3.1", Grouping.PACKAGE)
+
+    assert len(semantic.prompts) == 4 + 3
+    assert [p.step for p in by_package.prompts] == [p.step for p in semantic.prompts[:4]]
+    assert by_package.components == 1
+
+
+def test_prompts_stop_at_the_llm_call_budget(monkeypatch):
+    _one_group_analysis(monkeypatch)
+
+    estimate = estimate_run(Mock(), 2, "ollama", "llama3.1", max_llm_calls=5)
+
+    assert [p.step for p in estimate.prompts] == [
+        "meta",
+        "overview.final_analysis",
+        "overview.api_surfaces",
+        "overview.relations",
+        "component.final_analysis",
+    ]
+
+
+def test_cost_and_summary():
+    prompts, components = plan_prompts(300, [40], depth_level=2, overhead=_OVERHEAD)
+    estimate = CostEstimate("openai", "gpt-5", depth_level=2, components=components, prompts=prompts)
+
+    assert estimate.cost is None
+    assert "cost:       unknown" in estimate.summary()
+
+    estimate.pricing = ModelPricing(1.0, 10.0)
+    expected = (estimate.input_tokens * 1.0 + len(prompts) * ASSUMED_OUTPUT_TOKENS * 10.0) / 1_000_000
+    assert estimate.cost == pytest.approx(expected)
+    assert f"~${expected:.2f}" in estimate.summary()
+
+
+def test_max_cost_fails_fast_on_an_unpriced_model():
+    generator = object.__new__(DiagramGenerator)
+    generator.max_cost = 1.0
+    unpriced = CostEstimate("openai", "gpt-9", depth_level=1)
+
+    with (
+        patch.object(DiagramGenerator, "estimate_cost", return_value=unpriced),
+        pytest.raises(UnpricedModelError, match="set CB_PRICE_OPENAI_GPT_9="),
+    ):
+        generator._enforce_max_cost()
+
+
+def test_token_count_falls_back_to_characters(monkeypatch):
+    monkeypatch.setattr(cost_estimate, "_encoding", lambda model_name: None)
+
+    assert count_tokens("x" * 70, "ollama", "llama3.1") == 20
+
+
+def test_anthropic_tokens_are_counted_by_anthropic():
+    with patch("diagram_analysis.cost_estimate.ChatAnthropic") as chat_anthropic:
+        chat_anthropic.return_value.get_num_tokens_from_messages.return_value = 42
+        cost_estimate._anthropic_model.cache_clear()
+
+        assert count_tokens("x" * 70, "anthropic", "claude-sonnet-4-5") == 42
+
+    chat_anthropic.assert_called_once_with(model_name="claude-sonnet-4-5")
+    (messages,), _kwargs = chat_anthropic.return_value.get_num_tokens_from_messages.call_args
+    assert messages[0].content == "x" * 70
+    cost_estimate._anthropic_model.cache_clear()
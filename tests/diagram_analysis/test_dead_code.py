@@ -0,0 +1,120 @@
+from pathlib import Path
+
+from diagram_analysis.dead_code import build_dead_code_report, dead_code_markdown, write_dead_code_report
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+
+def _results(repo: Path) -> StaticAnalysisResults:
+    def path(rel: str) -> str:
+        return str(repo / rel)
+
+    cfg = CallGraph(language="go")
+    for qname, node_type, rel, line in [
+        ("cmd.app.main", NodeType.FUNCTION, "cmd/app.go", 5),
+        ("models.task.Task", NodeType.CLASS, "models/task.go", 3),
+        ("models.task.(Task).Speak", NodeType.METHOD, "models/task.go", 9),
+        ("models.task.(Task).Serialize", NodeType.METHOD, "models/task.go", 12),
+        ("models.task.NewTask", NodeType.FUNCTION, "models/task.go", 15),
+        ("models.task.formatTitle", NodeType.FUNCTION, "models/task.go", 20),
+        ("services.speaker.Speaker", NodeType.INTERFACE, "services/speaker.go", 3),
+        ("services.speaker.(Speaker).Speak", NodeType.METHOD, "services/speaker.go", 4),
+        ("unused.orphan.OrphanClass", NodeType.CLASS, "unused/orphan.go", 3),
+        ("unused.orphan.(OrphanClass).OrphanMethod", NodeType.METHOD, "unused/orphan.go", 5),
+        ("unused.orphan.NeverCalled", NodeType.FUNCTION, "unused/orphan.go", 9),
+    ]:
+        cfg.add_node(Node(qname, node_type, path(rel), line, line + 2))
+    cfg.add_edge("cmd.app.main", "models.task.NewTask")
+    cfg.add_edge("cmd.app.main", "services.speaker.(Speaker).Speak")
+    cfg.add_reference_edge("models.task.Task", "services.speaker.Speaker", EdgeKind.IMPLEMENTS)
+    cfg.add_reference_edge("models.task.NewTask", "models.task.Task", EdgeKind.TYPEREF)
+    # Containment is not a reference.
+    cfg.add_reference_edge("unused.orphan.(OrphanClass).OrphanMethod", "unused.orphan.OrphanClass", EdgeKind.CONTAINS)
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    constant = Node("unused.orphan.UnusedConstant", NodeType.CONSTANT, path("unused/orphan.go"), 1, 1)
+    results.add_references(Language.GO, [*cfg.nodes.values(), constant])
+    results.add_source_files(Language.GO, sorted({node.file_path for node in cfg.nodes.values()}))
+    results.add_class_hierarchy(
+        Language.GO,
+        {
+            "models.task.Task": {"superclasses": [], "subclasses": [], "interfaces": ["services.speaker.Speaker"]},
+            "services.speaker.Speaker": {"superclasses": [], "subclasses": [], "implementations": ["models.task.Task"]},
+        },
+    )
+    results.add_package_dependencies(
+        Language.GO,
+        {
+            "cmd": {"imports": ["models", "services"], "imported_by": []},
+            "models": {"imports": ["services"], "imported_by": ["cmd"]},
+            "services": {"imports": [], "imported_by": ["cmd", "models"]},
+            "unused": {"imports": [], "imported_by": ["models.tests"]},
+        },
+    )
+    return results
+
+
+def _names(group) -> dict[str, list[str]]:
+    return {package: [s.qualified_name for s in symbols] for package, symbols in group.items()}
+
+
+def test_unreferenced_symbols_are_grouped_by_package(tmp_path: Path):
+    report = build_dead_code_report(_results(tmp_path), repo_dir=tmp_path)
+
+    assert _names(report.unreferenced) == {
+        "models": ["models.task.(Task).Serialize", "models.task.formatTitle"],
+        "unused": [
+            "unused.orphan.UnusedConstant",
+            "unused.orphan.OrphanClass",
+            "unused.orphan.(OrphanClass).OrphanMethod",
+            "unused.orphan.NeverCalled",
+        ],
+    }
+    never_called = report.unreferenced["unused"][3]
+    assert (never_called.kind, never_called.location) == ("Function", "unused/orphan.go:9")
+
+
+def test_symbols_only_tests_name_are_flagged_separately(tmp_path: Path):
+    report = build_dead_code_report(_results(tmp_path), repo_dir=tmp_path, test_names={"formatTitle"})
+
+    assert _names(report.test_only) == {"models": ["models.task.formatTitle"]}
+    assert "models.task.formatTitle" not in _names(report.unreferenced)["models"]
+
+
+def test_packages_nothing_but_tests_import_are_called_out(tmp_path: Path):
+    report = build_dead_code_report(_results(tmp_path), repo_dir=tmp_path)
+
+    # cmd is imported by nobody either, but it holds the entry point.
+    assert report.unimported_packages == {"unused": 4}
+
+
+def test_markdown_and_test_scan(tmp_path: Path):
+    (tmp_path / "models").mkdir()
+    (tmp_path / "models" / "task_test.go").write_text("func TestFormat(t *testing.T) { formatTitle(\"x\") }\n")
+
+    output, report = write_dead_code_report(_results(tmp_path), tmp_path, tmp_path / "dead_code.md")
+    text = output.read_text()
+
+    assert _names(report.test_only) == {"models": ["models.task.formatTitle"]}
+    assert text.startswith("# Dead code\n\n5 symbols are never referenced, 1 only from tests, and 1 packages")
+    assert "- `unused` (4 symbols)" in text
+    assert "- `unused.orphan.NeverCalled` (Function) at `unused/orphan.go:9`" in text
+    assert dead_code_markdown(report) == text
+
+
+def test_swift_main_type_and_its_members_are_entry_points(tmp_path: Path):
+    source = tmp_path / "Sources" / "Zoo" / "ZooApp.swift"
+    source.parent.mkdir(parents=True)
+    source.write_text("import SwiftUI\n\n@main\nstruct ZooApp: App {\n    var body: some Scene { Window() }\n}\n")
+    cfg = CallGraph(language="swift")
+    cfg.add_node(Node("Zoo.ZooApp", NodeType.STRUCT, str(source), 4, 6))
+    cfg.add_node(Node("Zoo.ZooApp.body", NodeType.METHOD, str(source), 5, 5))
+    cfg.add_node(Node("Zoo.Window", NodeType.STRUCT, str(tmp_path / "Sources" / "Zoo" / "Window.swift"), 1, 3))
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.SWIFT, cfg)
+
+    report = build_dead_code_report(results, repo_dir=tmp_path)
+
+    assert _names(report.unreferenced) == {"Sources.Zoo": ["Zoo.Window"]}
@@ -0,0 +1,113 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.deprecation import (
+    DEPRECATED_COMPONENT_NAME,
+    collapse_deprecated_components,
+    component_definitions,
+    find_deprecated_symbols,
+    mark_deprecated_components,
+)
+from output_generators.markdown import generate_markdown
+from project_config import ProjectConfig
+
+_LEGACY_PY = """\
+import warnings
+
+
+# Deprecated: use new_api.fetch instead.
+def fetch():
+    return 1
+
+
+@deprecated("use Client")
+def connect():
+    return 2
+
+
+def shim():
+    warnings.warn("shim is going away", DeprecationWarning)
+    return 3
+"""
+
+_CURRENT_PY = """\
+# deprecated helpers live in legacy.py
+
+def run():
+    return fetch()
+"""
+
+
+def _component(cid: str, name: str, file_path: str, methods: list[tuple[str, int, int]]) -> Component:
+    entries = [MethodEntry(qualified_name=q, start_line=s, end_line=e, node_type="FUNCTION") for q, s, e in methods]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=file_path, methods=entries)],
+    )
+
+
+def _analysis() -> AnalysisInsights:
+    legacy = _component("1", "Legacy API", "legacy.py", [("legacy.fetch", 5, 6), ("legacy.connect", 10, 11)])
+    shims = _component("2", "Shims", "legacy.py", [("legacy.shim", 14, 16)])
+    app = _component("3", "App", "app.py", [("app.run", 3, 4)])
+    relations = [
+        Relation(relation="fetches via", src_name="App", dst_name="Legacy API", src_id="3", dst_id="1"),
+        Relation(relation="wraps", src_name="Shims", dst_name="Legacy API", src_id="2", dst_id="1"),
+    ]
+    return AnalysisInsights(description="", components=[legacy, app, shims], components_relations=relations)
+
+
+def test_markers_and_config_mark_deprecated_components(tmp_path: Path):
+    (tmp_path / "legacy.py").write_text(_LEGACY_PY)
+    (tmp_path / "app.py").write_text(_CURRENT_PY)
+    analysis = _analysis()
+
+    deprecated = find_deprecated_symbols(tmp_path, component_definitions(analysis), ProjectConfig())
+    assert deprecated.qualified_names == {"legacy.fetch", "legacy.connect", "legacy.shim"}
+
+    config = ProjectConfig(sections={"deprecated": {"symbols": ["app.*"]}})
+    assert "app.run" in find_deprecated_symbols(tmp_path, component_definitions(analysis), config)
+
+    mark_deprecated_components(analysis, {}, deprecated)
+    assert [c.deprecated for c in analysis.components] == [True, False, True]
+
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert "    class Legacy_API,Shims deprecated" in markdown
+    assert "_Deprecated._ Still used by App." in markdown
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert [c.deprecated for c in loaded.components] == [True, False, True]
+    assert loaded.components[0].deprecated_symbols == ["legacy.connect", "legacy.fetch"]
+
+
+def test_hide_deprecated_collapses_into_one_component():
+    analysis = _analysis()
+    for component in analysis.components:
+        component.deprecated = component.name != "App"
+    sub_analyses = {
+        "1": AnalysisInsights(
+            description="",
+            components=[_component("1.1", "Fetch", "legacy.py", [("legacy.fetch", 5, 6)])],
+            components_relations=[],
+        ),
+        "1.1": AnalysisInsights(description="", components=[], components_relations=[]),
+    }
+
+    collapsed = collapse_deprecated_components(analysis, sub_analyses)
+
+    assert collapsed is not None and collapsed.component_id == "1"
+    assert [c.name for c in analysis.components] == [DEPRECATED_COMPONENT_NAME, "App"]
+    assert collapsed.description.endswith("Still used by App.")
+    assert [(r.src_name, r.dst_name, r.dst_id) for r in analysis.components_relations] == [
+        ("App", DEPRECATED_COMPONENT_NAME, "1")
+    ]
+    assert sub_analyses == {}
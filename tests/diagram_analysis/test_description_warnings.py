@@ -0,0 +1,81 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from diagram_analysis.description_warnings import (
+    DESCRIPTION_WARNINGS_FILENAME,
+    WarningKind,
+    find_description_warnings,
+    write_description_warnings,
+)
+
+
+def _component(cid: str, name: str, description: str) -> Component:
+    return Component(name=name, description=description, key_entities=[], component_id=cid)
+
+
+def _relation(src: Component, dst: Component) -> Relation:
+    return Relation(
+        relation="calls", src_name=src.name, dst_name=dst.name, src_id=src.component_id, dst_id=dst.component_id
+    )
+
+
+def _analysis(components: list[Component], relations: list[Relation]) -> AnalysisInsights:
+    return AnalysisInsights(description="root", components=components, components_relations=relations)
+
+
+def test_flags_no_dependency_claim_with_outbound_edges():
+    api = _component("1", "API", "A self-contained HTTP layer.")
+    store = _component("2", "Storage", "Persists records.")
+
+    warnings = find_description_warnings(_analysis([api, store], [_relation(api, store)]), {})
+
+    assert [(w.component_id, w.kind) for w in warnings] == [("1", WarningKind.CLAIMS_NO_DEPENDENCIES)]
+    assert "Storage" in warnings[0].message
+
+
+def test_no_dependency_claim_is_fine_without_outbound_edges():
+    api = _component("1", "API", "A standalone HTTP layer.")
+    store = _component("2", "Storage", "Persists records.")
+
+    assert find_description_warnings(_analysis([api, store], [_relation(store, api)]), {}) == []
+
+
+def test_flags_named_dependency_missing_from_graph():
+    api = _component("1", "API", "Handles requests and delegates to the Storage layer.")
+    store = _component("2", "Storage", "Persists records.")
+
+    warnings = find_description_warnings(_analysis([api, store], []), {})
+
+    assert [(w.component_id, w.kind) for w in warnings] == [("1", WarningKind.UNLINKED_DEPENDENCY)]
+
+
+def test_named_dependency_satisfied_by_descendant_edge():
+    api = _component("1", "API", "Uses the Storage layer for persistence.")
+    store = _component("2", "Storage", "Persists records.")
+    writer = _component("2.1", "Writer", "Writes rows.")
+    sub = {"2": _analysis([writer], [])}
+
+    root = _analysis([api, store], [_relation(api, writer)])
+
+    assert find_description_warnings(root, sub) == []
+
+
+def test_flags_no_dependents_claim_with_inbound_edges():
+    api = _component("1", "API", "Entry point.")
+    legacy = _component("2", "Legacy", "Old helpers; has no dependents.")
+
+    warnings = find_description_warnings(_analysis([api, legacy], [_relation(api, legacy)]), {})
+
+    assert [(w.component_id, w.kind) for w in warnings] == [("2", WarningKind.CLAIMS_NO_DEPENDENTS)]
+
+
+def test_write_description_warnings(tmp_path: Path):
+    api = _component("1", "API", "Calls Storage directly.")
+    store = _component("2", "Storage", "Persists records.")
+
+    path = write_description_warnings(tmp_path, _analysis([api, store], []), {})
+
+    assert path == tmp_path / DESCRIPTION_WARNINGS_FILENAME
+    payload = json.loads(path.read_text())
+    assert payload["warnings"][0]["kind"] == "unlinked_dependency"
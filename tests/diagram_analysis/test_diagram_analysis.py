@@ -35,6 +35,7 @@ from diagram_analysis.analysis_json import (
 )
 from diagram_analysis.cluster_delta import ClusterMemberDelta, ClusterRef, LanguageStructuralDiff, StructuralClusterDiff
 from diagram_analysis.diagram_generator import (
+    CALL_BUDGET_REACHED,
     DiagramGenerator,
     _child_scope_needs_recursive_update,
     _component_depth,
@@ -42,6 +43,7 @@ from diagram_analysis.diagram_generator import (
 )
 from diagram_analysis.exceptions import IncrementalCacheMissingError
 from diagram_analysis.io_utils import load_analysis_metadata, save_analysis
+from monitoring.stats import RunStats
 from repo_utils.change_detector import ChangeSet
 from static_analyzer.analysis_cache import StaticAnalysisCache
 from static_analyzer.analysis_result import StaticAnalysisResults
@@ -1104,6 +1106,8 @@ class TestDiagramGenerator(unittest.TestCase):
             depth_cap,
             sub_analyses,
             file_coverage_summary,
+            llm_providers=None,
+            analysis_coverage=None,
         ):
             captured["expandable_components"] = expandable_components
             return "{}"
@@ -1263,6 +1267,43 @@ class TestDiagramGenerator(unittest.TestCase):
         self.assertEqual(set(sub_analyses), {"1.1"})
         self.assertEqual(mock_save_analysis.call_count, 1)
 
+    @patch("diagram_analysis.diagram_generator.save_analysis")
+    @patch("diagram_analysis.diagram_generator.get_expandable_components", return_value=[])
+    @patch("diagram_analysis.diagram_generator.MONITORING_CALLBACK")
+    def test_generate_subcomponents_stops_at_llm_call_budget(self, mock_callback, _mock_expandable, _mock_save):
+        mock_callback.stats = RunStats()
+        mock_callback.stats.llm_calls = 7  # requests made before this run
+        gen = DiagramGenerator(
+            repo_location=self.repo_location,
+            temp_folder=self.temp_folder,
+            repo_name="test_repo",
+            output_dir=self.output_dir,
+            depth_level=2,
+            run_id="test-run-id",
+            log_path="test_repo/test-run-log",
+        )
+        gen.deterministic = True
+        gen.max_llm_calls = 3
+        gen.details_agent = Mock()
+
+        def run(component):
+            mock_callback.stats.llm_calls += 2
+            return AnalysisInsights(description=component.name, components=[], components_relations=[]), {}
+
+        gen.details_agent.run.side_effect = run
+        root_analysis = AnalysisInsights(description="root", components=[], components_relations=[])
+        components = [
+            Component(name=f"C{i}", description="", key_entities=[], component_id=str(i)) for i in range(1, 5)
+        ]
+
+        _expanded, sub_analyses = gen._generate_subcomponents(root_analysis, components)
+
+        # 2 calls after the first component, 4 after the second: the rest never start.
+        self.assertEqual(set(sub_analyses), {"1", "2"})
+        self.assertEqual([c.not_described for c in components], ["", "", CALL_BUDGET_REACHED, CALL_BUDGET_REACHED])
+        component_json = from_component_to_json_component(components[2], [], self.repo_location)
+        self.assertEqual(component_json.not_described, "call budget reached")
+
     def test_removed_only_incremental_update_marks_scope_for_relation_refresh(self):
         gen = DiagramGenerator(
             repo_location=self.repo_location,
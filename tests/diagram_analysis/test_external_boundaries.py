@@ -0,0 +1,71 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from agents.file_index_models import FileMethodGroup
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.external_boundaries import (
+    EXTERNAL_MARKER_FILENAME,
+    load_external_boundaries,
+    mark_external_components,
+)
+from output_generators.markdown import generated_mermaid_str
+from project_config import ProjectConfig
+
+
+def _component(cid: str, name: str, files: list[str]) -> Component:
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=f) for f in files],
+    )
+
+
+def test_config_patterns_and_marker_directories(tmp_path: Path):
+    (tmp_path / "gen" / "billing").mkdir(parents=True)
+    (tmp_path / "gen" / "billing" / EXTERNAL_MARKER_FILENAME).touch()
+    config = ProjectConfig(sections={"boundaries": {"external": ["clients/payments/"]}})
+
+    boundaries = load_external_boundaries(
+        tmp_path, config, [str(tmp_path / "gen" / "billing" / "api.py"), "app/main.py"]
+    )
+
+    assert boundaries.patterns == ["clients/payments", "gen/billing"]
+    assert boundaries.is_external("gen/billing/v1/client.py")
+    assert boundaries.is_external("clients/payments/client.go")
+    assert not boundaries.is_external("gen/billing_utils.py")
+    assert not boundaries.is_external("app/main.py")
+
+
+def test_external_components_render_and_round_trip(tmp_path: Path):
+    app = _component("1", "App", ["app/main.py"])
+    client = _component("2", "Billing Client", ["gen/billing/api.py", "gen/billing/models.py"])
+    mixed = _component("3", "Glue", ["app/glue.py", "gen/billing/extra.py"])
+    analysis = AnalysisInsights(
+        description="",
+        components=[app, client, mixed],
+        components_relations=[
+            Relation(relation="charges via", src_name="App", dst_name="Billing Client", src_id="1", dst_id="2"),
+            Relation(relation="wires", src_name="App", dst_name="Glue", src_id="1", dst_id="3"),
+        ],
+    )
+    boundaries = load_external_boundaries(
+        tmp_path, ProjectConfig(sections={"boundaries": {"external": "gen/billing"}}), []
+    )
+
+    mark_external_components(analysis, {}, boundaries)
+
+    assert [c.external for c in analysis.components] == [False, True, False]
+    mermaid = generated_mermaid_str(analysis, expanded_components=set(), repo_ref="", project="demo")
+    assert 'Billing_Client{{"Billing Client"}}' in mermaid
+    assert 'App -. "charges via" .-> Billing_Client' in mermaid
+    assert 'App -- "wires" --> Glue' in mermaid
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    assert '"external": false' not in unified
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert [c.external for c in loaded.components] == [False, True, False]
@@ -0,0 +1,83 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.external_calls import assign_external_calls
+from output_generators.markdown import generate_markdown
+
+_REPORT_GO = """package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acme/zoo/models"
+	"github.com/spf13/cobra"
+)
+
+// Render prints fmt.Sprintf("%d") in a comment, which is not a call.
+func Render(names []string, cmd *cobra.Command) string {
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(strings.ToUpper(name))
+		sb.WriteString(", ")
+	}
+	fmt.Println(models.Count(names), "animals")
+	fmt.Println(sb.String())
+	cmd.Println("done")
+	return strings.Join(names, "; ")
+}
+
+func Quiet() {
+	out := &strings.Builder{}
+	out.Reset()
+}
+"""
+
+
+def _component(cid: str, name: str, file_path: str, methods: list[tuple[str, int, int]]) -> Component:
+    entries = [MethodEntry(qualified_name=q, start_line=s, end_line=e, node_type="FUNCTION") for q, s, e in methods]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=file_path, methods=entries)],
+    )
+
+
+def test_calls_into_outside_packages_are_counted_per_package(tmp_path: Path):
+    (tmp_path / "go.mod").write_text("module github.com/acme/zoo\n\ngo 1.22\n")
+    (tmp_path / "report").mkdir()
+    (tmp_path / "report" / "report.go").write_text(_REPORT_GO)
+    (tmp_path / "models").mkdir()
+    (tmp_path / "models" / "count.go").write_text("package models\n\nfunc Count(n []string) int { return len(n) }\n")
+    report = _component("1", "Report", "report/report.go", [("report.Render", 12, 22), ("report.Quiet", 24, 27)])
+    models = _component("2", "Models", "models/count.go", [("models.Count", 3, 3)])
+    analysis = AnalysisInsights(description="", components=[report, models], components_relations=[])
+
+    assign_external_calls(tmp_path, analysis, {}, enabled=True)
+
+    # strings: Builder.WriteString, ToUpper, Builder.String, Join, Builder.Reset; fmt: Println once per function.
+    assert [(entry.package, entry.calls) for entry in report.external_calls] == [
+        ("fmt", 1),
+        ("github.com/spf13/cobra", 1),
+        ("strings", 5),
+    ]
+    assert models.external_calls == []
+
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert 'ext_strings{{"strings"}}' in markdown
+    assert 'Report -. "5 calls" .-> ext_strings' in markdown
+    assert 'Report -. "1 call" .-> ext_github_com_spf13_cobra' in markdown
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert loaded.components[0].external_calls == report.external_calls
+
+    assign_external_calls(tmp_path, analysis, {}, enabled=False)
+    assert report.external_calls == []
@@ -0,0 +1,135 @@
+import json
+from pathlib import Path
+
+import pytest
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, RelationEdge, SourceCodeReference
+from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.focus import FocusError, load_symbol_graph, render_mermaid, spotlight
+from main import main
+from static_analyzer.analysis_cache import StaticAnalysisCache
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+# api.handle -> services.processor.dispatch -> services.processor.route -> store.save
+#                                            -> store.load
+# cli.run    -> services.processor.dispatch
+CALLS = [
+    ("api.handle", "services.processor.dispatch"),
+    ("cli.run", "services.processor.dispatch"),
+    ("services.processor.dispatch", "services.processor.route"),
+    ("services.processor.dispatch", "store.load"),
+    ("services.processor.route", "store.save"),
+]
+
+
+def _component(cid: str, name: str, path: str, methods: list[str]) -> Component:
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[
+            FileMethodGroup(
+                file_path=path,
+                methods=[
+                    MethodEntry(qualified_name=m, start_line=1, end_line=2, node_type="FUNCTION") for m in methods
+                ],
+            )
+        ],
+    )
+
+
+def _reference(name: str, path: str) -> SourceCodeReference:
+    return SourceCodeReference(qualified_name=name, reference_file=path, reference_start_line=1, reference_end_line=2)
+
+
+def _write_run(repo: Path, with_pkl: bool) -> Path:
+    analysis = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "Entry Points", "api.py", ["api.handle", "cli.run"]),
+            _component(
+                "2", "Processing", "services/processor.py", ["services.processor.dispatch", "services.processor.route"]
+            ),
+            _component("3", "Storage", "store.py", ["store.load", "store.save"]),
+        ],
+        components_relations=[
+            Relation(
+                relation="dispatches",
+                src_name="Entry Points",
+                dst_name="Processing",
+                all_edges=[
+                    RelationEdge(
+                        source=_reference("api.handle", "api.py"),
+                        target=_reference("services.processor.dispatch", "services/processor.py"),
+                    )
+                ],
+            )
+        ],
+    )
+    analysis.files = {
+        group.file_path: FileEntry(methods=group.methods) for c in analysis.components for group in c.file_methods
+    }
+    out = repo / ".codeboarding"
+    out.mkdir(parents=True)
+    path = out / "analysis.json"
+    path.write_text(
+        build_unified_analysis_json(
+            analysis, [], "demo", repo_dir=repo, source_tree_hash="", depth_cap=1, sub_analyses={}
+        )
+    )
+    if with_pkl:
+        graph = CallGraph(language="python")
+        for i, name in enumerate(sorted({n for edge in CALLS for n in edge})):
+            module = name.rsplit(".", 1)[0].replace(".", "/")
+            graph.add_node(Node(name, NodeType.FUNCTION, str(repo / f"{module}.py"), 10 * i + 1, 10 * i + 5))
+        for src, dst in CALLS:
+            graph.add_edge(src, dst)
+        results = StaticAnalysisResults()
+        results.add_cfg(Language.PYTHON, graph)
+        StaticAnalysisCache(out, repo).save(results)
+    return path
+
+
+def test_spotlight_follows_callers_and_callees_up_to_the_hop_limit(tmp_path: Path):
+    path = _write_run(tmp_path, with_pkl=True)
+    root, subs = parse_unified_analysis(json.loads(path.read_text()))
+    graph = load_symbol_graph(path, root, subs)
+
+    one = spotlight(graph, root, "processor.dispatch", hops=1)
+    assert one.symbol == "services.processor.dispatch"
+    assert one.distances == {
+        "api.handle": -1,
+        "cli.run": -1,
+        "services.processor.dispatch": 0,
+        "services.processor.route": 1,
+        "store.load": 1,
+    }
+    assert ("services.processor.route", "store.save") not in one.edges
+    assert one.components["store.load"] == "Storage"
+
+    two = spotlight(graph, root, "services.processor.dispatch", hops=2)
+    assert two.distances["store.save"] == 2
+    mermaid = render_mermaid(two)
+    assert mermaid.startswith("graph LR\n")
+    assert 'subgraph C2["Storage"]' in mermaid
+    assert '[["services.processor.dispatch"]]' in mermaid
+    assert mermaid.count(" --> ") == len(CALLS)
+
+    with pytest.raises(FocusError, match="No symbol 'missing'"):
+        spotlight(graph, root, "missing", hops=1)
+
+
+def test_focus_command_falls_back_to_analysis_json_edges(tmp_path: Path, capsys):
+    path = _write_run(tmp_path, with_pkl=False)
+
+    main(["focus", str(path), "--symbol", "dispatch", "--hops", "2", "--format", "json"])
+
+    rendered = json.loads(capsys.readouterr().out)
+    assert rendered["complete"] is False
+    assert [s["name"] for s in rendered["symbols"]] == ["api.handle", "services.processor.dispatch"]
+    assert rendered["edges"] == [{"source": "api.handle", "target": "services.processor.dispatch"}]
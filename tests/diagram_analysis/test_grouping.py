@@ -0,0 +1,102 @@
+from pathlib import Path
+from unittest.mock import MagicMock
+
+from agents.abstraction_agent import AbstractionAgent
+from agents.agent_responses import MetaAnalysisInsights
+from diagram_analysis.grouping import ROOT_DIRECTORY_NAME, Grouping, group_nodes
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+FILES = {
+    "main.go": "package main\n",
+    "models/dog.go": "package models\n",
+    "services/walker.go": "package services\n",
+    "utils/log.go": "package utils\n",
+    "unused/orphan.go": "package unused\n",
+    "cmd/tool/main.go": "package main\n",
+}
+
+
+def _results(repo: Path, with_tool: bool = False) -> StaticAnalysisResults:
+    for rel, text in FILES.items():
+        (repo / rel).parent.mkdir(parents=True, exist_ok=True)
+        (repo / rel).write_text(text + "\nfunc f() {}\n" * 4)
+    cfg = CallGraph(language="go")
+    nodes = [
+        ("main.main", "main.go", 3),
+        ("models.dog.NewDog", "models/dog.go", 3),
+        ("models.dog.Bark", "models/dog.go", 9),
+        ("services.walker.Walk", "services/walker.go", 3),
+        ("services.walker.Feed", "services/walker.go", 9),
+        ("utils.log.Print", "utils/log.go", 3),
+        ("unused.orphan.Orphan", "unused/orphan.go", 3),
+    ]
+    if with_tool:
+        nodes.append(("cmd.tool.main.main", "cmd/tool/main.go", 3))
+    for qname, rel, line in nodes:
+        cfg.add_node(Node(qname, NodeType.FUNCTION, str(repo / rel), line, line + 4))
+    cfg.add_edge("main.main", "models.dog.NewDog")
+    cfg.add_edge("main.main", "services.walker.Walk")
+    cfg.add_edge("models.dog.NewDog", "utils.log.Print")
+    cfg.add_edge("services.walker.Walk", "models.dog.Bark")
+    cfg.add_edge("services.walker.Feed", "utils.log.Print")
+    cfg.add_edge("services.walker.Walk", "utils.log.Print")
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    return results
+
+
+def test_package_grouping_makes_one_group_per_go_package(tmp_path: Path):
+    groups = group_nodes(_results(tmp_path), tmp_path, Grouping.PACKAGE)
+
+    assert [group.name for group in groups] == ["main", "models", "services", "unused", "utils"]
+    assert [node.fully_qualified_name for node in groups[2].nodes] == ["services.walker.Walk", "services.walker.Feed"]
+    assert groups[2].description(Grouping.PACKAGE) == "Package `services` in `services/`: 1 file, 2 symbols."
+
+
+def test_same_package_names_are_told_apart_by_directory(tmp_path: Path):
+    package_names = [group.name for group in group_nodes(_results(tmp_path, True), tmp_path, Grouping.PACKAGE)]
+    directory_names = [group.name for group in group_nodes(_results(tmp_path, True), tmp_path, Grouping.DIRECTORY)]
+
+    assert package_names[:2] == [f"main ({ROOT_DIRECTORY_NAME})", "main (cmd/tool)"]
+    assert directory_names == [ROOT_DIRECTORY_NAME, "cmd/tool", "models", "services", "unused", "utils"]
+
+
+def test_static_grouping_aggregates_the_calls_between_packages(tmp_path: Path):
+    agent = AbstractionAgent(
+        repo_dir=tmp_path,
+        static_analysis=_results(tmp_path),
+        project_name="zoo",
+        meta_context=MetaAnalysisInsights(
+            project_type="service",
+            domain="pets",
+            architectural_patterns=[],
+            expected_components=[],
+            technology_stack=["Go"],
+            architectural_bias="",
+        ),
+        agent_llm=MagicMock(),
+        parsing_llm=MagicMock(),
+        grouping=Grouping.PACKAGE,
+    )
+
+    analysis, _ = agent.run()
+
+    assert [(c.component_id, c.name) for c in analysis.components] == [
+        ("1", "main"),
+        ("2", "models"),
+        ("3", "services"),
+        ("4", "unused"),
+        ("5", "utils"),
+    ]
+    assert [entity.qualified_name for entity in analysis.components[4].key_entities] == ["utils.log.Print"]
+    edges = {(r.src_name, r.dst_name): len(r.all_edges) for r in analysis.components_relations}
+    assert edges == {
+        ("main", "models"): 1,
+        ("main", "services"): 1,
+        ("models", "utils"): 1,
+        ("services", "models"): 1,
+        ("services", "utils"): 2,
+    }
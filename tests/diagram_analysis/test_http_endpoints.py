@@ -0,0 +1,61 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.http_endpoints import assign_http_routes
+from output_generators.markdown import generate_markdown
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+
+def _component(cid: str, name: str, file_path: str, methods: list[str]) -> Component:
+    entries = [
+        MethodEntry(qualified_name=q, start_line=i, end_line=i, node_type="FUNCTION") for i, q in enumerate(methods, 1)
+    ]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=file_path, methods=entries)],
+    )
+
+
+def test_components_list_the_routes_of_their_handlers(tmp_path: Path):
+    cfg = CallGraph(language="go")
+    users = Node("api.users.GetUser", NodeType.FUNCTION, "api/users.go", 1, 1)
+    users.http_routes = ("GET /v1/users/{id}", "ANY /users")
+    cfg.add_node(users)
+    cfg.add_node(Node("store.db.Open", NodeType.FUNCTION, "store/db.go", 1, 1))
+    static_analysis = StaticAnalysisResults()
+    static_analysis.add_cfg(Language.GO, cfg)
+    api = _component("1", "API", "api/users.go", ["api.users.GetUser"])
+    store = _component("2", "Store", "store/db.go", ["store.db.Open"])
+    analysis = AnalysisInsights(description="", components=[api, store], components_relations=[])
+
+    assert assign_http_routes(analysis, {}, static_analysis) == 2
+
+    assert [(route.method, route.path, route.qualified_name) for route in api.http_routes] == [
+        ("ANY", "/users", "api.users.GetUser"),
+        ("GET", "/v1/users/{id}", "api.users.GetUser"),
+    ]
+    assert store.http_routes == []
+
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert "## HTTP Endpoints" in markdown
+    assert "| GET | `/v1/users/{id}` | `api.users.GetUser` | API |" in markdown
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert loaded.components[0].http_routes == api.http_routes
+
+    assign_http_routes(analysis, {}, None)
+    assert api.http_routes == []
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert "## HTTP Endpoints" not in markdown
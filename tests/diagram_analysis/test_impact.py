@@ -0,0 +1,120 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, RelationEdge, SourceCodeReference
+from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json
+from diagram_analysis.focus import SymbolGraph
+from diagram_analysis.impact import impact, render_impact_mermaid, render_text
+from main import main
+
+
+def _component(cid: str, name: str, path: str, methods: list[str]) -> Component:
+    entries = [MethodEntry(qualified_name=m, start_line=1, end_line=2, node_type="FUNCTION") for m in methods]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=path, methods=entries)],
+    )
+
+
+def _fan_in() -> tuple[SymbolGraph, AnalysisInsights]:
+    # utils.helpers.add <- 30 services.handler_N <- api.route (calls every handler); unrelated.main -> api.route
+    graph = SymbolGraph()
+    handlers = [f"services.handler_{i:02d}" for i in range(30)]
+    for handler in handlers:
+        graph.add_edge(handler, "utils.helpers.add")
+        graph.add_edge("api.route", handler)
+    graph.add_edge("unrelated.main", "api.route")
+    graph.add_edge("utils.helpers.add", "utils.math.sum")
+    graph.locations["services.handler_00"] = ("services/handlers.py", 12)
+    root = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "Utilities", "utils/helpers.py", ["utils.helpers.add", "utils.math.sum"]),
+            _component("2", "Services", "services/handlers.py", handlers),
+            _component("3", "API", "api.py", ["api.route"]),
+        ],
+        components_relations=[],
+    )
+    return graph, root
+
+
+def test_impact_collects_transitive_callers_per_component_and_depth():
+    graph, root = _fan_in()
+
+    full = impact(graph, root, "helpers.add")
+    assert full.symbol == "utils.helpers.add"
+    assert len(full.callers) == 32 and "utils.math.sum" not in full.callers
+    assert full.callers["api.route"] == 2 and full.callers["unrelated.main"] == 3
+    assert full.by_depth() == {1: 30, 2: 1, 3: 1}
+    assert [(name, len(names)) for name, names in full.by_component()] == [
+        ("Services", 30),
+        ("(no component)", 1),
+        ("API", 1),
+    ]
+    assert not full.depth_capped
+
+    capped = impact(graph, root, "utils.helpers.add", max_depth=2)
+    assert "unrelated.main" not in capped.callers and capped.depth_capped
+
+    text = render_text(capped, limit=5)
+    assert text.startswith(
+        "Changing utils.helpers.add affects 31 transitive callers within 2 hops in 2 components; "
+        "more callers lie beyond the depth limit\n"
+    )
+    assert "  Services: 30 (services.handler_00, services.handler_01, services.handler_02, ...)" in text
+    assert "  [1] services.handler_00  services/handlers.py:12" in text
+    assert "  ... and 26 more" in text
+
+    mermaid = render_impact_mermaid(full, limit=5)
+    assert mermaid.count('["services.handler_') == 5
+    assert 'OMITTED["+27 more callers in 3 components"] -.- ' in mermaid
+    assert '[["utils.helpers.add"]]' in mermaid
+
+
+def test_impact_command_writes_json_from_analysis_json_edges(tmp_path: Path):
+    analysis = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "API", "api.py", ["api.route"]),
+            _component("2", "Utilities", "utils/helpers.py", ["utils.helpers.add"]),
+        ],
+        components_relations=[
+            Relation(
+                relation="uses",
+                src_name="API",
+                dst_name="Utilities",
+                all_edges=[
+                    RelationEdge(
+                        source=SourceCodeReference(qualified_name="api.route", reference_file="api.py"),
+                        target=SourceCodeReference(
+                            qualified_name="utils.helpers.add", reference_file="utils/helpers.py"
+                        ),
+                    )
+                ],
+            )
+        ],
+    )
+    analysis.files = {
+        group.file_path: FileEntry(methods=group.methods) for c in analysis.components for group in c.file_methods
+    }
+    path = tmp_path / "analysis.json"
+    path.write_text(
+        build_unified_analysis_json(
+            analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+        )
+    )
+    output = tmp_path / "impact.json"
+
+    main(["impact", str(path), "--symbol", "add", "--format", "json", "--output", str(output)])
+
+    report = json.loads(output.read_text())
+    assert report["symbol"] == "utils.helpers.add" and report["complete"] is False
+    assert report["caller_count"] == 1
+    assert report["components"] == [{"name": "API", "callers": 1}]
+    caller = report["callers"][0]
+    assert (caller["name"], caller["distance"], caller["component"]) == ("api.route", 1, "API")
+    assert caller["file"] == "api.py"
@@ -0,0 +1,87 @@
+from pathlib import Path
+
+from diagram_analysis.import_graph import (
+    IMPORT_GRAPH_DOT_FILENAME,
+    build_import_graph,
+    import_graph_dot,
+    import_graph_mermaid,
+    write_import_graph,
+)
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+
+def _results(repo: Path) -> StaticAnalysisResults:
+    cfg = CallGraph(language="go")
+    for qname, rel, line in [
+        ("main.main", "main.go", 3),
+        ("models.dog.NewDog", "models/dog.go", 3),
+        ("models.dog.Bark", "models/dog.go", 9),
+        ("services.walker.Walk", "services/walker.go", 3),
+        ("services.walker.Feed", "services/walker.go", 9),
+        ("utils.log.Print", "utils/log.go", 3),
+        ("unused.orphan.Orphan", "unused/orphan.go", 3),
+    ]:
+        cfg.add_node(Node(qname, NodeType.FUNCTION, str(repo / rel), line, line + 4))
+    cfg.add_edge("main.main", "models.dog.NewDog")
+    cfg.add_edge("main.main", "services.walker.Walk")
+    cfg.add_edge("models.dog.NewDog", "utils.log.Print")
+    cfg.add_edge("models.dog.Bark", "services.walker.Feed")
+    cfg.add_edge("services.walker.Walk", "models.dog.Bark")
+    cfg.add_edge("services.walker.Feed", "utils.log.Print")
+    cfg.add_edge("services.walker.Walk", "utils.log.Print")
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    results.add_package_dependencies(
+        Language.GO,
+        {
+            "main": {"imports": ["models", "services"], "imported_by": []},
+            "models": {"imports": ["services", "utils"], "imported_by": ["main", "services"]},
+            "services": {"imports": ["models", "utils"], "imported_by": ["main", "models"]},
+            "utils": {"imports": [], "imported_by": ["models", "services"]},
+            "unused": {"imports": [], "imported_by": ["models.tests"]},
+            "models.tests": {"imports": ["unused"], "imported_by": []},
+        },
+    )
+    return results
+
+
+def test_imports_are_weighted_by_cross_package_calls(tmp_path: Path):
+    graph = build_import_graph(_results(tmp_path), repo_dir=tmp_path)
+
+    assert graph.packages == {"main", "models", "services", "utils", "unused"}
+    assert graph.imports == {
+        ("main", "models"): 1,
+        ("main", "services"): 1,
+        ("models", "services"): 1,
+        ("models", "utils"): 1,
+        ("services", "models"): 1,
+        ("services", "utils"): 2,
+    }
+    assert graph.unimported() == ["main", "unused"]
+    assert graph.cycle_edges() == {("models", "services"), ("services", "models")}
+
+
+def test_cycles_are_red_in_both_formats(tmp_path: Path):
+    graph = build_import_graph(_results(tmp_path), repo_dir=tmp_path)
+
+    mermaid = import_graph_mermaid(graph)
+    assert '    P1 -- "1 call" --> P2\n' in mermaid
+    assert '    P2 -- "2 calls" --> P4\n' in mermaid
+    # Edges sorted: main->models, main->services, models->services, models->utils, services->models, ...
+    assert "    linkStyle 2,4 stroke:red,stroke-width:2px\n" in mermaid
+    assert "Imported by no other package: `main`, `unused`" in mermaid
+
+    dot = import_graph_dot(graph, name="zoo")
+    assert dot.startswith('digraph "zoo" {\n')
+    assert '    "models" -> "services" [label="1", color=red, fontcolor=red];\n' in dot
+    assert '    "services" -> "utils" [label="2"];\n' in dot
+
+
+def test_both_files_are_written(tmp_path: Path):
+    paths, _ = write_import_graph(_results(tmp_path), tmp_path, tmp_path)
+
+    assert [path.name for path in paths] == ["imports.md", IMPORT_GRAPH_DOT_FILENAME]
+    assert paths[0].read_text().startswith("# Package imports\n\n```mermaid\ngraph LR\n")
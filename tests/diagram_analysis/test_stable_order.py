@@ -0,0 +1,110 @@
+from agents.agent_responses import (
+    AnalysisInsights,
+    Component,
+    FileMethodGroup,
+    Relation,
+    RelationCallSite,
+    RelationEdge,
+    SourceCodeReference,
+)
+from agents.file_index_models import MethodEntry
+from diagram_analysis.stable_order import sort_analysis, sort_static_analysis
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+NODES = [("app.main", "app.py", 1), ("app.run", "app.py", 5), ("db.save", "db.py", 1), ("db.Row", "db.py", 9)]
+EDGES = [
+    ("app.run", "db.save", {"file": "app.py", "line": 7, "column": 3}),
+    ("app.main", "app.run", {"file": "app.py", "line": 2, "column": 5}),
+    ("app.run", "db.save", {"file": "app.py", "line": 6, "column": 9}),
+]
+
+
+def _graph(reverse: bool) -> CallGraph:
+    order = reversed if reverse else list
+    graph = CallGraph(language="python")
+    for qname, path, line in order(NODES):
+        graph.add_node(Node(qname, NodeType.CLASS if qname.endswith("Row") else NodeType.FUNCTION, path, line, line))
+    for src, dst, site in order(EDGES):
+        graph.add_edge(src, dst, [site])
+    for src, dst in order([("db.save", "db.Row"), ("app.run", "db.Row")]):
+        graph.add_reference_edge(src, dst, EdgeKind.TYPEREF)
+    return graph
+
+
+def _layout(graph: CallGraph) -> tuple:
+    edges = [(e.get_source(), e.get_destination(), [site["line"] for site in e.call_sites]) for e in graph.edges]
+    return list(graph.nodes), edges, graph.reference_edges
+
+
+def test_call_graphs_built_in_any_order_sort_the_same():
+    results = []
+    for reverse in (False, True):
+        static_analysis = StaticAnalysisResults()
+        static_analysis.add_cfg(Language.PYTHON, _graph(reverse))
+        sort_static_analysis(static_analysis)
+        results.append(static_analysis.get_cfg(Language.PYTHON))
+
+    assert _layout(results[0]) == _layout(results[1])
+    assert _layout(results[0]) == (
+        ["app.main", "app.run", "db.Row", "db.save"],
+        [("app.main", "app.run", [2]), ("app.run", "db.save", [6, 7])],
+        [("app.run", "db.Row", "typeref"), ("db.save", "db.Row", "typeref")],
+    )
+
+
+def _edge(source: str, target: str, *lines: int) -> RelationEdge:
+    return RelationEdge(
+        source=SourceCodeReference(qualified_name=source, reference_file="app.py"),
+        target=SourceCodeReference(qualified_name=target, reference_file="db.py"),
+        call_sites=[RelationCallSite(line=line, column=1) for line in lines],
+    )
+
+
+def _analysis(reverse: bool) -> AnalysisInsights:
+    order = reversed if reverse else list
+    methods = [
+        MethodEntry(qualified_name="app.main", start_line=1, end_line=3, node_type="FUNCTION"),
+        MethodEntry(qualified_name="app.run", start_line=5, end_line=8, node_type="FUNCTION"),
+    ]
+    component = Component(
+        name="App",
+        description="",
+        key_entities=list(order([SourceCodeReference(qualified_name=q) for q in ("app.main", "app.run")])),
+        component_id="1",
+        file_methods=list(
+            order(
+                [FileMethodGroup(file_path="app.py", methods=list(order(methods))), FileMethodGroup(file_path="a.py")]
+            )
+        ),
+    )
+    relations = [
+        Relation(
+            relation="calls",
+            src_name="App",
+            dst_name="Db",
+            src_id="1",
+            dst_id="2",
+            all_edges=list(order([_edge("app.run", "db.save", *order([7, 6])), _edge("app.main", "db.save", 2)])),
+        ),
+        Relation(relation="calls", src_name="App", dst_name="Cli", src_id="1", dst_id="3"),
+    ]
+    return AnalysisInsights(description="", components=[component], components_relations=list(order(relations)))
+
+
+def test_analyses_assembled_in_any_order_serialize_the_same():
+    first, second = _analysis(False), _analysis(True)
+
+    sort_analysis(first)
+    sort_analysis(second)
+
+    assert first.model_dump_json() == second.model_dump_json()
+    assert [r.dst_id for r in first.components_relations] == ["2", "3"]
+    edges = first.components_relations[0].all_edges
+    assert [(e.source.qualified_name, [site.line for site in e.call_sites]) for e in edges] == [
+        ("app.main", [2]),
+        ("app.run", [6, 7]),
+    ]
+    assert [group.file_path for group in first.components[0].file_methods] == ["a.py", "app.py"]
@@ -0,0 +1,71 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from diagram_analysis.structural_coverage import (
+    TEST_COVERAGE_MD,
+    find_test_files,
+    is_test_file,
+    write_test_coverage_report,
+)
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+
+def _component(cid: str, name: str, qualified_names: list[str]) -> Component:
+    methods = [MethodEntry(qualified_name=q, start_line=1, end_line=2, node_type="FUNCTION") for q in qualified_names]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path="src/app.py", methods=methods)],
+    )
+
+
+def test_test_files_follow_the_default_ignore_conventions(tmp_path: Path):
+    files = ("src/app.py", "tests/test_api.py", "pkg/handler_test.go", "web/button.spec.ts", "node_modules/x/t.test.js")
+    for rel in files:
+        (tmp_path / rel).parent.mkdir(parents=True, exist_ok=True)
+        (tmp_path / rel).write_text("")
+
+    found = find_test_files(tmp_path, {".py", ".go", ".ts", ".js"})
+
+    assert [p.relative_to(tmp_path).as_posix() for p in found] == [
+        "pkg/handler_test.go",
+        "tests/test_api.py",
+        "web/button.spec.ts",
+    ]
+    assert is_test_file("src/FooTest.java") and not is_test_file("src/Foo.java")
+
+
+def test_components_unreached_from_tests_are_flagged(tmp_path: Path):
+    (tmp_path / "tests").mkdir()
+    (tmp_path / "tests" / "test_api.py").write_text("from app.api import handle\n\ndef test_handle():\n    handle()\n")
+    names = ["app.api.handle", "app.store.save", "app.admin.purge"]
+    cfg = CallGraph(nodes={n: Node(n, NodeType.FUNCTION, "src/app.py", 1, 2) for n in names})
+    cfg.add_edge("app.api.handle", "app.store.save")
+    analysis = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "API", ["app.api.handle"]),
+            _component("2", "Storage", ["app.store.save"]),
+            _component("3", "Admin", ["app.admin.purge"]),
+        ],
+        components_relations=[Relation(relation="saves", src_name="API", dst_name="Storage")],
+    )
+
+    path = write_test_coverage_report(tmp_path, tmp_path, analysis, {}, [cfg], ["src/app.py"])
+
+    payload = json.loads(path.read_text())
+    assert payload["test_files"] == 1
+    assert [(c["name"], c["reached"], c["directly_tested"], c["untested"]) for c in payload["components"]] == [
+        ("API", 1, 1, False),
+        ("Storage", 1, 0, False),
+        ("Admin", 0, 0, True),
+    ]
+    markdown = (tmp_path / TEST_COVERAGE_MD).read_text()
+    assert "    class Admin untested" in markdown
+    assert "- **Admin** (1 method)" in markdown
@@ -0,0 +1,114 @@
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from agents.abstraction_agent import AbstractionAgent
+from agents.agent_responses import MetaAnalysisInsights
+from diagram_analysis.grouping import Grouping
+from diagram_analysis.subtree import Subtree, SubtreeError, apply_subtree
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.cluster_relations import build_global_relations
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+FILES = {
+    "main.go": "package main\n",
+    "models/dog.go": "package models\n",
+    "services/walker.go": "package services\n",
+    "services/park/bench.go": "package park\n",
+    "utils/log.go": "package utils\n",
+}
+
+
+def _results(repo: Path) -> StaticAnalysisResults:
+    for rel, text in FILES.items():
+        (repo / rel).parent.mkdir(parents=True, exist_ok=True)
+        (repo / rel).write_text(text + "\nfunc f() {}\n" * 4)
+    cfg = CallGraph(language="go")
+    for qname, rel, line in [
+        ("main.main", "main.go", 3),
+        ("models.dog.NewDog", "models/dog.go", 3),
+        ("models.dog.Bark", "models/dog.go", 9),
+        ("services.walker.Walk", "services/walker.go", 3),
+        ("services.walker.Feed", "services/walker.go", 9),
+        ("services.park.bench.Sit", "services/park/bench.go", 3),
+        ("utils.log.Print", "utils/log.go", 3),
+    ]:
+        cfg.add_node(Node(qname, NodeType.FUNCTION, str(repo / rel), line, line + 4))
+    cfg.add_edge("main.main", "services.walker.Walk")
+    cfg.add_edge("models.dog.NewDog", "utils.log.Print")
+    cfg.add_edge("services.walker.Walk", "models.dog.Bark")
+    cfg.add_edge("services.walker.Walk", "services.park.bench.Sit")
+    cfg.add_edge("services.walker.Walk", "utils.log.Print")
+    cfg.add_edge("services.walker.Feed", "utils.log.Print")
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    return results
+
+
+def test_package_patterns_parse_like_go():
+    assert Subtree.from_package("./services/...") == Subtree("services", recursive=True)
+    assert Subtree.from_package("./services") == Subtree("services", recursive=False)
+    assert Subtree.from_package("./...") == Subtree("", recursive=True)
+    assert str(Subtree.from_path("services/")) == "./services/..."
+    with pytest.raises(SubtreeError):
+        Subtree.from_path("../elsewhere")
+    with pytest.raises(SubtreeError):
+        Subtree.from_package("./services...")
+
+
+def test_subtree_keeps_its_symbols_and_the_outside_packages_it_calls(tmp_path: Path):
+    results = _results(tmp_path)
+
+    view = apply_subtree(results, Subtree.from_path("services"), tmp_path)
+    package_only = apply_subtree(results, Subtree.from_package("./services"), tmp_path)
+
+    assert sorted(view.static_analysis.get_cfg(Language.GO).nodes) == [
+        "services.park.bench.Sit",
+        "services.walker.Feed",
+        "services.walker.Walk",
+    ]
+    assert [(group.name, [n.fully_qualified_name for n in group.nodes]) for group in view.dependencies(tmp_path)] == [
+        ("models", ["models.dog.Bark"]),
+        ("utils", ["utils.log.Print"]),
+    ]
+    assert [group.name for group in package_only.dependencies(tmp_path)] == ["models", "park", "utils"]
+    # The input is untouched: the static-analysis cache keeps the whole repository.
+    assert len(results.get_cfg(Language.GO).nodes) == 7
+
+
+def test_outside_packages_become_external_dependency_components(tmp_path: Path):
+    view = apply_subtree(_results(tmp_path), Subtree.from_path("services"), tmp_path)
+    agent = AbstractionAgent(
+        repo_dir=tmp_path,
+        static_analysis=view.static_analysis,
+        project_name="zoo",
+        meta_context=MetaAnalysisInsights(
+            project_type="service",
+            domain="pets",
+            architectural_patterns=[],
+            expected_components=[],
+            technology_stack=["Go"],
+            architectural_bias="",
+        ),
+        agent_llm=MagicMock(),
+        parsing_llm=MagicMock(),
+        grouping=Grouping.PACKAGE,
+    )
+    analysis, _ = agent.run()
+
+    calls = view.outside_calls["go"]
+    agent.add_dependency_components(analysis, view.dependencies(tmp_path), calls, "./services/...")
+
+    assert [(c.component_id, c.name, c.external) for c in analysis.components] == [
+        ("1", "park", False),
+        ("2", "services", False),
+        ("3", "models", True),
+        ("4", "utils", True),
+    ]
+    assert "models/dog.go" in analysis.files
+    cfg_graphs = view.with_outside_calls(view.static_analysis.available_cfgs())
+    edges = {(r.src_name, r.dst_name): len(r.all_edges) for r in build_global_relations(analysis, {}, cfg_graphs)}
+    assert edges == {("services", "park"): 1, ("services", "models"): 1, ("services", "utils"): 2}
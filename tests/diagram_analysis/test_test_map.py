@@ -0,0 +1,118 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from diagram_analysis.test_map import assign_component_tests
+from output_generators.markdown import generate_markdown
+
+TEST_BILLING_PY = """\
+import pytest
+
+from billing.invoice import issue_invoice
+
+
+def make_invoice(amount):
+    return issue_invoice(amount, currency="EUR")
+
+
+class TestInvoice:
+    def test_totals(
+        self,
+    ) -> None:
+        invoice = make_invoice(10)
+
+        assert invoice.total == 10
+
+    class TestRefunds:
+        def test_refund(self):
+            assert refund_payment(make_invoice(5))
+
+
+def test_unrelated():
+    assert sum([1, 2]) == 3
+"""
+
+STORE_TEST_GO = """\
+package store
+
+import "testing"
+
+func newStore(t *testing.T) *Store {
+	return OpenStore(t.TempDir())
+}
+
+func TestGet(t *testing.T) {
+	s := newStore(t)
+	if _, err := s.Get("k"); err == nil {
+		t.Fatal("want error")
+	}
+}
+"""
+
+CART_SPEC_TS = """\
+import { addItem } from "../src/cart";
+
+describe("cart", () => {
+  it("adds an item", () => {
+    expect(addItem([], "x")).toHaveLength(1);
+  });
+});
+"""
+
+
+def _component(cid: str, name: str, path: str, methods: list[str]) -> Component:
+    entries = [MethodEntry(qualified_name=m, start_line=1, end_line=2, node_type="FUNCTION") for m in methods]
+    return Component(
+        name=name,
+        description="",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=path, methods=entries)],
+    )
+
+
+def test_components_list_the_tests_that_name_their_methods(tmp_path: Path):
+    for rel, text in {
+        "tests/test_billing.py": TEST_BILLING_PY,
+        "store/store_test.go": STORE_TEST_GO,
+        "web/test/cart.spec.ts": CART_SPEC_TS,
+    }.items():
+        (tmp_path / rel).parent.mkdir(parents=True, exist_ok=True)
+        (tmp_path / rel).write_text(text)
+    analysis = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "Billing", "billing/invoice.py", ["billing.invoice.issue_invoice"]),
+            _component("2", "Payments", "billing/payments.py", ["billing.payments.refund_payment"]),
+            _component("3", "Store", "store/store.go", ["store.OpenStore", "store.Store.Get"]),
+            _component("4", "Cart", "web/src/cart.ts", ["cart.addItem"]),
+            _component("5", "Reports", "reports/report.py", ["reports.render_report"]),
+        ],
+        components_relations=[],
+    )
+
+    assign_component_tests(tmp_path, analysis, {}, enabled=True)
+
+    assert [c.tests for c in analysis.components] == [
+        [
+            "tests/test_billing.py::TestInvoice::TestRefunds::test_refund",
+            "tests/test_billing.py::TestInvoice::test_totals",
+        ],
+        ["tests/test_billing.py::TestInvoice::TestRefunds::test_refund"],
+        ["store/store_test.go::TestGet"],
+        ["web/test/cart.spec.ts::adds an item"],
+        [],
+    ]
+    markdown = generate_markdown(analysis, project="demo", repo_ref="", expanded_components=set())
+    assert "**Tests:**\n\n- `store/store_test.go::TestGet`\n" in markdown
+
+    unified = build_unified_analysis_json(
+        analysis, [], "demo", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(unified))
+    assert [c.tests for c in loaded.components] == [c.tests for c in analysis.components]
+
+    assign_component_tests(tmp_path, loaded, {}, enabled=False)
+    assert all(c.tests == [] for c in loaded.components)
@@ -0,0 +1,134 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from health.fitness import (
+    FITNESS_FILENAME,
+    FitnessConfig,
+    FitnessMetric,
+    evaluate_fitness,
+    load_fitness_config,
+    write_fitness_report,
+)
+from health.models import CircularDependencyCheck, HealthReport, StandardCheckSummary
+from project_config import ProjectConfig
+
+
+def _component(cid: str, name: str) -> Component:
+    return Component(name=name, description="", key_entities=[], component_id=cid)
+
+
+def _relation(src: Component, dst: Component) -> Relation:
+    return Relation(
+        relation="calls", src_name=src.name, dst_name=dst.name, src_id=src.component_id, dst_id=dst.component_id
+    )
+
+
+def _health(cycles: list[str], functions: int, unused: int) -> HealthReport:
+    return HealthReport(
+        repository_name="repo",
+        timestamp="2026-01-01T00:00:00+00:00",
+        overall_score=1.0,
+        check_summaries=[
+            CircularDependencyCheck(
+                check_name="circular_dependencies",
+                description="",
+                cycles=cycles,
+                packages_checked=5,
+                packages_in_cycles=2 * len(cycles),
+            ),
+            StandardCheckSummary(
+                check_name="function_size",
+                description="",
+                total_entities_checked=functions,
+                findings_count=0,
+                score=1.0,
+            ),
+            StandardCheckSummary(
+                check_name="unused_code_diagnostics",
+                description="",
+                total_entities_checked=unused,
+                findings_count=unused,
+                score=1.0,
+            ),
+        ],
+    )
+
+
+API, SERVICE, STORE = _component("1", "API"), _component("2", "Service"), _component("3", "Storage")
+LAYERS = {"order": ["interface", "domain", "infra"], "members": {"interface": ["API"], "infra": ["Stor*"]}}
+
+
+def _metric(report, metric: FitnessMetric):
+    return next(r for r in report.metrics if r.metric == metric)
+
+
+def test_clean_architecture_scores_full_marks():
+    root = AnalysisInsights(
+        description="", components=[API, SERVICE, STORE], components_relations=[_relation(API, STORE)]
+    )
+
+    report = evaluate_fitness(root, {}, _health([], functions=100, unused=0), FitnessConfig(), LAYERS)
+
+    assert report.passed
+    assert report.score == 1.0 - 0.1 / 4  # one coupling neighbour out of the default limit of 10
+
+
+def test_metrics_reflect_cycles_layering_and_dead_code():
+    root = AnalysisInsights(
+        description="",
+        components=[API, SERVICE, STORE],
+        components_relations=[_relation(STORE, API), _relation(API, SERVICE)],
+    )
+
+    report = evaluate_fitness(root, {}, _health(["a -> b -> a"], functions=10, unused=1), FitnessConfig(), LAYERS)
+
+    assert _metric(report, FitnessMetric.CYCLES).value == 1
+    assert _metric(report, FitnessMetric.MAX_COUPLING).value == 2
+    assert _metric(report, FitnessMetric.DEAD_CODE_RATIO).value == 0.1
+    layering = _metric(report, FitnessMetric.LAYERING_VIOLATIONS)
+    assert layering.value == 1
+    assert layering.details == ["Storage (infra) -> API (interface)"]
+
+
+def test_unmeasured_metrics_do_not_count_and_threshold_gates():
+    root = AnalysisInsights(
+        description="", components=[API, STORE], components_relations=[_relation(API, STORE), _relation(STORE, API)]
+    )
+    config = FitnessConfig(threshold=0.95, limits={FitnessMetric.MAX_COUPLING: 2})
+
+    report = evaluate_fitness(root, {}, None, config)
+
+    assert _metric(report, FitnessMetric.CYCLES).score is None
+    assert _metric(report, FitnessMetric.LAYERING_VIOLATIONS).score is None
+    assert report.score == 0.5
+    assert not report.passed
+
+
+def test_config_comes_from_project_fitness_section():
+    project = ProjectConfig(sections={"fitness": {"threshold": 0.5, "weights": {"cycles": 3}}})
+
+    config = load_fitness_config(project)
+
+    assert config.threshold == 0.5
+    assert config.weight(FitnessMetric.CYCLES) == 3
+    assert config.weight(FitnessMetric.DEAD_CODE_RATIO) == 1.0
+
+
+def test_invalid_config_falls_back_to_defaults():
+    project = ProjectConfig(sections={"fitness": {"threshold": 3, "weights": {"not_a_metric": 1}}})
+
+    assert load_fitness_config(project) == FitnessConfig()
+
+
+def test_write_fitness_report_reads_health_report_from_disk(tmp_path: Path):
+    (tmp_path / "health").mkdir()
+    (tmp_path / "health" / "health_report.json").write_text(_health(["x -> y -> x"], 10, 0).model_dump_json())
+    root = AnalysisInsights(description="", components=[API], components_relations=[])
+
+    write_fitness_report(tmp_path, root, {}, ProjectConfig())
+
+    payload = json.loads((tmp_path / FITNESS_FILENAME).read_text())
+    cycles = next(m for m in payload["metrics"] if m["metric"] == "cycles")
+    assert cycles["value"] == 1
+    assert cycles["details"] == ["x -> y -> x"]
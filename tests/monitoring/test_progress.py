@@ -0,0 +1,90 @@
+"""Tests for the run progress reporter."""
+
+import io
+import json
+
+from monitoring.progress import Phase, ProgressMode, ProgressReporter
+
+
+class _Clock:
+    def __init__(self) -> None:
+        self.now = 0.0
+
+    def __call__(self) -> float:
+        return self.now
+
+
+def _reporter(mode: ProgressMode) -> tuple[ProgressReporter, io.StringIO, _Clock]:
+    stream, clock = io.StringIO(), _Clock()
+    return ProgressReporter(mode, stream, clock), stream, clock
+
+
+def _events(stream: io.StringIO) -> list[dict]:
+    return [json.loads(line) for line in stream.getvalue().splitlines()]
+
+
+def test_json_events_cover_each_phase_and_the_run():
+    reporter, stream, clock = _reporter(ProgressMode.JSON)
+
+    reporter.start(Phase.SYMBOLS, 4, unit="file")
+    clock.now = 1.0
+    reporter.advance(Phase.SYMBOLS, 2, unit="file")
+    reporter.advance(Phase.SYMBOLS, 1, unit="file")  # within the interval: not emitted
+    clock.now = 3.0
+    reporter.advance(Phase.SYMBOLS, 1, unit="file")
+    reporter.finish(Phase.SYMBOLS)
+    reporter.retry("LLM call", 1, 5, 8.0, TimeoutError("slow"))
+    clock.now = 4.0
+    reporter.summary()
+
+    events = _events(stream)
+    assert [event["event"] for event in events] == [
+        "phase_start",
+        "progress",
+        "progress",
+        "phase_end",
+        "retry",
+        "run_end",
+    ]
+    assert {key: events[2][key] for key in ("phase", "done", "total", "unit", "elapsed_s")} == {
+        "phase": "symbols",
+        "done": 4,
+        "total": 4,
+        "unit": "file",
+        "elapsed_s": 3.0,
+    }
+    assert events[4]["wait_s"] == 8.0 and events[4]["error"] == "slow"
+    assert events[5]["phases"] == {"symbols": 3.0} and events[5]["elapsed_s"] == 4.0
+
+
+def test_repeated_phases_add_up_their_counts_and_time():
+    reporter, stream, clock = _reporter(ProgressMode.BAR)
+
+    for language_files in (3, 2):
+        reporter.start(Phase.REFERENCES, language_files, unit="file")
+        clock.now += 5.0
+        reporter.advance(Phase.REFERENCES, language_files, unit="file")
+        reporter.finish(Phase.REFERENCES)
+    with reporter.phase(Phase.LLM, total=0, unit="component"):
+        reporter.add_total(Phase.LLM, 2, unit="component")
+        clock.now += 10.0
+        reporter.advance(Phase.LLM, unit="component")
+    reporter.summary()
+
+    output = stream.getvalue()
+    assert "[references] [####################] 100% 5/5 files, 10.0s" in output
+    assert "[llm] [##########..........]  50% 1/2 components, 10.0s" in output
+    assert "[llm] done: 1 component in 10.0s" in output
+    assert "  references     10.0s  5 files" in output
+    assert "  total          20.0s" in output
+
+
+def test_quiet_prints_nothing():
+    reporter, stream, _ = _reporter(ProgressMode.QUIET)
+
+    with reporter.phase(Phase.RENDERING, 1, unit="file"):
+        reporter.advance(Phase.RENDERING, unit="file")
+    reporter.retry("LLM call", 1, 5, 1.0, "boom")
+    reporter.summary()
+
+    assert stream.getvalue() == ""
@@ -0,0 +1,148 @@
+from pathlib import Path
+
+import pytest
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, RelationEdge, SourceCodeReference
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from output_generators.c4 import C4_FILENAME, build_c4_model, generate_structurizr_dsl, package_for_file, write_c4_file
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language
+
+
+def _component(cid: str, name: str, files: dict[str, list[str]], external: bool = False) -> Component:
+    return Component(
+        name=name,
+        description=f"{name} does things",
+        key_entities=[],
+        component_id=cid,
+        external=external,
+        file_methods=[
+            FileMethodGroup(
+                file_path=path,
+                methods=[MethodEntry(qualified_name=q, start_line=1, end_line=2, node_type="FUNCTION") for q in names],
+            )
+            for path, names in files.items()
+        ],
+    )
+
+
+def _edge(source: str, target: str) -> RelationEdge:
+    return RelationEdge(
+        source=SourceCodeReference(qualified_name=source), target=SourceCodeReference(qualified_name=target)
+    )
+
+
+def _analysis() -> AnalysisInsights:
+    api = _component("1", "API", {"api/routes.py": ["api.routes.create"], "api/views.py": ["api.views.render"]})
+    store = _component("2", "Storage", {"store/db.py": ["store.db.save", "store.db.load"]})
+    helpers = _component("3", "Helpers", {"api/helpers.py": ["api.helpers.slug"]})
+    sdk = _component("4", "Payments SDK", {"vendor/pay.py": ["vendor.pay.charge"]}, external=True)
+    return AnalysisInsights(
+        description="A small web service",
+        components=[api, store, helpers, sdk],
+        components_relations=[
+            Relation(
+                relation="persists through",
+                src_name="API",
+                dst_name="Storage",
+                src_id="1",
+                dst_id="2",
+                all_edges=[
+                    _edge("api.routes.create", "store.db.save"),
+                    _edge("api.views.render", "store.db.load"),
+                ],
+            ),
+            # Same package: not a component relationship.
+            Relation(
+                relation="formats with",
+                src_name="API",
+                dst_name="Helpers",
+                src_id="1",
+                dst_id="3",
+                all_edges=[_edge("api.routes.create", "api.helpers.slug")],
+            ),
+            Relation(
+                relation="charges through",
+                src_name="API",
+                dst_name="Payments SDK",
+                src_id="1",
+                dst_id="4",
+                all_edges=[_edge("api.routes.create", "vendor.pay.charge")],
+            ),
+        ],
+    )
+
+
+def _static_analysis() -> StaticAnalysisResults:
+    results = StaticAnalysisResults()
+    results.add_package_dependencies(
+        Language.PYTHON,
+        {
+            "api": {"imports": ["store", "vendor"], "imported_by": []},
+            "store": {"imports": [], "imported_by": ["api"]},
+        },
+    )
+    return results
+
+
+def test_package_for_file():
+    assert package_for_file("api/routes.py") == "api"
+    assert package_for_file("src/models/user.go") == "src.models"
+    assert package_for_file("main.py") == "main"
+
+
+def test_components_sit_in_packages_and_cross_package_calls_are_relationships():
+    model = build_c4_model("shop", _analysis(), _static_analysis())
+
+    assert {package: [c.name for c in components] for package, components in model.containers.items()} == {
+        "api": ["API", "Helpers"],
+        "store": ["Storage"],
+    }
+    assert [c.name for c in model.external] == ["Payments SDK"]
+    assert model.container_relations == {("api", "store")}
+    assert model.component_relations == {("1", "2"): {"save", "load"}, ("1", "4"): {"charge"}}
+
+
+def test_component_level_dsl():
+    dsl = generate_structurizr_dsl(build_c4_model("shop", _analysis(), _static_analysis()), "component")
+
+    assert dsl.startswith('workspace "shop" "A small web service" {\n')
+    assert '            container_api = container "api" "Package" {\n' in dsl
+    assert '                component_1 = component "API" "API does things"\n' in dsl
+    assert '        external_4 = softwareSystem "Payments SDK" "Payments SDK does things" "External"\n' in dsl
+    assert '        container_api -> container_store "Uses"\n' in dsl
+    assert '        component_1 -> component_2 "Calls load, save"\n' in dsl
+    assert '        component_1 -> external_4 "Calls charge"\n' in dsl
+    assert "component_1 -> component_3" not in dsl
+    assert '        component container_api "Components_api" {\n' in dsl
+    assert dsl.count("{") == dsl.count("}")
+
+
+def test_container_level_has_no_components():
+    dsl = generate_structurizr_dsl(build_c4_model("shop", _analysis(), _static_analysis()), "container")
+
+    assert '            container_api = container "api" "Package"\n' in dsl
+    assert "component_1" not in dsl
+    assert '        container_api -> external_4 "Calls charge"\n' in dsl
+    assert '        container system "Containers" {\n' in dsl
+    assert "        component container_" not in dsl
+
+
+def test_context_level_is_the_system_and_its_external_systems():
+    dsl = generate_structurizr_dsl(build_c4_model("shop", _analysis()), "context")
+
+    assert '        system = softwareSystem "shop" "A small web service"\n' in dsl
+    assert "container_" not in dsl
+    assert '        system -> external_4 "Calls charge"\n' in dsl
+    assert '        systemContext system "Context" {\n' in dsl
+
+
+def test_unknown_level_is_rejected():
+    with pytest.raises(ValueError, match="Unknown C4 level"):
+        generate_structurizr_dsl(build_c4_model("shop", _analysis()), "deployment")
+
+
+def test_write(tmp_path: Path):
+    path = write_c4_file(build_c4_model("shop", _analysis()), tmp_path / C4_FILENAME, "context")
+
+    assert path.read_text().startswith("workspace ")
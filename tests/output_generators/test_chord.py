@@ -0,0 +1,49 @@
+import json
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, RelationEdge, SourceCodeReference
+from output_generators.chord import CHORD_JSON_FILENAME, build_chord_data, write_chord_files
+
+
+def _component(cid: str, name: str) -> Component:
+    return Component(name=name, description="", key_entities=[], component_id=cid)
+
+
+def _edge(src: str, dst: str) -> RelationEdge:
+    return RelationEdge(source=SourceCodeReference(qualified_name=src), target=SourceCodeReference(qualified_name=dst))
+
+
+def _relation(src: Component, dst: Component, edges: int) -> Relation:
+    return Relation(
+        relation="uses",
+        src_name=src.name,
+        dst_name=dst.name,
+        src_id=src.component_id,
+        dst_id=dst.component_id,
+        all_edges=[_edge(f"{src.name}.f{i}", f"{dst.name}.g{i}") for i in range(edges)],
+    )
+
+
+def test_matrix_weights_edges_and_totals_coupling():
+    api, store, auth = _component("1", "API"), _component("2", "Storage"), _component("3", "Auth")
+    analysis = AnalysisInsights(
+        description="",
+        components=[store, api, auth],
+        components_relations=[_relation(api, store, 3), _relation(auth, store, 0), _relation(api, api, 2)],
+    )
+
+    data = build_chord_data(analysis)
+
+    assert [c["name"] for c in data["components"]] == ["API", "Storage", "Auth"]
+    # Self-loops are dropped; a relation without static edges still counts once.
+    assert data["matrix"] == [[0, 3, 0], [0, 0, 0], [0, 1, 0]]
+    assert data["components"][1] == {"id": "2", "name": "Storage", "outgoing": 0, "incoming": 4}
+
+
+def test_write_chord_files_escapes_names(tmp_path: Path):
+    analysis = AnalysisInsights(description="", components=[_component("1", "</script><b>")], components_relations=[])
+
+    html_path = write_chord_files("demo", analysis, tmp_path)
+
+    assert "</script><b>" not in html_path.read_text()
+    assert json.loads((tmp_path / CHORD_JSON_FILENAME).read_text())["components"][0]["name"] == "</script><b>"
@@ -0,0 +1,169 @@
+from pathlib import Path
+
+from output_generators.class_diagram import (
+    CLASS_DIAGRAM_FILENAME,
+    build_class_diagram,
+    generate_class_diagram,
+    write_class_diagram,
+)
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+BASE_GO = """package models
+
+type Entity struct {
+	ID       string `json:"id"`
+	typeName string
+	disposed bool
+}
+
+func (e *Entity) Dispose() { e.disposed = true }
+"""
+
+TASK_GO = """package models
+
+type Speaker interface {
+	Speak() string
+}
+
+type Task struct {
+	*Entity
+	Owner *User // who created it
+	Title string
+}
+
+type User struct{}
+
+func (t Task) Serialize() string { return t.Title }
+
+func (t Task) Speak() string { return t.Title }
+"""
+
+
+def _line(source: str, text: str) -> int:
+    return next(i for i, line in enumerate(source.splitlines(), start=1) if text in line)
+
+
+def _go_results(tmp_path: Path) -> StaticAnalysisResults:
+    base = tmp_path / "models" / "base.go"
+    task = tmp_path / "models" / "task.go"
+    base.parent.mkdir()
+    base.write_text(BASE_GO)
+    task.write_text(TASK_GO)
+
+    def node(qname: str, node_type: NodeType, path: Path, text: str) -> Node:
+        line = _line(BASE_GO if path == base else TASK_GO, text)
+        return Node(qname, node_type, str(path), line, line)
+
+    cfg = CallGraph(language="go")
+    for graph_node in [
+        node("models.base.Entity", NodeType.CLASS, base, "type Entity"),
+        node("models.base.(*Entity).Dispose", NodeType.METHOD, base, "Dispose()"),
+        node("models.task.Speaker", NodeType.INTERFACE, task, "type Speaker"),
+        node("models.task.Task", NodeType.CLASS, task, "type Task"),
+        node("models.task.User", NodeType.CLASS, task, "type User"),
+        node("models.task.(Task).Serialize", NodeType.METHOD, task, "Serialize()"),
+        node("models.task.(Task).Speak", NodeType.METHOD, task, "(t Task) Speak()"),
+    ]:
+        cfg.add_node(graph_node)
+    cfg.add_reference_edge("models.task.Task", "models.task.Speaker", EdgeKind.IMPLEMENTS)
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    fields = [
+        node("models.base.(Entity).ID", NodeType.FIELD, base, "ID "),
+        node("models.base.(Entity).typeName", NodeType.FIELD, base, "typeName"),
+        node("models.base.(Entity).disposed", NodeType.FIELD, base, "disposed bool"),
+        node("models.task.(Task).Entity", NodeType.FIELD, task, "*Entity"),
+        node("models.task.(Task).Owner", NodeType.FIELD, task, "Owner"),
+        node("models.task.(Task).Title", NodeType.FIELD, task, "Title string"),
+    ]
+    results.add_references(Language.GO, [*cfg.nodes.values(), *fields])
+    return results
+
+
+def test_go_types_get_members_with_visibility(tmp_path: Path):
+    diagram = build_class_diagram(_go_results(tmp_path), repo_dir=tmp_path)
+
+    entity = diagram.classes["models.base.Entity"]
+    assert entity.fields == ["+ID string", "-typeName string", "-disposed bool"]
+    assert entity.methods == ["+Dispose() «pointer»"]
+    task = diagram.classes["models.task.Task"]
+    assert task.fields == ["+Owner *User", "+Title string"]
+    assert task.methods == ["+Serialize()", "+Speak()"]
+
+
+def test_embedding_and_implementation_are_inheritance(tmp_path: Path):
+    diagram = build_class_diagram(_go_results(tmp_path), repo_dir=tmp_path)
+
+    assert diagram.inheritance == {
+        ("models.base.Entity", "models.task.Task"),
+        ("models.task.Speaker", "models.task.Task"),
+    }
+    assert diagram.associations == {("models.task.Task", "models.task.User", "Owner")}
+
+
+def test_mermaid_source(tmp_path: Path):
+    source = generate_class_diagram(build_class_diagram(_go_results(tmp_path), repo_dir=tmp_path))
+
+    assert source.startswith("classDiagram\n")
+    assert '    class models_task_Speaker["Speaker"]\n    <<interface>> models_task_Speaker\n' in source
+    assert "    models_base_Entity : -disposed bool\n" in source
+    assert "    models_task_Task : +Serialize()\n" in source
+    assert "    models_base_Entity <|-- models_task_Task\n" in source
+    assert "    models_task_Speaker <|-- models_task_Task\n" in source
+    assert "    models_task_Task --> models_task_User : Owner\n" in source
+
+
+def test_python_members_use_the_qualified_name_and_underscore_visibility(tmp_path: Path):
+    path = str(tmp_path / "shapes.py")
+    cfg = CallGraph(language="python")
+    cfg.add_node(Node("shapes.Shape", NodeType.CLASS, path, 1, 5))
+    cfg.add_node(Node("shapes.Circle", NodeType.CLASS, path, 7, 20))
+    cfg.add_node(Node("shapes.Circle.area", NodeType.METHOD, path, 10, 12))
+    cfg.add_node(Node("shapes.Circle._scale", NodeType.METHOD, path, 14, 16))
+    cfg.add_reference_edge("shapes.Circle", "shapes.Shape", EdgeKind.INHERITS)
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.PYTHON, cfg)
+    results.add_references(Language.PYTHON, [Node("shapes.Circle._radius", NodeType.FIELD, path, 8, 8)])
+
+    diagram = build_class_diagram(results)
+
+    assert diagram.classes["shapes.Circle"].fields == ["-_radius"]
+    assert diagram.classes["shapes.Circle"].methods == ["+area()", "-_scale()"]
+    assert diagram.inheritance == {("shapes.Shape", "shapes.Circle")}
+
+
+def test_write_wraps_the_diagram_in_markdown(tmp_path: Path):
+    output = write_class_diagram(build_class_diagram(_go_results(tmp_path)), tmp_path / CLASS_DIAGRAM_FILENAME, "demo")
+
+    text = output.read_text()
+    assert text.startswith("# demo class diagram\n\n```mermaid\nclassDiagram\n")
+    assert text.endswith("```\n")
+
+
+def test_named_types_with_constants_are_enumerations(tmp_path: Path):
+    source = tmp_path / "models" / "task.go"
+    source.parent.mkdir()
+    source.write_text(
+        "package models\n\ntype Priority int\n\ntype Status string\n\n"
+        "const (\n\tPriorityLow Priority = iota\n\tPriorityMedium\n\tPriorityHigh\n)\n\n"
+        'const (\n\tStatusPending Status = "pending"\n\tStatusDone Status = "done"\n)\n\n'
+        "const maxRetries = 3\n"
+    )
+    (tmp_path / "models" / "task_test.go").write_text("package models\n\nconst PriorityTest Priority = 9\n")
+    cfg = CallGraph(language="go")
+    cfg.add_node(Node("models.task.Priority", NodeType.CLASS, str(source), 3, 3))
+    cfg.add_node(Node("models.task.Status", NodeType.CLASS, str(source), 5, 5))
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+
+    diagram = build_class_diagram(results, repo_dir=tmp_path)
+
+    priority = diagram.classes["models.task.Priority"]
+    assert priority.node_type == NodeType.ENUM
+    assert priority.enum_members == ["+PriorityLow = 0", "+PriorityMedium = 1", "+PriorityHigh = 2"]
+    source_text = generate_class_diagram(diagram)
+    assert "    <<enumeration>> models_task_Status\n" in source_text
+    assert "    models_task_Status : +StatusPending = 'pending'\n" in source_text
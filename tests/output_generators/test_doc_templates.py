@@ -0,0 +1,124 @@
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, SourceCodeReference
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from output_generators import doc_templates
+from output_generators.doc_templates import (
+    DEFAULT_TEMPLATES_DIR,
+    ComponentTemplate,
+    DocTemplateError,
+    DocTemplates,
+    component_context,
+    template_format,
+)
+from output_generators.markdown import generate_markdown
+
+
+def _analysis() -> AnalysisInsights:
+    api = Component(
+        name="Api Server",
+        description="Serves <requests>.",
+        key_entities=[
+            SourceCodeReference(
+                qualified_name="api.server.serve",
+                reference_file="api/server.py",
+                reference_start_line=3,
+                reference_end_line=9,
+            )
+        ],
+        component_id="1",
+        file_methods=[
+            FileMethodGroup(
+                file_path="api/server.py",
+                methods=[
+                    MethodEntry(qualified_name="api.server.serve", start_line=3, end_line=9, node_type="FUNCTION")
+                ],
+            )
+        ],
+    )
+    store = Component(name="Store", description="Persists state.", key_entities=[], component_id="2")
+    return AnalysisInsights(
+        description="A service.",
+        components=[api, store],
+        components_relations=[Relation(relation="reads from", src_name="Api Server", dst_name="Store")],
+    )
+
+
+def test_template_format_comes_from_the_inner_suffix():
+    assert template_format(Path("component.md.j2")) == ".md"
+    assert template_format(Path("docs/api.rst.j2")) == ".rst"
+    for name in ("component.j2", "component.txt.j2", "component.md"):
+        with pytest.raises(DocTemplateError):
+            template_format(Path(name))
+
+
+def test_component_context_is_plain_data():
+    analysis = _analysis()
+
+    context = component_context(analysis, analysis.components[0], expanded_components={"1"})
+
+    assert context["slug"] == "Api_Server"
+    assert context["expanded"] is True
+    assert context["depends_on"] == [{"name": "Store", "relation": "reads from"}]
+    assert context["used_by"] == []
+    assert context["files"][0]["methods"][0]["name"] == "api.server.serve"
+    assert context["metrics"] == {"files": 1, "methods": 1, "lines": 7}
+    assert component_context(analysis, analysis.components[1], set())["used_by"] == [
+        {"name": "Api Server", "relation": "reads from"}
+    ]
+
+
+def test_missing_jinja2_is_reported_when_loading(tmp_path: Path):
+    path = tmp_path / "component.md.j2"
+    path.write_text("{{ component.name }}")
+
+    with patch.object(doc_templates, "JINJA2_AVAILABLE", False):
+        with pytest.raises(DocTemplateError, match="codeboarding\\[templates\\]"):
+            ComponentTemplate.load(path)
+
+
+def test_template_lays_out_each_component(tmp_path: Path):
+    pytest.importorskip("jinja2")
+    path = tmp_path / "component.md.j2"
+    path.write_text("## {{ component.name }} ({{ component.metrics.methods }} methods)\n")
+    analysis = _analysis()
+
+    markdown = generate_markdown(
+        analysis,
+        project="demo",
+        repo_ref="",
+        expanded_components=set(),
+        doc_template=ComponentTemplate.load(path),
+    )
+
+    assert "## Api Server (1 methods)\n" in markdown
+    assert "## Store (0 methods)\n" in markdown
+    assert "Related Classes/Methods" not in markdown
+
+
+def test_template_errors_name_the_template(tmp_path: Path):
+    pytest.importorskip("jinja2")
+    broken = tmp_path / "component.md.j2"
+    broken.write_text("{% for x in %}\n")
+    with pytest.raises(DocTemplateError, match="component.md.j2, line 1"):
+        ComponentTemplate.load(broken)
+
+    typo = tmp_path / "component.html.j2"
+    typo.write_text("{{ component.nmae }}")
+    analysis = _analysis()
+    with pytest.raises(DocTemplateError, match="rendering 'Api Server' failed"):
+        ComponentTemplate.load(typo).render(analysis, analysis.components[0], set())
+
+
+def test_default_templates_compile():
+    pytest.importorskip("jinja2")
+
+    templates = DocTemplates.load(sorted(DEFAULT_TEMPLATES_DIR.glob("*.j2")))
+
+    assert sorted(templates.by_format) == [".html", ".md", ".mdx", ".rst"]
+    analysis = _analysis()
+    html = templates.for_format(".html").render(analysis, analysis.components[0], set())
+    assert "Serves &lt;requests&gt;." in html
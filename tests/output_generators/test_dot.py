@@ -0,0 +1,136 @@
+import subprocess
+from pathlib import Path
+from unittest.mock import patch
+
+from output_generators.dot import DOT_FILENAME, generate_dot, render_dot, write_dot_file
+from tests.output_generators.test_fluent_chains import build_query_graph
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+ENTITY_GO = "package models\n\ntype Entity struct {\n\tID string\n}\n"
+TASK_GO = "package models\n\ntype Task struct {\n\tEntity\n\tTitle string\n}\n"
+
+
+def _results(tmp_path: Path) -> StaticAnalysisResults:
+    models = tmp_path / "models"
+    models.mkdir()
+    (models / "entity.go").write_text(ENTITY_GO)
+    (models / "task.go").write_text(TASK_GO)
+    services = str(tmp_path / "services" / "speaker.go")
+    cfg = CallGraph(language="go")
+    cfg.add_node(Node("models.entity.Entity", NodeType.CLASS, str(models / "entity.go"), 3, 5))
+    cfg.add_node(Node("models.task.Task", NodeType.CLASS, str(models / "task.go"), 3, 6))
+    cfg.add_node(Node("models.task.(Task).Speak", NodeType.METHOD, str(models / "task.go"), 8, 8))
+    cfg.add_node(Node("services.speaker.Speaker", NodeType.INTERFACE, services, 3, 5))
+    cfg.add_node(Node("services.speaker.Announce", NodeType.FUNCTION, services, 7, 9))
+    cfg.add_edge("services.speaker.Announce", "models.task.(Task).Speak")
+    cfg.add_reference_edge("models.task.Task", "services.speaker.Speaker", EdgeKind.IMPLEMENTS)
+    cfg.add_reference_edge("models.task.(Task).Speak", "models.task.Task", EdgeKind.CONTAINS)
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    embedded = Node("models.task.(Task).Entity", NodeType.FIELD, str(models / "task.go"), 4, 4)
+    results.add_references(Language.GO, [*cfg.nodes.values(), embedded])
+    return results
+
+
+def test_nodes_are_clustered_by_package(tmp_path: Path):
+    dot = generate_dot(_results(tmp_path), repo_dir=tmp_path, name="demo")
+
+    assert dot.startswith('digraph "demo" {\n')
+    models = dot.index('subgraph "cluster_models"')
+    services = dot.index('subgraph "cluster_services"')
+    assert models < dot.index('"models.task.Task" [label="Task", shape=box') < services
+    assert services < dot.index('"services.speaker.Speaker" [label="Speaker", shape=ellipse')
+    assert dot.rstrip().endswith("}")
+
+
+def test_edges_are_styled_by_kind(tmp_path: Path):
+    dot = generate_dot(_results(tmp_path), repo_dir=tmp_path)
+
+    assert '    "services.speaker.Announce" -> "models.task.(Task).Speak";\n' in dot
+    assert '"models.task.Task" -> "services.speaker.Speaker" [style=dashed, arrowhead=empty, label="implements"]' in dot
+    assert '"models.task.Task" -> "models.entity.Entity" [style=bold, arrowhead=odiamond, label="embeds"]' in dot
+    # Clusters already show containment.
+    assert '"models.task.(Task).Speak" -> "models.task.Task"' not in dot
+
+
+def test_recursion_is_highlighted(tmp_path: Path):
+    results = _results(tmp_path)
+    cfg = results.get_cfg(Language.GO)
+    services = str(tmp_path / "services" / "speaker.go")
+    cfg.add_node(Node("services.speaker.even", NodeType.FUNCTION, services, 11, 13))
+    cfg.add_node(Node("services.speaker.odd", NodeType.FUNCTION, services, 15, 17))
+    cfg.add_edge("services.speaker.Announce", "services.speaker.Announce")
+    cfg.add_edge("services.speaker.Announce", "services.speaker.even")
+    cfg.add_edge("services.speaker.even", "services.speaker.odd")
+    cfg.add_edge("services.speaker.odd", "services.speaker.even")
+
+    dot = generate_dot(results, repo_dir=tmp_path)
+
+    assert '"services.speaker.Announce" -> "services.speaker.Announce" [color=darkorange, label="recursive"];' in dot
+    assert '"services.speaker.even" -> "services.speaker.odd" [color=darkorange];' in dot
+    assert '"services.speaker.Announce" -> "services.speaker.even";' in dot
+    assert '"services.speaker.odd" [label="odd", tooltip="services.speaker.odd", color=darkorange, penwidth=2];' in dot
+
+
+def test_dispatch_calls_are_labelled(tmp_path: Path):
+    results = _results(tmp_path)
+    cfg = results.get_cfg(Language.GO)
+    services = cfg.nodes["services.speaker.Announce"].file_path
+    cfg.add_node(Node("services.speaker.speakAll", NodeType.FUNCTION, services, 11, 13))
+    cfg.add_edge("services.speaker.speakAll", "models.task.(Task).Speak", [{"line": 12, "via": "dispatch"}])
+
+    dot = generate_dot(results, repo_dir=tmp_path)
+
+    assert '"services.speaker.speakAll" -> "models.task.(Task).Speak" [style=dashed, label="via dispatch"];' in dot
+    assert '    "services.speaker.Announce" -> "models.task.(Task).Speak";\n' in dot
+
+
+def test_render_skips_without_graphviz(tmp_path: Path):
+    dot_path = write_dot_file("digraph {}\n", tmp_path / DOT_FILENAME)
+
+    with patch("output_generators.dot.shutil.which", return_value=None):
+        assert render_dot(dot_path, "svg") is None
+    assert dot_path.exists()
+
+
+def test_render_runs_dot(tmp_path: Path):
+    dot_path = write_dot_file("digraph {}\n", tmp_path / DOT_FILENAME)
+
+    with (
+        patch("output_generators.dot.shutil.which", return_value="/usr/bin/dot"),
+        patch("output_generators.dot.subprocess.run") as run,
+    ):
+        image = render_dot(dot_path, "png")
+
+    assert image == tmp_path / "call_graph.png"
+    assert run.call_args.args[0] == ["dot", "-Tpng", str(dot_path), "-o", str(image)]
+
+
+def test_render_failure_keeps_the_dot_file(tmp_path: Path):
+    dot_path = write_dot_file("digraph {}\n", tmp_path / DOT_FILENAME)
+    error = subprocess.CalledProcessError(1, ["dot"], stderr=b"syntax error")
+
+    with (
+        patch("output_generators.dot.shutil.which", return_value="/usr/bin/dot"),
+        patch("output_generators.dot.subprocess.run", side_effect=error),
+    ):
+        assert render_dot(dot_path, "svg") is None
+    assert dot_path.exists()
+
+
+def test_collapse_chains_draws_one_labelled_edge_to_the_builder():
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, build_query_graph())
+
+    plain = generate_dot(results)
+    collapsed = generate_dot(results, collapse_chains=True)
+
+    assert plain.count('"store.query.BuildQuery" -> ') == 4
+    assert collapsed.count('"store.query.BuildQuery" -> ') == 1
+    assert (
+        '"store.query.BuildQuery" -> "store.query.QueryBuilder" [style=bold, label="chain: Where,OrderBy,Limit,Build"]'
+        in collapsed
+    )
@@ -0,0 +1,64 @@
+from output_generators.fluent_chains import Chain, find_chains, is_fluent, receiver_type
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node, Parameter, Signature
+
+QUERY_GO = "/repo/store/query.go"
+BUILDER = "store.query.QueryBuilder"
+
+
+def _method(name: str, result: str, line: int = 10) -> Node:
+    node = Node(f"store.query.(*QueryBuilder).{name}", NodeType.METHOD, QUERY_GO, line, line + 2)
+    node.signature = Signature(parameters=(), results=(Parameter(result),))
+    return node
+
+
+def build_query_graph() -> CallGraph:
+    cfg = CallGraph(language="go")
+    cfg.add_node(Node(BUILDER, NodeType.CLASS, QUERY_GO, 3, 6))
+    cfg.add_node(Node("store.query.BuildQuery", NodeType.FUNCTION, QUERY_GO, 20, 22))
+    for line, (name, result) in enumerate(
+        [("Where", "*QueryBuilder"), ("OrderBy", "*QueryBuilder"), ("Limit", "*QueryBuilder"), ("Build", "string")]
+    ):
+        cfg.add_node(_method(name, result, 30 + 3 * line))
+    # Listed out of order: the chain follows the call columns.
+    for name, column in [("Build", 40), ("Where", 4), ("Limit", 30), ("OrderBy", 18)]:
+        cfg.add_edge(
+            "store.query.BuildQuery",
+            f"store.query.(*QueryBuilder).{name}",
+            [{"file": QUERY_GO, "line": 21, "column": column}],
+        )
+    return cfg
+
+
+def test_fluent_methods_return_their_receiver_type():
+    assert is_fluent(_method("Where", "*QueryBuilder"))
+    assert is_fluent(_method("Where", "store.QueryBuilder"))
+    assert not is_fluent(_method("Build", "string"))
+    assert not is_fluent(Node("store.query.BuildQuery", NodeType.FUNCTION, QUERY_GO, 1, 1))
+    assert receiver_type("store.query.(*QueryBuilder).Where") == BUILDER
+    assert receiver_type("store.query.BuildQuery") is None
+
+
+def test_calls_into_one_builder_form_a_chain_in_call_order():
+    chains = find_chains(build_query_graph())
+
+    assert chains == [
+        Chain(
+            "store.query.BuildQuery",
+            BUILDER,
+            tuple(f"store.query.(*QueryBuilder).{name}" for name in ("Where", "OrderBy", "Limit", "Build")),
+        )
+    ]
+    assert chains[0].label() == "chain: Where,OrderBy,Limit,Build"
+
+
+def test_calls_without_a_fluent_method_are_not_a_chain():
+    cfg = CallGraph(language="go")
+    cfg.add_node(Node("store.query.Run", NodeType.FUNCTION, QUERY_GO, 1, 3))
+    cfg.add_node(_method("Build", "string"))
+    cfg.add_node(_method("Close", "error", 20))
+    cfg.add_edge("store.query.Run", "store.query.(*QueryBuilder).Build")
+    cfg.add_edge("store.query.Run", "store.query.(*QueryBuilder).Close")
+
+    assert find_chains(cfg) == []
@@ -0,0 +1,102 @@
+import json
+from pathlib import Path
+
+from output_generators.json_export import JSON_SCHEMA_VERSION, build_json_model, write_json_model
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node, Parameter, Signature
+
+REPO = Path("/repo")
+
+
+def _results() -> StaticAnalysisResults:
+    cfg = CallGraph(language="go")
+    path = str(REPO / "services" / "processor.go")
+    dispose = Node("services.processor.(*Entity).Dispose", NodeType.METHOD, path, 12, 15)
+    dispose.signature = Signature(parameters=(Parameter("bool", "force"),), results=(Parameter("error"),))
+    for node in [
+        dispose,
+        Node("services.processor.Entity", NodeType.STRUCT, path, 5, 9),
+        Node("services.processor.Process", NodeType.FUNCTION, path, 20, 30),
+        Node("services.processor.entityCount", NodeType.VARIABLE, path, 3, 3),
+        Node("main.run", NodeType.FUNCTION, str(REPO / "main.go"), 1, 4),
+    ]:
+        cfg.add_node(node)
+    cfg.add_edge(
+        "services.processor.Process",
+        "services.processor.(*Entity).Dispose",
+        [{"file": path, "line": 22, "column": 3}],
+    )
+    cfg.add_reference_edge("services.processor.Process", "services.processor.entityCount", EdgeKind.MUTATES)
+    cfg.add_reference_edge("main.run", "services.processor.Process", EdgeKind.SPAWNS, confidence="high")
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    return results
+
+
+def test_entities_carry_kind_package_file_receiver_and_signature():
+    model = build_json_model(_results(), REPO)
+
+    assert model["schema_version"] == JSON_SCHEMA_VERSION
+    assert model["languages"] == ["go"]
+    entities = {entity["qualified_name"]: entity for entity in model["entities"]}
+    assert list(entities) == sorted(entities)
+    dispose = entities["services.processor.(*Entity).Dispose"]
+    assert dispose["kind"] == "method"
+    assert dispose["package"] == "services"
+    assert dispose["file"] == "services/processor.go"
+    assert (dispose["line_start"], dispose["line_end"]) == (12, 15)
+    assert dispose["receiver"] == {"kind": "pointer", "type": "Entity"}
+    assert dispose["visibility"] == "exported"
+    assert entities["services.processor.entityCount"]["visibility"] == "unexported"
+    assert dispose["signature"] == {
+        "text": "Dispose(force bool) error",
+        "parameters": [{"name": "force", "type": "bool", "variadic": False}],
+        "results": [{"name": None, "type": "error", "variadic": False}],
+    }
+    entity = entities["services.processor.Entity"]
+    assert (entity["kind"], entity["symbol_kind"], entity["receiver"], entity["signature"]) == (
+        "type",
+        "struct",
+        None,
+        None,
+    )
+    assert entities["main.run"]["package"] == "main"
+
+
+def test_edges_carry_their_kind_call_sites_and_confidence():
+    model = build_json_model(_results(), REPO)
+
+    assert model["edges"] == [
+        {"source": "main.run", "target": "services.processor.Process", "kind": "spawns", "confidence": "high"},
+        {
+            "source": "services.processor.Process",
+            "target": "services.processor.(*Entity).Dispose",
+            "kind": "call",
+            "call_sites": [{"file": "services/processor.go", "line": 22, "column": 3}],
+        },
+        {"source": "services.processor.Process", "target": "services.processor.entityCount", "kind": "mutates"},
+    ]
+
+
+def test_recursive_calls_are_flagged_and_cycles_listed():
+    results = _results()
+    cfg = results.get_cfg(Language.GO)
+    cfg.add_edge("services.processor.Process", "services.processor.Process")
+    cfg.add_edge("services.processor.(*Entity).Dispose", "services.processor.Process")
+
+    model = build_json_model(results, REPO)
+
+    self_call = next(edge for edge in model["edges"] if edge["source"] == edge["target"])
+    assert (self_call["source"], self_call["recursive"]) == ("services.processor.Process", True)
+    assert all("recursive" not in edge for edge in model["edges"] if edge is not self_call)
+    assert model["recursive_cycles"] == [["services.processor.(*Entity).Dispose", "services.processor.Process"]]
+
+
+def test_written_file_is_deterministic(tmp_path: Path):
+    first = write_json_model(build_json_model(_results(), REPO), tmp_path / "a" / "static_analysis.json")
+    second = write_json_model(build_json_model(_results(), REPO), tmp_path / "b" / "static_analysis.json")
+
+    assert first.read_text() == second.read_text()
+    assert json.loads(first.read_text())["schema_version"] == JSON_SCHEMA_VERSION
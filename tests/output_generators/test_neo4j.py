@@ -0,0 +1,148 @@
+import csv
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, Relation, RelationEdge, SourceCodeReference
+from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from output_generators.neo4j import (
+    CYPHER_FILENAME,
+    EDGES_FILENAME,
+    NODES_FILENAME,
+    build_neo4j_graph,
+    write_neo4j_files,
+)
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+
+def _method(name: str, node_type: str, kind: str = "") -> MethodEntry:
+    return MethodEntry(qualified_name=name, start_line=3, end_line=9, node_type=node_type, kind=kind)
+
+
+def _component(cid: str, name: str, path: str, methods: list[MethodEntry]) -> Component:
+    return Component(
+        name=name,
+        description=f"{name} does things",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=path, methods=methods)],
+    )
+
+
+def _analyses() -> tuple[AnalysisInsights, dict[str, AnalysisInsights]]:
+    handler = _method("api.Handler", "CLASS", "type")
+    route = _method("api.route", "FUNCTION", "function")
+    # Saved before canonical kinds were recorded.
+    save = _method("store.save", "METHOD")
+    root = AnalysisInsights(
+        description="",
+        components=[
+            _component("1", "API", "api.py", [handler, route]),
+            _component("2", "Storage", "store.py", [save]),
+        ],
+        components_relations=[
+            Relation(
+                relation="writes through",
+                src_name="API",
+                dst_name="Storage",
+                src_id="1",
+                dst_id="2",
+                all_edges=[
+                    RelationEdge(
+                        source=SourceCodeReference(qualified_name="api.route"),
+                        target=SourceCodeReference(qualified_name="store.save"),
+                    )
+                ],
+            )
+        ],
+    )
+    root.files = {"api.py": FileEntry(methods=[handler, route]), "store.py": FileEntry(methods=[save])}
+    sub = AnalysisInsights(
+        description="", components=[_component("1.1", "Routing", "api.py", [route])], components_relations=[]
+    )
+    return root, {"1": sub}
+
+
+def test_graph_follows_schema_with_static_edges(tmp_path: Path):
+    root, subs = _analyses()
+    cfg = CallGraph(language="python")
+    cfg.add_node(Node("api.Handler", NodeType.CLASS, str(tmp_path / "api.py"), 1, 20))
+    cfg.add_node(Node("api.route", NodeType.FUNCTION, str(tmp_path / "api.py"), 3, 9))
+    cfg.add_node(Node("api.BaseHandler", NodeType.CLASS, str(tmp_path / "api.py"), 22, 30))
+    cfg.add_node(Node("store.save", NodeType.METHOD, str(tmp_path / "store.py"), 3, 9))
+    cfg.add_edge("api.route", "store.save")
+    cfg.add_reference_edge("api.Handler", "api.BaseHandler", EdgeKind.INHERITS)
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.PYTHON, cfg)
+
+    graph = build_neo4j_graph(root, subs, results, repo_dir=tmp_path)
+
+    route = graph.nodes["symbol:api.route"]
+    assert route.labels == ["Symbol", "Function"]
+    assert route.properties == {
+        "name": "route",
+        "qualified_name": "api.route",
+        "file": "api.py",
+        "line": 3,
+        "component_id": "1.1",
+    }
+    assert graph.nodes["symbol:store.save"].labels == ["Symbol", "Method"]
+    assert graph.nodes["symbol:api.BaseHandler"].properties["file"] == "api.py"
+    assert "component_id" not in graph.nodes["symbol:api.BaseHandler"].properties
+    assert graph.nodes["component:1.1"].properties["description"] == "Routing does things"
+
+    edges = {(e.start, e.end, e.type) for e in graph.edges}
+    assert edges == {
+        ("symbol:api.route", "symbol:store.save", "CALLS"),
+        ("symbol:api.Handler", "symbol:api.BaseHandler", "INHERITS"),
+        ("component:1.1", "component:1", "PART_OF"),
+        ("component:1", "component:2", "DEPENDS_ON"),
+        ("symbol:api.Handler", "component:1", "BELONGS_TO"),
+        ("symbol:api.route", "component:1", "BELONGS_TO"),
+        ("symbol:api.route", "component:1.1", "BELONGS_TO"),
+        ("symbol:store.save", "component:2", "BELONGS_TO"),
+    }
+    depends = next(e for e in graph.edges if e.type == "DEPENDS_ON")
+    assert depends.properties == {"relation": "writes through", "edges": 1}
+
+
+def test_files_load_with_neo4j_admin_headers_and_cypher(tmp_path: Path):
+    root, subs = _analyses()
+    root.components[0].description = 'Serves "public" requests'
+
+    out = write_neo4j_files(build_neo4j_graph(root, subs), tmp_path / "neo4j")
+
+    with open(out / NODES_FILENAME, encoding="utf-8", newline="") as f:
+        nodes = list(csv.DictReader(f))
+    assert list(nodes[0]) == [
+        "id:ID",
+        ":LABEL",
+        "name",
+        "qualified_name",
+        "description",
+        "file",
+        "line:int",
+        "component_id",
+    ]
+    api = next(row for row in nodes if row["id:ID"] == "component:1")
+    assert api[":LABEL"] == "Component" and api["description"] == 'Serves "public" requests'
+    with open(out / EDGES_FILENAME, encoding="utf-8", newline="") as f:
+        edges = list(csv.DictReader(f))
+    # Without static_analysis.pkl, symbol edges are the calls recorded on relations.
+    assert {"symbol:api.route", "symbol:store.save", "CALLS"} == {
+        edges[0][":START_ID"],
+        edges[0][":END_ID"],
+        edges[0][":TYPE"],
+    }
+
+    cypher = (out / CYPHER_FILENAME).read_text(encoding="utf-8")
+    assert "FOR (n:Symbol) REQUIRE n.id IS UNIQUE;" in cypher
+    assert (
+        'CREATE (:Component {id: "component:1", name: "API", description: "Serves \\"public\\" requests", '
+        'component_id: "1"});' in cypher
+    )
+    assert (
+        'MATCH (a:Component {id: "component:1"}), (b:Component {id: "component:2"}) '
+        'CREATE (a)-[:DEPENDS_ON {relation: "writes through", edges: 1}]->(b);' in cypher
+    )
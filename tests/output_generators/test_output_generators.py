@@ -10,6 +10,7 @@ from agents.agent_responses import (
     SourceCodeReference,
     assign_component_ids,
 )
+from agents.file_index_models import FileMethodGroup, MethodEntry
 from utils import sanitize
 from output_generators.html import (
     component_header_html,
@@ -18,6 +19,7 @@ from output_generators.html import (
     generate_html_file,
 )
 from output_generators.markdown import (
+    collapsible_component_header,
     component_header,
     generate_markdown,
     generate_markdown_file,
@@ -80,6 +82,15 @@ class TestMarkdownGenerator(unittest.TestCase):
         self.assertIn("Database", result)
         self.assertIn('Authentication -- "uses" --> Database', result)
 
+    def test_generated_mermaid_str_draws_implements_dotted(self):
+        self.insights.components_relations.append(
+            Relation(src_name="Database", dst_name="Authentication", relation="implements", implements=True)
+        )
+        result = generated_mermaid_str(self.insights, expanded_components=set(), repo_ref="", project="test")
+
+        self.assertIn('Authentication -- "uses" --> Database', result)
+        self.assertIn('Database -. "implements" .-> Authentication', result)
+
     def test_generated_mermaid_str_with_links(self):
         # Test with expanded components
         expanded = {self.comp1.component_id}
@@ -164,6 +175,50 @@ class TestMarkdownGenerator(unittest.TestCase):
         self.assertIn("TestComponent", result)
         self.assertNotIn("[[Expand]]", result)
 
+    def test_generate_markdown_collapsible_nests_source_directories(self):
+        method = MethodEntry(qualified_name="auth.login", start_line=1, end_line=5, node_type="FUNCTION")
+        comp = Component(name="Auth", description="Auth component", key_entities=[])
+        comp.file_methods = [
+            FileMethodGroup(file_path="src/auth/login.py", methods=[method]),
+            FileMethodGroup(file_path="src/auth/tokens.py", methods=[]),
+            FileMethodGroup(file_path="setup.py", methods=[]),
+        ]
+        insights = AnalysisInsights(description="Test", components=[comp], components_relations=[])
+        assign_component_ids(insights)
+
+        result = generate_markdown(insights, project="test", repo_ref="", expanded_components=set(), collapsible=True)
+
+        self.assertIn("<details>\n<summary><b>Auth</b></summary>\n", result)
+        # ``src`` has no files of its own, so it merges with ``auth`` into one summary.
+        self.assertIn("<summary><code>src/auth/</code> (2 files)</summary>", result)
+        self.assertNotIn("<code>src/</code>", result)
+        self.assertIn("- `src/auth/login.py`\n  - `auth.login` (L1-L5)", result)
+        self.assertLess(result.index("- `setup.py`"), result.index("<code>src/auth/</code>"))
+        self.assertEqual(result.count("<details>"), result.count("</details>"))
+
+    def test_generate_markdown_shows_declared_signatures(self):
+        method = MethodEntry(
+            qualified_name="mw.Compose",
+            start_line=3,
+            end_line=9,
+            node_type="FUNCTION",
+            signature="Compose(fns ...HandlerFunc) HandlerFunc",
+        )
+        comp = Component(name="Middleware", description="Middleware chain", key_entities=[])
+        comp.file_methods = [FileMethodGroup(file_path="mw/chain.go", methods=[method])]
+        insights = AnalysisInsights(description="Test", components=[comp], components_relations=[])
+        assign_component_ids(insights)
+
+        result = generate_markdown(insights, project="test", repo_ref="", expanded_components=set())
+
+        self.assertIn("  - `mw.Compose` (L3-L9) - Function `Compose(fns ...HandlerFunc) HandlerFunc`\n", result)
+
+    def test_collapsible_component_header_with_link(self):
+        result = collapsible_component_header("Test Component", "test_comp_id", {"test_comp_id"})
+
+        self.assertTrue(result.startswith("<details>\n<summary>"))
+        self.assertIn('<a href="./Test_Component.md">[Expand]</a>', result)
+
 
 class TestHTMLGenerator(unittest.TestCase):
     def setUp(self):
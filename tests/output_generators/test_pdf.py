@@ -0,0 +1,90 @@
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from codeboarding_workflows.rendering import render_pdf
+from diagram_analysis.analysis_json import build_unified_analysis_json
+from output_generators import pdf
+from output_generators.pdf import PdfSection, PdfToolchainError, build_pdf_html
+from output_generators.preamble import DocsPreamble
+
+OVERVIEW_MD = """\
+# Shop
+
+```mermaid
+graph LR
+    Api["Api"]
+    Store["Store"]
+    Api -- "reads" --> Store
+```
+
+[![CodeBoarding](https://img.shields.io/badge/Generated%20by-CodeBoarding-9cf?style=flat-square)](https://github.com/CodeBoarding/CodeBoarding)
+
+## Details
+
+### Store [[Expand]](./Store.md)
+Persists orders.
+
+### [FAQ](https://github.com/CodeBoarding/GeneratedOnBoardings/tree/main?tab=readme-ov-file#faq)
+"""
+
+
+def _fake_svg(source: str, svg_path: Path) -> Path:
+    svg_path.write_text("<svg/>")
+    return svg_path
+
+
+def test_pdf_document_has_cover_contents_and_inline_diagrams(tmp_path: Path):
+    (tmp_path / "overview.md").write_text(OVERVIEW_MD)
+    (tmp_path / "Store.md").write_text("### Orders table\nOne row per order.\n")
+    sections = [
+        PdfSection(anchor="overview", title="Overview", markdown_path=tmp_path / "overview.md"),
+        PdfSection(anchor="Store", title="Store", markdown_path=tmp_path / "Store.md"),
+    ]
+
+    with patch.object(pdf, "render_svg", side_effect=_fake_svg) as render_svg:
+        document = build_pdf_html("Shop <Architecture>", "Architecture of shop", sections, tmp_path)
+
+    assert render_svg.call_args.args[0].startswith("graph LR")
+    assert '<section class="cover">\n<h1>Shop &lt;Architecture&gt;</h1>' in document
+    assert '<li><a href="#overview">Overview</a></li>\n<li><a href="#Store">Store</a></li>' in document
+    assert f'src="{(tmp_path / "overview-0.svg").resolve().as_uri()}"' in document
+    assert "```mermaid" not in document
+    assert '<a href="#Store">[Expand]</a>' in document
+    assert '<section class="doc" id="Store">' in document
+    assert "img.shields.io" not in document and "FAQ" not in document
+
+
+def test_missing_toolchain_still_writes_the_markdown(tmp_path: Path):
+    analysis = AnalysisInsights(
+        description="A shop.",
+        components=[
+            Component(name="Api", description="Serves.", key_entities=[], component_id="1"),
+            Component(name="Store", description="Persists.", key_entities=[], component_id="2"),
+        ],
+        components_relations=[Relation(relation="reads", src_name="Api", dst_name="Store")],
+    )
+    analysis_path = tmp_path / "analysis.json"
+    analysis_path.write_text(
+        build_unified_analysis_json(
+            analysis, [], "shop", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+        )
+    )
+
+    with (
+        patch.object(pdf, "WEASYPRINT_AVAILABLE", False),
+        patch.object(pdf.shutil, "which", return_value=None),
+        pytest.raises(PdfToolchainError, match=r"codeboarding\[pdf\].*npm install -g @mermaid-js/mermaid-cli"),
+    ):
+        render_pdf(
+            analysis_path,
+            repo_name="shop",
+            output_dir=tmp_path / "pdf",
+            preamble=DocsPreamble(title="Shop", intro="For leadership."),
+        )
+
+    overview = (tmp_path / "pdf" / "overview.md").read_text()
+    assert "For leadership." in overview and "# Shop" not in overview
+    assert not (tmp_path / "pdf" / "architecture.pdf").exists()
@@ -0,0 +1,78 @@
+from pathlib import Path
+
+import pytest
+
+from output_generators.sequence import (
+    SEQUENCE_FILENAME,
+    SequenceError,
+    build_sequence,
+    generate_plantuml,
+    write_sequence_diagram,
+)
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+
+def _results() -> StaticAnalysisResults:
+    cfg = CallGraph(language="go")
+    for line, (qname, node_type) in enumerate(
+        [
+            ("main.main", NodeType.FUNCTION),
+            ("models.dog.NewDog", NodeType.FUNCTION),
+            ("models.base.(*Entity).SetType", NodeType.METHOD),
+            ("models.base.validate", NodeType.FUNCTION),
+            ("utils.walk.Walk", NodeType.FUNCTION),
+            ("utils.log.Print", NodeType.FUNCTION),
+        ],
+        start=1,
+    ):
+        cfg.add_node(Node(qname, node_type, f"/repo/{qname.split('.')[0]}/x.go", line * 10, line * 10 + 5))
+    # Added out of source order: the call sites decide the order.
+    cfg.add_edge("main.main", "utils.walk.Walk", call_sites=[{"line": 9, "column": 2}])
+    cfg.add_edge("main.main", "models.dog.NewDog", call_sites=[{"line": 5, "column": 7}])
+    cfg.add_edge("main.main", "utils.log.Print")
+    cfg.add_edge("models.dog.NewDog", "models.base.(*Entity).SetType", call_sites=[{"line": 3, "column": 1}])
+    cfg.add_edge("models.base.(*Entity).SetType", "models.base.validate", call_sites=[{"line": 4, "column": 1}])
+    cfg.add_edge("utils.walk.Walk", "utils.walk.Walk", call_sites=[{"line": 6, "column": 3}])
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    return results
+
+
+def _calls(sequence) -> list[tuple[str, str, int, bool]]:
+    return [(m.caller, m.callee, m.depth, m.recursion) for m in sequence.messages]
+
+
+def test_calls_are_traced_in_source_order_up_to_the_depth():
+    sequence = build_sequence(_results(), "main.main", max_depth=2)
+
+    assert _calls(sequence) == [
+        ("main.main", "models.dog.NewDog", 1, False),
+        ("models.dog.NewDog", "models.base.(*Entity).SetType", 2, False),
+        ("main.main", "utils.walk.Walk", 1, False),
+        ("utils.walk.Walk", "utils.walk.Walk", 2, True),
+        ("main.main", "utils.log.Print", 1, False),
+    ]
+    # validate sits three calls below main.
+    assert "models.base.validate" in {m.callee for m in build_sequence(_results(), "main", max_depth=3).messages}
+
+
+def test_recursion_is_a_noted_self_message():
+    uml = generate_plantuml(build_sequence(_results(), "main.main", max_depth=3))
+
+    assert uml.startswith("@startuml\ntitle Calls from main.main (max depth 3)\n")
+    assert 'participant "models.base.(*Entity).SetType" as P2' in uml
+    assert "P0 -> P1 : NewDog()\nactivate P1\nP1 -> P2 : SetType()\nactivate P2\n" in uml
+    assert "P4 -> P4 : Walk()\nnote right of P4 : [recursion]\ndeactivate P4\n" in uml
+    assert uml.endswith("P0 -> P5 : Print()\nactivate P5\ndeactivate P5\n@enduml\n")
+
+
+def test_unknown_entry_point_is_an_error(tmp_path: Path):
+    with pytest.raises(SequenceError, match="No symbol 'missing'"):
+        build_sequence(_results(), "missing")
+
+    output, sequence = write_sequence_diagram(_results(), "NewDog", tmp_path / SEQUENCE_FILENAME)
+    assert sequence.entry == "models.dog.NewDog"
+    assert output.read_text().count(" -> ") == 2
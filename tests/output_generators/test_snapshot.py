@@ -0,0 +1,58 @@
+from agents.agent_responses import AnalysisInsights, Component, Relation
+from agents.file_index_models import FileMethodGroup
+from output_generators.snapshot import generate_snapshot
+
+
+def _component(cid: str, name: str, files: list[str] = ()) -> Component:
+    return Component(
+        name=name,
+        description=f"LLM prose about {name}",
+        key_entities=[],
+        component_id=cid,
+        file_methods=[FileMethodGroup(file_path=f) for f in files],
+    )
+
+
+def _relation(src: Component, dst: Component) -> Relation:
+    return Relation(
+        relation="LLM label", src_name=src.name, dst_name=dst.name, src_id=src.component_id, dst_id=dst.component_id
+    )
+
+
+def _tree() -> tuple[AnalysisInsights, dict[str, AnalysisInsights]]:
+    api = _component("1", "API", ["src/api/app.py"])
+    router = _component("1.10", "Router", ["src/api/router.py"])
+    views = _component("1.9", "Views", ["src/api/views.py", "src/api/forms.py"])
+    store = _component("2", "Storage", ["src/db/store.py"])
+    root = AnalysisInsights(
+        description="LLM overview",
+        components=[store, api],
+        components_relations=[_relation(router, store), _relation(views, router), _relation(router, store)],
+    )
+    return root, {"1": AnalysisInsights(description="", components=[router, views], components_relations=[])}
+
+
+def test_snapshot_is_sorted_and_llm_free():
+    root, subs = _tree()
+
+    snapshot = generate_snapshot(root, subs)
+
+    assert "LLM" not in snapshot.replace("LLM-free", "")
+    body = snapshot.split("[components]\n", 1)[1]
+    assert body == (
+        "1 API\n1.9 Views\n1.10 Router\n2 Storage\n"
+        "\n[files]\n1.9 src/api/forms.py\n1.9 src/api/views.py\n1.10 src/api/router.py\n2 src/db/store.py\n"
+        "\n[edges]\n1.9 Views -> 1.10 Router\n1.10 Router -> 2 Storage\n"
+    )
+
+
+def test_snapshot_is_independent_of_input_order():
+    root, subs = _tree()
+    shuffled = root.model_copy(
+        update={
+            "components": list(reversed(root.components)),
+            "components_relations": list(reversed(root.components_relations)),
+        }
+    )
+
+    assert generate_snapshot(shuffled, subs) == generate_snapshot(root, subs)
@@ -0,0 +1,55 @@
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component, SourceCodeReference
+from output_generators.html import generate_html
+from output_generators.markdown import generate_markdown
+from output_generators.snippets import REDACTED, SnippetSource
+
+SOURCE = '''def connect(host):
+    api_key = "sk-live-123"
+    client = Client(host, api_key)
+    client.open()
+    return client
+'''
+
+
+def _reference(path: str, start: int = 1, end: int = 5) -> SourceCodeReference:
+    return SourceCodeReference(
+        qualified_name="app.db.connect", reference_file=path, reference_start_line=start, reference_end_line=end
+    )
+
+
+def test_extract_truncates_redacts_and_honors_exclude(tmp_path: Path):
+    (tmp_path / "app").mkdir()
+    (tmp_path / "app" / "db.py").write_text(SOURCE)
+    source = SnippetSource(repo_dir=tmp_path, max_lines=3, exclude=["config/**"])
+
+    snippet = source.extract(_reference(str(tmp_path / "app" / "db.py")))
+
+    assert snippet is not None and snippet.truncated and snippet.language == "python"
+    assert snippet.code.splitlines()[0] == "def connect(host):"
+    assert f'api_key = "{REDACTED}"' in snippet.code and "sk-live" not in snippet.code
+    assert len(snippet.code.splitlines()) == 3
+    assert source.extract(_reference("config/settings.py")) is None
+    assert source.extract(_reference("app/db.py", start=None, end=None)) is None
+
+
+def test_for_repo_reads_policy_and_docs_embed_snippets(tmp_path: Path):
+    (tmp_path / ".codeboarding").mkdir()
+    (tmp_path / ".codeboarding" / "config.toml").write_text("[snippets]\nmax_lines = 2\nredact = false\n")
+    (tmp_path / "db.py").write_text(SOURCE)
+    source = SnippetSource.for_repo(tmp_path)
+    assert (source.max_lines, source.redact) == (2, False)
+
+    reference = _reference("db.py")
+    insights = AnalysisInsights(
+        description="",
+        components=[Component(name="Db", description="", key_entities=[reference], component_id="1")],
+        components_relations=[],
+    )
+
+    markdown = generate_markdown(insights, project="demo", repo_ref="", repo_path=tmp_path, snippets=source)
+    assert '  ```python\n  def connect(host):\n      api_key = "sk-live-123"\n  ...\n  ```' in markdown
+    html = generate_html(insights, project="demo", repo_ref="", repo_path=tmp_path, snippets=source)
+    assert "connect" in html and "sk-live-123" in html
+    assert "```python" not in generate_markdown(insights, project="demo", repo_ref="", repo_path=tmp_path)
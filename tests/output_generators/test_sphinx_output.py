@@ -16,6 +16,7 @@ from output_generators.sphinx import (
     generate_rst,
     generate_rst_file,
     generated_mermaid_str,
+    rst_label,
 )
 
 
@@ -352,6 +353,26 @@ class TestSphinxOutput(unittest.TestCase):
         self.assertIn("external.module", result)
         # Should handle reference without creating invalid URL
 
+    def test_component_header_escapes_special_characters(self):
+        result = component_header("*Auth* | `tokens`_", "test_id", set())
+
+        title, underline = result.split("\n")
+        self.assertEqual(title, "\\*Auth\\* \\| \\`tokens\\`\\_")
+        self.assertEqual(len(underline), len(title))
+        self.assertEqual(len(component_header("\u8a8d\u8a3c", "test_id", set()).split("\n")[1]), 4)
+
+    def test_generate_rst_defines_ref_targets_and_toctree(self):
+        root = generate_rst(
+            self.analysis, project=self.project, expanded_components=self.expanded_components, file_name="index"
+        )
+        child = generate_rst(self.analysis, project=self.project, file_name="Component1")
+
+        self.assertTrue(root.startswith(f".. _{rst_label('index')}:\n"))
+        self.assertIn(f":ref:`Expand <{rst_label('Component1')}>`", root)
+        self.assertTrue(child.startswith(f".. _{rst_label('Component1')}:\n"))
+        self.assertIn(".. toctree::\n   :hidden:\n\n   Component1\n   Component2", root)
+        self.assertIn('click Component1 href "Component1.html"', root)
+
 
 if __name__ == "__main__":
     unittest.main()
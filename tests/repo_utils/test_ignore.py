@@ -167,5 +167,48 @@ dist/
         self.assertTrue(self.ignore_manager.should_ignore(Path("node_modules/react/index.js")))
 
 
+class TestAnalysisScope(unittest.TestCase):
+    """``--include`` / ``--exclude`` globs narrow the manager they are given to."""
+
+    def setUp(self):
+        self.temp_dir = tempfile.mkdtemp()
+        self.repo_path = Path(self.temp_dir).resolve()
+        for rel in ["cmd/app.go", "models/task.go", "unused/orphan.go", "services/gen/lib.go"]:
+            (self.repo_path / rel).parent.mkdir(parents=True, exist_ok=True)
+            (self.repo_path / rel).write_text("package x\n")
+        # No default template patterns: only the scope decides here.
+        (self.repo_path / CODEBOARDING_DIR_NAME).mkdir()
+        (self.repo_path / CODEBOARDING_DIR_NAME / ".codeboardingignore").write_text("# nothing\n")
+
+    def tearDown(self):
+        shutil.rmtree(self.temp_dir, ignore_errors=True)
+
+    def _kept(self, include: tuple[str, ...] = (), exclude: tuple[str, ...] = ()) -> list[str]:
+        manager = RepoIgnoreManager(self.repo_path, include, exclude)
+        files = sorted(p for p in self.repo_path.rglob("*.go"))
+        return [p.relative_to(self.repo_path).as_posix() for p in manager.filter_paths(files)]
+
+    def test_exclude_drops_a_package(self):
+        manager = RepoIgnoreManager(self.repo_path, exclude=["**/unused/**"])
+
+        self.assertEqual(self._kept(exclude=("**/unused/**",)), ["cmd/app.go", "models/task.go", "services/gen/lib.go"])
+        self.assertEqual(manager.categorize_file(Path("unused/orphan.go")), "analysis_scope")
+        manager.reload()
+        self.assertTrue(manager.should_ignore(Path("unused/orphan.go")))
+
+    def test_include_keeps_matching_files_and_never_prunes_directories(self):
+        include = ("models/**", "services/**")
+        manager = RepoIgnoreManager(self.repo_path, include)
+
+        self.assertEqual(self._kept(include), ["models/task.go", "services/gen/lib.go"])
+        self.assertFalse(manager.should_ignore(self.repo_path / "cmd"))
+
+    def test_exclusions_win_over_inclusions(self):
+        self.assertEqual(self._kept(include=("services/**",), exclude=("**/gen/**",)), [])
+
+    def test_no_scope_keeps_everything(self):
+        self.assertEqual(len(self._kept()), 4)
+
+
 if __name__ == "__main__":
     unittest.main()
@@ -0,0 +1,18 @@
+package aliased
+
+import (
+	"fmt"
+
+	u "example.com/edgecases/utils"
+)
+
+func Sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total = u.Add(total, v)
+	}
+	fmt.Println(total)
+	var c u.Counter
+	c.Incr()
+	return total
+}
@@ -0,0 +1,4 @@
+package calc
+
+// Add shares its name with utils.Add, so the name alone is ambiguous.
+func Add(a, b float64) float64 { return a + b }
@@ -0,0 +1,8 @@
+package main
+
+import (
+	_ "example.com/edgecases/drivers"
+	"example.com/edgecases/textfmt"
+)
+
+func main() { println(format.Pretty("app")) }
@@ -0,0 +1,10 @@
+package dotted
+
+import . "example.com/edgecases/utils"
+
+func Twice(v int) int { return Add(v, v) }
+
+func Shadowed(v int) int {
+	Add := func(a, b int) int { return a - b }
+	return Add(v, 1)
+}
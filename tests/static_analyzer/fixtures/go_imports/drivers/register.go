@@ -0,0 +1,5 @@
+package drivers
+
+var registered []string
+
+func init() { registered = append(registered, "memory") }
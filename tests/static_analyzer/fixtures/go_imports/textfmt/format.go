@@ -0,0 +1,4 @@
+// The directory is textfmt, the package is format: an unrenamed import is used as format.Pretty.
+package format
+
+func Pretty(s string) string { return "<" + s + ">" }
@@ -0,0 +1,9 @@
+package utils
+
+func Add(a, b int) int { return a + b }
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Incr() { c.n++ }
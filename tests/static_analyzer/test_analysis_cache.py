@@ -7,10 +7,13 @@ from pathlib import Path
 from unittest.mock import patch
 
 from static_analyzer.analysis_cache import (
+    STATIC_ANALYSIS_HASHES,
     STATIC_ANALYSIS_PKL,
     STATIC_ANALYSIS_SHA,
     StaticAnalysisCache,
+    changed_by_content_hash,
     copy_cache_files,
+    file_content_hash,
 )
 from static_analyzer.analysis_result import StaticAnalysisResults
 from static_analyzer.constants import Language
@@ -314,6 +317,57 @@ class TestLoadWithSha(unittest.TestCase):
             self.assertEqual(cached_sha, "sha-A")
 
 
+class TestFileContentHashes(unittest.TestCase):
+    def setUp(self):
+        self.temp_dir = tempfile.mkdtemp()
+        self.repo_root = Path(self.temp_dir).resolve()
+        self.cache = StaticAnalysisCache(self.repo_root / CODEBOARDING_DIR_NAME, self.repo_root)
+        (self.repo_root / "src").mkdir()
+        self.main = self.repo_root / "src" / "main.py"
+        self.utils = self.repo_root / "src" / "utils.py"
+        self.main.write_text("def main(): ...\n")
+        self.utils.write_text("def helper(): ...\n")
+
+    def tearDown(self):
+        shutil.rmtree(self.temp_dir, ignore_errors=True)
+
+    def _save(self):
+        results = StaticAnalysisResults()
+        results.add_source_files(Language.PYTHON, [str(self.main), str(self.utils)])
+        self.cache.save(results, source_sha="abc")
+
+    def test_save_writes_repo_relative_manifest(self):
+        self._save()
+
+        manifest = (self.cache.artifact_dir / STATIC_ANALYSIS_HASHES).read_text()
+        self.assertIn('"version": 1', manifest)
+        self.assertIn('"src/main.py"', manifest)
+        self.assertEqual(self.cache.read_file_hashes()[str(self.main)], file_content_hash(self.main))
+
+    def test_other_version_is_ignored(self):
+        self._save()
+        (self.cache.artifact_dir / STATIC_ANALYSIS_HASHES).write_text('{"version": 0, "files": {}}')
+
+        self.assertIsNone(self.cache.read_file_hashes())
+
+    def test_changed_new_and_deleted_files(self):
+        self._save()
+        hashes = self.cache.read_file_hashes()
+        self.utils.write_text("def helper(): return 1\n")
+        added = self.repo_root / "src" / "new.py"
+        added.write_text("x = 1\n")
+        self.main.unlink()
+
+        changed = changed_by_content_hash(hashes, [self.utils, added])
+
+        self.assertEqual(changed, {self.utils, added, self.main})
+
+    def test_unchanged_tree_has_no_changes(self):
+        self._save()
+
+        self.assertEqual(changed_by_content_hash(self.cache.read_file_hashes(), [self.main, self.utils]), set())
+
+
 class TestCopyCacheFiles(unittest.TestCase):
     """``copy_cache_files`` atomically copies the pkl + sha pair between dirs."""
 
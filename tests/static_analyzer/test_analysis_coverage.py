@@ -0,0 +1,77 @@
+from pathlib import Path
+
+from static_analyzer.analysis_coverage import AnalysisCoverage, compute_analysis_coverage
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.lsp_client.diagnostics import LSPDiagnostic
+from static_analyzer.node import Node
+
+
+def _diagnostic(code: str, message: str, line: int, severity: int = 1) -> LSPDiagnostic:
+    return LSPDiagnostic.from_lsp_dict(
+        {"code": code, "message": message, "severity": severity, "range": {"start": {"line": line, "character": 4}}}
+    )
+
+
+def test_coverage_combines_resolved_call_sites_and_parsed_files(tmp_path: Path):
+    files = {name: str(tmp_path / name) for name in ("app.py", "broken.py", "empty.py", "consts.py")}
+    (tmp_path / "app.py").write_text("def run():\n    helper()\n    helper()\n    missing()\n\ndef helper():\n    pass\n")
+    (tmp_path / "broken.py").write_text("def oops(:\n")
+    (tmp_path / "empty.py").write_text("\n")
+    (tmp_path / "consts.py").write_text("# no symbols reported\n")
+
+    graph = CallGraph(language="python")
+    graph.add_node(Node("app.run", NodeType.FUNCTION, files["app.py"], 1, 4))
+    graph.add_node(Node("app.helper", NodeType.FUNCTION, files["app.py"], 6, 7))
+    graph.add_node(Node("broken.oops", NodeType.FUNCTION, files["broken.py"], 1, 1))
+    graph.add_edge(
+        "app.run",
+        "app.helper",
+        call_sites=[{"file": files["app.py"], "line": 2, "column": 5}, {"file": files["app.py"], "line": 3, "column": 5}],
+    )
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.PYTHON, graph)
+    results.add_source_files(Language.PYTHON, list(files.values()))
+    results.diagnostics = {
+        Language.PYTHON: {
+            files["app.py"]: [
+                _diagnostic("reportUndefinedVariable", '"missing" is not defined', 3),
+                # Published twice by the server; counted once.
+                _diagnostic("reportUndefinedVariable", '"missing" is not defined', 3),
+                _diagnostic("reportUnusedVariable", "x is not accessed", 1, severity=4),
+            ],
+            files["broken.py"]: [_diagnostic("", "Expected parameter name", 0)],
+            str(tmp_path / "elsewhere.py"): [_diagnostic("2304", "Cannot find name 'y'", 0)],
+        }
+    }
+
+    coverage = compute_analysis_coverage(results)
+
+    assert coverage == AnalysisCoverage(resolved_call_sites=2, unresolved_call_sites=1, parsed_files=2, source_files=4)
+    # 2/3 call sites x 2/4 files.
+    assert coverage.percent == 33.3
+    assert coverage.summary_line() == "Analysis coverage: 33.3% (call sites resolved 2/3, files parsed 2/4)"
+    assert AnalysisCoverage().percent == 100.0
+
+
+def test_timed_out_files_are_listed(tmp_path: Path):
+    results = StaticAnalysisResults()
+    results.add_source_files(Language.GO, [str(tmp_path / "main.go"), str(tmp_path / "slow.go")])
+    results.timed_out_files = {Language.GO: [str(tmp_path / "slow.go")]}
+
+    coverage = compute_analysis_coverage(results)
+
+    assert coverage.timed_out_files == (str(tmp_path / "slow.go"),)
+    assert coverage.summary_line().endswith(", 1 partially analyzed after LSP timeouts")
+
+
+def test_degraded_files_are_listed(tmp_path: Path):
+    results = StaticAnalysisResults()
+    results.add_source_files(Language.GO, [str(tmp_path / "main.go"), str(tmp_path / "broken.go")])
+    results.degraded_files = {Language.GO: [str(tmp_path / "broken.go")]}
+
+    coverage = compute_analysis_coverage(results)
+
+    assert coverage.degraded_files == (str(tmp_path / "broken.go"),)
+    assert coverage.summary_line().endswith(", 1 degraded (project did not build)")
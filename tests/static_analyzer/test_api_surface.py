@@ -0,0 +1,69 @@
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.api_surface import INTERNAL_VIA, apply_api_only
+from static_analyzer.constants import Language, NodeType, Visibility
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node, visibility
+
+FILE = "/repo/services/processor.go"
+
+
+def _results() -> StaticAnalysisResults:
+    cfg = CallGraph(language="go")
+    for line, (qname, node_type) in enumerate(
+        [
+            ("services.processor.Handle", NodeType.FUNCTION),
+            ("services.processor.validate", NodeType.FUNCTION),
+            ("services.processor.logf", NodeType.FUNCTION),
+            ("services.processor.(*Store).Save", NodeType.METHOD),
+            ("services.processor.(*entity).Dispose", NodeType.METHOD),
+            ("services.processor.taskHandlers.func1", NodeType.FUNCTION),
+        ],
+        start=1,
+    ):
+        cfg.add_node(Node(qname, node_type, FILE, line * 10, line * 10 + 5))
+    cfg.add_edge("services.processor.Handle", "services.processor.validate", [{"file": FILE, "line": 12}])
+    cfg.add_edge("services.processor.Handle", "services.processor.logf")
+    cfg.add_edge("services.processor.validate", "services.processor.logf")
+    cfg.add_edge("services.processor.validate", "services.processor.taskHandlers.func1")
+    cfg.add_edge("services.processor.taskHandlers.func1", "services.processor.(*Store).Save")
+    cfg.add_edge("services.processor.taskHandlers.func1", "services.processor.Handle")
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    results.add_references(
+        Language.GO,
+        [
+            Node("services.processor.entityCount", NodeType.VARIABLE, FILE, 3, 3),
+            Node("services.processor.(Store).Path", NodeType.FIELD, FILE, 4, 4),
+            Node("services.processor.(Store).disposed", NodeType.FIELD, FILE, 5, 5),
+        ],
+    )
+    return results
+
+
+def test_visibility_follows_the_language():
+    assert visibility("services.processor.(*Store).Save", FILE) == Visibility.EXPORTED
+    assert visibility("services.processor.(*entity).Dispose", FILE) == Visibility.UNEXPORTED
+    assert visibility("services.processor.Handle.func1", FILE) == Visibility.UNEXPORTED
+    assert visibility("shapes.Circle._scale", "shapes.py") == Visibility.UNEXPORTED
+    assert visibility("shapes.Circle.area", "shapes.py") == Visibility.EXPORTED
+
+
+def test_unexported_symbols_are_pruned_and_calls_through_them_collapsed():
+    results = _results()
+
+    api = apply_api_only(results)
+
+    cfg = api.get_cfg(Language.GO)
+    assert sorted(cfg.nodes) == ["services.processor.(*Store).Save", "services.processor.Handle"]
+    assert [(edge.get_source(), edge.get_destination(), edge.call_sites) for edge in cfg.edges] == [
+        (
+            "services.processor.Handle",
+            "services.processor.(*Store).Save",
+            [{"file": FILE, "line": 12, "via": INTERNAL_VIA}],
+        )
+    ]
+    assert [node.fully_qualified_name for node in api.iter_reference_nodes(Language.GO)] == [
+        "services.processor.(Store).Path"
+    ]
+    # The input is untouched: the static-analysis cache keeps every symbol.
+    assert len(results.get_cfg(Language.GO).nodes) == 6
@@ -62,6 +62,7 @@ def _make_adapter() -> MagicMock:
 def _make_lsp() -> MagicMock:
     lsp = MagicMock()
     lsp.document_symbol.return_value = []
+    lsp.send_document_symbol_batch.side_effect = lambda files, **kwargs: [[] for _ in files]
     lsp.send_references_batch.return_value = ([], set())
     lsp.type_hierarchy_prepare.return_value = None
     return lsp
@@ -120,6 +121,49 @@ class TestDiscoverSymbols:
         # for the first file, so no second call
         assert lsp.document_symbol.call_count == 1
 
+    def test_a_timed_out_file_is_skipped_and_the_rest_analyzed(self):
+        lsp = _make_lsp()
+        adapter = _make_adapter()
+        builder = CallGraphBuilder(lsp, adapter, Path("/project"), concurrency=1)
+        lsp.document_symbol.side_effect = [[], TimeoutError("hung"), []]
+
+        builder._discover_symbols([Path("/project/a.py"), Path("/project/slow.py"), Path("/project/c.py")])
+
+        assert lsp.document_symbol.call_count == 3
+
+    def test_symbol_queries_are_pipelined_up_to_the_concurrency(self):
+        lsp = _make_lsp()
+        adapter = _make_adapter()
+        adapter.normalize_document_symbols.side_effect = lambda symbols: symbols
+        builder = CallGraphBuilder(lsp, adapter, Path("/project"), concurrency=2)
+        files = [Path(f"/project/file_{i}.py") for i in range(6)]
+        lsp.send_document_symbol_batch.side_effect = lambda window, **kwargs: [
+            [
+                {
+                    "name": f"fn_{path.stem}",
+                    "kind": NodeType.FUNCTION,
+                    "range": {"start": {"line": 0, "character": 0}, "end": {"line": 1, "character": 0}},
+                    "selectionRange": {"start": {"line": 0, "character": 4}, "end": {"line": 0, "character": 9}},
+                }
+            ]
+            for path in window
+        ]
+
+        builder._discover_symbols(files)
+
+        # The probe answers file_0; the other five go out two at a time, the last alone.
+        windows = [call.args[0] for call in lsp.send_document_symbol_batch.call_args_list]
+        assert windows == [files[1:3], files[3:5]]
+        assert lsp.document_symbol.call_args_list[-1].args == (files[5],)
+        assert "file_1.fn_file_1" in builder.symbol_table.symbols
+        assert "file_4.fn_file_4" in builder.symbol_table.symbols
+
+    @patch("static_analyzer.engine.call_graph_builder.os.cpu_count", return_value=3)
+    def test_concurrency_defaults_to_the_cpu_count(self, _cpu_count):
+        builder = CallGraphBuilder(_make_lsp(), _make_adapter(), Path("/project"))
+
+        assert builder._concurrency == 3
+
     def test_empty_source_files(self):
         lsp = _make_lsp()
         adapter = _make_adapter()
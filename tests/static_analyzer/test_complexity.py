@@ -0,0 +1,90 @@
+from pathlib import Path
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileMethodGroup, MethodEntry
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.complexity import build_metrics_markdown, component_functions, compute_function_complexity
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+PYTHON_SOURCE = '''def simple():
+    return "if for while"  # if and or
+
+
+def branchy(items):
+    """Loops over items; if empty, bail."""
+    if not items or items is None:
+        return []
+    for item in items:
+        try:
+            yield [x for x in item if x]
+        except ValueError:
+            continue
+'''
+
+GO_SOURCE = """func handle(r Request) error {
+	// if for case
+	switch r.Kind {
+	case "a", "b":
+		return nil
+	}
+	if r.Body == nil && r.Len > 0 {
+		return errEmpty
+	}
+	return nil
+}
+"""
+
+
+def _static_analysis(tmp_path: Path) -> StaticAnalysisResults:
+    (tmp_path / "mod.py").write_text(PYTHON_SOURCE)
+    (tmp_path / "main.go").write_text(GO_SOURCE)
+    results = StaticAnalysisResults()
+    py_cfg = CallGraph(language="python")
+    py_cfg.add_node(Node("mod.simple", NodeType.FUNCTION, str(tmp_path / "mod.py"), 1, 2))
+    py_cfg.add_node(Node("mod.branchy", NodeType.FUNCTION, str(tmp_path / "mod.py"), 5, 13))
+    py_cfg.add_node(Node("mod.Thing", NodeType.CLASS, str(tmp_path / "mod.py"), 1, 13))
+    results.add_cfg(Language.PYTHON, py_cfg)
+    go_cfg = CallGraph(language="go")
+    go_cfg.add_node(Node("main.handle", NodeType.FUNCTION, str(tmp_path / "main.go"), 1, 11))
+    results.add_cfg(Language.GO, go_cfg)
+    return results
+
+
+def test_counts_decision_points_per_language_ignoring_comments_and_strings(tmp_path: Path):
+    complexities = compute_function_complexity(_static_analysis(tmp_path), tmp_path)
+
+    assert set(complexities) == {"mod.simple", "mod.branchy", "main.handle"}
+    assert complexities["mod.simple"].complexity == 1
+    # if, or, for, comprehension for + if, except
+    assert complexities["mod.branchy"].complexity == 7
+    # case, if, &&
+    assert complexities["main.handle"].complexity == 4
+    assert complexities["main.handle"].file_path == "main.go"
+
+
+def test_metrics_markdown_aggregates_per_component(tmp_path: Path):
+    complexities = compute_function_complexity(_static_analysis(tmp_path), tmp_path)
+    component = Component(
+        name="Core",
+        description="",
+        key_entities=[],
+        component_id="1",
+        file_methods=[
+            FileMethodGroup(
+                file_path="mod.py",
+                methods=[
+                    MethodEntry(qualified_name=name, start_line=1, end_line=2, node_type="FUNCTION")
+                    for name in ("mod.simple", "mod.branchy")
+                ],
+            )
+        ],
+    )
+
+    assert [f.qualified_name for f in component_functions(component, complexities)] == ["mod.branchy", "mod.simple"]
+    markdown = build_metrics_markdown(
+        AnalysisInsights(description="", components=[component], components_relations=[]), {}, complexities
+    )
+    assert "| 1 Core | 2 | 8 | 4.0 | 7 | `mod.branchy` |" in markdown
+    assert "| `main.handle` | main.go:1-11 | 4 | - |" in markdown
@@ -187,6 +187,28 @@ class TestBuildQualifiedName:
         )
         assert result == "Contoso.Api.Program"
 
+    def test_partial_class_parts_share_qualified_name(self):
+        """Members of ``User.Validation.cs`` belong to the same ``User`` as ``User.cs``."""
+        for file_name in ("User.cs", "User.Validation.cs", "User.g.cs"):
+            result = self.adapter.build_qualified_name(
+                file_path=Path(f"/repo/Models/{file_name}"),
+                symbol_name="Validate",
+                symbol_kind=NodeType.METHOD,
+                parent_chain=[("User", NodeType.CLASS)],
+                project_root=self.root,
+            )
+            assert result == "Models.User.Validate"
+
+    def test_dotted_file_name_kept_when_type_differs(self):
+        result = self.adapter.build_qualified_name(
+            file_path=Path("/repo/Models/User.Validation.cs"),
+            symbol_name="Check",
+            symbol_kind=NodeType.METHOD,
+            parent_chain=[("UserValidator", NodeType.CLASS)],
+            project_root=self.root,
+        )
+        assert result == "Models.User.Validation.UserValidator.Check"
+
     def test_src_prefix_stripped(self):
         """The 'src' directory is stripped from qualified names."""
         result = self.adapter.build_qualified_name(
@@ -390,6 +412,28 @@ class TestPrepareProject:
         CSharpAdapter().prepare_project(tmp_path)
         assert called["cmd"][2] == "Foo.sln"
 
+    def test_restores_each_project_under_a_shared_root(self, tmp_path, monkeypatch):
+        for name in ("Api", "Core"):
+            (tmp_path / name).mkdir()
+            (tmp_path / name / f"{name}.csproj").write_text("<Project />")
+
+        calls = []
+
+        def fake_run(cmd, **kwargs):
+            calls.append((cmd[2], kwargs.get("cwd")))
+            return MagicMock(returncode=0, stdout="", stderr="")
+
+        monkeypatch.setattr(
+            "static_analyzer.engine.adapters.csharp_adapter.subprocess.run",
+            fake_run,
+        )
+        monkeypatch.setattr(
+            "static_analyzer.engine.adapters.csharp_adapter.resolve_dotnet_sdk",
+            lambda _root: _dotnet_resolution(),
+        )
+        CSharpAdapter().prepare_project(tmp_path)
+        assert calls == [("Api.csproj", str(tmp_path / "Api")), ("Core.csproj", str(tmp_path / "Core"))]
+
     def test_skips_when_no_project_file(self, tmp_path, monkeypatch):
         def fake_run(*_args, **_kwargs):
             raise AssertionError("subprocess.run should not be called")
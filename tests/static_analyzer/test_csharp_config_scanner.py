@@ -101,6 +101,23 @@ class TestCSharpConfigScanner:
         types = {p.project_type for p in projects}
         assert types == {"solution", "project"}
 
+    def test_projects_referencing_each_other_share_a_root(self, tmp_path: Path):
+        """Projects linked by ``<ProjectReference>`` load in one csharp-ls, so cross-project calls resolve."""
+        api = tmp_path / "src" / "Api"
+        core = tmp_path / "src" / "Core"
+        tool = tmp_path / "tools" / "Migrator"
+        for directory in (api, core, tool):
+            directory.mkdir(parents=True)
+        (api / "Api.csproj").write_text(
+            '<Project><ItemGroup><ProjectReference Include="..\\Core\\Core.csproj" /></ItemGroup></Project>'
+        )
+        (core / "Core.csproj").write_text("<Project/>")
+        (tool / "Migrator.csproj").write_text("<Project/>")
+
+        roots = {p.root for p in CSharpConfigScanner(tmp_path).scan()}
+
+        assert roots == {tmp_path / "src", tool}
+
     def test_nested_solutions(self, tmp_path: Path):
         """Multiple solutions in different directories."""
         api_dir = tmp_path / "api"
@@ -0,0 +1,72 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.csharp_extensions import add_extension_edges, extended_type
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+EXTENSIONS_CS = """namespace Shop.Orders;
+
+public static class OrderExtensions
+{
+    public static decimal Total(this Order order) => order.Lines.Sum(l => l.Price);
+
+    public static async Task<Order> ReloadAsync(
+        [NotNull] this Shop.Orders.Order order,
+        IOrderRepo repo)
+    {
+        return await repo.GetAsync(order.Id);
+    }
+
+    public static string Slug(this string value) => value.ToLowerInvariant();
+
+    public static decimal Sum(this IEnumerable<Order> orders) => orders.Sum(o => o.Total());
+
+    public static decimal Helper(Order order) { return order.Total(); }
+}
+"""
+
+
+def test_extended_type_reads_the_this_parameter():
+    lines = EXTENSIONS_CS.splitlines()
+
+    def at(text: str) -> str | None:
+        return extended_type(lines, next(i for i, line in enumerate(lines, start=1) if text in line))
+
+    assert at("Total(this Order") == "Order"
+    assert at("ReloadAsync(") == "Order"
+    assert at("Slug(") == "string"
+    assert at("Sum(this") == "IEnumerable"
+    assert at("Helper(") is None
+
+
+def test_extension_methods_are_contained_by_the_type_they_extend(tmp_path: Path):
+    source = tmp_path / "Orders" / "OrderExtensions.cs"
+    source.parent.mkdir()
+    source.write_text(EXTENSIONS_CS)
+    lines = EXTENSIONS_CS.splitlines()
+
+    def method(name: str) -> Node:
+        line = next(i for i, text in enumerate(lines, start=1) if f" {name}(" in text)
+        return Node(f"Orders.OrderExtensions.{name}", NodeType.METHOD, str(source), line, line)
+
+    cfg = CallGraph(language="csharp")
+    for name in ("Total", "ReloadAsync", "Slug", "Helper"):
+        cfg.add_node(method(name))
+    order = Node("Orders.Order", NodeType.CLASS, str(tmp_path / "Orders" / "Order.cs"), 3, 10)
+
+    added = add_extension_edges(cfg, [order])
+
+    contains = str(EdgeKind.CONTAINS)
+    assert added == [
+        ("Orders.Order", "Orders.OrderExtensions.Total", contains),
+        ("Orders.Order", "Orders.OrderExtensions.ReloadAsync", contains),
+    ]
+    assert "Orders.Order" in cfg.nodes
+    assert add_extension_edges(cfg, [order]) == []
+
+    # Two project types named ``Order``: the extension is not attributed to either.
+    other = Node("Billing.Order", NodeType.CLASS, str(tmp_path / "Billing" / "Order.cs"), 1, 5)
+    fresh = CallGraph(language="csharp")
+    fresh.add_node(method("Total"))
+    assert add_extension_edges(fresh, [order, other]) == []
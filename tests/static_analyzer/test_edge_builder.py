@@ -6,6 +6,7 @@ from unittest.mock import MagicMock
 from static_analyzer.engine.edge_builder import (
     EdgeMap,
     _best_candidate,
+    _is_self_call,
     _is_valid_edge,
     _process_references_for_position,
     _resolve_definition_to_symbol,
@@ -14,7 +15,7 @@ from static_analyzer.engine.edge_builder import (
 )
 from static_analyzer.constants import NodeType
 from static_analyzer.engine.edge_build_context import EdgeBuildContext
-from static_analyzer.engine.models import SymbolInfo
+from static_analyzer.engine.models import CallSite, SymbolInfo
 from static_analyzer.engine.source_inspector import SourceInspector
 from static_analyzer.engine.symbol_table import SymbolTable
 
@@ -254,6 +255,113 @@ def test_callback_reference_in_multiline_body_is_an_edge(tmp_path: Path):
     assert (caller.qualified_name, target.qualified_name) in edge_set
 
 
+def test_calls_in_defer_and_go_statements_are_edges(tmp_path: Path):
+    source = tmp_path / "tasks.go"
+    source.write_text(
+        "package tasks\n\nfunc DescribeTask(t Task) {\n\tdefer fmt.Println(format(t))\n\tgo worker(t)\n}\n"
+    )
+    target_file = tmp_path / "helpers.go"
+    target_file.write_text("package tasks\n\nfunc format(t Task) string {}\n\nfunc worker(t Task) {}\n")
+
+    ctx, adapter = _make_ctx()
+    caller = _sym("DescribeTask", "tasks.DescribeTask", NodeType.FUNCTION, str(source), 2, 5, 5, 1)
+    format_target = _sym("format", "tasks.format", NodeType.FUNCTION, str(target_file), 2, 5, 2, 11)
+    worker_target = _sym("worker", "tasks.worker", NodeType.FUNCTION, str(target_file), 4, 5, 4, 11)
+    ctx.symbol_table.file_symbols[str(source)] = [caller]
+    lines = source.read_text().splitlines()
+    edge_set: EdgeMap = {}
+
+    for target, line in ((format_target, 3), (worker_target, 4)):
+        start = lines[line].index(target.name)
+        reference = {
+            "uri": source.as_uri(),
+            "range": {
+                "start": {"line": line, "character": start},
+                "end": {"line": line, "character": start + len(target.name)},
+            },
+        }
+        _process_references_for_position(adapter, ctx, [target], [reference], edge_set)
+
+    assert (caller.qualified_name, format_target.qualified_name) in edge_set
+    assert (caller.qualified_name, worker_target.qualified_name) in edge_set
+
+
+def test_unpacked_and_discarded_call_results_are_one_edge_each(tmp_path: Path):
+    source = tmp_path / "main.go"
+    source.write_text(
+        "package main\n\nfunc runTasks(t2 *Task) {\n"
+        "\tname, prio := services.GetTaskInfo(t2)\n\t_ = services.Foo()\n\tfmt.Println(name, prio)\n}\n"
+    )
+    target_file = tmp_path / "services.go"
+    target_file.write_text(
+        "package services\n\nfunc (t *Task) GetTaskInfo() (string, int) {}\n\nfunc Foo() error {}\n"
+    )
+
+    ctx, adapter = _make_ctx()
+    caller = _sym("runTasks", "main.runTasks", NodeType.FUNCTION, str(source), 2, 5, 6, 1)
+    info = _sym("GetTaskInfo", "services.(*Task).GetTaskInfo", NodeType.METHOD, str(target_file), 2, 15, 2, 26)
+    foo = _sym("Foo", "services.Foo", NodeType.FUNCTION, str(target_file), 4, 5, 4, 8)
+    ctx.symbol_table.file_symbols[str(source)] = [caller]
+    lines = source.read_text().splitlines()
+    edge_set: EdgeMap = {}
+
+    for target, line in ((info, 3), (foo, 4)):
+        start = lines[line].index(target.name)
+        reference = {
+            "uri": source.as_uri(),
+            "range": {
+                "start": {"line": line, "character": start},
+                "end": {"line": line, "character": start + len(target.name)},
+            },
+        }
+        _process_references_for_position(adapter, ctx, [target], [reference], edge_set)
+
+    assert sorted(edge_set) == [
+        (caller.qualified_name, info.qualified_name),
+        (caller.qualified_name, foo.qualified_name),
+    ]
+    assert len(edge_set[(caller.qualified_name, info.qualified_name)]) == 1
+
+
+def test_calls_inside_function_literals_belong_to_the_enclosing_function(tmp_path: Path):
+    source = tmp_path / "events.go"
+    source.write_text(
+        "package events\n\n"
+        "func SetupEventProcessing(emitter *Emitter) {\n"
+        "\temitter.On(\"data\", func(data interface{}) {\n"
+        "\t\thandle(data)\n"
+        "\t})\n"
+        "}\n\n"
+        "func CreateMultiplier(factor int) func(int) int { return func(x int) int { return scale(x, factor) } }\n"
+    )
+    target_file = tmp_path / "helpers.go"
+    target_file.write_text("package events\n\nfunc handle(data interface{}) {}\n\nfunc scale(x, f int) int {}\n")
+
+    ctx, adapter = _make_ctx()
+    setup = _sym("SetupEventProcessing", "events.SetupEventProcessing", NodeType.FUNCTION, str(source), 2, 5, 6, 1)
+    multiplier = _sym("CreateMultiplier", "events.CreateMultiplier", NodeType.FUNCTION, str(source), 8, 5, 8, 102)
+    handle = _sym("handle", "events.handle", NodeType.FUNCTION, str(target_file), 2, 5, 2, 11)
+    scale = _sym("scale", "events.scale", NodeType.FUNCTION, str(target_file), 4, 5, 4, 10)
+    ctx.symbol_table.file_symbols[str(source)] = [setup, multiplier]
+    lines = source.read_text().splitlines()
+    edge_set: EdgeMap = {}
+
+    for target, line in ((handle, 4), (scale, 8)):
+        start = lines[line].index(target.name + "(")
+        reference = {
+            "uri": source.as_uri(),
+            "range": {
+                "start": {"line": line, "character": start},
+                "end": {"line": line, "character": start + len(target.name)},
+            },
+        }
+        _process_references_for_position(adapter, ctx, [target], [reference], edge_set)
+
+    assert (setup.qualified_name, handle.qualified_name) in edge_set
+    # On the declaration line the literal's block still counts as the function's body.
+    assert (multiplier.qualified_name, scale.qualified_name) in edge_set
+
+
 def test_non_call_reference_in_expression_body_is_not_an_edge(tmp_path: Path):
     source = tmp_path / "Caller.cs"
     source.write_text("public static Func<string> Caller() => Target;\n")
@@ -280,6 +388,31 @@ def test_non_call_reference_in_expression_body_is_not_an_edge(tmp_path: Path):
     assert edge_set == {}
 
 
+def test_recursive_call_is_a_self_edge(tmp_path: Path):
+    source = tmp_path / "tree.go"
+    source.write_text(
+        "package tree\n\nfunc Walk(n *Node) {\n\tfor _, c := range n.Children {\n\t\tWalk(c)\n\t}\n"
+        "\tregister(Walk)\n}\n"
+    )
+
+    ctx, adapter = _make_ctx()
+    walk = _sym("Walk", "tree.Walk", NodeType.FUNCTION, str(source), 2, 5, 7, 1)
+    ctx.symbol_table.file_symbols[str(source)] = [walk]
+    edge_set: EdgeMap = {}
+    references = [
+        {
+            "uri": source.as_uri(),
+            "range": {"start": {"line": line, "character": start}, "end": {"line": line, "character": start + 4}},
+        }
+        for line, start in ((2, 5), (4, 2), (6, 10))
+    ]
+
+    _process_references_for_position(adapter, ctx, [walk], references, edge_set)
+
+    # The declaration and the function passed as a value are not calls.
+    assert edge_set == {("tree.Walk", "tree.Walk"): [CallSite(str(source), 5, 3)]}
+
+
 # ---------------------------------------------------------------------------
 # _is_valid_edge
 # ---------------------------------------------------------------------------
@@ -316,6 +449,23 @@ class TestIsValidEdge:
         assert _is_valid_edge(a, b) is False
 
 
+class TestIsSelfCall:
+    def test_call_in_the_body_is_recursion(self):
+        walk = _sym("walk", "a.walk", NodeType.FUNCTION, "/p/a.py", 0, 4)
+        assert _is_self_call(_TestAdapter(), walk, walk, CallSite("/p/a.py", 3, 9)) is True
+
+    def test_rejects_the_declaration_line(self):
+        walk = _sym("walk", "a.walk", NodeType.FUNCTION, "/p/a.py", 0, 4)
+        assert _is_self_call(_TestAdapter(), walk, walk, CallSite("/p/a.py", 1, 5)) is False
+
+    def test_rejects_other_targets_and_classes(self):
+        walk = _sym("walk", "a.walk", NodeType.FUNCTION, "/p/a.py", 0, 4)
+        other = _sym("other", "a.other", NodeType.FUNCTION, "/p/a.py", 10)
+        cls = _sym("Tree", "a.Tree", NodeType.CLASS, "/p/a.py", 20)
+        assert _is_self_call(_TestAdapter(), walk, other, CallSite("/p/a.py", 3, 9)) is False
+        assert _is_self_call(_TestAdapter(), cls, cls, CallSite("/p/a.py", 25, 9)) is False
+
+
 # ---------------------------------------------------------------------------
 # _resolve_definition_to_symbol
 # ---------------------------------------------------------------------------
@@ -0,0 +1,117 @@
+"""Tests for the Elixir language adapter."""
+
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.engine.adapters import get_adapter
+from static_analyzer.engine.adapters.elixir_adapter import ElixirAdapter, function_signature
+from static_analyzer.engine.symbol_table import SymbolTable
+
+ROOT = Path("/tmp/elixir_project")
+ACCOUNTS = ROOT / "lib" / "my_app" / "accounts.ex"
+
+
+def _symbol(name: str, kind: int, start: int, end: int, children: list[dict] | None = None) -> dict:
+    symbol = {
+        "name": name,
+        "kind": kind,
+        "range": {"start": {"line": start, "character": 2}, "end": {"line": end, "character": 5}},
+        "selectionRange": {"start": {"line": start, "character": 6}, "end": {"line": start, "character": 10}},
+    }
+    if children is not None:
+        symbol["children"] = children
+    return symbol
+
+
+class TestElixirAdapterProperties:
+    def test_registered_with_elixir_defaults(self):
+        adapter = get_adapter("Elixir")
+        assert isinstance(adapter, ElixirAdapter)
+        assert adapter.language_enum is Language.ELIXIR
+        assert adapter.file_extensions == (".ex", ".exs")
+        assert adapter.language_id == "elixir"
+        assert adapter.line_comment_prefix == "#"
+
+    def test_modules_are_class_like(self):
+        adapter = ElixirAdapter()
+        assert adapter.is_class_like(NodeType.MODULE)
+        assert adapter.is_class_like(NodeType.STRUCT)
+        assert not adapter.is_class_like(NodeType.FUNCTION)
+
+    def test_missing_elixir_fails_fast(self):
+        with patch("static_analyzer.engine.adapters.elixir_adapter.shutil.which", return_value=None):
+            with pytest.raises(RuntimeError, match="elixir not found"):
+                ElixirAdapter().get_lsp_command(ROOT)
+
+
+@pytest.mark.parametrize(
+    "label, expected",
+    [
+        ("def fetch(id, opts \\\\ [])", "fetch/2"),
+        ("defp parse(%{a: 1, b: 2} = map, [head | tail], {x, y})", "parse/3"),
+        ("def valid?(x) when is_binary(x) and byte_size(x) > 0", "valid?/1"),
+        ("defmacro now", "now/0"),
+        ("def run()", "run/0"),
+        ("handle_call/3", "handle_call/3"),
+        ("MyApp.Accounts", None),
+        ("@moduledoc", None),
+    ],
+)
+def test_function_signature(label: str, expected: str | None):
+    assert function_signature(label) == expected
+
+
+def test_clauses_merge_into_one_symbol_per_arity():
+    symbols = [
+        _symbol(
+            "MyApp.Accounts",
+            NodeType.MODULE,
+            0,
+            30,
+            [
+                _symbol("def fetch(:admin)", NodeType.FUNCTION, 2, 3),
+                _symbol("def fetch(id) when is_integer(id)", NodeType.FUNCTION, 5, 8),
+                _symbol("def fetch(id, opts)", NodeType.FUNCTION, 10, 12),
+                _symbol("def fetch(id)", NodeType.FUNCTION, 14, 15),
+            ],
+        ),
+        _symbol("String.Chars, for: MyApp.User", NodeType.MODULE, 32, 36),
+    ]
+
+    normalized = ElixirAdapter().normalize_document_symbols(symbols)
+
+    functions = normalized[0]["children"]
+    assert [f["name"] for f in functions] == ["fetch/1", "fetch/2"]
+    assert functions[0]["range"]["start"]["line"] == 2 and functions[0]["range"]["end"]["line"] == 15
+    assert functions[0]["selectionRange"]["start"]["line"] == 2
+    assert normalized[1]["name"] == "String.Chars.MyApp.User"
+    # The server's response is left untouched.
+    assert symbols[0]["children"][0]["name"] == "def fetch(:admin)"
+    assert symbols[0]["children"][1]["range"]["end"]["line"] == 8
+
+
+def test_qualified_names_follow_module_names():
+    adapter = ElixirAdapter()
+    table = SymbolTable(adapter)
+    symbols = adapter.normalize_document_symbols(
+        [
+            _symbol(
+                "MyApp.Accounts",
+                NodeType.MODULE,
+                0,
+                20,
+                [
+                    _symbol("Cache", NodeType.MODULE, 2, 6, [_symbol("def get(key)", NodeType.FUNCTION, 3, 5)]),
+                    _symbol("defp normalize(email)", NodeType.FUNCTION, 8, 10),
+                ],
+            )
+        ]
+    )
+
+    table.register_symbols(ACCOUNTS, symbols, parent_chain=[], project_root=ROOT)
+
+    assert {"MyApp.Accounts", "MyApp.Accounts.Cache", "MyApp.Accounts.Cache.get/1"} <= set(table.symbols)
+    assert table.symbols["MyApp.Accounts.normalize/1"].kind == NodeType.FUNCTION
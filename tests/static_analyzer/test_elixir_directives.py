@@ -0,0 +1,86 @@
+from pathlib import Path
+
+from agents.agent_responses import SourceCodeReference
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.elixir_directives import (
+    DirectiveKind,
+    ElixirDirective,
+    behaviours,
+    expand_alias,
+    module_directives,
+    parse_directives,
+)
+from static_analyzer.node import Node
+from static_analyzer.reference_resolver import StaticReferenceResolver
+
+WORKER = """defmodule MyApp.Worker do
+  # alias Commented.Out
+  use GenServer
+  alias MyApp.{Accounts, Repo}
+  alias MyApp.Storage.Local, as: Store
+  import MyApp.Mailer
+  @behaviour MyApp.Storage
+  @moduledoc \"\"\"
+  alias Documented.Only
+  \"\"\"
+
+  defmodule Cache do
+    @behaviour Store
+  end
+end
+"""
+
+
+def test_parse_directives_reads_every_form_with_its_line():
+    assert parse_directives(WORKER) == [
+        ElixirDirective(DirectiveKind.USE, "GenServer", 2),
+        ElixirDirective(DirectiveKind.ALIAS, "MyApp.Accounts", 3, "Accounts"),
+        ElixirDirective(DirectiveKind.ALIAS, "MyApp.Repo", 3, "Repo"),
+        ElixirDirective(DirectiveKind.ALIAS, "MyApp.Storage.Local", 4, "Store"),
+        ElixirDirective(DirectiveKind.IMPORT, "MyApp.Mailer", 5),
+        ElixirDirective(DirectiveKind.BEHAVIOUR, "MyApp.Storage", 6),
+        ElixirDirective(DirectiveKind.BEHAVIOUR, "Store", 12),
+    ]
+
+
+def test_behaviours_go_to_the_innermost_module_through_the_file_aliases():
+    directives = parse_directives(WORKER)
+    by_module = module_directives(directives, [("MyApp.Worker", 0, 14), ("MyApp.Worker.Cache", 11, 13)])
+
+    assert behaviours(by_module["MyApp.Worker"], "MyApp.Worker", directives) == ["GenServer", "MyApp.Storage"]
+    assert behaviours(by_module["MyApp.Worker.Cache"], "MyApp.Worker.Cache", directives) == ["MyApp.Storage.Local"]
+    assert expand_alias("Accounts.create_user/2", directives) == "MyApp.Accounts.create_user/2"
+    assert expand_alias("Mailer.send/1", directives) == "Mailer.send/1"
+
+
+def test_aliased_imported_and_arityless_names_resolve(tmp_path: Path):
+    worker = tmp_path / "lib" / "my_app" / "worker.ex"
+    worker.parent.mkdir(parents=True)
+    worker.write_text(WORKER)
+    results = StaticAnalysisResults()
+    results.add_references(
+        Language.ELIXIR,
+        [
+            Node("MyApp.Accounts.create_user/2", NodeType.FUNCTION, str(tmp_path / "lib/my_app/accounts.ex"), 3, 6),
+            Node("MyApp.Accounts.get/1", NodeType.FUNCTION, str(tmp_path / "lib/my_app/accounts.ex"), 8, 9),
+            Node("MyApp.Accounts.get/2", NodeType.FUNCTION, str(tmp_path / "lib/my_app/accounts.ex"), 11, 12),
+            Node("MyApp.Mailer.deliver/1", NodeType.FUNCTION, str(tmp_path / "lib/my_app/mailer.ex"), 2, 4),
+            Node("MyApp.Notifier.deliver/1", NodeType.FUNCTION, str(tmp_path / "lib/my_app/notifier.ex"), 2, 4),
+            Node("MyApp.Storage.Local.put/2", NodeType.FUNCTION, str(tmp_path / "lib/my_app/local.ex"), 5, 7),
+        ],
+    )
+    results.add_source_files(Language.ELIXIR, [str(worker)])
+    resolver = StaticReferenceResolver(tmp_path, results)
+
+    def resolved(qname: str) -> str | None:
+        node = resolver.resolve_node(SourceCodeReference(qualified_name=qname))
+        return node.fully_qualified_name if node is not None else None
+
+    assert resolved("Accounts.create_user/2") == "MyApp.Accounts.create_user/2"
+    assert resolved("MyApp.Accounts.create_user") == "MyApp.Accounts.create_user/2"
+    assert resolved("Store.put/2") == "MyApp.Storage.Local.put/2"
+    # Only the imported module's ``deliver/1`` is in scope unqualified.
+    assert resolved("deliver/1") == "MyApp.Mailer.deliver/1"
+    # Both arities of ``get`` match; the name alone does not say which.
+    assert resolved("Accounts.get") is None
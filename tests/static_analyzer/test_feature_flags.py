@@ -0,0 +1,111 @@
+from pathlib import Path
+
+import pytest
+
+from agents.agent_responses import AnalysisInsights, Relation, RelationCallSite, RelationEdge, SourceCodeReference
+from diagram_analysis.analysis_json import _relation_edge_to_json
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.feature_flags import (
+    FlagBlock,
+    FlagSettingError,
+    apply_flag_settings,
+    compile_flag_patterns,
+    find_flag_blocks,
+    parse_flag_setting,
+    tag_flag_guarded_edges,
+)
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+CHECKOUT_GO = """\
+package shop
+
+func Run() {
+	if flags.Enabled("new_checkout") {
+		NewCheckout()
+	} else {
+		LegacyCheckout()
+	}
+	Audit()
+}
+"""
+
+REPORTS_PY = """\
+def run():
+    if not is_enabled('beta'):
+        old_path()
+    else:
+        new_path()
+    if is_enabled("beta") or force:
+        forced()
+    audit()
+"""
+
+PATTERNS = compile_flag_patterns(['flags.Enabled("{flag}")', "is_enabled('{flag}')"])
+
+
+def test_flag_blocks_cover_if_and_else_with_opposite_states():
+    assert find_flag_blocks(CHECKOUT_GO, ".go", PATTERNS) == [
+        FlagBlock("new_checkout", True, 4, 6),
+        FlagBlock("new_checkout", False, 6, 8),
+    ]
+    # The ``or`` condition doesn't guard its block.
+    assert find_flag_blocks(REPORTS_PY, ".py", PATTERNS) == [
+        FlagBlock("beta", False, 3, 3),
+        FlagBlock("beta", True, 5, 5),
+    ]
+    assert parse_flag_setting("new_checkout=OFF") == ("new_checkout", False)
+    with pytest.raises(FlagSettingError):
+        parse_flag_setting("new_checkout")
+
+
+def _results(repo: Path) -> StaticAnalysisResults:
+    source = str(repo / "checkout.go")
+    graph = CallGraph(language="go")
+    for line, qname in enumerate(["shop.Run", "shop.NewCheckout", "shop.LegacyCheckout", "shop.Audit"], start=1):
+        graph.add_node(Node(qname, NodeType.FUNCTION, source, line * 20, line * 20 + 5))
+    for target, line in [("shop.NewCheckout", 5), ("shop.LegacyCheckout", 7), ("shop.Audit", 9)]:
+        graph.add_edge("shop.Run", target, call_sites=[{"file": source, "line": line, "column": 3}])
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, graph)
+    return results
+
+
+def test_flag_setting_drops_calls_guarded_by_the_other_state(tmp_path: Path):
+    (tmp_path / "checkout.go").write_text(CHECKOUT_GO)
+    results = _results(tmp_path)
+
+    narrowed = apply_flag_settings(results, {"new_checkout": False}, PATTERNS)
+
+    assert {edge.get_destination() for edge in narrowed.get_cfg(Language.GO).edges} == {
+        "shop.LegacyCheckout",
+        "shop.Audit",
+    }
+    assert len(results.get_cfg(Language.GO).edges) == 3
+    assert len(apply_flag_settings(results, {"other": True}, PATTERNS).get_cfg(Language.GO).edges) == 3
+
+
+def test_relation_edges_are_tagged_with_their_guarding_flags(tmp_path: Path):
+    (tmp_path / "checkout.go").write_text(CHECKOUT_GO)
+
+    def edge(target: str, *lines: int) -> RelationEdge:
+        return RelationEdge(
+            source=SourceCodeReference(qualified_name="shop.Run", reference_file="checkout.go"),
+            target=SourceCodeReference(qualified_name=target, reference_file="checkout.go"),
+            call_sites=[RelationCallSite(line=line, column=3) for line in lines],
+        )
+
+    edges = [edge("shop.NewCheckout", 5), edge("shop.LegacyCheckout", 7), edge("shop.Audit", 9), edge("shop.X", 5, 7)]
+    analysis = AnalysisInsights(
+        description="",
+        components=[],
+        components_relations=[Relation(relation="calls", src_name="Shop", dst_name="Checkout", all_edges=edges)],
+    )
+
+    tag_flag_guarded_edges(tmp_path, analysis, {}, PATTERNS)
+
+    assert [e.flags for e in edges] == [{"new_checkout": "on"}, {"new_checkout": "off"}, {}, {}]
+    as_json = [_relation_edge_to_json(e, tmp_path).model_dump(exclude_none=True) for e in edges[:3]]
+    assert [e.get("flags") for e in as_json] == [{"new_checkout": "on"}, {"new_checkout": "off"}, None]
+    assert RelationEdge.from_dict(as_json[0], {}).flags == {"new_checkout": "on"}
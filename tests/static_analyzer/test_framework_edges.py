@@ -0,0 +1,127 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.framework_edges import Framework, add_framework_edges
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+NEST_CONTROLLER = """
+import { Controller, Get, Inject, forwardRef } from '@nestjs/common';
+
+@Controller('users')
+@UseGuards(AuthGuard('jwt'))
+export class UsersController {
+  constructor(
+    private readonly usersService: UsersService,
+    @Inject(forwardRef(() => AuditService)) private audit,
+  ) {}
+
+  @Get(':id')
+  findOne(@Param('id') id: string) {
+    return this.usersService.find(id);
+  }
+}
+"""
+
+NEST_MODULE = """
+@Module({
+  imports: [TypeOrmModule.forFeature([User]), AuditModule],
+  controllers: [UsersController],
+  providers: [UsersService, { provide: 'X', useClass: Other }],
+})
+export class UsersModule {}
+"""
+
+ANGULAR_MODULE = """
+const routes: Routes = [
+  { path: '', component: HomeComponent },
+  { path: 'admin', loadComponent: () => import('./admin').then((m) => m.AdminComponent) },
+];
+
+@NgModule({
+  declarations: [HomeComponent],
+  imports: [RouterModule.forRoot(routes)],
+})
+export class AppRoutingModule {}
+
+@Component({ selector: 'app-home', template: '' })
+export class HomeComponent {
+  private api = inject(ApiService);
+}
+"""
+
+
+def _graph(tmp_path: Path, files: dict[str, str], classes: dict[str, str]) -> tuple[CallGraph, list[str]]:
+    paths = []
+    for name, text in files.items():
+        path = tmp_path / name
+        path.write_text(text)
+        paths.append(str(path))
+    graph = CallGraph(language="typescript")
+    for i, (qname, file_name) in enumerate(classes.items()):
+        graph.add_node(Node(qname, NodeType.CLASS, str(tmp_path / file_name), i * 10, i * 10 + 5))
+    return graph, paths
+
+
+def _edges(graph: CallGraph) -> set[tuple[str, str]]:
+    return {(e.get_source().rsplit(".", 1)[-1], e.get_destination().rsplit(".", 1)[-1]) for e in graph.edges}
+
+
+def test_nest_injection_and_module_registration(tmp_path: Path):
+    graph, files = _graph(
+        tmp_path,
+        {"users.controller.ts": NEST_CONTROLLER, "users.module.ts": NEST_MODULE},
+        {
+            "users.users_controller.UsersController": "users.controller.ts",
+            "users.users_module.UsersModule": "users.module.ts",
+            "users.users_service.UsersService": "users.service.ts",
+            "audit.audit_service.AuditService": "audit.service.ts",
+            "audit.audit_module.AuditModule": "audit.module.ts",
+        },
+    )
+
+    added = add_framework_edges(graph, files, Framework.NEST)
+
+    assert _edges(graph) == {
+        ("UsersController", "UsersService"),
+        ("UsersController", "AuditService"),
+        ("UsersModule", "UsersController"),
+        ("UsersModule", "UsersService"),
+        ("UsersModule", "AuditModule"),
+    }
+    assert added == 5
+
+
+def test_angular_routes_declarations_and_inject(tmp_path: Path):
+    graph, files = _graph(
+        tmp_path,
+        {"app.ts": ANGULAR_MODULE},
+        {
+            "app.AppRoutingModule": "app.ts",
+            "app.HomeComponent": "app.ts",
+            "admin.AdminComponent": "admin.ts",
+            "api.ApiService": "api.ts",
+        },
+    )
+
+    add_framework_edges(graph, files, Framework.ANGULAR)
+
+    assert _edges(graph) == {
+        ("AppRoutingModule", "HomeComponent"),
+        ("AppRoutingModule", "AdminComponent"),
+        ("HomeComponent", "ApiService"),
+    }
+
+
+def test_ambiguous_class_names_are_skipped(tmp_path: Path):
+    graph, files = _graph(
+        tmp_path,
+        {"users.controller.ts": NEST_CONTROLLER},
+        {
+            "users.UsersController": "users.controller.ts",
+            "a.UsersService": "a.ts",
+            "b.UsersService": "b.ts",
+        },
+    )
+
+    assert add_framework_edges(graph, files, Framework.NEST) == 0
@@ -53,7 +53,7 @@ from static_analyzer.cluster_relations import (
     build_global_relations,
 )
 from static_analyzer.constants import NodeType
-from static_analyzer.graph import CallGraph, Edge
+from static_analyzer.graph import CallGraph, Edge, EdgeKind
 from static_analyzer.node import Node
 
 
@@ -726,5 +726,57 @@ class TestLlmOnlyRelations(unittest.TestCase):
         self.assertNotIn(("1", "9"), {(r.src_id, r.dst_id) for r in rels})
 
 
+class TestImplementsRelations(unittest.TestCase):
+    """IMPLEMENTS reference edges become their own relations, separate from calls."""
+
+    def _analysis(self) -> AnalysisInsights:
+        root = AnalysisInsights(
+            description="root",
+            components=[
+                _comp("Animals", [("zoo.Dog", "zoo/dog.go"), ("zoo.Duck", "zoo/duck.go")]),
+                _comp("Contracts", [("api.Speaker", "api/speaker.go"), ("api.Swimmer", "api/swimmer.go")]),
+            ],
+            components_relations=[],
+        )
+        assign_component_ids(root)
+        return root
+
+    def _cfg(self, with_call: bool = False) -> CallGraph:
+        cfg = CallGraph()
+        for name, path in (
+            ("zoo.Dog", "zoo/dog.go"),
+            ("zoo.Duck", "zoo/duck.go"),
+            ("api.Speaker", "api/speaker.go"),
+            ("api.Swimmer", "api/swimmer.go"),
+        ):
+            cfg.add_node(Node(name, NodeType.STRUCT, path, 1, 10))
+        cfg.add_reference_edge("zoo.Dog", "api.Speaker", EdgeKind.IMPLEMENTS)
+        cfg.add_reference_edge("zoo.Duck", "api.Speaker", EdgeKind.IMPLEMENTS)
+        cfg.add_reference_edge("zoo.Duck", "api.Swimmer", EdgeKind.IMPLEMENTS)
+        if with_call:
+            cfg.add_edge("zoo.Dog", "api.Speaker")
+        return cfg
+
+    def test_interface_satisfaction_becomes_implements_relation(self):
+        rels = build_global_relations(self._analysis(), {}, {"go": self._cfg()})
+
+        self.assertEqual(len(rels), 1)
+        relation = rels[0]
+        self.assertTrue(relation.implements)
+        self.assertEqual(relation.relation, "implements")
+        self.assertEqual((relation.src_id, relation.dst_id), ("1", "2"))
+        targets = {(edge.source.qualified_name, edge.target.qualified_name) for edge in relation.all_edges}
+        self.assertEqual(
+            targets, {("zoo.Dog", "api.Speaker"), ("zoo.Duck", "api.Speaker"), ("zoo.Duck", "api.Swimmer")}
+        )
+
+    def test_kept_apart_from_call_relation_between_same_pair(self):
+        rels = build_global_relations(self._analysis(), {}, {"go": self._cfg(with_call=True)})
+
+        self.assertEqual([(r.src_id, r.dst_id, r.implements) for r in rels], [("1", "2", False), ("1", "2", True)])
+        self.assertEqual(rels[0].relation, "calls")
+        self.assertNotEqual(rels[0].pair_key(), rels[1].pair_key())
+
+
 if __name__ == "__main__":
     unittest.main()
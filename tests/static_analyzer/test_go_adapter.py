@@ -6,7 +6,10 @@ from unittest.mock import MagicMock, patch
 
 import pytest
 
+from agents.file_index_models import MethodEntry
+from static_analyzer.constants import NodeType, ReceiverKind
 from static_analyzer.engine.adapters.go_adapter import GoAdapter, _directory_filters_from_ignore_manager
+from static_analyzer.node import Node
 from repo_utils.ignore import RepoIgnoreManager
 from utils import CODEBOARDING_DIR_NAME
 
@@ -168,3 +171,72 @@ class TestGoplsConfiguration:
         filters = _directory_filters_from_ignore_manager(ignore_manager)
         vendor_entries = [f for f in filters if "vendor" in f]
         assert len(vendor_entries) == 1
+
+
+class TestReceiverKind:
+    """Value and pointer receivers stay distinct from the qualified name to the method index."""
+
+    def _node(self, tmp_path: Path, name: str, detail: str, parent_chain: list) -> Node:
+        qname = GoAdapter().build_qualified_name(
+            tmp_path / "models" / "base.go", name, NodeType.METHOD, parent_chain, tmp_path, detail
+        )
+        return Node(qname, NodeType.METHOD, str(tmp_path / "models" / "base.go"), 1, 3)
+
+    def test_pointer_and_value_receivers(self, tmp_path: Path):
+        dispose = self._node(tmp_path, "Dispose", "func (e *Entity) Dispose()", [("Entity", 23)])
+        get_type = self._node(tmp_path, "GetType", "func (e Entity) GetType() string", [("Entity", 23)])
+
+        assert dispose.fully_qualified_name == "models.base.(*Entity).Dispose"
+        assert dispose.receiver_kind == ReceiverKind.POINTER
+        assert get_type.receiver_kind == ReceiverKind.VALUE
+
+    def test_functions_have_no_receiver(self, tmp_path: Path):
+        assert self._node(tmp_path, "NewEntity", "func() *Entity", []).receiver_kind == ReceiverKind.NONE
+
+    def test_method_entry_carries_the_receiver_kind(self, tmp_path: Path):
+        node = self._node(tmp_path, "SetType", "func (e *Entity) SetType(t string)", [("Entity", 23)])
+
+        assert MethodEntry.from_node(node).receiver_kind == "pointer"
+
+
+class TestGenericNames:
+    """Methods of generic types are named after the type without its type parameters."""
+
+    def test_type_parameters_are_dropped(self, tmp_path: Path):
+        adapter = GoAdapter()
+        path = tmp_path / "container" / "stack.go"
+
+        nested = adapter.build_qualified_name(
+            path, "Push", NodeType.METHOD, [("Stack[T]", 23)], tmp_path, "func (s *Stack[T]) Push(v T)"
+        )
+        flat = adapter.build_qualified_name(path, "(Stack[T]).Len", NodeType.METHOD, [], tmp_path)
+
+        assert nested == "container.stack.(*Stack).Push"
+        assert flat == "container.stack.(Stack).Len"
+
+
+class TestInitFunctions:
+    """Every ``init`` of a file stays its own symbol; the later ones get an ordinal."""
+
+    def test_later_inits_are_numbered(self, tmp_path: Path):
+        adapter = GoAdapter()
+        symbols = [
+            {"name": "init", "kind": NodeType.FUNCTION},
+            {"name": "taskHandlers", "kind": NodeType.VARIABLE},
+            {"name": "init", "kind": NodeType.FUNCTION},
+            {"name": "init", "kind": NodeType.FUNCTION},
+        ]
+
+        normalized = adapter.normalize_document_symbols(symbols)
+
+        assert [s["name"] for s in normalized] == ["init", "taskHandlers", "init#2", "init#3"]
+        assert symbols[2]["name"] == "init"
+        qname = adapter.build_qualified_name(
+            tmp_path / "services" / "processor.go", "init#2", NodeType.FUNCTION, [], tmp_path
+        )
+        assert qname == "services.processor.init#2"
+
+    def test_single_init_is_untouched(self):
+        symbols = [{"name": "init", "kind": NodeType.FUNCTION}]
+
+        assert GoAdapter().normalize_document_symbols(symbols) is symbols
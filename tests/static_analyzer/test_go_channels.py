@@ -0,0 +1,136 @@
+import re
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.go_channels import add_channel_edges, add_spawn_edges
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.node import Node
+
+PIPELINE_GO = """package pipeline
+
+import "fmt"
+
+var events = make(chan string, 8)
+
+type Hub struct {
+	updates chan int
+}
+
+// Run wires the workers: jobs <- are fanned out, results collected.
+func Run(items []int) {
+	jobs := make(chan int)
+	results := make(chan int)
+	go worker(jobs, results)
+	go func(out chan<- int) {
+		for _, item := range items {
+			out <- item
+		}
+		close(out)
+	}(jobs)
+	for r := range results {
+		fmt.Println("got <- ", r)
+	}
+}
+
+func worker(in <-chan int, out chan<- int) {
+	for v := range in {
+		out <- v * 2
+	}
+}
+
+func Emit(name string) {
+	events <- name
+}
+
+func Audit() {
+	for {
+		select {
+		case e := <-events:
+			fmt.Println(e)
+		}
+	}
+}
+
+func (h *Hub) Publish(v int) {
+	h.updates <- v
+}
+
+func (h *Hub) Listen() int {
+	return <-h.updates
+}
+"""
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, list[str]]:
+    path = tmp_path / "pipeline.go"
+    path.write_text(PIPELINE_GO)
+    graph = CallGraph(language="go")
+    lines = PIPELINE_GO.splitlines()
+    for index, line in enumerate(lines):
+        if match := re.match(r"func (?:\(h \*Hub\) )?(\w+)", line):
+            end = next(i for i in range(index, len(lines)) if lines[i] == "}")
+            owner = "pipeline.Hub." if line.startswith("func (h") else "pipeline."
+            graph.add_node(Node(owner + match.group(1), NodeType.FUNCTION, str(path), index + 1, end + 1))
+    return graph, [str(path)]
+
+
+def test_channel_sends_link_producers_to_consumers(tmp_path: Path):
+    graph, files = _graph(tmp_path)
+
+    links = add_channel_edges(graph, files)
+
+    assert {(link.producer, link.consumer, link.channel, link.confidence) for link in links} == {
+        ("pipeline.Run", "pipeline.worker", "in", "medium"),
+        ("pipeline.worker", "pipeline.Run", "out", "medium"),
+        ("pipeline.Emit", "pipeline.Audit", "events", "high"),
+        ("pipeline.Hub.Publish", "pipeline.Hub.Listen", "updates", "low"),
+    }
+    assert ("pipeline.Emit", "pipeline.Audit", str(EdgeKind.SENDS_TO)) in graph.reference_edges
+    assert graph.reference_confidence[("pipeline.Audit", "pipeline.Emit", str(EdgeKind.RECEIVES_FROM))] == "high"
+    assert graph.edges == []
+
+    # Re-running (as every warm start does) adds nothing new.
+    add_channel_edges(graph, files)
+    assert len(graph.reference_edges) == 2 * len(links)
+    filtered = graph.filter(lambda node: True, lambda edge: None)
+    assert filtered.reference_confidence == graph.reference_confidence
+
+
+SERVER_GO = """package server
+
+type Server struct{}
+
+func (s *Server) Serve(port int) {}
+
+func Start(s *Server) {
+	go s.Serve(8080)
+	go func() {
+		s.Serve(8081)
+	}()
+	defer cleanup()
+}
+
+func cleanup() {}
+"""
+
+
+def test_go_statements_become_spawns_edges(tmp_path: Path):
+    graph, files = _graph(tmp_path)
+    server = tmp_path / "server" / "server.go"
+    server.parent.mkdir()
+    server.write_text(SERVER_GO)
+    for qname, node_type, line, end in [
+        ("server.server.(*Server).Serve", NodeType.METHOD, 5, 5),
+        ("server.server.Start", NodeType.FUNCTION, 7, 13),
+        ("server.server.cleanup", NodeType.FUNCTION, 15, 15),
+    ]:
+        graph.add_node(Node(qname, node_type, str(server), line, end))
+    graph.add_edge("server.server.Start", "server.server.(*Server).Serve")
+
+    spawns = add_spawn_edges(graph, [*files, str(server)])
+
+    # ``go func() { ... }()`` runs Run's own code; ``defer`` starts no goroutine.
+    assert spawns == [("pipeline.Run", "pipeline.worker"), ("server.server.Start", "server.server.(*Server).Serve")]
+    assert ("pipeline.Run", "pipeline.worker", str(EdgeKind.SPAWNS)) in graph.reference_edges
+    add_spawn_edges(graph, [*files, str(server)])
+    assert len(graph.reference_edges) == 2
@@ -0,0 +1,137 @@
+from pathlib import Path
+
+from agents.agent_responses import SourceCodeReference
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.go_embedding import Promotions, add_embedding_edges
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.interface_dispatch import add_interface_dispatch_edges
+from static_analyzer.node import Node
+from static_analyzer.reference_resolver import StaticReferenceResolver
+
+BASE_GO = """package models
+
+type Typed interface {
+	GetType() string
+}
+
+type Disposer interface {
+	Typed
+	Dispose()
+}
+
+type Entity struct {
+	typeName string
+}
+
+func (e Entity) GetType() string { return e.typeName }
+
+func (e *Entity) Dispose() {}
+"""
+
+TASK_GO = """package models
+
+type Task struct {
+	Entity // promoted: GetType, Dispose
+	Title  string `json:"title"`
+}
+
+func (t Task) Dispose() {}
+"""
+
+MAIN_GO = """package main
+
+func main() {
+	var typed models.Typed = models.Task{}
+	typed.GetType()
+}
+"""
+
+
+def _line(source: str, text: str) -> int:
+    return next(i for i, line in enumerate(source.splitlines(), start=1) if text in line)
+
+
+def _write(tmp_path: Path, rel: str, source: str) -> str:
+    path = tmp_path / rel
+    path.parent.mkdir(parents=True, exist_ok=True)
+    path.write_text(source)
+    return str(path)
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, list[Node]]:
+    base = _write(tmp_path, "models/base.go", BASE_GO)
+    task = _write(tmp_path, "models/task.go", TASK_GO)
+    main = _write(tmp_path, "main.go", MAIN_GO)
+    graph = CallGraph(language="go")
+    for qname, node_type, path, source, text in [
+        ("models.base.Typed", NodeType.INTERFACE, base, BASE_GO, "type Typed"),
+        ("models.base.(Typed).GetType", NodeType.METHOD, base, BASE_GO, "GetType() string\n"),
+        ("models.base.Disposer", NodeType.INTERFACE, base, BASE_GO, "type Disposer"),
+        ("models.base.(Disposer).Typed", NodeType.INTERFACE, base, BASE_GO, "\tTyped"),
+        ("models.base.Entity", NodeType.STRUCT, base, BASE_GO, "type Entity"),
+        ("models.base.(Entity).GetType", NodeType.METHOD, base, BASE_GO, "func (e Entity) GetType"),
+        ("models.base.(*Entity).Dispose", NodeType.METHOD, base, BASE_GO, "func (e *Entity) Dispose"),
+        ("models.task.Task", NodeType.STRUCT, task, TASK_GO, "type Task"),
+        ("models.task.(Task).Dispose", NodeType.METHOD, task, TASK_GO, "func (t Task) Dispose"),
+        ("main.main", NodeType.FUNCTION, main, MAIN_GO, "func main"),
+    ]:
+        line = _line(source, text.rstrip("\n"))
+        graph.add_node(Node(qname, node_type, path, line, line))
+    fields = [
+        Node(qname, NodeType.FIELD, path, _line(source, text), _line(source, text))
+        for qname, path, source, text in [
+            ("models.task.(Task).Entity", task, TASK_GO, "Entity //"),
+            ("models.task.(Task).Title", task, TASK_GO, "Title"),
+            ("models.base.(Entity).typeName", base, BASE_GO, "\ttypeName"),
+        ]
+    ]
+    return graph, [*graph.nodes.values(), *fields]
+
+
+def test_embedded_struct_and_interface_get_embeds_edges(tmp_path: Path):
+    graph, members = _graph(tmp_path)
+
+    embeddings = add_embedding_edges(graph, members)
+
+    assert embeddings == [("models.base.Disposer", "models.base.Typed"), ("models.task.Task", "models.base.Entity")]
+    assert ("models.task.Task", "models.base.Entity", EdgeKind.EMBEDS) in graph.reference_edges
+    # Re-running (warm start) adds nothing.
+    add_embedding_edges(graph, members)
+    assert len(graph.reference_edges) == 2
+
+
+def test_promoted_methods_resolve_to_their_definition(tmp_path: Path):
+    graph, members = _graph(tmp_path)
+    add_embedding_edges(graph, members)
+    promotions = Promotions(graph)
+
+    assert promotions.resolve("models.task.(Task).GetType") == "models.base.(Entity).GetType"
+    assert promotions.resolve("models.task.Task.GetType") == "models.base.(Entity).GetType"
+    # Task declares its own Dispose, which shadows Entity's.
+    assert promotions.method("models.task.Task", "Dispose") == "models.task.(Task).Dispose"
+    assert promotions.resolve("models.task.(Task).Missing") is None
+
+
+def test_call_through_an_interface_reaches_the_promoted_method(tmp_path: Path):
+    graph, members = _graph(tmp_path)
+    add_embedding_edges(graph, members)
+    graph.add_edge("main.main", "models.base.(Typed).GetType")
+
+    add_interface_dispatch_edges(graph, {"models.base.Typed": {"implementations": ["models.task.Task"]}})
+
+    callees = {edge.get_destination() for edge in graph.edges if edge.get_source() == "main.main"}
+    assert "models.base.(Entity).GetType" in callees
+
+
+def test_reference_to_a_promoted_method_resolves(tmp_path: Path):
+    graph, members = _graph(tmp_path)
+    add_embedding_edges(graph, members)
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, graph)
+    results.add_references(Language.GO, members)
+    reference = SourceCodeReference(qualified_name="models.task.(Task).GetType")
+
+    assert StaticReferenceResolver(tmp_path, results).resolve_reference(reference)
+    assert reference.qualified_name == "models.base.(Entity).GetType"
+    assert reference.reference_start_line == _line(BASE_GO, "func (e Entity) GetType")
@@ -0,0 +1,52 @@
+from static_analyzer.go_enums import EnumConstant, constant_value, enum_constants
+
+SOURCE = """package models
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota // lowest first
+	PriorityMedium
+	_
+	PriorityHigh
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending" // a "//" in a literal is not a comment
+	StatusURL     Status = "http://done"
+)
+
+const MaxRetries = 3
+
+const Default Priority = PriorityMedium
+
+const (
+	KB Size = 1 << (10 * (iota + 1))
+	MB
+	a, b = 1, 2
+	Read = Mode(iota)
+	Write
+)
+"""
+
+
+def test_typed_constants_are_grouped_with_their_iota_values():
+    assert enum_constants(SOURCE) == [
+        EnumConstant("Priority", "PriorityLow", "0"),
+        EnumConstant("Priority", "PriorityMedium", "1"),
+        EnumConstant("Priority", "PriorityHigh", "3"),
+        EnumConstant("Status", "StatusPending", '"pending"'),
+        EnumConstant("Status", "StatusURL", '"http://done"'),
+        EnumConstant("Priority", "Default", "PriorityMedium"),
+        EnumConstant("Size", "KB", "1024"),
+        EnumConstant("Size", "MB", "1048576"),
+        EnumConstant("Mode", "Read", "3"),
+        EnumConstant("Mode", "Write", "4"),
+    ]
+
+
+def test_values_that_are_not_iota_arithmetic_are_kept_as_written():
+    assert constant_value("iota * time.Second", 2) == "iota * time.Second"
+    assert constant_value("7 / 2 + iota", 1) == "4"
@@ -0,0 +1,122 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.go_generics import add_constraint_edges, find_generic_declarations, type_parameters
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+CONTAINER_GO = """package container
+
+// Number is satisfied by every type whose underlying type is int or float64.
+type Number interface {
+	~int | ~float64
+}
+
+type Stack[T any] struct {
+	items []T
+}
+
+func (s *Stack[T]) Push(v T) { s.items = append(s.items, v) }
+
+func NewStack[T any]() *Stack[T] { return &Stack[T]{} }
+
+func Sum[T Number](xs []T) T {
+	var total T
+	for _, x := range xs {
+		total += x
+	}
+	return total
+}
+
+type Ranked[K comparable, V Number] struct {
+	byKey map[K]V
+}
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+	out := make([]U, 0, len(xs))
+	for _, x := range xs {
+		out = append(out, f(x))
+	}
+	return out
+}
+"""
+
+SCORE_GO = """package models
+
+type Score int
+"""
+
+MAIN_GO = """package main
+
+func main() {
+	s := container.NewStack[models.Score]()
+	s.Push(1)
+	total := container.Sum[models.Score]([]models.Score{1, 2})
+	ranked := container.Ranked[string, models.Score]{}
+	names := container.Map[int, string]([]int{1}, strconv.Itoa) // Sum[Ignored] in a comment
+}
+"""
+
+
+def _line(source: str, text: str) -> int:
+    return next(i for i, line in enumerate(source.splitlines(), start=1) if text in line)
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, list[str]]:
+    files = {}
+    for rel, source in (("container/stack.go", CONTAINER_GO), ("models/score.go", SCORE_GO), ("main.go", MAIN_GO)):
+        path = tmp_path / rel
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(source)
+        files[rel] = str(path)
+    graph = CallGraph(language="go")
+    for qname, node_type, rel, source, text in [
+        ("container.stack.Number", NodeType.INTERFACE, "container/stack.go", CONTAINER_GO, "type Number"),
+        ("container.stack.Stack", NodeType.STRUCT, "container/stack.go", CONTAINER_GO, "type Stack"),
+        ("container.stack.(*Stack).Push", NodeType.METHOD, "container/stack.go", CONTAINER_GO, "Push(v T)"),
+        ("container.stack.NewStack", NodeType.FUNCTION, "container/stack.go", CONTAINER_GO, "func NewStack"),
+        ("container.stack.Sum", NodeType.FUNCTION, "container/stack.go", CONTAINER_GO, "func Sum"),
+        ("container.stack.Ranked", NodeType.STRUCT, "container/stack.go", CONTAINER_GO, "type Ranked"),
+        ("container.stack.Map", NodeType.FUNCTION, "container/stack.go", CONTAINER_GO, "func Map"),
+        ("models.score.Score", NodeType.CLASS, "models/score.go", SCORE_GO, "type Score"),
+        ("main.main", NodeType.FUNCTION, "main.go", MAIN_GO, "func main"),
+    ]:
+        line = _line(source, text)
+        graph.add_node(Node(qname, node_type, files[rel], line, line + 1))
+    return graph, list(files.values())
+
+
+def test_type_parameter_lists():
+    assert type_parameters("T any") == ["any"]
+    assert type_parameters("K comparable, V Number") == ["comparable", "Number"]
+    assert type_parameters("T, U any") == ["any", "any"]
+    assert type_parameters("S ~[]E, E interface{ ~int }") == ["~[]E", "interface{ ~int }"]
+
+
+def test_generic_declarations_and_their_constraints(tmp_path: Path):
+    graph, _ = _graph(tmp_path)
+
+    declarations = {d.qualified_name: d.constraints for d in find_generic_declarations(graph)}
+
+    assert declarations == {
+        "container.stack.Stack": ((),),
+        "container.stack.NewStack": ((),),
+        "container.stack.Sum": (("container.stack.Number",),),
+        "container.stack.Ranked": ((), ("container.stack.Number",)),
+        "container.stack.Map": ((), ()),
+    }
+
+
+def test_constraints_and_type_arguments_become_edges(tmp_path: Path):
+    graph, source_files = _graph(tmp_path)
+
+    added = add_constraint_edges(graph, source_files)
+
+    assert added == [
+        ("container.stack.Ranked", "container.stack.Number", "typeref"),
+        ("container.stack.Sum", "container.stack.Number", "typeref"),
+        ("models.score.Score", "container.stack.Number", "implements"),
+    ]
+    # Re-running (warm start) adds nothing.
+    assert add_constraint_edges(graph, source_files) == []
+    assert len(graph.reference_edges) == 3
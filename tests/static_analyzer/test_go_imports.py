@@ -0,0 +1,109 @@
+from pathlib import Path
+
+from agents.agent_responses import SourceCodeReference
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.go_imports import GoImport, GoImportIndex, add_import_edges, parse_imports
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+from static_analyzer.reference_resolver import StaticReferenceResolver
+
+FIXTURE = (Path(__file__).parent / "fixtures" / "go_imports").resolve()
+
+
+def _results() -> StaticAnalysisResults:
+    results = StaticAnalysisResults()
+    nodes = [
+        Node("utils.math.Add", NodeType.FUNCTION, str(FIXTURE / "utils/math.go"), 3, 3),
+        Node("utils.math.Counter", NodeType.STRUCT, str(FIXTURE / "utils/math.go"), 5, 7),
+        Node("utils.math.(*Counter).Incr", NodeType.METHOD, str(FIXTURE / "utils/math.go"), 9, 9),
+        Node("calc.calc.Add", NodeType.FUNCTION, str(FIXTURE / "calc/calc.go"), 4, 4),
+        Node("aliased.aliased.Sum", NodeType.FUNCTION, str(FIXTURE / "aliased/aliased.go"), 9, 18),
+        Node("dotted.dotted.Twice", NodeType.FUNCTION, str(FIXTURE / "dotted/dotted.go"), 5, 5),
+        Node("textfmt.format.Pretty", NodeType.FUNCTION, str(FIXTURE / "textfmt/format.go"), 4, 4),
+        Node("cmd.app.main.main", NodeType.FUNCTION, str(FIXTURE / "cmd/app/main.go"), 8, 8),
+    ]
+    results.add_references(Language.GO, nodes)
+    results.add_source_files(Language.GO, sorted(str(path) for path in FIXTURE.rglob("*.go")))
+    return results
+
+
+def _resolved(qname: str, scope: str | None = None) -> str | None:
+    resolver = StaticReferenceResolver(FIXTURE, _results())
+    if scope is None:
+        node = resolver.resolve_node(SourceCodeReference(qualified_name=qname))
+        return node.fully_qualified_name if node is not None else None
+    repair = resolver.repair_key_entity_references(
+        [SourceCodeReference(qualified_name=qname)], allowed_files={scope, "utils/math.go"}
+    )
+    return repair.references[0].qualified_name if repair.references else None
+
+
+def test_parse_imports_keeps_aliases_in_both_forms():
+    source = 'package x\n\nimport u "a/utils"\n\nimport (\n\t"fmt" // stdlib\n\t. "a/dot"\n\t_ "a/blank"\n)\n'
+
+    assert parse_imports(source) == [
+        GoImport("a/utils", "u"),
+        GoImport("fmt"),
+        GoImport("a/dot", "."),
+        GoImport("a/blank", "_"),
+    ]
+
+
+def test_import_table_maps_qualifiers_to_package_directories():
+    index = GoImportIndex()
+    aliased = index.table(FIXTURE / "aliased/aliased.go")
+    main = index.table(FIXTURE / "cmd/app/main.go")
+
+    assert aliased.package_dir("u") == FIXTURE / "utils"
+    assert aliased.package_dir("utils") is None
+    assert aliased.package_dir("fmt") is None
+    assert index.table(FIXTURE / "dotted/dotted.go").dot_imported_dirs() == [FIXTURE / "utils"]
+    # The unrenamed import binds the package clause's name, not the directory's.
+    assert main.package_dir("format") == FIXTURE / "textfmt"
+    # A blank import binds no name but still maps to its package.
+    assert main.package_dir("_") is None
+    assert main.directories["example.com/edgecases/drivers"] == FIXTURE / "drivers"
+
+
+def test_aliased_and_dot_imported_names_resolve_to_the_canonical_package():
+    assert _resolved("u.Add") == "utils.math.Add"
+    assert _resolved("u.Counter.Incr") == "utils.math.(*Counter).Incr"
+    assert _resolved("format.Pretty") == "textfmt.format.Pretty"
+    assert _resolved("Add", scope="dotted/dotted.go") == "utils.math.Add"
+    assert _resolved("u.Add", scope="aliased/aliased.go") == "utils.math.Add"
+
+
+def test_unknown_qualifiers_do_not_resolve_through_imports():
+    assert _resolved("v.Add") is None
+    assert _resolved("fmt.Println") is None
+
+
+def _call_graph() -> CallGraph:
+    graph = CallGraph(language="go")
+    for qname, rel, line_start, line_end in [
+        ("utils.math.Add", "utils/math.go", 3, 3),
+        ("utils.math.(*Counter).Incr", "utils/math.go", 9, 9),
+        ("dotted.dotted.Twice", "dotted/dotted.go", 5, 5),
+        ("dotted.dotted.Shadowed", "dotted/dotted.go", 7, 10),
+        ("drivers.register.init", "drivers/register.go", 5, 5),
+        ("cmd.app.main.main", "cmd/app/main.go", 8, 8),
+    ]:
+        node_type = NodeType.METHOD if ".(" in qname else NodeType.FUNCTION
+        graph.add_node(Node(qname, node_type, str(FIXTURE / rel), line_start, line_end))
+    return graph
+
+
+def test_dot_imported_calls_and_blank_imported_inits_are_linked():
+    graph = _call_graph()
+
+    calls, imports = add_import_edges(graph, [str(path) for path in FIXTURE.rglob("*.go")])
+
+    # ``Shadowed`` calls its local ``Add``.
+    assert calls == [("dotted.dotted.Twice", "utils.math.Add")]
+    assert graph.edges[0].call_sites == [{"file": str(FIXTURE / "dotted/dotted.go"), "line": 5, "column": 32}]
+    # The init runs for the binary, but ``main`` does not call it.
+    assert imports == [("cmd.app.main.main", "drivers.register.init")]
+    assert ("cmd.app.main.main", "drivers.register.init", "import") in graph.reference_edges
+    assert len(graph.edges) == 1
+    assert add_import_edges(graph, [str(path) for path in FIXTURE.rglob("*.go")]) == ([], [])
@@ -0,0 +1,45 @@
+from pathlib import Path
+
+import pytest
+
+from static_analyzer.go_main_package import GoMainPackageError, reachable_go_files
+
+
+def _write(root: Path, rel: str, text: str) -> None:
+    path = root / rel
+    path.parent.mkdir(parents=True, exist_ok=True)
+    path.write_text(text)
+
+
+@pytest.fixture
+def go_repo(tmp_path: Path) -> Path:
+    _write(tmp_path, "go.mod", "module example.com/app\n\ngo 1.22\n")
+    _write(
+        tmp_path,
+        "cmd/server/main.go",
+        'package main\n\nimport (\n\t"fmt"\n\tapi "example.com/app/internal/api"\n)\n\n'
+        "func main() { fmt.Println(api.X) }\n",
+    )
+    _write(tmp_path, "cmd/server/main_test.go", "package main\n")
+    _write(tmp_path, "cmd/worker/main.go", 'package main\n\nimport "example.com/app/internal/queue"\n')
+    _write(tmp_path, "internal/api/api.go", 'package api\n\nimport "example.com/app/internal/store"\n')
+    _write(tmp_path, "internal/store/store.go", "package store\n")
+    _write(tmp_path, "internal/queue/queue.go", 'package queue\n\nimport "example.com/app/internal/store"\n')
+    return tmp_path
+
+
+def test_reachable_files_follow_module_imports_only(go_repo: Path):
+    files = reachable_go_files(go_repo, "./cmd/server")
+
+    assert sorted(f.relative_to(go_repo.resolve()).as_posix() for f in files) == [
+        "cmd/server/main.go",
+        "internal/api/api.go",
+        "internal/store/store.go",
+    ]
+
+
+def test_rejects_non_main_or_missing_package(go_repo: Path):
+    with pytest.raises(GoMainPackageError, match="package main"):
+        reachable_go_files(go_repo, "internal/api")
+    with pytest.raises(GoMainPackageError, match="not a directory"):
+        reachable_go_files(go_repo, "cmd/missing")
@@ -0,0 +1,173 @@
+import pickle
+from pathlib import Path
+
+from agents.file_index_models import MethodEntry
+from output_generators.class_diagram import build_class_diagram, generate_class_diagram, method_signature
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.go_signatures import add_go_signatures, parse_results, parse_signature
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node, Parameter
+
+UTILS_GO = """package utils
+
+// Clamp bounds value to [min, max].
+func Clamp(value, min, max int) (result int) {
+	return value
+}
+
+func (t *Task) GetTaskInfo() (string, int) {
+	return t.Title, t.Priority
+}
+
+func Split(s string,
+	sep string, // separator
+) (head, tail string, err error) {
+	return "", "", nil
+}
+
+func Map[T, U any](xs []T, f func(T) U) []U { return nil }
+
+func Watch() (<-chan int, func()) { return nil, nil }
+
+func Reset() {}
+
+func Compose(name string, fns ...HandlerFunc) HandlerFunc { return nil }
+"""
+
+
+def _graph(tmp_path: Path) -> CallGraph:
+    path = tmp_path / "utils" / "utils.go"
+    path.parent.mkdir()
+    path.write_text(UTILS_GO)
+    cfg = CallGraph(language="go")
+    for qname, text in [
+        ("utils.utils.Clamp", "func Clamp"),
+        ("utils.utils.(*Task).GetTaskInfo", "GetTaskInfo()"),
+        ("utils.utils.Split", "func Split"),
+        ("utils.utils.Map", "func Map"),
+        ("utils.utils.Watch", "func Watch"),
+        ("utils.utils.Reset", "func Reset"),
+        ("utils.utils.Compose", "func Compose"),
+    ]:
+        line = next(i for i, source in enumerate(UTILS_GO.splitlines(), start=1) if text in source)
+        node_type = NodeType.METHOD if ".(" in qname else NodeType.FUNCTION
+        cfg.add_node(Node(qname, node_type, str(path), line, line + 2))
+    return cfg
+
+
+def _results(cfg: CallGraph) -> StaticAnalysisResults:
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, cfg)
+    return results
+
+
+def test_named_and_unnamed_results_are_kept_apart(tmp_path: Path):
+    cfg = _graph(tmp_path)
+
+    assert add_go_signatures(cfg) == 7
+    clamp = cfg.nodes["utils.utils.Clamp"].signature
+    assert clamp.parameters == (Parameter("int", "value"), Parameter("int", "min"), Parameter("int", "max"))
+    assert clamp.parameters_str() == "value, min, max int"
+    assert clamp.results == (Parameter("int", "result"),)
+    assert clamp.named_results
+    info = cfg.nodes["utils.utils.(*Task).GetTaskInfo"].signature
+    assert info.results == (Parameter("string"), Parameter("int"))
+    assert not info.named_results
+    assert info.render("GetTaskInfo") == "GetTaskInfo() (string, int)"
+
+
+def test_wrapped_generic_and_bodyless_declarations(tmp_path: Path):
+    cfg = _graph(tmp_path)
+    add_go_signatures(cfg)
+
+    split = cfg.nodes["utils.utils.Split"].signature
+    assert split.parameters == (Parameter("string", "s"), Parameter("string", "sep"))
+    assert split.render("Split") == "Split(s, sep string) (head, tail string, err error)"
+    assert cfg.nodes["utils.utils.Map"].signature.render("Map") == "Map(xs []T, f func(T) U) []U"
+    watch = cfg.nodes["utils.utils.Watch"].signature
+    assert watch.results == (Parameter("<-chan int"), Parameter("func()"))
+    assert cfg.nodes["utils.utils.Reset"].signature.render("Reset") == "Reset()"
+    assert parse_results("(chan int, error)") == (Parameter("chan int"), Parameter("error"))
+
+
+def test_signature_reaches_the_llm_string_and_class_diagram(tmp_path: Path):
+    cfg = _graph(tmp_path)
+    add_go_signatures(cfg)
+    cfg.add_edge("utils.utils.Split", "utils.utils.Clamp")
+
+    assert "utils.utils.Split(s, sep string) (head, tail string, err error) calls:" in cfg.llm_str()
+    clamp = cfg.nodes["utils.utils.Clamp"]
+    assert method_signature(clamp, "Clamp") == "Clamp(value, min, max int) result int"
+    assert method_signature(Node("x.Y", NodeType.FUNCTION, "x.py", 1, 1), "Y") == "Y()"
+
+
+def test_variadic_parameter_is_marked_and_round_trips(tmp_path: Path):
+    cfg = _graph(tmp_path)
+    add_go_signatures(cfg)
+    node = cfg.nodes["utils.utils.Compose"]
+
+    compose = node.signature
+    assert compose.parameters[-1] == Parameter("HandlerFunc", "fns", is_variadic=True)
+    assert compose.is_variadic and not cfg.nodes["utils.utils.Clamp"].signature.is_variadic
+    rendered = compose.render("Compose")
+    assert rendered == "Compose(name string, fns ...HandlerFunc) HandlerFunc"
+    assert parse_signature(f"func {rendered} {{", "Compose") == compose
+    assert pickle.loads(pickle.dumps(node)).signature == compose
+    assert MethodEntry.from_node(node).signature == rendered
+    assert method_signature(node, "Compose") == rendered
+    # ``Compose("x", a, b, c)`` and ``Compose("x")`` both match the one declaration.
+    assert compose.accepts(4) and compose.accepts(1) and not compose.accepts(0)
+    assert parse_signature("func f(int, ...string)", "f").parameters[-1] == Parameter("string", is_variadic=True)
+
+
+HANDLERS_GO = """package utils
+
+type HandlerFunc func(int) int
+
+type (
+	Middleware = func(next HandlerFunc) HandlerFunc
+)
+
+var DefaultHandler HandlerFunc = double
+
+var square = func(x int) int { return x * x }
+
+func CreateMultiplier(n int) HandlerFunc {
+	return func(x int) int { return x * n }
+}
+"""
+
+
+def test_function_types_are_type_nodes_with_returns_edges(tmp_path: Path):
+    path = tmp_path / "utils" / "handlers.go"
+    path.parent.mkdir()
+    path.write_text(HANDLERS_GO)
+
+    def node(qname: str, node_type: NodeType, text: str) -> Node:
+        line = next(i for i, source in enumerate(HANDLERS_GO.splitlines(), start=1) if text in source)
+        return Node(qname, node_type, str(path), line, line)
+
+    cfg = CallGraph(language="go")
+    # gopls reports a function type like a function.
+    cfg.add_node(node("utils.handlers.HandlerFunc", NodeType.FUNCTION, "type HandlerFunc"))
+    cfg.add_node(node("utils.handlers.Middleware", NodeType.CLASS, "Middleware ="))
+    cfg.add_node(node("utils.handlers.CreateMultiplier", NodeType.FUNCTION, "func CreateMultiplier"))
+    variables = [
+        node("utils.handlers.DefaultHandler", NodeType.VARIABLE, "var DefaultHandler"),
+        node("utils.handlers.square", NodeType.VARIABLE, "var square"),
+    ]
+
+    assert add_go_signatures(cfg, variables) == 1
+
+    handler = cfg.nodes["utils.handlers.HandlerFunc"]
+    assert handler.type == NodeType.CLASS
+    assert handler.signature.render("func") == "func(int) int"
+    assert cfg.nodes["utils.handlers.Middleware"].signature.render("func") == "func(next HandlerFunc) HandlerFunc"
+    assert variables[0].signature == handler.signature
+    assert variables[1].signature.render("square") == "square(x int) int"
+    assert ("utils.handlers.CreateMultiplier", "utils.handlers.HandlerFunc", "returns") in cfg.reference_edges
+    assert ("utils.handlers.Middleware", "utils.handlers.HandlerFunc", "returns") not in cfg.reference_edges
+
+    source = generate_class_diagram(build_class_diagram(_results(cfg)))
+    assert "    <<function>> utils_handlers_HandlerFunc\n    utils_handlers_HandlerFunc : +func(int) int\n" in source
@@ -0,0 +1,99 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.engine.adapters.go_adapter import GoAdapter
+from static_analyzer.go_syntax_fallback import GoCall, add_syntax_fallback, parse_declarations
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+ORDERS_GO = """package orders
+
+import (
+\tstore "example.com/shop/internal/db"
+\t"example.com/shop/util"
+)
+
+type Order struct {
+\tID int
+}
+
+type Repo interface {
+\tSave(o Order) error
+}
+
+type Cache[K comparable] struct{}
+
+func (c *Cache[K]) Get(key K) {}
+
+func init() {}
+
+func init() {}
+
+func Place(o Order) error {
+\tif err := validate(o); err != nil {
+\t\treturn undefinedInThisBranch(err)
+\t}
+\tstore.Open()
+\treturn util.Clamp(o.ID)
+}
+
+func validate(o Order) error { return nil }
+"""
+
+
+def test_declarations_calls_and_imports_of_a_file():
+    declarations, imports = parse_declarations(ORDERS_GO.encode())
+
+    assert [(d.symbol["name"], d.symbol["kind"]) for d in declarations] == [
+        ("Order", NodeType.STRUCT),
+        ("Repo", NodeType.INTERFACE),
+        ("Cache", NodeType.STRUCT),
+        ("(*Cache).Get", NodeType.METHOD),
+        ("init", NodeType.FUNCTION),
+        ("init", NodeType.FUNCTION),
+        ("Place", NodeType.FUNCTION),
+        ("validate", NodeType.FUNCTION),
+    ]
+    place = declarations[6]
+    assert [(call.qualifier, call.name) for call in place.calls] == [
+        (None, "validate"),
+        (None, "undefinedInThisBranch"),
+        ("store", "Open"),
+        ("util", "Clamp"),
+    ]
+    assert place.calls[0] == GoCall("validate", None, 24, 11)
+    assert imports == {"store": "example.com/shop/internal/db", "util": "example.com/shop/util"}
+
+
+def test_files_without_symbols_are_parsed_and_linked(tmp_path: Path):
+    orders = tmp_path / "orders" / "orders.go"
+    orders.parent.mkdir()
+    orders.write_text(ORDERS_GO)
+    helpers = tmp_path / "util" / "helpers.go"
+    helpers.parent.mkdir()
+    helpers.write_text("package util\n\nfunc Clamp(n int) int { return n }\n")
+    cfg = CallGraph(language="go")
+    clamp = Node("util.helpers.Clamp", NodeType.FUNCTION, str(helpers), 3, 3)
+    cfg.add_node(clamp)
+
+    added, degraded = add_syntax_fallback(cfg, [str(orders), str(helpers)], [], GoAdapter(), tmp_path)
+
+    assert degraded == [str(orders)]
+    assert {node.fully_qualified_name for node in added} == {
+        "orders.orders.Order",
+        "orders.orders.Repo",
+        "orders.orders.Cache",
+        "orders.orders.(*Cache).Get",
+        "orders.orders.init",
+        "orders.orders.init#2",
+        "orders.orders.Place",
+        "orders.orders.validate",
+    }
+    assert sorted((edge.get_source(), edge.get_destination()) for edge in cfg.edges) == [
+        ("orders.orders.Place", "orders.orders.validate"),
+        ("orders.orders.Place", "util.helpers.Clamp"),
+    ]
+    # A file gopls did answer for is left alone; a re-run keeps the parsed file flagged.
+    assert add_syntax_fallback(cfg, [str(orders), str(helpers)], [], GoAdapter(), tmp_path) == ([], [])
+    _, degraded = add_syntax_fallback(cfg, [str(orders)], [], GoAdapter(), tmp_path, known_degraded=[str(orders)])
+    assert degraded == [str(orders)]
@@ -0,0 +1,189 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.go_variables import add_variable_edges, initializer, mutated_variables, stored_values
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+
+PROCESSOR_GO = """package services
+
+var taskHandlers = map[string]func(string) error{
+	"email": sendEmail,
+}
+
+var entityCount int
+
+var DefaultHandler HandlerFunc = double
+
+var square = func(x int) int { return x * x }
+
+func init() {
+	taskHandlers["sms"] = sendSMS
+	RegisterDefaults()
+}
+
+func init() {
+	entityCount = 0
+}
+
+func (e *Entity) SetType(t string) {
+	e.Type = t
+	entityCount++
+}
+
+func Process(name string) error {
+	total := entityCount // a read, not a write
+	_ = DefaultHandler(square(total))
+	return taskHandlers[name](name)
+}
+
+func sendEmail(to string) error { return nil }
+
+func sendSMS(to string) error { return nil }
+
+func double(x int) int { return x * 2 }
+"""
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, list[Node]]:
+    path = tmp_path / "services" / "processor.go"
+    path.parent.mkdir()
+    path.write_text(PROCESSOR_GO)
+    lines = PROCESSOR_GO.splitlines()
+
+    def node(qname: str, node_type: NodeType, text: str, occurrence: int = 0) -> Node:
+        start = [i for i, source in enumerate(lines, start=1) if text in source][occurrence]
+        if node_type is NodeType.VARIABLE:
+            return Node(qname, node_type, str(path), start, start)
+        end = next(i for i in range(start, len(lines) + 1) if lines[i - 1].endswith("}"))
+        return Node(qname, node_type, str(path), start, end)
+
+    cfg = CallGraph(language="go")
+    for qname, node_type, text, occurrence in [
+        ("services.processor.init", NodeType.FUNCTION, "func init()", 0),
+        ("services.processor.init#2", NodeType.FUNCTION, "func init()", 1),
+        ("services.processor.(*Entity).SetType", NodeType.METHOD, "SetType(", 0),
+        ("services.processor.Process", NodeType.FUNCTION, "func Process", 0),
+        ("services.processor.sendEmail", NodeType.FUNCTION, "func sendEmail", 0),
+        ("services.processor.sendSMS", NodeType.FUNCTION, "func sendSMS", 0),
+        ("services.processor.double", NodeType.FUNCTION, "func double", 0),
+    ]:
+        cfg.add_node(node(qname, node_type, text, occurrence))
+    variables = [
+        node(f"services.processor.{name}", NodeType.VARIABLE, f"var {name}")
+        for name in ("taskHandlers", "entityCount", "DefaultHandler", "square")
+    ]
+    return cfg, variables
+
+
+def test_writes_to_package_variables_become_mutates_edges(tmp_path: Path):
+    cfg, variables = _graph(tmp_path)
+
+    mutates, _ = add_variable_edges(cfg, variables)
+
+    assert mutates == [
+        ("services.processor.init", "services.processor.taskHandlers", "mutates"),
+        ("services.processor.init#2", "services.processor.entityCount", "mutates"),
+        ("services.processor.(*Entity).SetType", "services.processor.entityCount", "mutates"),
+    ]
+    assert "services.processor.entityCount" in cfg.nodes
+    assert add_variable_edges(cfg, variables) == ([], [])
+
+
+def test_calls_through_func_valued_variables_reach_the_wrapped_functions(tmp_path: Path):
+    cfg, variables = _graph(tmp_path)
+
+    _, calls = add_variable_edges(cfg, variables)
+
+    assert sorted(calls) == [
+        ("services.processor.Process", "services.processor.double"),
+        ("services.processor.Process", "services.processor.sendEmail"),
+        ("services.processor.Process", "services.processor.sendSMS"),
+        ("services.processor.Process", "services.processor.square"),
+    ]
+    assert cfg.nodes["services.processor.square"].type == NodeType.VARIABLE
+
+
+def test_assignment_site_helpers():
+    body = """
+	config.Timeout = 5
+	registry = append(registry, h)
+	total += n
+	delete(cache, key)
+	if limit == 3 { }
+	count := 1
+	count = 2
+	other.registry = nil
+"""
+
+    assert mutated_variables(body, ["cache", "config", "registry", "total", "limit", "count"]) == [
+        "config",
+        "registry",
+        "total",
+        "cache",
+    ]
+    assert stored_values("handlers[k] = onEvent\nhandlers[j] == other\nhandlers = build()", "handlers") == ["onEvent"]
+    assert initializer("var h HandlerFunc = double\nvar next = 1", "h").strip() == "double"
+    assert initializer("var h HandlerFunc", "h") is None
+
+
+DISPATCH_GO = """package services
+
+var taskHandlers = map[Status]func(t *Task) error{
+	Pending: func(t *Task) error {
+		return enqueue(t)
+	},
+	Done: archive,
+}
+
+func Dispatch(t *Task) error {
+	return taskHandlers[t.Status](t)
+}
+
+func Retry(t *Task) error {
+	if h, ok := taskHandlers[t.Status]; ok {
+		return h(t)
+	}
+	return nil
+}
+
+func enqueue(t *Task) error { return nil }
+
+func archive(t *Task) error { return nil }
+"""
+
+
+def test_map_dispatch_reaches_named_handlers_and_literal_bodies(tmp_path: Path):
+    path = tmp_path / "services" / "dispatch.go"
+    path.parent.mkdir()
+    path.write_text(DISPATCH_GO)
+    lines = DISPATCH_GO.splitlines()
+
+    def line(text: str) -> int:
+        return next(i for i, source in enumerate(lines, start=1) if text in source)
+
+    cfg = CallGraph(language="go")
+    for name, end in [("Dispatch", 3), ("Retry", 6), ("enqueue", 1), ("archive", 1)]:
+        start = line(f"func {name}(")
+        cfg.add_node(Node(f"services.dispatch.{name}", NodeType.FUNCTION, str(path), start, start + end - 1))
+    start = line("var taskHandlers")
+    variable = Node("services.dispatch.taskHandlers", NodeType.VARIABLE, str(path), start, start)
+
+    _, calls = add_variable_edges(cfg, [variable])
+
+    literal = cfg.nodes["services.dispatch.taskHandlers.func1"]
+    assert (literal.line_start, literal.line_end) == (line("Pending:"), line("Pending:") + 2)
+    assert sorted(calls) == [
+        ("services.dispatch.Dispatch", "services.dispatch.archive"),
+        ("services.dispatch.Dispatch", "services.dispatch.taskHandlers.func1"),
+        ("services.dispatch.Retry", "services.dispatch.archive"),
+        ("services.dispatch.Retry", "services.dispatch.taskHandlers.func1"),
+        ("services.dispatch.taskHandlers.func1", "services.dispatch.enqueue"),
+    ]
+    dispatch = cfg._edge_by_key[("services.dispatch.Dispatch", "services.dispatch.archive")]
+    assert dispatch.via == "dispatch"
+    assert dispatch.call_sites[0]["line"] == line("return taskHandlers[t.Status](t)")
+    assert cfg._edge_by_key[("services.dispatch.taskHandlers.func1", "services.dispatch.enqueue")].via is None
+    assert "calls: services.dispatch.archive (via dispatch), services.dispatch.taskHandlers.func1 (via dispatch)" in (
+        cfg.llm_str()
+    )
@@ -5,7 +5,7 @@ import networkx as nx
 
 from static_analyzer.constants import NodeType
 from static_analyzer.node import Node
-from static_analyzer.graph import Edge, CallGraph, ClusterResult
+from static_analyzer.graph import Edge, CallGraph, ClusterResult, EdgeKind
 
 
 class TestNode(unittest.TestCase):
@@ -218,6 +218,20 @@ class TestCallGraph(unittest.TestCase):
         self.assertEqual(len(graph.edges), 1)
         self.assertEqual(len(graph._edge_by_key), 1)
 
+    def test_recursive_cycles(self):
+        graph = CallGraph()
+        for i, name in enumerate(["module.walk", "module.even", "module.odd", "module.main", "module.helper"]):
+            graph.add_node(Node(name, NodeType.FUNCTION, "/file.py", i * 10, i * 10 + 5))
+        graph.add_edge("module.main", "module.walk")
+        graph.add_edge("module.walk", "module.walk")
+        graph.add_edge("module.main", "module.even")
+        graph.add_edge("module.even", "module.odd")
+        graph.add_edge("module.odd", "module.even")
+        graph.add_edge("module.odd", "module.helper")
+
+        self.assertEqual(graph.recursive_cycles(), [["module.even", "module.odd"], ["module.walk"]])
+        self.assertEqual([edge.recursive for edge in graph.edges], [False, True, False, False, False, False])
+
     def test_to_networkx(self):
         # Test converting to NetworkX graph
         graph = CallGraph()
@@ -769,6 +783,25 @@ class TestCallGraph(unittest.TestCase):
         self.assertIn("pkg.mod.bar", graph2.nodes)
         self.assertTrue(graph2.has_node("bar"))
 
+    def test_llm_edge_kinds_select_prompt_edges(self):
+        graph = CallGraph()
+        for name in ("a.Base", "a.run", "b.Impl", "b.helper"):
+            graph.add_node(Node(name, NodeType.FUNCTION, f"/{name[0]}.py", len(name), len(name) + 1))
+        graph.add_edge("a.run", "b.helper")
+        graph.add_reference_edge("b.Impl", "a.Base", EdgeKind.INHERITS)
+        result = ClusterResult(clusters={1: {"a.Base", "a.run"}, 2: {"b.Impl", "b.helper"}})
+
+        default = graph.to_cluster_string(cluster_result=result)
+        self.assertIn("a.run -> b.helper\n", default)
+        self.assertNotIn("b.Impl -> a.Base", default)
+
+        inherits_only = graph.to_cluster_string(cluster_result=result, edge_kinds=["inherits"])
+        self.assertIn("b.Impl -> a.Base [inherits]", inherits_only)
+        self.assertNotIn("a.run -> b.helper", inherits_only)
+        # Clustering and call relations still see every edge.
+        self.assertTrue(graph.clustering_networkx().has_edge("b.Impl", "a.Base"))
+        self.assertEqual(len(graph.edges), 1)
+
 
 class TestDetectCommunitiesDeterminism(unittest.TestCase):
     """Property test: same input + same seed -> byte-equal output.
@@ -13,6 +13,10 @@ from static_analyzer.engine.symbol_table import SymbolTable
 # Use absolute path so file_path.as_uri() works and uri_to_path() round-trips
 MOD_PATH = Path("/tmp/test_project/mod.py")
 MOD_PHP_PATH = Path("/tmp/test_project/mod.php")
+MOD_EX_PATH = Path("/tmp/test_project/mod.ex")
+MOD_GO_PATH = Path("/tmp/test_project/animals.go")
+MOD_SWIFT_PATH = Path("/tmp/test_project/Sources/Zoo/Animals.swift")
+MOD_SCALA_PATH = Path("/tmp/test_project/src/main/scala/zoo/Animals.scala")
 
 
 def _sym(
@@ -41,6 +45,8 @@ def _make_adapter() -> MagicMock:
     adapter = MagicMock()
     adapter.is_callable.side_effect = lambda k: k in (NodeType.FUNCTION, NodeType.METHOD)
     adapter.is_class_like.side_effect = lambda k: k == NodeType.CLASS
+    adapter.interface_supertypes_are_embedded = False
+    adapter.has_companion_objects = False
     return adapter
 
 
@@ -255,6 +261,147 @@ class TestBuildWithSourceInference:
 
         assert "mod.Speakable" in hierarchy["mod.Dog"]["superclasses"]
 
+    def test_infers_elixir_protocol_implementations(self):
+        adapter = _make_adapter()
+        protocol = _sym("Speakable", "Speakable", NodeType.CLASS, fpath=MOD_EX_PATH, start_line=0)
+        dog = _sym("MyApp.Dog", "MyApp.Dog", NodeType.CLASS, fpath=MOD_EX_PATH, start_line=5)
+        impl = _sym("Speakable.MyApp.Dog", "Speakable.MyApp.Dog", NodeType.CLASS, fpath=MOD_EX_PATH, start_line=9)
+        st = _setup_symbol_table(adapter, [protocol, dog, impl])
+
+        lsp = MagicMock()
+        lsp.type_hierarchy_prepare.return_value = None
+
+        si = MagicMock(spec=SourceInspector)
+        si.get_source_line.side_effect = lambda fp, line: {
+            0: "defprotocol Speakable do",
+            5: "defmodule MyApp.Dog do",
+            9: "defimpl Speakable, for: MyApp.Dog do",
+        }.get(line)
+
+        builder = HierarchyBuilder(lsp, st, si, adapter)
+        hierarchy = builder.build()
+
+        assert hierarchy["Speakable.MyApp.Dog"]["superclasses"] == ["Speakable"]
+        assert hierarchy["MyApp.Dog"]["superclasses"] == ["Speakable"]
+        assert sorted(hierarchy["Speakable"]["subclasses"]) == ["MyApp.Dog", "Speakable.MyApp.Dog"]
+
+    def test_links_elixir_behaviours_as_implementations(self):
+        adapter = _make_adapter()
+        adapter.is_class_like.side_effect = lambda k: k == NodeType.MODULE
+        adapter.declares_behaviours = True
+        storage = _sym("MyApp.Storage", "MyApp.Storage", NodeType.MODULE, MOD_EX_PATH, start_line=0, end_line=3)
+        local = _sym("MyApp.Local", "MyApp.Local", NodeType.MODULE, MOD_EX_PATH, start_line=5, end_line=11)
+        st = _setup_symbol_table(adapter, [storage, local])
+
+        lsp = MagicMock()
+        lsp.type_hierarchy_prepare.return_value = None
+
+        si = MagicMock(spec=SourceInspector)
+        si.get_source_line.return_value = None
+        si.get_file_lines.return_value = [
+            "defmodule MyApp.Storage do",
+            "  @callback put(key :: term, value :: term) :: :ok",
+            "  @callback get(key :: term) :: term",
+            "end",
+            "",
+            "defmodule MyApp.Local do",
+            "  use GenServer",
+            "  alias MyApp.Storage",
+            "  @behaviour Storage",
+            "",
+            "  def put(key, value), do: GenServer.call(__MODULE__, {:put, key, value})",
+            "end",
+        ]
+
+        hierarchy = HierarchyBuilder(lsp, st, si, adapter).build()
+
+        assert hierarchy["MyApp.Local"]["interfaces"] == ["MyApp.Storage"]
+        assert hierarchy["MyApp.Storage"]["implementations"] == ["MyApp.Local"]
+        assert hierarchy["MyApp.Local"]["superclasses"] == []
+        # GenServer is not in the project: kept by name so the callback module stays recognizable.
+        assert hierarchy["MyApp.Local"]["behaviours"] == ["GenServer"]
+
+    def test_infers_swift_conformances_as_implementations(self):
+        adapter = _make_adapter()
+        adapter.is_class_like.side_effect = lambda k: k in (NodeType.CLASS, NodeType.STRUCT, NodeType.INTERFACE)
+        adapter.resolves_interface_implementations = False
+        adapter.interface_supertypes_are_implementations = True
+        symbols = [
+            _sym("Speakable", "Zoo.Speakable", NodeType.INTERFACE, MOD_SWIFT_PATH, start_line=0),
+            _sym("Pet", "Zoo.Pet", NodeType.INTERFACE, MOD_SWIFT_PATH, start_line=2),
+            _sym("Animal", "Zoo.Animal", NodeType.CLASS, MOD_SWIFT_PATH, start_line=4),
+            _sym("Dog", "Zoo.Dog", NodeType.CLASS, MOD_SWIFT_PATH, start_line=6),
+            _sym("Cat", "Zoo.Cat", NodeType.STRUCT, MOD_SWIFT_PATH, start_line=8),
+            _sym("Cat", "Zoo.Cat<extension:Animals>", NodeType.NAMESPACE, MOD_SWIFT_PATH, start_line=10),
+        ]
+        st = _setup_symbol_table(adapter, symbols)
+
+        lsp = MagicMock()
+        lsp.type_hierarchy_prepare.return_value = None
+
+        si = MagicMock(spec=SourceInspector)
+        si.get_source_line.side_effect = lambda fp, line: {
+            0: "public protocol Speakable {",
+            2: "protocol Pet: Speakable {",
+            4: "open class Animal {",
+            6: "final class Dog: Animal, Speakable {",
+            8: "struct Cat<Food: Edible> {",
+            10: "extension Cat: Pet where Food == Fish {",
+        }.get(line)
+
+        hierarchy = HierarchyBuilder(lsp, st, si, adapter).build()
+
+        assert hierarchy["Zoo.Dog"]["superclasses"] == ["Zoo.Animal"]
+        assert hierarchy["Zoo.Dog"]["interfaces"] == ["Zoo.Speakable"]
+        assert hierarchy["Zoo.Cat"]["interfaces"] == ["Zoo.Pet"]
+        assert hierarchy["Zoo.Speakable"]["implementations"] == ["Zoo.Dog"]
+        # A protocol refining another still inherits from it.
+        assert hierarchy["Zoo.Pet"]["superclasses"] == ["Zoo.Speakable"]
+        assert hierarchy["Zoo.Speakable"]["subclasses"] == ["Zoo.Pet"]
+
+    def test_infers_scala_mixins_and_companions(self):
+        adapter = _make_adapter()
+        adapter.is_class_like.side_effect = lambda k: k in (NodeType.CLASS, NodeType.INTERFACE, NodeType.MODULE)
+        adapter.resolves_interface_implementations = False
+        adapter.interface_supertypes_are_implementations = True
+        adapter.interface_supertypes_are_embedded = True
+        adapter.has_companion_objects = True
+        symbols = [
+            _sym("Speakable", "zoo.Speakable", NodeType.INTERFACE, MOD_SCALA_PATH, start_line=0),
+            _sym("Pet", "zoo.Pet", NodeType.INTERFACE, MOD_SCALA_PATH, start_line=1),
+            _sym("Animal", "zoo.Animal", NodeType.CLASS, MOD_SCALA_PATH, start_line=2),
+            _sym("Dog", "zoo.Dog", NodeType.CLASS, MOD_SCALA_PATH, start_line=3),
+            _sym("Dog", "zoo.Dog$", NodeType.MODULE, MOD_SCALA_PATH, start_line=6),
+        ]
+        st = _setup_symbol_table(adapter, symbols)
+
+        lsp = MagicMock()
+        lsp.type_hierarchy_prepare.return_value = None
+
+        lines = [
+            "trait Speakable:",
+            "trait Pet extends Speakable { def name: String }",
+            "abstract class Animal(val legs: Int)",
+            "final case class Dog(name: String)",
+            "    extends Animal(4)",
+            "    with Pet {",
+            "object Dog extends (String => Dog) {",
+        ]
+        si = MagicMock(spec=SourceInspector)
+        si.get_source_line.side_effect = lambda fp, line: lines[line] if line < len(lines) else None
+        si.get_file_lines.return_value = lines
+
+        hierarchy = HierarchyBuilder(lsp, st, si, adapter).build()
+
+        assert hierarchy["zoo.Dog"]["superclasses"] == ["zoo.Animal"]
+        assert hierarchy["zoo.Dog"]["interfaces"] == ["zoo.Pet"]
+        assert hierarchy["zoo.Pet"]["implementations"] == ["zoo.Dog"]
+        # A trait mixing in a trait embeds it rather than inheriting from it.
+        assert hierarchy["zoo.Pet"]["superclasses"] == []
+        assert hierarchy["zoo.Pet"]["embeds"] == ["zoo.Speakable"]
+        assert hierarchy["zoo.Speakable"]["subclasses"] == []
+        assert hierarchy["zoo.Dog"]["companion"] == "zoo.Dog$"
+
     def test_skips_metaclass_keyword_arg(self):
         adapter = _make_adapter()
         cls = _sym("Meta", "mod.Meta", NodeType.CLASS, start_line=0)
@@ -292,6 +439,54 @@ class TestBuildWithSourceInference:
         assert hierarchy["mod.A"]["superclasses"] == []
 
 
+class TestInterfaceImplementations:
+    """Go-style implicit interfaces resolved via ``textDocument/implementation``."""
+
+    def _build(self, implementations: dict[int, list[int]]) -> dict[str, dict]:
+        adapter = _make_adapter()
+        adapter.is_class_like.side_effect = lambda k: k in (NodeType.STRUCT, NodeType.INTERFACE)
+        adapter.resolves_interface_implementations = True
+        symbols = [
+            _sym("Speaker", "zoo.Speaker", NodeType.INTERFACE, MOD_GO_PATH, start_line=0),
+            _sym("Swimmer", "zoo.Swimmer", NodeType.INTERFACE, MOD_GO_PATH, start_line=5),
+            _sym("Dog", "zoo.Dog", NodeType.STRUCT, MOD_GO_PATH, start_line=10),
+            _sym("Duck", "zoo.Duck", NodeType.STRUCT, MOD_GO_PATH, start_line=20),
+        ]
+        st = _setup_symbol_table(adapter, symbols)
+
+        def locations(queries):
+            return [
+                [
+                    {"uri": MOD_GO_PATH.as_uri(), "range": {"start": {"line": line, "character": 5}}}
+                    for line in implementations.get(query_line, [])
+                ]
+                for _, query_line, _ in queries
+            ], set()
+
+        lsp = MagicMock()
+        lsp.type_hierarchy_prepare.side_effect = MethodNotFoundError("no type hierarchy")
+        lsp.send_implementation_batch.side_effect = locations
+        si = MagicMock()
+        si.get_source_line.return_value = None
+        return HierarchyBuilder(lsp, st, si, adapter).build()
+
+    def test_type_satisfying_several_interfaces_links_to_each(self):
+        # Speaker is satisfied by Dog and Duck; Swimmer by Duck only.
+        hierarchy = self._build({0: [10, 20], 5: [20]})
+
+        assert hierarchy["zoo.Dog"]["interfaces"] == ["zoo.Speaker"]
+        assert hierarchy["zoo.Duck"]["interfaces"] == ["zoo.Speaker", "zoo.Swimmer"]
+        assert hierarchy["zoo.Speaker"]["implementations"] == ["zoo.Dog", "zoo.Duck"]
+        assert hierarchy["zoo.Duck"]["superclasses"] == []
+
+    def test_embedding_interface_is_not_an_implementation(self):
+        # gopls also reports interfaces related to the queried one; only concrete types count.
+        hierarchy = self._build({0: [5, 10]})
+
+        assert hierarchy["zoo.Speaker"]["implementations"] == ["zoo.Dog"]
+        assert hierarchy["zoo.Swimmer"]["interfaces"] == []
+
+
 class TestResolveTypeHierarchyItem:
     def test_resolves_by_name_and_line(self):
         adapter = _make_adapter()
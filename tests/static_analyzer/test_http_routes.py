@@ -0,0 +1,127 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.go_imports import parse_imports
+from static_analyzer.graph import CallGraph, EdgeKind
+from static_analyzer.http_routes import add_route_edges, find_routes
+from static_analyzer.node import Node
+
+GIN_GO = """package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// r.GET("/commented", h.Nope) is not a route.
+func Register(r *gin.Engine, h *UserHandler) {
+	r.GET("/health", health)
+	v1 := r.Group("/v1")
+	{
+		v1.GET("/users/:id", auth, h.GetUser)
+		admin := v1.Group("/admin")
+		admin.DELETE("/users/:id", h.DeleteUser)
+	}
+	r.Handle("PATCH", "/legacy", h.Legacy)
+	r.GET("/inline", func(c *gin.Context) { c.Status(http.StatusOK) })
+}
+"""
+
+CHI_GO = """package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func (s *Server) routes() {
+	r := chi.NewRouter()
+	r.With(s.auth).Post("/login", s.login)
+	r.Route("/api", func(r chi.Router) {
+		r.Get("/items", s.listItems)
+		r.Route("/items/{id}", func(r chi.Router) {
+			r.Method("PUT", "/", http.HandlerFunc(s.putItem))
+		})
+	})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", getUser)
+	http.Handle("/static/", http.FileServer(http.Dir("static")))
+	http.HandleFunc("/metrics", metrics)
+}
+"""
+
+ECHO_GO = """package web
+
+import "github.com/labstack/echo/v4"
+
+func setup(e *echo.Echo) {
+	g := e.Group("/admin")
+	g.GET("/stats", stats, audit)
+	e.Add("POST", "/hooks", hook)
+}
+"""
+
+
+def _routes(source: str) -> list[tuple[str, str, str]]:
+    return [(route.method, route.path, route.handler) for route in find_routes(source, parse_imports(source))]
+
+
+def test_registrations_of_known_routers():
+    assert _routes(GIN_GO) == [
+        ("GET", "/health", "health"),
+        ("GET", "/v1/users/:id", "h.GetUser"),
+        ("DELETE", "/v1/admin/users/:id", "h.DeleteUser"),
+        ("PATCH", "/legacy", "h.Legacy"),
+    ]
+    assert _routes(CHI_GO) == [
+        ("POST", "/login", "s.login"),
+        ("GET", "/api/items", "s.listItems"),
+        ("PUT", "/api/items/{id}", "s.putItem"),
+        ("GET", "/users/{id}", "getUser"),
+        ("ANY", "/metrics", "metrics"),
+    ]
+    assert _routes(ECHO_GO) == [("GET", "/admin/stats", "stats"), ("POST", "/hooks", "hook")]
+
+
+def test_unknown_routers_are_ignored():
+    source = 'package x\n\nimport "github.com/gorilla/mux"\n\nfunc f(r *mux.Router) { r.HandleFunc("/x", h) }\n'
+
+    assert find_routes(source, parse_imports(source)) == []
+
+
+def test_routes_are_recorded_on_handlers_and_linked_from_registrars(tmp_path: Path):
+    api = tmp_path / "api" / "routes.go"
+    api.parent.mkdir()
+    api.write_text(GIN_GO)
+    handlers = tmp_path / "api" / "users.go"
+    handlers.write_text("package api\n")
+    cfg = CallGraph(language="go")
+    nodes = [
+        Node("api.routes.Register", NodeType.FUNCTION, str(api), 10, 20),
+        Node("api.routes.health", NodeType.FUNCTION, str(api), 22, 22),
+        Node("api.users.(*UserHandler).GetUser", NodeType.METHOD, str(handlers), 3, 5),
+        Node("api.users.(*UserHandler).DeleteUser", NodeType.METHOD, str(handlers), 7, 9),
+        Node("api.users.(*AdminHandler).DeleteUser", NodeType.METHOD, str(handlers), 11, 13),
+    ]
+    for node in nodes:
+        cfg.add_node(node)
+
+    linked = add_route_edges(cfg, [str(api), str(handlers)])
+
+    # ``h.Legacy`` names no method of the project and is skipped; ``h`` types DeleteUser as UserHandler's.
+    assert linked == [
+        ("api.routes.Register", "api.routes.health", "GET /health"),
+        ("api.routes.Register", "api.users.(*UserHandler).GetUser", "GET /v1/users/:id"),
+        ("api.routes.Register", "api.users.(*UserHandler).DeleteUser", "DELETE /v1/admin/users/:id"),
+    ]
+    assert cfg.nodes["api.users.(*UserHandler).GetUser"].http_routes == ("GET /v1/users/:id",)
+    assert cfg.nodes["api.users.(*AdminHandler).DeleteUser"].http_routes == ()
+    assert ("api.routes.Register", "api.routes.health", str(EdgeKind.ROUTE)) in cfg.reference_edges
+    assert cfg.edges == []
+
+    # A re-run starts over: a removed registration drops its route.
+    api.write_text(GIN_GO.replace('r.GET("/health", health)', ""))
+    add_route_edges(cfg, [str(api), str(handlers)])
+    assert cfg.nodes["api.routes.health"].http_routes == ()
@@ -0,0 +1,108 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.interface_dispatch import add_interface_dispatch_edges, narrowed_type
+from static_analyzer.node import Node
+
+ANIMALS_GO = """package zoo
+
+type Speaker interface {
+	Speak() string
+}
+
+type Dog struct{}
+
+func (d Dog) Speak() string { return "woof" }
+
+type Cat struct{}
+
+func (c *Cat) Speak() string { return "meow" }
+
+func Narrowed() string {
+	dog := Dog{}
+	var speaker Speaker = dog
+	return speaker.Speak()
+}
+
+func Unknown(speaker Speaker) string {
+	return speaker.Speak()
+}
+"""
+
+HIERARCHY = {"zoo.Speaker": {"implementations": ["zoo.Dog", "zoo.Cat"]}}
+
+
+def _line(text: str) -> int:
+    return next(i for i, line in enumerate(ANIMALS_GO.splitlines(), start=1) if text in line)
+
+
+def _site(path: Path, text: str) -> dict:
+    line = _line(text)
+    column = ANIMALS_GO.splitlines()[line - 1].index("Speak()") + 1
+    return {"file": str(path), "line": line, "column": column}
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, Path]:
+    path = tmp_path / "zoo" / "animals.go"
+    path.parent.mkdir()
+    path.write_text(ANIMALS_GO)
+    graph = CallGraph(language="go")
+    file_path = str(path)
+    graph.add_node(Node("zoo.Speaker", NodeType.INTERFACE, file_path, _line("type Speaker"), _line("type Speaker") + 2))
+    graph.add_node(Node("zoo.animals.(Speaker).Speak", NodeType.METHOD, file_path, 4, 4))
+    graph.add_node(Node("zoo.Dog", NodeType.CLASS, file_path, _line("type Dog"), _line("type Dog")))
+    graph.add_node(Node("zoo.animals.(Dog).Speak", NodeType.METHOD, file_path, _line("(d Dog)"), _line("(d Dog)")))
+    graph.add_node(Node("zoo.Cat", NodeType.CLASS, file_path, _line("type Cat"), _line("type Cat")))
+    graph.add_node(Node("zoo.animals.(*Cat).Speak", NodeType.METHOD, file_path, _line("(c *Cat)"), _line("(c *Cat)")))
+    narrowed = _line("func Narrowed")
+    graph.add_node(Node("zoo.Narrowed", NodeType.FUNCTION, file_path, narrowed, narrowed + 4))
+    graph.add_node(Node("zoo.Unknown", NodeType.FUNCTION, file_path, _line("func Unknown"), _line("func Unknown") + 2))
+    return graph, path
+
+
+def _callees(graph: CallGraph, caller: str) -> set[str]:
+    return {edge.get_destination() for edge in graph.edges if edge.get_source() == caller}
+
+
+def test_assignment_narrows_call_to_one_implementation(tmp_path: Path):
+    graph, path = _graph(tmp_path)
+    graph.add_edge("zoo.Narrowed", "zoo.animals.(Speaker).Speak", [_site(path, "return speaker.Speak()")])
+
+    added = add_interface_dispatch_edges(graph, HIERARCHY)
+
+    assert added == 1
+    assert _callees(graph, "zoo.Narrowed") == {"zoo.animals.(Speaker).Speak", "zoo.animals.(Dog).Speak"}
+
+
+def test_unknown_receiver_fans_out_to_every_implementation(tmp_path: Path):
+    graph, path = _graph(tmp_path)
+    site = {"file": str(path), "line": _line("func Unknown") + 1, "column": 17}
+    graph.add_edge("zoo.Unknown", "zoo.animals.(Speaker).Speak", [site])
+
+    added = add_interface_dispatch_edges(graph, HIERARCHY)
+
+    assert added == 2
+    assert _callees(graph, "zoo.Unknown") == {
+        "zoo.animals.(Speaker).Speak",
+        "zoo.animals.(Dog).Speak",
+        "zoo.animals.(*Cat).Speak",
+    }
+    dispatched = next(edge for edge in graph.edges if edge.get_destination() == "zoo.animals.(*Cat).Speak")
+    assert dispatched.call_sites == [site]
+
+
+def test_no_implementations_adds_nothing(tmp_path: Path):
+    graph, path = _graph(tmp_path)
+    graph.add_edge("zoo.Unknown", "zoo.animals.(Speaker).Speak", [])
+
+    assert add_interface_dispatch_edges(graph, {}) == 0
+    assert _callees(graph, "zoo.Unknown") == {"zoo.animals.(Speaker).Speak"}
+
+
+def test_narrowed_type_reads_pointer_literals_and_new():
+    lines = ["func F() {", "\ts := &zoo.Cat{}", "\tt := new(Dog)", "\ts.Speak()", "\tt.Speak()", "}"]
+
+    assert narrowed_type(lines, 1, 4, "s") == "Cat"
+    assert narrowed_type(lines, 1, 5, "t") == "Dog"
+    assert narrowed_type(lines, 1, 4, "missing") is None
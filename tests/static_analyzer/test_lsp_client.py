@@ -5,7 +5,9 @@ without starting a real LSP server.
 """
 
 import json
+import logging
 import os
+import re
 from pathlib import Path
 from unittest.mock import MagicMock, patch
 
@@ -16,6 +18,7 @@ from static_analyzer.engine.lsp_client import (
     LSPClient,
     MethodNotFoundError,
 )
+from static_analyzer.engine.lsp_dump import LSPDumpRecorder
 
 
 class TestLSPClientInit:
@@ -322,6 +325,45 @@ class TestSendReferencesBatch:
         assert error_indices == {1}
 
 
+class TestSendDocumentSymbolBatch:
+    def test_pipelines_requests_and_keeps_file_order(self):
+        client = LSPClient(["cmd"], Path("/root"), default_timeout=20)
+        symbols_a = [{"name": "a", "kind": 12}]
+        written: list[dict] = []
+
+        def mock_collect(req_ids, timeout=None):
+            # Two files, two per-request timeouts.
+            assert timeout == 40
+            return {req_ids[1]: [], req_ids[0]: symbols_a}, set(), set()
+
+        with (
+            patch.object(client, "_write_message", side_effect=written.append),
+            patch.object(client, "_collect_batch_responses", side_effect=mock_collect),
+        ):
+            results = client.send_document_symbol_batch([Path("/root/a.py"), Path("/root/b.py")])
+
+        assert results == [symbols_a, []]
+        assert [m["method"] for m in written] == ["textDocument/documentSymbol"] * 2
+        assert written[1]["params"] == {"textDocument": {"uri": Path("/root/b.py").resolve().as_uri()}}
+
+    def test_retries_errored_files_one_at_a_time(self):
+        client = LSPClient(["cmd"], Path("/root"), default_timeout=20)
+        symbols_b = [{"name": "b", "kind": 12}]
+
+        def mock_collect(req_ids, timeout=None):
+            return {req_ids[0]: [], req_ids[1]: []}, set(), {req_ids[1]}
+
+        with (
+            patch.object(client, "_write_message"),
+            patch.object(client, "_collect_batch_responses", side_effect=mock_collect),
+            patch.object(client, "document_symbol", return_value=symbols_b) as document_symbol,
+        ):
+            results = client.send_document_symbol_batch([Path("/root/a.py"), Path("/root/b.py")])
+
+        assert results == [[], symbols_b]
+        document_symbol.assert_called_once_with(Path("/root/b.py"))
+
+
 class TestTypeHierarchy:
     def test_prepare_returns_list(self):
         client = LSPClient(["cmd"], Path("/root"))
@@ -358,6 +400,38 @@ class TestTypeHierarchy:
         assert result == []
 
 
+class TestLSPDump:
+    def test_records_raw_responses_per_file_namespaced_by_language(self, tmp_path: Path):
+        project = tmp_path / "repo"
+        (project / "pkg").mkdir(parents=True)
+        recorder = LSPDumpRecorder(tmp_path / "dump" / "python", "Python", project, ["pyright-langserver"])
+        recorder.server_info = {"name": "pyright", "version": "1.1"}
+        client = LSPClient(["cmd"], project, dump_recorder=recorder)
+        symbols = [{"name": "f", "kind": 12, "range": {}}]
+        refs = [{"uri": (project / "pkg" / "b.py").as_uri(), "range": {}}]
+
+        def mock_collect(req_ids, timeout=None):
+            return {req_ids[0]: refs}, set(), set()
+
+        with (
+            patch.object(client, "_write_message"),
+            patch.object(client, "_next_response", return_value={"id": 1, "result": symbols}),
+            patch.object(client, "_collect_batch_responses", side_effect=mock_collect),
+        ):
+            client.document_symbol(project / "pkg" / "a.py")
+            client.send_references_batch([(project / "pkg" / "a.py", 3, 4)])
+
+        assert recorder.write() == 1
+        server = json.loads((tmp_path / "dump" / "python" / "server.json").read_text())
+        assert server["format_version"] == 1 and server["server_info"]["name"] == "pyright"
+        dumped = json.loads((tmp_path / "dump" / "python" / "files" / "pkg" / "a.py.json").read_text())
+        assert dumped["file"] == "pkg/a.py"
+        assert dumped["responses"]["textDocument/documentSymbol"][0]["result"] == symbols
+        reference = dumped["responses"]["textDocument/references"][0]
+        assert reference["params"]["position"] == {"line": 3, "character": 4}
+        assert reference["result"] == refs
+
+
 class TestSendRequest:
     def test_returns_result(self):
         client = LSPClient(["cmd"], Path("/root"))
@@ -439,6 +513,18 @@ class TestSendRequest:
         result = client._send_request("test/method", {}, timeout=5)
         assert result == "correct"
 
+    def test_logs_method_and_duration_at_debug(self, caplog):
+        client = LSPClient(["cmd"], Path("/root"))
+        client._process = MagicMock()
+        client._process.poll.return_value = None
+        client._msg_queue.put({"jsonrpc": "2.0", "id": 1, "result": []})
+
+        with caplog.at_level(logging.DEBUG, logger="static_analyzer.engine.lsp_client"):
+            client._send_request("textDocument/references", {}, timeout=5)
+
+        assert re.search(r"LSP textDocument/references #1 ok in [\d.]+ ms", caplog.text)
+        assert client._in_flight == {}
+
 
 class TestCollectBatchResponses:
     def test_collects_all_responses(self):
@@ -506,6 +592,76 @@ class TestCollectBatchResponses:
         assert "x3" in error_lines[0].message
 
 
+def _written(stdin: MagicMock) -> list[dict]:
+    """The JSON-RPC messages written to a mocked server stdin."""
+    return [json.loads(call.args[0].split(b"\r\n\r\n", 1)[1]) for call in stdin.write.call_args_list]
+
+
+def _document_request(req_id: int, file_path: Path) -> dict:
+    params = {"textDocument": {"uri": file_path.as_uri()}}
+    return {"jsonrpc": "2.0", "id": req_id, "method": "textDocument/documentSymbol", "params": params}
+
+
+class TestTimeouts:
+    def test_timed_out_request_is_cancelled_and_its_file_marked(self):
+        client = LSPClient(["cmd"], Path("/root"))
+        client._process = MagicMock()
+        client._process.poll.return_value = None
+
+        with pytest.raises(TimeoutError):
+            client.document_symbol(Path("/root/slow.go"), timeout=1)
+
+        assert {"jsonrpc": "2.0", "method": "$/cancelRequest", "params": {"id": 1}} in _written(client._process.stdin)
+        assert client.pop_timed_out_files() == {Path("/root/slow.go").resolve()}
+        assert client.timed_out_files == set()
+
+    def test_server_restarts_after_consecutive_timeouts(self):
+        client = LSPClient(["cmd"], Path("/root"), restart_after_timeouts=2)
+        client._process = MagicMock()
+        client._process.poll.return_value = None
+
+        with patch.object(client, "restart") as restart:
+            client._write_message(_document_request(1, Path("/root/a.go")))
+            client._collect_batch_responses([1], timeout=1)
+            # A response in between: the server is not hung.
+            client._write_message(_document_request(2, Path("/root/b.go")))
+            client._msg_queue.put({"jsonrpc": "2.0", "id": 2, "result": []})
+            client._collect_batch_responses([2], timeout=1)
+            client._write_message(_document_request(3, Path("/root/c.go")))
+            client._collect_batch_responses([3], timeout=1)
+            restart.assert_not_called()
+
+            client._write_message(_document_request(4, Path("/root/d.go")))
+            client._collect_batch_responses([4], timeout=1)
+            restart.assert_called_once()
+
+        assert client.timed_out_files == {Path("/root/a.go"), Path("/root/c.go"), Path("/root/d.go")}
+
+    def test_restart_reopens_open_documents(self, tmp_path: Path):
+        source = tmp_path / "main.go"
+        source.write_text("package main\n")
+        client = LSPClient(["cmd"], tmp_path)
+        client._process = MagicMock()
+        client._process.poll.return_value = None
+        client.did_open(source, "go")
+        old_process = client._process
+
+        def start():
+            client._process = MagicMock()
+            client._process.poll.return_value = None
+
+        with patch.object(client, "shutdown", side_effect=client._opened_uris.clear), patch.object(
+            client, "start", side_effect=start
+        ):
+            client.restart()
+
+        assert client._process is not old_process
+        (reopened,) = _written(client._process.stdin)
+        assert reopened["method"] == "textDocument/didOpen"
+        assert reopened["params"]["textDocument"]["uri"] == source.resolve().as_uri()
+        assert reopened["params"]["textDocument"]["languageId"] == "go"
+
+
 class TestHandleNotification:
     def test_diagnostics_notification(self):
         client = LSPClient(["cmd"], Path("/root"), collect_diagnostics=True)
@@ -0,0 +1,133 @@
+from pathlib import Path
+from typing import cast
+from unittest.mock import MagicMock, patch
+
+from project_config import load_project_config
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer import EngineConfig, StaticAnalyzer, _create_engine_configs
+from static_analyzer.constants import Language
+from static_analyzer.engine.adapters.go_adapter import GoAdapter
+from static_analyzer.engine.language_adapter import LanguageAdapter
+from static_analyzer.path_overrides import PathOverrides
+from static_analyzer.programming_language import ProgrammingLanguage
+
+CONFIG_TOML = """\
+[[languages.paths]]
+glob = "services/go/**"
+language = "go"
+build_tags = ["integration", "linux"]
+env = { GOFLAGS = "-mod=vendor" }
+
+[[languages.paths]]
+glob = "web/**"
+language = "TypeScript"
+root = "web/app"
+
+[[languages.paths]]
+glob = "legacy/**"
+language = "cobol"
+
+[[languages.paths]]
+glob = "scripts/**"
+language = "python"
+build_tags = ["x"]
+"""
+
+
+def _repo(tmp_path: Path) -> Path:
+    for rel in (
+        "services/go/cmd/main.go",
+        "services/go/tools/gen.py",
+        "cli/main.go",
+        "tools/build.py",
+        "web/app/src/index.ts",
+        "web/shared/util.ts",
+    ):
+        (tmp_path / rel).parent.mkdir(parents=True, exist_ok=True)
+        (tmp_path / rel).write_text("")
+    (tmp_path / ".codeboarding").mkdir()
+    (tmp_path / ".codeboarding" / "config.toml").write_text(CONFIG_TOML)
+    return tmp_path.resolve()
+
+
+def test_entries_are_parsed_from_the_project_config(tmp_path: Path):
+    overrides = PathOverrides.from_project_config(load_project_config(_repo(tmp_path)))
+
+    go, web, scripts = overrides.entries
+    assert (go.language, go.root, go.build_tags, go.env) == (
+        Language.GO,
+        "services/go",
+        ("integration", "linux"),
+        {"GOFLAGS": "-mod=vendor"},
+    )
+    assert (web.language, web.root) == (Language.TYPESCRIPT, "web/app")
+    # Unknown languages are skipped; build tags only apply to Go.
+    assert (scripts.language, scripts.build_tags) == (Language.PYTHON, ())
+
+
+def test_owner_needs_glob_root_and_extension(tmp_path: Path):
+    repo = _repo(tmp_path)
+    overrides = PathOverrides.from_project_config(load_project_config(repo))
+    go, web, _ = overrides.entries
+
+    assert overrides.owner(repo, repo / "services/go/cmd/main.go") is go
+    assert overrides.owner(repo, repo / "services/go/tools/gen.py") is None
+    assert overrides.owner(repo, repo / "web/app/src/index.ts") is web
+    # Under the glob but outside the entry's root.
+    assert overrides.owner(repo, repo / "web/shared/util.ts") is None
+    assert overrides.owner(repo, repo / "cli/main.go") is None
+
+
+def test_overrides_take_their_files_from_the_detected_configs(tmp_path: Path):
+    repo = _repo(tmp_path)
+    overrides = PathOverrides.from_project_config(load_project_config(repo))
+    detected = [
+        ProgrammingLanguage("Go", 10, 50.0, [".go"], server_commands=["gopls"]),
+        ProgrammingLanguage("Python", 10, 50.0, [".py"], server_commands=["pyright"]),
+    ]
+
+    configs = _create_engine_configs(detected, repo, RepoIgnoreManager(repo), path_overrides=overrides)
+
+    by_root = {
+        (c.adapter.language, c.project_path.relative_to(repo).as_posix()): (
+            sorted(f.relative_to(repo).as_posix() for f in c.source_files),
+            c.path_override,
+        )
+        for c in configs
+    }
+    assert by_root == {
+        ("Go", "."): (["cli/main.go"], None),
+        ("Python", "."): ([], None),
+        ("Go", "services/go"): (["services/go/cmd/main.go"], overrides.entries[0]),
+        ("TypeScript", "web/app"): (["web/app/src/index.ts"], overrides.entries[1]),
+    }
+
+
+def test_clients_start_with_the_override_build_tags_and_env(tmp_path: Path):
+    repo = _repo(tmp_path)
+    with patch("static_analyzer.ProjectScanner") as scanner_cls:
+        scanner_cls.return_value.scan.return_value = []
+        analyzer = StaticAnalyzer(repo)
+    go_override = analyzer.path_overrides.entries[0]
+    adapter = MagicMock(name="GoAdapter")
+    adapter.language = "Go"
+    adapter.wait_for_workspace_ready = False
+    adapter.get_lsp_command.return_value = ["gopls", "serve"]
+    adapter.get_lsp_init_options.return_value = {"directoryFilters": []}
+    adapter.get_lsp_env.return_value = {"GOGC": "50"}
+    adapter.get_workspace_settings.return_value = {"ui.diagnostic.staticcheck": True}
+    adapter.get_build_tag_settings.side_effect = GoAdapter().get_build_tag_settings
+    analyzer._engine_configs = [
+        EngineConfig(cast(LanguageAdapter, adapter), repo / "services/go", path_override=go_override)
+    ]
+
+    with patch("static_analyzer.LSPClient") as client_cls:
+        analyzer.start_clients()
+
+    kwargs = client_cls.call_args.kwargs
+    assert kwargs["init_options"] == {"directoryFilters": [], "buildFlags": ["-tags=integration,linux"]}
+    assert kwargs["workspace_settings"] == {
+        "ui.diagnostic.staticcheck": True,
+        "buildFlags": ["-tags=integration,linux"],
+    }
+    assert kwargs["extra_env"] == {"GOGC": "50", "GOFLAGS": "-mod=vendor"}
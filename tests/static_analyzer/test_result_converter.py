@@ -452,6 +452,19 @@ class TestCallGraphConversion:
         assert "mod.handler" in cg.nodes
         assert "mod.process" in cg.nodes
 
+    def test_class_like_modules_are_nodes_tagged_with_external_behaviours(self):
+        """Elixir modules are class-like without being a graph node type; ``use GenServer`` tags them."""
+        adapter = _make_adapter()
+        adapter.is_class_like.side_effect = lambda k: k in (NodeType.CLASS, NodeType.MODULE)
+        st = SymbolTable(adapter)
+        _register(st, [_lsp_sym("Worker", NodeType.MODULE, 0, 10), _lsp_sym("Config", NodeType.NAMESPACE, 12, 14)])
+        hierarchy = {"mod.Worker": {"superclasses": [], "subclasses": [], "behaviours": ["GenServer"]}}
+        out = convert_to_codeboarding_format(st, LanguageAnalysisResult(hierarchy=hierarchy), adapter)
+
+        cg = out["call_graph"]
+        assert cg.nodes["mod.Worker"].standard_interfaces == ("GenServer",)
+        assert "mod.Config" not in cg.nodes
+
     def test_reference_reuses_graph_node(self):
         """If a symbol is both in the graph and reference-worthy, the same Node is reused."""
         adapter = _make_adapter()
@@ -643,6 +656,27 @@ def test_add_reference_edges_contains_and_inherits():
     assert not any(s == "mod.helper" and k == str(EdgeKind.CONTAINS) for s, d, k in cg.reference_edges)
 
 
+def test_add_reference_edges_implements_from_interfaces():
+    """Go types link to each interface they satisfy, not as inheritance."""
+    from static_analyzer.engine.result_converter import _add_reference_edges
+    from static_analyzer.graph import CallGraph, EdgeKind
+    from static_analyzer.node import Node
+
+    cg = CallGraph(language="go")
+    for i, (qname, kind) in enumerate((("zoo.Speaker", NodeType.INTERFACE), ("zoo.Duck", NodeType.STRUCT))):
+        cg.add_node(Node(qname, kind, "zoo.go", i * 10 + 1, i * 10 + 5))
+
+    result = LanguageAnalysisResult(
+        hierarchy={
+            "zoo.Duck": {"superclasses": [], "subclasses": [], "interfaces": ["zoo.Speaker"], "implementations": []},
+            "zoo.Speaker": {"superclasses": [], "subclasses": [], "interfaces": [], "implementations": ["zoo.Duck"]},
+        }
+    )
+    _add_reference_edges(cg, result)
+
+    assert cg.reference_edges == [("zoo.Duck", "zoo.Speaker", str(EdgeKind.IMPLEMENTS))]
+
+
 def test_clustering_networkx_includes_configured_reference_kinds():
     from static_analyzer.graph import CallGraph, EdgeKind
     from static_analyzer.node import Node
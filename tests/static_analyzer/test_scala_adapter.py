@@ -0,0 +1,164 @@
+"""Tests for the Scala language adapter."""
+
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.engine.adapters import get_adapter
+from static_analyzer.engine.adapters.scala_adapter import COMPANION_DETAIL, ScalaAdapter
+from static_analyzer.engine.edge_build_context import EdgeBuildContext
+from static_analyzer.engine.edge_builder import EdgeMap, _process_references_for_position
+from static_analyzer.engine.models import SymbolInfo
+from static_analyzer.engine.source_inspector import SourceInspector
+from static_analyzer.engine.symbol_table import SymbolTable
+
+ROOT = Path("/tmp/scala_project")
+USER_SOURCE = """package com.acme
+package accounts
+
+final case class User(name: String)
+
+object User:
+  def apply(name: String, admin: Boolean): User = User(name)
+"""
+
+
+def _symbol(name: str, kind: int, start: int, end: int, children: list[dict] | None = None) -> dict:
+    symbol = {
+        "name": name,
+        "kind": kind,
+        "range": {"start": {"line": start, "character": 0}, "end": {"line": end, "character": 1}},
+        "selectionRange": {"start": {"line": start, "character": 6}, "end": {"line": start, "character": 10}},
+    }
+    if children is not None:
+        symbol["children"] = children
+    return symbol
+
+
+class TestScalaAdapterProperties:
+    def test_registered_with_scala_defaults(self):
+        adapter = get_adapter("Scala")
+        assert isinstance(adapter, ScalaAdapter)
+        assert adapter.language_enum is Language.SCALA
+        assert adapter.file_extensions == (".scala", ".sc")
+        assert adapter.language_id == "scala"
+        assert adapter.lsp_command == ["metals"]
+        assert adapter.interface_supertypes_are_implementations
+        assert adapter.interface_supertypes_are_embedded
+        assert adapter.has_companion_objects
+        assert adapter.is_class_like(NodeType.MODULE)
+
+    def test_missing_java_fails_fast(self):
+        with patch("static_analyzer.engine.adapters.scala_adapter.shutil.which", return_value=None):
+            with pytest.raises(RuntimeError, match="java not found"):
+                ScalaAdapter().get_lsp_command(ROOT)
+
+    def test_build_import_is_not_left_to_a_prompt(self):
+        assert ScalaAdapter().get_workspace_settings() == {"metals": {"autoImportBuild": "all"}}
+
+    def test_case_clauses_are_decision_points_but_case_classes_are_not(self):
+        pattern = ScalaAdapter().decision_point_pattern
+        source = "case class A(x: Int); x match { case 1 if ok => a; case _ => b }; for (y <- ys) yield y"
+        assert len(pattern.findall(source)) == 4
+
+
+class TestNormalizeDocumentSymbols:
+    def test_object_beside_a_type_is_its_companion(self):
+        symbols = ScalaAdapter().normalize_document_symbols(
+            [
+                _symbol("User", NodeType.CLASS, 3, 3),
+                _symbol("User", NodeType.MODULE, 5, 6),
+                _symbol("Users", NodeType.MODULE, 8, 9),
+            ]
+        )
+
+        assert symbols[1]["detail"] == COMPANION_DETAIL
+        assert "detail" not in symbols[2]
+
+    def test_multi_line_values_become_methods_outside_callables(self):
+        original = _symbol(
+            "Routes",
+            NodeType.MODULE,
+            0,
+            20,
+            [
+                _symbol("port", NodeType.CONSTANT, 1, 1),
+                _symbol("handler", NodeType.VARIABLE, 2, 6),
+                _symbol("run", NodeType.METHOD, 7, 12, [_symbol("total", NodeType.VARIABLE, 8, 10)]),
+            ],
+        )
+
+        (routes,) = ScalaAdapter().normalize_document_symbols([original])
+
+        kinds = [child["kind"] for child in routes["children"]]
+        assert kinds == [NodeType.CONSTANT, NodeType.METHOD, NodeType.METHOD]
+        assert routes["children"][2]["children"][0]["kind"] == NodeType.VARIABLE
+        assert original["children"][1]["kind"] == NodeType.VARIABLE
+
+
+class TestQualifiedNames:
+    def test_names_follow_the_declared_package(self, tmp_path: Path):
+        user = tmp_path / "src" / "main" / "scala" / "User.scala"
+        user.parent.mkdir(parents=True)
+        user.write_text(USER_SOURCE)
+        script = tmp_path / "build.sc"
+        script.write_text("def compile = 1\n")
+        adapter = ScalaAdapter()
+        name = adapter.build_qualified_name
+
+        assert name(user, "User", NodeType.CLASS, [], tmp_path) == "com.acme.accounts.User"
+        assert name(user, "User", NodeType.MODULE, [], tmp_path, COMPANION_DETAIL) == "com.acme.accounts.User$"
+        assert name(user, "User", NodeType.CLASS, [("com.acme.accounts", NodeType.PACKAGE)], tmp_path) == (
+            "com.acme.accounts.User"
+        )
+        assert name(script, "compile", NodeType.METHOD, [], tmp_path) == "build.compile"
+        assert adapter.get_package_for_file(user, tmp_path) == "com.acme.accounts"
+
+    def test_companion_members_land_on_the_class(self, tmp_path: Path):
+        user = tmp_path / "User.scala"
+        user.write_text(USER_SOURCE)
+        adapter = ScalaAdapter()
+        table = SymbolTable(adapter)
+        symbols = [
+            _symbol("User", NodeType.CLASS, 3, 3),
+            _symbol("User", NodeType.MODULE, 5, 6, [_symbol("apply", NodeType.METHOD, 6, 6)]),
+        ]
+        table.register_symbols(user, adapter.normalize_document_symbols(symbols), [], tmp_path)
+
+        assert table.symbols["com.acme.accounts.User"].kind == NodeType.CLASS
+        assert table.symbols["com.acme.accounts.User$"].kind == NodeType.MODULE
+        assert "com.acme.accounts.User.apply" in table.symbols
+
+    def test_package_ends_at_the_first_type(self):
+        adapter = ScalaAdapter()
+        assert adapter.extract_package("com.acme.orders.OrderService.place") == "com.acme.orders"
+        assert adapter.extract_package("com.acme.orders.helper") == "com.acme.orders"
+        assert adapter.extract_package("Main.run") == "Main"
+
+
+def test_call_in_a_one_line_expression_body_is_an_edge(tmp_path: Path):
+    source = tmp_path / "Cart.scala"
+    source_text = "  def total(items: List[Item]) = items.map(price).sum\n"
+    source.write_text(source_text)
+    adapter = ScalaAdapter()
+    ctx = EdgeBuildContext(MagicMock(), SymbolTable(adapter), SourceInspector())
+    caller = SymbolInfo("total", "shop.Cart.total", NodeType.METHOD, source, 0, 2, 0, len(source_text) - 1)
+    item = SymbolInfo("Item", "shop.Item", NodeType.CLASS, tmp_path / "Item.scala", 0, 0, 0, 30)
+    price = SymbolInfo("price", "shop.Cart.price", NodeType.METHOD, tmp_path / "Price.scala", 0, 0, 0, 30)
+    ctx.symbol_table.file_symbols[str(source)] = [caller]
+
+    def reference(name: str) -> dict:
+        start = source_text.index(name)
+        return {
+            "uri": source.as_uri(),
+            "range": {"start": {"line": 0, "character": start}, "end": {"line": 0, "character": start + len(name)}},
+        }
+
+    edge_set: EdgeMap = {}
+    _process_references_for_position(adapter, ctx, [price], [reference("price")], edge_set)
+    _process_references_for_position(adapter, ctx, [item], [reference("Item")], edge_set)
+
+    # ``Item`` is the parameter type, before the body; ``price`` is passed in it.
+    assert list(edge_set) == [(caller.qualified_name, price.qualified_name)]
@@ -0,0 +1,114 @@
+from pathlib import Path
+
+from static_analyzer.constants import NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+from static_analyzer.scala_implicits import add_given_edges
+from static_analyzer.scala_source import (
+    body_start_column,
+    context_parameters,
+    declaration_header,
+    given_type,
+    package_of,
+    supertypes,
+)
+
+ORDERS_SCALA = """package com.acme
+package orders
+
+trait OrderRepo:
+  def save(order: Order): Unit
+
+class PostgresOrderRepo extends OrderRepo:
+  def save(order: Order): Unit = ()
+
+object Givens:
+  given repo: OrderRepo = PostgresOrderRepo()
+  implicit val ec: ExecutionContext = ExecutionContext.global
+
+class OrderService(clock: Clock)(using
+    repo: OrderRepo
+):
+  def place(order: Order)(implicit ec: ExecutionContext, mailer: Mailer): Unit =
+    repo.save(order)
+"""
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, list[Node]]:
+    path = tmp_path / "Orders.scala"
+    path.write_text(ORDERS_SCALA)
+    mailer = tmp_path / "Mailer.scala"
+    mailer.write_text("package com.acme.orders\n\ntrait Mailer\n")
+    lines = ORDERS_SCALA.splitlines()
+
+    def node(qname: str, node_type: NodeType, text: str, end_text: str | None = None) -> Node:
+        start = next(i for i, source in enumerate(lines, start=1) if text in source)
+        end = next(i for i, source in enumerate(lines, start=1) if end_text in source) if end_text else start
+        return Node(qname, node_type, str(path), start, end)
+
+    cfg = CallGraph(language="scala")
+    for qname, node_type, text, end_text in [
+        ("com.acme.orders.OrderService", NodeType.CLASS, "class OrderService", "repo.save"),
+        ("com.acme.orders.OrderService.place", NodeType.METHOD, "def place", "repo.save"),
+        ("com.acme.orders.PostgresOrderRepo.save", NodeType.METHOD, "= ()", None),
+    ]:
+        cfg.add_node(node(qname, node_type, text, end_text))
+    references = [
+        node("com.acme.orders.OrderRepo", NodeType.INTERFACE, "trait OrderRepo", "def save(order: Order): Unit"),
+        node("com.acme.orders.Givens", NodeType.MODULE, "object Givens", "implicit val"),
+        node("com.acme.orders.Givens.repo", NodeType.CONSTANT, "given repo"),
+        Node("com.acme.orders.Mailer", NodeType.INTERFACE, str(mailer), 3, 3),
+    ]
+    return cfg, references
+
+
+def test_context_parameters_link_to_the_given_or_its_type(tmp_path: Path):
+    cfg, references = _graph(tmp_path)
+
+    added = add_given_edges(cfg, references)
+
+    assert added == [
+        ("com.acme.orders.OrderService", "com.acme.orders.Givens.repo", "uses-given"),
+        # Metals reports no symbol for ``ec``: its object stands in for it.
+        ("com.acme.orders.OrderService.place", "com.acme.orders.Givens", "uses-given"),
+        # No instance of ``Mailer`` in the project: the type is the dependency.
+        ("com.acme.orders.OrderService.place", "com.acme.orders.Mailer", "uses-given"),
+    ]
+    assert "com.acme.orders.Givens.repo" in cfg.nodes
+    assert add_given_edges(cfg, references) == []
+
+
+def test_headers_span_lines_up_to_the_body():
+    lines = ORDERS_SCALA.splitlines()
+    service = next(i for i, line in enumerate(lines) if "class OrderService" in line)
+
+    assert declaration_header(lines, service) == "class OrderService(clock: Clock)(using repo: OrderRepo )"
+    assert package_of(ORDERS_SCALA) == "com.acme.orders"
+    assert package_of("package object util {\n}\n") == ""
+    assert body_start_column("  def total = sum(items) // = comment") == 12
+    assert body_start_column("  def total(items: List[Item]): Int") is None
+
+
+def test_supertypes_and_context_parameters():
+    assert supertypes("class Shop(db: Db) extends Store(db) with Logging, m.Metrics derives Codec") == [
+        "Store",
+        "Logging",
+        "Metrics",
+    ]
+    assert supertypes("enum Color extends Enum[Color]") == ["Enum"]
+    assert supertypes("case class Point(x: Int, y: Int)") == []
+    assert context_parameters("def run[F[_]](n: Int)(using F: Monad[F], log: => Logger)") == ["Monad[F]", "Logger"]
+    assert context_parameters("def f(x: Int = g(1))(implicit ec: ExecutionContext)") == ["ExecutionContext"]
+    assert context_parameters("def show(using Show[Int])") == ["Show[Int]"]
+
+
+def test_given_types():
+    assert given_type("given listOrd[T](using Ord[T]): Ord[List[T]]") == "Ord[List[T]]"
+    assert given_type("given Ord[Int]") == "Ord[Int]"
+    assert given_type("given intOrd: Ord[Int]") == "Ord[Int]"
+    assert given_type("implicit lazy val ec: ExecutionContext") == "ExecutionContext"
+    assert given_type("implicit object PostgresDb extends Database") == "Database"
+    assert given_type("implicit def ordering[T](implicit ev: Ord[T]): Ordering[T]") == "Ordering[T]"
+    # A conversion, not an instance.
+    assert given_type("implicit def toJson(user: User): Json") is None
+    assert given_type("implicit class RichInt(val n: Int)") is None
@@ -0,0 +1,126 @@
+"""Tests for static_analyzer.schema_parser."""
+
+from pathlib import Path
+
+from repo_utils.ignore import RepoIgnoreManager
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import EdgeKind
+from static_analyzer.schema_parser import build_schema_analysis, discover_schema_files
+
+PROTO = """\
+syntax = "proto3";
+package shop.v1;
+
+// Orders service.
+service OrderService {
+  rpc CreateOrder (CreateOrderRequest) returns (Order);
+  rpc WatchOrders (stream WatchRequest) returns (stream Order);
+}
+
+message CreateOrderRequest {
+  repeated LineItem items = 1;
+  string note = 2;
+}
+
+message Order {
+  message Status { string code = 1; }
+  Status status = 1;
+  map<string, LineItem> items = 2;
+}
+
+message LineItem { int32 qty = 1; }
+message WatchRequest {}
+"""
+
+GRAPHQL = '''\
+"""Root queries."""
+type Query {
+  user(id: ID!): User
+  search(filter: SearchFilter): [Result!]!
+}
+
+interface Node { id: ID! }
+
+type User implements Node {
+  id: ID!
+  posts: [Post!]!  # authored posts
+}
+
+type Post implements Node { id: ID! author: User }
+
+input SearchFilter { term: String }
+
+union Result = User | Post
+'''
+
+
+def _analysis(tmp_path: Path, name: str, content: str, language: Language) -> dict:
+    path = tmp_path / name
+    path.write_text(content)
+    return build_schema_analysis(language, [path])
+
+
+def _edges(analysis: dict) -> set[tuple[str, str]]:
+    return {(e.get_source(), e.get_destination()) for e in analysis["call_graph"].edges}
+
+
+class TestProtobuf:
+    def test_services_messages_and_rpcs_become_nodes(self, tmp_path: Path):
+        nodes = _analysis(tmp_path, "shop.proto", PROTO, Language.PROTOBUF)["call_graph"].nodes
+
+        assert nodes["shop.v1.OrderService"].type == NodeType.INTERFACE
+        assert nodes["shop.v1.OrderService.CreateOrder"].type == NodeType.METHOD
+        assert nodes["shop.v1.Order"].type == NodeType.STRUCT
+        assert nodes["shop.v1.Order.Status"].line_start == 16
+
+    def test_rpc_request_and_response_become_edges(self, tmp_path: Path):
+        edges = _edges(_analysis(tmp_path, "shop.proto", PROTO, Language.PROTOBUF))
+
+        assert ("shop.v1.OrderService.CreateOrder", "shop.v1.CreateOrderRequest") in edges
+        assert ("shop.v1.OrderService.CreateOrder", "shop.v1.Order") in edges
+        assert ("shop.v1.OrderService.WatchOrders", "shop.v1.WatchRequest") in edges
+
+    def test_field_types_become_typeref_edges(self, tmp_path: Path):
+        ref_edges = _analysis(tmp_path, "shop.proto", PROTO, Language.PROTOBUF)["call_graph"].reference_edges
+
+        assert ("shop.v1.CreateOrderRequest", "shop.v1.LineItem", EdgeKind.TYPEREF) in ref_edges
+        assert ("shop.v1.Order", "shop.v1.Order.Status", EdgeKind.TYPEREF) in ref_edges
+        assert ("shop.v1.Order", "shop.v1.LineItem", EdgeKind.TYPEREF) in ref_edges
+        assert ("shop.v1.OrderService", "shop.v1.OrderService.CreateOrder", EdgeKind.CONTAINS) in ref_edges
+
+
+class TestGraphQL:
+    def test_root_fields_become_operations(self, tmp_path: Path):
+        analysis = _analysis(tmp_path, "schema.graphql", GRAPHQL, Language.GRAPHQL)
+
+        assert analysis["call_graph"].nodes["schema.Query.user"].type == NodeType.METHOD
+        assert ("schema.Query.user", "schema.User") in _edges(analysis)
+        assert ("schema.Query.search", "schema.SearchFilter") in _edges(analysis)
+        assert ("schema.Query.search", "schema.Result") in _edges(analysis)
+
+    def test_implements_builds_hierarchy(self, tmp_path: Path):
+        analysis = _analysis(tmp_path, "schema.graphql", GRAPHQL, Language.GRAPHQL)
+
+        assert analysis["class_hierarchies"]["schema.User"]["superclasses"] == ["schema.Node"]
+        assert set(analysis["class_hierarchies"]["schema.Node"]["subclasses"]) == {"schema.User", "schema.Post"}
+
+    def test_object_fields_and_union_members_become_typeref_edges(self, tmp_path: Path):
+        ref_edges = _analysis(tmp_path, "schema.graphql", GRAPHQL, Language.GRAPHQL)["call_graph"].reference_edges
+
+        assert ("schema.User", "schema.Post", EdgeKind.TYPEREF) in ref_edges
+        assert ("schema.Post", "schema.User", EdgeKind.TYPEREF) in ref_edges
+        assert ("schema.Result", "schema.User", EdgeKind.TYPEREF) in ref_edges
+
+
+def test_discover_schema_files_groups_by_language(tmp_path: Path):
+    (tmp_path / "api").mkdir()
+    (tmp_path / "api" / "shop.proto").write_text(PROTO)
+    (tmp_path / "schema.gql").write_text(GRAPHQL)
+    (tmp_path / "main.py").write_text("pass\n")
+
+    found = discover_schema_files(tmp_path, RepoIgnoreManager(tmp_path))
+
+    assert found == {
+        Language.PROTOBUF: [(tmp_path / "api" / "shop.proto").resolve()],
+        Language.GRAPHQL: [(tmp_path / "schema.gql").resolve()],
+    }
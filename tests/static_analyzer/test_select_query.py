@@ -0,0 +1,104 @@
+from pathlib import Path
+
+import pytest
+
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node
+from static_analyzer.select_query import SelectQuery, SelectQueryError, apply_select_query
+
+
+def _results(repo: Path) -> StaticAnalysisResults:
+    (repo / "go.mod").write_text("module example.com/edgecases\n\ngo 1.22\n")
+    graph = CallGraph(language="go")
+    for qname, rel_path, node_type in [
+        ("edgecases.Run", "main.go", NodeType.FUNCTION),
+        ("store.Store", "internal/store/store.go", NodeType.STRUCT),
+        ("store.Store.Get", "internal/store/store.go", NodeType.METHOD),
+        ("store.helper_test", "internal/store/store_test.go", NodeType.FUNCTION),
+        ("tools.Gen", "tools/gen.go", NodeType.FUNCTION),
+    ]:
+        graph.add_node(Node(qname, node_type, str(repo / rel_path), 1, 2))
+    graph.add_edge("edgecases.Run", "store.Store.Get")
+    graph.add_edge("store.helper_test", "store.Store.Get")
+    graph.add_edge("tools.Gen", "edgecases.Run")
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, graph)
+    return results
+
+
+def _selected(repo: Path, text: str) -> set[str]:
+    results = _results(repo)
+    selected = apply_select_query(results, SelectQuery.parse(text), repo)
+    assert len(results.get_cfg(Language.GO).nodes) == 5
+    return set(selected.get_cfg(Language.GO).nodes)
+
+
+def test_select_combines_package_name_kind_and_path(tmp_path: Path):
+    assert _selected(tmp_path, "pkg(example.com/edgecases/**) and not name(*_test)") == {
+        "edgecases.Run",
+        "store.Store",
+        "store.Store.Get",
+        "tools.Gen",
+    }
+    assert _selected(tmp_path, "pkg(example.com/edgecases/internal/**) AND kind(callable)") == {
+        "store.Store.Get",
+        "store.helper_test",
+    }
+    assert _selected(tmp_path, "not (path(tools/**) or path(**/*_test.go)) and not kind(type)") == {
+        "edgecases.Run",
+        "store.Store.Get",
+    }
+    assert _selected(tmp_path, "name(store.Store*)") == {"store.Store", "store.Store.Get"}
+
+
+def test_select_keeps_only_edges_between_selected_symbols(tmp_path: Path):
+    results = _results(tmp_path)
+    selected = apply_select_query(results, SelectQuery.parse("not path(tools/*)"), tmp_path)
+
+    edges = {(edge.get_source(), edge.get_destination()) for edge in selected.get_cfg(Language.GO).edges}
+    assert edges == {("edgecases.Run", "store.Store.Get"), ("store.helper_test", "store.Store.Get")}
+
+
+@pytest.mark.parametrize(
+    ("text", "message", "column"),
+    [
+        ("", "empty expression", 1),
+        ("pkg(a) nd name(b)", "expected 'and', 'or' or the end of the expression, found 'nd'", 8),
+        ("file(a)", "unknown predicate 'file'", 1),
+        ("name(a) and", "expected a predicate, 'not' or '(' but the expression ended", 12),
+        ("(name(a) or kind(class)", "expected ')' to close the group opened", 1),
+        ("kind(fn)", "unknown kind 'fn'", 6),
+        ("path()", "path() needs a pattern", 6),
+        ("name(a", "unclosed '(' in name(...)", 5),
+    ],
+)
+def test_parse_errors_point_at_the_offending_column(text: str, message: str, column: int):
+    with pytest.raises(SelectQueryError) as excinfo:
+        SelectQuery.parse(text)
+
+    assert excinfo.value.message.startswith(message)
+    assert excinfo.value.position + 1 == column
+    assert str(excinfo.value).endswith(f"\n  {text}\n  {' ' * (column - 1)}^")
+
+
+def test_canonical_kinds_match_the_same_symbols_in_every_language(tmp_path: Path):
+    results = StaticAnalysisResults()
+    for language, symbols in {
+        Language.GO: [("store.Store", NodeType.STRUCT), ("store.Store.Get", NodeType.METHOD)],
+        Language.PYTHON: [("shop.Cart", NodeType.CLASS), ("shop.Cart.total", NodeType.PROPERTY)],
+        Language.JAVA: [("Order", NodeType.CLASS), ("Order.Order", NodeType.CONSTRUCTOR), ("Order.id", NodeType.FIELD)],
+    }.items():
+        graph = CallGraph(language=language.value)
+        for qname, node_type in symbols:
+            graph.add_node(Node(qname, node_type, str(tmp_path / f"{qname}.src"), 1, 2))
+        results.add_cfg(language, graph)
+
+    def selected(text: str) -> set[str]:
+        narrowed = apply_select_query(results, SelectQuery.parse(text), tmp_path)
+        return {name for language in narrowed.get_languages() for name in narrowed.get_cfg(language).nodes}
+
+    assert selected("kind(method)") == {"store.Store.Get", "shop.Cart.total", "Order.Order"}
+    assert selected("kind(type) and not kind(class)") == {"store.Store"}
+    assert selected("kind(variable)") == {"Order.id"}
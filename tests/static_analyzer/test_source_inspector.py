@@ -70,6 +70,27 @@ class TestIsInvocation:
         # After "List" at char 8, rest is "<String>()"
         assert si.is_invocation(f, 0, 12) is True
 
+    def test_awaited_csharp_calls(self, tmp_path: Path):
+        f = tmp_path / "Loader.cs"
+        source = "class L { async Task<U> Load() => await _repo.GetAsync<U>(1).ConfigureAwait(false); }\n"
+        f.write_text(source)
+        si = SourceInspector()
+        assert si.is_invocation(f, 0, source.index("GetAsync") + len("GetAsync")) is True
+        assert si.is_invocation(f, 0, source.index("ConfigureAwait") + len("ConfigureAwait")) is True
+
+    def test_go_instantiated_generic_call(self, tmp_path: Path):
+        f = tmp_path / "test.go"
+        f.write_text(
+            "package x\n\nfunc f() {\n\tys := Map[int, string](xs, g)\n\tz := Max[int](1, 2)\n"
+            "\tv := util.Map[int, string](xs, g)\n}\n"
+        )
+        si = SourceInspector()
+        # The call is attributed to the generic function, not to its last type argument.
+        assert si.is_invocation(f, 3, 10) is True  # after "Map"
+        assert si.is_invocation(f, 4, 9) is True  # after "Max"
+        assert si.is_invocation(f, 4, 13) is False  # after "int"
+        assert si.is_invocation(f, 5, 14) is True  # after "util.Map"
+
     def test_conservative_on_missing_file(self):
         si = SourceInspector()
         assert si.is_invocation(Path("/nonexistent.py"), 0, 0) is True
@@ -311,6 +332,36 @@ class TestFindCallSites:
         # "sort" should be found via the call pattern
         assert any(site.line == 1 for site in sites)
 
+    def test_finds_calls_in_defer_and_go_statements(self, tmp_path: Path):
+        f = tmp_path / "tasks.go"
+        f.write_text(
+            "package tasks\n\nfunc DescribeTask(t Task) {\n"
+            "\tdefer fmt.Println(format(t))\n\tgo worker(t)\n\tgo func() { notify(done) }()\n}\n"
+        )
+        si = SourceInspector()
+        positions = _positions(si.find_call_sites(f))
+        assert (4, 12) in positions  # Println
+        assert (4, 20) in positions  # format, an argument of the deferred call
+        assert (5, 5) in positions  # worker
+        assert (6, 14) in positions  # notify, inside the goroutine's function literal
+        # The function literal itself has no name to query; its last identifier is not a call target.
+        assert (6, 21) not in positions  # done
+        assert si.is_invocation(f, 5, 24) is False
+
+    def test_finds_calls_whose_results_are_unpacked_or_discarded(self, tmp_path: Path):
+        f = tmp_path / "main.go"
+        f.write_text(
+            "package main\n\nfunc runTasks(t2 *Task) {\n"
+            "\tname, prio := services.GetTaskInfo(t2)\n\t_ = services.Foo()\n\tfmt.Println(name, prio)\n}\n"
+        )
+        si = SourceInspector()
+        sites = si.find_call_sites(f)
+        positions = _positions(sites)
+        # One site for the two-value call, none for the names it is unpacked into.
+        assert [(site.line, site.column) for site in sites if site.line == 4] == [(4, 25)]  # GetTaskInfo
+        assert (5, 15) in positions  # Foo, its result thrown away
+        assert (5, 2) not in positions  # _
+
     def test_uses_shared_constants_for_module_suffixes(self, tmp_path: Path):
         f = tmp_path / "test.mjs"
         f.write_text("foo()\n")
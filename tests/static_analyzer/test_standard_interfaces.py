@@ -0,0 +1,124 @@
+from pathlib import Path
+
+from output_generators.class_diagram import build_class_diagram, generate_class_diagram
+from project_config import ProjectConfig
+from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.go_embedding import add_embedding_edges
+from static_analyzer.graph import CallGraph
+from static_analyzer.node import Node, Parameter, Signature
+from static_analyzer.standard_interfaces import (
+    collect_standard_interface_tags,
+    load_standard_interfaces,
+    method_matches,
+    tag_standard_interfaces,
+)
+
+MODELS_GO = """package models
+
+type Entity struct {
+	ID string
+}
+
+func (e Entity) String() string { return e.ID }
+
+type Task struct {
+	Entity
+}
+
+type Sink struct{}
+
+func (s *Sink) Write(p []byte) (n int, err error) { return len(p), nil }
+
+func (s *Sink) Error() int { return 0 }
+"""
+
+
+def _line(text: str) -> int:
+    return next(i for i, line in enumerate(MODELS_GO.splitlines(), start=1) if text in line)
+
+
+def _graph(tmp_path: Path) -> tuple[CallGraph, list[Node]]:
+    path = tmp_path / "models" / "models.go"
+    path.parent.mkdir(parents=True)
+    path.write_text(MODELS_GO)
+    graph = CallGraph(language="go")
+    members = []
+    for qname, node_type, text, signature in [
+        ("models.models.Entity", NodeType.STRUCT, "type Entity", None),
+        ("models.models.(Entity).String", NodeType.METHOD, "func (e Entity) String", ((), ("string",))),
+        ("models.models.Task", NodeType.STRUCT, "type Task", None),
+        ("models.models.(Task).Entity", NodeType.FIELD, "\tEntity", None),
+        ("models.models.Sink", NodeType.STRUCT, "type Sink", None),
+        ("models.models.(*Sink).Write", NodeType.METHOD, "Write(p", ((("p", "[]byte"),), ("int", "error"))),
+        ("models.models.(*Sink).Error", NodeType.METHOD, "Error() int", ((), ("int",))),
+    ]:
+        line = _line(text)
+        node = Node(qname, node_type, str(path), line, line)
+        if signature is not None:
+            parameters, results = signature
+            node.signature = Signature(
+                tuple(Parameter(type_, name) for name, type_ in parameters), tuple(Parameter(r) for r in results)
+            )
+        if node_type == NodeType.FIELD:
+            members.append(node)
+        else:
+            graph.add_node(node)
+    add_embedding_edges(graph, members)
+    return graph, members
+
+
+def test_types_are_tagged_through_their_own_and_promoted_methods(tmp_path: Path):
+    graph, members = _graph(tmp_path)
+
+    tags = tag_standard_interfaces(graph, members, load_standard_interfaces(ProjectConfig())[Language.GO])
+
+    # ``Sink.Error`` returns an int, so Sink is not an error.
+    assert tags == {
+        "models.models.Entity": ("fmt.Stringer",),
+        "models.models.Task": ("fmt.Stringer",),
+        "models.models.Sink": ("io.Writer",),
+    }
+    assert graph.nodes["models.models.Task"].standard_interfaces == ("fmt.Stringer",)
+
+
+def test_method_specs_match_types_and_fall_back_to_names():
+    write = Node("s.(*Sink).Write", NodeType.METHOD, "s.go", 1, 1)
+    assert method_matches("Write([]byte) (int, error)", write)
+    write.signature = Signature((Parameter("[]byte", "p"),), (Parameter("int", "n"), Parameter("error", "err")))
+    assert method_matches("Write([]byte) (int, error)", write)
+    assert method_matches("Write", write)
+    assert not method_matches("Write(string) (int, error)", write)
+
+
+def test_project_config_adds_and_drops_interfaces_per_language():
+    config = ProjectConfig(
+        sections={
+            "standard_interfaces": {
+                "go": {"json.Marshaler": ["MarshalJSON() ([]byte, error)"], "sort.Interface": []},
+                "rust": {"Display": "fmt"},
+                "cobol": {"Printable": ["PRINT"]},
+            }
+        }
+    )
+
+    interfaces = load_standard_interfaces(config)
+
+    go_names = [iface.name for iface in interfaces[Language.GO]]
+    assert "json.Marshaler" in go_names and "sort.Interface" not in go_names
+    assert [iface.name for iface in interfaces[Language.RUST]] == ["Display"]
+
+
+def test_tags_reach_the_prompt_and_the_class_diagram(tmp_path: Path):
+    graph, members = _graph(tmp_path)
+    tag_standard_interfaces(graph, members, load_standard_interfaces(ProjectConfig())[Language.GO])
+    results = StaticAnalysisResults()
+    results.add_cfg(Language.GO, graph)
+    results.add_references(Language.GO, [*graph.nodes.values(), *members])
+
+    prompt = collect_standard_interface_tags(results).llm_str()
+    diagram = generate_class_diagram(build_class_diagram(results, tmp_path))
+
+    assert "- `models.models.Entity` is a fmt.Stringer\n" in prompt
+    assert "    <<fmt.Stringer>> models_models_Entity\n" in diagram
+    assert "    <<io.Writer>> models_models_Sink\n" in diagram
@@ -169,6 +169,19 @@ class TestStartClientsWorkspaceReadyDispatch:
         rust_client.wait_for_server_ready.assert_called_once()
 
 
+class TestRestartCrashedClients:
+    def test_dead_server_restarts_every_client(self, analyzer: StaticAnalyzer, tmp_path: Path) -> None:
+        analyzer._engine_configs = [EngineConfig(_make_adapter("Go"), tmp_path)]
+        crashed, fresh = MagicMock(name="Crashed", is_running=False), MagicMock(name="Fresh", is_running=True)
+        with patch("static_analyzer.LSPClient", side_effect=[crashed, fresh]):
+            analyzer.start_clients()
+            assert analyzer.restart_crashed_clients() == ["Go"]
+
+        crashed.shutdown.assert_called_once()
+        assert analyzer._engine_clients[0][1] is fresh
+        assert analyzer.restart_crashed_clients() == []
+
+
 class TestFlushCacheRespectsCacheDir:
     """``flush_cache`` writes to ``_pending_cache_dir`` (set by ``analyze``)
     when supplied, otherwise to the default ``get_artifact_dir(repository_path)``.
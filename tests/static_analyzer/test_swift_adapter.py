@@ -0,0 +1,107 @@
+"""Tests for the Swift language adapter."""
+
+from pathlib import Path
+from unittest.mock import patch
+
+import pytest
+
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.engine.adapters import get_adapter
+from static_analyzer.engine.adapters.swift_adapter import EXTENSION_DETAIL, SwiftAdapter
+from static_analyzer.engine.symbol_table import SymbolTable
+
+ROOT = Path("/tmp/swift_project")
+DOG = ROOT / "Sources" / "Zoo" / "Models" / "Dog.swift"
+DOG_CODABLE = ROOT / "Sources" / "Zoo" / "Dog+Codable.swift"
+
+
+def _symbol(name: str, kind: int, start: int, end: int, children: list[dict] | None = None) -> dict:
+    symbol = {
+        "name": name,
+        "kind": kind,
+        "range": {"start": {"line": start, "character": 0}, "end": {"line": end, "character": 1}},
+        "selectionRange": {"start": {"line": start, "character": 7}, "end": {"line": start, "character": 10}},
+    }
+    if children is not None:
+        symbol["children"] = children
+    return symbol
+
+
+class TestSwiftAdapterProperties:
+    def test_registered_with_swift_defaults(self):
+        adapter = get_adapter("Swift")
+        assert isinstance(adapter, SwiftAdapter)
+        assert adapter.language_enum is Language.SWIFT
+        assert adapter.file_extensions == (".swift",)
+        assert adapter.language_id == "swift"
+        assert adapter.lsp_command == ["sourcekit-lsp"]
+        assert adapter.interface_supertypes_are_implementations
+
+    def test_missing_swift_fails_fast(self):
+        with patch("static_analyzer.engine.adapters.swift_adapter.shutil.which", return_value=None):
+            with pytest.raises(RuntimeError, match="swift not found"):
+                SwiftAdapter().get_lsp_command(ROOT)
+
+    def test_sourcekit_lsp_off_path_is_found_through_xcrun(self):
+        xcode = "/Applications/Xcode.app/Contents/Developer/Toolchains/XcodeDefault.xctoolchain/usr/bin/sourcekit-lsp"
+        which = {"swift": "/usr/bin/swift"}.get
+        with (
+            patch("static_analyzer.engine.adapters.swift_adapter.shutil.which", side_effect=which),
+            patch("static_analyzer.engine.adapters.swift_adapter.find_sourcekit_lsp", return_value=xcode),
+            patch("static_analyzer.engine.language_adapter.get_config", return_value={}),
+        ):
+            assert SwiftAdapter().get_lsp_command(ROOT) == [xcode]
+
+    def test_guard_and_nil_coalescing_are_decision_points(self):
+        pattern = SwiftAdapter().decision_point_pattern
+        assert len(pattern.findall("guard let x = y else { return }; let z = a ?? b; repeat { } while ok")) == 4
+
+
+class TestNormalizeDocumentSymbols:
+    def test_extensions_are_marked_and_named_after_the_extended_type(self):
+        symbols = SwiftAdapter().normalize_document_symbols(
+            [_symbol("Array<Element> where Element == Int", NodeType.NAMESPACE, 0, 4)]
+        )
+
+        assert symbols[0]["name"] == "Array"
+        assert symbols[0]["detail"] == EXTENSION_DETAIL
+
+    def test_computed_properties_become_methods(self):
+        original = _symbol(
+            "ContentView",
+            NodeType.STRUCT,
+            0,
+            10,
+            [_symbol("title", NodeType.PROPERTY, 1, 1), _symbol("body", NodeType.PROPERTY, 2, 8)],
+        )
+
+        (view,) = SwiftAdapter().normalize_document_symbols([original])
+
+        assert [child["kind"] for child in view["children"]] == [NodeType.PROPERTY, NodeType.METHOD]
+        assert original["children"][1]["kind"] == NodeType.PROPERTY
+
+
+class TestQualifiedNames:
+    def test_module_is_the_swiftpm_target_then_the_top_directory(self):
+        adapter = SwiftAdapter()
+        name = adapter.build_qualified_name
+        assert name(DOG, "bark", NodeType.METHOD, [("Dog", NodeType.CLASS)], ROOT) == "Zoo.Dog.bark"
+        assert name(ROOT / "Tests" / "ZooTests" / "DogTests.swift", "testBark", NodeType.METHOD, [], ROOT) == (
+            "ZooTests.testBark"
+        )
+        assert name(ROOT / "ZooApp" / "Views" / "Home.swift", "Home", NodeType.STRUCT, [], ROOT) == "ZooApp.Home"
+        assert name(ROOT / "main.swift", "run", NodeType.FUNCTION, [], ROOT) == "main.run"
+
+    def test_extension_members_land_on_the_base_type(self):
+        adapter = SwiftAdapter()
+        table = SymbolTable(adapter)
+        table.register_symbols(
+            DOG, adapter.normalize_document_symbols([_symbol("Dog", NodeType.CLASS, 0, 9, [])]), [], ROOT
+        )
+        extension = _symbol("Dog", NodeType.NAMESPACE, 0, 9, [_symbol("encode(to:)", NodeType.METHOD, 2, 6)])
+        table.register_symbols(DOG_CODABLE, adapter.normalize_document_symbols([extension]), [], ROOT)
+
+        assert table.symbols["Zoo.Dog"].file_path == DOG
+        assert table.symbols["Zoo.Dog"].kind == NodeType.CLASS
+        assert table.symbols["Zoo.Dog.encode(to:)"].file_path == DOG_CODABLE
+        assert "Zoo.Dog<extension:Dog+Codable>" in table.symbols
@@ -0,0 +1,66 @@
+import json
+
+from agents.agent_responses import AnalysisInsights, Component
+from agents.file_index_models import FileEntry, FileMethodGroup, MethodEntry
+from diagram_analysis.analysis_json import build_unified_analysis_json, parse_unified_analysis
+from project_config import ProjectConfig
+from static_analyzer.constants import Language, NodeType
+from static_analyzer.symbol_kinds import CanonicalKind, assign_method_kinds, kind_label, load_kind_map
+
+
+def test_project_table_overrides_defaults_per_language():
+    kind_map = load_kind_map(
+        ProjectConfig(
+            sections={
+                "symbol_kinds": {
+                    "event": "function",
+                    "go": {"struct": "interface"},
+                    "python": {"property": "variable", "bogus": "type"},
+                    "cobol": {"class": "type"},
+                    "enum_member": "wat",
+                }
+            }
+        )
+    )
+
+    assert kind_map.canonical(NodeType.STRUCT, Language.GO) == CanonicalKind.INTERFACE
+    assert kind_map.canonical(NodeType.STRUCT, Language.RUST) == CanonicalKind.TYPE
+    # The project table wins over the adapter's own override (Python properties are methods).
+    assert kind_map.canonical(NodeType.PROPERTY, Language.PYTHON) == CanonicalKind.VARIABLE
+    assert load_kind_map(ProjectConfig()).canonical(NodeType.PROPERTY, "python") == CanonicalKind.METHOD
+    assert kind_map.canonical(NodeType.EVENT, Language.CSHARP) == CanonicalKind.FUNCTION
+    assert kind_map.canonical(NodeType.ENUM_MEMBER) == CanonicalKind.CONSTANT
+    assert kind_map.canonical(NodeType.CONSTRUCTOR, "unknown") == CanonicalKind.METHOD
+
+
+def test_method_kinds_are_saved_and_label_the_docs(tmp_path):
+    methods = [
+        MethodEntry(qualified_name="store.Store", start_line=3, end_line=9, node_type="STRUCT"),
+        MethodEntry(qualified_name="store.New", start_line=11, end_line=14, node_type="FUNCTION"),
+    ]
+    analysis = AnalysisInsights(
+        description="A store.",
+        components=[
+            Component(
+                name="Store",
+                description="Persists.",
+                key_entities=[],
+                component_id="1",
+                file_methods=[FileMethodGroup(file_path="store/store.go", methods=methods)],
+            )
+        ],
+        components_relations=[],
+    )
+    analysis.files = {"store/store.go": FileEntry(methods=[m.model_copy() for m in methods])}
+
+    assign_method_kinds([analysis], load_kind_map(ProjectConfig()))
+    payload = build_unified_analysis_json(
+        analysis, [], "store", repo_dir=tmp_path, source_tree_hash="", depth_cap=1, sub_analyses={}
+    )
+    loaded, _ = parse_unified_analysis(json.loads(payload))
+
+    assert '"kind": "type"' in payload
+    reloaded = loaded.components[0].file_methods[0].methods
+    assert [(m.qualified_name, m.kind) for m in reloaded] == [("store.Store", "type"), ("store.New", "function")]
+    assert kind_label(reloaded[0].node_type, reloaded[0].kind) == "Type"
+    assert kind_label("STRUCT") == "Struct"
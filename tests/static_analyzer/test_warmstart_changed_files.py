@@ -12,6 +12,7 @@ from unittest.mock import MagicMock, patch
 
 from static_analyzer import EngineConfig, StaticAnalyzer
 from static_analyzer.analysis_result import StaticAnalysisResults
+from static_analyzer.path_overrides import PathOverrides
 
 
 def _analyzer_with_one_engine(project_path: Path, changed_files: set[Path] | None) -> StaticAnalyzer:
@@ -25,6 +26,8 @@ def _analyzer_with_one_engine(project_path: Path, changed_files: set[Path] | Non
     analyzer.ignore_manager = MagicMock()
     analyzer._loc_for_adapter = MagicMock(return_value=0)
     analyzer.changed_files = changed_files
+    analyzer.path_overrides = PathOverrides()
+    analyzer.lsp_concurrency = None
     return analyzer
 
 
@@ -108,6 +111,29 @@ class TestWarmStartChangedFiles(unittest.TestCase):
         mock_full.assert_called_once()
         mock_update.assert_not_called()
 
+    @patch("static_analyzer.update_cfg_for_changed_files", return_value={})
+    @patch("static_analyzer.get_changed_files_since")
+    def test_content_hashes_bypass_git_and_add_importers(self, mock_git, mock_update) -> None:
+        edited, importer, unrelated = self.project / "a" / "x.py", self.project / "b" / "y.py", self.project / "c.py"
+        analyzer = _analyzer_with_one_engine(self.project, changed_files=None)
+        adapter = analyzer._engine_clients[0][0].adapter
+        adapter.file_extensions = {".py"}
+        adapter.discover_source_files.return_value = [edited, importer, unrelated]
+        adapter.get_package_for_file.side_effect = lambda f, root: ".".join(f.relative_to(root).parent.parts) or f.stem
+        relations = {"a": {"imports": [], "imported_by": ["b"]}, "b": {"imports": ["a"], "imported_by": []}}
+        hashes = {str(edited): "old", str(importer): "same", str(unrelated): "same"}
+        with (
+            patch.object(analyzer, "_extract_language_dict", return_value={"package_relations": relations}),
+            patch.object(analyzer, "_absorb_into_results"),
+            patch.object(analyzer, "_collect_diagnostics_for"),
+            patch("static_analyzer.track_lsp_result"),
+            patch("static_analyzer.analysis_cache.file_content_hash", return_value="same"),
+        ):
+            analyzer._update_cached_results(self.cached, cached_sha="deadbeef", file_hashes=hashes)
+
+        mock_git.assert_not_called()
+        self.assertEqual(mock_update.call_args.args[1], {edited, importer})
+
 
 if __name__ == "__main__":
     unittest.main()
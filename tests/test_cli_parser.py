@@ -1,5 +1,8 @@
 from unittest.mock import patch
 
+import pytest
+
+from codeboarding_cli.bootstrap import write_requested_outputs
 from main import build_parser, main
 
 
@@ -68,3 +71,222 @@ def test_force_flag_registered_and_defaults_false() -> None:
 def test_force_flag_sets_true_when_passed() -> None:
     args = build_parser().parse_args(["full", "--local", "/tmp/repo", "--force"])
     assert args.force is True
+
+
+def test_cli_dispatches_batch_merge() -> None:
+    with (
+        patch("main.batch.run_from_args") as run_batch,
+        patch("main.full_analysis.run_from_args") as run_full,
+    ):
+        main(["batch", "merge", "shard-1", "shard-2", "--output-dir", "merged"])
+
+    run_batch.assert_called_once()
+    run_full.assert_not_called()
+    (args, _parser), _kwargs = run_batch.call_args
+    assert [str(p) for p in args.shard_dirs] == ["shard-1", "shard-2"]
+
+
+def test_shard_flag_is_parsed_for_remote_runs() -> None:
+    args = build_parser().parse_args(["full", "https://github.com/user/repo", "--shard", "2/4"])
+    assert args.shard == "2/4"
+    assert args.remote_cache is None
+
+
+def test_framework_flag_accepts_known_frameworks_for_every_local_command() -> None:
+    parser = build_parser()
+
+    assert parser.parse_args(["full", "--local", "/tmp/repo", "--framework", "nest"]).framework == ["nest"]
+    assert parser.parse_args(["incremental", "--local", "/tmp/repo", "--framework", "angular"]).framework == [
+        "angular"
+    ]
+    with pytest.raises(SystemExit):
+        parser.parse_args(["full", "--local", "/tmp/repo", "--framework", "spring"])
+
+
+def test_select_flag_parses_the_query_and_reports_errors(capsys) -> None:
+    parser = build_parser()
+
+    args = parser.parse_args(["incremental", "--local", "/tmp/repo", "--select", "kind(method) and not name(*_test)"])
+    assert args.select.text == "kind(method) and not name(*_test)"
+    with pytest.raises(SystemExit):
+        parser.parse_args(["full", "--local", "/tmp/repo", "--select", "pkg(a) nd name(b)"])
+    assert "found 'nd' at column 8" in capsys.readouterr().err
+
+
+def test_output_format_json_is_local_only_and_skips_the_llm_setup() -> None:
+    parser = build_parser()
+    assert parser.parse_args(["full", "--local", "/tmp/repo"]).output_format == "docs"
+
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment") as bootstrap,
+        patch("codeboarding_cli.commands.full_analysis._write_json") as write_json,
+        patch("codeboarding_cli.commands.full_analysis.run_analysis_pipeline") as pipeline,
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(["full", "--local", "/tmp/repo", "--output-format", "json", "--output-dir", "/tmp/repo-json-out"])
+
+    assert bootstrap.call_args.kwargs["configure_llm"] is False
+    write_json.assert_called_once()
+    pipeline.assert_not_called()
+    with pytest.raises(SystemExit):
+        main(["full", "https://github.com/user/repo", "--output-format", "json"])
+
+
+def test_no_llm_is_local_only_and_skips_the_llm_setup() -> None:
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment") as bootstrap,
+        patch("codeboarding_cli.commands.full_analysis._write_static_outputs") as write_static,
+        patch("codeboarding_cli.commands.full_analysis.run_analysis_pipeline") as pipeline,
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(["full", "--local", "/tmp/repo", "--no-llm", "--output-dir", "/tmp/repo-no-llm-out"])
+
+    assert bootstrap.call_args.kwargs["configure_llm"] is False
+    write_static.assert_called_once()
+    pipeline.assert_not_called()
+    with pytest.raises(SystemExit):
+        main(["full", "https://github.com/user/repo", "--no-llm"])
+    with pytest.raises(SystemExit):
+        main(["full", "--local", "/tmp/repo", "--no-llm", "--snapshot"])
+
+
+def test_snippets_reach_the_local_pdf_docs(tmp_path) -> None:
+    args = build_parser().parse_args(["full", "--local", str(tmp_path), "--format", "pdf", "--snippets"])
+    with patch("codeboarding_cli.bootstrap.render_pdf") as render_pdf:
+        write_requested_outputs(args, tmp_path / "analysis.json", "repo", tmp_path)
+
+    assert render_pdf.call_args.kwargs["snippets"].repo_dir == tmp_path
+    with pytest.raises(SystemExit):
+        main(["full", "--local", str(tmp_path), "--collapsible-md"])
+
+
+def test_diff_parses_the_ref_range_and_is_local_only(capsys) -> None:
+    parser = build_parser()
+    args = parser.parse_args(["full", "--local", "/tmp/repo", "--diff", "main...HEAD"])
+    assert (args.diff.base, args.diff.head, args.diff.from_merge_base) == ("main", "HEAD", True)
+
+    with pytest.raises(SystemExit):
+        parser.parse_args(["full", "--local", "/tmp/repo", "--diff", "..HEAD"])
+    assert "expected BASE..HEAD" in capsys.readouterr().err
+    with pytest.raises(SystemExit):
+        main(["full", "https://github.com/user/repo", "--diff", "main..HEAD"])
+
+
+def test_progress_json_reports_the_run_end_on_stderr(capsys) -> None:
+    with patch("main.full_analysis.run_from_args"):
+        main(["full", "--local", "/tmp/repo", "--progress", "json"])
+
+    assert '"event": "run_end"' in capsys.readouterr().err
+    assert build_parser().parse_args(["full", "--local", "/tmp/repo"]).progress == "bar"
+    with pytest.raises(SystemExit):
+        build_parser().parse_args(["full", "--local", "/tmp/repo", "--progress", "spinner"])
+
+
+def test_log_level_and_file_reach_the_logging_setup(capsys) -> None:
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment") as bootstrap,
+        patch("codeboarding_cli.commands.full_analysis._write_static_outputs"),
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(
+            [
+                "full",
+                "--local",
+                "/tmp/repo",
+                "--no-llm",
+                "--output-dir",
+                "/tmp/repo-log-out",
+                "--log-level",
+                "warn",
+                "--log-file",
+                "/tmp/repo-log-out/run.log",
+            ]
+        )
+
+    assert bootstrap.call_args.kwargs["log_level"] == "WARNING"
+    assert str(bootstrap.call_args.kwargs["log_file"]) == "/tmp/repo-log-out/run.log"
+    with pytest.raises(SystemExit):
+        build_parser().parse_args(["full", "--local", "/tmp/repo", "--log-level", "verbose"])
+    assert "unknown log level 'verbose'" in capsys.readouterr().err
+
+
+def test_concurrency_reaches_the_analysis_options() -> None:
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment"),
+        patch("codeboarding_cli.commands.full_analysis._write_static_outputs") as write_static,
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(["full", "--local", "/tmp/repo", "--no-llm", "--output-dir", "/tmp/repo-jobs-out", "--concurrency", "4"])
+
+    assert write_static.call_args.args[2].lsp_concurrency == 4
+
+
+def test_include_and_exclude_reach_the_analysis_options() -> None:
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment"),
+        patch("codeboarding_cli.commands.full_analysis._write_static_outputs") as write_static,
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(
+            [
+                "full",
+                "--local",
+                "/tmp/repo",
+                "--no-llm",
+                "--output-dir",
+                "/tmp/repo-scope-out",
+                "--include",
+                "src/**",
+                "--exclude",
+                "**/vendor/**",
+                "--exclude",
+                "**/gen/**",
+            ]
+        )
+
+    options = write_static.call_args.args[2]
+    assert (options.include, options.exclude) == (("src/**",), ("**/vendor/**", "**/gen/**"))
+
+
+def test_lsp_timeout_reaches_the_analysis_options(capsys) -> None:
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment"),
+        patch("codeboarding_cli.commands.full_analysis._write_static_outputs") as write_static,
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(
+            ["full", "--local", "/tmp/repo", "--no-llm", "--output-dir", "/tmp/repo-timeout-out", "--lsp-timeout", "5"]
+        )
+
+    assert write_static.call_args.args[2].lsp_timeout == 5
+    assert build_parser().parse_args(["full", "--local", "/tmp/repo"]).lsp_timeout is None
+    with pytest.raises(SystemExit):
+        build_parser().parse_args(["full", "--local", "/tmp/repo", "--lsp-timeout", "0"])
+    assert "must be at least 1" in capsys.readouterr().err
+
+
+def test_prompt_dir_is_checked_and_reaches_the_analysis_options(tmp_path, capsys) -> None:
+    (tmp_path / "component.md").write_text("Describe {component_name}:\n{cluster_analysis}")
+    with (
+        patch("codeboarding_cli.commands.full_analysis.bootstrap_environment"),
+        patch("codeboarding_cli.commands.full_analysis._write_static_outputs") as write_static,
+        patch("codeboarding_cli.commands.full_analysis.initialize_codeboardingignore"),
+    ):
+        main(
+            [
+                "full",
+                "--local",
+                "/tmp/repo",
+                "--no-llm",
+                "--output-dir",
+                "/tmp/repo-prompts-out",
+                "--prompt-dir",
+                str(tmp_path),
+            ]
+        )
+
+    assert write_static.call_args.args[2].prompt_dir == tmp_path.resolve()
+    (tmp_path / "component.md").write_text("Describe {component_title}.")
+    with pytest.raises(SystemExit):
+        build_parser().parse_args(["full", "--local", "/tmp/repo", "--prompt-dir", str(tmp_path)])
+    assert "unknown variable(s) {component_title}" in capsys.readouterr().err
@@ -0,0 +1,65 @@
+import json
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+from github_pr_comment import (
+    COMMENT_MARKER,
+    MAX_COMMENT_CHARS,
+    comment_body,
+    pull_request_number,
+    upsert_comment,
+)
+
+API = "https://api.github.com"
+
+
+def _response(payload, links: dict | None = None) -> MagicMock:
+    response = MagicMock()
+    response.json.return_value = payload
+    response.links = links or {}
+    return response
+
+
+def test_pull_request_number_reads_pull_request_and_issue_comment_events(tmp_path: Path):
+    event = tmp_path / "event.json"
+    event.write_text(json.dumps({"pull_request": {"number": 12}}))
+    assert pull_request_number(event) == 12
+    event.write_text(json.dumps({"issue": {"number": 7, "pull_request": {"url": "x"}}}))
+    assert pull_request_number(event) == 7
+    event.write_text(json.dumps({"ref": "refs/heads/main"}))
+    assert pull_request_number(event) is None
+
+
+def test_comment_body_is_marked_and_truncated():
+    assert comment_body("hello").startswith(COMMENT_MARKER)
+    long_body = comment_body("x" * (MAX_COMMENT_CHARS * 2))
+    assert len(long_body) == MAX_COMMENT_CHARS
+    assert long_body.endswith("artifact._\n")
+
+
+def test_upsert_edits_the_marked_comment_on_a_later_page():
+    first_page = _response(
+        [{"body": "LGTM", "url": "c1"}], links={"next": {"url": f"{API}/repos/o/r/issues/3/comments?page=2"}}
+    )
+    second_page = _response([{"body": f"{COMMENT_MARKER}\nold", "url": f"{API}/repos/o/r/issues/comments/9"}])
+    with (
+        patch("github_pr_comment.requests.get", side_effect=[first_page, second_page]),
+        patch("github_pr_comment.requests.patch", return_value=_response({"html_url": "edited"})) as edit,
+        patch("github_pr_comment.requests.post") as post,
+    ):
+        assert upsert_comment(API, "o/r", 3, "new", "token") == "edited"
+
+    assert edit.call_args.args[0] == f"{API}/repos/o/r/issues/comments/9"
+    assert edit.call_args.kwargs["json"] == {"body": "new"}
+    post.assert_not_called()
+
+
+def test_upsert_posts_when_no_comment_is_marked():
+    with (
+        patch("github_pr_comment.requests.get", return_value=_response([])),
+        patch("github_pr_comment.requests.post", return_value=_response({"html_url": "posted"})) as post,
+    ):
+        assert upsert_comment(API, "o/r", 3, "new", "token") == "posted"
+
+    assert post.call_args.args[0] == f"{API}/repos/o/r/issues/3/comments"
+    assert post.call_args.kwargs["headers"]["Authorization"] == "Bearer token"
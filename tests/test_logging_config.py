@@ -8,7 +8,7 @@ from pathlib import Path
 from unittest.mock import patch
 
 # Assuming logging_config.py exists and setup_logging is importable
-from logging_config import add_file_handler, setup_logging
+from logging_config import add_file_handler, normalize_log_level, setup_logging
 
 
 class TestLoggingConfig(unittest.TestCase):
@@ -262,6 +262,33 @@ class TestLoggingConfig(unittest.TestCase):
 
             self._clean_logging_handlers()
 
+    def test_log_file_gets_the_level_while_the_console_keeps_to_warnings(self):
+        with tempfile.TemporaryDirectory() as temp_dir:
+            log_file = Path(temp_dir) / "debug" / "run.log"
+            setup_logging(default_level="DEBUG", log_file=log_file)
+
+            logging.getLogger("test.log_file").debug("slow query")
+            console = next(h for h in logging.root.handlers if not isinstance(h, logging.FileHandler))
+            self.assertEqual(console.level, logging.WARNING)
+            self._clean_logging_handlers()
+            self.assertIn("slow query", log_file.read_text(encoding="utf-8"))
+
+    def test_third_party_noise_is_kept_below_debug(self):
+        setup_logging(default_level="WARN")
+        self.assertEqual(logging.getLogger("httpx").level, logging.WARNING)
+        self.assertEqual(logging.getLogger("openai").level, logging.WARNING)
+
+        setup_logging(default_level="DEBUG")
+        self.assertEqual(logging.getLogger("httpx").level, logging.NOTSET)
+        self.assertEqual(logging.root.level, logging.DEBUG)
+        self._clean_logging_handlers()
+
+    def test_normalize_log_level(self):
+        self.assertEqual(normalize_log_level("warn"), "WARNING")
+        self.assertEqual(normalize_log_level(" error "), "ERROR")
+        with self.assertRaises(ValueError):
+            normalize_log_level("verbose")
+
 
 if __name__ == "__main__":
     unittest.main()
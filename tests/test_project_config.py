@@ -0,0 +1,67 @@
+from pathlib import Path
+
+from main import _apply_project_config, _inject_default_subcommand, build_parser
+from project_config import load_project_config, project_config_path
+from user_config import LLMUserConfig
+
+
+def _write_config(repo: Path, text: str) -> None:
+    path = project_config_path(repo)
+    path.parent.mkdir(parents=True)
+    path.write_text(text)
+
+
+def test_missing_config_is_empty(tmp_path: Path):
+    cfg = load_project_config(tmp_path)
+
+    assert cfg.root is None
+    assert cfg.options == {}
+    assert cfg.section("layers") == {}
+
+
+def test_loads_options_llm_and_feature_sections(tmp_path: Path):
+    _write_config(
+        tmp_path,
+        """
+[options]
+depth-level = 4
+
+[llm]
+agent_model = "project-agent"
+
+[layers]
+order = ["api", "core"]
+""",
+    )
+
+    cfg = load_project_config(tmp_path)
+
+    assert cfg.options == {"depth-level": 4}
+    assert cfg.section("layers") == {"order": ["api", "core"]}
+    layered = cfg.layer_llm(LLMUserConfig(agent_model="user-agent", parsing_model="user-parser"))
+    assert (layered.agent_model, layered.parsing_model) == ("project-agent", "user-parser")
+
+
+def test_invalid_toml_falls_back_to_empty(tmp_path: Path):
+    _write_config(tmp_path, "[options\n")
+
+    assert load_project_config(tmp_path).options == {}
+
+
+def test_cli_defaults_skip_unknown_and_project_locating_options(tmp_path: Path):
+    _write_config(tmp_path, '[options]\ndepth_level = 4\nlocal = "/elsewhere"\nno_such_flag = true\n')
+
+    assert load_project_config(tmp_path).cli_defaults({"depth_level", "local"}) == {"depth_level": 4}
+
+
+def test_project_options_sit_between_builtin_defaults_and_cli_flags(tmp_path: Path):
+    _write_config(tmp_path, "[options]\ndepth_level = 4\n")
+
+    argv = _inject_default_subcommand(["--local", str(tmp_path)])
+    parser = build_parser()
+    _, args = _apply_project_config(parser, parser.parse_args(argv), argv)
+    assert args.depth_level == 4
+
+    argv = _inject_default_subcommand(["--local", str(tmp_path), "--depth-level", "2"])
+    _, args = _apply_project_config(parser, parser.parse_args(argv), argv)
+    assert args.depth_level == 2
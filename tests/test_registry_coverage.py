@@ -288,6 +288,9 @@ class TestLspAdapterAndLanguageEnumParity(unittest.TestCase):
         "csharp": "CSharp",
         "java": "Java",
         "rust": "Rust",
+        "elixir": "Elixir",
+        "swift": "Swift",
+        "scala": "Scala",
     }
 
     def test_every_lsp_tool_has_an_adapter_per_supported_language(self):
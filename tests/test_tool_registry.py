@@ -907,10 +907,11 @@ class TestManifest(unittest.TestCase):
         self.assertTrue(needs_install())
 
     def test_registry_native_tools_have_source(self):
-        # Tools resolved externally (e.g. csharp-ls via `dotnet tool install`)
-        # intentionally declare no ``source`` — the installer skips them and
-        # the adapter resolves the binary from PATH / a known install location.
-        externally_installed = {"csharp"}
+        # Tools resolved externally (e.g. csharp-ls via `dotnet tool install`,
+        # sourcekit-lsp from the Swift toolchain) intentionally declare no
+        # ``source`` — the installer skips them and the adapter resolves the
+        # binary from PATH / a known install location.
+        externally_installed = {"csharp", "swift"}
         for dep in TOOL_REGISTRY:
             if dep.kind is ToolKind.NATIVE and dep.key not in externally_installed:
                 self.assertIsNotNone(dep.source, f"{dep.key} should have a source")
@@ -961,16 +962,16 @@ class TestManifest(unittest.TestCase):
 def _populate_complete_servers_dir(base_dir: Path) -> None:
     """Populate base_dir to match the layout install_tools produces.
 
-    NATIVE -> platform_bin_dir/<name><exe>;
+    NATIVE -> platform_bin_dir/<name><exe> (none for a toolchain's own server, e.g. sourcekit-lsp);
     NODE -> node_modules/<js_entry_parent>/lib/<js_entry_file>
     (find_runnable does a substring match on parent dir);
-    ARCHIVE -> bin/<archive_subdir>/plugins/;
+    ARCHIVE -> bin/<archive_subdir>/plugins/ (JDTLS) or its launcher script (ElixirLS);
     PACKAGE_MANAGER -> platform_bin_dir/pm-tools/<subdir>/<name><exe>
     """
     bin_dir = platform_bin_dir(base_dir)
     bin_dir.mkdir(parents=True, exist_ok=True)
     for dep in TOOL_REGISTRY:
-        if dep.kind is ToolKind.NATIVE:
+        if dep.kind is ToolKind.NATIVE and dep.source is not None:
             native = bin_dir / f"{dep.binary_name}{exe_suffix()}"
             native.write_text("#!/bin/sh\n")
             native.chmod(0o755)  # install_native_tools chmods natives; has_required_tools now checks X_OK
@@ -979,7 +980,12 @@ def _populate_complete_servers_dir(base_dir: Path) -> None:
             entry_dir.mkdir(parents=True, exist_ok=True)
             (entry_dir / dep.js_entry_file).write_text("// stub\n")
         elif dep.kind is ToolKind.ARCHIVE and dep.archive_subdir:
-            (base_dir / "bin" / dep.archive_subdir / "plugins").mkdir(parents=True, exist_ok=True)
+            archive_dir = base_dir / "bin" / dep.archive_subdir
+            if dep.archive_launcher():
+                archive_dir.mkdir(parents=True, exist_ok=True)
+                (archive_dir / dep.archive_launcher()).write_text("#!/bin/sh\n")
+            else:
+                (archive_dir / "plugins").mkdir(parents=True, exist_ok=True)
         elif dep.kind is ToolKind.PACKAGE_MANAGER:
             subdir = dep.archive_subdir or dep.key
             pm_dir = bin_dir / "pm-tools" / subdir
@@ -1028,6 +1034,14 @@ class TestHasRequiredTools(unittest.TestCase):
             (platform_bin_dir(base_dir) / f"gopls{exe_suffix()}").chmod(0o644)
             self.assertFalse(has_required_tools(base_dir))
 
+    def test_toolchain_server_is_not_required(self):
+        """sourcekit-lsp comes with the Swift toolchain; its absence must not re-arm the install."""
+        with tempfile.TemporaryDirectory() as tmp:
+            base_dir = Path(tmp)
+            _populate_complete_servers_dir(base_dir)
+            self.assertFalse((platform_bin_dir(base_dir) / f"sourcekit-lsp{exe_suffix()}").exists())
+            self.assertTrue(has_required_tools(base_dir))
+
     def test_missing_node_js_entry_returns_false(self):
         with tempfile.TemporaryDirectory() as tmp:
             base_dir = Path(tmp)
@@ -1056,6 +1070,14 @@ class TestHasRequiredTools(unittest.TestCase):
             shutil.rmtree(base_dir / "bin" / "jdtls" / "plugins")
             self.assertFalse(has_required_tools(base_dir))
 
+    def test_archive_dir_without_launcher_returns_false(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            base_dir = Path(tmp)
+            _populate_complete_servers_dir(base_dir)
+            elixir = next(dep for dep in TOOL_REGISTRY if dep.key == "elixir")
+            (base_dir / "bin" / "elixir-ls" / elixir.archive_launcher()).unlink()
+            self.assertFalse(has_required_tools(base_dir))
+
     def test_needs_install_triggers_on_missing_node_install(self):
         """Integration: matching fingerprints but missing node_modules/pyright/ -> needs_install."""
         with tempfile.TemporaryDirectory() as tmp:
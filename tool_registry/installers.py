@@ -469,7 +469,7 @@ def install_node_tools(
         logger.exception("Node.js package installation failed")
 
 
-# -- Archive installer (JDTLS) ------------------------------------------------
+# -- Archive installer (JDTLS, ElixirLS) --------------------------------------------
 
 
 def install_archive_tool(
@@ -485,23 +485,31 @@ def install_archive_tool(
         on_progress(dep.key, 1, 1)
 
     extract_dir = target_dir / "bin" / dep.archive_subdir
-    if extract_dir.exists() and (extract_dir / "plugins").is_dir():
+    if extract_dir.exists() and (extract_dir / dep.archive_marker()).exists():
         logger.info("%s already installed", dep.key)
         return
 
     logger.info("Downloading %s...", dep.key)
     extract_dir.mkdir(parents=True, exist_ok=True)
-    archive_path = target_dir / "bin" / f"{dep.archive_subdir}.tar.gz"
-
     url = asset_url(dep.source, "")
+    is_zip = url.endswith(".zip")
+    archive_path = target_dir / "bin" / f"{dep.archive_subdir}{'.zip' if is_zip else '.tar.gz'}"
+
     expected_hash = dep.source.sha256.get("") if isinstance(dep.source, GitHubToolSource) else None
     try:
         if not download_asset(url, archive_path, expected_sha256=expected_hash):
             logger.warning("%s download failed (empty file)", dep.key)
             return
 
-        with tarfile.open(archive_path, "r:gz") as tar:
-            tar.extractall(path=extract_dir, filter="tar")
+        if is_zip:
+            with zipfile.ZipFile(archive_path) as archive:
+                archive.extractall(path=extract_dir)
+            # zipfile drops the executable bit; launcher scripts need it back.
+            for script in extract_dir.glob("*.sh"):
+                script.chmod(0o755)
+        else:
+            with tarfile.open(archive_path, "r:gz") as tar:
+                tar.extractall(path=extract_dir, filter="tar")
         archive_path.unlink()
         logger.info("%s installed successfully", dep.key)
     except Exception:
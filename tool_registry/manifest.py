@@ -243,8 +243,12 @@ def resolve_config(base_dir: Path) -> dict[str, Any]:
 
         elif dep.kind is ToolKind.ARCHIVE and dep.archive_subdir:
             archive_dir = base_dir / "bin" / dep.archive_subdir
-            if archive_dir.is_dir() and (archive_dir / "plugins").is_dir():
-                config[dep.config_section][dep.key]["jdtls_root"] = str(archive_dir)
+            if archive_dir.is_dir() and (archive_dir / dep.archive_marker()).exists():
+                if dep.archive_launcher():
+                    cmd = cast(list[str], config[dep.config_section][dep.key]["command"])
+                    cmd[0] = str(archive_dir / dep.archive_launcher())
+                else:
+                    config[dep.config_section][dep.key]["jdtls_root"] = str(archive_dir)
 
     return config
 
@@ -279,16 +283,20 @@ def has_required_tools(base_dir: Path) -> bool:
     """Return True when every ``TOOL_REGISTRY`` artifact is present on disk.
 
     Validation rules are kept in sync with ``resolve_config``:
-    NATIVE -> ``platform_bin_dir/<binary><exe>`` exists;
+    NATIVE -> ``platform_bin_dir/<binary><exe>`` exists (unless it has no source to download from);
     NODE -> ``find_runnable`` locates ``js_entry_file`` (``.bin/`` wrapper is
     skipped because Windows AV strips it first, and the resolver bypasses it too);
-    ARCHIVE -> ``bin/<archive_subdir>/plugins/`` exists.
+    ARCHIVE -> ``bin/<archive_subdir>/<archive_marker>`` exists (``plugins/``
+    for JDTLS, the launcher script for ElixirLS).
     """
     if not base_dir.exists():
         return False
 
     for dep in TOOL_REGISTRY:
         if dep.kind is ToolKind.NATIVE:
+            # Nothing to download (sourcekit-lsp comes with the Swift toolchain).
+            if dep.source is None:
+                continue
             # Skip the check when the installer would also skip the download,
             # otherwise ``needs_install`` loops forever on unsupported hosts.
             if not dep.is_available_on_host():
@@ -336,7 +344,7 @@ def has_required_tools(base_dir: Path) -> bool:
 
         elif dep.kind is ToolKind.ARCHIVE and dep.archive_subdir:
             archive_dir = base_dir / "bin" / dep.archive_subdir
-            if not (archive_dir.is_dir() and (archive_dir / "plugins").is_dir()):
+            if not (archive_dir.is_dir() and (archive_dir / dep.archive_marker()).exists()):
                 logger.info(
                     "has_required_tools: %s archive missing or incomplete at %s",
                     dep.key,
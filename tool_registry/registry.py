@@ -30,6 +30,15 @@ JDTLS_VERSION = "1.59.0"
 JDTLS_BUILD = "202605111959"
 JDTLS_URL_TEMPLATE = "https://download.eclipse.org/jdtls/snapshots/jdt-language-server-{version}-{build}.tar.gz"
 
+# ElixirLS ships one platform-independent zip of compiled BEAM files plus
+# launcher scripts; it runs on the user's own Elixir/Erlang install.
+ELIXIR_LS_VERSION = "0.29.3"
+ELIXIR_LS_URL_TEMPLATE = "https://github.com/elixir-lsp/elixir-ls/releases/download/v{version}/elixir-ls-v{version}.zip"
+
+# Metals is published to Maven Central; Coursier (``cs``) resolves it and
+# writes a launcher that runs it on the user's Java 17+.
+METALS_VERSION = "1.5.1"
+
 # rust-analyzer is pulled directly from upstream (weekly releases, ~17MB
 # per platform) rather than mirrored. Bumping the tag triggers a reinstall
 # via ``tools_fingerprint()``.
@@ -56,7 +65,7 @@ class ToolKind(StrEnum):
 
     NATIVE = "native"  # Pre-built binary downloaded from GitHub releases
     NODE = "node"  # npm package installed via `npm install`
-    ARCHIVE = "archive"  # Tarball downloaded and extracted from GitHub releases
+    ARCHIVE = "archive"  # Tarball or zip downloaded and extracted (e.g. JDTLS, ElixirLS)
     PACKAGE_MANAGER = (
         "package_manager"  # Installed by invoking a user-provided package manager (e.g. `dotnet tool install`)
     )
@@ -132,9 +141,20 @@ class ToolDependency:
     source: ToolSource | None = None
     npm_packages: list[str] = field(default_factory=list)
     archive_subdir: str = ""
+    # ARCHIVE tools started through a script inside the archive, keyed by ``platform.system()``.
+    # Empty for JDTLS, whose command is assembled from ``jdtls_root`` by the Java adapter.
+    archive_launchers: dict[str, str] = field(default_factory=dict)
     js_entry_file: str = ""
     js_entry_parent: str = ""
 
+    def archive_launcher(self) -> str:
+        """This host's launcher script inside the extracted archive, or ``""``."""
+        return self.archive_launchers.get(platform.system(), "")
+
+    def archive_marker(self) -> str:
+        """Entry of the extracted archive whose presence means the install is complete."""
+        return self.archive_launcher() or "plugins"
+
     def is_available_on_host(self) -> bool:
         """True unless this is an arch-aware NATIVE dep whose override map
         excludes the running ``(system, machine)`` (e.g. rust-analyzer on
@@ -235,6 +255,20 @@ TOOL_REGISTRY: list[ToolDependency] = [
         ),
         archive_subdir="csharp-ls",
     ),
+    # Metals installed via ``cs install``; ``--install-dir`` keeps the launcher
+    # out of the user's Coursier bin directory.
+    ToolDependency(
+        key="scala",
+        binary_name="metals",
+        kind=ToolKind.PACKAGE_MANAGER,
+        config_section=ConfigSection.LSP_SERVERS,
+        source=PackageManagerToolSource(
+            tag=METALS_VERSION,
+            manager_binary="cs",
+            install_args=("install", "metals:{tag}", "--install-dir", "{tool_path}"),
+        ),
+        archive_subdir="metals",
+    ),
     ToolDependency(
         key="java",
         binary_name="java",
@@ -247,6 +281,19 @@ TOOL_REGISTRY: list[ToolDependency] = [
         ),
         archive_subdir="jdtls",
     ),
+    ToolDependency(
+        key="elixir",
+        binary_name="language_server.sh",
+        kind=ToolKind.ARCHIVE,
+        config_section=ConfigSection.LSP_SERVERS,
+        source=UpstreamToolSource(tag=ELIXIR_LS_VERSION, url_template=ELIXIR_LS_URL_TEMPLATE),
+        archive_subdir="elixir-ls",
+        archive_launchers={
+            "Linux": "language_server.sh",
+            "Darwin": "language_server.sh",
+            "Windows": "language_server.bat",
+        },
+    ),
     ToolDependency(
         key="rust",
         binary_name="rust-analyzer",
@@ -268,4 +315,13 @@ TOOL_REGISTRY: list[ToolDependency] = [
             },
         ),
     ),
+    # sourcekit-lsp ships with every Swift toolchain (Xcode or swift.org) and
+    # only works against it, so there is no source: the installer skips it and
+    # the Swift adapter resolves it from PATH (or ``xcrun`` on macOS).
+    ToolDependency(
+        key="swift",
+        binary_name="sourcekit-lsp",
+        kind=ToolKind.NATIVE,
+        config_section=ConfigSection.LSP_SERVERS,
+    ),
 ]
@@ -28,6 +28,7 @@ _PROVIDER_SECRETS: dict[str, str] = {
     "openai_api_key": "OPENAI_API_KEY",
     "anthropic_api_key": "ANTHROPIC_API_KEY",
     "google_api_key": "GOOGLE_API_KEY",
+    "gemini_api_key": "GEMINI_API_KEY",
     "vercel_api_key": "VERCEL_API_KEY",
     "aws_bearer_token_bedrock": "AWS_BEARER_TOKEN_BEDROCK",
     "cerebras_api_key": "CEREBRAS_API_KEY",
@@ -63,6 +64,7 @@ CONFIG_TEMPLATE = """\
 # openai_base_url           = "http://localhost:8000/v1"   # self-hosted / OpenAI-compatible proxy
 # anthropic_api_key         = "sk-ant-..."
 # google_api_key            = "AIza..."
+# gemini_api_key            = "AIza..."            # same API as google_api_key, as the Gemini SDKs name it
 # vercel_api_key            = "vck_..."
 # aws_bearer_token_bedrock  = "..."
 # cerebras_api_key          = "..."
@@ -92,6 +94,7 @@ class ProviderUserConfig:
     openai_base_url: str | None = None
     anthropic_api_key: str | None = None
     google_api_key: str | None = None
+    gemini_api_key: str | None = None
     vercel_api_key: str | None = None
     aws_bearer_token_bedrock: str | None = None
     cerebras_api_key: str | None = None
@@ -5,6 +5,7 @@ import shutil
 import hashlib
 import uuid
 from collections.abc import Iterable
+from datetime import datetime, timezone
 from pathlib import Path
 
 from constants import CODEBOARDING_DIR_NAME
@@ -15,6 +16,7 @@ logger = logging.getLogger(__name__)
 RUN_OUTPUT_DIR_NAME = "run-output"
 ANALYSIS_FILENAME = "analysis.json"
 FINGERPRINT_FILENAME = "fingerprint.json"
+CACHE_ROOT_ENV = "CODEBOARDING_CACHE_ROOT"
 
 
 class CFGGenerationError(Exception):
@@ -41,7 +43,15 @@ def get_cache_dir(repo_dir: Path) -> Path:
     Anything under here may be deleted at any time; consumers must tolerate
     cache misses. Run-artifact files (e.g. ``static_analysis.pkl``) belong in
     :func:`get_artifact_dir` instead.
+
+    ``CODEBOARDING_CACHE_ROOT`` moves every repo's cache under one root, keyed
+    by repo name plus a hash of its absolute path so same-named checkouts don't collide.
     """
+    cache_root = os.getenv(CACHE_ROOT_ENV)
+    if cache_root:
+        resolved = repo_dir.resolve()
+        digest = hashlib.sha256(str(resolved).encode("utf-8")).hexdigest()[:12]
+        return Path(cache_root).expanduser() / f"{resolved.name}-{digest}"
     return repo_dir / CODEBOARDING_DIR_NAME / "cache"
 
 
@@ -98,6 +108,14 @@ def generate_run_id() -> str:
     return uuid.uuid4().hex
 
 
+def generated_at() -> str:
+    """ISO timestamp for report metadata: ``SOURCE_DATE_EPOCH`` when set (``--deterministic``), else now."""
+    epoch = os.getenv("SOURCE_DATE_EPOCH", "").strip()
+    if epoch.isdigit():
+        return datetime.fromtimestamp(int(epoch), timezone.utc).isoformat()
+    return datetime.now(timezone.utc).isoformat()
+
+
 def copy_files(files: Iterable[Path], target_dir: Path) -> None:
     """Copy each file in *files* into *target_dir*, preserving metadata."""
     target_dir.mkdir(parents=True, exist_ok=True)
@@ -130,6 +130,33 @@ VSCODE_CONFIG = {
             # and surfaces in error messages when the binary cannot be located.
             "install_commands": "codeboarding-setup (downloads rust-analyzer automatically)",
         },
+        "elixir": {
+            "name": "ElixirLS",
+            "command": ["language_server.sh"],  # Resolved to the extracted release by tool_registry
+            "languages": ["elixir"],
+            "file_extensions": [".ex", ".exs"],
+            # The ElixirLS release zip is downloaded by tool_registry; running it needs
+            # Elixir and Erlang/OTP on PATH, which are too large to bundle.
+            "install_commands": "codeboarding-setup (downloads ElixirLS automatically; requires Elixir 1.14+)",
+        },
+        "swift": {
+            "name": "sourcekit-lsp",
+            "command": ["sourcekit-lsp"],
+            "languages": ["swift"],
+            "file_extensions": [".swift"],
+            # sourcekit-lsp ships with every Swift toolchain (Xcode or swift.org),
+            # so there is nothing to download; on macOS it is found via ``xcrun``.
+            "install_commands": "Install Xcode or a Swift toolchain from https://www.swift.org/install/",
+        },
+        "scala": {
+            "name": "Metals",
+            "command": ["metals"],
+            "languages": ["scala"],
+            "file_extensions": [".scala", ".sc"],
+            # Metals is installed through Coursier (`cs install --install-dir`) by
+            # tool_registry; the launcher runs it on the JDK found on PATH.
+            "install_commands": "codeboarding-setup (installs Metals through Coursier; requires cs and Java 17+)",
+        },
     },
     "tools": {
         "tokei": {